@@ -19,8 +19,38 @@ type MinMaxer interface {
 
 // Extent represents the minx, miny, maxx and maxy
 // A nil extent represents the whole universe.
+//
+// An extent with a min greater than its max, such as NewEmptyExtent, is
+// empty: it contains nothing, and adding points or extents to it gives
+// their extent. Empty extents are what to start from when building the
+// extent of geometries, as the zero Extent already contains the origin.
 type Extent [4]float64
 
+// NewEmptyExtent returns an empty extent, one that contains nothing.
+func NewEmptyExtent() *Extent {
+	return &Extent{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+}
+
+// NewUniverseExtent returns an extent of the whole universe, the same as
+// the nil extent but one that can be added to and changed.
+func NewUniverseExtent() *Extent {
+	return &Extent{-math.MaxFloat64, -math.MaxFloat64, math.MaxFloat64, math.MaxFloat64}
+}
+
+// IsNil returns whether the extent is nil, which is the whole universe.
+func (e *Extent) IsNil() bool { return e == nil }
+
+// IsEmpty returns whether the extent contains nothing, which is when its
+// min is greater than its max, or it has NaN values. A nil extent is the
+// whole universe, so is not empty.
+func (e *Extent) IsEmpty() bool {
+	if e == nil {
+		return false
+	}
+	// the comparisons are false for NaN
+	return !(e[0] <= e[2] && e[1] <= e[3])
+}
+
 /* ========================= ATTRIBUTES ========================= */
 
 // Vertices return the vertices of the Bounding Box. The vertices are ordered in the following maner.
@@ -93,20 +123,26 @@ func (e *Extent) Max() [2]float64 {
 	return [2]float64{e[2], e[3]}
 }
 
-// XSpan is the distance of the Extent in X or inf
+// XSpan is the distance of the Extent in X or inf, 0 if it is empty.
 // TODO (gdey): look at how to have this function take into account the dpi.
 func (e *Extent) XSpan() float64 {
 	if e == nil {
 		return math.Inf(1)
 	}
+	if e.IsEmpty() {
+		return 0
+	}
 	return e[2] - e[0]
 }
 
-// YSpan is the distance of the Extent in Y or Inf
+// YSpan is the distance of the Extent in Y or Inf, 0 if it is empty.
 func (e *Extent) YSpan() float64 {
 	if e == nil {
 		return math.Inf(1)
 	}
+	if e.IsEmpty() {
+		return 0
+	}
 	return e[3] - e[1]
 }
 
@@ -117,11 +153,15 @@ func (e *Extent) Extent() [4]float64 {
 
 /* ========================= EXPANDING BOUNDING BOX ========================= */
 
-// Add will expand the extent to contain the given extent.
+// Add will expand the extent to contain the given extent. Adding an empty
+// extent does not change it.
 func (e *Extent) Add(extent MinMaxer) {
 	if e == nil {
 		return
 	}
+	if ne, ok := extent.(*Extent); ok && ne.IsEmpty() {
+		return
+	}
 	e[0] = math.Min(e[0], extent.MinX())
 	e[2] = math.Max(e[2], extent.MaxX())
 	e[1] = math.Min(e[1], extent.MinY())
@@ -160,8 +200,34 @@ func (e *Extent) AddGeometry(g Geometry) error {
 // AsPolygon will return the extent as a Polygon
 func (e *Extent) AsPolygon() Polygon { return Polygon{e.Vertices()} }
 
+// AsPolygonWithSegments will return the extent as a Polygon with each side
+// split into the given number of segments, starting at (minx,miny) in the
+// same order as Vertices. Adding points along the sides keeps the shape of
+// the extent when the polygon is reprojected. Segment counts less than 1
+// are taken as 1.
+func (e *Extent) AsPolygonWithSegments(segments int) Polygon {
+	if segments <= 1 {
+		return e.AsPolygon()
+	}
+	v := e.Vertices()
+	ring := make([][2]float64, 0, 4*segments)
+	for i := range v {
+		a, b := v[i], v[(i+1)%len(v)]
+		ring = append(ring, a)
+		for j := 1; j < segments; j++ {
+			t := float64(j) / float64(segments)
+			ring = append(ring, [2]float64{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])})
+		}
+	}
+	return Polygon{ring}
+}
+
 // Area returns the area of the extent, if the extent is nil, it will return 0
+// An empty extent has no area.
 func (e *Extent) Area() float64 {
+	if e.IsEmpty() {
+		return 0
+	}
 	return math.Abs((e.MaxY() - e.MinY()) * (e.MaxX() - e.MinX()))
 }
 
@@ -225,7 +291,10 @@ func NewExtentFromPoints(points ...Point) *Extent {
 	return &extent
 }
 
-// NewExtentFromGeometry tries to create an extent based on the geometry
+// NewExtentFromGeometry tries to create an extent based on the geometry.
+// For a geometry with no points it returns nil, so check the geometry with
+// IsEmpty first or use NewEmptyExtent and AddGeometry, which leaves the
+// extent empty.
 func NewExtentFromGeometry(g Geometry) (*Extent, error) {
 	var pts []Point
 	if err := getCoordinates(g, &pts); err != nil {
@@ -243,6 +312,7 @@ func NewExtentFromGeometry(g Geometry) (*Extent, error) {
 }
 
 // Contains will return whether the given  extent is inside of the  extent.
+// An empty extent is inside of every extent, and contains no other.
 func (e *Extent) Contains(ne MinMaxer) bool {
 	// Nil extent contains the world.
 	if e == nil {
@@ -251,6 +321,9 @@ func (e *Extent) Contains(ne MinMaxer) bool {
 	if ne == nil {
 		return false
 	}
+	if nee, ok := ne.(*Extent); ok && nee.IsEmpty() {
+		return true
+	}
 	return e.MinX() <= ne.MinX() &&
 		e.MaxX() >= ne.MaxX() &&
 		e.MinY() <= ne.MinY() &&
@@ -285,7 +358,7 @@ func (e *Extent) ContainsGeom(g Geometry) (bool, error) {
 		return e.Contains(extenter), nil
 	}
 	// we will use a exntent that contains the geometry, and check to see if this extent contains that extent.
-	var ne = new(Extent)
+	var ne = NewEmptyExtent()
 	if err := ne.AddGeometry(g); err != nil {
 		return false, err
 	}
@@ -314,6 +387,73 @@ func (e *Extent) ExpandBy(s float64) *Extent {
 	)
 }
 
+// ExpandByXY will expand the extent by dx on the left and right, and by dy
+// on the top and bottom.
+func (e *Extent) ExpandByXY(dx, dy float64) *Extent {
+	if e == nil {
+		return nil
+	}
+	return NewExtent(
+		[2]float64{e[0] - dx, e[1] - dy},
+		[2]float64{e[2] + dx, e[3] + dy},
+	)
+}
+
+// Center returns the point in the middle of the extent.
+func (e *Extent) Center() [2]float64 {
+	return [2]float64{(e.MinX() + e.MaxX()) / 2, (e.MinY() + e.MaxY()) / 2}
+}
+
+// ScaleAroundCenter will scale the extent by the given scale factor, keeping
+// the same center.
+func (e *Extent) ScaleAroundCenter(s float64) *Extent {
+	if e == nil {
+		return nil
+	}
+	c := e.Center()
+	hx, hy := e.XSpan()*s/2, e.YSpan()*s/2
+	return NewExtent(
+		[2]float64{c[0] - hx, c[1] - hy},
+		[2]float64{c[0] + hx, c[1] + hy},
+	)
+}
+
+// Quadrants splits the extent into four extents at its center. They are in
+// the order (minx,miny), (maxx,miny), (maxx,maxy), (minx,maxy), the same as
+// Vertices, with each containing that vertex.
+func (e *Extent) Quadrants() [4]*Extent {
+	if e == nil {
+		return [4]*Extent{}
+	}
+	g := e.Grid(2, 2)
+	return [4]*Extent{g[0], g[1], g[3], g[2]}
+}
+
+// Grid splits the extent into columns by rows extents of the same size. They
+// are ordered by row, from miny to maxy, and in each row, from minx to maxx.
+// The extents on the sides of the grid have the same sides as the extent.
+// Grid returns nil if the extent is nil, or if either count is less than 1.
+func (e *Extent) Grid(columns, rows int) []*Extent {
+	if e == nil || columns < 1 || rows < 1 {
+		return nil
+	}
+	at := func(min, max float64, i, n int) float64 {
+		if i == n {
+			return max
+		}
+		return min + (max-min)*float64(i)/float64(n)
+	}
+	cells := make([]*Extent, 0, columns*rows)
+	for r := 0; r < rows; r++ {
+		miny, maxy := at(e[1], e[3], r, rows), at(e[1], e[3], r+1, rows)
+		for c := 0; c < columns; c++ {
+			minx, maxx := at(e[0], e[2], c, columns), at(e[0], e[2], c+1, columns)
+			cells = append(cells, &Extent{minx, miny, maxx, maxy})
+		}
+	}
+	return cells
+}
+
 // Clone returns a new Extent with contents copied.
 func (e *Extent) Clone() *Extent {
 	if e == nil {
@@ -336,7 +476,11 @@ func (e *Extent) Clone() *Extent {
 // For example the for the above Box A intersects Box B at the area surround by C.
 //
 // If the Boxes don't intersect does will be false, otherwise ibb will be the intersect.
+// Empty extents intersect nothing.
 func (e *Extent) Intersect(ne *Extent) (*Extent, bool) {
+	if e.IsEmpty() || ne.IsEmpty() {
+		return nil, false
+	}
 	// if e in nil, then the intersect is ne. As a nil extent is the whole universe.
 	if e == nil {
 		return ne.Clone(), true
@@ -374,8 +518,23 @@ func (e *Extent) Intersect(ne *Extent) (*Extent, bool) {
 	return &Extent{minx, miny, maxx, maxy}, true
 }
 
-// IsUniverse returns weather the extent contains the universe. This is true if the clip box is nil or the x,y values are max values.
+// IsUniverse returns weather the extent contains the universe. This is true if the clip box is nil or the x,y values are max values, or infinite.
 func (e *Extent) IsUniverse() bool {
-	return e == nil || (e.MinX() == -math.MaxFloat64 && e.MaxX() == math.MaxFloat64 &&
-		e.MinY() == -math.MaxFloat64 && e.MaxY() == math.MaxFloat64)
+	return e == nil || (e.MinX() <= -math.MaxFloat64 && e.MaxX() >= math.MaxFloat64 &&
+		e.MinY() <= -math.MaxFloat64 && e.MaxY() >= math.MaxFloat64)
+}
+
+// Union returns a new extent that contains both extents. If either is nil
+// the union is the whole universe, which is nil, and if one is empty it is
+// the other.
+func (e *Extent) Union(ne *Extent) *Extent {
+	if e == nil || ne == nil {
+		return nil
+	}
+	u := e.Clone()
+	u.Add(ne)
+	if u.IsEmpty() {
+		return NewEmptyExtent()
+	}
+	return u
 }
@@ -587,3 +587,236 @@ func TestExtentContainsLine(t *testing.T) {
 	}
 
 }
+
+func TestExtentExpandByXY(t *testing.T) {
+	type tcase struct {
+		bb     *geom.Extent
+		dx, dy float64
+		ebb    *geom.Extent
+	}
+	fn := func(t *testing.T, tc tcase) {
+		sbb := tc.bb.ExpandByXY(tc.dx, tc.dy)
+		if !cmp.GeomExtent(tc.ebb, sbb) {
+			t.Errorf("Expand by xy, expected %v got %v", tc.ebb, sbb)
+		}
+	}
+	tests := map[string]tcase{
+		"nil": {
+			dx: 1,
+			dy: 2,
+		},
+		"1,2": {
+			bb:  &geom.Extent{0, 0, 10, 10},
+			ebb: &geom.Extent{-1, -2, 11, 12},
+			dx:  1,
+			dy:  2,
+		},
+	}
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestExtentScaleAroundCenter(t *testing.T) {
+	type tcase struct {
+		bb    *geom.Extent
+		scale float64
+		ebb   *geom.Extent
+	}
+	fn := func(t *testing.T, tc tcase) {
+		sbb := tc.bb.ScaleAroundCenter(tc.scale)
+		if !cmp.GeomExtent(tc.ebb, sbb) {
+			t.Errorf("Scale around center, expected %v got %v", tc.ebb, sbb)
+		}
+	}
+	tests := map[string]tcase{
+		"nil": {
+			scale: 2.0,
+		},
+		"2.0 scale": {
+			bb:    &geom.Extent{0, 0, 10, 20},
+			ebb:   &geom.Extent{-5, -10, 15, 30},
+			scale: 2.0,
+		},
+		"0.5 scale": {
+			bb:    &geom.Extent{10, 10, 14, 18},
+			ebb:   &geom.Extent{11, 12, 13, 16},
+			scale: 0.5,
+		},
+	}
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestExtentQuadrants(t *testing.T) {
+	bb := &geom.Extent{0, 0, 10, 20}
+	expected := [4]*geom.Extent{
+		{0, 0, 5, 10},
+		{5, 0, 10, 10},
+		{5, 10, 10, 20},
+		{0, 10, 5, 20},
+	}
+	got := bb.Quadrants()
+	for i := range expected {
+		if !cmp.GeomExtent(expected[i], got[i]) {
+			t.Errorf("quadrant %v, expected %v got %v", i, expected[i], got[i])
+		}
+	}
+}
+
+func TestExtentGrid(t *testing.T) {
+	type tcase struct {
+		bb            *geom.Extent
+		columns, rows int
+		expected      []*geom.Extent
+	}
+	fn := func(t *testing.T, tc tcase) {
+		got := tc.bb.Grid(tc.columns, tc.rows)
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("grid, expected %v got %v", tc.expected, got)
+		}
+	}
+	tests := map[string]tcase{
+		"nil": {
+			columns: 2,
+			rows:    2,
+		},
+		"no rows": {
+			bb:      &geom.Extent{0, 0, 10, 10},
+			columns: 2,
+		},
+		"3x2": {
+			bb:      &geom.Extent{0, 0, 3, 10},
+			columns: 3,
+			rows:    2,
+			expected: []*geom.Extent{
+				{0, 0, 1, 5}, {1, 0, 2, 5}, {2, 0, 3, 5},
+				{0, 5, 1, 10}, {1, 5, 2, 10}, {2, 5, 3, 10},
+			},
+		},
+	}
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestExtentAsPolygonWithSegments(t *testing.T) {
+	type tcase struct {
+		bb       *geom.Extent
+		segments int
+		expected geom.Polygon
+	}
+	fn := func(t *testing.T, tc tcase) {
+		got := tc.bb.AsPolygonWithSegments(tc.segments)
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("as polygon, expected %v got %v", tc.expected, got)
+		}
+	}
+	tests := map[string]tcase{
+		"1 segment": {
+			bb:       &geom.Extent{0, 0, 2, 4},
+			segments: 1,
+			expected: geom.Polygon{{{0, 0}, {2, 0}, {2, 4}, {0, 4}}},
+		},
+		"2 segments": {
+			bb:       &geom.Extent{0, 0, 2, 4},
+			segments: 2,
+			expected: geom.Polygon{{{0, 0}, {1, 0}, {2, 0}, {2, 2}, {2, 4}, {1, 4}, {0, 4}, {0, 2}}},
+		},
+	}
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestExtentEmpty(t *testing.T) {
+	empty := geom.NewEmptyExtent()
+	box := &geom.Extent{0, 0, 10, 10}
+
+	if !empty.IsEmpty() || box.IsEmpty() || (*geom.Extent)(nil).IsEmpty() {
+		t.Errorf("is empty, expected only the empty extent to be empty")
+	}
+	if !(*geom.Extent)(nil).IsNil() || empty.IsNil() {
+		t.Errorf("is nil, expected only the nil extent to be nil")
+	}
+	if nan := (&geom.Extent{math.NaN(), 0, 1, 1}); !nan.IsEmpty() {
+		t.Errorf("is empty, expected %v to be empty", nan)
+	}
+	if empty.Area() != 0 || empty.XSpan() != 0 || empty.YSpan() != 0 {
+		t.Errorf("size, expected 0 got %v %v %v", empty.Area(), empty.XSpan(), empty.YSpan())
+	}
+	if empty.ContainsPoint([2]float64{0, 0}) || empty.Contains(box) {
+		t.Errorf("contains, expected the empty extent to contain nothing")
+	}
+	if !box.Contains(empty) || !(*geom.Extent)(nil).Contains(empty) {
+		t.Errorf("contains, expected the empty extent to be contained")
+	}
+	if _, ok := box.Intersect(empty); ok {
+		t.Errorf("intersect, expected no intersection with the empty extent")
+	}
+	if _, ok := (*geom.Extent)(nil).Intersect(empty); ok {
+		t.Errorf("intersect, expected no intersection of the universe with the empty extent")
+	}
+
+	// building an extent from an empty one
+	ext := geom.NewEmptyExtent()
+	ext.AddPoints([2]float64{5, 5}, [2]float64{7, 6})
+	if exp := (&geom.Extent{5, 5, 7, 6}); !reflect.DeepEqual(ext, exp) {
+		t.Errorf("add points, expected %v got %v", exp, ext)
+	}
+	ext.Add(geom.NewEmptyExtent())
+	if exp := (&geom.Extent{5, 5, 7, 6}); !reflect.DeepEqual(ext, exp) {
+		t.Errorf("add empty, expected %v got %v", exp, ext)
+	}
+
+	// a geometry away from the origin is not inside a box from the origin
+	ok, err := (&geom.Extent{4, 4, 8, 8}).ContainsGeom(geom.LineString{{5, 5}, {7, 7}})
+	if err != nil || !ok {
+		t.Errorf("contains geom, expected true got %v %v", ok, err)
+	}
+
+	type tcase struct {
+		a, b *geom.Extent
+		exp  *geom.Extent
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got := tc.a.Union(tc.b)
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("union, expected %v got %v", tc.exp, got)
+			}
+		}
+	}
+	tests := map[string]tcase{
+		"boxes": {
+			a:   box,
+			b:   &geom.Extent{5, -5, 20, 5},
+			exp: &geom.Extent{0, -5, 20, 10},
+		},
+		"empty": {
+			a:   empty,
+			b:   box,
+			exp: box,
+		},
+		"both empty": {
+			a:   empty,
+			b:   geom.NewEmptyExtent(),
+			exp: empty,
+		},
+		"universe": {
+			a: box,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	if !geom.NewUniverseExtent().IsUniverse() || !(&geom.Extent{math.Inf(-1), math.Inf(-1), math.Inf(1), math.Inf(1)}).IsUniverse() {
+		t.Errorf("is universe, expected true got false")
+	}
+}
@@ -0,0 +1,168 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+)
+
+// ErrBuild is the reason a builder could not build a valid geometry.
+type ErrBuild struct {
+	// Ring is the index of the ring of a polygon the issue is in, 0 for
+	// line strings
+	Ring int
+	// Index is the index of the point, in the order they were added to the
+	// ring or line string, the issue is at, or -1 if it is the whole of it
+	Index int
+	Issue string
+}
+
+func (e ErrBuild) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("geom: %v in ring %v", e.Issue, e.Ring)
+	}
+	return fmt.Sprintf("geom: %v at point %v of ring %v", e.Issue, e.Index, e.Ring)
+}
+
+// buildPoints returns the points without consecutive duplicates, or an
+// error if RejectDuplicates is set and there are any, or if any of the
+// points are not finite. For rings the last point is dropped if it is the
+// same as the first, which closes the ring.
+func buildPoints(pts [][2]float64, ring int, closed, rejectDuplicates bool) ([][2]float64, error) {
+	out := make([][2]float64, 0, len(pts))
+	for i, pt := range pts {
+		if math.IsNaN(pt[0]) || math.IsNaN(pt[1]) || math.IsInf(pt[0], 0) || math.IsInf(pt[1], 0) {
+			return nil, ErrBuild{Ring: ring, Index: i, Issue: "coordinate not finite"}
+		}
+		if len(out) == 0 || out[len(out)-1] != pt {
+			out = append(out, pt)
+			continue
+		}
+		if rejectDuplicates {
+			return nil, ErrBuild{Ring: ring, Index: i, Issue: "duplicate point"}
+		}
+	}
+	// the first point repeated at the end closes the ring
+	for closed && len(out) > 1 && out[len(out)-1] == out[0] {
+		out = out[:len(out)-1]
+	}
+	return out, nil
+}
+
+// LineStringBuilder builds a LineString from the points added to it,
+// checking that they make a valid one. Its methods can be chained:
+//
+//	ls, err := geom.NewLineStringBuilder().Add(0, 0).Add(10, 0).LineString()
+type LineStringBuilder struct {
+	// RejectDuplicates makes consecutive points that are the same an
+	// error, rather than being dropped
+	RejectDuplicates bool
+
+	pts [][2]float64
+}
+
+// NewLineStringBuilder returns a builder with no points.
+func NewLineStringBuilder() *LineStringBuilder { return new(LineStringBuilder) }
+
+// Add adds the point x, y.
+func (b *LineStringBuilder) Add(x, y float64) *LineStringBuilder {
+	b.pts = append(b.pts, [2]float64{x, y})
+	return b
+}
+
+// AddPoints adds the points.
+func (b *LineStringBuilder) AddPoints(pts ...[2]float64) *LineStringBuilder {
+	b.pts = append(b.pts, pts...)
+	return b
+}
+
+// LineString returns the line string of the points added, without
+// consecutive duplicate points. It returns an ErrBuild if a point is not
+// finite, if there are fewer than two distinct points, or if there are
+// duplicates and RejectDuplicates is set.
+func (b *LineStringBuilder) LineString() (LineString, error) {
+	pts, err := buildPoints(b.pts, 0, false, b.RejectDuplicates)
+	if err != nil {
+		return nil, err
+	}
+	if len(pts) < 2 {
+		return nil, ErrBuild{Index: -1, Issue: "fewer than two distinct points"}
+	}
+	return LineString(pts), nil
+}
+
+// PolygonBuilder builds a Polygon from the rings of points added to it,
+// checking that they make valid rings. The points are added to the last
+// ring, and Ring starts a new one, so the first ring is the outer one and
+// the others its holes:
+//
+//	ply, err := geom.NewPolygonBuilder().
+//		AddPoints(shell...).
+//		Ring().AddPoints(hole...).
+//		Polygon()
+//
+// Rings need not be closed; if the first point is repeated at the end it is
+// dropped, as rings of a Polygon are closed without it. The winding of the
+// rings is not changed, see winding.ForceWinding.
+type PolygonBuilder struct {
+	// RejectDuplicates makes consecutive points that are the same an
+	// error, rather than being dropped
+	RejectDuplicates bool
+
+	rings [][][2]float64
+}
+
+// NewPolygonBuilder returns a builder with an empty outer ring.
+func NewPolygonBuilder() *PolygonBuilder {
+	return &PolygonBuilder{rings: [][][2]float64{nil}}
+}
+
+// Add adds the point x, y to the last ring.
+func (b *PolygonBuilder) Add(x, y float64) *PolygonBuilder {
+	return b.AddPoints([2]float64{x, y})
+}
+
+// AddPoints adds the points to the last ring.
+func (b *PolygonBuilder) AddPoints(pts ...[2]float64) *PolygonBuilder {
+	if len(b.rings) == 0 {
+		b.rings = append(b.rings, nil)
+	}
+	last := len(b.rings) - 1
+	b.rings[last] = append(b.rings[last], pts...)
+	return b
+}
+
+// Ring starts a new ring, a hole of the outer ring.
+func (b *PolygonBuilder) Ring() *PolygonBuilder {
+	b.rings = append(b.rings, nil)
+	return b
+}
+
+// Polygon returns the polygon of the rings added, closed and without
+// consecutive duplicate points. It returns an ErrBuild if a point is not
+// finite, if a ring has fewer than three distinct points or no area, or if
+// there are duplicates and RejectDuplicates is set.
+func (b *PolygonBuilder) Polygon() (Polygon, error) {
+	if len(b.rings) == 0 {
+		return nil, ErrBuild{Index: -1, Issue: "no outer ring"}
+	}
+	ply := make(Polygon, 0, len(b.rings))
+	for i, ring := range b.rings {
+		pts, err := buildPoints(ring, i, true, b.RejectDuplicates)
+		if err != nil {
+			return nil, err
+		}
+		if len(pts) < 3 {
+			return nil, ErrBuild{Ring: i, Index: -1, Issue: "fewer than three distinct points"}
+		}
+		var area float64
+		for j := range pts {
+			a, c := pts[j], pts[(j+1)%len(pts)]
+			area += a[0]*c[1] - c[0]*a[1]
+		}
+		if area == 0 {
+			return nil, ErrBuild{Ring: i, Index: -1, Issue: "no area"}
+		}
+		ply = append(ply, pts)
+	}
+	return ply, nil
+}
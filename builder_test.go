@@ -0,0 +1,120 @@
+package geom_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestLineStringBuilder(t *testing.T) {
+	type tcase struct {
+		b   *geom.LineStringBuilder
+		exp geom.LineString
+		err error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := tc.b.LineString()
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("line string, expected %v got %v", tc.exp, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"simple": {
+			b:   geom.NewLineStringBuilder().Add(0, 0).Add(10, 0).Add(10, 10),
+			exp: geom.LineString{{0, 0}, {10, 0}, {10, 10}},
+		},
+		"duplicates dropped": {
+			b:   geom.NewLineStringBuilder().AddPoints([2]float64{0, 0}, [2]float64{0, 0}, [2]float64{1, 1}, [2]float64{1, 1}),
+			exp: geom.LineString{{0, 0}, {1, 1}},
+		},
+		"duplicates rejected": {
+			b: (&geom.LineStringBuilder{RejectDuplicates: true}).
+				Add(0, 0).Add(1, 1).Add(1, 1),
+			err: geom.ErrBuild{Index: 2, Issue: "duplicate point"},
+		},
+		"one point": {
+			b:   geom.NewLineStringBuilder().Add(1, 1).Add(1, 1),
+			err: geom.ErrBuild{Index: -1, Issue: "fewer than two distinct points"},
+		},
+		"not finite": {
+			b:   geom.NewLineStringBuilder().Add(0, 0).Add(math.NaN(), 1),
+			err: geom.ErrBuild{Index: 1, Issue: "coordinate not finite"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestPolygonBuilder(t *testing.T) {
+	type tcase struct {
+		b   *geom.PolygonBuilder
+		exp geom.Polygon
+		err error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := tc.b.Polygon()
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("polygon, expected %v got %v", tc.exp, got)
+			}
+		}
+	}
+
+	shell := [][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	hole := [][2]float64{{2, 2}, {2, 4}, {4, 4}, {4, 2}}
+
+	tests := map[string]tcase{
+		"with hole": {
+			b:   geom.NewPolygonBuilder().AddPoints(shell...).Ring().AddPoints(hole...),
+			exp: geom.Polygon{shell, hole},
+		},
+		"closed": {
+			b:   geom.NewPolygonBuilder().AddPoints(shell...).Add(0, 0).Add(0, 0),
+			exp: geom.Polygon{shell},
+		},
+		"duplicates dropped": {
+			b:   geom.NewPolygonBuilder().Add(0, 0).Add(10, 0).Add(10, 0).Add(10, 10).Add(0, 10),
+			exp: geom.Polygon{shell},
+		},
+		"duplicates rejected": {
+			b: (&geom.PolygonBuilder{RejectDuplicates: true}).
+				AddPoints(shell...).Ring().Add(2, 2).Add(2, 2),
+			err: geom.ErrBuild{Ring: 1, Index: 1, Issue: "duplicate point"},
+		},
+		"too few points": {
+			b:   geom.NewPolygonBuilder().Add(0, 0).Add(1, 1).Add(0, 0),
+			err: geom.ErrBuild{Index: -1, Issue: "fewer than three distinct points"},
+		},
+		"no area": {
+			b:   geom.NewPolygonBuilder().AddPoints(shell...).Ring().Add(1, 1).Add(2, 2).Add(3, 3),
+			err: geom.ErrBuild{Ring: 1, Index: -1, Issue: "no area"},
+		},
+		"empty": {
+			b:   new(geom.PolygonBuilder),
+			err: geom.ErrBuild{Index: -1, Issue: "no outer ring"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	if s := (geom.ErrBuild{Ring: 1, Index: 3, Issue: "duplicate point"}).Error(); s != "geom: duplicate point at point 3 of ring 1" {
+		t.Errorf("error string, expected the point and ring got %q", s)
+	}
+}
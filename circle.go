@@ -139,3 +139,196 @@ func (c Circle) AsSegments(k uint) []Line {
 	}
 	return lines
 }
+
+// Extent returns the extent of the circle.
+func (c Circle) Extent() [4]float64 {
+	return [4]float64{
+		c.Center[0] - c.Radius, c.Center[1] - c.Radius,
+		c.Center[0] + c.Radius, c.Center[1] + c.Radius,
+	}
+}
+
+// AsPolygon returns the polygon approximating the circle with the given
+// number of segments, whose points are on the circle counter-clockwise
+// starting at the angle zero. The ring is not closed. A value less than 3
+// will use the default value of 30.
+func (c Circle) AsPolygon(segments int) Polygon {
+	if segments < 3 {
+		segments = 30
+	}
+	ring := make([][2]float64, segments)
+	for i := range ring {
+		t := 2 * math.Pi * float64(i) / float64(segments)
+		ring[i] = [2]float64{
+			c.Center[0] + c.Radius*math.Cos(t),
+			c.Center[1] + c.Radius*math.Sin(t),
+		}
+	}
+	return Polygon{ring}
+}
+
+// errOutsideCircle stops Contains at the first point outside the circle
+var errOutsideCircle = errors.New("geom: point outside of circle")
+
+// Contains returns weather every point of the geometry is in the circle,
+// points on the circle included. A circle is convex, so a line or polygon
+// is in the circle when its points are. Geometries without points are not
+// contained. Unlike ContainsPoint the distances are not rounded.
+func (c Circle) Contains(g Geometry) (bool, error) {
+	if e, ok := extentPolygon(g); ok {
+		g = e
+	}
+	var found bool
+	err := Walk(g, func(pt [2]float64) error {
+		if !c.covers(pt) {
+			return errOutsideCircle
+		}
+		found = true
+		return nil
+	})
+	switch err {
+	case nil:
+		return found, nil
+	case errOutsideCircle:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// Intersects returns weather the geometry and the circle share a point:
+// a point is in the circle, a line or the boundary of a polygon passes
+// within the radius of the center, or the center is in a polygon. The
+// points on the circle are in it.
+func (c Circle) Intersects(g Geometry) (bool, error) {
+	if e, ok := extentPolygon(g); ok {
+		g = e
+	}
+	switch gg := g.(type) {
+
+	default:
+
+		if xy, ok := XYGeometry(g); ok {
+			return c.Intersects(xy)
+		}
+		return false, ErrUnknownGeometry{g}
+
+	case Pointer:
+		return c.covers(gg.XY()), nil
+
+	case MultiPointer:
+		for _, pt := range gg.Points() {
+			if c.covers(pt) {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case LineStringer:
+		return c.intersectsLine(gg.Vertices(), false), nil
+
+	case MultiLineStringer:
+		for _, ls := range gg.LineStrings() {
+			if c.intersectsLine(ls, false) {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case Polygoner:
+		return c.intersectsPolygon(gg.LinearRings()), nil
+
+	case MultiPolygoner:
+		for _, p := range gg.Polygons() {
+			if c.intersectsPolygon(p) {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case Collectioner:
+		for _, child := range gg.Geometries() {
+			ok, err := c.Intersects(child)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+
+	}
+}
+
+// extentPolygon returns extents as polygons, so the predicates can treat
+// them as other geometries.
+func extentPolygon(g Geometry) (Geometry, bool) {
+	switch e := g.(type) {
+	case Extent:
+		return e.AsPolygon(), true
+	case *Extent:
+		if e == nil {
+			return Polygon{}, true
+		}
+		return e.AsPolygon(), true
+	}
+	return nil, false
+}
+
+// covers returns weather the point is within the radius of the center.
+func (c Circle) covers(pt [2]float64) bool {
+	dx, dy := pt[0]-c.Center[0], pt[1]-c.Center[1]
+	return dx*dx+dy*dy <= c.Radius*c.Radius
+}
+
+// coversSegment returns weather a point of the segment from a to b is
+// within the radius of the center.
+func (c Circle) coversSegment(a, b [2]float64) bool {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	l2 := dx*dx + dy*dy
+	if l2 == 0 {
+		return c.covers(a)
+	}
+	// the point of the segment nearest the center
+	t := ((c.Center[0]-a[0])*dx + (c.Center[1]-a[1])*dy) / l2
+	t = math.Max(0, math.Min(1, t))
+	return c.covers([2]float64{a[0] + t*dx, a[1] + t*dy})
+}
+
+// intersectsLine returns weather the line, closed if it is a ring, passes
+// within the radius of the center.
+func (c Circle) intersectsLine(pts [][2]float64, closed bool) bool {
+	if len(pts) == 1 {
+		return c.covers(pts[0])
+	}
+	for i := 1; i < len(pts); i++ {
+		if c.coversSegment(pts[i-1], pts[i]) {
+			return true
+		}
+	}
+	return closed && len(pts) > 2 && c.coversSegment(pts[len(pts)-1], pts[0])
+}
+
+// intersectsPolygon returns weather the boundary of the polygon passes
+// within the radius of the center, or the center is in the polygon.
+func (c Circle) intersectsPolygon(rings [][][2]float64) bool {
+	for _, ring := range rings {
+		if c.intersectsLine(ring, true) {
+			return true
+		}
+	}
+	// the circle is in the polygon, or in a hole, or outside of it; the
+	// center is in the polygon if a ray from it crosses the rings an odd
+	// number of times
+	var in bool
+	for _, ring := range rings {
+		for i := range ring {
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			if (a[1] > c.Center[1]) == (b[1] > c.Center[1]) {
+				continue
+			}
+			if c.Center[0] < a[0]+(c.Center[1]-a[1])*(b[0]-a[0])/(b[1]-a[1]) {
+				in = !in
+			}
+		}
+	}
+	return in
+}
@@ -1,6 +1,8 @@
 package geom_test
 
 import (
+	"math"
+	"reflect"
 	"testing"
 
 	"github.com/go-spatial/geom"
@@ -81,3 +83,137 @@ func TestCircleFromPoints(t *testing.T) {
 		t.Run(name, fn(tc))
 	}
 }
+
+func TestCircleAsPolygon(t *testing.T) {
+	c := geom.Circle{Center: [2]float64{1, 2}, Radius: 2}
+	ply := c.AsPolygon(4)
+	expected := geom.Polygon{{{3, 2}, {1, 4}, {-1, 2}, {1, 0}}}
+	if len(ply) != 1 || len(ply[0]) != 4 {
+		t.Fatalf("polygon, expected %v got %v", expected, ply)
+	}
+	for i, pt := range ply[0] {
+		if math.Abs(pt[0]-expected[0][i][0]) > 1e-9 || math.Abs(pt[1]-expected[0][i][1]) > 1e-9 {
+			t.Errorf("polygon, expected %v got %v", expected, ply)
+			break
+		}
+	}
+	if n := len(c.AsPolygon(2)[0]); n != 30 {
+		t.Errorf("default segments, expected 30 got %v", n)
+	}
+	if ext := c.Extent(); ext != [4]float64{-1, 0, 3, 4} {
+		t.Errorf("extent, expected [-1 0 3 4] got %v", ext)
+	}
+}
+
+func TestCirclePredicates(t *testing.T) {
+	type tcase struct {
+		g          geom.Geometry
+		intersects bool
+		contains   bool
+		err        error
+	}
+	c := geom.Circle{Center: [2]float64{0, 0}, Radius: 5}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			intersects, err := c.Intersects(tc.g)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("intersects error, expected %v got %v", tc.err, err)
+			}
+			if intersects != tc.intersects {
+				t.Errorf("intersects, expected %v got %v", tc.intersects, intersects)
+			}
+			contains, err := c.Contains(tc.g)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("contains error, expected %v got %v", tc.err, err)
+			}
+			if contains != tc.contains {
+				t.Errorf("contains, expected %v got %v", tc.contains, contains)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point inside": {
+			g:          geom.Point{1, 1},
+			intersects: true,
+			contains:   true,
+		},
+		"point on circle": {
+			g:          geom.Point{3, 4},
+			intersects: true,
+			contains:   true,
+		},
+		"point outside": {
+			g: geom.Point{4, 4},
+		},
+		"multipoint partly inside": {
+			g:          geom.MultiPoint{{1, 1}, {10, 10}},
+			intersects: true,
+		},
+		"line crossing": {
+			// neither end point is in the circle
+			g:          geom.LineString{{-10, 1}, {10, 1}},
+			intersects: true,
+		},
+		"line touching": {
+			g:          geom.LineString{{-10, 5}, {10, 5}},
+			intersects: true,
+		},
+		"line outside": {
+			g: geom.MultiLineString{{{-10, 6}, {10, 6}}},
+		},
+		"line inside": {
+			g:          geom.LineString{{-1, 1}, {2, 2}},
+			intersects: true,
+			contains:   true,
+		},
+		"polygon around circle": {
+			g:          geom.Polygon{{{-10, -10}, {10, -10}, {10, 10}, {-10, 10}}},
+			intersects: true,
+		},
+		"circle in hole": {
+			g: geom.Polygon{
+				{{-10, -10}, {10, -10}, {10, 10}, {-10, 10}},
+				{{-6, -6}, {-6, 6}, {6, 6}, {6, -6}},
+			},
+		},
+		"polygon inside": {
+			g:          geom.MultiPolygon{{{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}}}},
+			intersects: true,
+			contains:   true,
+		},
+		"polygon outside": {
+			g: geom.Polygon{{{6, 6}, {8, 6}, {8, 8}, {6, 8}}},
+		},
+		"extent corner": {
+			// the corner is outside, though the extents overlap
+			g: &geom.Extent{4, 4, 8, 8},
+		},
+		"extent inside": {
+			g:          geom.Extent{-1, -1, 1, 1},
+			intersects: true,
+			contains:   true,
+		},
+		"collection": {
+			g:          geom.Collection{geom.Point{10, 10}, geom.LineString{{-10, 1}, {10, 1}}},
+			intersects: true,
+		},
+		"empty collection": {
+			g: geom.Collection{},
+		},
+		"point z": {
+			g:          geom.PointZ{1, 1, 100},
+			intersects: true,
+			contains:   true,
+		},
+		"unknown": {
+			g:   struct{}{},
+			err: geom.ErrUnknownGeometry{Geom: struct{}{}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
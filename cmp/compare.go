@@ -49,14 +49,14 @@ func (cmp Compare) Float(f1, f2 float64) bool {
 		return math.Abs(f2-f1) < tolerance
 	}
 
-	i1 := int64(math.Float64bits(f1))
-	i2 := int64(math.Float64bits(f2))
-	d := i2 - i1
-
-	if d < 0 {
-		return d > -bitTolerance
+	// NaN are the ordinates of empty points, which are the same
+	if math.IsNaN(f1) || math.IsNaN(f2) {
+		return math.IsNaN(f1) && math.IsNaN(f2)
 	}
-	return d < bitTolerance
+
+	// floats of opposite signs are compared by their units in the last
+	// place too, rather than the difference of their bits
+	return bitTolerance > 0 && ULPDistance(f1, f2) < uint64(bitTolerance)
 }
 
 // FloatSlice compares two sets of float64 slices within the given tolerance.
@@ -0,0 +1,25 @@
+package cmp
+
+import "context"
+
+type compareKey struct{}
+
+// NewContext returns a context carrying the comparator, for the functions
+// that take a context to compare points with instead of the default. This
+// lets data of different precisions, such as survey coordinates in
+// millimetres and tile coordinates, be worked on at the same time without
+// changing the default with SetDefault.
+func NewContext(ctx context.Context, cmp Compare) context.Context {
+	return context.WithValue(ctx, compareKey{}, cmp)
+}
+
+// FromContext returns the comparator carried by the context, and whether
+// there is one. The functions taking a context use their usual comparator,
+// most often the DefaultCompare, if there is none.
+func FromContext(ctx context.Context) (cmp Compare, ok bool) {
+	if ctx == nil {
+		return cmp, false
+	}
+	cmp, ok = ctx.Value(compareKey{}).(Compare)
+	return cmp, ok
+}
@@ -0,0 +1,20 @@
+package cmp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Errorf("ok, expected false got true")
+	}
+	exp := New(0.001)
+	got, ok := FromContext(NewContext(context.Background(), exp))
+	if !ok || got != exp {
+		t.Errorf("compare, expected %v got %v", exp, got)
+	}
+	if !got.Float(1, 1.0001) || HiCMP.Float(1, 1.0001) {
+		t.Errorf("float, expected the context comparator to be coarser")
+	}
+}
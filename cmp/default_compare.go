@@ -114,3 +114,7 @@ func CollectionerEqual(col1, col2 geom.Collectioner) bool {
 // GeometryEqual checks if the two geometries are of the same type and then
 // calls the type method to check if they are equal
 func GeometryEqual(g1, g2 geom.Geometry) bool { return DefaultCompare().GeometryEqual(g1, g2) }
+
+// GeometryEqualTopo checks if the two geometries are the same once
+// normalized, using the default tolerances; see Compare.GeometryEqualTopo.
+func GeometryEqualTopo(g1, g2 geom.Geometry) bool { return DefaultCompare().GeometryEqualTopo(g1, g2) }
@@ -0,0 +1,123 @@
+package cmp
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// GeometryEqualExact reports whether the geometries are of the same kind,
+// and have exactly the same points, in the same order.
+func GeometryEqualExact(g1, g2 geom.Geometry) bool {
+	return geometryEqual(g1, g2, func(p1, p2 [2]float64) bool { return p1 == p2 })
+}
+
+// GeometryEqualWithin reports whether the geometries are of the same kind,
+// and have the same number of points, in the same order, with each point
+// within tolerance of the other in x and y.
+func GeometryEqualWithin(g1, g2 geom.Geometry, tolerance float64) bool {
+	return geometryEqual(g1, g2, func(p1, p2 [2]float64) bool {
+		return math.Abs(p1[0]-p2[0]) <= tolerance && math.Abs(p1[1]-p2[1]) <= tolerance
+	})
+}
+
+// GeometryEqualTopo reports whether the geometries are of the same kind,
+// and have the same points once normalized with geom.Normalize, compared
+// with the tolerances of cmp. So the start of rings, the direction of lines
+// and rings, and the order of parts do not matter. Geometries that can not
+// be normalized are not equal.
+func (cmp Compare) GeometryEqualTopo(g1, g2 geom.Geometry) bool {
+	n1, err := geom.Normalize(g1)
+	if err != nil {
+		return false
+	}
+	n2, err := geom.Normalize(g2)
+	if err != nil {
+		return false
+	}
+	return geometryEqual(n1, n2, cmp.PointEqual)
+}
+
+// geometryEqual reports whether the geometries are of the same kind, with
+// the same structure and equal points in the same order. Nil pointers are
+// only equal to nil pointers, and untyped nils are not equal to anything.
+func geometryEqual(g1, g2 geom.Geometry, eq func(p1, p2 [2]float64) bool) bool {
+	if g1 == nil || g2 == nil {
+		return false
+	}
+	if nil1, nil2 := IsNil(g1), IsNil(g2); nil1 || nil2 {
+		return nil1 && nil2
+	}
+	points := func(pts1, pts2 [][2]float64) bool {
+		if len(pts1) != len(pts2) {
+			return false
+		}
+		for i := range pts1 {
+			if !eq(pts1[i], pts2[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	lines := func(lns1, lns2 [][][2]float64) bool {
+		if len(lns1) != len(lns2) {
+			return false
+		}
+		for i := range lns1 {
+			if !points(lns1[i], lns2[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch gg1 := g1.(type) {
+	case geom.Pointer:
+		if gg2, ok := g2.(geom.Pointer); ok {
+			return eq(gg1.XY(), gg2.XY())
+		}
+	case geom.MultiPointer:
+		if gg2, ok := g2.(geom.MultiPointer); ok {
+			return points(gg1.Points(), gg2.Points())
+		}
+	case geom.LineStringer:
+		if gg2, ok := g2.(geom.LineStringer); ok {
+			return points(gg1.Vertices(), gg2.Vertices())
+		}
+	case geom.MultiLineStringer:
+		if gg2, ok := g2.(geom.MultiLineStringer); ok {
+			return lines(gg1.LineStrings(), gg2.LineStrings())
+		}
+	case geom.Polygoner:
+		if gg2, ok := g2.(geom.Polygoner); ok {
+			return lines(gg1.LinearRings(), gg2.LinearRings())
+		}
+	case geom.MultiPolygoner:
+		if gg2, ok := g2.(geom.MultiPolygoner); ok {
+			plys1, plys2 := gg1.Polygons(), gg2.Polygons()
+			if len(plys1) != len(plys2) {
+				return false
+			}
+			for i := range plys1 {
+				if !lines(plys1[i], plys2[i]) {
+					return false
+				}
+			}
+			return true
+		}
+	case geom.Collectioner:
+		if gg2, ok := g2.(geom.Collectioner); ok {
+			geos1, geos2 := gg1.Geometries(), gg2.Geometries()
+			if len(geos1) != len(geos2) {
+				return false
+			}
+			for i := range geos1 {
+				if !geometryEqual(geos1[i], geos2[i], eq) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package cmp
+
+import (
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestGeometryEqualVariants(t *testing.T) {
+	type tcase struct {
+		g1, g2 geom.Geometry
+		exact  bool
+		within bool
+		topo   bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if got := GeometryEqualExact(tc.g1, tc.g2); got != tc.exact {
+				t.Errorf("exact, expected %v got %v", tc.exact, got)
+			}
+			if got := GeometryEqualWithin(tc.g1, tc.g2, 0.01); got != tc.within {
+				t.Errorf("within, expected %v got %v", tc.within, got)
+			}
+			if got := GeometryEqualTopo(tc.g1, tc.g2); got != tc.topo {
+				t.Errorf("topo, expected %v got %v", tc.topo, got)
+			}
+		}
+	}
+
+	square := geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}
+	tests := map[string]tcase{
+		"same": {
+			g1: square, g2: geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			exact: true, within: true, topo: true,
+		},
+		"near": {
+			g1: square, g2: geom.Polygon{{{0, 0}, {10, 0.001}, {10, 10}, {0, 10}}},
+			within: true,
+		},
+		"rotated": {
+			g1: square, g2: geom.Polygon{{{10, 10}, {0, 10}, {0, 0}, {10, 0}}},
+			topo: true,
+		},
+		"reversed": {
+			g1: square, g2: geom.Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}}},
+			topo: true,
+		},
+		"different": {
+			g1: square, g2: geom.Polygon{{{0, 0}, {20, 0}, {20, 20}, {0, 20}}},
+		},
+		"line reversed": {
+			g1: geom.LineString{{0, 0}, {1, 1}, {2, 0}}, g2: geom.LineString{{2, 0}, {1, 1}, {0, 0}},
+			topo: true,
+		},
+		"multipoint order": {
+			g1: geom.MultiPoint{{0, 0}, {1, 1}}, g2: geom.MultiPoint{{1, 1}, {0, 0}},
+			topo: true,
+		},
+		"multilinestring order": {
+			g1:   geom.MultiLineString{{{0, 0}, {1, 1}}, {{2, 2}, {3, 3}}},
+			g2:   geom.MultiLineString{{{3, 3}, {2, 2}}, {{0, 0}, {1, 1}}},
+			topo: true,
+		},
+		"kinds": {
+			g1: geom.Point{0, 0}, g2: geom.MultiPoint{{0, 0}},
+		},
+		"collection": {
+			g1:    geom.Collection{geom.Point{1, 2}, square},
+			g2:    &geom.Collection{&geom.Point{1, 2}, square},
+			exact: true, within: true, topo: true,
+		},
+		"nil pointers": {
+			g1: (*geom.LineString)(nil), g2: (*geom.LineString)(nil),
+			exact: true, within: true, topo: true,
+		},
+		"nil": {
+			g1: nil, g2: nil,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
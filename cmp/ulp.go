@@ -0,0 +1,44 @@
+package cmp
+
+import "math"
+
+// ulpOrdered maps the float to an integer such that the integers of
+// neighbouring floats differ by one, across zero as well, with 0 and -0
+// both 0.
+func ulpOrdered(f float64) int64 {
+	b := math.Float64bits(f)
+	if b>>63 == 1 {
+		return -int64(b &^ (1 << 63))
+	}
+	return int64(b)
+}
+
+// ULPDistance returns the number of floats from a to b, the units in the
+// last place they are apart. It is 0 for 0 and -0, and the largest uint64
+// if either is NaN.
+func ULPDistance(a, b float64) uint64 {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.MaxUint64
+	}
+	x, y := ulpOrdered(a), ulpOrdered(b)
+	if x < y {
+		x, y = y, x
+	}
+	// the difference does not fit an int64 for floats of opposite signs
+	// far from zero, but does a uint64
+	return uint64(x) - uint64(y)
+}
+
+// FloatULP returns whether a and b are within ulps floats of each other.
+// Unlike an absolute tolerance, which at the magnitudes of Web Mercator
+// coordinates, about 2e7 where floats are 4e-9 apart, either takes in many
+// floats or none but the same one, this is the same relative precision
+// whatever the magnitude. Near zero, where floats are very close together,
+// an absolute tolerance is what is wanted, see Compare.Float. NaN is not
+// within any ulps of anything, and infinities only of themselves.
+func FloatULP(a, b float64, ulps uint) bool {
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return a == b
+	}
+	return ULPDistance(a, b) <= uint64(ulps)
+}
@@ -0,0 +1,76 @@
+package cmp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFloatULP(t *testing.T) {
+	type tcase struct {
+		a, b float64
+		ulps uint
+		dist uint64
+		exp  bool
+	}
+
+	// web mercator magnitudes
+	const x = 2.0037508342789244e7
+	next := math.Nextafter(x, math.Inf(1))
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if got := ULPDistance(tc.a, tc.b); got != tc.dist {
+				t.Errorf("distance, expected %v got %v", tc.dist, got)
+			}
+			if got := FloatULP(tc.a, tc.b, tc.ulps); got != tc.exp {
+				t.Errorf("float ulp, expected %v got %v", tc.exp, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"same":        {a: x, b: x, exp: true},
+		"next":        {a: x, b: next, ulps: 1, dist: 1, exp: true},
+		"next, exact": {a: next, b: x, dist: 1, exp: false},
+		"apart": {
+			a:    x,
+			b:    math.Nextafter(next, math.Inf(1)),
+			ulps: 1,
+			dist: 2,
+			exp:  false,
+		},
+		"zeros": {a: 0, b: math.Copysign(0, -1), exp: true},
+		"across zero": {
+			a:    math.SmallestNonzeroFloat64,
+			b:    -math.SmallestNonzeroFloat64,
+			ulps: 2,
+			dist: 2,
+			exp:  true,
+		},
+		"extremes": {
+			a:    math.MaxFloat64,
+			b:    -math.MaxFloat64,
+			ulps: math.MaxUint32,
+			dist: 2 * math.Float64bits(math.MaxFloat64),
+			exp:  false,
+		},
+		"infinity": {
+			a:    math.Inf(1),
+			b:    math.MaxFloat64,
+			ulps: 1,
+			dist: 1,
+			exp:  false,
+		},
+		"nan": {a: math.NaN(), b: math.NaN(), ulps: 1, dist: math.MaxUint64},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	// one ulp at web mercator magnitudes is more than the high precision
+	// tolerance, but within its bit tolerance
+	if next-x <= HiPrecision || !HiCMP.Float(x, next) {
+		t.Errorf("high precision, expected %v and %v to be equal", x, next)
+	}
+}
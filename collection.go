@@ -22,3 +22,22 @@ func (c *Collection) SetGeometries(input []Geometry) (err error) {
 	*c = append((*c)[:0], input...)
 	return
 }
+
+// Flatten returns the geometries of the collection, with the geometries of
+// any collections in it in their place, recursively, so none of the
+// geometries returned are collections. The order of the geometries is
+// kept. Flatten returns nil for a nil collection.
+func Flatten(col Collectioner) Collection {
+	if col == nil {
+		return nil
+	}
+	var flat Collection
+	for _, g := range col.Geometries() {
+		if c, ok := g.(Collectioner); ok {
+			flat = append(flat, Flatten(c)...)
+			continue
+		}
+		flat = append(flat, g)
+	}
+	return flat
+}
@@ -71,3 +71,66 @@ func TestCollectionSetter(t *testing.T) {
 		t.Run(strconv.FormatInt(int64(i), 10), func(t *testing.T) { fn(t, tc) })
 	}
 }
+
+func TestFlatten(t *testing.T) {
+	type tcase struct {
+		col      geom.Collectioner
+		expected geom.Collection
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got := geom.Flatten(tc.col)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("flatten, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+	tests := map[string]tcase{
+		"nil": {},
+		"empty": {
+			col: geom.Collection{},
+		},
+		"flat": {
+			col:      geom.Collection{geom.Point{1, 2}, geom.LineString{{0, 0}, {1, 1}}},
+			expected: geom.Collection{geom.Point{1, 2}, geom.LineString{{0, 0}, {1, 1}}},
+		},
+		"nested": {
+			col: geom.Collection{
+				geom.Point{1, 2},
+				geom.Collection{
+					geom.MultiPoint{{3, 4}},
+					&geom.Collection{geom.Point{5, 6}},
+					geom.Collection{},
+				},
+				geom.Point{7, 8},
+			},
+			expected: geom.Collection{
+				geom.Point{1, 2},
+				geom.MultiPoint{{3, 4}},
+				geom.Point{5, 6},
+				geom.Point{7, 8},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestCollectionExtent(t *testing.T) {
+	col := geom.Collection{
+		geom.Point{1, 2},
+		&geom.Collection{
+			geom.Collection{geom.LineString{{-1, 0}, {3, 5}}},
+			geom.MultiPoint{{0, 7}},
+		},
+	}
+	expected := &geom.Extent{-1, 0, 3, 7}
+	got, err := geom.NewExtentFromGeometry(col)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("extent, expected %v got %v", expected, got)
+	}
+}
@@ -0,0 +1,229 @@
+package geom
+
+import "errors"
+
+// ErrInvalidCoordSeq is returned for flat coordinates that are not a whole
+// number of coordinates of the layout
+var ErrInvalidCoordSeq = errors.New("geom: invalid CoordSeq")
+
+// Layout is the ordinates of each coordinate of a CoordSeq.
+type Layout uint8
+
+const (
+	// LayoutXY is 2D coordinates
+	LayoutXY Layout = iota
+	// LayoutXYZ is 3D coordinates
+	LayoutXYZ
+	// LayoutXYM is 2D coordinates and measures
+	LayoutXYM
+	// LayoutXYZM is 3D coordinates and measures
+	LayoutXYZM
+)
+
+// Stride returns the number of ordinates of each coordinate of the layout.
+func (l Layout) Stride() int {
+	switch l {
+	case LayoutXYZ, LayoutXYM:
+		return 3
+	case LayoutXYZM:
+		return 4
+	default:
+		return 2
+	}
+}
+
+// CoordSeq is a sequence of coordinates held in one flat slice of
+// ordinates, x1 y1 x2 y2 ... for LayoutXY, which is one allocation
+// however many coordinates there are.
+type CoordSeq struct {
+	Layout Layout
+	Flat   []float64
+}
+
+// NewCoordSeq returns the CoordSeq of the flat ordinates, which must be a
+// whole number of coordinates of the layout. The ordinates are not copied.
+func NewCoordSeq(layout Layout, flat []float64) (CoordSeq, error) {
+	if len(flat)%layout.Stride() != 0 {
+		return CoordSeq{}, ErrInvalidCoordSeq
+	}
+	return CoordSeq{Layout: layout, Flat: flat}, nil
+}
+
+// MakeCoordSeq returns an empty CoordSeq with room for n coordinates.
+func MakeCoordSeq(layout Layout, n int) CoordSeq {
+	return CoordSeq{Layout: layout, Flat: make([]float64, 0, n*layout.Stride())}
+}
+
+// Len returns the number of coordinates.
+func (cs CoordSeq) Len() int { return len(cs.Flat) / cs.Layout.Stride() }
+
+// Coord returns the ordinates of the ith coordinate. It shares the storage
+// of the sequence, so changing it changes the sequence.
+func (cs CoordSeq) Coord(i int) []float64 {
+	s := cs.Layout.Stride()
+	return cs.Flat[i*s : (i+1)*s : (i+1)*s]
+}
+
+// XY returns the x and y of the ith coordinate.
+func (cs CoordSeq) XY(i int) [2]float64 {
+	s := cs.Layout.Stride()
+	return [2]float64{cs.Flat[i*s], cs.Flat[i*s+1]}
+}
+
+// Append adds a coordinate to the end of the sequence. Ordinates beyond the
+// stride of the layout are dropped and missing ones are 0.
+func (cs *CoordSeq) Append(ordinates ...float64) {
+	s := cs.Layout.Stride()
+	for i := 0; i < s; i++ {
+		v := 0.0
+		if i < len(ordinates) {
+			v = ordinates[i]
+		}
+		cs.Flat = append(cs.Flat, v)
+	}
+}
+
+// XYs returns the x and y of each coordinate.
+func (cs CoordSeq) XYs() [][2]float64 {
+	if cs.Flat == nil {
+		return nil
+	}
+	xys := make([][2]float64, cs.Len())
+	for i := range xys {
+		xys[i] = cs.XY(i)
+	}
+	return xys
+}
+
+// Extent returns the extent of the x and y of the coordinates, or nil if
+// there are none.
+func (cs CoordSeq) Extent() *Extent {
+	n := cs.Len()
+	if n == 0 {
+		return nil
+	}
+	e := NewExtent(cs.XY(0))
+	for i := 1; i < n; i++ {
+		e.AddPoints(cs.XY(i))
+	}
+	return e
+}
+
+func coordSeqOf2(pts [][2]float64) CoordSeq {
+	cs := MakeCoordSeq(LayoutXY, len(pts))
+	for _, pt := range pts {
+		cs.Flat = append(cs.Flat, pt[:]...)
+	}
+	return cs
+}
+
+func coordSeqOf3(layout Layout, pts [][3]float64) CoordSeq {
+	cs := MakeCoordSeq(layout, len(pts))
+	for _, pt := range pts {
+		cs.Flat = append(cs.Flat, pt[:]...)
+	}
+	return cs
+}
+
+func coordSeqOf4(pts [][4]float64) CoordSeq {
+	cs := MakeCoordSeq(LayoutXYZM, len(pts))
+	for _, pt := range pts {
+		cs.Flat = append(cs.Flat, pt[:]...)
+	}
+	return cs
+}
+
+// PackedLineString is a line string with its vertices held in a CoordSeq.
+// It is a LineStringer, so can be used where a LineString can.
+type PackedLineString struct {
+	Seq CoordSeq
+}
+
+// PackLineString packs the vertices of the line string, which is a
+// LineString, LineStringZ, LineStringM or LineStringZM, in a CoordSeq of
+// the matching layout.
+func PackLineString(g Geometry) (PackedLineString, error) {
+	if ls, ok := g.(*LineString); ok {
+		if ls == nil {
+			return PackedLineString{}, ErrNilLineString
+		}
+		g = *ls
+	}
+	switch ls := derefZM(g).(type) {
+	case LineString:
+		return PackedLineString{coordSeqOf2(ls)}, nil
+	case LineStringZ:
+		return PackedLineString{coordSeqOf3(LayoutXYZ, ls)}, nil
+	case LineStringM:
+		return PackedLineString{coordSeqOf3(LayoutXYM, ls)}, nil
+	case LineStringZM:
+		return PackedLineString{coordSeqOf4(ls)}, nil
+	default:
+		return PackedLineString{}, ErrUnknownGeometry{g}
+	}
+}
+
+// Vertices returns the x and y of the vertices.
+func (ls PackedLineString) Vertices() [][2]float64 { return ls.Seq.XYs() }
+
+// LineString returns the 2D line string.
+func (ls PackedLineString) LineString() LineString { return LineString(ls.Seq.XYs()) }
+
+// PackedPolygon is a polygon with the vertices of each of its rings held
+// in a CoordSeq. As for a Polygon the first vertex of a ring is not
+// repeated at its end. It is a Polygoner, so can be used where a Polygon
+// can.
+type PackedPolygon []CoordSeq
+
+// PackPolygon packs the vertices of the rings of the polygon, which is a
+// Polygon, PolygonZ, PolygonM or PolygonZM, in CoordSeqs of the matching
+// layout.
+func PackPolygon(g Geometry) (PackedPolygon, error) {
+	if p, ok := g.(*Polygon); ok {
+		if p == nil {
+			return nil, ErrNilPolygon
+		}
+		g = *p
+	}
+	var p PackedPolygon
+	switch ply := derefZM(g).(type) {
+	case Polygon:
+		p = make(PackedPolygon, len(ply))
+		for i := range ply {
+			p[i] = coordSeqOf2(ply[i])
+		}
+	case PolygonZ:
+		p = make(PackedPolygon, len(ply))
+		for i := range ply {
+			p[i] = coordSeqOf3(LayoutXYZ, ply[i])
+		}
+	case PolygonM:
+		p = make(PackedPolygon, len(ply))
+		for i := range ply {
+			p[i] = coordSeqOf3(LayoutXYM, ply[i])
+		}
+	case PolygonZM:
+		p = make(PackedPolygon, len(ply))
+		for i := range ply {
+			p[i] = coordSeqOf4(ply[i])
+		}
+	default:
+		return nil, ErrUnknownGeometry{g}
+	}
+	return p, nil
+}
+
+// LinearRings returns the x and y of the vertices of the rings.
+func (p PackedPolygon) LinearRings() [][][2]float64 {
+	if p == nil {
+		return nil
+	}
+	rings := make([][][2]float64, len(p))
+	for i := range p {
+		rings[i] = p[i].XYs()
+	}
+	return rings
+}
+
+// Polygon returns the 2D polygon.
+func (p PackedPolygon) Polygon() Polygon { return Polygon(p.LinearRings()) }
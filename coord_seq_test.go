@@ -0,0 +1,115 @@
+package geom_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestCoordSeq(t *testing.T) {
+	cs, err := geom.NewCoordSeq(geom.LayoutXYZ, []float64{1, 2, 3, 4, 5, 6})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if cs.Len() != 2 {
+		t.Errorf("len, expected 2 got %v", cs.Len())
+	}
+	if c := cs.Coord(1); !reflect.DeepEqual(c, []float64{4, 5, 6}) {
+		t.Errorf("coord, expected [4 5 6] got %v", c)
+	}
+	cs.Append(7, 8)
+	if exp := [][2]float64{{1, 2}, {4, 5}, {7, 8}}; !reflect.DeepEqual(cs.XYs(), exp) {
+		t.Errorf("xys, expected %v got %v", exp, cs.XYs())
+	}
+	if c := cs.Coord(2); !reflect.DeepEqual(c, []float64{7, 8, 0}) {
+		t.Errorf("appended coord, expected [7 8 0] got %v", c)
+	}
+	if exp := (&geom.Extent{1, 2, 7, 8}); !reflect.DeepEqual(cs.Extent(), exp) {
+		t.Errorf("extent, expected %v got %v", exp, cs.Extent())
+	}
+
+	if _, err = geom.NewCoordSeq(geom.LayoutXYZM, []float64{1, 2, 3}); err != geom.ErrInvalidCoordSeq {
+		t.Errorf("error, expected %v got %v", geom.ErrInvalidCoordSeq, err)
+	}
+}
+
+func TestPackLineString(t *testing.T) {
+	type tcase struct {
+		g   geom.Geometry
+		exp geom.PackedLineString
+		err error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := geom.PackLineString(tc.g)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("packed, expected %v got %v", tc.exp, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"linestring": {
+			g:   geom.LineString{{1, 2}, {3, 4}},
+			exp: geom.PackedLineString{Seq: geom.CoordSeq{Layout: geom.LayoutXY, Flat: []float64{1, 2, 3, 4}}},
+		},
+		"linestring m": {
+			g:   &geom.LineStringM{{1, 2, 3}, {4, 5, 6}},
+			exp: geom.PackedLineString{Seq: geom.CoordSeq{Layout: geom.LayoutXYM, Flat: []float64{1, 2, 3, 4, 5, 6}}},
+		},
+		"nil": {
+			g:   (*geom.LineString)(nil),
+			err: geom.ErrNilLineString,
+		},
+		"point": {
+			g:   geom.Point{1, 2},
+			err: geom.ErrUnknownGeometry{Geom: geom.Point{1, 2}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestPackedPolygon(t *testing.T) {
+	ply := geom.PolygonZM{
+		{{0, 0, 1, 2}, {10, 0, 1, 2}, {10, 10, 1, 2}},
+		{{2, 2, 0, 0}, {3, 2, 0, 0}, {3, 3, 0, 0}},
+	}
+	p, err := geom.PackPolygon(ply)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if len(p) != 2 || p[0].Layout != geom.LayoutXYZM || len(p[1].Flat) != 12 {
+		t.Errorf("packed, expected two rings of three XYZM coordinates got %v", p)
+	}
+	if exp := ply.Polygon(); !reflect.DeepEqual(p.Polygon(), exp) {
+		t.Errorf("polygon, expected %v got %v", exp, p.Polygon())
+	}
+
+	// packed geometries can be used as the unpacked ones
+	e, err := geom.NewExtentFromGeometry(p)
+	if err != nil {
+		t.Fatalf("extent error, expected nil got %v", err)
+	}
+	if exp := (&geom.Extent{0, 0, 10, 10}); !reflect.DeepEqual(e, exp) {
+		t.Errorf("extent, expected %v got %v", exp, e)
+	}
+}
+
+func BenchmarkPackLineString(b *testing.B) {
+	ls := make(geom.LineString, 10000)
+	for i := range ls {
+		ls[i] = [2]float64{float64(i), float64(i % 7)}
+	}
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		geom.PackLineString(ls)
+	}
+}
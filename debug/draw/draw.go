@@ -0,0 +1,316 @@
+// Package draw draws geometries, and the edges of triangulations, on an
+// image to be written as a PNG, for debugging. It is pure Go, using
+// image/draw, with a small built in font for labels so the edges and
+// points of a step of an algorithm can be numbered.
+//
+// A debug mode can write an image for each step of an algorithm instead of
+// logging the WKT of each, for example:
+//
+//	c := draw.NewCanvas(extent, 800)
+//	c.Subdivision(sd, color.Black, true)
+//	c.Geometry(x, draw.Red)
+//	c.WriteFile(fmt.Sprintf("step_%03d.png", step))
+package draw
+
+import (
+	"image"
+	"image/color"
+	imagedraw "image/draw"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay/quadedge"
+)
+
+// Colors for drawing with.
+var (
+	Red   = color.RGBA{0xcc, 0x22, 0x22, 0xff}
+	Green = color.RGBA{0x22, 0x99, 0x33, 0xff}
+	Blue  = color.RGBA{0x22, 0x44, 0xcc, 0xff}
+)
+
+// Canvas is an image geometries are drawn on, showing an extent of their
+// coordinates.
+type Canvas struct {
+	img   *image.RGBA
+	ext   geom.Extent
+	scale float64
+	yUp   bool
+}
+
+// NewCanvas returns a white canvas showing the extent, width pixels wide
+// and as high as the extent is at the same scale. A width less than 1 is
+// taken as 512. If the extent has no width or height it is grown to have
+// some.
+func NewCanvas(extent geom.Extent, width int) *Canvas {
+	if width < 1 {
+		width = 512
+	}
+	ext := &extent
+	if ext.XSpan() == 0 || ext.YSpan() == 0 {
+		m := math.Max(ext.XSpan(), ext.YSpan()) / 2
+		if m == 0 {
+			m = 1
+		}
+		ext = ext.ExpandBy(m)
+	}
+	scale := float64(width) / ext.XSpan()
+	height := int(math.Ceil(ext.YSpan() * scale))
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	imagedraw.Draw(img, img.Bounds(), image.White, image.Point{}, imagedraw.Src)
+	return &Canvas{img: img, ext: *ext, scale: scale}
+}
+
+// SetYUp sets whether y increases going up, as for longitude and latitude,
+// in which case the geometries are drawn the right way up. By default y
+// increases going down, as for tiles.
+func (c *Canvas) SetYUp(yUp bool) { c.yUp = yUp }
+
+// Image returns the image drawn on.
+func (c *Canvas) Image() *image.RGBA { return c.img }
+
+// toPixel returns the position on the image of the point.
+func (c *Canvas) toPixel(pt [2]float64) (float64, float64) {
+	x := (pt[0] - c.ext[0]) * c.scale
+	if c.yUp {
+		return x, (c.ext[3] - pt[1]) * c.scale
+	}
+	return x, (pt[1] - c.ext[1]) * c.scale
+}
+
+// Point draws the point as a square five pixels across.
+func (c *Canvas) Point(pt [2]float64, col color.Color) {
+	x, y := c.toPixel(pt)
+	px, py := int(math.Floor(x)), int(math.Floor(y))
+	r := image.Rect(px-2, py-2, px+3, py+3)
+	imagedraw.Draw(c.img, r, image.NewUniform(col), image.Point{}, imagedraw.Over)
+}
+
+// Line draws the line one pixel wide.
+func (c *Canvas) Line(l geom.Line, col color.Color) {
+	x0, y0 := c.toPixel(l[0])
+	x1, y1 := c.toPixel(l[1])
+	b := c.img.Bounds()
+	// clip to the image, so long lines off it are not walked
+	t0, t1, ok := clip(x0, y0, x1, y1, float64(b.Min.X-1), float64(b.Min.Y-1), float64(b.Max.X+1), float64(b.Max.Y+1))
+	if !ok {
+		return
+	}
+	dx, dy := x1-x0, y1-y0
+	x0, y0, x1, y1 = x0+t0*dx, y0+t0*dy, x0+t1*dx, y0+t1*dy
+
+	steps := int(math.Ceil(math.Max(math.Abs(x1-x0), math.Abs(y1-y0))))
+	for i := 0; i <= steps; i++ {
+		t := 0.0
+		if steps > 0 {
+			t = float64(i) / float64(steps)
+		}
+		c.img.Set(int(math.Floor(x0+t*(x1-x0))), int(math.Floor(y0+t*(y1-y0))), col)
+	}
+}
+
+// clip returns the part of the segment from x0, y0 to x1, y1 inside of the
+// rectangle as the positions t0 and t1 along it, by Liang-Barsky.
+func clip(x0, y0, x1, y1, minx, miny, maxx, maxy float64) (t0, t1 float64, ok bool) {
+	t0, t1 = 0, 1
+	dx, dy := x1-x0, y1-y0
+	for _, pq := range [4][2]float64{
+		{-dx, x0 - minx},
+		{dx, maxx - x0},
+		{-dy, y0 - miny},
+		{dy, maxy - y0},
+	} {
+		p, q := pq[0], pq[1]
+		if p == 0 {
+			if q < 0 {
+				return 0, 0, false
+			}
+			continue
+		}
+		r := q / p
+		if p < 0 {
+			t0 = math.Max(t0, r)
+		} else {
+			t1 = math.Min(t1, r)
+		}
+		if t0 > t1 {
+			return 0, 0, false
+		}
+	}
+	return t0, t1, true
+}
+
+// ring draws the edges of the ring.
+func (c *Canvas) ring(ring [][2]float64, col color.Color) {
+	for i := range ring {
+		c.Line(geom.Line{ring[i], ring[(i+1)%len(ring)]}, col)
+	}
+}
+
+// fill fills the rings by the even odd rule, with the color at a third of
+// its opacity.
+func (c *Canvas) fill(rings [][][2]float64, col color.Color) {
+	r, g, b, a := col.RGBA()
+	fill := image.NewUniform(color.RGBA64{uint16(r / 3), uint16(g / 3), uint16(b / 3), uint16(a / 3)})
+	bounds := c.img.Bounds()
+	var xs []float64
+	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+		// the crossings of the rings with the middle of the row
+		y := float64(py) + 0.5
+		xs = xs[:0]
+		for _, ring := range rings {
+			for i := range ring {
+				ax, ay := c.toPixel(ring[i])
+				bx, by := c.toPixel(ring[(i+1)%len(ring)])
+				if (ay <= y) == (by <= y) {
+					continue
+				}
+				xs = append(xs, ax+(y-ay)/(by-ay)*(bx-ax))
+			}
+		}
+		sort.Float64s(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			x0, x1 := int(math.Round(xs[i])), int(math.Round(xs[i+1]))
+			imagedraw.Draw(c.img, image.Rect(x0, py, x1, py+1), fill, image.Point{}, imagedraw.Over)
+		}
+	}
+}
+
+// Geometry draws the geometry: points as squares, lines one pixel wide,
+// and polygons filled with the color at a third of its opacity with their
+// rings drawn over. The members of collections are drawn in turn, and Z
+// and M values are dropped.
+func (c *Canvas) Geometry(g geom.Geometry, col color.Color) error {
+	switch gg := g.(type) {
+	case geom.Pointer:
+		c.Point(gg.XY(), col)
+	case geom.MultiPointer:
+		for _, pt := range gg.Points() {
+			c.Point(pt, col)
+		}
+	case geom.LineStringer:
+		ls := gg.Vertices()
+		for i := 0; i+1 < len(ls); i++ {
+			c.Line(geom.Line{ls[i], ls[i+1]}, col)
+		}
+	case geom.MultiLineStringer:
+		for _, ls := range gg.LineStrings() {
+			c.Geometry(geom.LineString(ls), col)
+		}
+	case geom.Polygoner:
+		rings := gg.LinearRings()
+		c.fill(rings, col)
+		for _, ring := range rings {
+			c.ring(ring, col)
+		}
+	case geom.MultiPolygoner:
+		for _, ply := range gg.Polygons() {
+			c.Geometry(geom.Polygon(ply), col)
+		}
+	case geom.Collectioner:
+		for _, m := range gg.Geometries() {
+			if err := c.Geometry(m, col); err != nil {
+				return err
+			}
+		}
+	default:
+		x, ok := xy(g)
+		if !ok {
+			return geom.ErrUnknownGeometry{Geom: g}
+		}
+		return c.Geometry(x, col)
+	}
+	return nil
+}
+
+// xy returns the 2D geometry of the Z, M and ZM geometries, ok is false
+// for other geometries.
+func xy(g geom.Geometry) (_ geom.Geometry, ok bool) {
+	switch gg := g.(type) {
+	case interface{ Point() geom.Point }:
+		return gg.Point(), true
+	case interface{ MultiPoint() geom.MultiPoint }:
+		return gg.MultiPoint(), true
+	case interface{ LineString() geom.LineString }:
+		return gg.LineString(), true
+	case interface{ MultiLineString() geom.MultiLineString }:
+		return gg.MultiLineString(), true
+	case interface{ Polygon() geom.Polygon }:
+		return gg.Polygon(), true
+	case interface{ MultiPolygon() geom.MultiPolygon }:
+		return gg.MultiPolygon(), true
+	default:
+		return g, false
+	}
+}
+
+// Label writes the text with its top left corner at the point. The font
+// has digits, letters, and - . : # ( ) and space; other
+// characters are drawn as blocks.
+func (c *Canvas) Label(pt [2]float64, text string, col color.Color) {
+	x, y := c.toPixel(pt)
+	c.text(int(math.Round(x)), int(math.Round(y)), text, col)
+}
+
+// Lines draws the lines, writing the label of each, if there is one, at
+// its middle.
+func (c *Canvas) Lines(lines []geom.Line, labels []string, col color.Color) {
+	for i, l := range lines {
+		c.Line(l, col)
+		if i < len(labels) && labels[i] != "" {
+			mid := [2]float64{(l[0][0] + l[1][0]) / 2, (l[0][1] + l[1][1]) / 2}
+			c.Label(mid, labels[i], col)
+		}
+	}
+}
+
+// EdgeWalker walks the edges of a triangulation, such as a
+// subdivision.Subdivision.
+type EdgeWalker interface {
+	WalkAllEdges(fn func(e *quadedge.Edge) error) error
+}
+
+// Subdivision draws the edges of the triangulation in the order they are
+// walked, numbering them from 0 if label is true.
+func (c *Canvas) Subdivision(sd EdgeWalker, col color.Color, label bool) error {
+	var (
+		lines  []geom.Line
+		labels []string
+	)
+	err := sd.WalkAllEdges(func(e *quadedge.Edge) error {
+		if e.Orig() == nil || e.Dest() == nil {
+			return nil
+		}
+		lines = append(lines, e.AsLine())
+		if label {
+			labels = append(labels, strconv.Itoa(len(lines)-1))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	c.Lines(lines, labels, col)
+	return nil
+}
+
+// EncodePNG writes the image as a PNG.
+func (c *Canvas) EncodePNG(w io.Writer) error { return png.Encode(w, c.img) }
+
+// WriteFile writes the image as a PNG to the named file.
+func (c *Canvas) WriteFile(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err = c.EncodePNG(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,143 @@
+package draw
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay/subdivision"
+)
+
+func isWhite(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return r == 0xffff && g == 0xffff && b == 0xffff
+}
+
+func TestCanvasGeometry(t *testing.T) {
+	type tcase struct {
+		geom  geom.Geometry
+		yUp   bool
+		white []image.Point
+		drawn []image.Point
+		err   error
+	}
+
+	ply := geom.Polygon{
+		{{10, 10}, {90, 10}, {90, 90}, {10, 90}},
+		{{40, 40}, {60, 40}, {60, 60}, {40, 60}},
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			c := NewCanvas(geom.Extent{0, 0, 100, 100}, 100)
+			c.SetYUp(tc.yUp)
+			err := c.Geometry(tc.geom, Red)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			img := c.Image()
+			for _, pt := range tc.white {
+				if !isWhite(img.At(pt.X, pt.Y)) {
+					t.Errorf("pixel %v, expected white got %v", pt, img.At(pt.X, pt.Y))
+				}
+			}
+			for _, pt := range tc.drawn {
+				if isWhite(img.At(pt.X, pt.Y)) {
+					t.Errorf("pixel %v, expected drawn got white", pt)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"polygon": {
+			geom:  ply,
+			white: []image.Point{{5, 5}, {50, 50}, {95, 50}},
+			drawn: []image.Point{{20, 20}, {10, 50}, {40, 50}, {80, 80}},
+		},
+		"line y down": {
+			geom:  geom.LineString{{0, 10}, {100, 10}},
+			white: []image.Point{{50, 90}, {50, 50}},
+			drawn: []image.Point{{0, 10}, {50, 10}, {99, 10}},
+		},
+		"line y up": {
+			geom:  geom.LineString{{0, 10}, {100, 10}},
+			yUp:   true,
+			white: []image.Point{{50, 10}},
+			drawn: []image.Point{{50, 90}},
+		},
+		"line off the image": {
+			geom:  geom.Line{{-1e9, 50}, {1e9, 50}},
+			drawn: []image.Point{{0, 50}, {99, 50}},
+		},
+		"point z": {
+			geom:  geom.PointZ{50, 50, 3},
+			white: []image.Point{{10, 10}},
+			drawn: []image.Point{{50, 50}, {48, 48}, {52, 52}},
+		},
+		"collection": {
+			geom:  geom.Collection{geom.Point{20, 20}, geom.MultiPoint{{80, 80}}},
+			drawn: []image.Point{{20, 20}, {80, 80}},
+		},
+		"unknown": {
+			geom: "POINT (1 2)",
+			err:  geom.ErrUnknownGeometry{Geom: "POINT (1 2)"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestCanvasLabel(t *testing.T) {
+	c := NewCanvas(geom.Extent{0, 0, 100, 100}, 100)
+	c.Label([2]float64{10, 10}, "12", Blue)
+	img := c.Image()
+	var drawn int
+	for y := 0; y < 30; y++ {
+		for x := 0; x < 40; x++ {
+			if !isWhite(img.At(x, y)) {
+				drawn++
+			}
+		}
+	}
+	// 1 has 8 pixels and 2 has 11, each 2 by 2
+	if drawn != (8+11)*4 {
+		t.Errorf("drawn pixels, expected %v got %v", (8+11)*4, drawn)
+	}
+	if !isWhite(img.At(9, 9)) || isWhite(img.At(10, 12)) {
+		t.Errorf("label not at its point")
+	}
+}
+
+func TestCanvasSubdivision(t *testing.T) {
+	sd := subdivision.New(geom.Point{0, 0}, geom.Point{100, 0}, geom.Point{0, 100})
+	c := NewCanvas(geom.Extent{0, 0, 100, 100}, 200)
+	if err := c.Subdivision(sd, Green, true); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	img := c.Image()
+	// the bottom edge, y is down
+	if isWhite(img.At(100, 0)) {
+		t.Errorf("edge along y=0 not drawn")
+	}
+	if !isWhite(img.At(180, 180)) {
+		t.Errorf("expected nothing drawn outside the triangle")
+	}
+
+	var buf bytes.Buffer
+	if err := c.EncodePNG(&buf); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	got, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if want := image.Rect(0, 0, 200, 200); got.Bounds() != want {
+		t.Errorf("bounds, expected %v got %v", want, got.Bounds())
+	}
+}
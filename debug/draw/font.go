@@ -0,0 +1,82 @@
+package draw
+
+import (
+	"image"
+	"image/color"
+	imagedraw "image/draw"
+	"unicode"
+)
+
+// glyphs are the characters of the font, each five rows of three pixels,
+// the bits of a row being the pixels from left to right.
+var glyphs = map[rune][5]uint8{
+	'0': {7, 5, 5, 5, 7},
+	'1': {2, 6, 2, 2, 7},
+	'2': {7, 1, 7, 4, 7},
+	'3': {7, 1, 3, 1, 7},
+	'4': {5, 5, 7, 1, 1},
+	'5': {7, 4, 7, 1, 7},
+	'6': {7, 4, 7, 5, 7},
+	'7': {7, 1, 1, 2, 2},
+	'8': {7, 5, 7, 5, 7},
+	'9': {7, 5, 7, 1, 7},
+	'A': {2, 5, 7, 5, 5},
+	'B': {6, 5, 6, 5, 6},
+	'C': {3, 4, 4, 4, 3},
+	'D': {6, 5, 5, 5, 6},
+	'E': {7, 4, 6, 4, 7},
+	'F': {7, 4, 6, 4, 4},
+	'G': {3, 4, 5, 5, 3},
+	'H': {5, 5, 7, 5, 5},
+	'I': {7, 2, 2, 2, 7},
+	'J': {1, 1, 1, 5, 2},
+	'K': {5, 5, 6, 5, 5},
+	'L': {4, 4, 4, 4, 7},
+	'M': {5, 7, 7, 5, 5},
+	'N': {6, 5, 5, 5, 5},
+	'O': {2, 5, 5, 5, 2},
+	'P': {6, 5, 6, 4, 4},
+	'Q': {2, 5, 5, 6, 3},
+	'R': {6, 5, 6, 5, 5},
+	'S': {3, 4, 2, 1, 6},
+	'T': {7, 2, 2, 2, 2},
+	'U': {5, 5, 5, 5, 7},
+	'V': {5, 5, 5, 5, 2},
+	'W': {5, 5, 7, 7, 5},
+	'X': {5, 5, 2, 5, 5},
+	'Y': {5, 5, 2, 2, 2},
+	'Z': {7, 1, 2, 4, 7},
+	'-': {0, 0, 7, 0, 0},
+	'.': {0, 0, 0, 0, 2},
+	':': {0, 2, 0, 2, 0},
+	'#': {5, 7, 5, 7, 5},
+	'(': {1, 2, 2, 2, 1},
+	')': {4, 2, 2, 2, 4},
+	' ': {},
+}
+
+// glyphScale is the size in pixels of the pixels of the font
+const glyphScale = 2
+
+// text writes the text with its top left corner at x, y on the image.
+// Lower case letters are written as upper case.
+func (c *Canvas) text(x, y int, text string, col color.Color) {
+	src := image.NewUniform(col)
+	for _, r := range text {
+		g, ok := glyphs[unicode.ToUpper(r)]
+		if !ok {
+			g = [5]uint8{7, 7, 7, 7, 7}
+		}
+		for row, bits := range g {
+			for px := 0; px < 3; px++ {
+				if bits&(4>>uint(px)) == 0 {
+					continue
+				}
+				rect := image.Rect(x+px*glyphScale, y+row*glyphScale, x+(px+1)*glyphScale, y+(row+1)*glyphScale)
+				imagedraw.Draw(c.img, rect, src, image.Point{}, imagedraw.Over)
+			}
+		}
+		// a pixel of the font between characters
+		x += 4 * glyphScale
+	}
+}
@@ -0,0 +1,293 @@
+package flatgeobuf
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// field indexes of the Feature table
+const (
+	featureGeometry = iota
+	featureProperties
+	featureColumns
+	featureFields
+)
+
+// readFeature reads the Feature table. Geometries are of the type typ,
+// unless it is Unknown. The properties are of the columns, unless the
+// feature has its own.
+func readFeature(b []byte, typ GeometryType, cols []Column) (Feature, error) {
+	fb := &fbBuffer{b: b}
+	t := fb.root()
+	var f Feature
+	var s shape
+	gt, hasGeometry := t.table(featureGeometry)
+	if hasGeometry {
+		s = readShape(gt, typ)
+	}
+	if fc := readColumns(t.tables(featureColumns)); fc != nil {
+		cols = fc
+	}
+	props := t.bytes(featureProperties)
+	if fb.invalid {
+		return f, ErrInvalidFeature
+	}
+
+	if hasGeometry {
+		var err error
+		if f.Geometry, err = s.geometry(); err != nil {
+			return f, err
+		}
+	}
+	var err error
+	f.Properties, err = readProperties(props, cols)
+	return f, err
+}
+
+// buildFeature returns the Feature table of the shape and properties, with
+// its size before it
+func buildFeature(s *shape, props []byte) []byte {
+	var b fbBuilder
+	var geomOff, propsOff int
+	if s != nil {
+		geomOff = s.build(&b)
+	}
+	if len(props) > 0 {
+		propsOff = b.createBytes(props, false)
+	}
+	b.startTable(featureFields)
+	if propsOff != 0 {
+		b.addOffset(featureProperties, propsOff)
+	}
+	if geomOff != 0 {
+		b.addOffset(featureGeometry, geomOff)
+	}
+	return b.finish(b.endTable())
+}
+
+// readProperties reads the properties: for each value, the index of its
+// column as a uint16, then the value
+func readProperties(b []byte, cols []Column) (map[string]interface{}, error) {
+	props := make(map[string]interface{})
+	for len(b) > 0 {
+		if len(b) < 2 {
+			return nil, ErrInvalidFeature
+		}
+		i := int(binary.LittleEndian.Uint16(b))
+		b = b[2:]
+		if i >= len(cols) {
+			return nil, ErrInvalidFeature
+		}
+
+		n := columnSize(cols[i].Type)
+		if n == 0 {
+			if len(b) < 4 {
+				return nil, ErrInvalidFeature
+			}
+			n = int(binary.LittleEndian.Uint32(b))
+			b = b[4:]
+		}
+		if n < 0 || n > len(b) {
+			return nil, ErrInvalidFeature
+		}
+		v := b[:n]
+		b = b[n:]
+
+		var val interface{}
+		switch cols[i].Type {
+		case Byte:
+			val = int8(v[0])
+		case UByte:
+			val = v[0]
+		case Bool:
+			val = v[0] != 0
+		case Short:
+			val = int16(binary.LittleEndian.Uint16(v))
+		case UShort:
+			val = binary.LittleEndian.Uint16(v)
+		case Int:
+			val = int32(binary.LittleEndian.Uint32(v))
+		case UInt:
+			val = binary.LittleEndian.Uint32(v)
+		case Long:
+			val = int64(binary.LittleEndian.Uint64(v))
+		case ULong:
+			val = binary.LittleEndian.Uint64(v)
+		case Float:
+			val = math.Float32frombits(binary.LittleEndian.Uint32(v))
+		case Double:
+			val = math.Float64frombits(binary.LittleEndian.Uint64(v))
+		case String, JSON, DateTime:
+			val = string(v)
+		case Binary:
+			val = append([]byte(nil), v...)
+		default:
+			return nil, ErrInvalidFeature
+		}
+		props[cols[i].Name] = val
+	}
+	return props, nil
+}
+
+// columnSize returns the size of the values of the column type, or zero if
+// their size is before them
+func columnSize(typ ColumnType) int {
+	switch typ {
+	case Byte, UByte, Bool:
+		return 1
+	case Short, UShort:
+		return 2
+	case Int, UInt, Float:
+		return 4
+	case Long, ULong, Double:
+		return 8
+	}
+	return 0
+}
+
+// buildProperties returns the values of the columns that the properties
+// have; properties that are not of a column are ignored
+func buildProperties(props map[string]interface{}, cols []Column) ([]byte, error) {
+	var b []byte
+	for i, c := range cols {
+		v, ok := props[c.Name]
+		if !ok || v == nil {
+			continue
+		}
+		var col [2]byte
+		binary.LittleEndian.PutUint16(col[:], uint16(i))
+		b = append(b, col[:]...)
+
+		var err error
+		if b, err = appendValue(b, c, v); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// appendValue appends the value of the column. Integer columns take any
+// integer that fits in them, Float and Double columns take any number.
+func appendValue(b []byte, c Column, v interface{}) ([]byte, error) {
+	invalid := ErrInvalidValue{Column: c, Value: v}
+	var buf [8]byte
+	switch c.Type {
+	case Bool:
+		t, ok := v.(bool)
+		if !ok {
+			return nil, invalid
+		}
+		if t {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+
+	case Byte, Short, Int, Long:
+		n, ok := toInt64(v)
+		bits := 8 * uint(columnSize(c.Type))
+		if !ok || n < -1<<(bits-1) || n > 1<<(bits-1)-1 {
+			return nil, invalid
+		}
+		binary.LittleEndian.PutUint64(buf[:], uint64(n))
+		return append(b, buf[:columnSize(c.Type)]...), nil
+
+	case UByte, UShort, UInt, ULong:
+		n, ok := toUint64(v)
+		bits := 8 * uint(columnSize(c.Type))
+		if !ok || (bits < 64 && n > 1<<bits-1) {
+			return nil, invalid
+		}
+		binary.LittleEndian.PutUint64(buf[:], n)
+		return append(b, buf[:columnSize(c.Type)]...), nil
+
+	case Float, Double:
+		var f float64
+		switch n := v.(type) {
+		case float32:
+			f = float64(n)
+		case float64:
+			f = n
+		default:
+			i, ok := toInt64(v)
+			if !ok {
+				return nil, invalid
+			}
+			f = float64(i)
+		}
+		if c.Type == Float {
+			binary.LittleEndian.PutUint32(buf[:], math.Float32bits(float32(f)))
+			return append(b, buf[:4]...), nil
+		}
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(f))
+		return append(b, buf[:]...), nil
+
+	case String, JSON, DateTime, Binary:
+		var p []byte
+		switch s := v.(type) {
+		case string:
+			if c.Type == Binary {
+				return nil, invalid
+			}
+			p = []byte(s)
+		case time.Time:
+			if c.Type != DateTime {
+				return nil, invalid
+			}
+			p = []byte(s.Format(time.RFC3339Nano))
+		case []byte:
+			if c.Type != Binary {
+				return nil, invalid
+			}
+			p = s
+		default:
+			return nil, invalid
+		}
+		binary.LittleEndian.PutUint32(buf[:], uint32(len(p)))
+		b = append(b, buf[:4]...)
+		return append(b, p...), nil
+	}
+	return nil, invalid
+}
+
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint8:
+		return int64(n), true
+	case uint16:
+		return int64(n), true
+	case uint32:
+		return int64(n), true
+	case uint, uint64:
+		u, _ := toUint64(v)
+		if u > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(u), true
+	}
+	return 0, false
+}
+
+func toUint64(v interface{}) (uint64, bool) {
+	switch n := v.(type) {
+	case uint:
+		return uint64(n), true
+	case uint64:
+		return n, true
+	}
+	i, ok := toInt64(v)
+	if !ok || i < 0 {
+		return 0, false
+	}
+	return uint64(i), true
+}
@@ -0,0 +1,398 @@
+package flatgeobuf
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// The header and features are FlatBuffers tables. Only what the FlatGeobuf
+// schema needs is implemented here: tables of scalars, strings, tables and
+// vectors of scalars and tables.
+
+// fbBuffer is a FlatBuffers buffer being read. Reads outside of it set
+// invalid and return zero values, so a table can be read without checking
+// each field, and invalid checked at the end.
+type fbBuffer struct {
+	b       []byte
+	invalid bool
+}
+
+func (fb *fbBuffer) in(pos, n int) bool {
+	if pos < 0 || n < 0 || pos > len(fb.b)-n {
+		fb.invalid = true
+		return false
+	}
+	return true
+}
+
+func (fb *fbBuffer) uint16(pos int) uint16 {
+	if !fb.in(pos, 2) {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(fb.b[pos:])
+}
+
+func (fb *fbBuffer) uint32(pos int) uint32 {
+	if !fb.in(pos, 4) {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(fb.b[pos:])
+}
+
+func (fb *fbBuffer) uint64(pos int) uint64 {
+	if !fb.in(pos, 8) {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(fb.b[pos:])
+}
+
+// deref returns the position the offset at pos refers to
+func (fb *fbBuffer) deref(pos int) int {
+	off := fb.uint32(pos)
+	if off == 0 || int64(off) > int64(len(fb.b)) {
+		fb.invalid = true
+		return 0
+	}
+	return pos + int(off)
+}
+
+// root returns the table the buffer starts with an offset to
+func (fb *fbBuffer) root() fbTable {
+	return fb.table(fb.deref(0))
+}
+
+// table returns the table at pos
+func (fb *fbBuffer) table(pos int) fbTable {
+	t := fbTable{fb: fb, pos: pos}
+	t.vtable = pos - int(int32(fb.uint32(pos)))
+	t.vtableLen = int(fb.uint16(t.vtable))
+	if t.vtableLen < 4 || !fb.in(t.vtable, t.vtableLen) {
+		fb.invalid = true
+		t.vtableLen = 0
+	}
+	return t
+}
+
+// fbTable is a table of a FlatBuffers buffer being read
+type fbTable struct {
+	fb        *fbBuffer
+	pos       int
+	vtable    int
+	vtableLen int
+}
+
+// field returns the position of the field, or zero if it is not set
+func (t fbTable) field(i int) int {
+	o := 4 + 2*i
+	if o+2 > t.vtableLen {
+		return 0
+	}
+	off := int(t.fb.uint16(t.vtable + o))
+	if off == 0 {
+		return 0
+	}
+	return t.pos + off
+}
+
+func (t fbTable) uint8(i int, def uint8) uint8 {
+	pos := t.field(i)
+	if pos == 0 || !t.fb.in(pos, 1) {
+		return def
+	}
+	return t.fb.b[pos]
+}
+
+func (t fbTable) bool(i int, def bool) bool {
+	var d uint8
+	if def {
+		d = 1
+	}
+	return t.uint8(i, d) != 0
+}
+
+func (t fbTable) uint16(i int, def uint16) uint16 {
+	if pos := t.field(i); pos != 0 {
+		return t.fb.uint16(pos)
+	}
+	return def
+}
+
+func (t fbTable) int32(i int, def int32) int32 {
+	if pos := t.field(i); pos != 0 {
+		return int32(t.fb.uint32(pos))
+	}
+	return def
+}
+
+func (t fbTable) uint64(i int, def uint64) uint64 {
+	if pos := t.field(i); pos != 0 {
+		return t.fb.uint64(pos)
+	}
+	return def
+}
+
+// vector returns the position of the first element of the vector, and the
+// number of elements, checking they are in the buffer
+func (t fbTable) vector(i int, elemLen int) (start, n int) {
+	pos := t.field(i)
+	if pos == 0 {
+		return 0, 0
+	}
+	pos = t.fb.deref(pos)
+	n = int(t.fb.uint32(pos))
+	if int64(n)*int64(elemLen) > int64(len(t.fb.b)) || !t.fb.in(pos+4, n*elemLen) {
+		t.fb.invalid = true
+		return 0, 0
+	}
+	return pos + 4, n
+}
+
+func (t fbTable) bytes(i int) []byte {
+	start, n := t.vector(i, 1)
+	return t.fb.b[start : start+n]
+}
+
+func (t fbTable) string(i int) string {
+	return string(t.bytes(i))
+}
+
+func (t fbTable) float64s(i int) []float64 {
+	start, n := t.vector(i, 8)
+	if n == 0 {
+		return nil
+	}
+	fs := make([]float64, n)
+	for k := range fs {
+		fs[k] = math.Float64frombits(binary.LittleEndian.Uint64(t.fb.b[start+8*k:]))
+	}
+	return fs
+}
+
+func (t fbTable) uint32s(i int) []uint32 {
+	start, n := t.vector(i, 4)
+	if n == 0 {
+		return nil
+	}
+	us := make([]uint32, n)
+	for k := range us {
+		us[k] = binary.LittleEndian.Uint32(t.fb.b[start+4*k:])
+	}
+	return us
+}
+
+// table returns the table of the field, and whether it is set
+func (t fbTable) table(i int) (fbTable, bool) {
+	pos := t.field(i)
+	if pos == 0 {
+		return fbTable{}, false
+	}
+	return t.fb.table(t.fb.deref(pos)), true
+}
+
+func (t fbTable) tables(i int) []fbTable {
+	start, n := t.vector(i, 4)
+	if n == 0 {
+		return nil
+	}
+	ts := make([]fbTable, n)
+	for k := range ts {
+		ts[k] = t.fb.table(t.fb.deref(start + 4*k))
+		if t.fb.invalid {
+			return nil
+		}
+	}
+	return ts
+}
+
+// fbBuilder builds a FlatBuffers buffer from the back to the front, as the
+// offsets from tables to what they refer to must be positive: everything a
+// table refers to is built before it. Offsets of what has been built are
+// from the end of the buffer.
+type fbBuilder struct {
+	// the buffer is buf[head:]
+	buf  []byte
+	head int
+	// minAlign is the largest alignment needed
+	minAlign int
+	// fields are the offsets of the fields of the table being built
+	fields   []int
+	tableEnd int
+}
+
+func (b *fbBuilder) offset() int { return len(b.buf) - b.head }
+
+// reserve returns n bytes at the front of the buffer
+func (b *fbBuilder) reserve(n int) []byte {
+	if b.head < n {
+		size := len(b.buf) - b.head
+		newLen := 2*len(b.buf) + n
+		buf := make([]byte, newLen)
+		copy(buf[newLen-size:], b.buf[b.head:])
+		b.buf, b.head = buf, newLen-size
+	}
+	b.head -= n
+	return b.buf[b.head : b.head+n]
+}
+
+// align pads the front of the buffer so that, once another n bytes are
+// added, it is aligned to size bytes
+func (b *fbBuilder) align(size, n int) {
+	if size > b.minAlign {
+		b.minAlign = size
+	}
+	pad := -(b.offset() + n) & (size - 1)
+	p := b.reserve(pad)
+	for i := range p {
+		p[i] = 0
+	}
+}
+
+func (b *fbBuilder) putUint8(v uint8) { b.reserve(1)[0] = v }
+
+func (b *fbBuilder) putUint16(v uint16) {
+	b.align(2, 0)
+	binary.LittleEndian.PutUint16(b.reserve(2), v)
+}
+
+func (b *fbBuilder) putUint32(v uint32) {
+	b.align(4, 0)
+	binary.LittleEndian.PutUint32(b.reserve(4), v)
+}
+
+func (b *fbBuilder) putUint64(v uint64) {
+	b.align(8, 0)
+	binary.LittleEndian.PutUint64(b.reserve(8), v)
+}
+
+// putOffset adds an offset to what was built at off
+func (b *fbBuilder) putOffset(off int) {
+	b.align(4, 0)
+	b.putUint32(uint32(b.offset() + 4 - off))
+}
+
+// createBytes adds a vector of bytes, with a zero after it if terminated, as
+// for strings, and returns its offset
+func (b *fbBuilder) createBytes(p []byte, terminated bool) int {
+	n := len(p)
+	if terminated {
+		n++
+	}
+	b.align(4, n)
+	if terminated {
+		b.putUint8(0)
+	}
+	copy(b.reserve(len(p)), p)
+	b.putUint32(uint32(len(p)))
+	return b.offset()
+}
+
+func (b *fbBuilder) createString(s string) int {
+	return b.createBytes([]byte(s), true)
+}
+
+func (b *fbBuilder) createFloat64s(fs []float64) int {
+	b.align(4, 8*len(fs))
+	b.align(8, 8*len(fs))
+	p := b.reserve(8 * len(fs))
+	for i, f := range fs {
+		binary.LittleEndian.PutUint64(p[8*i:], math.Float64bits(f))
+	}
+	b.putUint32(uint32(len(fs)))
+	return b.offset()
+}
+
+func (b *fbBuilder) createUint32s(us []uint32) int {
+	b.align(4, 4*len(us))
+	p := b.reserve(4 * len(us))
+	for i, u := range us {
+		binary.LittleEndian.PutUint32(p[4*i:], u)
+	}
+	b.putUint32(uint32(len(us)))
+	return b.offset()
+}
+
+// createOffsets adds a vector of offsets to tables
+func (b *fbBuilder) createOffsets(offs []int) int {
+	b.align(4, 4*len(offs))
+	for i := len(offs) - 1; i >= 0; i-- {
+		b.putOffset(offs[i])
+	}
+	b.putUint32(uint32(len(offs)))
+	return b.offset()
+}
+
+// startTable starts a table with up to n fields
+func (b *fbBuilder) startTable(n int) {
+	b.fields = make([]int, n)
+	b.tableEnd = b.offset()
+}
+
+func (b *fbBuilder) addUint8(i int, v uint8) {
+	b.putUint8(v)
+	b.fields[i] = b.offset()
+}
+
+func (b *fbBuilder) addBool(i int, v bool) {
+	var u uint8
+	if v {
+		u = 1
+	}
+	b.addUint8(i, u)
+}
+
+func (b *fbBuilder) addUint16(i int, v uint16) {
+	b.putUint16(v)
+	b.fields[i] = b.offset()
+}
+
+func (b *fbBuilder) addInt32(i int, v int32) {
+	b.putUint32(uint32(v))
+	b.fields[i] = b.offset()
+}
+
+func (b *fbBuilder) addUint64(i int, v uint64) {
+	b.putUint64(v)
+	b.fields[i] = b.offset()
+}
+
+// addOffset adds a field referring to what was built at off
+func (b *fbBuilder) addOffset(i int, off int) {
+	b.putOffset(off)
+	b.fields[i] = b.offset()
+}
+
+// endTable adds the table's vtable, and returns the offset of the table
+func (b *fbBuilder) endTable() int {
+	// the offset to the vtable is set once the vtable is built
+	b.putUint32(0)
+	table := b.offset()
+
+	n := len(b.fields)
+	for n > 0 && b.fields[n-1] == 0 {
+		n--
+	}
+	for i := n - 1; i >= 0; i-- {
+		var off uint16
+		if b.fields[i] != 0 {
+			off = uint16(table - b.fields[i])
+		}
+		b.putUint16(off)
+	}
+	b.putUint16(uint16(table - b.tableEnd))
+	b.putUint16(uint16(4 + 2*n))
+
+	vtable := b.offset()
+	pos := len(b.buf) - table
+	binary.LittleEndian.PutUint32(b.buf[pos:], uint32(int32(vtable-table)))
+	b.fields = nil
+	return table
+}
+
+// finish adds the offset to the root table, and the size of the buffer
+// before it, and returns the buffer
+func (b *fbBuilder) finish(root int) []byte {
+	b.align(b.minAlign, 8)
+	b.putOffset(root)
+	b.putUint32(uint32(b.offset()))
+	return b.buf[b.head:]
+}
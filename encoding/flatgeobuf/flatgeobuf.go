@@ -0,0 +1,212 @@
+// Package flatgeobuf is for reading and writing FlatGeobuf files: a header
+// describing the features, an optional packed Hilbert R-tree index of
+// their bounding boxes, then the features, each a FlatBuffers table.
+//
+// Specification at https://github.com/flatgeobuf/flatgeobuf
+//
+// A Reader reads the features in order from any io.Reader. A Searcher uses
+// the index to read only the features whose bounding boxes intersect an
+// extent, reading just the parts of the file it needs from an io.ReaderAt;
+// with an HTTPRangeReader these are HTTP range requests, so a large file
+// on a server can be queried without fetching it all.
+//
+// Only x and y coordinates are supported: z, m and t values are ignored
+// when reading, and geometries with them can not be written. The rings of
+// polygons are closed when written, and read as they are stored, closed.
+// Curved geometry types, TINs and polyhedral surfaces are not supported.
+package flatgeobuf
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-spatial/geom"
+)
+
+// magic is the start of every file: "fgb", the major version, "fgb" and the
+// patch version
+var magic = [8]byte{'f', 'g', 'b', 3, 'f', 'g', 'b', 0}
+
+// GeometryType is the type of a geometry.
+type GeometryType uint8
+
+// geometry types
+const (
+	Unknown GeometryType = iota
+	Point
+	LineString
+	Polygon
+	MultiPoint
+	MultiLineString
+	MultiPolygon
+	GeometryCollection
+	CircularString
+	CompoundCurve
+	CurvePolygon
+	MultiCurve
+	MultiSurface
+	Curve
+	Surface
+	PolyhedralSurface
+	TIN
+	Triangle
+)
+
+func (t GeometryType) String() string {
+	switch t {
+	case Unknown:
+		return "Unknown"
+	case Point:
+		return "Point"
+	case LineString:
+		return "LineString"
+	case Polygon:
+		return "Polygon"
+	case MultiPoint:
+		return "MultiPoint"
+	case MultiLineString:
+		return "MultiLineString"
+	case MultiPolygon:
+		return "MultiPolygon"
+	case GeometryCollection:
+		return "GeometryCollection"
+	}
+	return fmt.Sprintf("GeometryType(%d)", uint8(t))
+}
+
+// ColumnType is the type of the values of a column.
+type ColumnType uint8
+
+// column types
+const (
+	// Byte values are int8
+	Byte ColumnType = iota
+	// UByte values are uint8
+	UByte
+	// Bool values are bool
+	Bool
+	// Short values are int16
+	Short
+	// UShort values are uint16
+	UShort
+	// Int values are int32
+	Int
+	// UInt values are uint32
+	UInt
+	// Long values are int64
+	Long
+	// ULong values are uint64
+	ULong
+	// Float values are float32
+	Float
+	// Double values are float64
+	Double
+	// String values are string
+	String
+	// JSON values are string
+	JSON
+	// DateTime values are ISO 8601 strings; time.Time values are written
+	// in RFC 3339 format
+	DateTime
+	// Binary values are []byte
+	Binary
+)
+
+// Column describes the values of a property of the features.
+type Column struct {
+	Name        string
+	Type        ColumnType
+	Title       string
+	Description string
+	Metadata    string
+}
+
+// CRS is the coordinate reference system of the features.
+type CRS struct {
+	// Org is the organization the code is of, such as "EPSG"
+	Org  string
+	Code int32
+	// CodeString is the code, for codes that are not numbers
+	CodeString  string
+	Name        string
+	Description string
+	WKT         string
+}
+
+// Header describes the features of a file.
+type Header struct {
+	Name string
+	// GeometryType is the type of all the geometries, or Unknown if they
+	// are of different types
+	GeometryType GeometryType
+	Columns      []Column
+	CRS          *CRS
+	Title        string
+	Description  string
+	Metadata     string
+
+	// The following are set when reading, and ignored when writing.
+
+	// Extent is the bounding box of the features, if the file has one
+	Extent *geom.Extent
+	// FeaturesCount is the number of features, or zero if it is not known
+	FeaturesCount uint64
+	// IndexNodeSize is the number of children of each node of the index, or
+	// zero if there is no index
+	IndexNodeSize uint16
+	// HasZ, HasM, HasT and HasTM report whether the geometries have z, m, t
+	// and tm values, which are ignored
+	HasZ, HasM, HasT, HasTM bool
+}
+
+// Feature is a geometry and its properties.
+type Feature struct {
+	Geometry geom.Geometry
+	// Properties are the values of the columns, by name, of the types
+	// given by the ColumnTypes. Columns with no value are not included.
+	Properties map[string]interface{}
+}
+
+var (
+	// ErrInvalidFile is returned when a file does not start with the
+	// FlatGeobuf magic bytes and a valid header.
+	ErrInvalidFile = errors.New("flatgeobuf: invalid file")
+	// ErrInvalidFeature is returned when a feature can not be read.
+	ErrInvalidFeature = errors.New("flatgeobuf: invalid feature")
+	// ErrNoIndex is returned when searching a file without an index.
+	ErrNoIndex = errors.New("flatgeobuf: file has no index")
+	// ErrClosed is returned when writing to a closed Writer.
+	ErrClosed = errors.New("flatgeobuf: writer is closed")
+)
+
+// ErrUnsupportedGeometryType is returned when reading a geometry of a type
+// that is not supported.
+type ErrUnsupportedGeometryType struct {
+	Typ GeometryType
+}
+
+func (e ErrUnsupportedGeometryType) Error() string {
+	return fmt.Sprintf("flatgeobuf: unsupported geometry type %v", e.Typ)
+}
+
+// ErrGeometryTypeMismatch is returned when writing a geometry that is not
+// of the geometry type of the header.
+type ErrGeometryTypeMismatch struct {
+	Typ  GeometryType
+	Geom geom.Geometry
+}
+
+func (e ErrGeometryTypeMismatch) Error() string {
+	return fmt.Sprintf("flatgeobuf: can not write %T as %v", e.Geom, e.Typ)
+}
+
+// ErrInvalidValue is returned when writing a property value that is not of
+// the type of its column.
+type ErrInvalidValue struct {
+	Column Column
+	Value  interface{}
+}
+
+func (e ErrInvalidValue) Error() string {
+	return fmt.Sprintf("flatgeobuf: invalid value %v (%T) for column %v", e.Value, e.Value, e.Column.Name)
+}
@@ -0,0 +1,415 @@
+package flatgeobuf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/geom"
+)
+
+func writeFile(t *testing.T, hdr Header, features []Feature, opts ...WriterOption) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, hdr, opts...)
+	if err != nil {
+		t.Fatalf("new writer error, expected nil got %v", err)
+	}
+	for _, f := range features {
+		if err := w.Write(f); err != nil {
+			t.Fatalf("write error, expected nil got %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close error, expected nil got %v", err)
+	}
+	return buf.Bytes()
+}
+
+func readAll(t *testing.T, b []byte) (Header, []Feature) {
+	t.Helper()
+	r, err := NewReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("new reader error, expected nil got %v", err)
+	}
+	var features []Feature
+	for {
+		f, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next error, expected nil got %v", err)
+		}
+		features = append(features, f)
+	}
+	return r.Header(), features
+}
+
+func TestRoundTrip(t *testing.T) {
+	type tcase struct {
+		hdr      Header
+		features []Feature
+		// expected are the features read, if not the same as written
+		expected []Feature
+		opts     []WriterOption
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			b := writeFile(t, tc.hdr, tc.features, tc.opts...)
+			hdr, got := readAll(t, b)
+			if hdr.FeaturesCount != uint64(len(tc.features)) {
+				t.Errorf("features count, expected %v got %v", len(tc.features), hdr.FeaturesCount)
+			}
+			if hdr.Name != tc.hdr.Name || hdr.GeometryType != tc.hdr.GeometryType || !reflect.DeepEqual(hdr.Columns, tc.hdr.Columns) || !reflect.DeepEqual(hdr.CRS, tc.hdr.CRS) {
+				t.Errorf("header, expected %+v got %+v", tc.hdr, hdr)
+			}
+			expected := tc.expected
+			if expected == nil {
+				expected = tc.features
+			}
+			// the features are in the order of the index
+			if hdr.IndexNodeSize > 0 {
+				sortFeatures(expected)
+				sortFeatures(got)
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("features, expected %v got %v", expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"points": {
+			hdr: Header{
+				Name:         "points",
+				GeometryType: Point,
+				Columns:      []Column{{Name: "id", Type: Int}, {Name: "name", Type: String, Title: "Name"}},
+				CRS:          &CRS{Org: "EPSG", Code: 4326},
+			},
+			features: []Feature{
+				{Geometry: geom.Point{1, 2}, Properties: map[string]interface{}{"id": int32(1), "name": "one"}},
+				{Geometry: geom.Point{-3, 4}, Properties: map[string]interface{}{"id": int32(2)}},
+				{Geometry: geom.Point{5, -6}, Properties: map[string]interface{}{}},
+			},
+		},
+		"no index": {
+			hdr: Header{GeometryType: LineString},
+			features: []Feature{
+				{Geometry: geom.LineString{{0, 0}, {1, 1}, {2, 0}}, Properties: map[string]interface{}{}},
+				{Geometry: geom.LineString{{5, 5}, {6, 6}}, Properties: map[string]interface{}{}},
+			},
+			opts: []WriterOption{WithIndexNodeSize(0)},
+		},
+		"polygons": {
+			hdr: Header{GeometryType: Polygon},
+			features: []Feature{
+				{Geometry: geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, {{2, 2}, {2, 4}, {4, 4}, {2, 2}}}},
+				{Geometry: geom.Polygon{{{20, 20}, {30, 20}, {30, 30}}}},
+			},
+			expected: []Feature{
+				{
+					Geometry:   geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}, {{2, 2}, {2, 4}, {4, 4}, {2, 2}}},
+					Properties: map[string]interface{}{},
+				},
+				{
+					Geometry:   geom.Polygon{{{20, 20}, {30, 20}, {30, 30}, {20, 20}}},
+					Properties: map[string]interface{}{},
+				},
+			},
+		},
+		"mixed": {
+			hdr: Header{GeometryType: Unknown},
+			features: []Feature{
+				{Geometry: geom.MultiPoint{{1, 1}, {2, 2}}, Properties: map[string]interface{}{}},
+				{Geometry: geom.MultiLineString{{{0, 0}, {1, 0}}, {{0, 1}, {1, 1}, {2, 1}}}, Properties: map[string]interface{}{}},
+				{Geometry: geom.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}, {{{5, 5}, {6, 5}, {6, 6}, {5, 5}}}}, Properties: map[string]interface{}{}},
+				{Geometry: geom.Collection{geom.Point{3, 3}, geom.LineString{{4, 4}, {5, 5}}}, Properties: map[string]interface{}{}},
+				{Properties: map[string]interface{}{}},
+			},
+		},
+		"one feature": {
+			hdr: Header{GeometryType: Point},
+			features: []Feature{
+				{Geometry: geom.Point{1, 1}, Properties: map[string]interface{}{}},
+			},
+		},
+		"no features": {
+			hdr: Header{GeometryType: Point},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+// sortFeatures sorts the features by their first x value
+func sortFeatures(fs []Feature) {
+	first := func(f Feature) float64 {
+		x := math.Inf(1)
+		geom.Walk(f.Geometry, func(pt [2]float64) error {
+			x = math.Min(x, pt[0])
+			return nil
+		})
+		return x
+	}
+	sort.SliceStable(fs, func(i, j int) bool { return first(fs[i]) < first(fs[j]) })
+}
+
+func TestProperties(t *testing.T) {
+	cols := []Column{
+		{Name: "byte", Type: Byte},
+		{Name: "ubyte", Type: UByte},
+		{Name: "bool", Type: Bool},
+		{Name: "short", Type: Short},
+		{Name: "ushort", Type: UShort},
+		{Name: "int", Type: Int},
+		{Name: "uint", Type: UInt},
+		{Name: "long", Type: Long},
+		{Name: "ulong", Type: ULong},
+		{Name: "float", Type: Float},
+		{Name: "double", Type: Double},
+		{Name: "string", Type: String},
+		{Name: "json", Type: JSON},
+		{Name: "datetime", Type: DateTime},
+		{Name: "binary", Type: Binary},
+	}
+	props := map[string]interface{}{
+		"byte":     -5,
+		"ubyte":    200,
+		"bool":     true,
+		"short":    int16(-300),
+		"ushort":   60000,
+		"int":      -70000,
+		"uint":     uint32(4000000000),
+		"long":     int64(-1) << 40,
+		"ulong":    uint64(1) << 63,
+		"float":    1.5,
+		"double":   math.Pi,
+		"string":   "héllo",
+		"json":     `{"a":1}`,
+		"datetime": time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		"binary":   []byte{0, 1, 2},
+		"other":    "ignored",
+	}
+	expected := map[string]interface{}{
+		"byte":     int8(-5),
+		"ubyte":    uint8(200),
+		"bool":     true,
+		"short":    int16(-300),
+		"ushort":   uint16(60000),
+		"int":      int32(-70000),
+		"uint":     uint32(4000000000),
+		"long":     int64(-1) << 40,
+		"ulong":    uint64(1) << 63,
+		"float":    float32(1.5),
+		"double":   math.Pi,
+		"string":   "héllo",
+		"json":     `{"a":1}`,
+		"datetime": "2020-01-02T03:04:05Z",
+		"binary":   []byte{0, 1, 2},
+	}
+	b := writeFile(t, Header{Columns: cols}, []Feature{{Geometry: geom.Point{0, 0}, Properties: props}})
+	_, got := readAll(t, b)
+	if len(got) != 1 || !reflect.DeepEqual(got[0].Properties, expected) {
+		t.Errorf("properties, expected %v got %v", expected, got)
+	}
+
+	invalid := map[string]Feature{
+		"byte too big":      {Properties: map[string]interface{}{"byte": 128}},
+		"ubyte negative":    {Properties: map[string]interface{}{"ubyte": -1}},
+		"bool not bool":     {Properties: map[string]interface{}{"bool": 1}},
+		"string not string": {Properties: map[string]interface{}{"string": 1}},
+		"binary string":     {Properties: map[string]interface{}{"binary": "a"}},
+	}
+	for name, f := range invalid {
+		t.Run(name, func(t *testing.T) {
+			w, _ := NewWriter(&bytes.Buffer{}, Header{Columns: cols})
+			if _, ok := w.Write(f).(ErrInvalidValue); !ok {
+				t.Errorf("error, expected ErrInvalidValue got %v", w.Write(f))
+			}
+		})
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	w, err := NewWriter(&bytes.Buffer{}, Header{GeometryType: Point})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if _, ok := w.Write(Feature{Geometry: geom.LineString{{0, 0}, {1, 1}}}).(ErrGeometryTypeMismatch); !ok {
+		t.Errorf("error, expected ErrGeometryTypeMismatch")
+	}
+	if _, ok := w.Write(Feature{Geometry: geom.PointZ{0, 0, 0}}).(geom.ErrUnknownGeometry); !ok {
+		t.Errorf("error, expected ErrUnknownGeometry")
+	}
+	w.Close()
+	if err := w.Write(Feature{}); err != ErrClosed {
+		t.Errorf("error, expected %v got %v", ErrClosed, err)
+	}
+	if _, err := NewWriter(&bytes.Buffer{}, Header{GeometryType: TIN}); err == nil {
+		t.Errorf("error, expected ErrUnsupportedGeometryType got nil")
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	valid := writeFile(t, Header{GeometryType: Point}, []Feature{{Geometry: geom.Point{1, 1}}})
+	// the index is after the magic bytes and the header and its size
+	index := 12 + int(binary.LittleEndian.Uint32(valid[8:]))
+	tests := map[string][]byte{
+		"empty":       {},
+		"bad magic":   append([]byte("fgb\x02"), valid[4:]...),
+		"no header":   valid[:8],
+		"bad header":  append(append([]byte(nil), valid[:12]...), make([]byte, index-12)...),
+		"short index": valid[:index+nodeLen],
+	}
+	for name, b := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewReader(bytes.NewReader(b)); err != ErrInvalidFile {
+				t.Errorf("error, expected %v got %v", ErrInvalidFile, err)
+			}
+		})
+	}
+
+	r, err := NewReader(bytes.NewReader(valid[:len(valid)-4]))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if _, err := r.Next(); err != ErrInvalidFeature {
+		t.Errorf("error, expected %v got %v", ErrInvalidFeature, err)
+	}
+}
+
+// countingReaderAt counts the reads
+type countingReaderAt struct {
+	r     io.ReaderAt
+	reads int
+}
+
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	c.reads++
+	return c.r.ReadAt(p, off)
+}
+
+func gridFeatures(n int) []Feature {
+	var fs []Feature
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			fs = append(fs, Feature{
+				Geometry:   geom.Point{float64(x), float64(y)},
+				Properties: map[string]interface{}{"id": int32(x*n + y)},
+			})
+		}
+	}
+	return fs
+}
+
+func TestSearch(t *testing.T) {
+	hdr := Header{GeometryType: Point, Columns: []Column{{Name: "id", Type: Int}}}
+	b := writeFile(t, hdr, gridFeatures(50), WithIndexNodeSize(4))
+
+	type tcase struct {
+		ext geom.Extent
+		ids []int32
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			cr := &countingReaderAt{r: bytes.NewReader(b)}
+			s, err := NewSearcher(cr)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			var ids []int32
+			err = s.Search(tc.ext, func(f Feature) bool {
+				ids = append(ids, f.Properties["id"].(int32))
+				return true
+			})
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+			if !reflect.DeepEqual(ids, tc.ids) {
+				t.Errorf("ids, expected %v got %v", tc.ids, ids)
+			}
+			// the header, a read for each run of each level of the index,
+			// and two reads for each feature
+			if max := 1 + 20 + 2*len(tc.ids); cr.reads > max {
+				t.Errorf("reads, expected at most %v got %v", max, cr.reads)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"one": {
+			ext: geom.Extent{9.5, 9.5, 10.5, 10.5},
+			ids: []int32{510},
+		},
+		"block": {
+			ext: geom.Extent{1, 1, 2, 3},
+			ids: []int32{51, 52, 53, 101, 102, 103},
+		},
+		"outside": {
+			ext: geom.Extent{100, 100, 101, 101},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	if _, err := NewSearcher(bytes.NewReader(writeFile(t, hdr, gridFeatures(2), WithIndexNodeSize(0)))); err != ErrNoIndex {
+		t.Errorf("error, expected %v got %v", ErrNoIndex, err)
+	}
+}
+
+func TestSearchHTTP(t *testing.T) {
+	hdr := Header{GeometryType: Point, Columns: []Column{{Name: "id", Type: Int}}}
+	b := writeFile(t, hdr, gridFeatures(20))
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.ServeContent(w, r, "grid.fgb", time.Time{}, bytes.NewReader(b))
+	}))
+	defer srv.Close()
+
+	s, err := NewSearcher(HTTPRangeReader{URL: srv.URL})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	var got []geom.Geometry
+	err = s.Search(geom.Extent{4.5, 4.5, 5.5, 5.5}, func(f Feature) bool {
+		got = append(got, f.Geometry)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if expected := []geom.Geometry{geom.Point{5, 5}}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("features, expected %v got %v", expected, got)
+	}
+	if requests > 10 {
+		t.Errorf("requests, expected at most 10 got %v", requests)
+	}
+}
+
+func TestHilbert(t *testing.T) {
+	// the order of the curve through the corners of the 2x2 grid
+	corners := [][2]uint32{{0, 0}, {0, 0xFFFF}, {0xFFFF, 0xFFFF}, {0xFFFF, 0}}
+	for i := 1; i < len(corners); i++ {
+		a, b := hilbert(corners[i-1][0], corners[i-1][1]), hilbert(corners[i][0], corners[i][1])
+		if a >= b {
+			t.Errorf("hilbert, expected %v before %v, got %v and %v", corners[i-1], corners[i], a, b)
+		}
+	}
+	if h := hilbert(0xFFFF, 0); h != 0xFFFFFFFF {
+		t.Errorf("hilbert, expected %v got %v", uint32(0xFFFFFFFF), h)
+	}
+}
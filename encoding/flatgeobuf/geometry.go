@@ -0,0 +1,249 @@
+package flatgeobuf
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// field indexes of the Geometry table
+const (
+	geometryEnds = iota
+	geometryXY
+	geometryZ
+	geometryM
+	geometryT
+	geometryTM
+	geometryType
+	geometryParts
+	geometryFields
+)
+
+// shape is a geometry as it is stored: the coordinates of all its points,
+// the ends of its lines or rings, and its parts, for MultiPolygons and
+// GeometryCollections
+type shape struct {
+	typ   GeometryType
+	xy    []float64
+	ends  []uint32
+	parts []shape
+}
+
+// newShape returns the shape of the geometry
+func newShape(g geom.Geometry) (shape, error) {
+	switch gg := g.(type) {
+	case geom.Pointer:
+		xy := gg.XY()
+		s := shape{typ: Point}
+		if !math.IsNaN(xy[0]) || !math.IsNaN(xy[1]) {
+			s.xy = xy[:]
+		}
+		return s, nil
+	case geom.MultiPointer:
+		return shape{typ: MultiPoint, xy: flatten(gg.Points())}, nil
+	case geom.LineStringer:
+		return shape{typ: LineString, xy: flatten(gg.Vertices())}, nil
+	case geom.MultiLineStringer:
+		s := shape{typ: MultiLineString}
+		s.lines(gg.LineStrings(), false)
+		return s, nil
+	case geom.Polygoner:
+		s := shape{typ: Polygon}
+		s.lines(gg.LinearRings(), true)
+		return s, nil
+	case geom.MultiPolygoner:
+		plys := gg.Polygons()
+		s := shape{typ: MultiPolygon, parts: make([]shape, len(plys))}
+		for i := range plys {
+			s.parts[i].typ = Polygon
+			s.parts[i].lines(plys[i], true)
+		}
+		return s, nil
+	case geom.Collectioner:
+		geos := gg.Geometries()
+		s := shape{typ: GeometryCollection, parts: make([]shape, len(geos))}
+		for i := range geos {
+			var err error
+			if s.parts[i], err = newShape(geos[i]); err != nil {
+				return s, err
+			}
+		}
+		return s, nil
+	default:
+		return shape{}, geom.ErrUnknownGeometry{Geom: g}
+	}
+}
+
+func flatten(pts [][2]float64) []float64 {
+	xy := make([]float64, 0, 2*len(pts))
+	for _, pt := range pts {
+		xy = append(xy, pt[0], pt[1])
+	}
+	return xy
+}
+
+// lines sets the points of the shape to those of the lines, closing them
+// if they are rings. The ends are only needed if there is more than one
+// line.
+func (s *shape) lines(lns [][][2]float64, rings bool) {
+	for _, ln := range lns {
+		s.xy = append(s.xy, flatten(ln)...)
+		if rings && len(ln) > 0 && ln[0] != ln[len(ln)-1] {
+			s.xy = append(s.xy, ln[0][0], ln[0][1])
+		}
+		s.ends = append(s.ends, uint32(len(s.xy)/2))
+	}
+	if len(s.ends) == 1 {
+		s.ends = nil
+	}
+}
+
+// extent returns the bounding box of the points of the shape, or nil if it
+// has none
+func (s shape) extent() *geom.Extent {
+	var ext *geom.Extent
+	for i := 0; i+1 < len(s.xy); i += 2 {
+		pt := [2]float64{s.xy[i], s.xy[i+1]}
+		if ext == nil {
+			ext = geom.NewExtent(pt)
+			continue
+		}
+		ext.AddPoints(pt)
+	}
+	for _, p := range s.parts {
+		pe := p.extent()
+		switch {
+		case pe == nil:
+		case ext == nil:
+			ext = pe
+		default:
+			ext.Add(pe)
+		}
+	}
+	return ext
+}
+
+// build adds the shape to the builder as a Geometry table
+func (s shape) build(b *fbBuilder) int {
+	parts := make([]int, len(s.parts))
+	for i := range s.parts {
+		parts[i] = s.parts[i].build(b)
+	}
+	var partsOff, xyOff, endsOff int
+	if len(parts) > 0 {
+		partsOff = b.createOffsets(parts)
+	}
+	if len(s.xy) > 0 {
+		xyOff = b.createFloat64s(s.xy)
+	}
+	if len(s.ends) > 0 {
+		endsOff = b.createUint32s(s.ends)
+	}
+
+	b.startTable(geometryFields)
+	if partsOff != 0 {
+		b.addOffset(geometryParts, partsOff)
+	}
+	if xyOff != 0 {
+		b.addOffset(geometryXY, xyOff)
+	}
+	if endsOff != 0 {
+		b.addOffset(geometryEnds, endsOff)
+	}
+	b.addUint8(geometryType, uint8(s.typ))
+	return b.endTable()
+}
+
+// readShape reads the Geometry table. The type of the geometry is typ,
+// unless typ is Unknown, in which case it is the type in the table.
+func readShape(t fbTable, typ GeometryType) shape {
+	s := shape{typ: typ}
+	if typ == Unknown {
+		s.typ = GeometryType(t.uint8(geometryType, 0))
+	}
+	s.xy = t.float64s(geometryXY)
+	s.ends = t.uint32s(geometryEnds)
+	partTyp := Unknown
+	if s.typ == MultiPolygon {
+		partTyp = Polygon
+	}
+	for _, pt := range t.tables(geometryParts) {
+		s.parts = append(s.parts, readShape(pt, partTyp))
+	}
+	return s
+}
+
+// geometry returns the geometry of the shape
+func (s shape) geometry() (geom.Geometry, error) {
+	if len(s.xy)%2 != 0 {
+		return nil, ErrInvalidFeature
+	}
+	switch s.typ {
+	case Point:
+		if len(s.xy) == 0 {
+			return geom.Point{math.NaN(), math.NaN()}, nil
+		}
+		return geom.Point{s.xy[0], s.xy[1]}, nil
+	case MultiPoint:
+		return geom.MultiPoint(s.points(0, len(s.xy)/2)), nil
+	case LineString:
+		return geom.LineString(s.points(0, len(s.xy)/2)), nil
+	case MultiLineString:
+		lns, err := s.split()
+		return geom.MultiLineString(lns), err
+	case Polygon:
+		rings, err := s.split()
+		return geom.Polygon(rings), err
+	case MultiPolygon:
+		mply := make(geom.MultiPolygon, len(s.parts))
+		for i := range s.parts {
+			rings, err := s.parts[i].split()
+			if err != nil {
+				return nil, err
+			}
+			mply[i] = rings
+		}
+		return mply, nil
+	case GeometryCollection:
+		col := make(geom.Collection, len(s.parts))
+		for i := range s.parts {
+			var err error
+			if col[i], err = s.parts[i].geometry(); err != nil {
+				return nil, err
+			}
+		}
+		return col, nil
+	default:
+		return nil, ErrUnsupportedGeometryType{Typ: s.typ}
+	}
+}
+
+// points returns the points from the start up to the end
+func (s shape) points(start, end int) [][2]float64 {
+	pts := make([][2]float64, end-start)
+	for i := range pts {
+		pts[i] = [2]float64{s.xy[2*(start+i)], s.xy[2*(start+i)+1]}
+	}
+	return pts
+}
+
+// split returns the lines or rings of the shape
+func (s shape) split() ([][][2]float64, error) {
+	n := len(s.xy) / 2
+	if len(s.ends) == 0 {
+		if n == 0 {
+			return [][][2]float64{}, nil
+		}
+		return [][][2]float64{s.points(0, n)}, nil
+	}
+	lns := make([][][2]float64, len(s.ends))
+	start := 0
+	for i, end := range s.ends {
+		if int(end) < start || int(end) > n {
+			return nil, ErrInvalidFeature
+		}
+		lns[i] = s.points(start, int(end))
+		start = int(end)
+	}
+	return lns, nil
+}
@@ -0,0 +1,173 @@
+package flatgeobuf
+
+import (
+	"github.com/go-spatial/geom"
+)
+
+// field indexes of the Header table
+const (
+	headerName = iota
+	headerEnvelope
+	headerGeometryType
+	headerHasZ
+	headerHasM
+	headerHasT
+	headerHasTM
+	headerColumns
+	headerFeaturesCount
+	headerIndexNodeSize
+	headerCRS
+	headerTitle
+	headerDescription
+	headerMetadata
+	headerFields
+)
+
+// field indexes of the Column table
+const (
+	columnName = iota
+	columnType
+	columnTitle
+	columnDescription
+	columnWidth
+	columnPrecision
+	columnScale
+	columnNullable
+	columnUnique
+	columnPrimaryKey
+	columnMetadata
+	columnFields
+)
+
+// field indexes of the Crs table
+const (
+	crsOrg = iota
+	crsCode
+	crsName
+	crsDescription
+	crsWKT
+	crsCodeString
+	crsFields
+)
+
+// readHeader reads the Header table
+func readHeader(b []byte) (Header, error) {
+	fb := &fbBuffer{b: b}
+	t := fb.root()
+	hdr := Header{
+		Name:          t.string(headerName),
+		GeometryType:  GeometryType(t.uint8(headerGeometryType, 0)),
+		HasZ:          t.bool(headerHasZ, false),
+		HasM:          t.bool(headerHasM, false),
+		HasT:          t.bool(headerHasT, false),
+		HasTM:         t.bool(headerHasTM, false),
+		Columns:       readColumns(t.tables(headerColumns)),
+		FeaturesCount: t.uint64(headerFeaturesCount, 0),
+		IndexNodeSize: t.uint16(headerIndexNodeSize, 16),
+		Title:         t.string(headerTitle),
+		Description:   t.string(headerDescription),
+		Metadata:      t.string(headerMetadata),
+	}
+	if env := t.float64s(headerEnvelope); len(env) >= 4 {
+		hdr.Extent = &geom.Extent{env[0], env[1], env[2], env[3]}
+	}
+	if ct, ok := t.table(headerCRS); ok {
+		hdr.CRS = &CRS{
+			Org:         ct.string(crsOrg),
+			Code:        ct.int32(crsCode, 0),
+			Name:        ct.string(crsName),
+			Description: ct.string(crsDescription),
+			WKT:         ct.string(crsWKT),
+			CodeString:  ct.string(crsCodeString),
+		}
+	}
+	if fb.invalid {
+		return Header{}, ErrInvalidFile
+	}
+	return hdr, nil
+}
+
+func readColumns(ts []fbTable) []Column {
+	if len(ts) == 0 {
+		return nil
+	}
+	cols := make([]Column, len(ts))
+	for i, ct := range ts {
+		cols[i] = Column{
+			Name:        ct.string(columnName),
+			Type:        ColumnType(ct.uint8(columnType, 0)),
+			Title:       ct.string(columnTitle),
+			Description: ct.string(columnDescription),
+			Metadata:    ct.string(columnMetadata),
+		}
+	}
+	return cols
+}
+
+// buildHeader returns the Header table, with its size before it
+func buildHeader(hdr Header) []byte {
+	var b fbBuilder
+	str := func(s string) int {
+		if s == "" {
+			return 0
+		}
+		return b.createString(s)
+	}
+
+	cols := make([]int, len(hdr.Columns))
+	for i, c := range hdr.Columns {
+		name, title, desc, meta := b.createString(c.Name), str(c.Title), str(c.Description), str(c.Metadata)
+		b.startTable(columnFields)
+		b.addOffset(columnName, name)
+		if title != 0 {
+			b.addOffset(columnTitle, title)
+		}
+		if desc != 0 {
+			b.addOffset(columnDescription, desc)
+		}
+		if meta != 0 {
+			b.addOffset(columnMetadata, meta)
+		}
+		b.addUint8(columnType, uint8(c.Type))
+		cols[i] = b.endTable()
+	}
+	var colsOff int
+	if len(cols) > 0 {
+		colsOff = b.createOffsets(cols)
+	}
+
+	var crsOff int
+	if c := hdr.CRS; c != nil {
+		org, name, desc, wkt, code := str(c.Org), str(c.Name), str(c.Description), str(c.WKT), str(c.CodeString)
+		b.startTable(crsFields)
+		for _, f := range [][2]int{{crsOrg, org}, {crsName, name}, {crsDescription, desc}, {crsWKT, wkt}, {crsCodeString, code}} {
+			if f[1] != 0 {
+				b.addOffset(f[0], f[1])
+			}
+		}
+		if c.Code != 0 {
+			b.addInt32(crsCode, c.Code)
+		}
+		crsOff = b.endTable()
+	}
+
+	var envOff int
+	if e := hdr.Extent; e != nil {
+		envOff = b.createFloat64s(e[:])
+	}
+	name, title, desc, meta := str(hdr.Name), str(hdr.Title), str(hdr.Description), str(hdr.Metadata)
+
+	b.startTable(headerFields)
+	b.addUint64(headerFeaturesCount, hdr.FeaturesCount)
+	for _, f := range [][2]int{
+		{headerName, name}, {headerEnvelope, envOff}, {headerColumns, colsOff}, {headerCRS, crsOff},
+		{headerTitle, title}, {headerDescription, desc}, {headerMetadata, meta},
+	} {
+		if f[1] != 0 {
+			b.addOffset(f[0], f[1])
+		}
+	}
+	b.addUint16(headerIndexNodeSize, hdr.IndexNodeSize)
+	b.addUint8(headerGeometryType, uint8(hdr.GeometryType))
+	return b.finish(b.endTable())
+}
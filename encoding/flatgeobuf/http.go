@@ -0,0 +1,52 @@
+package flatgeobuf
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// HTTPRangeReader reads a file on an HTTP server with range requests, so a
+// Searcher can read just the parts of it that it needs.
+type HTTPRangeReader struct {
+	// Client is the client to make the requests with, or nil for
+	// http.DefaultClient
+	Client *http.Client
+	URL    string
+}
+
+// ReadAt reads len(p) bytes of the file from the offset, with a request
+// for just those bytes.
+func (h HTTPRangeReader) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	req, err := http.NewRequest(http.MethodGet, h.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1))
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusRequestedRangeNotSatisfiable:
+		return 0, io.EOF
+	default:
+		return 0, fmt.Errorf("flatgeobuf: range request for %v: %v", h.URL, resp.Status)
+	}
+	n, err := io.ReadFull(resp.Body, p)
+	if err == io.ErrUnexpectedEOF {
+		// the range went past the end of the file
+		err = io.EOF
+	}
+	return n, err
+}
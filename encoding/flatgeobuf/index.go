@@ -0,0 +1,196 @@
+package flatgeobuf
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+)
+
+// The index is a packed Hilbert R-tree: the features are sorted by the
+// Hilbert value of the centers of their bounding boxes, and each level of
+// the tree has nodes with the bounding boxes of up to the node size nodes
+// of the level below. The nodes are stored from the root down, so the
+// leaves, one for each feature, are last.
+
+// DefaultIndexNodeSize is the number of children of each node of the index
+// written, unless the Writer is given another size.
+const DefaultIndexNodeSize = 16
+
+// nodeLen is the size of a node: its bounding box, then the offset of its
+// first child, or for a leaf, of its feature in the features
+const nodeLen = 40
+
+type node struct {
+	ext    [4]float64
+	offset uint64
+}
+
+// emptyExtent is the bounding box of features without points, which
+// intersects nothing
+var emptyExtent = [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+
+func (n node) intersects(ext [4]float64) bool {
+	return n.ext[0] <= ext[2] && n.ext[2] >= ext[0] && n.ext[1] <= ext[3] && n.ext[3] >= ext[1]
+}
+
+func readNode(b []byte) node {
+	var n node
+	for i := range n.ext {
+		n.ext[i] = math.Float64frombits(binary.LittleEndian.Uint64(b[8*i:]))
+	}
+	n.offset = binary.LittleEndian.Uint64(b[32:])
+	return n
+}
+
+func (n node) appendTo(b []byte) []byte {
+	var buf [nodeLen]byte
+	for i, v := range n.ext {
+		binary.LittleEndian.PutUint64(buf[8*i:], math.Float64bits(v))
+	}
+	binary.LittleEndian.PutUint64(buf[32:], n.offset)
+	return append(b, buf[:]...)
+}
+
+// levelBounds returns the first node and the node after the last of each
+// level of a tree of the items, from the leaves up
+func levelBounds(items, nodeSize int) [][2]int {
+	counts := []int{items}
+	total := items
+	// there is always a root above the leaves, even for one item
+	for n := items; ; {
+		n = (n + nodeSize - 1) / nodeSize
+		counts = append(counts, n)
+		total += n
+		if n == 1 {
+			break
+		}
+	}
+	bounds := make([][2]int, len(counts))
+	end := total
+	for i, n := range counts {
+		bounds[i] = [2]int{end - n, end}
+		end -= n
+	}
+	return bounds
+}
+
+// indexSize returns the size in bytes of the index of the items
+func indexSize(items, nodeSize int) int {
+	if items == 0 || nodeSize == 0 {
+		return 0
+	}
+	bounds := levelBounds(items, nodeSize)
+	return bounds[0][1] * nodeLen
+}
+
+// buildIndex returns the nodes of the tree with the leaves, which are in
+// the order of their features
+func buildIndex(leaves []node, nodeSize int) []node {
+	bounds := levelBounds(len(leaves), nodeSize)
+	nodes := make([]node, bounds[0][1])
+	copy(nodes[bounds[0][0]:], leaves)
+	for level := 0; level < len(bounds)-1; level++ {
+		parent := bounds[level+1][0]
+		for i := bounds[level][0]; i < bounds[level][1]; i += nodeSize {
+			p := node{ext: emptyExtent, offset: uint64(i)}
+			for j := i; j < i+nodeSize && j < bounds[level][1]; j++ {
+				e := nodes[j].ext
+				p.ext = [4]float64{
+					math.Min(p.ext[0], e[0]), math.Min(p.ext[1], e[1]),
+					math.Max(p.ext[2], e[2]), math.Max(p.ext[3], e[3]),
+				}
+			}
+			nodes[parent] = p
+			parent++
+		}
+	}
+	return nodes
+}
+
+// hilbertSort sorts the items, whose bounding boxes are the leaves, by the
+// Hilbert values of the centers of the bounding boxes in the extent
+func hilbertSort(leaves []node, items []int, ext *geom.Extent) {
+	values := make([]uint32, len(leaves))
+	if ext != nil {
+		const max = 1<<16 - 1
+		w, h := ext.XSpan(), ext.YSpan()
+		scale := func(v, min, span float64) uint32 {
+			if span == 0 {
+				return 0
+			}
+			return uint32(math.Floor(max * (v - min) / span))
+		}
+		for i, l := range leaves {
+			if l.ext == emptyExtent {
+				continue
+			}
+			x := scale((l.ext[0]+l.ext[2])/2, ext.MinX(), w)
+			y := scale((l.ext[1]+l.ext[3])/2, ext.MinY(), h)
+			values[i] = hilbert(x, y)
+		}
+	}
+	sort.Stable(hilbertSorter{values: values, leaves: leaves, items: items})
+}
+
+type hilbertSorter struct {
+	values []uint32
+	leaves []node
+	items  []int
+}
+
+func (s hilbertSorter) Len() int           { return len(s.values) }
+func (s hilbertSorter) Less(i, j int) bool { return s.values[i] < s.values[j] }
+func (s hilbertSorter) Swap(i, j int) {
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+	s.leaves[i], s.leaves[j] = s.leaves[j], s.leaves[i]
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+}
+
+// hilbert returns the position of the point along the Hilbert curve
+// through the 2^16 by 2^16 grid, computed without loops as described at
+// http://threadlocalmutex.com/?p=126
+func hilbert(x, y uint32) uint32 {
+	a := x ^ y
+	b := 0xFFFF ^ a
+	c := 0xFFFF ^ (x | y)
+	d := x & (y ^ 0xFFFF)
+
+	A := a | (b >> 1)
+	B := (a >> 1) ^ a
+	C := ((c >> 1) ^ (b & (d >> 1))) ^ c
+	D := ((a & (c >> 1)) ^ (d >> 1)) ^ d
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 2)) ^ (b & (b >> 2))
+	B = (a & (b >> 2)) ^ (b & ((a ^ b) >> 2))
+	C ^= (a & (c >> 2)) ^ (b & (d >> 2))
+	D ^= (b & (c >> 2)) ^ ((a ^ b) & (d >> 2))
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 4)) ^ (b & (b >> 4))
+	B = (a & (b >> 4)) ^ (b & ((a ^ b) >> 4))
+	C ^= (a & (c >> 4)) ^ (b & (d >> 4))
+	D ^= (b & (c >> 4)) ^ ((a ^ b) & (d >> 4))
+
+	a, b, c, d = A, B, C, D
+	C ^= (a & (c >> 8)) ^ (b & (d >> 8))
+	D ^= (b & (c >> 8)) ^ ((a ^ b) & (d >> 8))
+
+	a = C ^ (C >> 1)
+	b = D ^ (D >> 1)
+
+	i0 := x ^ y
+	i1 := b | (0xFFFF ^ (i0 | a))
+	return (interleave(i1) << 1) | interleave(i0)
+}
+
+// interleave spreads the lower 16 bits out to the even bits
+func interleave(v uint32) uint32 {
+	v = (v | (v << 8)) & 0x00FF00FF
+	v = (v | (v << 4)) & 0x0F0F0F0F
+	v = (v | (v << 2)) & 0x33333333
+	v = (v | (v << 1)) & 0x55555555
+	return v
+}
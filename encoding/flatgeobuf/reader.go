@@ -0,0 +1,230 @@
+package flatgeobuf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/go-spatial/geom"
+)
+
+// readSized reads a uint32 size and that many bytes after it, growing the
+// buffer as they are read, so a corrupt size does not allocate more than
+// there is to read
+func readSized(r io.Reader, buf *bytes.Buffer) ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+	n := int64(binary.LittleEndian.Uint32(size[:]))
+	buf.Reset()
+	if m, err := io.CopyN(buf, r, n); m != n {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readStart reads the magic bytes and the header
+func readStart(r io.Reader) (Header, int64, error) {
+	var m [8]byte
+	// any patch version can be read
+	if _, err := io.ReadFull(r, m[:]); err != nil || !bytes.Equal(m[:7], magic[:7]) {
+		return Header{}, 0, ErrInvalidFile
+	}
+	var buf bytes.Buffer
+	b, err := readSized(r, &buf)
+	if err != nil {
+		return Header{}, 0, ErrInvalidFile
+	}
+	hdr, err := readHeader(b)
+	if err != nil {
+		return hdr, 0, err
+	}
+	// the index must be able to be read, and the features found after it
+	if hdr.hasIndex() && (hdr.IndexNodeSize < 2 || hdr.FeaturesCount > maxFeatures) {
+		return Header{}, 0, ErrInvalidFile
+	}
+	return hdr, int64(len(magic) + 4 + len(b)), nil
+}
+
+// maxFeatures is the most features a file with an index can have, so the
+// size of the index can be calculated without overflowing
+const maxFeatures = 1 << 48
+
+// hasIndex reports whether a file with the header has an index
+func (hdr Header) hasIndex() bool {
+	return hdr.IndexNodeSize > 0 && hdr.FeaturesCount > 0
+}
+
+// Reader reads the features of a FlatGeobuf file in order.
+type Reader struct {
+	r    *bufio.Reader
+	hdr  Header
+	read uint64
+	buf  bytes.Buffer
+}
+
+// NewReader returns a Reader of the file, having read its header. The index
+// of the file, if it has one, is skipped.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	hdr, _, err := readStart(br)
+	if err != nil {
+		return nil, err
+	}
+	if hdr.hasIndex() {
+		n := int64(indexSize(int(hdr.FeaturesCount), int(hdr.IndexNodeSize)))
+		if m, _ := io.CopyN(ioutil.Discard, br, n); m != n {
+			return nil, ErrInvalidFile
+		}
+	}
+	return &Reader{r: br, hdr: hdr}, nil
+}
+
+// Header returns the header of the file.
+func (r *Reader) Header() Header { return r.hdr }
+
+// Next returns the next feature. io.EOF is returned after the last
+// feature.
+func (r *Reader) Next() (Feature, error) {
+	if r.hdr.FeaturesCount > 0 && r.read == r.hdr.FeaturesCount {
+		return Feature{}, io.EOF
+	}
+	b, err := readSized(r.r, &r.buf)
+	switch {
+	case err == io.EOF && r.hdr.FeaturesCount == 0:
+		return Feature{}, io.EOF
+	case err != nil:
+		return Feature{}, ErrInvalidFeature
+	}
+	r.read++
+	return readFeature(b, r.hdr.GeometryType, r.hdr.Columns)
+}
+
+// Searcher reads the features of a FlatGeobuf file whose bounding boxes
+// intersect an extent, using the index of the file to only read those
+// features and the nodes of the index above them.
+type Searcher struct {
+	r   io.ReaderAt
+	hdr Header
+	// index and features are the positions of the index and the features
+	index, features int64
+}
+
+// NewSearcher returns a Searcher of the file, having read its header.
+// ErrNoIndex is returned if the file has no index.
+func NewSearcher(r io.ReaderAt) (*Searcher, error) {
+	// the header is read with as few reads as possible, as each may be a
+	// request
+	hdr, n, err := readStart(bufio.NewReaderSize(io.NewSectionReader(r, 0, 1<<62), 1<<16))
+	if err != nil {
+		return nil, err
+	}
+	if !hdr.hasIndex() {
+		return nil, ErrNoIndex
+	}
+	size := indexSize(int(hdr.FeaturesCount), int(hdr.IndexNodeSize))
+	return &Searcher{r: r, hdr: hdr, index: n, features: n + int64(size)}, nil
+}
+
+// Header returns the header of the file.
+func (s *Searcher) Header() Header { return s.hdr }
+
+// Search calls fn with each feature whose bounding box intersects the
+// extent, in the order they are in the file, until fn returns false.
+//
+// The index is read a level at a time, reading the runs of nodes next to
+// each other that are needed at once, then the features are read, so over
+// HTTP there are a few requests for the index and two for each feature.
+func (s *Searcher) Search(ext geom.Extent, fn func(Feature) bool) error {
+	offsets, err := s.search(ext)
+	if err != nil {
+		return err
+	}
+	var b []byte
+	for _, off := range offsets {
+		var size [4]byte
+		if _, err := s.r.ReadAt(size[:], s.features+off); err != nil {
+			return ErrInvalidFeature
+		}
+		n := int(binary.LittleEndian.Uint32(size[:]))
+		if cap(b) < n {
+			b = make([]byte, n)
+		}
+		b = b[:n]
+		if _, err := s.r.ReadAt(b, s.features+off+4); err != nil {
+			return ErrInvalidFeature
+		}
+		f, err := readFeature(b, s.hdr.GeometryType, s.hdr.Columns)
+		if err != nil {
+			return err
+		}
+		if !fn(f) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// search returns the sorted offsets of the features whose leaves intersect
+// the extent
+func (s *Searcher) search(ext geom.Extent) ([]int64, error) {
+	nodeSize := int(s.hdr.IndexNodeSize)
+	bounds := levelBounds(int(s.hdr.FeaturesCount), nodeSize)
+	var offsets []int64
+
+	// the runs of nodes to read on the level
+	runs := [][2]int{{0, 1}}
+	var b []byte
+	for level := len(bounds) - 1; level >= 0 && len(runs) > 0; level-- {
+		var next [][2]int
+		for _, run := range runs {
+			n := (run[1] - run[0]) * nodeLen
+			if cap(b) < n {
+				b = make([]byte, n)
+			}
+			b = b[:n]
+			if _, err := s.r.ReadAt(b, s.index+int64(run[0]*nodeLen)); err != nil {
+				return nil, ErrInvalidFile
+			}
+			for i := 0; i < run[1]-run[0]; i++ {
+				nd := readNode(b[i*nodeLen:])
+				if !nd.intersects(ext) {
+					continue
+				}
+				if level == 0 {
+					if nd.offset > 1<<62 {
+						return nil, ErrInvalidFile
+					}
+					offsets = append(offsets, int64(nd.offset))
+					continue
+				}
+				child := nd.offset
+				below := bounds[level-1]
+				if child < uint64(below[0]) || child >= uint64(below[1]) {
+					return nil, ErrInvalidFile
+				}
+				end := int(child) + nodeSize
+				if end > below[1] {
+					end = below[1]
+				}
+				// children of nodes next to each other are next to each
+				// other
+				if k := len(next) - 1; k >= 0 && next[k][1] == int(child) {
+					next[k][1] = end
+					continue
+				}
+				next = append(next, [2]int{int(child), end})
+			}
+		}
+		runs = next
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	return offsets, nil
+}
@@ -0,0 +1,135 @@
+package flatgeobuf
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/go-spatial/geom"
+)
+
+// WriterOption is an option for a Writer.
+type WriterOption func(*writerOptions)
+
+type writerOptions struct {
+	indexNodeSize uint16
+}
+
+// WithIndexNodeSize sets the number of children of each node of the index.
+// Zero writes no index, and one is taken as two, the least there can be.
+func WithIndexNodeSize(n uint16) WriterOption {
+	return func(o *writerOptions) {
+		if n == 1 {
+			n = 2
+		}
+		o.indexNodeSize = n
+	}
+}
+
+// Writer writes the features of a FlatGeobuf file. The header has the
+// number of features and their extent, and the index is before the
+// features, so the features are kept until the Writer is closed, when the
+// file is written.
+type Writer struct {
+	w        io.Writer
+	hdr      Header
+	opts     writerOptions
+	features [][]byte
+	leaves   []node
+	ext      *geom.Extent
+	closed   bool
+}
+
+// NewWriter returns a Writer of features described by the header. The
+// Extent, FeaturesCount, IndexNodeSize and the Has fields of the header are
+// ignored. The features are indexed with DefaultIndexNodeSize children for
+// each node, unless another size is given with WithIndexNodeSize.
+func NewWriter(w io.Writer, hdr Header, opts ...WriterOption) (*Writer, error) {
+	if hdr.GeometryType > GeometryCollection {
+		return nil, ErrUnsupportedGeometryType{Typ: hdr.GeometryType}
+	}
+	hdr.Extent, hdr.FeaturesCount = nil, 0
+	hdr.HasZ, hdr.HasM, hdr.HasT, hdr.HasTM = false, false, false, false
+	wr := &Writer{w: w, hdr: hdr, opts: writerOptions{indexNodeSize: DefaultIndexNodeSize}}
+	for _, opt := range opts {
+		opt(&wr.opts)
+	}
+	return wr, nil
+}
+
+// Write adds the feature. The geometry must be nil or of the geometry type
+// of the header, unless it is Unknown, otherwise ErrGeometryTypeMismatch is
+// returned. Properties that are not of a column of the header are ignored.
+func (w *Writer) Write(f Feature) error {
+	if w.closed {
+		return ErrClosed
+	}
+	var s *shape
+	leaf := node{ext: emptyExtent}
+	if f.Geometry != nil {
+		sh, err := newShape(f.Geometry)
+		if err != nil {
+			return err
+		}
+		if w.hdr.GeometryType != Unknown && sh.typ != w.hdr.GeometryType {
+			return ErrGeometryTypeMismatch{Typ: w.hdr.GeometryType, Geom: f.Geometry}
+		}
+		s = &sh
+		if ext := sh.extent(); ext != nil {
+			leaf.ext = *ext
+			if w.ext == nil {
+				w.ext = ext.Clone()
+			} else {
+				w.ext.Add(ext)
+			}
+		}
+	}
+	props, err := buildProperties(f.Properties, w.hdr.Columns)
+	if err != nil {
+		return err
+	}
+	w.features = append(w.features, buildFeature(s, props))
+	w.leaves = append(w.leaves, leaf)
+	return nil
+}
+
+// Close writes the file. It does not close the io.Writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return ErrClosed
+	}
+	w.closed = true
+
+	order := make([]int, len(w.features))
+	for i := range order {
+		order[i] = i
+	}
+	hdr := w.hdr
+	hdr.FeaturesCount = uint64(len(w.features))
+	hdr.Extent = w.ext
+	hdr.IndexNodeSize = w.opts.indexNodeSize
+	var index []node
+	if hdr.hasIndex() {
+		hilbertSort(w.leaves, order, w.ext)
+		var off uint64
+		for k, i := range order {
+			w.leaves[k].offset = off
+			off += uint64(len(w.features[i]))
+		}
+		index = buildIndex(w.leaves, int(hdr.IndexNodeSize))
+	}
+
+	bw := bufio.NewWriter(w.w)
+	bw.Write(magic[:])
+	bw.Write(buildHeader(hdr))
+	var b []byte
+	for _, n := range index {
+		b = n.appendTo(b[:0])
+		bw.Write(b)
+	}
+	for _, i := range order {
+		bw.Write(w.features[i])
+	}
+	w.features, w.leaves = nil, nil
+	// bufio.Writer keeps the first error, and returns it from Flush
+	return bw.Flush()
+}
@@ -0,0 +1,373 @@
+package geobuf
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/geojson"
+)
+
+type decoder struct {
+	keys []string
+	e    float64
+}
+
+func decodeData(b []byte) (interface{}, error) {
+	dec := decoder{e: math.Pow10(DefaultPrecision)}
+	r := pbfReader{buf: b}
+	var (
+		typ  int
+		data []byte
+	)
+	for field, wire, ok := r.next(); ok; field, wire, ok = r.next() {
+		switch field {
+		case dataKeys:
+			dec.keys = append(dec.keys, string(r.bytes()))
+		case dataDimensions:
+			if dims := r.varint(); r.err == nil && dims != 2 {
+				return nil, ErrUnsupportedDimensions{Dimensions: dims}
+			}
+		case dataPrecision:
+			p := r.varint()
+			if p > MaxPrecision {
+				return nil, ErrInvalidData
+			}
+			dec.e = math.Pow10(int(p))
+		case dataFeatureCollection, dataFeature, dataGeometry:
+			typ, data = field, r.bytes()
+		default:
+			r.skip(wire)
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	switch typ {
+	case dataFeatureCollection:
+		return dec.featureCollection(data)
+	case dataFeature:
+		return dec.feature(data)
+	case dataGeometry:
+		return dec.geometry(data)
+	default:
+		return nil, ErrInvalidData
+	}
+}
+
+func (dec *decoder) key(i uint64) (string, error) {
+	if i >= uint64(len(dec.keys)) {
+		return "", ErrInvalidData
+	}
+	return dec.keys[i], nil
+}
+
+// properties decodes the pairs of key and value indexes of a message
+func (dec *decoder) properties(idx []uint64, values []interface{}, fn func(k string, v interface{})) error {
+	if len(idx)%2 != 0 {
+		return ErrInvalidData
+	}
+	for i := 0; i < len(idx); i += 2 {
+		k, err := dec.key(idx[i])
+		if err != nil {
+			return err
+		}
+		if idx[i+1] >= uint64(len(values)) {
+			return ErrInvalidData
+		}
+		fn(k, values[idx[i+1]])
+	}
+	return nil
+}
+
+// customProperties decodes the custom properties of a message as its bbox
+// and foreign members
+func (dec *decoder) customProperties(idx []uint64, values []interface{}, bbox *[]float64) (map[string]json.RawMessage, error) {
+	var foreign map[string]json.RawMessage
+	var err error
+	perr := dec.properties(idx, values, func(k string, v interface{}) {
+		raw, ok := v.(json.RawMessage)
+		if !ok {
+			if raw, err = json.Marshal(v); err != nil {
+				return
+			}
+		}
+		if k == bboxKey && json.Unmarshal(raw, bbox) == nil {
+			return
+		}
+		if foreign == nil {
+			foreign = make(map[string]json.RawMessage)
+		}
+		foreign[k] = raw
+	})
+	if perr != nil {
+		return nil, perr
+	}
+	return foreign, err
+}
+
+func (dec *decoder) featureCollection(b []byte) (geojson.FeatureCollection, error) {
+	var (
+		fc     geojson.FeatureCollection
+		values []interface{}
+		custom []uint64
+	)
+	r := pbfReader{buf: b}
+	for field, wire, ok := r.next(); ok; field, wire, ok = r.next() {
+		switch field {
+		case featureCollectionFeatures:
+			f, err := dec.feature(r.bytes())
+			if err != nil {
+				return fc, err
+			}
+			fc.Features = append(fc.Features, f)
+		case fieldValues:
+			v, err := decodeValue(r.bytes())
+			if err != nil {
+				return fc, err
+			}
+			values = append(values, v)
+		case fieldCustomProperties:
+			custom = r.varints(wire, custom)
+		default:
+			r.skip(wire)
+		}
+	}
+	if r.err != nil {
+		return fc, r.err
+	}
+	var err error
+	fc.ForeignMembers, err = dec.customProperties(custom, values, &fc.BBox)
+	return fc, err
+}
+
+func (dec *decoder) feature(b []byte) (geojson.Feature, error) {
+	var (
+		f             geojson.Feature
+		values        []interface{}
+		props, custom []uint64
+	)
+	r := pbfReader{buf: b}
+	for field, wire, ok := r.next(); ok; field, wire, ok = r.next() {
+		switch field {
+		case featureGeometry:
+			g, err := dec.geometry(r.bytes())
+			if err != nil {
+				return f, err
+			}
+			f.Geometry.Geometry = g
+		case featureID:
+			f.SetID(string(r.bytes()))
+		case featureIntID:
+			f.SetID(json.Number(strconv.FormatInt(unzigzag(r.varint()), 10)))
+		case fieldValues:
+			v, err := decodeValue(r.bytes())
+			if err != nil {
+				return f, err
+			}
+			values = append(values, v)
+		case fieldProperties:
+			props = r.varints(wire, props)
+		case fieldCustomProperties:
+			custom = r.varints(wire, custom)
+		default:
+			r.skip(wire)
+		}
+	}
+	if r.err != nil {
+		return f, r.err
+	}
+	f.Properties = make(map[string]interface{}, len(props)/2)
+	var jerr error
+	err := dec.properties(props, values, func(k string, v interface{}) {
+		if raw, ok := v.(json.RawMessage); ok {
+			v = nil
+			if err := json.Unmarshal(raw, &v); err != nil {
+				jerr = ErrInvalidData
+			}
+		}
+		f.Properties[k] = v
+	})
+	if err == nil {
+		err = jerr
+	}
+	if err != nil {
+		return f, err
+	}
+	f.ForeignMembers, err = dec.customProperties(custom, values, &f.BBox)
+	return f, err
+}
+
+// decodeValue decodes a Value message, keeping JSON values as
+// json.RawMessages
+func decodeValue(b []byte) (interface{}, error) {
+	var v interface{}
+	r := pbfReader{buf: b}
+	for field, wire, ok := r.next(); ok; field, wire, ok = r.next() {
+		switch field {
+		case valueString:
+			v = string(r.bytes())
+		case valueDouble:
+			v = r.double()
+		case valuePosInt:
+			v = float64(r.varint())
+		case valueNegInt:
+			v = -float64(r.varint())
+		case valueBool:
+			v = r.varint() != 0
+		case valueJSON:
+			v = json.RawMessage(r.bytes())
+		default:
+			r.skip(wire)
+		}
+	}
+	return v, r.err
+}
+
+// point returns the point of the integer coordinates
+func (dec *decoder) point(x, y int64) [2]float64 {
+	return [2]float64{float64(x) / dec.e, float64(y) / dec.e}
+}
+
+// line decodes the n delta encoded points of cs, returning the points and
+// the rest of cs. If closed the first point is added to the end.
+func (dec *decoder) line(cs []int64, n uint64, closed bool) ([][2]float64, []int64, error) {
+	if n > uint64(len(cs)/2) {
+		return nil, nil, ErrInvalidData
+	}
+	line := make([][2]float64, 0, n+1)
+	var x, y int64
+	for i := uint64(0); i < n; i++ {
+		x += cs[2*i]
+		y += cs[2*i+1]
+		line = append(line, dec.point(x, y))
+	}
+	if closed && n > 0 {
+		line = append(line, line[0])
+	}
+	return line, cs[2*n:], nil
+}
+
+// lines decodes the lines of the lengths, or the one line of all the
+// coordinates if there are no lengths but there are coordinates
+func (dec *decoder) lines(cs []int64, lengths []uint64, closed bool) ([][][2]float64, error) {
+	if lengths == nil && len(cs) > 0 {
+		lengths = []uint64{uint64(len(cs) / 2)}
+	}
+	lines := make([][][2]float64, len(lengths))
+	var err error
+	for i, n := range lengths {
+		if lines[i], cs, err = dec.line(cs, n, closed); err != nil {
+			return nil, err
+		}
+	}
+	return lines, nil
+}
+
+func (dec *decoder) geometry(b []byte) (geom.Geometry, error) {
+	var (
+		typ     uint64
+		lengths []uint64
+		cs      []int64
+		geos    []geom.Geometry
+	)
+	r := pbfReader{buf: b}
+	for field, wire, ok := r.next(); ok; field, wire, ok = r.next() {
+		switch field {
+		case geometryType:
+			typ = r.varint()
+		case geometryLengths:
+			lengths = r.varints(wire, lengths)
+		case geometryCoords:
+			var vs []uint64
+			for _, v := range r.varints(wire, vs) {
+				cs = append(cs, unzigzag(v))
+			}
+		case geometryGeometries:
+			g, err := dec.geometry(r.bytes())
+			if err != nil {
+				return nil, err
+			}
+			geos = append(geos, g)
+		default:
+			r.skip(wire)
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(cs)%2 != 0 {
+		return nil, ErrInvalidData
+	}
+
+	switch typ {
+	case typePoint:
+		if len(cs) != 2 {
+			return nil, ErrInvalidData
+		}
+		return geom.Point(dec.point(cs[0], cs[1])), nil
+	case typeMultiPoint, typeLineString:
+		line, _, err := dec.line(cs, uint64(len(cs)/2), false)
+		if err != nil {
+			return nil, err
+		}
+		if typ == typeMultiPoint {
+			return geom.MultiPoint(line), nil
+		}
+		return geom.LineString(line), nil
+	case typeMultiLineString:
+		lines, err := dec.lines(cs, lengths, false)
+		if err != nil {
+			return nil, err
+		}
+		return geom.MultiLineString(lines), nil
+	case typePolygon:
+		lines, err := dec.lines(cs, lengths, true)
+		if err != nil {
+			return nil, err
+		}
+		return geom.Polygon(lines), nil
+	case typeMultiPolygon:
+		if lengths == nil {
+			ring, _, err := dec.line(cs, uint64(len(cs)/2), true)
+			if err != nil {
+				return nil, err
+			}
+			return geom.MultiPolygon{{ring}}, nil
+		}
+		if lengths[0] > uint64(len(lengths)) {
+			return nil, ErrInvalidData
+		}
+		mp := make(geom.MultiPolygon, 0, lengths[0])
+		i := 1
+		for p := uint64(0); p < lengths[0]; p++ {
+			if i >= len(lengths) {
+				return nil, ErrInvalidData
+			}
+			nrings := lengths[i]
+			i++
+			if nrings > uint64(len(lengths)-i) {
+				return nil, ErrInvalidData
+			}
+			ply := make(geom.Polygon, nrings)
+			for j := range ply {
+				var err error
+				if ply[j], cs, err = dec.line(cs, lengths[i], true); err != nil {
+					return nil, err
+				}
+				i++
+			}
+			mp = append(mp, ply)
+		}
+		return mp, nil
+	case typeGeometryCollection:
+		if geos == nil {
+			geos = geom.Collection{}
+		}
+		return geom.Collection(geos), nil
+	default:
+		return nil, ErrInvalidData
+	}
+}
@@ -0,0 +1,560 @@
+package geobuf
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/geojson"
+)
+
+// The fields of the messages of geobuf.proto
+const (
+	dataKeys              = 1
+	dataDimensions        = 2
+	dataPrecision         = 3
+	dataFeatureCollection = 4
+	dataFeature           = 5
+	dataGeometry          = 6
+
+	featureCollectionFeatures = 1
+
+	featureGeometry = 1
+	featureID       = 11
+	featureIntID    = 12
+
+	// the values and custom properties fields are the same for features,
+	// feature collections and geometries
+	fieldValues           = 13
+	fieldProperties       = 14
+	fieldCustomProperties = 15
+
+	geometryType       = 1
+	geometryLengths    = 2
+	geometryCoords     = 3
+	geometryGeometries = 4
+
+	valueString = 1
+	valueDouble = 2
+	valuePosInt = 3
+	valueNegInt = 4
+	valueBool   = 5
+	valueJSON   = 6
+)
+
+// The types of geometries of geobuf.proto
+const (
+	typePoint = iota
+	typeMultiPoint
+	typeLineString
+	typeMultiLineString
+	typePolygon
+	typeMultiPolygon
+	typeGeometryCollection
+)
+
+// bboxKey is the key of the custom property of a bbox
+const bboxKey = "bbox"
+
+type encoder struct {
+	keys     []string
+	keyIndex map[string]uint64
+	// precision is the decimal digits of the coordinates, and e is ten to
+	// its power
+	precision int
+	e         float64
+}
+
+func encodeData(v interface{}, o encodeOptions) ([]byte, error) {
+	enc := encoder{keyIndex: make(map[string]uint64)}
+	switch vv := v.(type) {
+	case *geojson.FeatureCollection:
+		v = *vv
+	case *geojson.Feature:
+		v = *vv
+	case geojson.Geometry:
+		v = vv.Geometry
+	case *geojson.Geometry:
+		v = vv.Geometry
+	}
+	if err := enc.analyze(v, o.precision); err != nil {
+		return nil, err
+	}
+
+	var w pbfWriter
+	for _, k := range enc.keys {
+		w.stringField(dataKeys, k)
+	}
+	if enc.precision != DefaultPrecision {
+		w.varintField(dataPrecision, uint64(enc.precision))
+	}
+	switch vv := v.(type) {
+	case geojson.FeatureCollection:
+		b, err := enc.featureCollection(vv)
+		if err != nil {
+			return nil, err
+		}
+		w.bytesField(dataFeatureCollection, b)
+	case geojson.Feature:
+		b, err := enc.feature(vv)
+		if err != nil {
+			return nil, err
+		}
+		w.bytesField(dataFeature, b)
+	default:
+		b, err := enc.geometry(vv)
+		if err != nil {
+			return nil, err
+		}
+		w.bytesField(dataGeometry, b)
+	}
+	return w.buf, nil
+}
+
+// analyze finds the keys of the properties and the precision of the
+// coordinates, up to the most decimal digits
+func (enc *encoder) analyze(v interface{}, most int) error {
+	var err error
+	eachCoord := func(g geom.Geometry) {
+		if err != nil || g == nil {
+			return
+		}
+		err = coords(g, func(xy [2]float64) {
+			for _, c := range xy {
+				if math.IsNaN(c) || math.IsInf(c, 0) {
+					err = ErrInvalidCoordinates
+					return
+				}
+				for enc.precision < most {
+					e := math.Pow10(enc.precision)
+					if math.Round(c*e)/e == c {
+						break
+					}
+					enc.precision++
+				}
+			}
+		})
+	}
+	switch vv := v.(type) {
+	case geojson.FeatureCollection:
+		enc.customKeys(vv.BBox, vv.ForeignMembers)
+		for _, f := range vv.Features {
+			enc.featureKeys(f)
+			eachCoord(f.Geometry.Geometry)
+		}
+	case geojson.Feature:
+		enc.featureKeys(vv)
+		eachCoord(vv.Geometry.Geometry)
+	default:
+		if vv == nil {
+			return geom.ErrUnknownGeometry{Geom: vv}
+		}
+		eachCoord(vv)
+	}
+	if err != nil {
+		return err
+	}
+	enc.e = math.Pow10(enc.precision)
+	return nil
+}
+
+func (enc *encoder) addKey(k string) {
+	if _, ok := enc.keyIndex[k]; !ok {
+		enc.keyIndex[k] = uint64(len(enc.keys))
+		enc.keys = append(enc.keys, k)
+	}
+}
+
+func (enc *encoder) featureKeys(f geojson.Feature) {
+	for _, k := range sortedKeys(f.Properties) {
+		enc.addKey(k)
+	}
+	enc.customKeys(f.BBox, f.ForeignMembers)
+}
+
+func (enc *encoder) customKeys(bbox []float64, foreign map[string]json.RawMessage) {
+	if len(bbox) > 0 {
+		enc.addKey(bboxKey)
+	}
+	for _, k := range sortedRawKeys(foreign) {
+		enc.addKey(k)
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedRawKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// customProperties writes the bbox and foreign members as the values and
+// custom properties of a message, which has n values before them
+func (enc *encoder) customProperties(w *pbfWriter, n int, bbox []float64, foreign map[string]json.RawMessage) error {
+	var props []uint64
+	if len(bbox) > 0 {
+		b, err := json.Marshal(bbox)
+		if err != nil {
+			return err
+		}
+		w.bytesField(fieldValues, jsonValue(b))
+		props = append(props, enc.keyIndex[bboxKey], uint64(n))
+		n++
+	}
+	for _, k := range sortedRawKeys(foreign) {
+		w.bytesField(fieldValues, jsonValue(foreign[k]))
+		props = append(props, enc.keyIndex[k], uint64(n))
+		n++
+	}
+	w.packedVarintsField(fieldCustomProperties, props)
+	return nil
+}
+
+func (enc *encoder) featureCollection(fc geojson.FeatureCollection) ([]byte, error) {
+	var w pbfWriter
+	for _, f := range fc.Features {
+		b, err := enc.feature(f)
+		if err != nil {
+			return nil, err
+		}
+		w.bytesField(featureCollectionFeatures, b)
+	}
+	if err := enc.customProperties(&w, 0, fc.BBox, fc.ForeignMembers); err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
+
+func (enc *encoder) feature(f geojson.Feature) ([]byte, error) {
+	var w pbfWriter
+	if f.Geometry.Geometry != nil {
+		b, err := enc.geometry(f.Geometry.Geometry)
+		if err != nil {
+			return nil, err
+		}
+		w.bytesField(featureGeometry, b)
+	}
+	if err := writeID(&w, f.IDValue()); err != nil {
+		return nil, err
+	}
+
+	var props []uint64
+	for i, k := range sortedKeys(f.Properties) {
+		b, err := value(f.Properties[k])
+		if err != nil {
+			return nil, err
+		}
+		w.bytesField(fieldValues, b)
+		props = append(props, enc.keyIndex[k], uint64(i))
+	}
+	w.packedVarintsField(fieldProperties, props)
+
+	if err := enc.customProperties(&w, len(props)/2, f.BBox, f.ForeignMembers); err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
+
+func writeID(w *pbfWriter, id interface{}) error {
+	var i int64
+	switch v := id.(type) {
+	case nil:
+		return nil
+	case string:
+		w.stringField(featureID, v)
+		return nil
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			w.stringField(featureID, v.String())
+			return nil
+		}
+		i = n
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) >= 1<<63 {
+			return ErrInvalidID
+		}
+		i = int64(v)
+	case int:
+		i = int64(v)
+	case int32:
+		i = int64(v)
+	case int64:
+		i = v
+	case uint:
+		i = int64(v)
+	case uint32:
+		i = int64(v)
+	case uint64:
+		if v > math.MaxInt64 {
+			// too large for the signed integer id
+			w.stringField(featureID, strconv.FormatUint(v, 10))
+			return nil
+		}
+		i = int64(v)
+	default:
+		return ErrInvalidID
+	}
+	w.varintField(featureIntID, zigzag(i))
+	return nil
+}
+
+func jsonValue(b []byte) []byte {
+	var w pbfWriter
+	w.stringField(valueJSON, string(b))
+	return w.buf
+}
+
+// value returns the Value message of a property
+func value(v interface{}) ([]byte, error) {
+	var w pbfWriter
+	switch vv := v.(type) {
+	case string:
+		w.stringField(valueString, vv)
+	case bool:
+		b := uint64(0)
+		if vv {
+			b = 1
+		}
+		w.varintField(valueBool, b)
+	case float64:
+		number(&w, vv)
+	case float32:
+		number(&w, float64(vv))
+	case int:
+		integer(&w, int64(vv))
+	case int8:
+		integer(&w, int64(vv))
+	case int16:
+		integer(&w, int64(vv))
+	case int32:
+		integer(&w, int64(vv))
+	case int64:
+		integer(&w, vv)
+	case uint:
+		w.varintField(valuePosInt, uint64(vv))
+	case uint8:
+		w.varintField(valuePosInt, uint64(vv))
+	case uint16:
+		w.varintField(valuePosInt, uint64(vv))
+	case uint32:
+		w.varintField(valuePosInt, uint64(vv))
+	case uint64:
+		w.varintField(valuePosInt, vv)
+	case json.Number:
+		if i, err := vv.Int64(); err == nil {
+			integer(&w, i)
+		} else if f, err := vv.Float64(); err == nil {
+			number(&w, f)
+		} else {
+			w.stringField(valueJSON, vv.String())
+		}
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		w.stringField(valueJSON, string(b))
+	}
+	return w.buf, nil
+}
+
+// number writes an integer value if the number is one, otherwise a double
+func number(w *pbfWriter, f float64) {
+	if f == math.Trunc(f) && math.Abs(f) < 1<<63 {
+		integer(w, int64(f))
+		return
+	}
+	w.doubleField(valueDouble, f)
+}
+
+func integer(w *pbfWriter, i int64) {
+	if i < 0 {
+		w.varintField(valueNegInt, uint64(-i))
+		return
+	}
+	w.varintField(valuePosInt, uint64(i))
+}
+
+// coords calls fn with each coordinate of the geometry
+func coords(g geom.Geometry, fn func([2]float64)) error {
+	switch gg := g.(type) {
+	case geom.Pointer:
+		fn(gg.XY())
+	case geom.MultiPointer:
+		for _, p := range gg.Points() {
+			fn(p)
+		}
+	case geom.LineStringer:
+		for _, p := range gg.Vertices() {
+			fn(p)
+		}
+	case geom.MultiLineStringer:
+		for _, l := range gg.LineStrings() {
+			for _, p := range l {
+				fn(p)
+			}
+		}
+	case geom.Polygoner:
+		for _, l := range gg.LinearRings() {
+			for _, p := range l {
+				fn(p)
+			}
+		}
+	case geom.MultiPolygoner:
+		for _, ply := range gg.Polygons() {
+			for _, l := range ply {
+				for _, p := range l {
+					fn(p)
+				}
+			}
+		}
+	case geom.Collectioner:
+		for _, cg := range gg.Geometries() {
+			if err := coords(cg, fn); err != nil {
+				return err
+			}
+		}
+	default:
+		return geom.ErrUnknownGeometry{Geom: g}
+	}
+	return nil
+}
+
+// round returns the coordinate as an integer of the precision
+func (enc *encoder) round(c float64) (int64, error) {
+	r := math.Round(c * enc.e)
+	if math.Abs(r) >= 1<<63 {
+		return 0, ErrInvalidCoordinates
+	}
+	return int64(r), nil
+}
+
+// line appends the delta encoded coordinates of the line. If closed the
+// last point, which closes the ring, is left out, if it is there.
+func (enc *encoder) line(cs []int64, line [][2]float64, closed bool) ([]int64, int, error) {
+	if closed && len(line) > 1 && line[0] == line[len(line)-1] {
+		line = line[:len(line)-1]
+	}
+	var sum [2]int64
+	for _, p := range line {
+		for j := range p {
+			n, err := enc.round(p[j])
+			if err != nil {
+				return nil, 0, err
+			}
+			cs = append(cs, n-sum[j])
+			sum[j] = n
+		}
+	}
+	return cs, len(line), nil
+}
+
+// lines appends the coordinates of the lines, and their lengths if there
+// is not just one
+func (enc *encoder) lines(w *pbfWriter, lines [][][2]float64, closed bool) error {
+	var cs []int64
+	lengths := make([]uint64, len(lines))
+	for i, l := range lines {
+		var n int
+		var err error
+		if cs, n, err = enc.line(cs, l, closed); err != nil {
+			return err
+		}
+		lengths[i] = uint64(n)
+	}
+	if len(lines) != 1 {
+		w.packedVarintsField(geometryLengths, lengths)
+	}
+	w.packedSVarintsField(geometryCoords, cs)
+	return nil
+}
+
+func (enc *encoder) geometry(g geom.Geometry) ([]byte, error) {
+	var w pbfWriter
+	var err error
+	switch gg := g.(type) {
+	case geom.Pointer:
+		w.varintField(geometryType, typePoint)
+		var cs []int64
+		xy := gg.XY()
+		for _, c := range xy {
+			n, err := enc.round(c)
+			if err != nil {
+				return nil, err
+			}
+			cs = append(cs, n)
+		}
+		w.packedSVarintsField(geometryCoords, cs)
+	case geom.MultiPointer:
+		w.varintField(geometryType, typeMultiPoint)
+		var cs []int64
+		if cs, _, err = enc.line(nil, gg.Points(), false); err == nil {
+			w.packedSVarintsField(geometryCoords, cs)
+		}
+	case geom.LineStringer:
+		w.varintField(geometryType, typeLineString)
+		var cs []int64
+		if cs, _, err = enc.line(nil, gg.Vertices(), false); err == nil {
+			w.packedSVarintsField(geometryCoords, cs)
+		}
+	case geom.MultiLineStringer:
+		w.varintField(geometryType, typeMultiLineString)
+		err = enc.lines(&w, gg.LineStrings(), false)
+	case geom.Polygoner:
+		w.varintField(geometryType, typePolygon)
+		err = enc.lines(&w, gg.LinearRings(), true)
+	case geom.MultiPolygoner:
+		w.varintField(geometryType, typeMultiPolygon)
+		plys := gg.Polygons()
+		if len(plys) == 1 && len(plys[0]) == 1 {
+			var cs []int64
+			if cs, _, err = enc.line(nil, plys[0][0], true); err == nil {
+				w.packedSVarintsField(geometryCoords, cs)
+			}
+			break
+		}
+		var cs []int64
+		lengths := []uint64{uint64(len(plys))}
+		for _, ply := range plys {
+			lengths = append(lengths, uint64(len(ply)))
+			for _, l := range ply {
+				var n int
+				if cs, n, err = enc.line(cs, l, true); err != nil {
+					return nil, err
+				}
+				lengths = append(lengths, uint64(n))
+			}
+		}
+		w.packedVarintsField(geometryLengths, lengths)
+		w.packedSVarintsField(geometryCoords, cs)
+	case geom.Collectioner:
+		w.varintField(geometryType, typeGeometryCollection)
+		for _, cg := range gg.Geometries() {
+			b, err := enc.geometry(cg)
+			if err != nil {
+				return nil, err
+			}
+			w.bytesField(geometryGeometries, b)
+		}
+	default:
+		return nil, geom.ErrUnknownGeometry{Geom: g}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
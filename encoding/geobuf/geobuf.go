@@ -0,0 +1,109 @@
+// Package geobuf is for encoding and decoding GeoJSON feature collections,
+// features and geometries as geobuf, the compact protocol buffer encoding
+// of GeoJSON by Mapbox. Specification at https://github.com/mapbox/geobuf
+//
+// Coordinates are kept to a number of decimal digits, the fewest that keep
+// all of them exact, up to a most that is six unless it is set with
+// WithPrecision. Only geometries of two dimensions are supported.
+//
+// Properties are decoded as they are from GeoJSON: numbers as float64s,
+// and objects and arrays as map[string]interface{} and []interface{}. Ids
+// are set with Feature.SetID, so integer ids that fit in a uint64 are in
+// ID, and negative ids, as json.Numbers, and string ids are in OtherID.
+// The bbox and foreign members of features and feature collections are
+// kept as custom properties. The rings of polygons are closed when
+// decoded.
+package geobuf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// DefaultPrecision is the most decimal digits coordinates are kept to
+	// unless it is set with WithPrecision.
+	DefaultPrecision = 6
+	// MaxPrecision is the most decimal digits coordinates can be kept to.
+	MaxPrecision = 15
+)
+
+var (
+	// ErrInvalidData is returned when decoding data that is not geobuf.
+	ErrInvalidData = errors.New("geobuf: invalid data")
+	// ErrInvalidCoordinates is returned when encoding coordinates that are
+	// not finite, or too large for the precision.
+	ErrInvalidCoordinates = errors.New("geobuf: invalid coordinates")
+	// ErrInvalidID is returned when encoding an id that is not a string or
+	// an integer.
+	ErrInvalidID = errors.New("geobuf: id is not a string or an integer")
+)
+
+// ErrUnsupportedDimensions is returned when decoding coordinates that are
+// not of two dimensions.
+type ErrUnsupportedDimensions struct {
+	Dimensions uint64
+}
+
+func (e ErrUnsupportedDimensions) Error() string {
+	return fmt.Sprintf("geobuf: unsupported dimensions %v", e.Dimensions)
+}
+
+// EncodeOption is an option for encoding.
+type EncodeOption func(*encodeOptions)
+
+type encodeOptions struct {
+	precision int
+}
+
+// WithPrecision sets the most decimal digits coordinates are kept to, at
+// most MaxPrecision.
+func WithPrecision(digits uint) EncodeOption {
+	return func(o *encodeOptions) {
+		if digits > MaxPrecision {
+			digits = MaxPrecision
+		}
+		o.precision = int(digits)
+	}
+}
+
+// Encode writes the geobuf of v, which is a geojson.FeatureCollection, a
+// geojson.Feature, or a geometry. Geometries with z or m values are not
+// supported, ErrUnknownGeometry is returned for them.
+func Encode(w io.Writer, v interface{}, opts ...EncodeOption) error {
+	b, err := EncodeBytes(v, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// EncodeBytes returns the geobuf of v, which is a geojson.FeatureCollection,
+// a geojson.Feature, or a geometry. Geometries with z or m values are not
+// supported, ErrUnknownGeometry is returned for them.
+func EncodeBytes(v interface{}, opts ...EncodeOption) ([]byte, error) {
+	o := encodeOptions{precision: DefaultPrecision}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return encodeData(v, o)
+}
+
+// Decode reads all of the geobuf, returning a geojson.FeatureCollection, a
+// geojson.Feature, or a geometry.
+func Decode(r io.Reader) (interface{}, error) {
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	return DecodeBytes(buf.Bytes())
+}
+
+// DecodeBytes decodes the geobuf, returning a geojson.FeatureCollection, a
+// geojson.Feature, or a geometry.
+func DecodeBytes(b []byte) (interface{}, error) {
+	return decodeData(b)
+}
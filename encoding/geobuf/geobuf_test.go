@@ -0,0 +1,314 @@
+package geobuf_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/geobuf"
+	"github.com/go-spatial/geom/encoding/geojson"
+)
+
+func uint64Ptr(u uint64) *uint64 { return &u }
+
+func TestEncode(t *testing.T) {
+	type tcase struct {
+		v    interface{}
+		opts []geobuf.EncodeOption
+		// hex encoding of the expected bytes
+		exp string
+		err error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var buff bytes.Buffer
+			err := geobuf.Encode(&buff, tc.v, tc.opts...)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if got := hex.EncodeToString(buff.Bytes()); got != tc.exp {
+				t.Errorf("encode, expected %v got %v", tc.exp, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			v: geom.Point{1, 2},
+			// precision 0, geometry of type point with coordinates 1 2
+			exp: "1800" + "3206" + "0800" + "1a020204",
+		},
+		"point default precision": {
+			v:   geom.Point{0.000001, 2},
+			exp: "3209" + "0800" + "1a05" + "02" + "8092f401",
+		},
+		"point precision": {
+			v:    geom.Point{0.125, 2},
+			opts: []geobuf.EncodeOption{geobuf.WithPrecision(1)},
+			exp:  "1801" + "3206" + "0800" + "1a020228",
+		},
+		"linestring deltas": {
+			v:   geom.LineString{{1, 1}, {2, 3}},
+			exp: "1800" + "3208" + "0802" + "1a0402020204",
+		},
+		"polygon leaves out closing point": {
+			v:   geom.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+			exp: "1800" + "320a" + "0804" + "1a06000002000002",
+		},
+		"feature": {
+			v: geojson.Feature{
+				ID:         uint64Ptr(3),
+				Geometry:   geojson.Geometry{Geometry: geom.Point{1, 2}},
+				Properties: map[string]interface{}{"a": "b"},
+			},
+			// key a, precision 0, feature with geometry, int id 3, value
+			// "b" and property a = "b"
+			exp: "0a0161" + "1800" + "2a13" + "0a060800" + "1a020204" + "6006" + "6a03" + "0a0162" + "72020000",
+		},
+		"nil": {
+			v:   nil,
+			err: geom.ErrUnknownGeometry{},
+		},
+		"point z": {
+			v:   geom.PointZ{1, 2, 3},
+			err: geom.ErrUnknownGeometry{Geom: geom.PointZ{1, 2, 3}},
+		},
+		"large id": {
+			v: geojson.Feature{ID: uint64Ptr(1 << 63)},
+			// precision 0, feature with string id "9223372036854775808"
+			exp: "1800" + "2a15" + "5a13" + "39323233333732303336383534373735383038",
+		},
+		"invalid id": {
+			v:   geojson.Feature{OtherID: 1.5},
+			err: geobuf.ErrInvalidID,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDecode(t *testing.T) {
+	type tcase struct {
+		// hex encoding of the bytes
+		geobuf string
+		exp    interface{}
+		err    error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			b, err := hex.DecodeString(tc.geobuf)
+			if err != nil {
+				t.Fatalf("hex, expected nil got %v", err)
+			}
+			got, err := geobuf.Decode(bytes.NewReader(b))
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("decode, expected %#v got %#v", tc.exp, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			geobuf: "1800" + "3206" + "0800" + "1a020204",
+			exp:    geom.Point{1, 2},
+		},
+		"point default precision": {
+			geobuf: "3206" + "0800" + "1a020204",
+			exp:    geom.Point{0.000001, 0.000002},
+		},
+		"unpacked coordinates": {
+			geobuf: "1800" + "3206" + "0800" + "1802" + "1804",
+			exp:    geom.Point{1, 2},
+		},
+		"unknown fields": {
+			geobuf: "1800" + "4001" + "3208" + "0800" + "1a020204" + "3800",
+			exp:    geom.Point{1, 2},
+		},
+		"polygon closes rings": {
+			geobuf: "1800" + "320a" + "0804" + "1a06000002000002",
+			exp:    geom.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		},
+		"three dimensions": {
+			geobuf: "1003" + "3206" + "0800" + "1a03020406",
+			err:    geobuf.ErrUnsupportedDimensions{Dimensions: 3},
+		},
+		"no data": {
+			geobuf: "1800",
+			err:    geobuf.ErrInvalidData,
+		},
+		"truncated": {
+			geobuf: "1800" + "3206" + "0800",
+			err:    geobuf.ErrInvalidData,
+		},
+		"too few coordinates": {
+			geobuf: "1800" + "3208" + "0803" + "120103" + "1a020204",
+			err:    geobuf.ErrInvalidData,
+		},
+		"unknown geometry type": {
+			geobuf: "1800" + "3202" + "0809",
+			err:    geobuf.ErrInvalidData,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	type tcase struct {
+		v    interface{}
+		opts []geobuf.EncodeOption
+		exp  interface{}
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			b, err := geobuf.EncodeBytes(tc.v, tc.opts...)
+			if err != nil {
+				t.Fatalf("encode error, expected nil got %v", err)
+			}
+			got, err := geobuf.DecodeBytes(b)
+			if err != nil {
+				t.Fatalf("decode error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("round trip, expected %#v got %#v", tc.exp, got)
+			}
+		}
+	}
+
+	mp := geom.MultiPolygon{
+		{{{0, 0}, {4, 0}, {4, 4}, {0, 0}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+		{{{10, 10}, {11, 10}, {11, 11}, {10, 10}}},
+	}
+	tests := map[string]tcase{
+		"multipoint": {
+			v:   geom.MultiPoint{{1.5, 2.25}, {-3, 4}},
+			exp: geom.MultiPoint{{1.5, 2.25}, {-3, 4}},
+		},
+		"multilinestring": {
+			v:   geom.MultiLineString{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}, {9, 10}}},
+			exp: geom.MultiLineString{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}, {9, 10}}},
+		},
+		"multilinestring of one": {
+			v:   geom.MultiLineString{{{1, 2}, {3, 4}}},
+			exp: geom.MultiLineString{{{1, 2}, {3, 4}}},
+		},
+		"polygon closes rings": {
+			v:   geom.Polygon{{{0, 0}, {4, 0}, {4, 4}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+			exp: geom.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 0}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+		},
+		"multipolygon": {
+			v:   mp,
+			exp: mp,
+		},
+		"multipolygon of one ring": {
+			v:   geom.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}},
+			exp: geom.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}},
+		},
+		"collection": {
+			v:   geom.Collection{geom.Point{1, 2}, geom.Collection{geom.LineString{{1, 2}, {3, 4}}}},
+			exp: geom.Collection{geom.Point{1, 2}, geom.Collection{geom.LineString{{1, 2}, {3, 4}}}},
+		},
+		"precision": {
+			v:    geom.LineString{{0.123456789, -0.987654321}, {1, 1}},
+			opts: []geobuf.EncodeOption{geobuf.WithPrecision(3)},
+			exp:  geom.LineString{{0.123, -0.988}, {1, 1}},
+		},
+		"feature collection": {
+			v: &geojson.FeatureCollection{
+				BBox: []float64{0, 0, 4, 4},
+				Features: []geojson.Feature{
+					{
+						OtherID:  "a",
+						Geometry: geojson.Geometry{Geometry: mp},
+						Properties: map[string]interface{}{
+							"name":   "x",
+							"count":  float64(3),
+							"neg":    float64(-7),
+							"ratio":  0.5,
+							"ok":     true,
+							"tags":   []interface{}{"a", float64(1)},
+							"nested": map[string]interface{}{"b": nil},
+							"none":   nil,
+						},
+						ForeignMembers: map[string]json.RawMessage{"title": json.RawMessage(`"t"`)},
+					},
+					{
+						OtherID:    json.Number("-12"),
+						Properties: map[string]interface{}{"count": 10},
+						BBox:       []float64{1, 2, 3, 4},
+					},
+				},
+				ForeignMembers: map[string]json.RawMessage{"crs": json.RawMessage(`{"type":"name"}`)},
+			},
+			exp: geojson.FeatureCollection{
+				BBox: []float64{0, 0, 4, 4},
+				Features: []geojson.Feature{
+					{
+						OtherID:  "a",
+						Geometry: geojson.Geometry{Geometry: mp},
+						Properties: map[string]interface{}{
+							"name":   "x",
+							"count":  float64(3),
+							"neg":    float64(-7),
+							"ratio":  0.5,
+							"ok":     true,
+							"tags":   []interface{}{"a", float64(1)},
+							"nested": map[string]interface{}{"b": nil},
+							"none":   nil,
+						},
+						ForeignMembers: map[string]json.RawMessage{"title": json.RawMessage(`"t"`)},
+					},
+					{
+						OtherID:    json.Number("-12"),
+						Properties: map[string]interface{}{"count": float64(10)},
+						BBox:       []float64{1, 2, 3, 4},
+					},
+				},
+				ForeignMembers: map[string]json.RawMessage{"crs": json.RawMessage(`{"type":"name"}`)},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestSmallerThanGeoJSON(t *testing.T) {
+	var fc geojson.FeatureCollection
+	for i := 0; i < 100; i++ {
+		var ls geom.LineString
+		for j := 0; j < 50; j++ {
+			ls = append(ls, [2]float64{-122.123456 + float64(i*j)*0.000125, 37.654321 + float64(j)*0.00025})
+		}
+		fc.Features = append(fc.Features, geojson.Feature{
+			ID:         uint64Ptr(1),
+			Geometry:   geojson.Geometry{Geometry: ls},
+			Properties: map[string]interface{}{"highway": "residential", "lanes": float64(2)},
+		})
+	}
+	gj, err := json.Marshal(fc)
+	if err != nil {
+		t.Fatalf("json error, expected nil got %v", err)
+	}
+	gb, err := geobuf.EncodeBytes(fc)
+	if err != nil {
+		t.Fatalf("encode error, expected nil got %v", err)
+	}
+	if len(gb)*3 > len(gj) {
+		t.Errorf("size, expected at most a third of %v got %v", len(gj), len(gb))
+	}
+}
@@ -0,0 +1,188 @@
+package geobuf
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// The protocol buffer wire types used by geobuf
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// pbfWriter appends the fields of a protocol buffer message
+type pbfWriter struct {
+	buf []byte
+}
+
+func (w *pbfWriter) varint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	w.buf = append(w.buf, b[:n]...)
+}
+
+func (w *pbfWriter) key(field, wire int) {
+	w.varint(uint64(field<<3 | wire))
+}
+
+func (w *pbfWriter) varintField(field int, v uint64) {
+	w.key(field, wireVarint)
+	w.varint(v)
+}
+
+func (w *pbfWriter) bytesField(field int, b []byte) {
+	w.key(field, wireBytes)
+	w.varint(uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *pbfWriter) stringField(field int, s string) {
+	w.key(field, wireBytes)
+	w.varint(uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *pbfWriter) doubleField(field int, f float64) {
+	w.key(field, wireFixed64)
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(f))
+	w.buf = append(w.buf, b[:]...)
+}
+
+// packedVarintsField writes the values as a packed field, if there are any
+func (w *pbfWriter) packedVarintsField(field int, vs []uint64) {
+	if len(vs) == 0 {
+		return
+	}
+	var p pbfWriter
+	for _, v := range vs {
+		p.varint(v)
+	}
+	w.bytesField(field, p.buf)
+}
+
+// packedSVarintsField writes the values zigzag encoded as a packed field,
+// if there are any
+func (w *pbfWriter) packedSVarintsField(field int, vs []int64) {
+	if len(vs) == 0 {
+		return
+	}
+	var p pbfWriter
+	for _, v := range vs {
+		p.varint(zigzag(v))
+	}
+	w.bytesField(field, p.buf)
+}
+
+func zigzag(v int64) uint64 { return uint64(v<<1) ^ uint64(v>>63) }
+
+func unzigzag(v uint64) int64 { return int64(v>>1) ^ -int64(v&1) }
+
+// pbfReader reads the fields of a protocol buffer message. Once the
+// message is found to be invalid err is set and the reads return zero
+// values.
+type pbfReader struct {
+	buf []byte
+	err error
+}
+
+// next reads the key of the next field, returning false at the end of the
+// message or if it is invalid
+func (r *pbfReader) next() (field, wire int, ok bool) {
+	if r.err != nil || len(r.buf) == 0 {
+		return 0, 0, false
+	}
+	k := r.varint()
+	if r.err != nil {
+		return 0, 0, false
+	}
+	return int(k >> 3), int(k & 7), true
+}
+
+func (r *pbfReader) varint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.err = ErrInvalidData
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *pbfReader) bytes() []byte {
+	n := r.varint()
+	if r.err != nil {
+		return nil
+	}
+	if n > uint64(len(r.buf)) {
+		r.err = ErrInvalidData
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *pbfReader) fixed(size int) uint64 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.buf) < size {
+		r.err = ErrInvalidData
+		return 0
+	}
+	var v uint64
+	if size == 8 {
+		v = binary.LittleEndian.Uint64(r.buf)
+	} else {
+		v = uint64(binary.LittleEndian.Uint32(r.buf))
+	}
+	r.buf = r.buf[size:]
+	return v
+}
+
+func (r *pbfReader) double() float64 {
+	return math.Float64frombits(r.fixed(8))
+}
+
+// skip skips the value of a field that is not used
+func (r *pbfReader) skip(wire int) {
+	switch wire {
+	case wireVarint:
+		r.varint()
+	case wireFixed64:
+		r.fixed(8)
+	case wireBytes:
+		r.bytes()
+	case wireFixed32:
+		r.fixed(4)
+	default:
+		r.err = ErrInvalidData
+	}
+}
+
+// varints appends the values of a repeated varint field, which may be
+// packed or not
+func (r *pbfReader) varints(wire int, vs []uint64) []uint64 {
+	if wire == wireVarint {
+		return append(vs, r.varint())
+	}
+	if wire != wireBytes {
+		r.err = ErrInvalidData
+		return vs
+	}
+	p := pbfReader{buf: r.bytes()}
+	for r.err == nil && p.err == nil && len(p.buf) > 0 {
+		vs = append(vs, p.varint())
+	}
+	if p.err != nil {
+		r.err = p.err
+	}
+	return vs
+}
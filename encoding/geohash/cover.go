@@ -0,0 +1,99 @@
+package geohash
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/clip"
+)
+
+// MaxCoverCells is the most geohashes Cover looks at, as the number of
+// cells in the extent of a geometry grows by 32 for each character of
+// precision.
+var MaxCoverCells = 1 << 20
+
+// ErrTooManyCells is returned by Cover when there are more than
+// MaxCoverCells cells of the precision in the extent of the geometry.
+var ErrTooManyCells = errors.New("geohash: too many cells")
+
+// Cover returns the geohashes of precision characters whose cells
+// intersect the geometry, sorted from the south west, by longitude then
+// latitude; they are the fewest geohashes of that precision that cover
+// it. Extents and *Extents cover all the cells of their extent, and the
+// other geometries the cells they have a point, line or area in. Cells
+// that polygons only touch are not included.
+func Cover(g geom.Geometry, precision int) ([]string, error) {
+	if err := checkPrecision(precision); err != nil {
+		return nil, err
+	}
+
+	var ext *geom.Extent
+	switch gg := g.(type) {
+	case geom.Extent:
+		ext, g = &gg, nil
+	case *geom.Extent:
+		ext, g = gg, nil
+	default:
+		var err error
+		if ext, err = geom.NewExtentFromGeometry(g); err != nil {
+			return nil, err
+		}
+	}
+	if ext == nil {
+		return nil, nil
+	}
+
+	lo, err := cellOf(ext.Min(), precision)
+	if err != nil {
+		return nil, err
+	}
+	hi, err := cellOf(ext.Max(), precision)
+	if err != nil {
+		return nil, err
+	}
+	if (hi.x-lo.x+1)*(hi.y-lo.y+1) > uint64(MaxCoverCells) {
+		return nil, ErrTooManyCells
+	}
+
+	var hashes []string
+	for c := lo; c.x <= hi.x; c.x++ {
+		for c.y = lo.y; c.y <= hi.y; c.y++ {
+			ok, err := intersects(g, c.extent())
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				hashes = append(hashes, c.hash())
+			}
+		}
+	}
+	return hashes, nil
+}
+
+// intersects reports whether the geometry, nil for an extent, has part of
+// it in the cell
+func intersects(g geom.Geometry, cell *geom.Extent) (bool, error) {
+	if g == nil {
+		return true, nil
+	}
+	cg, err := clip.Geometry(context.Background(), g, cell)
+	if err != nil {
+		return false, err
+	}
+	switch cgg := cg.(type) {
+	case nil:
+		return false, nil
+	case geom.MultiPoint:
+		return len(cgg) > 0, nil
+	case geom.MultiLineString:
+		return len(cgg) > 0, nil
+	case geom.Polygon:
+		return len(cgg) > 0, nil
+	case geom.MultiPolygon:
+		return len(cgg) > 0, nil
+	case geom.Collection:
+		return len(cgg) > 0, nil
+	}
+	return true, nil
+}
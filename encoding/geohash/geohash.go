@@ -0,0 +1,243 @@
+// Package geohash encodes longitude and latitude points as geohashes, and
+// finds the geohashes around a point or covering a geometry.
+//
+// A geohash of n characters names a cell of a grid over the world: its 5n
+// bits alternate between halving the cell by longitude and by latitude,
+// starting with longitude. Points are [2]float64{longitude, latitude} in
+// degrees.
+package geohash
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/go-spatial/geom"
+)
+
+// MaxPrecision is the most characters of a geohash.
+const MaxPrecision = 12
+
+// base32 are the characters of a geohash, indexed by their value
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+var (
+	// ErrInvalidPrecision is returned when the precision is not between 1
+	// and MaxPrecision.
+	ErrInvalidPrecision = errors.New("geohash: invalid precision")
+	// ErrOutOfRange is returned when a longitude is not between -180 and
+	// 180, or a latitude between -90 and 90.
+	ErrOutOfRange = errors.New("geohash: point out of range")
+	// ErrInvalidHash is returned when a geohash is empty, too long or has
+	// characters that are not in a geohash.
+	ErrInvalidHash = errors.New("geohash: invalid hash")
+	// ErrInvalidDirection is returned when the direction is not one of the
+	// Direction constants.
+	ErrInvalidDirection = errors.New("geohash: invalid direction")
+)
+
+// cell is a cell of the grid of geohashes of a precision, indexed from the
+// south west
+type cell struct {
+	x, y      uint64
+	precision int
+}
+
+// bits returns the number of longitude and latitude bits of geohashes of
+// the precision
+func bits(precision int) (xbits, ybits uint) {
+	n := uint(5 * precision)
+	return (n + 1) / 2, n / 2
+}
+
+// size returns the width and height of the cells of the precision
+func size(precision int) (w, h float64) {
+	xbits, ybits := bits(precision)
+	return 360 / float64(uint64(1)<<xbits), 180 / float64(uint64(1)<<ybits)
+}
+
+func checkPrecision(precision int) error {
+	if precision < 1 || precision > MaxPrecision {
+		return ErrInvalidPrecision
+	}
+	return nil
+}
+
+// cellOf returns the cell containing the point; points on the lines
+// between cells are in the cell to the north east, except at the
+// antimeridian and the north pole
+func cellOf(pt [2]float64, precision int) (cell, error) {
+	if err := checkPrecision(precision); err != nil {
+		return cell{}, err
+	}
+	if !(pt[0] >= -180 && pt[0] <= 180 && pt[1] >= -90 && pt[1] <= 90) {
+		return cell{}, ErrOutOfRange
+	}
+	xbits, ybits := bits(precision)
+	return cell{
+		x:         index(pt[0]+180, 360, xbits),
+		y:         index(pt[1]+90, 180, ybits),
+		precision: precision,
+	}, nil
+}
+
+// index returns the index of the cell of 2^n cells over [0, span] v is in
+func index(v, span float64, n uint) uint64 {
+	cells := uint64(1) << n
+	i := uint64(math.Floor(v / span * float64(cells)))
+	if i >= cells {
+		i = cells - 1
+	}
+	return i
+}
+
+// hash returns the geohash of the cell
+func (c cell) hash() string {
+	xbits, ybits := bits(c.precision)
+	b := make([]byte, c.precision)
+	for i := range b {
+		var v byte
+		for j := 0; j < 5; j++ {
+			var bit uint64
+			// the bits alternate starting with longitude
+			if k := 5*i + j; k%2 == 0 {
+				xbits--
+				bit = c.x >> xbits & 1
+			} else {
+				ybits--
+				bit = c.y >> ybits & 1
+			}
+			v = v<<1 | byte(bit)
+		}
+		b[i] = base32[v]
+	}
+	return string(b)
+}
+
+// extent returns the extent of the cell
+func (c cell) extent() *geom.Extent {
+	w, h := size(c.precision)
+	return &geom.Extent{
+		float64(c.x)*w - 180,
+		float64(c.y)*h - 90,
+		float64(c.x+1)*w - 180,
+		float64(c.y+1)*h - 90,
+	}
+}
+
+// parse returns the cell of the geohash
+func parse(hash string) (cell, error) {
+	if len(hash) == 0 || len(hash) > MaxPrecision {
+		return cell{}, ErrInvalidHash
+	}
+	c := cell{precision: len(hash)}
+	for i := 0; i < len(hash); i++ {
+		v := strings.IndexByte(base32, lower(hash[i]))
+		if v == -1 {
+			return cell{}, ErrInvalidHash
+		}
+		for j := 4; j >= 0; j-- {
+			bit := uint64(v>>uint(j)) & 1
+			if k := 5*i + 4 - j; k%2 == 0 {
+				c.x = c.x<<1 | bit
+			} else {
+				c.y = c.y<<1 | bit
+			}
+		}
+	}
+	return c, nil
+}
+
+func lower(b byte) byte {
+	if b >= 'A' && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
+
+// Encode returns the geohash of precision characters of the cell
+// containing the point. Points on the lines between cells are in the cell
+// to their north east, except on the antimeridian at 180 and the north
+// pole.
+func Encode(pt [2]float64, precision int) (string, error) {
+	c, err := cellOf(pt, precision)
+	if err != nil {
+		return "", err
+	}
+	return c.hash(), nil
+}
+
+// Decode returns the extent of the cell of the geohash. Upper case
+// characters are decoded as lower case ones.
+func Decode(hash string) (*geom.Extent, error) {
+	c, err := parse(hash)
+	if err != nil {
+		return nil, err
+	}
+	return c.extent(), nil
+}
+
+// Direction is the direction of a neighbor of a geohash.
+type Direction uint8
+
+// The directions of the neighbors of a geohash, clockwise from north.
+const (
+	North Direction = iota
+	NorthEast
+	East
+	SouthEast
+	South
+	SouthWest
+	West
+	NorthWest
+)
+
+// offsets are the cells to the neighbors in each direction
+var offsets = [...][2]int{
+	North:     {0, 1},
+	NorthEast: {1, 1},
+	East:      {1, 0},
+	SouthEast: {1, -1},
+	South:     {0, -1},
+	SouthWest: {-1, -1},
+	West:      {-1, 0},
+	NorthWest: {-1, 1},
+}
+
+// Neighbor returns the geohash of the same precision next to the geohash
+// in the direction. Neighbors wrap around the antimeridian; there are none
+// past the poles, where the geohash is empty.
+func Neighbor(hash string, dir Direction) (string, error) {
+	c, err := parse(hash)
+	if err != nil {
+		return "", err
+	}
+	if int(dir) >= len(offsets) {
+		return "", ErrInvalidDirection
+	}
+	xbits, ybits := bits(c.precision)
+	off := offsets[dir]
+	y := int64(c.y) + int64(off[1])
+	if y < 0 || y >= int64(1)<<ybits {
+		return "", nil
+	}
+	// wrap around the antimeridian
+	xcells := int64(1) << xbits
+	c.x = uint64((int64(c.x) + int64(off[0]) + xcells) % xcells)
+	c.y = uint64(y)
+	return c.hash(), nil
+}
+
+// Neighbors returns the neighbors of the geohash in the order of the
+// directions, clockwise from north.
+func Neighbors(hash string) ([8]string, error) {
+	var ns [8]string
+	for dir := range ns {
+		n, err := Neighbor(hash, Direction(dir))
+		if err != nil {
+			return ns, err
+		}
+		ns[dir] = n
+	}
+	return ns, nil
+}
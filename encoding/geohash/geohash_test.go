@@ -0,0 +1,181 @@
+package geohash_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/geohash"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	type tcase struct {
+		pt        [2]float64
+		precision int
+		hash      string
+		err       error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			hash, err := geohash.Encode(tc.pt, tc.precision)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				return
+			}
+			if hash != tc.hash {
+				t.Errorf("hash, expected %v got %v", tc.hash, hash)
+			}
+
+			ext, err := geohash.Decode(hash)
+			if err != nil {
+				t.Fatalf("decode error, expected nil got %v", err)
+			}
+			if !ext.ContainsPoint(tc.pt) {
+				t.Errorf("decode, expected %v to contain %v", ext, tc.pt)
+			}
+			// a level has 32 times the cells of the one before
+			if area := 360.0 * 180; ext.Area()*float64(int64(1)<<uint(5*tc.precision)) != area {
+				t.Errorf("decode area, expected %v got %v", area/float64(int64(1)<<uint(5*tc.precision)), ext.Area())
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"ezs42":         {pt: [2]float64{-5.6, 42.6}, precision: 5, hash: "ezs42"},
+		"u4pruydqqvj":   {pt: [2]float64{10.40744, 57.64911}, precision: 11, hash: "u4pruydqqvj"},
+		"origin":        {pt: [2]float64{0, 0}, precision: 1, hash: "s"},
+		"south west":    {pt: [2]float64{-180, -90}, precision: 3, hash: "000"},
+		"north east":    {pt: [2]float64{180, 90}, precision: 3, hash: "zzz"},
+		"max precision": {pt: [2]float64{1, 1}, precision: geohash.MaxPrecision, hash: "s00twy01mtw0"},
+		"precision 0":   {pt: [2]float64{1, 1}, err: geohash.ErrInvalidPrecision},
+		"precision 13":  {pt: [2]float64{1, 1}, precision: 13, err: geohash.ErrInvalidPrecision},
+		"longitude":     {pt: [2]float64{181, 1}, precision: 1, err: geohash.ErrOutOfRange},
+		"latitude":      {pt: [2]float64{1, -91}, precision: 1, err: geohash.ErrOutOfRange},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	if ext, err := geohash.Decode("EZS42"); err != nil || ext == nil || !ext.ContainsPoint([2]float64{-5.6, 42.6}) {
+		t.Errorf("decode upper case, expected to contain [-5.6 42.6] got %v %v", ext, err)
+	}
+	for _, hash := range []string{"", "ezs4a", "0123456789bcd"} {
+		if _, err := geohash.Decode(hash); err != geohash.ErrInvalidHash {
+			t.Errorf("decode %q, expected %v got %v", hash, geohash.ErrInvalidHash, err)
+		}
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	type tcase struct {
+		hash      string
+		neighbors [8]string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ns, err := geohash.Neighbors(tc.hash)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if ns != tc.neighbors {
+				t.Errorf("neighbors, expected %v got %v", tc.neighbors, ns)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"ezs42": {
+			hash:      "ezs42",
+			neighbors: [8]string{"ezs48", "ezs49", "ezs43", "ezs41", "ezs40", "ezefp", "ezefr", "ezefx"},
+		},
+		"south west corner": {
+			hash:      "0",
+			neighbors: [8]string{"2", "3", "1", "", "", "", "p", "r"},
+		},
+		"north east corner": {
+			hash:      "z",
+			neighbors: [8]string{"", "", "b", "8", "x", "w", "y", ""},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	if _, err := geohash.Neighbor("ezs42", geohash.NorthWest+1); err != geohash.ErrInvalidDirection {
+		t.Errorf("direction, expected %v got %v", geohash.ErrInvalidDirection, err)
+	}
+}
+
+func TestCover(t *testing.T) {
+	type tcase struct {
+		geom      geom.Geometry
+		precision int
+		hashes    []string
+		err       error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			hashes, err := geohash.Cover(tc.geom, tc.precision)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(hashes, tc.hashes) {
+				t.Errorf("hashes, expected %v got %v", tc.hashes, hashes)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"extent": {
+			geom:      geom.Extent{0, 0, 10, 10},
+			precision: 1,
+			hashes:    []string{"s"},
+		},
+		"extent across cells": {
+			geom:      &geom.Extent{-10, -10, 10, 10},
+			precision: 1,
+			hashes:    []string{"7", "e", "k", "s"},
+		},
+		"polygon": {
+			// the triangle only touches the cell s at the origin
+			geom:      geom.Polygon{{{-10, -10}, {10, -10}, {-10, 10}}},
+			precision: 1,
+			hashes:    []string{"7", "e", "k"},
+		},
+		"line": {
+			geom:      geom.LineString{{-10, 10}, {-5, 10}},
+			precision: 1,
+			hashes:    []string{"e"},
+		},
+		"point": {
+			geom:      geom.Point{-5.6, 42.6},
+			precision: 5,
+			hashes:    []string{"ezs42"},
+		},
+		"empty": {
+			geom:      geom.MultiPoint{},
+			precision: 1,
+		},
+		"too many cells": {
+			geom:      geom.Extent{0, 0, 10, 10},
+			precision: 8,
+			err:       geohash.ErrTooManyCells,
+		},
+		"out of range": {
+			geom:      geom.LineString{{170, 0}, {190, 0}},
+			precision: 1,
+			err:       geohash.ErrOutOfRange,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,303 @@
+package geojson
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/go-spatial/geom/encoding"
+)
+
+// featureType allows the GeoJSON type for Feature to be automatically set during json Marshalling
+// which avoids the user from accidentally setting the incorrect GeoJSON type.
+type featureType struct{}
+
+func (_ featureType) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + FeatureType + `"`), nil
+}
+func (fc *featureType) UnmarshalJSON(b []byte) error { return nil }
+
+// Feature is a geometry with an id and properties.
+type Feature struct {
+	Type featureType `json:"type"`
+	// ID is the id of the feature when it is a number that fits in a uint64
+	ID *uint64 `json:"id,omitempty"`
+	// OtherID is the id of the feature when it is not a uint64, a string or
+	// a number decoded as a json.Number. It is ignored when ID is set.
+	OtherID interface{} `json:"-"`
+	// BBox is the bounding box of the feature, the minimums of each axis
+	// followed by the maximums
+	BBox []float64 `json:"bbox,omitempty"`
+	// can be null
+	Geometry Geometry `json:"geometry"`
+	// can be null
+	Properties map[string]interface{} `json:"properties"`
+	// ForeignMembers are the other members of the feature object, they are
+	// kept as is so they are written back out when the feature is encoded
+	ForeignMembers map[string]json.RawMessage `json:"-"`
+}
+
+// IDValue returns the id of the feature, the uint64 in ID when it is set
+// and OtherID otherwise.
+func (f Feature) IDValue() interface{} {
+	if f.ID != nil {
+		return *f.ID
+	}
+	return f.OtherID
+}
+
+// SetID sets the id of the feature. A json.Number or an integer that fits
+// in a uint64 is stored in ID, any other id in OtherID.
+func (f *Feature) SetID(id interface{}) {
+	f.ID, f.OtherID = nil, nil
+	var u uint64
+	switch v := id.(type) {
+	case nil:
+		return
+	case uint64:
+		u = v
+	case uint:
+		u = uint64(v)
+	case uint32:
+		u = uint64(v)
+	case int:
+		if v < 0 {
+			f.OtherID = id
+			return
+		}
+		u = uint64(v)
+	case int64:
+		if v < 0 {
+			f.OtherID = id
+			return
+		}
+		u = uint64(v)
+	case float64:
+		if v < 0 || v >= 1<<64 || v != math.Trunc(v) {
+			f.OtherID = id
+			return
+		}
+		u = uint64(v)
+	case json.Number:
+		n, err := strconv.ParseUint(string(v), 10, 64)
+		if err != nil {
+			f.OtherID = id
+			return
+		}
+		u = n
+	default:
+		f.OtherID = id
+		return
+	}
+	f.ID = &u
+}
+
+// featureMembers are the members of a feature that are not foreign
+var featureMembers = []string{"type", "id", "bbox", "geometry", "properties"}
+
+// MarshalJSON encodes the feature, a nil geometry is encoded as null.
+func (f Feature) MarshalJSON() ([]byte, error) {
+	type feature struct {
+		Type       featureType            `json:"type"`
+		ID         interface{}            `json:"id,omitempty"`
+		BBox       []float64              `json:"bbox,omitempty"`
+		Geometry   *Geometry              `json:"geometry"`
+		Properties map[string]interface{} `json:"properties"`
+	}
+	ff := feature{
+		ID:         f.IDValue(),
+		BBox:       f.BBox,
+		Properties: f.Properties,
+	}
+	if f.Geometry.Geometry != nil {
+		ff.Geometry = &f.Geometry
+	}
+	b, err := json.Marshal(ff)
+	if err != nil {
+		return nil, err
+	}
+	return appendMembers(b, f.ForeignMembers, featureMembers)
+}
+
+// UnmarshalJSON decodes a feature, the members that are not part of a
+// feature are kept in ForeignMembers.
+func (f *Feature) UnmarshalJSON(b []byte) error {
+	members, err := decodeMembers(b, FeatureType)
+	if err != nil {
+		return err
+	}
+	var ff Feature
+	if raw, ok := members["id"]; ok {
+		id, err := decodeID(raw)
+		if err != nil {
+			return err
+		}
+		ff.SetID(id)
+	}
+	if err = unmarshalMember(members, "bbox", &ff.BBox); err != nil {
+		return err
+	}
+	if err = unmarshalMember(members, "geometry", &ff.Geometry); err != nil {
+		return err
+	}
+	if err = unmarshalMember(members, "properties", &ff.Properties); err != nil {
+		return err
+	}
+	ff.ForeignMembers = foreignMembers(members, featureMembers)
+	*f = ff
+	return nil
+}
+
+// featureCollectionType allows the GeoJSON type for Feature to be automatically set during json Marshalling
+// which avoids the user from accidentally setting the incorrect GeoJSON type.
+type featureCollectionType struct{}
+
+func (_ featureCollectionType) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + FeatureCollectionType + `"`), nil
+}
+func (fc *featureCollectionType) UnmarshalJSON(b []byte) error { return nil }
+
+// FeatureCollection is a list of features.
+type FeatureCollection struct {
+	Type featureCollectionType `json:"type"`
+	// BBox is the bounding box of the features, the minimums of each axis
+	// followed by the maximums
+	BBox     []float64 `json:"bbox,omitempty"`
+	Features []Feature `json:"features"`
+	// ForeignMembers are the other members of the feature collection
+	// object, they are kept as is so they are written back out when the
+	// collection is encoded
+	ForeignMembers map[string]json.RawMessage `json:"-"`
+}
+
+// featureCollectionMembers are the members of a feature collection that
+// are not foreign
+var featureCollectionMembers = []string{"type", "bbox", "features"}
+
+// MarshalJSON encodes the feature collection, nil Features are encoded as
+// an empty list.
+func (fc FeatureCollection) MarshalJSON() ([]byte, error) {
+	type featureCollection struct {
+		Type     featureCollectionType `json:"type"`
+		BBox     []float64             `json:"bbox,omitempty"`
+		Features []Feature             `json:"features"`
+	}
+	ffc := featureCollection{
+		BBox:     fc.BBox,
+		Features: fc.Features,
+	}
+	if ffc.Features == nil {
+		ffc.Features = []Feature{}
+	}
+	b, err := json.Marshal(ffc)
+	if err != nil {
+		return nil, err
+	}
+	return appendMembers(b, fc.ForeignMembers, featureCollectionMembers)
+}
+
+// UnmarshalJSON decodes a feature collection, the members that are not
+// part of a feature collection are kept in ForeignMembers.
+func (fc *FeatureCollection) UnmarshalJSON(b []byte) error {
+	members, err := decodeMembers(b, FeatureCollectionType)
+	if err != nil {
+		return err
+	}
+	var ffc FeatureCollection
+	if err = unmarshalMember(members, "bbox", &ffc.BBox); err != nil {
+		return err
+	}
+	if err = unmarshalMember(members, "features", &ffc.Features); err != nil {
+		return err
+	}
+	ffc.ForeignMembers = foreignMembers(members, featureCollectionMembers)
+	*fc = ffc
+	return nil
+}
+
+// decodeMembers decodes the members of an object, checking its type is typ
+func decodeMembers(b []byte, typ GeoJSONType) (map[string]json.RawMessage, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(b, &members); err != nil {
+		return nil, err
+	}
+	var t GeoJSONType
+	if err := unmarshalMember(members, "type", &t); err != nil {
+		return nil, err
+	}
+	if t != typ {
+		return nil, encoding.ErrInvalidGeoJSON{GJSON: b}
+	}
+	return members, nil
+}
+
+// unmarshalMember decodes the member into v if it is in members
+func unmarshalMember(members map[string]json.RawMessage, name string, v interface{}) error {
+	raw, ok := members[name]
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// decodeID decodes an id, which is a string or a number
+func decodeID(raw json.RawMessage) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var id interface{}
+	if err := dec.Decode(&id); err != nil {
+		return nil, err
+	}
+	switch id.(type) {
+	case nil, string, json.Number:
+		return id, nil
+	default:
+		return nil, encoding.ErrInvalidGeoJSON{GJSON: raw}
+	}
+}
+
+func isMember(name string, names []string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// foreignMembers returns the members whose names are not in names
+func foreignMembers(members map[string]json.RawMessage, names []string) map[string]json.RawMessage {
+	var foreign map[string]json.RawMessage
+	for name, raw := range members {
+		if isMember(name, names) {
+			continue
+		}
+		if foreign == nil {
+			foreign = make(map[string]json.RawMessage)
+		}
+		foreign[name] = raw
+	}
+	return foreign
+}
+
+// appendMembers adds the foreign members, except those whose names are in
+// names, to the end of the encoded object obj
+func appendMembers(obj []byte, foreign map[string]json.RawMessage, names []string) ([]byte, error) {
+	extra := make(map[string]json.RawMessage, len(foreign))
+	for name, raw := range foreign {
+		if !isMember(name, names) {
+			extra[name] = raw
+		}
+	}
+	if len(extra) == 0 {
+		return obj, nil
+	}
+	b, err := json.Marshal(extra)
+	if err != nil {
+		return nil, err
+	}
+	// join {...} and {...} into {...,...}
+	obj = append(obj[:len(obj)-1], ',')
+	return append(obj, b[1:]...), nil
+}
@@ -0,0 +1,191 @@
+package geojson_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/geojson"
+)
+
+func uint64Ptr(u uint64) *uint64 { return &u }
+
+func TestFeatureRoundTrip(t *testing.T) {
+	type tcase struct {
+		gjson    string
+		expected geojson.Feature
+		// output is the expected encoding, if it differs from gjson
+		output string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var f geojson.Feature
+			if err := json.Unmarshal([]byte(tc.gjson), &f); err != nil {
+				t.Fatalf("unmarshal, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(f, tc.expected) {
+				t.Errorf("unmarshal, expected %#v got %#v", tc.expected, f)
+			}
+			output, err := json.Marshal(f)
+			if err != nil {
+				t.Fatalf("marshal, expected nil got %v", err)
+			}
+			exp := tc.output
+			if exp == "" {
+				exp = tc.gjson
+			}
+			if string(output) != exp {
+				t.Errorf("marshal, expected %v got %v", exp, string(output))
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"number id": {
+			gjson: `{"type":"Feature","id":12345678901234567890,"geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"a"}}`,
+			expected: geojson.Feature{
+				ID:         uint64Ptr(12345678901234567890),
+				Geometry:   geojson.Geometry{geom.Point{1, 2}},
+				Properties: map[string]interface{}{"name": "a"},
+			},
+		},
+		"string id": {
+			gjson: `{"type":"Feature","id":"a1","geometry":{"type":"Point","coordinates":[1,2]},"properties":null}`,
+			expected: geojson.Feature{
+				OtherID:  "a1",
+				Geometry: geojson.Geometry{geom.Point{1, 2}},
+			},
+		},
+		"negative id": {
+			gjson: `{"type":"Feature","id":-1.5,"geometry":{"type":"Point","coordinates":[1,2]},"properties":null}`,
+			expected: geojson.Feature{
+				OtherID:  json.Number("-1.5"),
+				Geometry: geojson.Geometry{geom.Point{1, 2}},
+			},
+		},
+		"bbox": {
+			gjson: `{"type":"Feature","bbox":[1,2,3,4],"geometry":{"type":"LineString","coordinates":[[1,2],[3,4]]},"properties":null}`,
+			expected: geojson.Feature{
+				BBox:     []float64{1, 2, 3, 4},
+				Geometry: geojson.Geometry{geom.LineString{{1, 2}, {3, 4}}},
+			},
+		},
+		"null geometry": {
+			gjson: `{"type":"Feature","geometry":null,"properties":{"n":1}}`,
+			expected: geojson.Feature{
+				Properties: map[string]interface{}{"n": float64(1)},
+			},
+		},
+		"foreign members": {
+			gjson: `{"properties":null,"title":"x","type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"extra":{"a":[1, 2]}}`,
+			expected: geojson.Feature{
+				Geometry: geojson.Geometry{geom.Point{1, 2}},
+				ForeignMembers: map[string]json.RawMessage{
+					"title": json.RawMessage(`"x"`),
+					"extra": json.RawMessage(`{"a":[1, 2]}`),
+				},
+			},
+			output: `{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":null,"extra":{"a":[1,2]},"title":"x"}`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestFeatureCollectionRoundTrip(t *testing.T) {
+	gjson := `{"type":"FeatureCollection","bbox":[0,0,1,1],"features":[{"type":"Feature","id":1,"geometry":{"type":"Point","coordinates":[0,0]},"properties":null}],"name":"points"}`
+	expected := geojson.FeatureCollection{
+		BBox: []float64{0, 0, 1, 1},
+		Features: []geojson.Feature{{
+			ID:       uint64Ptr(1),
+			Geometry: geojson.Geometry{geom.Point{0, 0}},
+		}},
+		ForeignMembers: map[string]json.RawMessage{"name": json.RawMessage(`"points"`)},
+	}
+
+	var fc geojson.FeatureCollection
+	if err := json.Unmarshal([]byte(gjson), &fc); err != nil {
+		t.Fatalf("unmarshal, expected nil got %v", err)
+	}
+	if !reflect.DeepEqual(fc, expected) {
+		t.Errorf("unmarshal, expected %#v got %#v", expected, fc)
+	}
+	output, err := json.Marshal(fc)
+	if err != nil {
+		t.Fatalf("marshal, expected nil got %v", err)
+	}
+	if string(output) != gjson {
+		t.Errorf("marshal, expected %v got %v", gjson, string(output))
+	}
+
+	output, err = json.Marshal(geojson.FeatureCollection{})
+	if err != nil {
+		t.Fatalf("marshal empty, expected nil got %v", err)
+	}
+	if exp := `{"type":"FeatureCollection","features":[]}`; string(output) != exp {
+		t.Errorf("marshal empty, expected %v got %v", exp, string(output))
+	}
+}
+
+func TestFeatureUnmarshalErrors(t *testing.T) {
+	tests := map[string]string{
+		"wrong type":  `{"type":"Point","coordinates":[1,2]}`,
+		"no type":     `{"geometry":null,"properties":null}`,
+		"object id":   `{"type":"Feature","id":{},"geometry":null,"properties":null}`,
+		"bad feature": `{"type":"Feature","geometry":{"coordinates":[1,2]},"properties":null}`,
+	}
+	for name, gjson := range tests {
+		var f geojson.Feature
+		if err := json.Unmarshal([]byte(gjson), &f); err == nil {
+			t.Errorf("%v: expected an error got nil", name)
+		}
+	}
+}
+
+func TestFeatureSetID(t *testing.T) {
+	type tcase struct {
+		id      interface{}
+		uid     *uint64
+		otherID interface{}
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			f := geojson.Feature{OtherID: "old"}
+			f.SetID(tc.id)
+			if !reflect.DeepEqual(f.ID, tc.uid) {
+				t.Errorf("id, expected %v got %v", tc.uid, f.ID)
+			}
+			if !reflect.DeepEqual(f.OtherID, tc.otherID) {
+				t.Errorf("other id, expected %#v got %#v", tc.otherID, f.OtherID)
+			}
+			exp := tc.otherID
+			if tc.uid != nil {
+				exp = *tc.uid
+			}
+			if !reflect.DeepEqual(f.IDValue(), exp) {
+				t.Errorf("id value, expected %#v got %#v", exp, f.IDValue())
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"nil":             {},
+		"uint64":          {id: uint64(1 << 63), uid: uint64Ptr(1 << 63)},
+		"int":             {id: 7, uid: uint64Ptr(7)},
+		"negative int":    {id: -7, otherID: -7},
+		"float":           {id: 7.0, uid: uint64Ptr(7)},
+		"fraction":        {id: 7.5, otherID: 7.5},
+		"number":          {id: json.Number("18446744073709551615"), uid: uint64Ptr(18446744073709551615)},
+		"negative number": {id: json.Number("-1"), otherID: json.Number("-1")},
+		"string":          {id: "a", otherID: "a"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -1,6 +1,7 @@
 package geojson
 
 import (
+	"bytes"
 	"encoding/json"
 
 	"github.com/go-spatial/geom"
@@ -96,43 +97,14 @@ func (geo Geometry) MarshalJSON() ([]byte, error) {
 			Geometries: geos,
 		})
 
+	case Feature, FeatureCollection, *Feature, *FeatureCollection:
+		return json.Marshal(g)
+
 	default:
 		return nil, geom.ErrUnknownGeometry{g}
 	}
 }
 
-// featureType allows the GeoJSON type for Feature to be automatically set during json Marshalling
-// which avoids the user from accidentally setting the incorrect GeoJSON type.
-type featureType struct{}
-
-func (_ featureType) MarshalJSON() ([]byte, error) {
-	return []byte(`"` + FeatureType + `"`), nil
-}
-func (fc *featureType) UnmarshalJSON(b []byte) error { return nil }
-
-type Feature struct {
-	Type featureType `json:"type"`
-	ID   *uint64     `json:"id,omitempty"`
-	// can be null
-	Geometry Geometry `json:"geometry"`
-	// can be null
-	Properties map[string]interface{} `json:"properties"`
-}
-
-// featureCollectionType allows the GeoJSON type for Feature to be automatically set during json Marshalling
-// which avoids the user from accidentally setting the incorrect GeoJSON type.
-type featureCollectionType struct{}
-
-func (_ featureCollectionType) MarshalJSON() ([]byte, error) {
-	return []byte(`"` + FeatureCollectionType + `"`), nil
-}
-func (fc *featureCollectionType) UnmarshalJSON(b []byte) error { return nil }
-
-type FeatureCollection struct {
-	Type     featureCollectionType `json:"type"`
-	Features []Feature             `json:"features"`
-}
-
 func closePolygon(p geom.Polygon) {
 	for i := range p {
 		if len(p[i]) == 0 {
@@ -148,10 +120,18 @@ func closePolygon(p geom.Polygon) {
 }
 
 func (geo *Geometry) UnmarshalJSON(b []byte) error {
+	if string(bytes.TrimSpace(b)) == "null" {
+		geo.Geometry = nil
+		return nil
+	}
+
 	var geojsonMap map[string]*json.RawMessage
 	if err := json.Unmarshal(b, &geojsonMap); err != nil {
 		return err
 	}
+	if geojsonMap["type"] == nil {
+		return encoding.ErrInvalidGeoJSON{GJSON: b}
+	}
 
 	var geomType GeoJSONType
 	if err := json.Unmarshal(*geojsonMap["type"], &geomType); err != nil {
@@ -105,11 +105,8 @@ func TestFeatureMarshalJSON(t *testing.T) {
 			expected: []byte(`{"type":"Feature","geometry":{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[12.2,17.7]},{"type":"MultiPoint","coordinates":[[12.2,17.7],[13.3,18.8]]},{"type":"LineString","coordinates":[[3.2,4.3],[5.4,6.5],[7.6,8.7],[9.8,10.9]]}]},"properties":null}`),
 		},
 		"nil geom": {
-			geom: nil,
-			expectedErr: json.MarshalerError{
-				Type: reflect.TypeOf(geojson.Geometry{}),
-				Err:  geom.ErrUnknownGeometry{nil},
-			},
+			geom:     nil,
+			expected: []byte(`{"type":"Feature","geometry":null,"properties":null}`),
 		},
 	}
 
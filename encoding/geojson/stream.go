@@ -0,0 +1,269 @@
+package geojson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/go-spatial/geom/encoding"
+)
+
+// ErrEncoderClosed is returned when a feature is encoded after the stream
+// encoder is closed.
+var ErrEncoderClosed = errors.New("geojson: stream encoder closed")
+
+// StreamDecoder decodes features one at a time, so feature collections
+// larger than memory can be read. The input is a sequence of JSON objects,
+// each a Feature or a FeatureCollection, separated by whitespace; a single
+// FeatureCollection and newline-delimited features are both sequences.
+type StreamDecoder struct {
+	dec *json.Decoder
+	// inFeatures is true when the decoder is in the features of a
+	// collection
+	inFeatures bool
+	// members are the members of the collection being decoded, other than
+	// its features
+	members map[string]json.RawMessage
+	fc      FeatureCollection
+	err     error
+}
+
+// NewStreamDecoder returns a decoder reading from r. It buffers its
+// reads, so it may read past the end of the features from r.
+func NewStreamDecoder(r io.Reader) *StreamDecoder {
+	return &StreamDecoder{dec: json.NewDecoder(r)}
+}
+
+// Next returns the next feature, from the features of a collection or on
+// its own, and io.EOF at the end of the input. Once it returns an error it
+// returns the same error from then on.
+func (d *StreamDecoder) Next() (Feature, error) {
+	if d.err != nil {
+		return Feature{}, d.err
+	}
+	f, err := d.next()
+	if err != nil {
+		d.err = err
+	}
+	return f, err
+}
+
+func (d *StreamDecoder) next() (Feature, error) {
+	for {
+		if d.inFeatures {
+			if d.dec.More() {
+				var f Feature
+				err := d.dec.Decode(&f)
+				return f, err
+			}
+			// the closing ]
+			if _, err := d.dec.Token(); err != nil {
+				return Feature{}, err
+			}
+			d.inFeatures = false
+			if err := d.endCollection(); err != nil {
+				return Feature{}, err
+			}
+			continue
+		}
+
+		tok, err := d.dec.Token()
+		if err != nil {
+			// io.EOF at the end of the input
+			return Feature{}, err
+		}
+		if tok != json.Delim('{') {
+			return Feature{}, encoding.ErrInvalidGeoJSON{GJSON: []byte(tokenString(tok))}
+		}
+		d.members = make(map[string]json.RawMessage)
+		features, err := d.readMembers()
+		if err != nil {
+			return Feature{}, err
+		}
+		if features {
+			d.inFeatures = true
+			continue
+		}
+		// an object without features is a feature
+		b, err := json.Marshal(d.members)
+		if err != nil {
+			return Feature{}, err
+		}
+		var f Feature
+		err = f.UnmarshalJSON(b)
+		return f, err
+	}
+}
+
+// readMembers reads the members of the object into d.members until the
+// end of the object, or until the start of its features, returning true if
+// it stopped at the features
+func (d *StreamDecoder) readMembers() (bool, error) {
+	for d.dec.More() {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return false, err
+		}
+		name, _ := tok.(string)
+		if name != "features" {
+			var raw json.RawMessage
+			if err := d.dec.Decode(&raw); err != nil {
+				return false, err
+			}
+			d.members[name] = raw
+			continue
+		}
+		if err := d.checkType(); err != nil {
+			return false, err
+		}
+		if tok, err = d.dec.Token(); err != nil {
+			return false, err
+		}
+		if tok != json.Delim('[') {
+			return false, encoding.ErrInvalidGeoJSON{GJSON: []byte(tokenString(tok))}
+		}
+		return true, nil
+	}
+	// the closing }
+	_, err := d.dec.Token()
+	return false, err
+}
+
+// endCollection reads the members after the features of a collection
+func (d *StreamDecoder) endCollection() error {
+	if _, err := d.readMembers(); err != nil {
+		return err
+	}
+	if _, ok := d.members["type"]; !ok {
+		return d.invalidCollection()
+	}
+	if err := d.checkType(); err != nil {
+		return err
+	}
+	var fc FeatureCollection
+	if err := unmarshalMember(d.members, "bbox", &fc.BBox); err != nil {
+		return err
+	}
+	fc.ForeignMembers = foreignMembers(d.members, featureCollectionMembers)
+	d.fc = fc
+	return nil
+}
+
+// checkType checks the type of the collection being read is
+// FeatureCollection, if it has been read
+func (d *StreamDecoder) checkType() error {
+	var t GeoJSONType
+	if err := unmarshalMember(d.members, "type", &t); err != nil {
+		return err
+	}
+	if _, ok := d.members["type"]; ok && t != FeatureCollectionType {
+		return d.invalidCollection()
+	}
+	return nil
+}
+
+func (d *StreamDecoder) invalidCollection() error {
+	b, _ := json.Marshal(d.members)
+	return encoding.ErrInvalidGeoJSON{GJSON: b}
+}
+
+// Collection returns the bounding box and foreign members of the last
+// feature collection whose features have all been returned by Next.
+// Members after the features are only known once the features have been
+// read, so the members of the collection being read are not returned.
+func (d *StreamDecoder) Collection() FeatureCollection {
+	return d.fc
+}
+
+func tokenString(tok json.Token) string {
+	b, _ := json.Marshal(tok)
+	return string(b)
+}
+
+// StreamEncoder encodes features one at a time, either as the features of
+// a FeatureCollection or as newline-delimited features.
+type StreamEncoder struct {
+	w io.Writer
+	// header and trailer are the parts of the collection before and after
+	// its features; they are nil for newline-delimited features
+	header, trailer []byte
+	count           int
+	closed          bool
+	err             error
+}
+
+// NewStreamEncoder returns an encoder writing a FeatureCollection with the
+// bounding box and foreign members of fc to w; the Features of fc are
+// ignored. Close must be called to end the collection.
+func NewStreamEncoder(w io.Writer, fc FeatureCollection) (*StreamEncoder, error) {
+	b, err := json.Marshal(FeatureCollection{
+		BBox:           fc.BBox,
+		ForeignMembers: fc.ForeignMembers,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the features are encoded as an empty list, which the features go in
+	features := []byte(`"features":[`)
+	i := bytes.Index(b, features) + len(features)
+	return &StreamEncoder{
+		w:       w,
+		header:  b[:i],
+		trailer: b[i:],
+	}, nil
+}
+
+// NewLineDelimitedEncoder returns an encoder writing each feature to w on
+// a line of its own.
+func NewLineDelimitedEncoder(w io.Writer) *StreamEncoder {
+	return &StreamEncoder{w: w}
+}
+
+// Encode writes the feature. Once it returns an error it returns the same
+// error from then on.
+func (e *StreamEncoder) Encode(f Feature) error {
+	if e.err != nil {
+		return e.err
+	}
+	if e.closed {
+		return ErrEncoderClosed
+	}
+	b, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	var buf []byte
+	switch {
+	case e.header == nil:
+		buf = append(b, '\n')
+	case e.count == 0:
+		buf = append(append(buf, e.header...), b...)
+	default:
+		buf = append(append(buf, ','), b...)
+	}
+	if _, e.err = e.w.Write(buf); e.err != nil {
+		return e.err
+	}
+	e.count++
+	return nil
+}
+
+// Close ends the collection, it does not close the writer. Features can
+// not be encoded after it is closed.
+func (e *StreamEncoder) Close() error {
+	if e.err != nil || e.closed {
+		return e.err
+	}
+	e.closed = true
+	if e.header == nil {
+		return nil
+	}
+	var buf []byte
+	if e.count == 0 {
+		buf = append(buf, e.header...)
+	}
+	_, e.err = e.w.Write(append(buf, e.trailer...))
+	return e.err
+}
@@ -0,0 +1,186 @@
+package geojson_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/geojson"
+)
+
+func TestStreamDecoder(t *testing.T) {
+	type tcase struct {
+		gjson    string
+		expected []geojson.Feature
+		// collection is the collection after the last feature
+		collection geojson.FeatureCollection
+		err        bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			dec := geojson.NewStreamDecoder(strings.NewReader(tc.gjson))
+			var got []geojson.Feature
+			var err error
+			for {
+				var f geojson.Feature
+				if f, err = dec.Next(); err != nil {
+					break
+				}
+				got = append(got, f)
+			}
+			if tc.err {
+				if err == io.EOF {
+					t.Errorf("error, expected an error got io.EOF")
+				}
+				return
+			}
+			if err != io.EOF {
+				t.Fatalf("error, expected io.EOF got %v", err)
+			}
+			if _, err = dec.Next(); err != io.EOF {
+				t.Errorf("next after end, expected io.EOF got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("features, expected %#v got %#v", tc.expected, got)
+			}
+			if !reflect.DeepEqual(dec.Collection(), tc.collection) {
+				t.Errorf("collection, expected %#v got %#v", tc.collection, dec.Collection())
+			}
+		}
+	}
+
+	pt := func(id string, x, y float64) geojson.Feature {
+		f := geojson.Feature{Geometry: geojson.Geometry{geom.Point{x, y}}}
+		f.SetID(json.Number(id))
+		return f
+	}
+
+	tests := map[string]tcase{
+		"collection": {
+			gjson: `{"type":"FeatureCollection","bbox":[0,0,1,1],"features":[
+				{"type":"Feature","id":1,"geometry":{"type":"Point","coordinates":[0,0]},"properties":null},
+				{"type":"Feature","id":2,"geometry":{"type":"Point","coordinates":[1,1]},"properties":null}
+			],"name":"points"}`,
+			expected: []geojson.Feature{pt("1", 0, 0), pt("2", 1, 1)},
+			collection: geojson.FeatureCollection{
+				BBox:           []float64{0, 0, 1, 1},
+				ForeignMembers: map[string]json.RawMessage{"name": json.RawMessage(`"points"`)},
+			},
+		},
+		"type after features": {
+			gjson:    `{"features":[{"type":"Feature","id":1,"geometry":{"type":"Point","coordinates":[0,0]},"properties":null}],"type":"FeatureCollection"}`,
+			expected: []geojson.Feature{pt("1", 0, 0)},
+		},
+		"empty collection": {
+			gjson: `{"type":"FeatureCollection","features":[]}`,
+		},
+		"newline delimited": {
+			gjson: `{"type":"Feature","id":1,"geometry":{"type":"Point","coordinates":[0,0]},"properties":null}
+{"type":"Feature","id":2,"geometry":{"type":"Point","coordinates":[1,1]},"properties":null}
+`,
+			expected: []geojson.Feature{pt("1", 0, 0), pt("2", 1, 1)},
+		},
+		"collections and features": {
+			gjson: `{"type":"FeatureCollection","features":[{"type":"Feature","id":1,"geometry":{"type":"Point","coordinates":[0,0]},"properties":null}]}
+{"type":"Feature","id":2,"geometry":{"type":"Point","coordinates":[1,1]},"properties":null}`,
+			expected: []geojson.Feature{pt("1", 0, 0), pt("2", 1, 1)},
+		},
+		"empty": {},
+		"geometry": {
+			gjson: `{"type":"Point","coordinates":[1,2]}`,
+			err:   true,
+		},
+		"wrong collection type": {
+			gjson: `{"type":"Feature","features":[]}`,
+			err:   true,
+		},
+		"no collection type": {
+			gjson: `{"features":[]}`,
+			err:   true,
+		},
+		"array": {
+			gjson: `[{"type":"Feature","geometry":null,"properties":null}]`,
+			err:   true,
+		},
+		"truncated": {
+			gjson: `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":null,"properties":null},`,
+			err:   true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestStreamEncoder(t *testing.T) {
+	features := []geojson.Feature{
+		{ID: uint64Ptr(1), Geometry: geojson.Geometry{geom.Point{0, 0}}},
+		{ID: uint64Ptr(2), Geometry: geojson.Geometry{geom.Point{1, 1}}},
+	}
+	type tcase struct {
+		collection *geojson.FeatureCollection
+		features   []geojson.Feature
+		expected   string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var buf bytes.Buffer
+			enc := geojson.NewLineDelimitedEncoder(&buf)
+			if tc.collection != nil {
+				var err error
+				if enc, err = geojson.NewStreamEncoder(&buf, *tc.collection); err != nil {
+					t.Fatalf("new, expected nil got %v", err)
+				}
+			}
+			for _, f := range tc.features {
+				if err := enc.Encode(f); err != nil {
+					t.Fatalf("encode, expected nil got %v", err)
+				}
+			}
+			if err := enc.Close(); err != nil {
+				t.Fatalf("close, expected nil got %v", err)
+			}
+			if buf.String() != tc.expected {
+				t.Errorf("output, expected %v got %v", tc.expected, buf.String())
+			}
+			if err := enc.Encode(features[0]); err != geojson.ErrEncoderClosed {
+				t.Errorf("encode after close, expected %v got %v", geojson.ErrEncoderClosed, err)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"collection": {
+			collection: &geojson.FeatureCollection{
+				BBox:           []float64{0, 0, 1, 1},
+				ForeignMembers: map[string]json.RawMessage{"name": json.RawMessage(`"points"`)},
+			},
+			features: features,
+			expected: `{"type":"FeatureCollection","bbox":[0,0,1,1],"features":[` +
+				`{"type":"Feature","id":1,"geometry":{"type":"Point","coordinates":[0,0]},"properties":null},` +
+				`{"type":"Feature","id":2,"geometry":{"type":"Point","coordinates":[1,1]},"properties":null}` +
+				`],"name":"points"}`,
+		},
+		"empty collection": {
+			collection: &geojson.FeatureCollection{},
+			expected:   `{"type":"FeatureCollection","features":[]}`,
+		},
+		"newline delimited": {
+			features: features,
+			expected: `{"type":"Feature","id":1,"geometry":{"type":"Point","coordinates":[0,0]},"properties":null}` + "\n" +
+				`{"type":"Feature","id":2,"geometry":{"type":"Point","coordinates":[1,1]},"properties":null}` + "\n",
+		},
+		"newline delimited empty": {},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,565 @@
+package gml
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-spatial/geom"
+)
+
+// Decoder reads geometries from GML.
+type Decoder struct {
+	d       *xml.Decoder
+	srsName string
+	// decoded is whether a geometry has been decoded
+	decoded bool
+}
+
+// NewDecoder returns a Decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{d: xml.NewDecoder(r)}
+}
+
+// SRSName returns the srsName attribute of the last geometry decoded, or
+// an empty string if it had none.
+func (dec *Decoder) SRSName() string { return dec.srsName }
+
+// Decode reads the next element of the GML, decoding it as a geometry.
+// An Envelope or Box is decoded as a geom.Extent. io.EOF is returned if
+// there are no more elements, or ErrNoGeometry if there were none.
+func (dec *Decoder) Decode() (geom.Geometry, error) {
+	var start *xml.StartElement
+	for start == nil {
+		tok, err := dec.d.Token()
+		if err == io.EOF && !dec.decoded {
+			return nil, ErrNoGeometry
+		}
+		if err != nil {
+			return nil, err
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			start = &se
+		}
+	}
+	dec.decoded = true
+	n, err := readElement(dec.d, *start)
+	if err != nil {
+		return nil, err
+	}
+	dec.srsName = n.attrs["srsName"]
+	return decodeGeometry(n, 0)
+}
+
+// element is an element of the GML, without its namespace
+type element struct {
+	name     string
+	attrs    map[string]string
+	children []*element
+	text     string
+}
+
+// readElement reads the element up to its end
+func readElement(d *xml.Decoder, start xml.StartElement) (*element, error) {
+	n := &element{name: start.Name.Local, attrs: make(map[string]string, len(start.Attr))}
+	for _, a := range start.Attr {
+		n.attrs[a.Name.Local] = a.Value
+	}
+	var text strings.Builder
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			c, err := readElement(d, t)
+			if err != nil {
+				return nil, err
+			}
+			n.children = append(n.children, c)
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			n.text = text.String()
+			return n, nil
+		}
+	}
+}
+
+// child returns the first child element with one of the names
+func (n *element) child(names ...string) *element {
+	for _, c := range n.children {
+		for _, name := range names {
+			if c.name == name {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// dim returns the srsDimension of the element, or the given one of the
+// element containing it
+func (n *element) dim(dim int) (int, error) {
+	s, ok := n.attrs["srsDimension"]
+	if !ok {
+		return dim, nil
+	}
+	d, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || d < 2 {
+		return 0, ErrInvalidCoordinates
+	}
+	return d, nil
+}
+
+// coords decodes the coordinates of the pos, posList and coordinates
+// elements of the element. dim is the srsDimension, or 0 if there is none.
+func (n *element) coords(dim int) ([][]float64, error) {
+	dim, err := n.dim(dim)
+	if err != nil {
+		return nil, err
+	}
+	if c := n.child("posList"); c != nil {
+		if dim, err = c.dim(dim); err != nil {
+			return nil, err
+		}
+		if dim == 0 {
+			dim = 2
+		}
+		return parseCoords(strings.Fields(c.text), dim)
+	}
+	if c := n.child("coordinates"); c != nil {
+		return parseCoordinates(c, dim)
+	}
+	var cs [][]float64
+	for _, c := range n.children {
+		if c.name != "pos" {
+			continue
+		}
+		cdim, err := c.dim(dim)
+		if err != nil {
+			return nil, err
+		}
+		fs := strings.Fields(c.text)
+		if cdim == 0 {
+			cdim = len(fs)
+		}
+		pos, err := parseCoords(fs, cdim)
+		if err != nil {
+			return nil, err
+		}
+		if len(pos) != 1 {
+			return nil, ErrInvalidCoordinates
+		}
+		cs = append(cs, pos[0])
+	}
+	if cs == nil {
+		return nil, ErrUnsupportedElement{Name: n.name}
+	}
+	return cs, nil
+}
+
+// parseCoords parses the ordinates, dim for each coordinate
+func parseCoords(fs []string, dim int) ([][]float64, error) {
+	if dim < 2 || len(fs)%dim != 0 {
+		return nil, ErrInvalidCoordinates
+	}
+	cs := make([][]float64, len(fs)/dim)
+	for i := range cs {
+		cs[i] = make([]float64, dim)
+		for j := range cs[i] {
+			v, err := strconv.ParseFloat(fs[i*dim+j], 64)
+			if err != nil {
+				return nil, ErrInvalidCoordinates
+			}
+			cs[i][j] = v
+		}
+	}
+	return cs, nil
+}
+
+// parseCoordinates parses a GML 2 coordinates element, with the separators
+// of its attributes
+func parseCoordinates(n *element, dim int) ([][]float64, error) {
+	cs, ts, decimal := ",", " ", "."
+	if s, ok := n.attrs["cs"]; ok {
+		cs = s
+	}
+	if s, ok := n.attrs["ts"]; ok {
+		ts = s
+	}
+	if s, ok := n.attrs["decimal"]; ok {
+		decimal = s
+	}
+	text := n.text
+	if strings.TrimSpace(ts) == "" {
+		// whitespace separating tuples may be any whitespace
+		text = strings.Join(strings.Fields(text), " ")
+		ts = " "
+	}
+	var coords [][]float64
+	for _, tuple := range strings.Split(strings.TrimSpace(text), ts) {
+		if tuple == "" {
+			continue
+		}
+		fs := strings.Split(tuple, cs)
+		for i := range fs {
+			fs[i] = strings.TrimSpace(fs[i])
+			if decimal != "." {
+				fs[i] = strings.Replace(fs[i], decimal, ".", -1)
+			}
+		}
+		d := dim
+		if d == 0 {
+			d = len(fs)
+		}
+		c, err := parseCoords(fs, d)
+		if err != nil {
+			return nil, err
+		}
+		if len(c) != 1 {
+			return nil, ErrInvalidCoordinates
+		}
+		coords = append(coords, c[0])
+	}
+	if len(coords) > 0 {
+		for _, c := range coords[1:] {
+			if len(c) != len(coords[0]) {
+				return nil, ErrInvalidCoordinates
+			}
+		}
+	}
+	return coords, nil
+}
+
+// ringCoords decodes the coordinates of the ring in the boundary element
+func ringCoords(n *element, dim int) ([][]float64, error) {
+	dim, err := n.dim(dim)
+	if err != nil {
+		return nil, err
+	}
+	ring := n.child("LinearRing")
+	if ring == nil {
+		return nil, ErrUnsupportedElement{Name: n.name}
+	}
+	return ring.coords(dim)
+}
+
+// members returns the geometry elements of the member elements of the
+// element, with the srsDimension of the element
+func (n *element) members(dim int, member, members string) ([]*element, int, error) {
+	dim, err := n.dim(dim)
+	if err != nil {
+		return nil, 0, err
+	}
+	var geos []*element
+	for _, c := range n.children {
+		switch c.name {
+		case member:
+			if len(c.children) != 1 {
+				return nil, 0, ErrUnsupportedElement{Name: c.name}
+			}
+			geos = append(geos, c.children[0])
+		case members:
+			geos = append(geos, c.children...)
+		}
+	}
+	return geos, dim, nil
+}
+
+// decodeGeometry decodes the geometry element. dim is the srsDimension of
+// the elements containing it, or 0 if there is none.
+func decodeGeometry(n *element, dim int) (geom.Geometry, error) {
+	switch n.name {
+	case "Point":
+		cs, err := n.coords(dim)
+		if err != nil {
+			return nil, err
+		}
+		if len(cs) != 1 {
+			return nil, ErrInvalidCoordinates
+		}
+		return newPoint(cs[0])
+
+	case "LineString", "LinearRing":
+		cs, err := n.coords(dim)
+		if err != nil {
+			return nil, err
+		}
+		return newLineString(cs)
+
+	case "Polygon":
+		dim, err := n.dim(dim)
+		if err != nil {
+			return nil, err
+		}
+		var rings [][][]float64
+		for _, c := range n.children {
+			switch c.name {
+			case "exterior", "outerBoundaryIs":
+				if len(rings) > 0 {
+					return nil, ErrUnsupportedElement{Name: c.name}
+				}
+			case "interior", "innerBoundaryIs":
+				if len(rings) == 0 {
+					return nil, ErrUnsupportedElement{Name: c.name}
+				}
+			default:
+				continue
+			}
+			r, err := ringCoords(c, dim)
+			if err != nil {
+				return nil, err
+			}
+			rings = append(rings, r)
+		}
+		return newPolygon(rings)
+
+	case "MultiPoint":
+		geos, dim, err := n.members(dim, "pointMember", "pointMembers")
+		if err != nil {
+			return nil, err
+		}
+		var mp geom.MultiPoint
+		var mpz geom.MultiPointZ
+		for _, c := range geos {
+			g, err := decodeGeometry(c, dim)
+			if err != nil {
+				return nil, err
+			}
+			switch p := g.(type) {
+			case geom.Point:
+				if len(mpz) > 0 {
+					return nil, ErrInvalidMember
+				}
+				mp = append(mp, p)
+			case geom.PointZ:
+				if len(mp) > 0 {
+					return nil, ErrInvalidMember
+				}
+				mpz = append(mpz, p)
+			default:
+				return nil, ErrInvalidMember
+			}
+		}
+		if len(mpz) > 0 {
+			return mpz, nil
+		}
+		return mp, nil
+
+	case "MultiCurve", "MultiLineString":
+		geos, dim, err := n.members(dim, n.memberName(), n.memberName()+"s")
+		if err != nil {
+			return nil, err
+		}
+		var ml geom.MultiLineString
+		var mlz geom.MultiLineStringZ
+		for _, c := range geos {
+			g, err := decodeGeometry(c, dim)
+			if err != nil {
+				return nil, err
+			}
+			switch l := g.(type) {
+			case geom.LineString:
+				if len(mlz) > 0 {
+					return nil, ErrInvalidMember
+				}
+				ml = append(ml, l)
+			case geom.LineStringZ:
+				if len(ml) > 0 {
+					return nil, ErrInvalidMember
+				}
+				mlz = append(mlz, l)
+			default:
+				return nil, ErrInvalidMember
+			}
+		}
+		if len(mlz) > 0 {
+			return mlz, nil
+		}
+		return ml, nil
+
+	case "MultiSurface", "MultiPolygon":
+		geos, dim, err := n.members(dim, n.memberName(), n.memberName()+"s")
+		if err != nil {
+			return nil, err
+		}
+		var mp geom.MultiPolygon
+		var mpz geom.MultiPolygonZ
+		for _, c := range geos {
+			g, err := decodeGeometry(c, dim)
+			if err != nil {
+				return nil, err
+			}
+			switch p := g.(type) {
+			case geom.Polygon:
+				if len(mpz) > 0 {
+					return nil, ErrInvalidMember
+				}
+				mp = append(mp, p)
+			case geom.PolygonZ:
+				if len(mp) > 0 {
+					return nil, ErrInvalidMember
+				}
+				mpz = append(mpz, p)
+			default:
+				return nil, ErrInvalidMember
+			}
+		}
+		if len(mpz) > 0 {
+			return mpz, nil
+		}
+		return mp, nil
+
+	case "MultiGeometry":
+		geos, dim, err := n.members(dim, "geometryMember", "geometryMembers")
+		if err != nil {
+			return nil, err
+		}
+		col := geom.Collection{}
+		for _, c := range geos {
+			g, err := decodeGeometry(c, dim)
+			if err != nil {
+				return nil, err
+			}
+			col = append(col, g)
+		}
+		return col, nil
+
+	case "Envelope":
+		dim, err := n.dim(dim)
+		if err != nil {
+			return nil, err
+		}
+		lower, upper := n.child("lowerCorner"), n.child("upperCorner")
+		if lower == nil || upper == nil {
+			return nil, ErrUnsupportedElement{Name: n.name}
+		}
+		var corners [][]float64
+		for _, c := range []*element{lower, upper} {
+			cdim, err := c.dim(dim)
+			if err != nil {
+				return nil, err
+			}
+			fs := strings.Fields(c.text)
+			if cdim == 0 {
+				cdim = len(fs)
+			}
+			cs, err := parseCoords(fs, cdim)
+			if err != nil {
+				return nil, err
+			}
+			if len(cs) != 1 {
+				return nil, ErrInvalidCoordinates
+			}
+			corners = append(corners, cs[0])
+		}
+		return newExtent(corners)
+
+	case "Box":
+		cs, err := n.coords(dim)
+		if err != nil {
+			return nil, err
+		}
+		return newExtent(cs)
+
+	default:
+		return nil, ErrUnsupportedElement{Name: n.name}
+	}
+}
+
+// memberName returns the name of the member elements of the multi geometry
+// element, which are of GML 2 for the elements of GML 2
+func (n *element) memberName() string {
+	switch n.name {
+	case "MultiLineString":
+		return "lineStringMember"
+	case "MultiPolygon":
+		return "polygonMember"
+	case "MultiCurve":
+		return "curveMember"
+	default:
+		return "surfaceMember"
+	}
+}
+
+func newPoint(c []float64) (geom.Geometry, error) {
+	switch len(c) {
+	case 2:
+		return geom.Point{c[0], c[1]}, nil
+	case 3:
+		return geom.PointZ{c[0], c[1], c[2]}, nil
+	default:
+		return nil, ErrInvalidCoordinates
+	}
+}
+
+func newLineString(cs [][]float64) (geom.Geometry, error) {
+	if len(cs) == 0 {
+		return geom.LineString{}, nil
+	}
+	for _, c := range cs[1:] {
+		if len(c) != len(cs[0]) {
+			return nil, ErrInvalidCoordinates
+		}
+	}
+	switch len(cs[0]) {
+	case 2:
+		ls := make(geom.LineString, len(cs))
+		for i, c := range cs {
+			ls[i] = [2]float64{c[0], c[1]}
+		}
+		return ls, nil
+	case 3:
+		ls := make(geom.LineStringZ, len(cs))
+		for i, c := range cs {
+			ls[i] = [3]float64{c[0], c[1], c[2]}
+		}
+		return ls, nil
+	default:
+		return nil, ErrInvalidCoordinates
+	}
+}
+
+// newPolygon returns a Polygon, or a PolygonZ if the rings are of three
+// dimensions. The rings must all be of the same dimension.
+func newPolygon(rings [][][]float64) (geom.Geometry, error) {
+	var ply geom.Polygon
+	var plyz geom.PolygonZ
+	for _, r := range rings {
+		g, err := newLineString(r)
+		if err != nil {
+			return nil, err
+		}
+		switch ls := g.(type) {
+		case geom.LineString:
+			if len(plyz) > 0 {
+				return nil, ErrInvalidCoordinates
+			}
+			ply = append(ply, ls)
+		case geom.LineStringZ:
+			if len(ply) > 0 {
+				return nil, ErrInvalidCoordinates
+			}
+			plyz = append(plyz, ls)
+		}
+	}
+	if len(plyz) > 0 {
+		return plyz, nil
+	}
+	if ply == nil {
+		ply = geom.Polygon{}
+	}
+	return ply, nil
+}
+
+// newExtent returns the Extent of the corners, which must be of at least
+// two dimensions; the other ordinates are dropped
+func newExtent(corners [][]float64) (geom.Geometry, error) {
+	if len(corners) != 2 {
+		return nil, ErrInvalidCoordinates
+	}
+	return geom.Extent{corners[0][0], corners[0][1], corners[1][0], corners[1][1]}, nil
+}
@@ -0,0 +1,322 @@
+package gml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strconv"
+
+	"github.com/go-spatial/geom"
+)
+
+// Encoder writes geometries as GML.
+type Encoder struct {
+	w        io.Writer
+	srsName  string
+	idPrefix string
+	ids      int
+}
+
+// NewEncoder returns an Encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// SetSRSName sets the srsName attribute of the geometries, such as
+// "urn:ogc:def:crs:EPSG::4326". It is not written if it is empty.
+func (enc *Encoder) SetSRSName(name string) { enc.srsName = name }
+
+// SetIDPrefix sets the prefix of the gml:id attributes of the geometries,
+// which GML 3.2 requires. Each geometry and member is given the prefix
+// followed by a number, unique for the Encoder. No ids are written if the
+// prefix is empty.
+func (enc *Encoder) SetIDPrefix(prefix string) { enc.idPrefix = prefix }
+
+// Encode writes the GML of the geometry, with the gml namespace declared on
+// its element. Geometries with z values are written with a srsDimension of
+// 3; measures are left out, as GML has no place for them.
+func (enc *Encoder) Encode(g geom.Geometry) error {
+	e := elementWriter{enc: enc, top: true}
+	if err := e.geometry(g); err != nil {
+		return err
+	}
+	_, err := enc.w.Write(e.buf.Bytes())
+	return err
+}
+
+// elementWriter writes the elements of a geometry
+type elementWriter struct {
+	enc *Encoder
+	buf bytes.Buffer
+	// top is whether the next element is the geometry's
+	top bool
+}
+
+// start writes the start of an element, with the attributes of a geometry
+// if it is one
+func (e *elementWriter) start(name string, isGeometry bool) {
+	e.buf.WriteString("<gml:")
+	e.buf.WriteString(name)
+	if e.top {
+		e.attr("xmlns:gml", Namespace)
+		if e.enc.srsName != "" {
+			e.attr("srsName", e.enc.srsName)
+		}
+		e.top = false
+	}
+	if isGeometry && e.enc.idPrefix != "" {
+		e.enc.ids++
+		e.attr("gml:id", e.enc.idPrefix+strconv.Itoa(e.enc.ids))
+	}
+	e.buf.WriteByte('>')
+}
+
+func (e *elementWriter) attr(name, value string) {
+	e.buf.WriteByte(' ')
+	e.buf.WriteString(name)
+	e.buf.WriteString(`="`)
+	xml.EscapeText(&e.buf, []byte(value))
+	e.buf.WriteByte('"')
+}
+
+func (e *elementWriter) end(name string) {
+	e.buf.WriteString("</gml:")
+	e.buf.WriteString(name)
+	e.buf.WriteByte('>')
+}
+
+// coords writes a pos or posList element of the coordinates
+func (e *elementWriter) coords(name string, dim int, cs ...[]float64) {
+	e.buf.WriteString("<gml:")
+	e.buf.WriteString(name)
+	if dim == 3 {
+		e.buf.WriteString(` srsDimension="3"`)
+	}
+	e.buf.WriteByte('>')
+	for i, c := range cs {
+		for j, v := range c {
+			if i > 0 || j > 0 {
+				e.buf.WriteByte(' ')
+			}
+			e.buf.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+		}
+	}
+	e.end(name)
+}
+
+func (e *elementWriter) point(dim int, c []float64) {
+	e.start("Point", true)
+	e.coords("pos", dim, c)
+	e.end("Point")
+}
+
+func (e *elementWriter) lineString(dim int, cs [][]float64) {
+	e.start("LineString", true)
+	e.coords("posList", dim, cs...)
+	e.end("LineString")
+}
+
+func (e *elementWriter) polygon(dim int, rings [][][]float64) {
+	e.start("Polygon", true)
+	for i, r := range rings {
+		boundary := "exterior"
+		if i > 0 {
+			boundary = "interior"
+		}
+		e.start(boundary, false)
+		e.start("LinearRing", false)
+		if len(r) > 0 && !float64sEqual(r[0], r[len(r)-1]) {
+			r = append(r[:len(r):len(r)], r[0])
+		}
+		e.coords("posList", dim, r...)
+		e.end("LinearRing")
+		e.end(boundary)
+	}
+	e.end("Polygon")
+}
+
+func float64sEqual(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// members writes the elements of a multi geometry, each in a member
+// element
+func (e *elementWriter) members(name, member string, n int, fn func(i int)) {
+	e.start(name, true)
+	for i := 0; i < n; i++ {
+		e.start(member, false)
+		fn(i)
+		e.end(member)
+	}
+	e.end(name)
+}
+
+func (e *elementWriter) geometry(g geom.Geometry) error {
+	switch gg := g.(type) {
+	case geom.Extent:
+		e.start("Envelope", false)
+		e.coords("lowerCorner", 2, gg[:2])
+		e.coords("upperCorner", 2, gg[2:])
+		e.end("Envelope")
+	case *geom.Extent:
+		if gg == nil {
+			return geom.ErrUnknownGeometry{Geom: g}
+		}
+		return e.geometry(*gg)
+
+	case geom.Pointer:
+		xy := gg.XY()
+		e.point(2, xy[:])
+	case geom.MultiPointer:
+		pts := coords2(gg.Points())
+		e.members("MultiPoint", "pointMember", len(pts), func(i int) { e.point(2, pts[i]) })
+	case geom.LineStringer:
+		e.lineString(2, coords2(gg.Vertices()))
+	case geom.MultiLineStringer:
+		lns := gg.LineStrings()
+		e.members("MultiCurve", "curveMember", len(lns), func(i int) { e.lineString(2, coords2(lns[i])) })
+	case geom.Polygoner:
+		e.polygon(2, lines2(gg.LinearRings()))
+	case geom.MultiPolygoner:
+		plys := gg.Polygons()
+		e.members("MultiSurface", "surfaceMember", len(plys), func(i int) { e.polygon(2, lines2(plys[i])) })
+
+	case geom.PointZ:
+		e.point(3, gg[:])
+	case geom.MultiPointZ:
+		pts := coords3(gg)
+		e.members("MultiPoint", "pointMember", len(pts), func(i int) { e.point(3, pts[i]) })
+	case geom.LineStringZ:
+		e.lineString(3, coords3(gg))
+	case geom.MultiLineStringZ:
+		e.members("MultiCurve", "curveMember", len(gg), func(i int) { e.lineString(3, coords3(gg[i])) })
+	case geom.PolygonZ:
+		e.polygon(3, lines3(gg))
+	case geom.MultiPolygonZ:
+		e.members("MultiSurface", "surfaceMember", len(gg), func(i int) { e.polygon(3, lines3(gg[i])) })
+
+	// GML has no measures, so they are left out
+	case geom.PointM:
+		e.point(2, gg[:2])
+	case geom.MultiPointM:
+		pts := coordsM(gg)
+		e.members("MultiPoint", "pointMember", len(pts), func(i int) { e.point(2, pts[i]) })
+	case geom.LineStringM:
+		e.lineString(2, coordsM(gg))
+	case geom.MultiLineStringM:
+		e.members("MultiCurve", "curveMember", len(gg), func(i int) { e.lineString(2, coordsM(gg[i])) })
+	case geom.PolygonM:
+		e.polygon(2, linesM(gg))
+	case geom.MultiPolygonM:
+		e.members("MultiSurface", "surfaceMember", len(gg), func(i int) { e.polygon(2, linesM(gg[i])) })
+	case geom.PointZM:
+		e.point(3, gg[:3])
+	case geom.MultiPointZM:
+		pts := coordsZM(gg)
+		e.members("MultiPoint", "pointMember", len(pts), func(i int) { e.point(3, pts[i]) })
+	case geom.LineStringZM:
+		e.lineString(3, coordsZM(gg))
+	case geom.MultiLineStringZM:
+		e.members("MultiCurve", "curveMember", len(gg), func(i int) { e.lineString(3, coordsZM(gg[i])) })
+	case geom.PolygonZM:
+		e.polygon(3, linesZM(gg))
+	case geom.MultiPolygonZM:
+		e.members("MultiSurface", "surfaceMember", len(gg), func(i int) { e.polygon(3, linesZM(gg[i])) })
+
+	case geom.Collectioner:
+		geos := gg.Geometries()
+		e.start("MultiGeometry", true)
+		for _, cg := range geos {
+			e.start("geometryMember", false)
+			if err := e.geometry(cg); err != nil {
+				return err
+			}
+			e.end("geometryMember")
+		}
+		e.end("MultiGeometry")
+
+	default:
+		// pointers to the Z, M and ZM geometries
+		if v := reflect.ValueOf(g); v.Kind() == reflect.Ptr && !v.IsNil() {
+			return e.geometry(v.Elem().Interface())
+		}
+		return geom.ErrUnknownGeometry{Geom: g}
+	}
+	return nil
+}
+
+func coords2(pts [][2]float64) [][]float64 {
+	c := make([][]float64, len(pts))
+	for i := range pts {
+		c[i] = pts[i][:]
+	}
+	return c
+}
+
+func coords3(pts [][3]float64) [][]float64 {
+	c := make([][]float64, len(pts))
+	for i := range pts {
+		c[i] = pts[i][:]
+	}
+	return c
+}
+
+func lines2(lines [][][2]float64) [][][]float64 {
+	c := make([][][]float64, len(lines))
+	for i := range lines {
+		c[i] = coords2(lines[i])
+	}
+	return c
+}
+
+func lines3(lines [][][3]float64) [][][]float64 {
+	c := make([][][]float64, len(lines))
+	for i := range lines {
+		c[i] = coords3(lines[i])
+	}
+	return c
+}
+
+// coordsM returns the x and y of the points, leaving out their measures
+func coordsM(pts [][3]float64) [][]float64 {
+	c := make([][]float64, len(pts))
+	for i := range pts {
+		c[i] = pts[i][:2]
+	}
+	return c
+}
+
+// coordsZM returns the x, y and z of the points, leaving out their
+// measures
+func coordsZM(pts [][4]float64) [][]float64 {
+	c := make([][]float64, len(pts))
+	for i := range pts {
+		c[i] = pts[i][:3]
+	}
+	return c
+}
+
+func linesM(lines [][][3]float64) [][][]float64 {
+	c := make([][][]float64, len(lines))
+	for i := range lines {
+		c[i] = coordsM(lines[i])
+	}
+	return c
+}
+
+func linesZM(lines [][][4]float64) [][][]float64 {
+	c := make([][][]float64, len(lines))
+	for i := range lines {
+		c[i] = coordsZM(lines[i])
+	}
+	return c
+}
@@ -0,0 +1,86 @@
+// Package gml is for encoding and decoding geometries as Geography Markup
+// Language (GML) 3.2, the XML encoding of geometries used by OGC services
+// such as WFS. Specification at https://www.ogc.org/standards/gml
+//
+// Geometries are encoded as Points, LineStrings, Polygons, MultiPoints,
+// MultiCurves, MultiSurfaces and MultiGeometries, with pos and posList
+// elements, and Extents as Envelopes. Geometries with z values are encoded
+// with a srsDimension of 3. The rings of polygons are closed when encoded.
+//
+// Decoding is lenient, so the GML of older services can be read: the
+// namespace of the elements is ignored, and the MultiLineString and
+// MultiPolygon elements, the outerBoundaryIs and innerBoundaryIs elements
+// and the coordinates element of GML 2, and lists of pos elements, are
+// accepted. Coordinates have the srsDimension of their element or the
+// closest element containing them that has one; without one, pos and
+// coordinates elements have as many ordinates as are given, and posList
+// elements have two. Rings are decoded as they are, closed.
+package gml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-spatial/geom"
+)
+
+// Namespace is the namespace of GML 3.2.
+const Namespace = "http://www.opengis.net/gml/3.2"
+
+var (
+	// ErrNoGeometry is returned when decoding XML without any elements.
+	ErrNoGeometry = errors.New("gml: no geometry")
+	// ErrInvalidCoordinates is returned when decoding coordinates that are
+	// not numbers, or are not of the dimension of their element.
+	ErrInvalidCoordinates = errors.New("gml: invalid coordinates")
+	// ErrInvalidMember is returned when decoding a member of a geometry that
+	// is not of the type, or of the dimension, of the other members.
+	ErrInvalidMember = errors.New("gml: invalid member")
+)
+
+// ErrUnsupportedElement is returned when decoding an element that is not a
+// supported geometry, or is missing an element it needs.
+type ErrUnsupportedElement struct {
+	Name string
+}
+
+func (e ErrUnsupportedElement) Error() string {
+	return fmt.Sprintf("gml: unsupported element %v", e.Name)
+}
+
+// Encode writes the GML of the geometry.
+func Encode(w io.Writer, g geom.Geometry) error {
+	return NewEncoder(w).Encode(g)
+}
+
+// EncodeBytes returns the GML of the geometry.
+func EncodeBytes(g geom.Geometry) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, g); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeString returns the GML of the geometry.
+func EncodeString(g geom.Geometry) (string, error) {
+	b, err := EncodeBytes(g)
+	return string(b), err
+}
+
+// Decode reads the first geometry of the GML.
+func Decode(r io.Reader) (geom.Geometry, error) {
+	return NewDecoder(r).Decode()
+}
+
+// DecodeBytes decodes the first geometry of the GML.
+func DecodeBytes(b []byte) (geom.Geometry, error) {
+	return Decode(bytes.NewReader(b))
+}
+
+// DecodeString decodes the first geometry of the GML.
+func DecodeString(s string) (geom.Geometry, error) {
+	return DecodeBytes([]byte(s))
+}
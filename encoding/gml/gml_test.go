@@ -0,0 +1,321 @@
+package gml_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/gml"
+)
+
+const ns = `xmlns:gml="http://www.opengis.net/gml/3.2"`
+
+func TestEncode(t *testing.T) {
+	type tcase struct {
+		geom     geom.Geometry
+		srsName  string
+		idPrefix string
+		exp      string
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var buff bytes.Buffer
+			enc := gml.NewEncoder(&buff)
+			enc.SetSRSName(tc.srsName)
+			enc.SetIDPrefix(tc.idPrefix)
+			err := enc.Encode(tc.geom)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if got := buff.String(); got != tc.exp {
+				t.Errorf("encode, expected\n%v\ngot\n%v", tc.exp, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			geom: geom.Point{1, 2.5},
+			exp:  `<gml:Point ` + ns + `><gml:pos>1 2.5</gml:pos></gml:Point>`,
+		},
+		"point srs name and id": {
+			geom:     geom.Point{1, 2},
+			srsName:  "urn:ogc:def:crs:EPSG::3857",
+			idPrefix: "g",
+			exp:      `<gml:Point ` + ns + ` srsName="urn:ogc:def:crs:EPSG::3857" gml:id="g1"><gml:pos>1 2</gml:pos></gml:Point>`,
+		},
+		"point z": {
+			geom: geom.PointZ{1, 2, 3},
+			exp:  `<gml:Point ` + ns + `><gml:pos srsDimension="3">1 2 3</gml:pos></gml:Point>`,
+		},
+		"linestring": {
+			geom: geom.LineString{{1, 2}, {3, 4}},
+			exp:  `<gml:LineString ` + ns + `><gml:posList>1 2 3 4</gml:posList></gml:LineString>`,
+		},
+		"polygon closes rings": {
+			geom: geom.Polygon{{{0, 0}, {4, 0}, {4, 4}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+			exp: `<gml:Polygon ` + ns + `>` +
+				`<gml:exterior><gml:LinearRing><gml:posList>0 0 4 0 4 4 0 0</gml:posList></gml:LinearRing></gml:exterior>` +
+				`<gml:interior><gml:LinearRing><gml:posList>1 1 2 1 2 2 1 1</gml:posList></gml:LinearRing></gml:interior>` +
+				`</gml:Polygon>`,
+		},
+		"multipoint ids": {
+			geom:     geom.MultiPoint{{1, 2}, {3, 4}},
+			idPrefix: "mp.",
+			exp: `<gml:MultiPoint ` + ns + ` gml:id="mp.1">` +
+				`<gml:pointMember><gml:Point gml:id="mp.2"><gml:pos>1 2</gml:pos></gml:Point></gml:pointMember>` +
+				`<gml:pointMember><gml:Point gml:id="mp.3"><gml:pos>3 4</gml:pos></gml:Point></gml:pointMember>` +
+				`</gml:MultiPoint>`,
+		},
+		"multilinestring z": {
+			geom: geom.MultiLineStringZ{{{1, 2, 3}, {4, 5, 6}}},
+			exp: `<gml:MultiCurve ` + ns + `>` +
+				`<gml:curveMember><gml:LineString><gml:posList srsDimension="3">1 2 3 4 5 6</gml:posList></gml:LineString></gml:curveMember>` +
+				`</gml:MultiCurve>`,
+		},
+		"multipolygon": {
+			geom: geom.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}},
+			exp: `<gml:MultiSurface ` + ns + `>` +
+				`<gml:surfaceMember><gml:Polygon><gml:exterior><gml:LinearRing><gml:posList>0 0 1 0 1 1 0 0</gml:posList></gml:LinearRing></gml:exterior></gml:Polygon></gml:surfaceMember>` +
+				`</gml:MultiSurface>`,
+		},
+		"collection": {
+			geom: geom.Collection{geom.Point{1, 2}, geom.LineString{{1, 2}, {3, 4}}},
+			exp: `<gml:MultiGeometry ` + ns + `>` +
+				`<gml:geometryMember><gml:Point><gml:pos>1 2</gml:pos></gml:Point></gml:geometryMember>` +
+				`<gml:geometryMember><gml:LineString><gml:posList>1 2 3 4</gml:posList></gml:LineString></gml:geometryMember>` +
+				`</gml:MultiGeometry>`,
+		},
+		"point zm pointer": {
+			geom: &geom.PointZM{1, 2, 3, 4},
+			exp:  `<gml:Point ` + ns + `><gml:pos srsDimension="3">1 2 3</gml:pos></gml:Point>`,
+		},
+		"linestring m": {
+			geom: geom.LineStringM{{1, 2, 3}, {4, 5, 6}},
+			exp:  `<gml:LineString ` + ns + `><gml:posList>1 2 4 5</gml:posList></gml:LineString>`,
+		},
+		"polygon zm": {
+			geom: geom.PolygonZM{{{0, 0, 1, 9}, {1, 0, 1, 9}, {1, 1, 1, 9}}},
+			exp: `<gml:Polygon ` + ns + `>` +
+				`<gml:exterior><gml:LinearRing><gml:posList srsDimension="3">0 0 1 1 0 1 1 1 1 0 0 1</gml:posList></gml:LinearRing></gml:exterior>` +
+				`</gml:Polygon>`,
+		},
+		"extent": {
+			geom: geom.Extent{1, 2, 3, 4},
+			exp:  `<gml:Envelope ` + ns + `><gml:lowerCorner>1 2</gml:lowerCorner><gml:upperCorner>3 4</gml:upperCorner></gml:Envelope>`,
+		},
+		"srs name escaped": {
+			geom:    geom.Point{1, 2},
+			srsName: `a"b`,
+			exp:     `<gml:Point ` + ns + ` srsName="a&#34;b"><gml:pos>1 2</gml:pos></gml:Point>`,
+		},
+		"unknown geometry": {
+			geom: nil,
+			err:  geom.ErrUnknownGeometry{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDecode(t *testing.T) {
+	type tcase struct {
+		gml     string
+		exp     geom.Geometry
+		srsName string
+		err     error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			dec := gml.NewDecoder(bytes.NewReader([]byte(tc.gml)))
+			got, err := dec.Decode()
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("decode, expected %v got %v", tc.exp, got)
+			}
+			if got := dec.SRSName(); got != tc.srsName {
+				t.Errorf("srs name, expected %v got %v", tc.srsName, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			gml:     `<gml:Point xmlns:gml="http://www.opengis.net/gml/3.2" gml:id="p1" srsName="EPSG:4326"><gml:pos>1 2</gml:pos></gml:Point>`,
+			exp:     geom.Point{1, 2},
+			srsName: "EPSG:4326",
+		},
+		"point z from pos": {
+			gml: `<Point><pos>1 2 3</pos></Point>`,
+			exp: geom.PointZ{1, 2, 3},
+		},
+		"point in feature": {
+			gml: `<?xml version="1.0"?>
+<gml:Point xmlns:gml="http://www.opengis.net/gml">
+	<gml:pos> 1.5
+	 -2 </gml:pos>
+</gml:Point>`,
+			exp: geom.Point{1.5, -2},
+		},
+		"linestring poslist": {
+			gml: `<LineString><posList>1 2 3 4</posList></LineString>`,
+			exp: geom.LineString{{1, 2}, {3, 4}},
+		},
+		"linestring poslist srs dimension": {
+			gml: `<LineString srsDimension="3"><posList>1 2 3 4 5 6</posList></LineString>`,
+			exp: geom.LineStringZ{{1, 2, 3}, {4, 5, 6}},
+		},
+		"linestring pos": {
+			gml: `<LineString><pos>1 2</pos><pos>3 4</pos></LineString>`,
+			exp: geom.LineString{{1, 2}, {3, 4}},
+		},
+		"linestring gml 2 coordinates": {
+			gml: `<LineString><coordinates>1,2 3,4</coordinates></LineString>`,
+			exp: geom.LineString{{1, 2}, {3, 4}},
+		},
+		"linestring coordinates separators": {
+			gml: `<LineString><coordinates cs=" " ts=";" decimal=",">1,5 2;3 4,25</coordinates></LineString>`,
+			exp: geom.LineString{{1.5, 2}, {3, 4.25}},
+		},
+		"polygon": {
+			gml: `<Polygon>
+	<exterior><LinearRing><posList>0 0 4 0 4 4 0 0</posList></LinearRing></exterior>
+	<interior><LinearRing><posList>1 1 2 1 2 2 1 1</posList></LinearRing></interior>
+</Polygon>`,
+			exp: geom.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 0}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+		},
+		"polygon gml 2": {
+			gml: `<Polygon><outerBoundaryIs><LinearRing><coordinates>0,0 1,0 1,1 0,0</coordinates></LinearRing></outerBoundaryIs></Polygon>`,
+			exp: geom.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		},
+		"polygon z": {
+			gml: `<Polygon srsDimension="3"><exterior><LinearRing><posList>0 0 1 1 0 1 1 1 1 0 0 1</posList></LinearRing></exterior></Polygon>`,
+			exp: geom.PolygonZ{{{0, 0, 1}, {1, 0, 1}, {1, 1, 1}, {0, 0, 1}}},
+		},
+		"multipoint": {
+			gml: `<MultiPoint><pointMember><Point><pos>1 2</pos></Point></pointMember><pointMembers><Point><pos>3 4</pos></Point><Point><pos>5 6</pos></Point></pointMembers></MultiPoint>`,
+			exp: geom.MultiPoint{{1, 2}, {3, 4}, {5, 6}},
+		},
+		"multicurve": {
+			gml: `<MultiCurve><curveMember><LineString><posList>1 2 3 4</posList></LineString></curveMember></MultiCurve>`,
+			exp: geom.MultiLineString{{{1, 2}, {3, 4}}},
+		},
+		"multilinestring gml 2": {
+			gml: `<MultiLineString><lineStringMember><LineString><coordinates>1,2 3,4</coordinates></LineString></lineStringMember></MultiLineString>`,
+			exp: geom.MultiLineString{{{1, 2}, {3, 4}}},
+		},
+		"multisurface srs dimension": {
+			gml: `<MultiSurface srsDimension="3"><surfaceMember><Polygon><exterior><LinearRing><posList>0 0 0 1 0 0 1 1 0 0 0 0</posList></LinearRing></exterior></Polygon></surfaceMember></MultiSurface>`,
+			exp: geom.MultiPolygonZ{{{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 0, 0}}}},
+		},
+		"multipolygon gml 2": {
+			gml: `<MultiPolygon><polygonMember><Polygon><outerBoundaryIs><LinearRing><coordinates>0,0 1,0 1,1 0,0</coordinates></LinearRing></outerBoundaryIs></Polygon></polygonMember></MultiPolygon>`,
+			exp: geom.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}},
+		},
+		"multigeometry": {
+			gml: `<MultiGeometry><geometryMember><Point><pos>1 2</pos></Point></geometryMember><geometryMember><LineString><posList>1 2 3 4</posList></LineString></geometryMember></MultiGeometry>`,
+			exp: geom.Collection{geom.Point{1, 2}, geom.LineString{{1, 2}, {3, 4}}},
+		},
+		"envelope": {
+			gml: `<Envelope><lowerCorner>1 2</lowerCorner><upperCorner>3 4</upperCorner></Envelope>`,
+			exp: geom.Extent{1, 2, 3, 4},
+		},
+		"box": {
+			gml: `<Box><coordinates>1,2 3,4</coordinates></Box>`,
+			exp: geom.Extent{1, 2, 3, 4},
+		},
+		"no geometry": {
+			gml: `<?xml version="1.0"?>`,
+			err: gml.ErrNoGeometry,
+		},
+		"unsupported": {
+			gml: `<Curve/>`,
+			err: gml.ErrUnsupportedElement{Name: "Curve"},
+		},
+		"point without pos": {
+			gml: `<Point/>`,
+			err: gml.ErrUnsupportedElement{Name: "Point"},
+		},
+		"invalid number": {
+			gml: `<Point><pos>1 a</pos></Point>`,
+			err: gml.ErrInvalidCoordinates,
+		},
+		"poslist not of dimension": {
+			gml: `<LineString srsDimension="3"><posList>1 2 3 4</posList></LineString>`,
+			err: gml.ErrInvalidCoordinates,
+		},
+		"mixed dimension members": {
+			gml: `<MultiPoint><pointMember><Point><pos>1 2</pos></Point></pointMember><pointMember><Point><pos>1 2 3</pos></Point></pointMember></MultiPoint>`,
+			err: gml.ErrInvalidMember,
+		},
+		"wrong member": {
+			gml: `<MultiCurve><curveMember><Point><pos>1 2</pos></Point></curveMember></MultiCurve>`,
+			err: gml.ErrInvalidMember,
+		},
+		"unterminated": {
+			gml: `<Point><pos>1 2</pos>`,
+			err: &xml.SyntaxError{Msg: "unexpected EOF", Line: 1},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	geoms := map[string]geom.Geometry{
+		"point":           geom.Point{1.25, -2},
+		"multipoint z":    geom.MultiPointZ{{1, 2, 3}, {4, 5, 6}},
+		"linestring z":    geom.LineStringZ{{1, 2, 3}, {4, 5, 6}},
+		"multilinestring": geom.MultiLineString{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}}},
+		"polygon":         geom.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 0}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+		"multipolygon z":  geom.MultiPolygonZ{{{{0, 0, 1}, {1, 0, 1}, {1, 1, 1}, {0, 0, 1}}}},
+		"collection":      geom.Collection{geom.PointZ{1, 2, 3}, geom.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}},
+	}
+
+	for name, g := range geoms {
+		g := g
+		t.Run(name, func(t *testing.T) {
+			b, err := gml.EncodeBytes(g)
+			if err != nil {
+				t.Fatalf("encode error, expected nil got %v", err)
+			}
+			got, err := gml.DecodeBytes(b)
+			if err != nil {
+				t.Fatalf("decode error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(got, g) {
+				t.Errorf("round trip, expected %v got %v", g, got)
+			}
+		})
+	}
+}
+
+func TestDecodeMany(t *testing.T) {
+	dec := gml.NewDecoder(bytes.NewReader([]byte(`<Point><pos>1 2</pos></Point>
+<LineString><posList>1 2 3 4</posList></LineString>`)))
+	exp := []geom.Geometry{geom.Point{1, 2}, geom.LineString{{1, 2}, {3, 4}}}
+	for i, e := range exp {
+		got, err := dec.Decode()
+		if err != nil {
+			t.Fatalf("error %v, expected nil got %v", i, err)
+		}
+		if !reflect.DeepEqual(got, e) {
+			t.Errorf("geometry %v, expected %v got %v", i, e, got)
+		}
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		t.Errorf("error, expected %v got %v", io.EOF, err)
+	}
+}
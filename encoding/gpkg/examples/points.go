@@ -99,4 +99,19 @@ func main() {
 	}
 	h.UpdateGeometryExtent("poi", ext)
 
+	// index the points, the triggers will keep the index up to date
+	if err = h.AddSpatialIndex("poi"); err != nil {
+		log.Println("err:", err)
+		return
+	}
+
+	// read back the points in the western hemisphere
+	west := geom.NewExtent([2]float64{-20037508.34, -20037508.34}, [2]float64{0, 20037508.34})
+	err = h.ReadFeatures("poi", west, func(f *gpkg.Feature) error {
+		fmt.Println(f.ID, f.Properties["name"], f.Geometry)
+		return nil
+	})
+	if err != nil {
+		log.Println("err:", err)
+	}
 }
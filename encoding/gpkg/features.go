@@ -0,0 +1,256 @@
+// +build cgo
+
+package gpkg
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/go-spatial/geom"
+)
+
+// Feature is a row of a features table
+type Feature struct {
+	// ID is the value of the primary key of the row
+	ID int64
+	// SRSID is the srs id stored in the header of the geometry
+	SRSID int32
+	// Geometry is nil if the geometry column is NULL
+	Geometry geom.Geometry
+	// Properties are the values of the other columns, by column name
+	Properties map[string]interface{}
+}
+
+// geometryTypeForName returns the geometry type for the name used in the
+// geometry columns table
+func geometryTypeForName(name string) GeometryType {
+	for gt := Geometry; gt <= GeometryCollection; gt++ {
+		if strings.EqualFold(gt.String(), name) {
+			return gt
+		}
+	}
+	return Geometry
+}
+
+const selectFeatureTablesSQL = `
+	SELECT
+		c.table_name,
+		c.identifier,
+		c.description,
+		g.column_name,
+		g.geometry_type_name,
+		g.srs_id,
+		g.z,
+		g.m
+	FROM
+		gpkg_contents c
+		JOIN gpkg_geometry_columns g ON c.table_name = g.table_name
+	WHERE
+		c.data_type = 'features'
+	`
+
+func scanTableDescription(row interface{ Scan(...interface{}) error }) (*TableDescription, error) {
+	var (
+		td                      TableDescription
+		identifier, description sql.NullString
+		typeName                string
+	)
+	err := row.Scan(
+		&td.Name,
+		&identifier,
+		&description,
+		&td.GeometryField,
+		&typeName,
+		&td.SRS,
+		&td.Z,
+		&td.M,
+	)
+	if err != nil {
+		return nil, err
+	}
+	td.ShortName = identifier.String
+	td.Description = description.String
+	td.GeometryType = geometryTypeForName(typeName)
+	return &td, nil
+}
+
+// FeatureTables returns the descriptions of all the features tables, sorted
+// by name.
+func (h *Handle) FeatureTables() ([]TableDescription, error) {
+	rows, err := h.Query(selectFeatureTablesSQL + ` ORDER BY c.table_name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []TableDescription
+	for rows.Next() {
+		td, err := scanTableDescription(rows)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, *td)
+	}
+	return tables, rows.Err()
+}
+
+// FeatureTable returns the description of the given features table.
+func (h *Handle) FeatureTable(tablename string) (*TableDescription, error) {
+	td, err := scanTableDescription(
+		h.QueryRow(selectFeatureTablesSQL+` AND c.table_name = ?`, tablename),
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("unknown features table %v", tablename)
+	}
+	return td, err
+}
+
+// SRS returns the spatial reference system with the given id from the
+// spatial ref table.
+func (h *Handle) SRS(id int32) (*SpatialReferenceSystem, error) {
+	const selectSQL = `
+		SELECT
+			srs_name,
+			srs_id,
+			organization,
+			organization_coordsys_id,
+			definition,
+			description
+		FROM
+			gpkg_spatial_ref_sys
+		WHERE
+			srs_id = ?
+		`
+	var (
+		srs         SpatialReferenceSystem
+		description sql.NullString
+	)
+	err := h.QueryRow(selectSQL, id).Scan(
+		&srs.Name,
+		&srs.ID,
+		&srs.Organization,
+		&srs.OrganizationCoordsysID,
+		&srs.Definition,
+		&description,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("unknown srs: %v", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	srs.Description = description.String
+	return &srs, nil
+}
+
+// envelopesOverlap reports if the extents overlap or touch
+func envelopesOverlap(e1, e2 *geom.Extent) bool {
+	return e1.MinX() <= e2.MaxX() && e2.MinX() <= e1.MaxX() &&
+		e1.MinY() <= e2.MaxY() && e2.MinY() <= e1.MaxY()
+}
+
+// ReadFeatures calls fn with each of the features of the given table, in
+// order of their primary key, stopping at the first error. If extent is not
+// nil only the features whose envelope overlaps or touches it are read, using
+// the spatial index if the table has one; features with NULL or empty
+// geometries are then skipped.
+func (h *Handle) ReadFeatures(tablename string, extent *geom.Extent, fn func(*Feature) error) error {
+	const (
+		selectSQL = `SELECT * FROM "%v" ORDER BY "%v"`
+		// the rtree stores float32s rounded out, so the envelopes are checked
+		// again once the geometries are decoded
+		selectIndexSQL = `
+		SELECT t.* FROM "%v" t
+			JOIN "%v" r ON t."%v" = r.id
+		WHERE
+			r.minx <= ? AND r.maxx >= ? AND
+			r.miny <= ? AND r.maxy >= ?
+		ORDER BY t."%[3]v"
+		`
+	)
+
+	column, err := h.geometryColumn(tablename)
+	if err != nil {
+		return err
+	}
+	id, err := h.primaryKey(tablename)
+	if err != nil {
+		return err
+	}
+
+	var rows *sql.Rows
+	indexed := false
+	if extent != nil {
+		if indexed, err = h.HasSpatialIndex(tablename); err != nil {
+			return err
+		}
+	}
+	if indexed {
+		rows, err = h.Query(
+			fmt.Sprintf(selectIndexSQL, tablename, rtreeName(tablename, column), id),
+			extent.MaxX(), extent.MinX(), extent.MaxY(), extent.MinY(),
+		)
+	} else {
+		rows, err = h.Query(fmt.Sprintf(selectSQL, tablename, id))
+	}
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	values := make([]interface{}, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err = rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		feature := Feature{
+			Properties: make(map[string]interface{}, len(columns)-2),
+		}
+		for i, name := range columns {
+			switch name {
+			case id:
+				fid, ok := values[i].(int64)
+				if !ok {
+					return fmt.Errorf("primary key %v of %v is not an integer", id, tablename)
+				}
+				feature.ID = fid
+			case column:
+				if values[i] == nil {
+					continue
+				}
+				var sb StandardBinary
+				if err = sb.Scan(values[i]); err != nil {
+					return err
+				}
+				feature.SRSID = sb.SRSID
+				feature.Geometry = sb.Geometry
+			default:
+				feature.Properties[name] = values[i]
+			}
+		}
+
+		if extent != nil {
+			if feature.Geometry == nil || geom.IsEmpty(feature.Geometry) {
+				continue
+			}
+			ext, err := geom.NewExtentFromGeometry(feature.Geometry)
+			if err != nil || !envelopesOverlap(ext, extent) {
+				continue
+			}
+		}
+		if err = fn(&feature); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
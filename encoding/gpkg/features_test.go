@@ -0,0 +1,162 @@
+// +build cgo
+
+package gpkg
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+// newTestHandle returns a handle to a new file with a poi features table
+// with the given points in it, and a function to remove the file
+func newTestHandle(t *testing.T, pts []geom.Point) (*Handle, func()) {
+	dir, err := ioutil.TempDir("", "gpkg")
+	if err != nil {
+		t.Fatalf("temp dir, expected nil got %v", err)
+	}
+
+	h, err := New(filepath.Join(dir, "test.gpkg"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("new, expected nil got %v", err)
+	}
+	cleanup := func() {
+		h.Close()
+		os.RemoveAll(dir)
+	}
+
+	_, err = h.Exec(fmt.Sprintf(`
+	CREATE TABLE poi (
+		fid INTEGER NOT NULL PRIMARY KEY,
+		name TEXT,
+		geom %v
+	);
+	`, Point))
+	if err != nil {
+		t.Fatalf("create table, expected nil got %v", err)
+	}
+	err = h.AddGeometryTable(TableDescription{
+		Name:          "poi",
+		ShortName:     "points",
+		GeometryField: "geom",
+		GeometryType:  Point,
+		SRS:           4326,
+		Z:             Prohibited,
+		M:             Prohibited,
+	})
+	if err != nil {
+		t.Fatalf("add geometry table, expected nil got %v", err)
+	}
+	for i, pt := range pts {
+		sb, err := NewBinary(4326, pt)
+		if err != nil {
+			t.Fatalf("new binary, expected nil got %v", err)
+		}
+		if _, err = h.Exec(`INSERT INTO poi(name, geom) VALUES(?,?)`, fmt.Sprintf("pt%v", i), sb); err != nil {
+			t.Fatalf("insert, expected nil got %v", err)
+		}
+	}
+	return h, cleanup
+}
+
+func readIDs(t *testing.T, h *Handle, extent *geom.Extent) []int64 {
+	var ids []int64
+	err := h.ReadFeatures("poi", extent, func(f *Feature) error {
+		if f.SRSID != 4326 {
+			t.Errorf("srs id, expected 4326 got %v", f.SRSID)
+		}
+		if f.Properties["name"] != fmt.Sprintf("pt%v", f.ID-1) {
+			t.Errorf("name, expected pt%v got %v", f.ID-1, f.Properties["name"])
+		}
+		ids = append(ids, f.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("read features, expected nil got %v", err)
+	}
+	return ids
+}
+
+func TestReadFeatures(t *testing.T) {
+	h, cleanup := newTestHandle(t, []geom.Point{{0, 0}, {10, 10}, {5, 5}, {-3, 8}})
+	defer cleanup()
+
+	tables, err := h.FeatureTables()
+	if err != nil {
+		t.Fatalf("feature tables, expected nil got %v", err)
+	}
+	expTables := []TableDescription{{
+		Name:          "poi",
+		ShortName:     "points",
+		GeometryField: "geom",
+		GeometryType:  Point,
+		SRS:           4326,
+		Z:             Prohibited,
+		M:             Prohibited,
+	}}
+	if !reflect.DeepEqual(tables, expTables) {
+		t.Errorf("feature tables, expected %v got %v", expTables, tables)
+	}
+
+	srs, err := h.SRS(tables[0].SRS)
+	if err != nil {
+		t.Fatalf("srs, expected nil got %v", err)
+	}
+	if srs.Name != KnownSRS[4326].Name || srs.Organization != OREPSG {
+		t.Errorf("srs, expected %v got %v", KnownSRS[4326], srs)
+	}
+
+	extent := geom.NewExtent([2]float64{0, 0}, [2]float64{5, 9})
+	if ids, exp := readIDs(t, h, nil), []int64{1, 2, 3, 4}; !reflect.DeepEqual(ids, exp) {
+		t.Errorf("all ids, expected %v got %v", exp, ids)
+	}
+	if ids, exp := readIDs(t, h, extent), []int64{1, 3}; !reflect.DeepEqual(ids, exp) {
+		t.Errorf("ids in extent, expected %v got %v", exp, ids)
+	}
+
+	// the same features are found with the spatial index
+	if err = h.AddSpatialIndex("poi"); err != nil {
+		t.Fatalf("add spatial index, expected nil got %v", err)
+	}
+	if ok, err := h.HasSpatialIndex("poi"); err != nil || !ok {
+		t.Fatalf("has spatial index, expected true got %v, %v", ok, err)
+	}
+	if ids, exp := readIDs(t, h, extent), []int64{1, 3}; !reflect.DeepEqual(ids, exp) {
+		t.Errorf("indexed ids in extent, expected %v got %v", exp, ids)
+	}
+
+	// the triggers keep the index up to date
+	sb, err := NewBinary(4326, geom.Point{20, 20})
+	if err != nil {
+		t.Fatalf("new binary, expected nil got %v", err)
+	}
+	if _, err = h.Exec(`UPDATE poi SET geom = ? WHERE fid = 1`, sb); err != nil {
+		t.Fatalf("update, expected nil got %v", err)
+	}
+	if _, err = h.Exec(`DELETE FROM poi WHERE fid = 3`); err != nil {
+		t.Fatalf("delete, expected nil got %v", err)
+	}
+	sb, err = NewBinary(4326, geom.Point{1, 1})
+	if err != nil {
+		t.Fatalf("new binary, expected nil got %v", err)
+	}
+	if _, err = h.Exec(`INSERT INTO poi(fid, name, geom) VALUES(5, 'pt4', ?)`, sb); err != nil {
+		t.Fatalf("insert, expected nil got %v", err)
+	}
+	if ids, exp := readIDs(t, h, extent), []int64{5}; !reflect.DeepEqual(ids, exp) {
+		t.Errorf("updated ids in extent, expected %v got %v", exp, ids)
+	}
+	var count int
+	if err = h.QueryRow(`SELECT Count(*) FROM rtree_poi_geom`).Scan(&count); err != nil {
+		t.Fatalf("count, expected nil got %v", err)
+	}
+	if count != 4 {
+		t.Errorf("rtree rows, expected 4 got %v", count)
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"strings"
 
 	"github.com/go-spatial/geom"
-	_ "github.com/mattn/go-sqlite3"
 )
 
 const (
@@ -195,7 +194,7 @@ func nonZeroFileExists(filename string) bool {
 func Open(filename string) (*Handle, error) {
 	var h = new(Handle)
 
-	db, err := sql.Open(SQLITE3, filename)
+	db, err := sql.Open(Driver, filename)
 	if err != nil {
 		return nil, err
 	}
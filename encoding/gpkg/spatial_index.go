@@ -0,0 +1,277 @@
+// +build cgo
+
+package gpkg
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+
+	"github.com/gdey/errors"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/go-spatial/geom"
+)
+
+const (
+	// Driver is the database driver name used by Open. It is the SQLITE3
+	// driver with the ST_MinX, ST_MaxX, ST_MinY, ST_MaxY and ST_IsEmpty
+	// functions the spatial index triggers need.
+	Driver = "sqlite3_gpkg"
+
+	// ExtensionRTree is the name of the rtree spatial index extension
+	// http://www.geopackage.org/spec/#extension_rtree
+	ExtensionRTree = "gpkg_rtree_index"
+
+	// TableExtensionsSQL is the normative sql for the extensions table.
+	// http://www.geopackage.org/spec/#gpkg_extensions_sql
+	TableExtensionsSQL = `
+	CREATE TABLE IF NOT EXISTS gpkg_extensions (
+		table_name TEXT,
+		column_name TEXT,
+		extension_name TEXT NOT NULL,
+		definition TEXT NOT NULL,
+		scope TEXT NOT NULL,
+		CONSTRAINT ge_tce UNIQUE (table_name, column_name, extension_name)
+	);
+	`
+)
+
+func init() {
+	sql.Register(Driver, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			fns := map[string]interface{}{
+				"ST_MinX":    stExtent(func(ext *geom.Extent) float64 { return ext.MinX() }),
+				"ST_MaxX":    stExtent(func(ext *geom.Extent) float64 { return ext.MaxX() }),
+				"ST_MinY":    stExtent(func(ext *geom.Extent) float64 { return ext.MinY() }),
+				"ST_MaxY":    stExtent(func(ext *geom.Extent) float64 { return ext.MaxY() }),
+				"ST_IsEmpty": stIsEmpty,
+			}
+			for name, fn := range fns {
+				if err := conn.RegisterFunc(name, fn, true); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// decodeBlob decodes the geometry blob passed to one of the ST_ functions
+func decodeBlob(value interface{}) (*StandardBinary, error) {
+	data, ok := value.([]byte)
+	if !ok {
+		return nil, errors.String("only support byte slice for Geometry")
+	}
+	return DecodeGeometry(data)
+}
+
+// stExtent returns an ST_ function returning the value of the extent
+// of the geometry, or NaN if the geometry is empty
+func stExtent(fn func(*geom.Extent) float64) func(interface{}) (float64, error) {
+	return func(value interface{}) (float64, error) {
+		sb, err := decodeBlob(value)
+		if err != nil {
+			return 0, err
+		}
+		ext := sb.Extent()
+		if ext == nil {
+			return math.NaN(), nil
+		}
+		return fn(ext), nil
+	}
+}
+
+// stIsEmpty is the ST_IsEmpty function, NULL is taken to be empty
+func stIsEmpty(value interface{}) (bool, error) {
+	if value == nil {
+		return true, nil
+	}
+	sb, err := decodeBlob(value)
+	if err != nil {
+		return false, err
+	}
+	return sb.Header.IsGeometryEmpty() || geom.IsEmpty(sb.Geometry), nil
+}
+
+// primaryKey returns the name of the integer primary key column of the table
+func (h *Handle) primaryKey(tablename string) (string, error) {
+	rows, err := h.Query(fmt.Sprintf(`PRAGMA table_info("%v")`, tablename))
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid, notnull, pk int
+			name, typ        string
+			dflt             interface{}
+		)
+		if err = rows.Scan(&cid, &name, &typ, &notnull, &dflt, &pk); err != nil {
+			return "", err
+		}
+		if pk == 1 {
+			return name, nil
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("table %v does not have a primary key", tablename)
+}
+
+// geometryColumn returns the geometry column of the features table
+func (h *Handle) geometryColumn(tablename string) (string, error) {
+	const selectGeomColSQL = `
+		SELECT
+			column_name
+		FROM
+			gpkg_geometry_columns
+		WHERE
+			table_name = ?
+		`
+	var columnName string
+	err := h.QueryRow(selectGeomColSQL, tablename).Scan(&columnName)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("unknown features table %v", tablename)
+	}
+	return columnName, err
+}
+
+// rtreeName is the name of the virtual table of the spatial index
+func rtreeName(tablename, column string) string {
+	return fmt.Sprintf("rtree_%v_%v", tablename, column)
+}
+
+// HasSpatialIndex reports if the features table has a spatial index.
+func (h *Handle) HasSpatialIndex(tablename string) (bool, error) {
+	const selectSQL = `
+		SELECT Count(*)
+		FROM sqlite_master
+		WHERE
+			type = 'table' AND
+			name = ?
+		`
+	column, err := h.geometryColumn(tablename)
+	if err != nil {
+		return false, err
+	}
+	var count int
+	if err = h.QueryRow(selectSQL, rtreeName(tablename, column)).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// AddSpatialIndex will add the rtree spatial index to the given features
+// table, filled with the geometries already in the table, along with the
+// triggers that keep it up to date. The table should already be added with
+// AddGeometryTable, and have an integer primary key.
+func (h *Handle) AddSpatialIndex(tablename string) error {
+	const (
+		createSQL = `
+		CREATE VIRTUAL TABLE IF NOT EXISTS "%[1]v" USING rtree(id, minx, maxx, miny, maxy);
+		`
+		populateSQL = `
+		INSERT OR REPLACE INTO "%[1]v"
+			SELECT "%[3]v", ST_MinX("%[4]v"), ST_MaxX("%[4]v"), ST_MinY("%[4]v"), ST_MaxY("%[4]v")
+			FROM "%[2]v"
+			WHERE "%[4]v" NOT NULL AND NOT ST_IsEmpty("%[4]v");
+		`
+		// http://www.geopackage.org/spec/#extension_rtree
+		triggersSQL = `
+		CREATE TRIGGER IF NOT EXISTS "%[1]v_insert" AFTER INSERT ON "%[2]v"
+		WHEN (NEW."%[4]v" NOT NULL AND NOT ST_IsEmpty(NEW."%[4]v"))
+		BEGIN
+			INSERT OR REPLACE INTO "%[1]v" VALUES (
+				NEW."%[3]v",
+				ST_MinX(NEW."%[4]v"), ST_MaxX(NEW."%[4]v"),
+				ST_MinY(NEW."%[4]v"), ST_MaxY(NEW."%[4]v")
+			);
+		END;
+
+		CREATE TRIGGER IF NOT EXISTS "%[1]v_update1" AFTER UPDATE OF "%[4]v" ON "%[2]v"
+		WHEN OLD."%[3]v" = NEW."%[3]v" AND
+			(NEW."%[4]v" NOTNULL AND NOT ST_IsEmpty(NEW."%[4]v"))
+		BEGIN
+			INSERT OR REPLACE INTO "%[1]v" VALUES (
+				NEW."%[3]v",
+				ST_MinX(NEW."%[4]v"), ST_MaxX(NEW."%[4]v"),
+				ST_MinY(NEW."%[4]v"), ST_MaxY(NEW."%[4]v")
+			);
+		END;
+
+		CREATE TRIGGER IF NOT EXISTS "%[1]v_update2" AFTER UPDATE OF "%[4]v" ON "%[2]v"
+		WHEN OLD."%[3]v" = NEW."%[3]v" AND
+			(NEW."%[4]v" ISNULL OR ST_IsEmpty(NEW."%[4]v"))
+		BEGIN
+			DELETE FROM "%[1]v" WHERE id = OLD."%[3]v";
+		END;
+
+		CREATE TRIGGER IF NOT EXISTS "%[1]v_update3" AFTER UPDATE ON "%[2]v"
+		WHEN OLD."%[3]v" != NEW."%[3]v" AND
+			(NEW."%[4]v" NOTNULL AND NOT ST_IsEmpty(NEW."%[4]v"))
+		BEGIN
+			DELETE FROM "%[1]v" WHERE id = OLD."%[3]v";
+			INSERT OR REPLACE INTO "%[1]v" VALUES (
+				NEW."%[3]v",
+				ST_MinX(NEW."%[4]v"), ST_MaxX(NEW."%[4]v"),
+				ST_MinY(NEW."%[4]v"), ST_MaxY(NEW."%[4]v")
+			);
+		END;
+
+		CREATE TRIGGER IF NOT EXISTS "%[1]v_update4" AFTER UPDATE ON "%[2]v"
+		WHEN OLD."%[3]v" != NEW."%[3]v" AND
+			(NEW."%[4]v" ISNULL OR ST_IsEmpty(NEW."%[4]v"))
+		BEGIN
+			DELETE FROM "%[1]v" WHERE id IN (OLD."%[3]v", NEW."%[3]v");
+		END;
+
+		CREATE TRIGGER IF NOT EXISTS "%[1]v_delete" AFTER DELETE ON "%[2]v"
+		WHEN OLD."%[4]v" NOT NULL
+		BEGIN
+			DELETE FROM "%[1]v" WHERE id = OLD."%[3]v";
+		END;
+		`
+		updateExtensionsSQL = `
+		INSERT INTO gpkg_extensions(
+			table_name,
+			column_name,
+			extension_name,
+			definition,
+			scope
+		)
+		VALUES (?,?,?,?,?)
+		ON CONFLICT(table_name, column_name, extension_name) DO NOTHING;
+		`
+	)
+
+	column, err := h.geometryColumn(tablename)
+	if err != nil {
+		return err
+	}
+	id, err := h.primaryKey(tablename)
+	if err != nil {
+		return err
+	}
+	rtree := rtreeName(tablename, column)
+
+	if _, err = h.Exec(TableExtensionsSQL); err != nil {
+		return err
+	}
+	for _, sql := range []string{createSQL, populateSQL, triggersSQL} {
+		if _, err = h.Exec(fmt.Sprintf(sql, rtree, tablename, id, column)); err != nil {
+			return err
+		}
+	}
+	_, err = h.Exec(
+		updateExtensionsSQL,
+		tablename,
+		column,
+		ExtensionRTree,
+		"http://www.geopackage.org/spec/#extension_rtree",
+		"write-only",
+	)
+	return err
+}
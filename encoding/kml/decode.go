@@ -0,0 +1,333 @@
+package kml
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-spatial/geom"
+)
+
+// geometryElements are the names of the geometry elements of KML
+var geometryElements = map[string]bool{
+	"Point":         true,
+	"LineString":    true,
+	"LinearRing":    true,
+	"Polygon":       true,
+	"MultiGeometry": true,
+	"Model":         true,
+	"Track":         true,
+	"MultiTrack":    true,
+}
+
+// Decoder reads the geometries of KML, such as those of the Placemarks of
+// a KML document.
+type Decoder struct {
+	d       *xml.Decoder
+	decoded bool
+	// inPlacemark is whether the tokens read are in a Placemark
+	inPlacemark bool
+	name        string
+}
+
+// NewDecoder returns a Decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{d: xml.NewDecoder(r)}
+}
+
+// Name returns the name of the Placemark of the last geometry decoded, or
+// an empty string if it had none or was not in a Placemark.
+func (dec *Decoder) Name() string { return dec.name }
+
+// Decode reads the next geometry of the KML, skipping the elements that are
+// not geometries. io.EOF is returned if there are no more geometries, or
+// ErrNoGeometry if there were none.
+func (dec *Decoder) Decode() (geom.Geometry, error) {
+	for {
+		tok, err := dec.d.Token()
+		if err == io.EOF && !dec.decoded {
+			return nil, ErrNoGeometry
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case geometryElements[t.Name.Local]:
+				dec.decoded = true
+				if !dec.inPlacemark {
+					dec.name = ""
+				}
+				n, err := readElement(dec.d, t)
+				if err != nil {
+					return nil, err
+				}
+				return decodeGeometry(n)
+			case t.Name.Local == "Placemark":
+				dec.inPlacemark, dec.name = true, ""
+			case t.Name.Local == "name" && dec.inPlacemark:
+				n, err := readElement(dec.d, t)
+				if err != nil {
+					return nil, err
+				}
+				dec.name = strings.TrimSpace(n.text)
+			}
+		case xml.EndElement:
+			if t.Name.Local == "Placemark" {
+				dec.inPlacemark = false
+			}
+		}
+	}
+}
+
+// element is an element of the KML, without its namespace
+type element struct {
+	name     string
+	children []*element
+	text     string
+}
+
+// readElement reads the element up to its end
+func readElement(d *xml.Decoder, start xml.StartElement) (*element, error) {
+	n := &element{name: start.Name.Local}
+	var text strings.Builder
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			c, err := readElement(d, t)
+			if err != nil {
+				return nil, err
+			}
+			n.children = append(n.children, c)
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			n.text = text.String()
+			return n, nil
+		}
+	}
+}
+
+// child returns the first child element with the name
+func (n *element) child(name string) *element {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// coords decodes the coordinates element of the element
+func (n *element) coords() ([][]float64, error) {
+	c := n.child("coordinates")
+	if c == nil {
+		return nil, ErrUnsupportedElement{Name: n.name}
+	}
+	return parseCoordinates(c.text)
+}
+
+// parseCoordinates parses the coordinates of a coordinates element. Some
+// writers put spaces after the commas, which are not taken as separating
+// coordinates.
+func parseCoordinates(s string) ([][]float64, error) {
+	fs := strings.Fields(s)
+	var tuples []string
+	for i := 0; i < len(fs); i++ {
+		tuple := fs[i]
+		for i+1 < len(fs) && (strings.HasSuffix(tuple, ",") || strings.HasPrefix(fs[i+1], ",")) {
+			i++
+			tuple += fs[i]
+		}
+		tuples = append(tuples, tuple)
+	}
+
+	cs := make([][]float64, len(tuples))
+	for i, tuple := range tuples {
+		ords := strings.Split(tuple, ",")
+		if len(ords) != 2 && len(ords) != 3 {
+			return nil, ErrInvalidCoordinates
+		}
+		cs[i] = make([]float64, len(ords))
+		for j, o := range ords {
+			v, err := strconv.ParseFloat(o, 64)
+			if err != nil {
+				return nil, ErrInvalidCoordinates
+			}
+			cs[i][j] = v
+		}
+	}
+	return cs, nil
+}
+
+// hasZ returns whether any of the coordinates has an altitude
+func hasZ(cs ...[][]float64) bool {
+	for _, c := range cs {
+		for _, p := range c {
+			if len(p) == 3 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func lineString(cs [][]float64) geom.LineString {
+	ls := make(geom.LineString, len(cs))
+	for i, c := range cs {
+		ls[i] = [2]float64{c[0], c[1]}
+	}
+	return ls
+}
+
+// lineStringZ returns the line string of the coordinates, with an altitude
+// of zero for those that have none
+func lineStringZ(cs [][]float64) geom.LineStringZ {
+	ls := make(geom.LineStringZ, len(cs))
+	for i, c := range cs {
+		ls[i] = [3]float64{c[0], c[1], 0}
+		if len(c) == 3 {
+			ls[i][2] = c[2]
+		}
+	}
+	return ls
+}
+
+// decodeGeometry decodes the geometry element
+func decodeGeometry(n *element) (geom.Geometry, error) {
+	switch n.name {
+	case "Point":
+		cs, err := n.coords()
+		if err != nil {
+			return nil, err
+		}
+		if len(cs) != 1 {
+			return nil, ErrInvalidCoordinates
+		}
+		if hasZ(cs) {
+			return geom.PointZ(lineStringZ(cs)[0]), nil
+		}
+		return geom.Point(lineString(cs)[0]), nil
+
+	case "LineString", "LinearRing":
+		cs, err := n.coords()
+		if err != nil {
+			return nil, err
+		}
+		if hasZ(cs) {
+			return lineStringZ(cs), nil
+		}
+		return lineString(cs), nil
+
+	case "Polygon":
+		var rings [][][]float64
+		outer := n.child("outerBoundaryIs")
+		if outer == nil {
+			return nil, ErrUnsupportedElement{Name: n.name}
+		}
+		for _, c := range n.children {
+			if c != outer && c.name != "innerBoundaryIs" {
+				continue
+			}
+			// some writers put all the inner rings in one innerBoundaryIs
+			for _, r := range c.children {
+				if r.name != "LinearRing" {
+					continue
+				}
+				cs, err := r.coords()
+				if err != nil {
+					return nil, err
+				}
+				if c == outer && len(rings) > 0 {
+					return nil, ErrUnsupportedElement{Name: c.name}
+				}
+				rings = append(rings, cs)
+			}
+			if c == outer && len(rings) == 0 {
+				return nil, ErrUnsupportedElement{Name: c.name}
+			}
+		}
+		if hasZ(rings...) {
+			ply := make(geom.PolygonZ, len(rings))
+			for i := range rings {
+				ply[i] = lineStringZ(rings[i])
+			}
+			return ply, nil
+		}
+		ply := make(geom.Polygon, len(rings))
+		for i := range rings {
+			ply[i] = lineString(rings[i])
+		}
+		return ply, nil
+
+	case "MultiGeometry":
+		geos := make([]geom.Geometry, 0, len(n.children))
+		for _, c := range n.children {
+			if !geometryElements[c.name] {
+				continue
+			}
+			g, err := decodeGeometry(c)
+			if err != nil {
+				return nil, err
+			}
+			geos = append(geos, g)
+		}
+		return multiGeometry(geos), nil
+
+	default:
+		return nil, ErrUnsupportedElement{Name: n.name}
+	}
+}
+
+// multiGeometry returns the multi geometry of the geometries if they are
+// all of the same type, or otherwise a Collection of them
+func multiGeometry(geos []geom.Geometry) geom.Geometry {
+	if len(geos) == 0 {
+		return geom.Collection{}
+	}
+	var (
+		mp   geom.MultiPoint
+		mpz  geom.MultiPointZ
+		ml   geom.MultiLineString
+		mlz  geom.MultiLineStringZ
+		mply geom.MultiPolygon
+		mplz geom.MultiPolygonZ
+	)
+	for _, g := range geos {
+		switch gg := g.(type) {
+		case geom.Point:
+			mp = append(mp, gg)
+		case geom.PointZ:
+			mpz = append(mpz, gg)
+		case geom.LineString:
+			ml = append(ml, gg)
+		case geom.LineStringZ:
+			mlz = append(mlz, gg)
+		case geom.Polygon:
+			mply = append(mply, gg)
+		case geom.PolygonZ:
+			mplz = append(mplz, gg)
+		}
+	}
+	switch len(geos) {
+	case len(mp):
+		return mp
+	case len(mpz):
+		return mpz
+	case len(ml):
+		return ml
+	case len(mlz):
+		return mlz
+	case len(mply):
+		return mply
+	case len(mplz):
+		return mplz
+	}
+	return geom.Collection(geos)
+}
@@ -0,0 +1,330 @@
+package kml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/go-spatial/geom"
+)
+
+// Encoder writes geometries as KML.
+type Encoder struct {
+	w            io.Writer
+	altitudeMode AltitudeMode
+}
+
+// NewEncoder returns an Encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// SetAltitudeMode sets the altitudeMode of the geometries with z values.
+// It is not written if it is empty, so Google Earth clamps them to the
+// ground.
+func (enc *Encoder) SetAltitudeMode(mode AltitudeMode) { enc.altitudeMode = mode }
+
+// Encode writes the KML of the geometry, with the kml namespace declared on
+// its element. Z values are written as altitudes; measures are left out, as
+// KML has no place for them.
+func (enc *Encoder) Encode(g geom.Geometry) error {
+	e := elementWriter{enc: enc}
+	e.top = true
+	if err := e.geometry(g); err != nil {
+		return err
+	}
+	_, err := enc.w.Write(e.buf.Bytes())
+	return err
+}
+
+// EncodePlacemark writes a Placemark of the geometry, with the kml
+// namespace declared on it. The name is not written if it is empty.
+func (enc *Encoder) EncodePlacemark(name string, g geom.Geometry) error {
+	e := elementWriter{enc: enc}
+	e.buf.WriteString(`<Placemark xmlns="` + Namespace + `">`)
+	if name != "" {
+		e.buf.WriteString("<name>")
+		xml.EscapeText(&e.buf, []byte(name))
+		e.buf.WriteString("</name>")
+	}
+	if err := e.geometry(g); err != nil {
+		return err
+	}
+	e.buf.WriteString("</Placemark>")
+	_, err := enc.w.Write(e.buf.Bytes())
+	return err
+}
+
+// elementWriter writes the elements of a geometry
+type elementWriter struct {
+	enc *Encoder
+	buf bytes.Buffer
+	// top is whether the next element is the geometry's
+	top bool
+}
+
+// start writes the start of a geometry element, and its altitudeMode if it
+// has altitudes
+func (e *elementWriter) start(name string, hasZ bool) {
+	e.buf.WriteString("<")
+	e.buf.WriteString(name)
+	if e.top {
+		e.buf.WriteString(` xmlns="` + Namespace + `"`)
+		e.top = false
+	}
+	e.buf.WriteByte('>')
+	if hasZ && e.enc.altitudeMode != "" {
+		e.buf.WriteString("<altitudeMode>")
+		xml.EscapeText(&e.buf, []byte(e.enc.altitudeMode))
+		e.buf.WriteString("</altitudeMode>")
+	}
+}
+
+func (e *elementWriter) end(name string) {
+	e.buf.WriteString("</")
+	e.buf.WriteString(name)
+	e.buf.WriteByte('>')
+}
+
+// coords writes a coordinates element of the coordinates
+func (e *elementWriter) coords(cs ...[]float64) {
+	e.buf.WriteString("<coordinates>")
+	for i, c := range cs {
+		if i > 0 {
+			e.buf.WriteByte(' ')
+		}
+		for j, v := range c {
+			if j > 0 {
+				e.buf.WriteByte(',')
+			}
+			e.buf.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+		}
+	}
+	e.buf.WriteString("</coordinates>")
+}
+
+func (e *elementWriter) point(c []float64) error {
+	if math.IsNaN(c[0]) || math.IsNaN(c[1]) {
+		return ErrEmptyPoint
+	}
+	e.start("Point", len(c) == 3)
+	e.coords(c)
+	e.end("Point")
+	return nil
+}
+
+func (e *elementWriter) lineString(cs [][]float64, hasZ bool) {
+	e.start("LineString", hasZ)
+	e.coords(cs...)
+	e.end("LineString")
+}
+
+func (e *elementWriter) polygon(rings [][][]float64, hasZ bool) {
+	e.start("Polygon", hasZ)
+	for i, r := range rings {
+		boundary := "outerBoundaryIs"
+		if i > 0 {
+			boundary = "innerBoundaryIs"
+		}
+		e.buf.WriteString("<" + boundary + "><LinearRing>")
+		if len(r) > 0 && !float64sEqual(r[0], r[len(r)-1]) {
+			r = append(r[:len(r):len(r)], r[0])
+		}
+		e.coords(r...)
+		e.buf.WriteString("</LinearRing></" + boundary + ">")
+	}
+	e.end("Polygon")
+}
+
+func float64sEqual(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// multi writes a MultiGeometry of n geometries
+func (e *elementWriter) multi(n int, fn func(i int) error) error {
+	e.start("MultiGeometry", false)
+	for i := 0; i < n; i++ {
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	e.end("MultiGeometry")
+	return nil
+}
+
+func (e *elementWriter) geometry(g geom.Geometry) error {
+	switch gg := g.(type) {
+	case geom.Pointer:
+		xy := gg.XY()
+		return e.point(xy[:])
+	case geom.MultiPointer:
+		pts := coords2(gg.Points())
+		return e.multi(len(pts), func(i int) error { return e.point(pts[i]) })
+	case geom.LineStringer:
+		e.lineString(coords2(gg.Vertices()), false)
+	case geom.MultiLineStringer:
+		lns := gg.LineStrings()
+		return e.multi(len(lns), func(i int) error {
+			e.lineString(coords2(lns[i]), false)
+			return nil
+		})
+	case geom.Polygoner:
+		e.polygon(lines2(gg.LinearRings()), false)
+	case geom.MultiPolygoner:
+		plys := gg.Polygons()
+		return e.multi(len(plys), func(i int) error {
+			e.polygon(lines2(plys[i]), false)
+			return nil
+		})
+
+	case geom.PointZ:
+		return e.point(gg[:])
+	case geom.MultiPointZ:
+		pts := coords3(gg)
+		return e.multi(len(pts), func(i int) error { return e.point(pts[i]) })
+	case geom.LineStringZ:
+		e.lineString(coords3(gg), true)
+	case geom.MultiLineStringZ:
+		return e.multi(len(gg), func(i int) error {
+			e.lineString(coords3(gg[i]), true)
+			return nil
+		})
+	case geom.PolygonZ:
+		e.polygon(lines3(gg), true)
+	case geom.MultiPolygonZ:
+		return e.multi(len(gg), func(i int) error {
+			e.polygon(lines3(gg[i]), true)
+			return nil
+		})
+
+	// KML has no measures, so they are left out
+	case geom.PointM:
+		return e.point(gg[:2])
+	case geom.MultiPointM:
+		pts := coordsM(gg)
+		return e.multi(len(pts), func(i int) error { return e.point(pts[i]) })
+	case geom.LineStringM:
+		e.lineString(coordsM(gg), false)
+	case geom.MultiLineStringM:
+		return e.multi(len(gg), func(i int) error {
+			e.lineString(coordsM(gg[i]), false)
+			return nil
+		})
+	case geom.PolygonM:
+		e.polygon(linesM(gg), false)
+	case geom.MultiPolygonM:
+		return e.multi(len(gg), func(i int) error {
+			e.polygon(linesM(gg[i]), false)
+			return nil
+		})
+	case geom.PointZM:
+		return e.point(gg[:3])
+	case geom.MultiPointZM:
+		pts := coordsZM(gg)
+		return e.multi(len(pts), func(i int) error { return e.point(pts[i]) })
+	case geom.LineStringZM:
+		e.lineString(coordsZM(gg), true)
+	case geom.MultiLineStringZM:
+		return e.multi(len(gg), func(i int) error {
+			e.lineString(coordsZM(gg[i]), true)
+			return nil
+		})
+	case geom.PolygonZM:
+		e.polygon(linesZM(gg), true)
+	case geom.MultiPolygonZM:
+		return e.multi(len(gg), func(i int) error {
+			e.polygon(linesZM(gg[i]), true)
+			return nil
+		})
+
+	case geom.Collectioner:
+		geos := gg.Geometries()
+		return e.multi(len(geos), func(i int) error { return e.geometry(geos[i]) })
+
+	default:
+		// pointers to the Z, M and ZM geometries
+		if v := reflect.ValueOf(g); v.Kind() == reflect.Ptr && !v.IsNil() {
+			return e.geometry(v.Elem().Interface())
+		}
+		return geom.ErrUnknownGeometry{Geom: g}
+	}
+	return nil
+}
+
+func coords2(pts [][2]float64) [][]float64 {
+	c := make([][]float64, len(pts))
+	for i := range pts {
+		c[i] = pts[i][:]
+	}
+	return c
+}
+
+func coords3(pts [][3]float64) [][]float64 {
+	c := make([][]float64, len(pts))
+	for i := range pts {
+		c[i] = pts[i][:]
+	}
+	return c
+}
+
+func lines2(lines [][][2]float64) [][][]float64 {
+	c := make([][][]float64, len(lines))
+	for i := range lines {
+		c[i] = coords2(lines[i])
+	}
+	return c
+}
+
+func lines3(lines [][][3]float64) [][][]float64 {
+	c := make([][][]float64, len(lines))
+	for i := range lines {
+		c[i] = coords3(lines[i])
+	}
+	return c
+}
+
+// coordsM returns the x and y of the points, leaving out their measures
+func coordsM(pts [][3]float64) [][]float64 {
+	c := make([][]float64, len(pts))
+	for i := range pts {
+		c[i] = pts[i][:2]
+	}
+	return c
+}
+
+// coordsZM returns the x, y and z of the points, leaving out their
+// measures
+func coordsZM(pts [][4]float64) [][]float64 {
+	c := make([][]float64, len(pts))
+	for i := range pts {
+		c[i] = pts[i][:3]
+	}
+	return c
+}
+
+func linesM(lines [][][3]float64) [][][]float64 {
+	c := make([][][]float64, len(lines))
+	for i := range lines {
+		c[i] = coordsM(lines[i])
+	}
+	return c
+}
+
+func linesZM(lines [][][4]float64) [][][]float64 {
+	c := make([][][]float64, len(lines))
+	for i := range lines {
+		c[i] = coordsZM(lines[i])
+	}
+	return c
+}
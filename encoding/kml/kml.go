@@ -0,0 +1,96 @@
+// Package kml is for encoding and decoding the geometries of Keyhole Markup
+// Language (KML) 2.2, the XML format of Google Earth.
+// Specification at https://www.ogc.org/standards/kml
+//
+// KML coordinates are longitude, latitude and optionally altitude, in that
+// order, separated by commas, with whitespace between coordinates. They are
+// the x, y and z of the geometries, so geometries with z values are encoded
+// with altitudes, and geometries are decoded with z values if any of their
+// coordinates has an altitude, missing altitudes being taken as zero.
+//
+// KML has no multi geometries but the MultiGeometry, so MultiPoints,
+// MultiLineStrings, MultiPolygons and Collections are all encoded as
+// MultiGeometries. When decoded, a MultiGeometry of only Points,
+// LineStrings or Polygons is a MultiPoint, MultiLineString or MultiPolygon,
+// and any other MultiGeometry is a Collection. LinearRings are decoded as
+// LineStrings. The rings of polygons are closed when encoded, and decoded
+// as they are, closed.
+package kml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-spatial/geom"
+)
+
+// Namespace is the namespace of KML 2.2.
+const Namespace = "http://www.opengis.net/kml/2.2"
+
+// AltitudeMode is how the altitudes of coordinates are interpreted.
+type AltitudeMode string
+
+// The altitude modes of KML 2.2.
+const (
+	ClampToGround    AltitudeMode = "clampToGround"
+	RelativeToGround AltitudeMode = "relativeToGround"
+	Absolute         AltitudeMode = "absolute"
+)
+
+var (
+	// ErrNoGeometry is returned when decoding KML without any geometries.
+	ErrNoGeometry = errors.New("kml: no geometry")
+	// ErrInvalidCoordinates is returned when decoding coordinates that are
+	// not numbers, or do not have two or three ordinates.
+	ErrInvalidCoordinates = errors.New("kml: invalid coordinates")
+	// ErrEmptyPoint is returned when encoding a point without coordinates,
+	// which KML cannot have.
+	ErrEmptyPoint = errors.New("kml: empty point")
+)
+
+// ErrUnsupportedElement is returned when decoding a geometry element that
+// is not supported, or is missing an element it needs.
+type ErrUnsupportedElement struct {
+	Name string
+}
+
+func (e ErrUnsupportedElement) Error() string {
+	return fmt.Sprintf("kml: unsupported element %v", e.Name)
+}
+
+// Encode writes the KML of the geometry.
+func Encode(w io.Writer, g geom.Geometry) error {
+	return NewEncoder(w).Encode(g)
+}
+
+// EncodeBytes returns the KML of the geometry.
+func EncodeBytes(g geom.Geometry) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, g); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeString returns the KML of the geometry.
+func EncodeString(g geom.Geometry) (string, error) {
+	b, err := EncodeBytes(g)
+	return string(b), err
+}
+
+// Decode reads the first geometry of the KML.
+func Decode(r io.Reader) (geom.Geometry, error) {
+	return NewDecoder(r).Decode()
+}
+
+// DecodeBytes decodes the first geometry of the KML.
+func DecodeBytes(b []byte) (geom.Geometry, error) {
+	return Decode(bytes.NewReader(b))
+}
+
+// DecodeString decodes the first geometry of the KML.
+func DecodeString(s string) (geom.Geometry, error) {
+	return DecodeBytes([]byte(s))
+}
@@ -0,0 +1,306 @@
+package kml_test
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/kml"
+)
+
+const ns = `xmlns="http://www.opengis.net/kml/2.2"`
+
+func TestEncode(t *testing.T) {
+	type tcase struct {
+		geom         geom.Geometry
+		altitudeMode kml.AltitudeMode
+		exp          string
+		err          error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var buff bytes.Buffer
+			enc := kml.NewEncoder(&buff)
+			enc.SetAltitudeMode(tc.altitudeMode)
+			err := enc.Encode(tc.geom)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if got := buff.String(); got != tc.exp {
+				t.Errorf("encode, expected\n%v\ngot\n%v", tc.exp, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			geom: geom.Point{-122.5, 37.25},
+			exp:  `<Point ` + ns + `><coordinates>-122.5,37.25</coordinates></Point>`,
+		},
+		"point z altitude mode": {
+			geom:         geom.PointZ{1, 2, 300},
+			altitudeMode: kml.RelativeToGround,
+			exp:          `<Point ` + ns + `><altitudeMode>relativeToGround</altitudeMode><coordinates>1,2,300</coordinates></Point>`,
+		},
+		"point altitude mode without z": {
+			geom:         geom.Point{1, 2},
+			altitudeMode: kml.Absolute,
+			exp:          `<Point ` + ns + `><coordinates>1,2</coordinates></Point>`,
+		},
+		"linestring": {
+			geom: geom.LineString{{1, 2}, {3, 4}},
+			exp:  `<LineString ` + ns + `><coordinates>1,2 3,4</coordinates></LineString>`,
+		},
+		"polygon closes rings": {
+			geom: geom.Polygon{{{0, 0}, {4, 0}, {4, 4}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+			exp: `<Polygon ` + ns + `>` +
+				`<outerBoundaryIs><LinearRing><coordinates>0,0 4,0 4,4 0,0</coordinates></LinearRing></outerBoundaryIs>` +
+				`<innerBoundaryIs><LinearRing><coordinates>1,1 2,1 2,2 1,1</coordinates></LinearRing></innerBoundaryIs>` +
+				`</Polygon>`,
+		},
+		"multipoint": {
+			geom: geom.MultiPoint{{1, 2}, {3, 4}},
+			exp:  `<MultiGeometry ` + ns + `><Point><coordinates>1,2</coordinates></Point><Point><coordinates>3,4</coordinates></Point></MultiGeometry>`,
+		},
+		"multilinestring z": {
+			geom: geom.MultiLineStringZ{{{1, 2, 3}, {4, 5, 6}}},
+			exp:  `<MultiGeometry ` + ns + `><LineString><coordinates>1,2,3 4,5,6</coordinates></LineString></MultiGeometry>`,
+		},
+		"collection": {
+			geom: geom.Collection{geom.Point{1, 2}, geom.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}}},
+			exp: `<MultiGeometry ` + ns + `><Point><coordinates>1,2</coordinates></Point>` +
+				`<MultiGeometry><Polygon><outerBoundaryIs><LinearRing><coordinates>0,0 1,0 1,1 0,0</coordinates></LinearRing></outerBoundaryIs></Polygon></MultiGeometry>` +
+				`</MultiGeometry>`,
+		},
+		"point zm pointer": {
+			geom: &geom.PointZM{1, 2, 3, 4},
+			exp:  `<Point ` + ns + `><coordinates>1,2,3</coordinates></Point>`,
+		},
+		"linestring m": {
+			geom: geom.LineStringM{{1, 2, 3}, {4, 5, 6}},
+			exp:  `<LineString ` + ns + `><coordinates>1,2 4,5</coordinates></LineString>`,
+		},
+		"multipolygon zm": {
+			geom: geom.MultiPolygonZM{{{{0, 0, 1, 9}, {1, 0, 1, 9}, {1, 1, 1, 9}}}},
+			exp:  `<MultiGeometry ` + ns + `><Polygon><outerBoundaryIs><LinearRing><coordinates>0,0,1 1,0,1 1,1,1 0,0,1</coordinates></LinearRing></outerBoundaryIs></Polygon></MultiGeometry>`,
+		},
+		"nil pointer": {
+			geom: (*geom.PointZ)(nil),
+			err:  geom.ErrUnknownGeometry{Geom: (*geom.PointZ)(nil)},
+		},
+		"empty point": {
+			geom: geom.Point{math.NaN(), math.NaN()},
+			err:  kml.ErrEmptyPoint,
+		},
+		"unknown geometry": {
+			geom: nil,
+			err:  geom.ErrUnknownGeometry{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestEncodePlacemark(t *testing.T) {
+	var buff bytes.Buffer
+	if err := kml.NewEncoder(&buff).EncodePlacemark("a & b", geom.Point{1, 2}); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	exp := `<Placemark ` + ns + `><name>a &amp; b</name><Point><coordinates>1,2</coordinates></Point></Placemark>`
+	if got := buff.String(); got != exp {
+		t.Errorf("encode, expected\n%v\ngot\n%v", exp, got)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	type tcase struct {
+		kml  string
+		exp  geom.Geometry
+		name string
+		err  error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			dec := kml.NewDecoder(strings.NewReader(tc.kml))
+			got, err := dec.Decode()
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("decode, expected %v got %v", tc.exp, got)
+			}
+			if got := dec.Name(); got != tc.name {
+				t.Errorf("name, expected %v got %v", tc.name, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			kml: `<Point><coordinates>-122.5,37.25</coordinates></Point>`,
+			exp: geom.Point{-122.5, 37.25},
+		},
+		"point altitude": {
+			kml: `<Point><altitudeMode>absolute</altitudeMode><coordinates>1,2,300</coordinates></Point>`,
+			exp: geom.PointZ{1, 2, 300},
+		},
+		"placemark in document": {
+			kml: `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+<Document>
+	<name>document</name>
+	<Placemark>
+		<name> place </name>
+		<description>a place</description>
+		<LineString>
+			<tessellate>1</tessellate>
+			<coordinates>
+				1,2
+				3,4
+			</coordinates>
+		</LineString>
+	</Placemark>
+</Document>
+</kml>`,
+			exp:  geom.LineString{{1, 2}, {3, 4}},
+			name: "place",
+		},
+		"spaces after commas": {
+			kml: `<LineString><coordinates>1, 2 3 ,4</coordinates></LineString>`,
+			exp: geom.LineString{{1, 2}, {3, 4}},
+		},
+		"some altitudes": {
+			kml: `<LineString><coordinates>1,2,10 3,4</coordinates></LineString>`,
+			exp: geom.LineStringZ{{1, 2, 10}, {3, 4, 0}},
+		},
+		"linear ring": {
+			kml: `<LinearRing><coordinates>0,0 1,0 1,1 0,0</coordinates></LinearRing>`,
+			exp: geom.LineString{{0, 0}, {1, 0}, {1, 1}, {0, 0}},
+		},
+		"polygon": {
+			kml: `<Polygon>
+	<outerBoundaryIs><LinearRing><coordinates>0,0 4,0 4,4 0,0</coordinates></LinearRing></outerBoundaryIs>
+	<innerBoundaryIs><LinearRing><coordinates>1,1 2,1 2,2 1,1</coordinates></LinearRing></innerBoundaryIs>
+</Polygon>`,
+			exp: geom.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 0}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+		},
+		"polygon inner rings in one boundary": {
+			kml: `<Polygon>
+	<outerBoundaryIs><LinearRing><coordinates>0,0 4,0 4,4 0,0</coordinates></LinearRing></outerBoundaryIs>
+	<innerBoundaryIs>
+		<LinearRing><coordinates>1,1 2,1 2,2 1,1</coordinates></LinearRing>
+		<LinearRing><coordinates>3,1 3.5,1 3.5,2 3,1</coordinates></LinearRing>
+	</innerBoundaryIs>
+</Polygon>`,
+			exp: geom.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 0}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}, {{3, 1}, {3.5, 1}, {3.5, 2}, {3, 1}}},
+		},
+		"polygon altitude": {
+			kml: `<Polygon><outerBoundaryIs><LinearRing><coordinates>0,0,5 1,0,5 1,1,5 0,0,5</coordinates></LinearRing></outerBoundaryIs></Polygon>`,
+			exp: geom.PolygonZ{{{0, 0, 5}, {1, 0, 5}, {1, 1, 5}, {0, 0, 5}}},
+		},
+		"multigeometry of points": {
+			kml: `<MultiGeometry><Point><coordinates>1,2</coordinates></Point><Point><coordinates>3,4</coordinates></Point></MultiGeometry>`,
+			exp: geom.MultiPoint{{1, 2}, {3, 4}},
+		},
+		"multigeometry of polygons": {
+			kml: `<MultiGeometry><Polygon><outerBoundaryIs><LinearRing><coordinates>0,0 1,0 1,1 0,0</coordinates></LinearRing></outerBoundaryIs></Polygon></MultiGeometry>`,
+			exp: geom.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}},
+		},
+		"multigeometry mixed": {
+			kml: `<MultiGeometry><Point><coordinates>1,2</coordinates></Point><LineString><coordinates>1,2 3,4</coordinates></LineString></MultiGeometry>`,
+			exp: geom.Collection{geom.Point{1, 2}, geom.LineString{{1, 2}, {3, 4}}},
+		},
+		"no geometry": {
+			kml: `<kml><Document><name>empty</name></Document></kml>`,
+			err: kml.ErrNoGeometry,
+		},
+		"model": {
+			kml:  `<Placemark><name>house</name><Model><Link/></Model></Placemark>`,
+			name: "house",
+			err:  kml.ErrUnsupportedElement{Name: "Model"},
+		},
+		"point without coordinates": {
+			kml: `<Point/>`,
+			err: kml.ErrUnsupportedElement{Name: "Point"},
+		},
+		"polygon without outer boundary": {
+			kml: `<Polygon/>`,
+			err: kml.ErrUnsupportedElement{Name: "Polygon"},
+		},
+		"invalid number": {
+			kml: `<Point><coordinates>1,a</coordinates></Point>`,
+			err: kml.ErrInvalidCoordinates,
+		},
+		"too many ordinates": {
+			kml: `<Point><coordinates>1,2,3,4</coordinates></Point>`,
+			err: kml.ErrInvalidCoordinates,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDecodePlacemarks(t *testing.T) {
+	dec := kml.NewDecoder(strings.NewReader(`<kml><Document>
+<Placemark><name>a</name><Point><coordinates>1,2</coordinates></Point></Placemark>
+<Placemark><Point><coordinates>3,4</coordinates></Point></Placemark>
+</Document></kml>`))
+	type placemark struct {
+		name string
+		geom geom.Geometry
+	}
+	exp := []placemark{{"a", geom.Point{1, 2}}, {"", geom.Point{3, 4}}}
+	for i, e := range exp {
+		got, err := dec.Decode()
+		if err != nil {
+			t.Fatalf("error %v, expected nil got %v", i, err)
+		}
+		if !reflect.DeepEqual(got, e.geom) {
+			t.Errorf("geometry %v, expected %v got %v", i, e.geom, got)
+		}
+		if dec.Name() != e.name {
+			t.Errorf("name %v, expected %v got %v", i, e.name, dec.Name())
+		}
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		t.Errorf("error, expected %v got %v", io.EOF, err)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	geoms := map[string]geom.Geometry{
+		"point z":         geom.PointZ{1, 2, 3},
+		"multipoint":      geom.MultiPoint{{1, 2}, {3, 4}},
+		"multilinestring": geom.MultiLineString{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}}},
+		"polygon":         geom.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 0}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+		"multipolygon z":  geom.MultiPolygonZ{{{{0, 0, 1}, {1, 0, 1}, {1, 1, 1}, {0, 0, 1}}}},
+		"collection":      geom.Collection{geom.PointZ{1, 2, 3}, geom.LineString{{0, 0}, {1, 0}}},
+	}
+
+	for name, g := range geoms {
+		g := g
+		t.Run(name, func(t *testing.T) {
+			b, err := kml.EncodeBytes(g)
+			if err != nil {
+				t.Fatalf("encode error, expected nil got %v", err)
+			}
+			got, err := kml.DecodeBytes(b)
+			if err != nil {
+				t.Fatalf("decode error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(got, g) {
+				t.Errorf("round trip, expected %v got %v", g, got)
+			}
+		})
+	}
+}
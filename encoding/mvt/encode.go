@@ -0,0 +1,180 @@
+package mvt
+
+import (
+	"context"
+	"math"
+
+	"github.com/go-spatial/geom"
+	vectorTile "github.com/go-spatial/geom/encoding/mvt/vector_tile"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/clip"
+)
+
+// Encoder converts geometries into the pixel coordinates of a tile and
+// encodes them as MVT geometry commands. Unlike PrepareGeo, the geometries
+// do not need to be inside the tile, they are clipped to it.
+type Encoder struct {
+	// Tile is the extent of the tile, in the same projection as the
+	// geometries.
+	Tile *geom.Extent
+	// Extent is the dimension of the (square) tile in pixels, if zero
+	// DefaultExtent is used.
+	Extent uint32
+	// Buffer is the number of pixels around the tile that geometries are
+	// kept in, so lines and polygons on the edge of the tile can be drawn
+	// without seams.
+	Buffer uint32
+	// Simplifier is used, if not nil, to simplify the geometries after
+	// they are converted to pixels, so its tolerance is in pixels.
+	Simplifier planar.Simplifer
+}
+
+func (e Encoder) extent() float64 {
+	if e.Extent == 0 {
+		return float64(DefaultExtent)
+	}
+	return float64(e.Extent)
+}
+
+// Prepare returns the geometry in the pixel coordinates of the tile: simplified,
+// clipped to the tile and its buffer, and rounded to whole pixels. Parts of
+// the geometry that are outside the tile, or that become too small, are
+// dropped; if nothing is left the result is nil.
+func (e Encoder) Prepare(ctx context.Context, g geom.Geometry) (geom.Geometry, error) {
+	if e.Tile == nil {
+		return nil, ErrNilTileExtent
+	}
+	if g == nil {
+		return nil, ErrNilGeometryType
+	}
+
+	switch g.(type) {
+	case geom.Point, geom.MultiPoint, geom.LineString, geom.MultiLineString,
+		geom.Polygon, geom.MultiPolygon, *geom.MultiPolygon:
+	default:
+		return nil, ErrUnknownGeometryType
+	}
+
+	pg := PrepareGeo(g, e.Tile, e.extent())
+	if pg == nil {
+		return nil, nil
+	}
+	pg, err := planar.Simplify(ctx, e.Simplifier, pg)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := float64(e.Buffer)
+	clipbox := &geom.Extent{-buf, -buf, e.extent() + buf, e.extent() + buf}
+	switch pg := pg.(type) {
+	case geom.Point:
+		if !clipbox.ContainsPoint(pg) {
+			return nil, nil
+		}
+		return roundPoint(pg), nil
+
+	case geom.MultiPoint:
+		var mp geom.MultiPoint
+		for _, pt := range pg {
+			if clipbox.ContainsPoint(pt) {
+				mp = append(mp, roundPoint(pt))
+			}
+		}
+		if len(mp) == 0 {
+			return nil, nil
+		}
+		return mp, nil
+
+	case geom.LineString, geom.MultiLineString:
+		clipped, err := clip.Geometry(ctx, pg, clipbox)
+		if err != nil {
+			return nil, err
+		}
+		mls, _ := clipped.(geom.MultiLineString)
+		var lines geom.MultiLineString
+		for _, ln := range mls {
+			if ln = roundLine(ln); len(ln) >= 2 {
+				lines = append(lines, ln)
+			}
+		}
+		switch len(lines) {
+		case 0:
+			return nil, nil
+		case 1:
+			return geom.LineString(lines[0]), nil
+		}
+		return lines, nil
+
+	case geom.Polygon, geom.MultiPolygon, *geom.MultiPolygon:
+		mp, err := planar.Intersection(ctx, pg, clipbox.AsPolygon())
+		if err != nil {
+			return nil, err
+		}
+		var polys geom.MultiPolygon
+		for _, poly := range mp {
+			var rings geom.Polygon
+			for i, ring := range poly {
+				ring = roundLine(ring)
+				if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+					ring = ring[:len(ring)-1]
+				}
+				if len(ring) < 3 {
+					if i == 0 {
+						break
+					}
+					continue
+				}
+				rings = append(rings, ring)
+			}
+			if len(rings) > 0 {
+				polys = append(polys, rings)
+			}
+		}
+		switch len(polys) {
+		case 0:
+			return nil, nil
+		case 1:
+			return geom.Polygon(polys[0]), nil
+		}
+		return polys, nil
+	}
+	return nil, nil
+}
+
+// Encode prepares the geometry, see Prepare, and encodes it as MVT geometry
+// commands, with the rings of polygons wound the way the spec expects. If
+// nothing of the geometry is left in the tile, the commands are nil and the
+// type is vectorTile.Tile_UNKNOWN.
+func (e Encoder) Encode(ctx context.Context, g geom.Geometry) ([]uint32, vectorTile.Tile_GeomType, error) {
+	pg, err := e.Prepare(ctx, g)
+	if err != nil || pg == nil {
+		return nil, vectorTile.Tile_UNKNOWN, err
+	}
+	return encodeGeometry(ctx, pg)
+}
+
+func roundPoint(pt geom.Point) geom.Point {
+	x, y := math.Round(pt[0]), math.Round(pt[1])
+	// no negative zeros
+	if x == 0 {
+		x = 0
+	}
+	if y == 0 {
+		y = 0
+	}
+	return geom.Point{x, y}
+}
+
+// roundLine rounds the points of the line to whole pixels, dropping points
+// that round to the point before them
+func roundLine(ln [][2]float64) [][2]float64 {
+	out := make([][2]float64, 0, len(ln))
+	for _, pt := range ln {
+		rpt := [2]float64(roundPoint(pt))
+		if len(out) > 0 && out[len(out)-1] == rpt {
+			continue
+		}
+		out = append(out, rpt)
+	}
+	return out
+}
@@ -0,0 +1,120 @@
+package mvt
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	vectorTile "github.com/go-spatial/geom/encoding/mvt/vector_tile"
+	"github.com/go-spatial/geom/planar/simplify"
+)
+
+func TestEncoderPrepare(t *testing.T) {
+	type tcase struct {
+		enc      Encoder
+		g        geom.Geometry
+		expected geom.Geometry
+		err      error
+	}
+
+	// a tile of 0,0 to 100,100 that is 10 pixels across
+	tile := &geom.Extent{0, 0, 100, 100}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := tc.enc.Prepare(context.Background(), tc.g)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("geometry, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"nil tile": {
+			g:   geom.Point{1, 1},
+			err: ErrNilTileExtent,
+		},
+		"unknown geometry": {
+			enc: Encoder{Tile: tile, Extent: 10},
+			g:   geom.Collection{geom.Point{1, 1}},
+			err: ErrUnknownGeometryType,
+		},
+		"point": {
+			enc:      Encoder{Tile: tile, Extent: 10},
+			g:        geom.Point{21, 69},
+			expected: geom.Point{2, 3},
+		},
+		"point in buffer": {
+			enc:      Encoder{Tile: tile, Extent: 10, Buffer: 1},
+			g:        geom.Point{-5, 50},
+			expected: geom.Point{-1, 5},
+		},
+		"point outside": {
+			enc: Encoder{Tile: tile, Extent: 10, Buffer: 1},
+			g:   geom.Point{-15, 50},
+		},
+		"multipoint": {
+			enc:      Encoder{Tile: tile, Extent: 10},
+			g:        geom.MultiPoint{{10, 10}, {200, 10}, {90, 90}},
+			expected: geom.MultiPoint{{1, 9}, {9, 1}},
+		},
+		"line clipped": {
+			enc:      Encoder{Tile: tile, Extent: 10, Buffer: 1},
+			g:        geom.LineString{{-100, 50}, {50, 50}, {50, 200}},
+			expected: geom.LineString{{-1, 5}, {5, 5}, {5, -1}},
+		},
+		"line outside": {
+			enc: Encoder{Tile: tile, Extent: 10},
+			g:   geom.LineString{{-100, -50}, {200, -50}},
+		},
+		"line simplified": {
+			enc: Encoder{
+				Tile:       tile,
+				Extent:     10,
+				Simplifier: simplify.DouglasPeucker{Tolerance: 1},
+			},
+			g:        geom.LineString{{0, 50}, {50, 53}, {100, 50}},
+			expected: geom.LineString{{0, 5}, {10, 5}},
+		},
+		"polygon clipped": {
+			enc: Encoder{Tile: tile, Extent: 10},
+			g:   geom.Polygon{{{50, 50}, {150, 50}, {150, 150}, {50, 150}}},
+			expected: geom.Polygon{
+				{{10, 5}, {5, 5}, {5, 0}, {10, 0}},
+			},
+		},
+		"polygon too small": {
+			enc: Encoder{Tile: tile, Extent: 10},
+			g:   geom.Polygon{{{50, 50}, {51, 50}, {51, 51}, {50, 51}}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestEncoderEncode(t *testing.T) {
+	enc := Encoder{Tile: &geom.Extent{0, 0, 4096, 4096}}
+	// a polygon going outside the top right of the tile
+	g, typ, err := enc.Encode(context.Background(), geom.Polygon{{{3000, 3000}, {5000, 3000}, {5000, 5000}, {3000, 5000}}})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if typ != vectorTile.Tile_POLYGON {
+		t.Errorf("type, expected %v got %v", vectorTile.Tile_POLYGON, typ)
+	}
+	// the ring is 3000,1096 4096,1096 4096,0 3000,0 in pixels; MoveTo,
+	// LineTo 3 and ClosePath
+	if len(g) != 3+1+6+1 {
+		t.Errorf("commands, expected 11 got %v: %v", len(g), g)
+	}
+
+	g, typ, err = enc.Encode(context.Background(), geom.Point{-10, -10})
+	if err != nil || g != nil || typ != vectorTile.Tile_UNKNOWN {
+		t.Errorf("outside, expected nil %v nil got %v %v %v", vectorTile.Tile_UNKNOWN, g, typ, err)
+	}
+}
@@ -15,6 +15,7 @@ var (
 	ErrNilFeature          = fmt.Errorf("feature is nil")
 	ErrUnknownGeometryType = fmt.Errorf("unknown geometry type")
 	ErrNilGeometryType     = fmt.Errorf("geometry is nil")
+	ErrNilTileExtent       = fmt.Errorf("tile extent is nil")
 )
 
 // TODO: Need to put in validation for the Geometry, as current the system
@@ -50,7 +51,7 @@ func NewFeatures(geo geom.Geometry, tags map[string]interface{}) (f []Feature) {
 		return f // return empty feature set for a nil geometry
 	}
 
-	if g, ok := geo.(geom.Collection); ok {
+	if g, ok := geo.(geom.Collectioner); ok {
 		geos := g.Geometries()
 		for i := range geos {
 			f = append(f, NewFeatures(geos[i], tags)...)
@@ -13,6 +13,9 @@ note: the geometry must not go outside the tile extent. If this is unknown,
 use the clip package before encoding.
 (https://godoc.org/github.com/go-spatial/geom/planar/clip#Geometry)
 
+Alternatively, an `Encoder` does the conversion to pixels, the clipping to
+the tile and its buffer, and optionally simplification, in one step.
+
 
 To encode:
 	1. Call `PrepareGeomtry`, it returns a `geom.Geometry` that is "reprojected"
@@ -0,0 +1,357 @@
+package shp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldType is the type of a .dbf field.
+type FieldType byte
+
+// field types
+const (
+	Character FieldType = 'C'
+	Numeric   FieldType = 'N'
+	Float     FieldType = 'F'
+	Logical   FieldType = 'L'
+	Date      FieldType = 'D'
+)
+
+// Field describes a column of the .dbf file.
+type Field struct {
+	// Name is at most 10 characters
+	Name string
+	Type FieldType
+	// Length is the number of bytes of the values, at most 254
+	Length uint8
+	// Decimals is the number of digits after the decimal point, for
+	// Numeric and Float fields. Numeric fields without decimals are read
+	// as int64.
+	Decimals uint8
+}
+
+// ErrInvalidField is returned when writing a .dbf file with a field that can
+// not be stored.
+type ErrInvalidField struct {
+	Field Field
+}
+
+func (e ErrInvalidField) Error() string {
+	return fmt.Sprintf("shp: invalid field %q", e.Field.Name)
+}
+
+// ErrInvalidValue is returned when writing an attribute that can not be
+// stored in its field.
+type ErrInvalidValue struct {
+	Field Field
+	Value interface{}
+}
+
+func (e ErrInvalidValue) Error() string {
+	return fmt.Sprintf("shp: can not store %v (%T) in field %q", e.Value, e.Value, e.Field.Name)
+}
+
+const (
+	dbfVersion         = 0x03
+	dbfHeaderLen       = 32
+	dbfFieldLen        = 32
+	dbfHeaderEnd       = 0x0d
+	dbfEOF             = 0x1a
+	dbfDeleted         = '*'
+	dbfDateLayout      = "20060102"
+	dbfMaxFieldNameLen = 10
+)
+
+// dbfReader reads the records of a .dbf file
+type dbfReader struct {
+	r         *bufio.Reader
+	fields    []Field
+	records   uint32
+	recordLen uint16
+	read      uint32
+	buf       []byte
+}
+
+func newDBFReader(r io.Reader) (*dbfReader, error) {
+	br := bufio.NewReader(r)
+	var hdr [dbfHeaderLen]byte
+	if _, err := io.ReadFull(br, hdr[:]); err != nil {
+		return nil, ErrInvalidDBF
+	}
+	d := &dbfReader{
+		r:         br,
+		records:   binary.LittleEndian.Uint32(hdr[4:]),
+		recordLen: binary.LittleEndian.Uint16(hdr[10:]),
+	}
+	headerLen := int(binary.LittleEndian.Uint16(hdr[8:]))
+	if headerLen < dbfHeaderLen+1 {
+		return nil, ErrInvalidDBF
+	}
+
+	rest := make([]byte, headerLen-dbfHeaderLen)
+	if _, err := io.ReadFull(br, rest); err != nil {
+		return nil, ErrInvalidDBF
+	}
+	length := 1 // the deletion flag
+	for i := 0; i+dbfFieldLen <= len(rest) && rest[i] != dbfHeaderEnd; i += dbfFieldLen {
+		fd := rest[i : i+dbfFieldLen]
+		name := fd[:11]
+		if n := bytes.IndexByte(name, 0); n >= 0 {
+			name = name[:n]
+		}
+		f := Field{
+			Name:     strings.TrimSpace(string(name)),
+			Type:     FieldType(fd[11]),
+			Length:   fd[16],
+			Decimals: fd[17],
+		}
+		length += int(f.Length)
+		d.fields = append(d.fields, f)
+	}
+	if length > int(d.recordLen) {
+		return nil, ErrInvalidDBF
+	}
+	d.buf = make([]byte, d.recordLen)
+	return d, nil
+}
+
+// next returns the attributes of the next record, and whether it is marked
+// as deleted. io.EOF is returned after the last record.
+func (d *dbfReader) next() (map[string]interface{}, bool, error) {
+	if d.read == d.records {
+		return nil, false, io.EOF
+	}
+	if _, err := io.ReadFull(d.r, d.buf); err != nil {
+		return nil, false, ErrInvalidDBF
+	}
+	d.read++
+
+	attrs := make(map[string]interface{}, len(d.fields))
+	off := 1
+	for _, f := range d.fields {
+		attrs[f.Name] = parseValue(f, d.buf[off:off+int(f.Length)])
+		off += int(f.Length)
+	}
+	return attrs, d.buf[0] == dbfDeleted, nil
+}
+
+// parseValue returns the value of the field, or nil if it is blank or can
+// not be parsed
+func parseValue(f Field, b []byte) interface{} {
+	s := strings.TrimSpace(string(b))
+	switch f.Type {
+	case Character:
+		s = strings.TrimRight(string(b), " \x00")
+		if s == "" {
+			return nil
+		}
+		return s
+	case Numeric, Float:
+		if s == "" || strings.Trim(s, "*") == "" {
+			return nil
+		}
+		if f.Type == Numeric && f.Decimals == 0 {
+			if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return v
+			}
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil
+		}
+		return v
+	case Logical:
+		switch s {
+		case "T", "t", "Y", "y":
+			return true
+		case "F", "f", "N", "n":
+			return false
+		}
+		return nil
+	case Date:
+		t, err := time.Parse(dbfDateLayout, s)
+		if err != nil {
+			return nil
+		}
+		return t
+	}
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// dbfWriter writes the records of a .dbf file
+type dbfWriter struct {
+	w         io.WriteSeeker
+	fields    []Field
+	records   uint32
+	recordLen uint16
+	buf       []byte
+}
+
+func newDBFWriter(w io.WriteSeeker, fields []Field) (*dbfWriter, error) {
+	recordLen := 1
+	names := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		key := strings.ToUpper(f.Name)
+		if f.Name == "" || len(f.Name) > dbfMaxFieldNameLen || names[key] {
+			return nil, ErrInvalidField{Field: f}
+		}
+		names[key] = true
+		switch f.Type {
+		case Character, Numeric, Float:
+			if f.Length == 0 {
+				return nil, ErrInvalidField{Field: f}
+			}
+		case Logical:
+			if f.Length != 1 {
+				return nil, ErrInvalidField{Field: f}
+			}
+		case Date:
+			if f.Length != 8 {
+				return nil, ErrInvalidField{Field: f}
+			}
+		default:
+			return nil, ErrInvalidField{Field: f}
+		}
+		recordLen += int(f.Length)
+	}
+	if recordLen > math.MaxUint16 {
+		return nil, ErrInvalidField{Field: fields[len(fields)-1]}
+	}
+	d := &dbfWriter{
+		w:         w,
+		fields:    fields,
+		recordLen: uint16(recordLen),
+		buf:       make([]byte, recordLen),
+	}
+	return d, d.writeHeader()
+}
+
+func (d *dbfWriter) writeHeader() error {
+	hdr := make([]byte, dbfHeaderLen+dbfFieldLen*len(d.fields)+1)
+	hdr[0] = dbfVersion
+	now := time.Now()
+	hdr[1], hdr[2], hdr[3] = byte(now.Year()-1900), byte(now.Month()), byte(now.Day())
+	binary.LittleEndian.PutUint32(hdr[4:], d.records)
+	binary.LittleEndian.PutUint16(hdr[8:], uint16(len(hdr)))
+	binary.LittleEndian.PutUint16(hdr[10:], d.recordLen)
+	for i, f := range d.fields {
+		fd := hdr[dbfHeaderLen+i*dbfFieldLen:]
+		copy(fd[:11], f.Name)
+		fd[11] = byte(f.Type)
+		fd[16] = f.Length
+		fd[17] = f.Decimals
+	}
+	hdr[len(hdr)-1] = dbfHeaderEnd
+	_, err := d.w.Write(hdr)
+	return err
+}
+
+func (d *dbfWriter) write(attrs map[string]interface{}) error {
+	buf := d.buf
+	buf[0] = ' '
+	off := 1
+	for _, f := range d.fields {
+		if err := formatValue(f, attrs[f.Name], buf[off:off+int(f.Length)]); err != nil {
+			return err
+		}
+		off += int(f.Length)
+	}
+	if _, err := d.w.Write(buf); err != nil {
+		return err
+	}
+	d.records++
+	return nil
+}
+
+// close writes the end of file marker and updates the record count
+func (d *dbfWriter) close() error {
+	if _, err := d.w.Write([]byte{dbfEOF}); err != nil {
+		return err
+	}
+	if _, err := d.w.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return d.writeHeader()
+}
+
+// formatValue writes the value into b, which is the length of the field
+func formatValue(f Field, v interface{}, b []byte) error {
+	for i := range b {
+		b[i] = ' '
+	}
+	if v == nil {
+		return nil
+	}
+
+	var s string
+	rightAlign := false
+	switch f.Type {
+	case Character:
+		str, ok := v.(string)
+		if !ok {
+			return ErrInvalidValue{Field: f, Value: v}
+		}
+		s = str
+	case Numeric, Float:
+		rightAlign = true
+		switch n := v.(type) {
+		case int:
+			s = formatNumber(float64(n), int64(n), true, f.Decimals)
+		case int32:
+			s = formatNumber(float64(n), int64(n), true, f.Decimals)
+		case int64:
+			s = formatNumber(float64(n), n, true, f.Decimals)
+		case float32:
+			s = formatNumber(float64(n), 0, false, f.Decimals)
+		case float64:
+			if math.IsNaN(n) || math.IsInf(n, 0) {
+				return ErrInvalidValue{Field: f, Value: v}
+			}
+			s = formatNumber(n, 0, false, f.Decimals)
+		default:
+			return ErrInvalidValue{Field: f, Value: v}
+		}
+	case Logical:
+		t, ok := v.(bool)
+		if !ok {
+			return ErrInvalidValue{Field: f, Value: v}
+		}
+		s = "F"
+		if t {
+			s = "T"
+		}
+	case Date:
+		t, ok := v.(time.Time)
+		if !ok {
+			return ErrInvalidValue{Field: f, Value: v}
+		}
+		s = t.Format(dbfDateLayout)
+	}
+
+	if len(s) > len(b) {
+		return ErrInvalidValue{Field: f, Value: v}
+	}
+	if rightAlign {
+		copy(b[len(b)-len(s):], s)
+	} else {
+		copy(b, s)
+	}
+	return nil
+}
+
+func formatNumber(f float64, i int64, isInt bool, decimals uint8) string {
+	if isInt && decimals == 0 {
+		return strconv.FormatInt(i, 10)
+	}
+	return strconv.FormatFloat(f, 'f', int(decimals), 64)
+}
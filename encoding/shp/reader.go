@@ -0,0 +1,189 @@
+package shp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+
+	"github.com/go-spatial/geom"
+)
+
+const (
+	headerLen       = 100
+	recordHeaderLen = 8
+)
+
+// header is the header of the .shp and .shx files
+type header struct {
+	// length is the file length in 16-bit words
+	length int32
+	typ    ShapeType
+	// ext is the bounding box, followed by the z and m ranges
+	ext [8]float64
+}
+
+func readHeader(r io.Reader) (header, error) {
+	var b [headerLen]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return header{}, ErrInvalidFile
+	}
+	if binary.BigEndian.Uint32(b[0:]) != fileCode || binary.LittleEndian.Uint32(b[28:]) != version {
+		return header{}, ErrInvalidFile
+	}
+	h := header{
+		length: int32(binary.BigEndian.Uint32(b[24:])),
+		typ:    ShapeType(binary.LittleEndian.Uint32(b[32:])),
+	}
+	for i := range h.ext {
+		h.ext[i] = math.Float64frombits(binary.LittleEndian.Uint64(b[36+8*i:]))
+	}
+	return h, nil
+}
+
+func (h header) bytes() []byte {
+	var b [headerLen]byte
+	binary.BigEndian.PutUint32(b[0:], fileCode)
+	binary.BigEndian.PutUint32(b[24:], uint32(h.length))
+	binary.LittleEndian.PutUint32(b[28:], version)
+	binary.LittleEndian.PutUint32(b[32:], uint32(h.typ))
+	for i, v := range h.ext {
+		binary.LittleEndian.PutUint64(b[36+8*i:], math.Float64bits(v))
+	}
+	return b[:]
+}
+
+// Reader reads the records of a shapefile.
+type Reader struct {
+	shp     *bufio.Reader
+	dbf     *dbfReader
+	hdr     header
+	closers []io.Closer
+	buf     []byte
+}
+
+// NewReader returns a Reader of the .shp file and its .dbf file. The .dbf
+// file may be nil, in which case the records have no attributes.
+func NewReader(shp io.Reader, dbf io.Reader) (*Reader, error) {
+	br := bufio.NewReader(shp)
+	hdr, err := readHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	r := &Reader{shp: br, hdr: hdr}
+	if dbf != nil {
+		if r.dbf, err = newDBFReader(dbf); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Open opens the shapefile at the path, with or without the .shp extension,
+// and its .dbf file if there is one. The Reader must be closed.
+func Open(path string) (*Reader, error) {
+	base := basePath(path)
+	shp, err := os.Open(base + ".shp")
+	if err != nil {
+		return nil, err
+	}
+	var dbf io.Reader
+	dbfFile, err := os.Open(base + ".dbf")
+	switch {
+	case err == nil:
+		dbf = dbfFile
+	case !os.IsNotExist(err):
+		shp.Close()
+		return nil, err
+	}
+
+	r, err := NewReader(shp, dbf)
+	if err != nil {
+		shp.Close()
+		if dbfFile != nil {
+			dbfFile.Close()
+		}
+		return nil, err
+	}
+	r.closers = append(r.closers, shp)
+	if dbfFile != nil {
+		r.closers = append(r.closers, dbfFile)
+	}
+	return r, nil
+}
+
+// ShapeType returns the shape type of the file.
+func (r *Reader) ShapeType() ShapeType { return r.hdr.typ }
+
+// Extent returns the bounding box of the shapes, from the header of the
+// file.
+func (r *Reader) Extent() geom.Extent {
+	return geom.Extent{r.hdr.ext[0], r.hdr.ext[1], r.hdr.ext[2], r.hdr.ext[3]}
+}
+
+// Fields returns the fields of the .dbf file.
+func (r *Reader) Fields() []Field {
+	if r.dbf == nil {
+		return nil
+	}
+	return append([]Field(nil), r.dbf.fields...)
+}
+
+// Next returns the next record. io.EOF is returned after the last record.
+func (r *Reader) Next() (Record, error) {
+	var rec Record
+	var rh [recordHeaderLen]byte
+	if _, err := io.ReadFull(r.shp, rh[:]); err != nil {
+		if err == io.EOF {
+			if r.dbf != nil && r.dbf.read != r.dbf.records {
+				return rec, ErrRecordCount
+			}
+			return rec, io.EOF
+		}
+		return rec, ErrInvalidRecord
+	}
+	n := int(binary.BigEndian.Uint32(rh[4:])) * 2
+	if n < 4 {
+		return rec, ErrInvalidRecord
+	}
+	if cap(r.buf) < n {
+		r.buf = make([]byte, n)
+	}
+	b := r.buf[:n]
+	if _, err := io.ReadFull(r.shp, b); err != nil {
+		return rec, ErrInvalidRecord
+	}
+
+	s, err := readShape(b)
+	if err != nil {
+		return rec, err
+	}
+	if s.typ != Null && s.typ != r.hdr.typ {
+		return rec, ErrInvalidRecord
+	}
+	rec.Geometry = s.geometry()
+
+	if r.dbf != nil {
+		rec.Attributes, _, err = r.dbf.next()
+		if err == io.EOF {
+			return rec, ErrRecordCount
+		}
+		if err != nil {
+			return rec, err
+		}
+	}
+	return rec, nil
+}
+
+// Close closes the files opened by Open.
+func (r *Reader) Close() error {
+	var err error
+	for _, c := range r.closers {
+		if e := c.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	r.closers = nil
+	return err
+}
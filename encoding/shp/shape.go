@@ -0,0 +1,649 @@
+package shp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/winding"
+)
+
+// shape is the content of a record: the parts of a PolyLine or Polygon, or
+// a single part holding the points of a Point or MultiPoint. Coordinates
+// are x, y, z and m.
+type shape struct {
+	typ        ShapeType
+	hasZ, hasM bool
+	parts      [][][4]float64
+}
+
+// readShape decodes the content of a record
+func readShape(b []byte) (shape, error) {
+	r := &contentReader{b: b}
+	typ := ShapeType(r.int32())
+	s := shape{typ: typ, hasZ: typ.HasZ()}
+	if r.err != nil {
+		return s, ErrInvalidRecord
+	}
+
+	switch typ.base() {
+	case Null:
+		return s, nil
+
+	case Point:
+		c := [4]float64{r.float64(), r.float64(), 0, math.NaN()}
+		if typ.HasZ() {
+			c[2] = r.float64()
+		}
+		// the measure is optional for PointZ
+		if typ.HasM() && (r.len() >= 8 || !typ.HasZ()) {
+			c[3] = measure(r.float64())
+			s.hasM = !math.IsNaN(c[3])
+		}
+		s.parts = [][][4]float64{{c}}
+
+	case MultiPoint, PolyLine, Polygon:
+		r.skip(32) // the bounding box
+		numParts, partsLen := 1, 0
+		if typ.base() != MultiPoint {
+			numParts = int(r.int32())
+			partsLen = numParts * 4
+		}
+		numPoints := int(r.int32())
+		if r.err != nil || numParts < 0 || numPoints < 0 || partsLen+numPoints*16 > r.len() {
+			return s, ErrInvalidRecord
+		}
+		starts := []int{0}
+		if typ.base() != MultiPoint {
+			starts = make([]int, numParts)
+			for i := range starts {
+				starts[i] = int(r.int32())
+				if starts[i] < 0 || starts[i] > numPoints || (i > 0 && starts[i] < starts[i-1]) {
+					return s, ErrInvalidRecord
+				}
+			}
+		}
+
+		pts := make([][4]float64, numPoints)
+		for i := range pts {
+			pts[i] = [4]float64{r.float64(), r.float64(), 0, math.NaN()}
+		}
+		if typ.HasZ() {
+			r.skip(16) // the z range
+			for i := range pts {
+				pts[i][2] = r.float64()
+			}
+		}
+		// the measures are optional for the z types
+		if typ.HasM() && (r.len() >= 16+8*numPoints || !typ.HasZ()) {
+			r.skip(16) // the m range
+			for i := range pts {
+				pts[i][3] = measure(r.float64())
+				s.hasM = s.hasM || !math.IsNaN(pts[i][3])
+			}
+		}
+		if r.err != nil {
+			return s, ErrInvalidRecord
+		}
+
+		s.parts = make([][][4]float64, len(starts))
+		for i, start := range starts {
+			end := numPoints
+			if i+1 < len(starts) {
+				end = starts[i+1]
+			}
+			s.parts[i] = pts[start:end:end]
+		}
+
+	default:
+		return s, ErrUnsupportedShapeType{Typ: typ}
+	}
+
+	if r.err != nil {
+		return s, ErrInvalidRecord
+	}
+	// M types always have measures, even if there is no data
+	s.hasM = s.hasM || (typ.HasM() && !typ.HasZ())
+	return s, nil
+}
+
+// measure returns the measure, or NaN if it is no data
+func measure(m float64) float64 {
+	if m < noData {
+		return math.NaN()
+	}
+	return m
+}
+
+// contentReader reads the little endian values of a record's content
+type contentReader struct {
+	b   []byte
+	err error
+}
+
+func (r *contentReader) len() int { return len(r.b) }
+
+func (r *contentReader) skip(n int) {
+	if len(r.b) < n {
+		r.err, r.b = ErrInvalidRecord, nil
+		return
+	}
+	r.b = r.b[n:]
+}
+
+func (r *contentReader) int32() int32 {
+	if len(r.b) < 4 {
+		r.err, r.b = ErrInvalidRecord, nil
+		return 0
+	}
+	v := int32(binary.LittleEndian.Uint32(r.b))
+	r.b = r.b[4:]
+	return v
+}
+
+func (r *contentReader) float64() float64 {
+	if len(r.b) < 8 {
+		r.err, r.b = ErrInvalidRecord, nil
+		return 0
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(r.b))
+	r.b = r.b[8:]
+	return v
+}
+
+// geometry returns the geometry of the shape
+func (s shape) geometry() geom.Geometry {
+	switch s.typ.base() {
+	case Null:
+		return nil
+	case Point:
+		return s.point(s.parts[0][0])
+	case MultiPoint:
+		return s.multiPoint(s.parts[0])
+	case PolyLine:
+		if len(s.parts) == 1 {
+			return s.lineString(s.parts[0])
+		}
+		return s.multiLineString(s.parts)
+	default:
+		plys := groupRings(s.parts)
+		if len(plys) == 1 {
+			return s.polygon(plys[0])
+		}
+		return s.multiPolygon(plys)
+	}
+}
+
+func (s shape) point(c [4]float64) geom.Geometry {
+	switch {
+	case s.hasZ && s.hasM:
+		return geom.PointZM(c)
+	case s.hasZ:
+		return geom.PointZ{c[0], c[1], c[2]}
+	case s.hasM:
+		return geom.PointM{c[0], c[1], c[3]}
+	}
+	return geom.Point{c[0], c[1]}
+}
+
+func (s shape) multiPoint(pts [][4]float64) geom.Geometry {
+	switch {
+	case s.hasZ && s.hasM:
+		return geom.MultiPointZM(coordsZM(pts))
+	case s.hasZ:
+		return geom.MultiPointZ(coordsZ(pts))
+	case s.hasM:
+		return geom.MultiPointM(coordsM(pts))
+	}
+	return geom.MultiPoint(coordsXY(pts))
+}
+
+func (s shape) lineString(pts [][4]float64) geom.Geometry {
+	switch {
+	case s.hasZ && s.hasM:
+		return geom.LineStringZM(coordsZM(pts))
+	case s.hasZ:
+		return geom.LineStringZ(coordsZ(pts))
+	case s.hasM:
+		return geom.LineStringM(coordsM(pts))
+	}
+	return geom.LineString(coordsXY(pts))
+}
+
+func (s shape) multiLineString(parts [][][4]float64) geom.Geometry {
+	switch {
+	case s.hasZ && s.hasM:
+		mls := make(geom.MultiLineStringZM, len(parts))
+		for i := range parts {
+			mls[i] = coordsZM(parts[i])
+		}
+		return mls
+	case s.hasZ:
+		mls := make(geom.MultiLineStringZ, len(parts))
+		for i := range parts {
+			mls[i] = coordsZ(parts[i])
+		}
+		return mls
+	case s.hasM:
+		mls := make(geom.MultiLineStringM, len(parts))
+		for i := range parts {
+			mls[i] = coordsM(parts[i])
+		}
+		return mls
+	}
+	mls := make(geom.MultiLineString, len(parts))
+	for i := range parts {
+		mls[i] = coordsXY(parts[i])
+	}
+	return mls
+}
+
+func (s shape) polygon(rings [][][4]float64) geom.Geometry {
+	switch g := s.multiLineString(rings).(type) {
+	case geom.MultiLineStringZM:
+		return geom.PolygonZM(g)
+	case geom.MultiLineStringZ:
+		return geom.PolygonZ(g)
+	case geom.MultiLineStringM:
+		return geom.PolygonM(g)
+	default:
+		return geom.Polygon(g.(geom.MultiLineString))
+	}
+}
+
+func (s shape) multiPolygon(plys [][][][4]float64) geom.Geometry {
+	switch {
+	case s.hasZ && s.hasM:
+		mp := make(geom.MultiPolygonZM, len(plys))
+		for i := range plys {
+			mp[i] = s.polygon(plys[i]).(geom.PolygonZM)
+		}
+		return mp
+	case s.hasZ:
+		mp := make(geom.MultiPolygonZ, len(plys))
+		for i := range plys {
+			mp[i] = s.polygon(plys[i]).(geom.PolygonZ)
+		}
+		return mp
+	case s.hasM:
+		mp := make(geom.MultiPolygonM, len(plys))
+		for i := range plys {
+			mp[i] = s.polygon(plys[i]).(geom.PolygonM)
+		}
+		return mp
+	}
+	mp := make(geom.MultiPolygon, len(plys))
+	for i := range plys {
+		mp[i] = s.polygon(plys[i]).(geom.Polygon)
+	}
+	return mp
+}
+
+func coordsXY(pts [][4]float64) [][2]float64 {
+	ret := make([][2]float64, len(pts))
+	for i, c := range pts {
+		ret[i] = [2]float64{c[0], c[1]}
+	}
+	return ret
+}
+
+func coordsZ(pts [][4]float64) [][3]float64 {
+	ret := make([][3]float64, len(pts))
+	for i, c := range pts {
+		ret[i] = [3]float64{c[0], c[1], c[2]}
+	}
+	return ret
+}
+
+func coordsM(pts [][4]float64) [][3]float64 {
+	ret := make([][3]float64, len(pts))
+	for i, c := range pts {
+		ret[i] = [3]float64{c[0], c[1], c[3]}
+	}
+	return ret
+}
+
+func coordsZM(pts [][4]float64) [][4]float64 {
+	return append([][4]float64(nil), pts...)
+}
+
+// groupRings returns the polygons made by the rings. Clockwise rings are
+// outer rings, and counter clockwise rings are holes in the smallest outer
+// ring containing them. Holes not in any outer ring are used as outer
+// rings.
+func groupRings(rings [][][4]float64) [][][][4]float64 {
+	var (
+		plys  [][][][4]float64
+		outer []geom.Polygon
+		areas []float64
+		holes [][][4]float64
+	)
+	for _, r := range rings {
+		a := winding.SignedArea(coordsXY(r)...)
+		if a > 0 {
+			holes = append(holes, r)
+			continue
+		}
+		plys = append(plys, [][][4]float64{r})
+		outer = append(outer, geom.Polygon{coordsXY(r)})
+		areas = append(areas, -a)
+	}
+
+	for _, h := range holes {
+		best := -1
+		if len(h) > 0 {
+			pt := geom.Point{h[0][0], h[0][1]}
+			for i := range outer {
+				if (best < 0 || areas[i] < areas[best]) && planar.PolygonContains(outer[i], pt) {
+					best = i
+				}
+			}
+		}
+		if best < 0 {
+			plys = append(plys, [][][4]float64{h})
+			continue
+		}
+		plys[best] = append(plys[best], h)
+	}
+	return plys
+}
+
+// newShape returns the shape of the geometry, which must be able to be
+// stored as the shape type. Rings are closed and given the directions of
+// the shapefile: clockwise for outer rings and counter clockwise for holes.
+func newShape(typ ShapeType, g geom.Geometry) (shape, error) {
+	var (
+		s      = shape{hasZ: typ.HasZ(), hasM: typ.HasM()}
+		base   ShapeType
+		hasZ   bool
+		hasM   bool
+		parts  [][][4]float64
+		rings  []int // the number of rings of each polygon
+		mismat = ErrShapeTypeMismatch{Typ: typ, Geom: g}
+	)
+
+	switch gg := g.(type) {
+	case nil:
+		s.typ = Null
+		return s, nil
+
+	case geom.Point:
+		base, parts = Point, [][][4]float64{{xy(gg)}}
+	case geom.PointZ:
+		base, hasZ, parts = Point, true, [][][4]float64{{xyz(gg)}}
+	case geom.PointM:
+		base, hasM, parts = Point, true, [][][4]float64{{xym(gg)}}
+	case geom.PointZM:
+		base, hasZ, hasM, parts = Point, true, true, [][][4]float64{{gg}}
+
+	case geom.MultiPoint:
+		base, parts = MultiPoint, [][][4]float64{line2(gg)}
+	case geom.MultiPointZ:
+		base, hasZ, parts = MultiPoint, true, [][][4]float64{lineZ(gg)}
+	case geom.MultiPointM:
+		base, hasM, parts = MultiPoint, true, [][][4]float64{lineM(gg)}
+	case geom.MultiPointZM:
+		base, hasZ, hasM, parts = MultiPoint, true, true, [][][4]float64{lineZM(gg)}
+
+	case geom.LineString:
+		base, parts = PolyLine, [][][4]float64{line2(gg)}
+	case geom.LineStringZ:
+		base, hasZ, parts = PolyLine, true, [][][4]float64{lineZ(gg)}
+	case geom.LineStringM:
+		base, hasM, parts = PolyLine, true, [][][4]float64{lineM(gg)}
+	case geom.LineStringZM:
+		base, hasZ, hasM, parts = PolyLine, true, true, [][][4]float64{lineZM(gg)}
+
+	case geom.MultiLineString:
+		base = PolyLine
+		for _, l := range gg {
+			parts = append(parts, line2(l))
+		}
+	case geom.MultiLineStringZ:
+		base, hasZ = PolyLine, true
+		for _, l := range gg {
+			parts = append(parts, lineZ(l))
+		}
+	case geom.MultiLineStringM:
+		base, hasM = PolyLine, true
+		for _, l := range gg {
+			parts = append(parts, lineM(l))
+		}
+	case geom.MultiLineStringZM:
+		base, hasZ, hasM = PolyLine, true, true
+		for _, l := range gg {
+			parts = append(parts, lineZM(l))
+		}
+
+	case geom.Polygon:
+		base, rings = Polygon, []int{len(gg)}
+		for _, r := range gg {
+			parts = append(parts, line2(r))
+		}
+	case geom.PolygonZ:
+		base, hasZ, rings = Polygon, true, []int{len(gg)}
+		for _, r := range gg {
+			parts = append(parts, lineZ(r))
+		}
+	case geom.PolygonM:
+		base, hasM, rings = Polygon, true, []int{len(gg)}
+		for _, r := range gg {
+			parts = append(parts, lineM(r))
+		}
+	case geom.PolygonZM:
+		base, hasZ, hasM, rings = Polygon, true, true, []int{len(gg)}
+		for _, r := range gg {
+			parts = append(parts, lineZM(r))
+		}
+
+	case geom.MultiPolygon:
+		base = Polygon
+		for _, p := range gg {
+			rings = append(rings, len(p))
+			for _, r := range p {
+				parts = append(parts, line2(r))
+			}
+		}
+	case geom.MultiPolygonZ:
+		base, hasZ = Polygon, true
+		for _, p := range gg {
+			rings = append(rings, len(p))
+			for _, r := range p {
+				parts = append(parts, lineZ(r))
+			}
+		}
+	case geom.MultiPolygonM:
+		base, hasM = Polygon, true
+		for _, p := range gg {
+			rings = append(rings, len(p))
+			for _, r := range p {
+				parts = append(parts, lineM(r))
+			}
+		}
+	case geom.MultiPolygonZM:
+		base, hasZ, hasM = Polygon, true, true
+		for _, p := range gg {
+			rings = append(rings, len(p))
+			for _, r := range p {
+				parts = append(parts, lineZM(r))
+			}
+		}
+
+	case geom.Collectioner:
+		return collectionShape(typ, gg, mismat)
+
+	default:
+		return s, mismat
+	}
+
+	// Z types take geometries with z, with or without measures, and M types
+	// take geometries with measures but no z
+	switch {
+	case base != typ.base(),
+		typ.HasZ() != hasZ,
+		!typ.HasZ() && typ.HasM() != hasM:
+		return s, mismat
+	}
+
+	s.typ = typ
+	s.parts = parts
+	if base == Polygon {
+		i := 0
+		for _, n := range rings {
+			for j := 0; j < n; j, i = j+1, i+1 {
+				s.parts[i] = closeRing(s.parts[i], j == 0)
+			}
+		}
+	}
+	return s, nil
+}
+
+// collectionShape returns the shape of the geometries of the collection,
+// which are written as one shape so they must all be able to be stored as
+// the shape type typ. A Point shape holds only one point.
+func collectionShape(typ ShapeType, col geom.Collectioner, mismat error) (shape, error) {
+	s := shape{typ: Null, hasZ: typ.HasZ(), hasM: typ.HasM()}
+	for _, g := range col.Geometries() {
+		ms, err := newShape(typ, g)
+		if err != nil {
+			return s, mismat
+		}
+		if ms.typ == Null {
+			continue
+		}
+		if typ.base() == MultiPoint && len(s.parts) > 0 {
+			s.parts[0] = append(s.parts[0], ms.parts[0]...)
+		} else {
+			s.parts = append(s.parts, ms.parts...)
+		}
+		s.typ = typ
+	}
+	if typ.base() == Point && len(s.parts) > 1 {
+		return s, mismat
+	}
+	return s, nil
+}
+
+// closeRing returns the ring closed and in the direction for an outer ring
+// or a hole
+func closeRing(ring [][4]float64, outer bool) [][4]float64 {
+	if len(ring) == 0 {
+		return ring
+	}
+	// measures may be NaN, so only the coordinates are compared
+	if first, last := ring[0], ring[len(ring)-1]; first[0] != last[0] || first[1] != last[1] || first[2] != last[2] {
+		ring = append(ring[:len(ring):len(ring)], ring[0])
+	}
+	if (winding.SignedArea(coordsXY(ring)...) > 0) == outer {
+		for i, j := 0, len(ring)-1; i < j; i, j = i+1, j-1 {
+			ring[i], ring[j] = ring[j], ring[i]
+		}
+	}
+	return ring
+}
+
+func xy(c [2]float64) [4]float64  { return [4]float64{c[0], c[1], 0, math.NaN()} }
+func xyz(c [3]float64) [4]float64 { return [4]float64{c[0], c[1], c[2], math.NaN()} }
+func xym(c [3]float64) [4]float64 { return [4]float64{c[0], c[1], 0, c[2]} }
+func line2(l [][2]float64) [][4]float64 {
+	ret := make([][4]float64, len(l))
+	for i, c := range l {
+		ret[i] = xy(c)
+	}
+	return ret
+}
+func lineZ(l [][3]float64) [][4]float64 {
+	ret := make([][4]float64, len(l))
+	for i, c := range l {
+		ret[i] = xyz(c)
+	}
+	return ret
+}
+func lineM(l [][3]float64) [][4]float64 {
+	ret := make([][4]float64, len(l))
+	for i, c := range l {
+		ret[i] = xym(c)
+	}
+	return ret
+}
+func lineZM(l [][4]float64) [][4]float64 { return append([][4]float64(nil), l...) }
+
+// content returns the content of the record for the shape, and its
+// bounding box and z and m ranges
+func (s shape) content() (b []byte, ext [8]float64) {
+	ext = [8]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1), math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)}
+	var pts [][4]float64
+	for _, p := range s.parts {
+		pts = append(pts, p...)
+	}
+	for _, c := range pts {
+		ext[0], ext[1] = math.Min(ext[0], c[0]), math.Min(ext[1], c[1])
+		ext[2], ext[3] = math.Max(ext[2], c[0]), math.Max(ext[3], c[1])
+		ext[4], ext[5] = math.Min(ext[4], c[2]), math.Max(ext[5], c[2])
+		if !math.IsNaN(c[3]) {
+			ext[6], ext[7] = math.Min(ext[6], c[3]), math.Max(ext[7], c[3])
+		}
+	}
+
+	var buf bytes.Buffer
+	w := func(v interface{}) { binary.Write(&buf, binary.LittleEndian, v) }
+	m := func(v float64) float64 {
+		if math.IsNaN(v) {
+			return 2 * noData
+		}
+		return v
+	}
+	rng := func(lo, hi float64) {
+		if lo > hi {
+			lo, hi = 0, 0
+		}
+		w([2]float64{lo, hi})
+	}
+
+	w(int32(s.typ))
+	switch s.typ.base() {
+	case Null:
+
+	case Point:
+		c := pts[0]
+		w([2]float64{c[0], c[1]})
+		if s.typ.HasZ() {
+			w(c[2])
+		}
+		if s.typ.HasM() {
+			w(m(c[3]))
+		}
+
+	default:
+		w([4]float64{ext[0], ext[1], ext[2], ext[3]})
+		if s.typ.base() != MultiPoint {
+			w(int32(len(s.parts)))
+		}
+		w(int32(len(pts)))
+		if s.typ.base() != MultiPoint {
+			start := 0
+			for _, p := range s.parts {
+				w(int32(start))
+				start += len(p)
+			}
+		}
+		for _, c := range pts {
+			w([2]float64{c[0], c[1]})
+		}
+		if s.typ.HasZ() {
+			rng(ext[4], ext[5])
+			for _, c := range pts {
+				w(c[2])
+			}
+		}
+		if s.typ.HasM() {
+			rng(ext[6], ext[7])
+			for _, c := range pts {
+				w(m(c[3]))
+			}
+		}
+	}
+	return buf.Bytes(), ext
+}
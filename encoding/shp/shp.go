@@ -0,0 +1,163 @@
+// Package shp is for reading and writing ESRI shapefiles: the geometries in
+// the .shp file, the index of them in the .shx file and their attributes in
+// the .dbf file.
+//
+// Specification at
+// https://www.esri.com/content/dam/esrisites/sitecore-archive/Files/Pdfs/library/whitepapers/pdfs/shapefile.pdf
+//
+// PolyLines with one part are read as LineStrings and Polygons with one
+// outer ring as Polygons, otherwise the multi geometries are used. The Z
+// shapes are read as the Z geometries unless they have measures, in which
+// case the ZM geometries are used. Measures that are "no data" are NaN.
+// MultiPatch shapes are not supported.
+package shp
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-spatial/geom"
+)
+
+// ShapeType is the type of the shapes in a shapefile.
+type ShapeType int32
+
+// shape types
+const (
+	Null        ShapeType = 0
+	Point       ShapeType = 1
+	PolyLine    ShapeType = 3
+	Polygon     ShapeType = 5
+	MultiPoint  ShapeType = 8
+	PointZ      ShapeType = 11
+	PolyLineZ   ShapeType = 13
+	PolygonZ    ShapeType = 15
+	MultiPointZ ShapeType = 18
+	PointM      ShapeType = 21
+	PolyLineM   ShapeType = 23
+	PolygonM    ShapeType = 25
+	MultiPointM ShapeType = 28
+	MultiPatch  ShapeType = 31
+)
+
+func (t ShapeType) String() string {
+	switch t {
+	case Null:
+		return "Null"
+	case Point:
+		return "Point"
+	case PolyLine:
+		return "PolyLine"
+	case Polygon:
+		return "Polygon"
+	case MultiPoint:
+		return "MultiPoint"
+	case PointZ:
+		return "PointZ"
+	case PolyLineZ:
+		return "PolyLineZ"
+	case PolygonZ:
+		return "PolygonZ"
+	case MultiPointZ:
+		return "MultiPointZ"
+	case PointM:
+		return "PointM"
+	case PolyLineM:
+		return "PolyLineM"
+	case PolygonM:
+		return "PolygonM"
+	case MultiPointM:
+		return "MultiPointM"
+	case MultiPatch:
+		return "MultiPatch"
+	}
+	return fmt.Sprintf("ShapeType(%d)", int32(t))
+}
+
+// base returns the shape type without z or m
+func (t ShapeType) base() ShapeType {
+	switch t {
+	case PointZ, PointM:
+		return Point
+	case PolyLineZ, PolyLineM:
+		return PolyLine
+	case PolygonZ, PolygonM:
+		return Polygon
+	case MultiPointZ, MultiPointM:
+		return MultiPoint
+	}
+	return t
+}
+
+// HasZ reports whether the shapes have z values, and measures.
+func (t ShapeType) HasZ() bool { return t >= PointZ && t <= MultiPointZ }
+
+// HasM reports whether the shapes have measures.
+func (t ShapeType) HasM() bool { return t.HasZ() || (t >= PointM && t <= MultiPointM) }
+
+// fileCode and version are the values at the start of the .shp and .shx
+// headers
+const (
+	fileCode = 9994
+	version  = 1000
+)
+
+// noData is the largest measure that means there is no measure
+const noData = -1e38
+
+var (
+	// ErrInvalidFile is returned when a .shp or .shx file does not start
+	// with the shapefile header.
+	ErrInvalidFile = errors.New("shp: invalid file")
+	// ErrInvalidRecord is returned when a record's content does not match
+	// its shape.
+	ErrInvalidRecord = errors.New("shp: invalid record")
+	// ErrInvalidDBF is returned when a .dbf file can not be read.
+	ErrInvalidDBF = errors.New("shp: invalid dbf file")
+	// ErrRecordCount is returned when the .dbf file has a different number
+	// of records to the .shp file.
+	ErrRecordCount = errors.New("shp: dbf record count does not match shapes")
+	// ErrClosed is returned when writing to a closed Writer.
+	ErrClosed = errors.New("shp: writer is closed")
+)
+
+// ErrUnsupportedShapeType is returned for shape types that can not be read
+// or written.
+type ErrUnsupportedShapeType struct {
+	Typ ShapeType
+}
+
+func (e ErrUnsupportedShapeType) Error() string {
+	return fmt.Sprintf("shp: unsupported shape type %v", e.Typ)
+}
+
+// ErrShapeTypeMismatch is returned when writing a geometry that can not be
+// stored as the shape type of the file.
+type ErrShapeTypeMismatch struct {
+	Typ  ShapeType
+	Geom geom.Geometry
+}
+
+func (e ErrShapeTypeMismatch) Error() string {
+	return fmt.Sprintf("shp: can not write %T as %v", e.Geom, e.Typ)
+}
+
+// Record is a shape and its attributes.
+type Record struct {
+	// Geometry is nil for Null shapes
+	Geometry geom.Geometry
+	// Attributes are the values of the fields of the .dbf file, by name.
+	// The values are string, int64, float64, bool, time.Time, or nil when
+	// they are blank.
+	Attributes map[string]interface{}
+}
+
+// basePath returns the path without the .shp extension
+func basePath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".shp") {
+		return path[:len(path)-4]
+	}
+	return path
+}
@@ -0,0 +1,334 @@
+package shp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/geom"
+)
+
+// buffer is an in memory io.WriteSeeker
+type buffer struct {
+	b   []byte
+	pos int
+}
+
+func (b *buffer) Write(p []byte) (int, error) {
+	if end := b.pos + len(p); end > len(b.b) {
+		b.b = append(b.b, make([]byte, end-len(b.b))...)
+	}
+	n := copy(b.b[b.pos:], p)
+	b.pos += n
+	return n, nil
+}
+
+func (b *buffer) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+		b.pos = int(offset)
+	case io.SeekCurrent:
+		b.pos += int(offset)
+	case io.SeekEnd:
+		b.pos = len(b.b) + int(offset)
+	}
+	if b.pos < 0 {
+		return 0, errors.New("negative position")
+	}
+	return int64(b.pos), nil
+}
+
+func TestRoundTrip(t *testing.T) {
+	fields := []Field{
+		{Name: "NAME", Type: Character, Length: 16},
+		{Name: "COUNT", Type: Numeric, Length: 8},
+		{Name: "VALUE", Type: Numeric, Length: 12, Decimals: 3},
+		{Name: "OK", Type: Logical, Length: 1},
+		{Name: "DAY", Type: Date, Length: 8},
+	}
+	day := time.Date(2020, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	type tcase struct {
+		typ     ShapeType
+		records []Record
+		// expected is the geometries read, if different to those written
+		expected []geom.Geometry
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var shp, shx, dbf buffer
+			w, err := NewWriter(&shp, &shx, &dbf, tc.typ, fields)
+			if err != nil {
+				t.Fatalf("new writer, expected nil got %v", err)
+			}
+			for _, rec := range tc.records {
+				if err := w.Write(rec); err != nil {
+					t.Fatalf("write, expected nil got %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("close, expected nil got %v", err)
+			}
+			if len(shp.b)%2 != 0 || int(binaryLen(shp.b)) != len(shp.b)/2 {
+				t.Errorf("shp length, expected %v got %v", len(shp.b)/2, binaryLen(shp.b))
+			}
+			if expected := headerLen + recordHeaderLen*len(tc.records); len(shx.b) != expected {
+				t.Errorf("shx length, expected %v got %v", expected, len(shx.b))
+			}
+
+			r, err := NewReader(bytes.NewReader(shp.b), bytes.NewReader(dbf.b))
+			if err != nil {
+				t.Fatalf("new reader, expected nil got %v", err)
+			}
+			if r.ShapeType() != tc.typ {
+				t.Errorf("shape type, expected %v got %v", tc.typ, r.ShapeType())
+			}
+			if !reflect.DeepEqual(r.Fields(), fields) {
+				t.Errorf("fields, expected %v got %v", fields, r.Fields())
+			}
+			for i, rec := range tc.records {
+				got, err := r.Next()
+				if err != nil {
+					t.Fatalf("next %v, expected nil got %v", i, err)
+				}
+				expected := rec.Geometry
+				if tc.expected != nil {
+					expected = tc.expected[i]
+				}
+				if !reflect.DeepEqual(got.Geometry, expected) {
+					t.Errorf("geometry %v, expected %v got %v", i, expected, got.Geometry)
+				}
+				if !reflect.DeepEqual(got.Attributes, rec.Attributes) {
+					t.Errorf("attributes %v, expected %v got %v", i, rec.Attributes, got.Attributes)
+				}
+			}
+			if _, err := r.Next(); err != io.EOF {
+				t.Errorf("end, expected io.EOF got %v", err)
+			}
+		}
+	}
+
+	attrs := map[string]interface{}{
+		"NAME":  "first",
+		"COUNT": int64(42),
+		"VALUE": 1.5,
+		"OK":    true,
+		"DAY":   day,
+	}
+	blank := map[string]interface{}{
+		"NAME":  nil,
+		"COUNT": nil,
+		"VALUE": nil,
+		"OK":    nil,
+		"DAY":   nil,
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			typ: Point,
+			records: []Record{
+				{Geometry: geom.Point{1, 2}, Attributes: attrs},
+				{Geometry: nil, Attributes: blank},
+				{Geometry: geom.Point{-3, 4}, Attributes: blank},
+			},
+		},
+		"point z": {
+			typ: PointZ,
+			records: []Record{
+				{Geometry: geom.PointZ{1, 2, 3}, Attributes: attrs},
+				{Geometry: geom.PointZM{1, 2, 3, 4}, Attributes: attrs},
+			},
+		},
+		"multipoint m": {
+			typ: MultiPointM,
+			records: []Record{
+				{Geometry: geom.MultiPointM{{1, 2, 3}, {4, 5, 6}}, Attributes: attrs},
+			},
+		},
+		"polyline": {
+			typ: PolyLine,
+			records: []Record{
+				{Geometry: geom.LineString{{0, 0}, {1, 1}, {2, 0}}, Attributes: attrs},
+				{Geometry: geom.MultiLineString{{{0, 0}, {1, 1}}, {{5, 5}, {6, 7}, {8, 8}}}, Attributes: blank},
+			},
+		},
+		"polyline zm": {
+			typ: PolyLineZ,
+			records: []Record{
+				{Geometry: geom.MultiLineStringZM{{{0, 0, 1, 2}, {1, 1, 3, 4}}, {{5, 5, 5, 6}, {6, 7, 7, 8}}}, Attributes: attrs},
+			},
+		},
+		"polygon": {
+			typ: Polygon,
+			records: []Record{
+				{
+					// counter clockwise, open, with a clockwise hole
+					Geometry: geom.Polygon{
+						{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+						{{2, 2}, {2, 4}, {4, 4}, {4, 2}},
+					},
+					Attributes: attrs,
+				},
+				{
+					Geometry: geom.MultiPolygon{
+						{{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}, {{2, 2}, {4, 2}, {4, 4}, {2, 4}, {2, 2}}},
+						{{{3, 3}, {3, 3.5}, {3.5, 3.5}, {3.5, 3}, {3, 3}}},
+						{{{20, 0}, {20, 5}, {25, 5}, {25, 0}, {20, 0}}},
+					},
+					Attributes: blank,
+				},
+			},
+			expected: []geom.Geometry{
+				geom.Polygon{
+					{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}},
+					{{2, 2}, {4, 2}, {4, 4}, {2, 4}, {2, 2}},
+				},
+				geom.MultiPolygon{
+					{{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}, {{2, 2}, {4, 2}, {4, 4}, {2, 4}, {2, 2}}},
+					{{{3, 3}, {3, 3.5}, {3.5, 3.5}, {3.5, 3}, {3, 3}}},
+					{{{20, 0}, {20, 5}, {25, 5}, {25, 0}, {20, 0}}},
+				},
+			},
+		},
+		"collections": {
+			typ: PolyLine,
+			records: []Record{
+				{
+					Geometry: geom.Collection{
+						geom.LineString{{0, 0}, {1, 1}},
+						&geom.Collection{geom.MultiLineString{{{5, 5}, {6, 7}}}},
+					},
+					Attributes: attrs,
+				},
+				{Geometry: geom.Collection{}, Attributes: blank},
+			},
+			expected: []geom.Geometry{
+				geom.MultiLineString{{{0, 0}, {1, 1}}, {{5, 5}, {6, 7}}},
+				nil,
+			},
+		},
+		"multipoint collection": {
+			typ: MultiPoint,
+			records: []Record{
+				{Geometry: geom.Collection{geom.MultiPoint{{1, 2}}, geom.MultiPoint{{3, 4}, {5, 6}}}, Attributes: attrs},
+			},
+			expected: []geom.Geometry{geom.MultiPoint{{1, 2}, {3, 4}, {5, 6}}},
+		},
+		"polygon collection": {
+			typ: Polygon,
+			records: []Record{
+				{
+					Geometry: geom.Collection{
+						geom.Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}}},
+						geom.Collection{geom.Polygon{{{20, 0}, {20, 5}, {25, 5}, {25, 0}}}},
+					},
+					Attributes: attrs,
+				},
+			},
+			expected: []geom.Geometry{
+				geom.MultiPolygon{
+					{{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}},
+					{{{20, 0}, {20, 5}, {25, 5}, {25, 0}, {20, 0}}},
+				},
+			},
+		},
+		"polygon z": {
+			typ: PolygonZ,
+			records: []Record{
+				{
+					Geometry:   geom.PolygonZ{{{0, 0, 1}, {0, 1, 2}, {1, 1, 3}, {0, 0, 1}}},
+					Attributes: attrs,
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func binaryLen(b []byte) int32 {
+	if len(b) < 28 {
+		return -1
+	}
+	return int32(b[24])<<24 | int32(b[25])<<16 | int32(b[26])<<8 | int32(b[27])
+}
+
+func TestNoDataMeasures(t *testing.T) {
+	var shp buffer
+	w, err := NewWriter(&shp, nil, nil, PolyLineM, nil)
+	if err != nil {
+		t.Fatalf("new writer, expected nil got %v", err)
+	}
+	ls := geom.LineStringM{{0, 0, math.NaN()}, {1, 1, 2}}
+	if err := w.Write(Record{Geometry: ls}); err != nil {
+		t.Fatalf("write, expected nil got %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close, expected nil got %v", err)
+	}
+
+	r, err := NewReader(bytes.NewReader(shp.b), nil)
+	if err != nil {
+		t.Fatalf("new reader, expected nil got %v", err)
+	}
+	rec, err := r.Next()
+	if err != nil {
+		t.Fatalf("next, expected nil got %v", err)
+	}
+	got, ok := rec.Geometry.(geom.LineStringM)
+	if !ok || len(got) != 2 {
+		t.Fatalf("geometry, expected LineStringM got %v", rec.Geometry)
+	}
+	if !math.IsNaN(got[0][2]) || got[1][2] != 2 {
+		t.Errorf("measures, expected [NaN 2] got [%v %v]", got[0][2], got[1][2])
+	}
+	if rec.Attributes != nil {
+		t.Errorf("attributes, expected nil got %v", rec.Attributes)
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	type tcase struct {
+		typ  ShapeType
+		geom geom.Geometry
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var shp buffer
+			w, err := NewWriter(&shp, nil, nil, tc.typ, nil)
+			if err != nil {
+				t.Fatalf("new writer, expected nil got %v", err)
+			}
+			err = w.Write(Record{Geometry: tc.geom})
+			if _, ok := err.(ErrShapeTypeMismatch); !ok {
+				t.Errorf("error, expected ErrShapeTypeMismatch got %v", err)
+			}
+		}
+	}
+	tests := map[string]tcase{
+		"line as point":    {typ: Point, geom: geom.LineString{{0, 0}, {1, 1}}},
+		"2d as z":          {typ: PolyLineZ, geom: geom.LineString{{0, 0}, {1, 1}}},
+		"z as 2d":          {typ: PolyLine, geom: geom.LineStringZ{{0, 0, 0}, {1, 1, 1}}},
+		"zm as m":          {typ: PointM, geom: geom.PointZM{0, 0, 0, 0}},
+		"unknown geometry": {typ: Polygon, geom: geom.Extent{0, 0, 1, 1}},
+		"mixed collection": {typ: PolyLine, geom: geom.Collection{geom.LineString{{0, 0}, {1, 1}}, geom.Point{0, 0}}},
+		"points as point":  {typ: Point, geom: geom.Collection{geom.Point{0, 0}, geom.Point{1, 1}}},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	if _, err := NewWriter(&buffer{}, nil, nil, MultiPatch, nil); err == nil {
+		t.Errorf("multipatch, expected error got nil")
+	}
+	if _, err := NewWriter(&buffer{}, nil, &buffer{}, Point, []Field{{Name: "NAMETOOLONG", Type: Character, Length: 1}}); err == nil {
+		t.Errorf("field, expected error got nil")
+	}
+}
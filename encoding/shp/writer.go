@@ -0,0 +1,204 @@
+package shp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+)
+
+// Writer writes the records of a shapefile.
+type Writer struct {
+	shp, shx io.WriteSeeker
+	shpBuf   *bufio.Writer
+	shxBuf   *bufio.Writer
+	dbf      *dbfWriter
+	hdr      header
+	// offset is the position of the next record in 16-bit words
+	offset  int32
+	records int32
+	closers []io.Closer
+	closed  bool
+}
+
+// NewWriter returns a Writer of shapes of the shape type, and their
+// attributes in the fields. The .shx and .dbf files may be nil, in which
+// case they are not written. The headers are written again when the Writer
+// is closed, so the files must be seekable.
+func NewWriter(shp, shx, dbf io.WriteSeeker, typ ShapeType, fields []Field) (*Writer, error) {
+	switch typ.base() {
+	case Point, PolyLine, Polygon, MultiPoint:
+	default:
+		return nil, ErrUnsupportedShapeType{Typ: typ}
+	}
+	w := &Writer{
+		shp:    shp,
+		shx:    shx,
+		shpBuf: bufio.NewWriter(shp),
+		hdr:    header{typ: typ},
+		offset: headerLen / 2,
+	}
+	for i := range w.hdr.ext {
+		w.hdr.ext[i] = math.Inf(1 - 2*(i%2))
+	}
+	if _, err := w.shpBuf.Write(w.hdr.bytes()); err != nil {
+		return nil, err
+	}
+	if shx != nil {
+		w.shxBuf = bufio.NewWriter(shx)
+		if _, err := w.shxBuf.Write(w.hdr.bytes()); err != nil {
+			return nil, err
+		}
+	}
+	if dbf != nil {
+		var err error
+		if w.dbf, err = newDBFWriter(dbf, fields); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// Create creates the .shp, .shx and .dbf files at the path, with or without
+// the .shp extension. The Writer must be closed.
+func Create(path string, typ ShapeType, fields []Field) (*Writer, error) {
+	base := basePath(path)
+	var files []*os.File
+	closeAll := func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}
+	for _, ext := range []string{".shp", ".shx", ".dbf"} {
+		f, err := os.Create(base + ext)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	w, err := NewWriter(files[0], files[1], files[2], typ, fields)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+	for _, f := range files {
+		w.closers = append(w.closers, f)
+	}
+	return w, nil
+}
+
+// Write writes the record. The geometry must be nil or be able to be stored
+// as the shape type of the file, otherwise ErrShapeTypeMismatch is
+// returned. Z shape types take geometries with z, with or without
+// measures, and M shape types take geometries with measures. A collection
+// is written as one shape made of its geometries.
+func (w *Writer) Write(rec Record) error {
+	if w.closed {
+		return ErrClosed
+	}
+	s, err := newShape(w.hdr.typ, rec.Geometry)
+	if err != nil {
+		return err
+	}
+	content, ext := s.content()
+	length := int32(len(content) / 2)
+
+	// check the attributes before writing the shape
+	if w.dbf != nil {
+		for _, f := range w.dbf.fields {
+			if err := formatValue(f, rec.Attributes[f.Name], make([]byte, f.Length)); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.records++
+	var rh [recordHeaderLen]byte
+	binary.BigEndian.PutUint32(rh[0:], uint32(w.records))
+	binary.BigEndian.PutUint32(rh[4:], uint32(length))
+	if _, err := w.shpBuf.Write(rh[:]); err != nil {
+		return err
+	}
+	if _, err := w.shpBuf.Write(content); err != nil {
+		return err
+	}
+	if w.shxBuf != nil {
+		var idx [recordHeaderLen]byte
+		binary.BigEndian.PutUint32(idx[0:], uint32(w.offset))
+		binary.BigEndian.PutUint32(idx[4:], uint32(length))
+		if _, err := w.shxBuf.Write(idx[:]); err != nil {
+			return err
+		}
+	}
+	w.offset += recordHeaderLen/2 + length
+
+	if s.typ != Null {
+		for i := range ext {
+			if i%2 == 0 {
+				w.hdr.ext[i] = math.Min(w.hdr.ext[i], ext[i])
+			} else {
+				w.hdr.ext[i] = math.Max(w.hdr.ext[i], ext[i])
+			}
+		}
+	}
+
+	if w.dbf != nil {
+		return w.dbf.write(rec.Attributes)
+	}
+	return nil
+}
+
+// Close writes the headers with the lengths of the files and the bounding
+// box of the shapes, and closes the files opened by Create.
+func (w *Writer) Close() error {
+	if w.closed {
+		return ErrClosed
+	}
+	w.closed = true
+	err := w.close()
+	for _, c := range w.closers {
+		if e := c.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (w *Writer) close() error {
+	hdr := w.hdr
+	for i := range hdr.ext {
+		if math.IsInf(hdr.ext[i], 0) {
+			hdr.ext[i] = 0
+		}
+	}
+
+	hdr.length = w.offset
+	if err := rewriteHeader(w.shpBuf, w.shp, hdr); err != nil {
+		return err
+	}
+	if w.shx != nil {
+		hdr.length = headerLen/2 + w.records*recordHeaderLen/2
+		if err := rewriteHeader(w.shxBuf, w.shx, hdr); err != nil {
+			return err
+		}
+	}
+	if w.dbf != nil {
+		return w.dbf.close()
+	}
+	return nil
+}
+
+// rewriteHeader flushes the buffer and writes the header at the start of the
+// file
+func rewriteHeader(buf *bufio.Writer, ws io.WriteSeeker, hdr header) error {
+	if err := buf.Flush(); err != nil {
+		return err
+	}
+	if _, err := ws.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := ws.Write(hdr.bytes())
+	return err
+}
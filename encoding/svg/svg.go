@@ -0,0 +1,347 @@
+// Package svg is for drawing geometries as Scalable Vector Graphics, to
+// look at the results of triangulations, overlays and the like in a
+// browser.
+//
+// Each geometry is drawn as a path. The rings of polygons are closed
+// subpaths, filled with the evenodd rule so holes are empty, line strings
+// open subpaths, and points subpaths of no length, which are drawn as dots
+// by their round line caps. The members of collections are drawn as paths
+// of their own. Z and M values are dropped.
+//
+// SVG has y increasing going down. For coordinates with y increasing going
+// up, such as longitude and latitude, set the encoder's YUp so the drawing
+// is not upside down.
+package svg
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-spatial/geom"
+)
+
+// Style is how a geometry is drawn. Empty fields are given the defaults for
+// the kind of geometry.
+type Style struct {
+	// Fill is the fill color of polygons
+	Fill string
+	// FillOpacity is the opacity of the fill, from 0 to 1
+	FillOpacity float64
+	// Stroke is the color of lines, the edges of polygons, and points
+	Stroke string
+	// StrokeWidth is the width of lines in pixels, whatever the scale the
+	// drawing is shown at. Points are drawn as dots of twice the width.
+	StrokeWidth float64
+}
+
+// The default styles of the kinds of geometry.
+var (
+	PolygonStyle = Style{Fill: "#88aaee", FillOpacity: 0.5, Stroke: "#224488", StrokeWidth: 1}
+	LineStyle    = Style{Stroke: "#224488", StrokeWidth: 1}
+	PointStyle   = Style{Stroke: "#cc3322", StrokeWidth: 3}
+)
+
+// kind is the kind of geometry of a path
+type kind uint8
+
+const (
+	pointKind kind = iota
+	lineKind
+	polygonKind
+)
+
+func (k kind) style() Style {
+	switch k {
+	case polygonKind:
+		return PolygonStyle
+	case lineKind:
+		return LineStyle
+	default:
+		return PointStyle
+	}
+}
+
+// withDefaults returns the style with its empty fields set from def.
+func (s Style) withDefaults(def Style) Style {
+	if s.Fill == "" {
+		s.Fill = def.Fill
+	}
+	if s.FillOpacity == 0 {
+		s.FillOpacity = def.FillOpacity
+	}
+	if s.Stroke == "" {
+		s.Stroke = def.Stroke
+	}
+	if s.StrokeWidth == 0 {
+		s.StrokeWidth = def.StrokeWidth
+	}
+	return s
+}
+
+func formatFloat(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+
+// pathWriter writes the path data of geometries
+type pathWriter struct {
+	buf bytes.Buffer
+	yUp bool
+}
+
+func (p *pathWriter) point(cmd byte, pt [2]float64) {
+	if p.buf.Len() > 0 {
+		p.buf.WriteByte(' ')
+	}
+	y := pt[1]
+	if p.yUp {
+		// not -y, which writes 0 as -0
+		y = 0 - y
+	}
+	p.buf.WriteByte(cmd)
+	p.buf.WriteString(formatFloat(pt[0]))
+	p.buf.WriteByte(' ')
+	p.buf.WriteString(formatFloat(y))
+}
+
+func (p *pathWriter) line(pts [][2]float64, closed bool) {
+	for i, pt := range pts {
+		cmd := byte('L')
+		if i == 0 {
+			cmd = 'M'
+		}
+		p.point(cmd, pt)
+	}
+	if closed && len(pts) > 0 {
+		p.buf.WriteString(" Z")
+	}
+}
+
+func (p *pathWriter) dot(pt [2]float64) {
+	p.point('M', pt)
+	p.buf.WriteString(" Z")
+}
+
+// xy returns the 2D geometry of the Z, M and ZM geometries, ok is false
+// for other geometries.
+func xy(g geom.Geometry) (_ geom.Geometry, ok bool) {
+	switch gg := g.(type) {
+	case interface{ Point() geom.Point }:
+		return gg.Point(), true
+	case interface{ MultiPoint() geom.MultiPoint }:
+		return gg.MultiPoint(), true
+	case interface{ LineString() geom.LineString }:
+		return gg.LineString(), true
+	case interface{ MultiLineString() geom.MultiLineString }:
+		return gg.MultiLineString(), true
+	case interface{ Polygon() geom.Polygon }:
+		return gg.Polygon(), true
+	case interface{ MultiPolygon() geom.MultiPolygon }:
+		return gg.MultiPolygon(), true
+	default:
+		return g, false
+	}
+}
+
+// geometry writes the path data of the geometry, which is not a
+// collection, and returns its kind.
+func (p *pathWriter) geometry(g geom.Geometry) (kind, error) {
+	switch gg := g.(type) {
+	case geom.Pointer:
+		p.dot(gg.XY())
+		return pointKind, nil
+	case geom.MultiPointer:
+		for _, pt := range gg.Points() {
+			p.dot(pt)
+		}
+		return pointKind, nil
+	case geom.LineStringer:
+		p.line(gg.Vertices(), false)
+		return lineKind, nil
+	case geom.MultiLineStringer:
+		for _, ln := range gg.LineStrings() {
+			p.line(ln, false)
+		}
+		return lineKind, nil
+	case geom.Polygoner:
+		for _, ring := range gg.LinearRings() {
+			p.line(ring, true)
+		}
+		return polygonKind, nil
+	case geom.MultiPolygoner:
+		for _, ply := range gg.Polygons() {
+			for _, ring := range ply {
+				p.line(ring, true)
+			}
+		}
+		return polygonKind, nil
+	}
+	if x, ok := xy(g); ok {
+		return p.geometry(x)
+	}
+	return 0, geom.ErrUnknownGeometry{Geom: g}
+}
+
+// PathData returns the path data, the d attribute of a path element, of
+// the geometry. Collections are drawn as one path. If yUp is true the y
+// values are negated, so the geometry is the right way up.
+func PathData(g geom.Geometry, yUp bool) (string, error) {
+	p := pathWriter{yUp: yUp}
+	err := walkMembers(g, func(m geom.Geometry) error {
+		_, err := p.geometry(m)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return p.buf.String(), nil
+}
+
+// Encoder writes geometries as an SVG document.
+type Encoder struct {
+	w      io.Writer
+	extent *geom.Extent
+	yUp    bool
+	width  float64
+	style  func(g geom.Geometry) Style
+}
+
+// NewEncoder returns an Encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// SetExtent sets the extent shown, the viewBox of the document. If it is
+// nil, the extent of the geometries encoded with a margin is shown.
+func (enc *Encoder) SetExtent(extent *geom.Extent) { enc.extent = extent }
+
+// SetYUp sets whether y increases going up, in which case the geometries
+// are flipped to be the right way up.
+func (enc *Encoder) SetYUp(yUp bool) { enc.yUp = yUp }
+
+// SetWidth sets the width of the document in pixels, the height being that
+// of the extent at the same scale. If it is 0, which is the default, the
+// document has no size and fills where it is shown.
+func (enc *Encoder) SetWidth(width float64) { enc.width = width }
+
+// SetStyle sets the function that gives the style of each geometry, or
+// member of a collection, drawn. The empty fields of the style it returns
+// are given the defaults for the kind of geometry.
+func (enc *Encoder) SetStyle(fn func(g geom.Geometry) Style) { enc.style = fn }
+
+// viewBox returns the extent of the geometries, with a margin of a
+// twentieth of its size, or of 1 if it has no size.
+func viewBox(geoms []geom.Geometry) *geom.Extent {
+	ext := geom.NewEmptyExtent()
+	for _, g := range geoms {
+		// geometries that can not be drawn are found when they are
+		ext.AddGeometry(g)
+	}
+	if ext.IsEmpty() {
+		return &geom.Extent{0, 0, 1, 1}
+	}
+	m := ext.XSpan()
+	if ext.YSpan() > m {
+		m = ext.YSpan()
+	}
+	m /= 20
+	if m == 0 {
+		m = 1
+	}
+	return ext.ExpandBy(m)
+}
+
+// Encode writes an SVG document of the geometries, drawn in order.
+func (enc *Encoder) Encode(geoms ...geom.Geometry) error {
+	ext := enc.extent
+	if ext == nil {
+		ext = viewBox(geoms)
+	}
+	miny := ext.MinY()
+	if enc.yUp {
+		miny = 0 - ext.MaxY()
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="`)
+	buf.WriteString(formatFloat(ext.MinX()) + " " + formatFloat(miny) + " " +
+		formatFloat(ext.XSpan()) + " " + formatFloat(ext.YSpan()) + `"`)
+	if enc.width > 0 && ext.XSpan() > 0 {
+		buf.WriteString(` width="` + formatFloat(enc.width) +
+			`" height="` + formatFloat(enc.width*ext.YSpan()/ext.XSpan()) + `"`)
+	}
+	buf.WriteString(">\n")
+
+	for _, g := range geoms {
+		// each member of a collection is a path of its own
+		err := walkMembers(g, func(m geom.Geometry) error {
+			p := pathWriter{yUp: enc.yUp}
+			k, err := p.geometry(m)
+			if err != nil {
+				return err
+			}
+			var s Style
+			if enc.style != nil {
+				s = enc.style(m)
+			}
+			writePath(&buf, p.buf.String(), k, s.withDefaults(k.style()))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	buf.WriteString("</svg>\n")
+	_, err := enc.w.Write(buf.Bytes())
+	return err
+}
+
+// walkMembers calls fn with each of the geometries of the collections, or
+// the geometry if it is not a collection.
+func walkMembers(g geom.Geometry, fn func(g geom.Geometry) error) error {
+	col, ok := g.(geom.Collectioner)
+	if !ok {
+		return fn(g)
+	}
+	for _, m := range col.Geometries() {
+		if err := walkMembers(m, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attrEscaper escapes the values of attributes
+var attrEscaper = strings.NewReplacer(`&`, "&amp;", `<`, "&lt;", `>`, "&gt;", `"`, "&quot;")
+
+func writePath(buf *bytes.Buffer, d string, k kind, s Style) {
+	buf.WriteString(`<path d="` + d + `"`)
+	switch k {
+	case polygonKind:
+		buf.WriteString(` fill="` + attrEscaper.Replace(s.Fill) + `" fill-opacity="` + formatFloat(s.FillOpacity) + `" fill-rule="evenodd"`)
+	default:
+		buf.WriteString(` fill="none"`)
+	}
+	width := s.StrokeWidth
+	if k == pointKind {
+		width *= 2
+		buf.WriteString(` stroke-linecap="round"`)
+	}
+	buf.WriteString(` stroke="` + attrEscaper.Replace(s.Stroke) + `" stroke-width="` + formatFloat(width) +
+		`" vector-effect="non-scaling-stroke"/>` + "\n")
+}
+
+// Encode writes an SVG document of the geometries, with y increasing going
+// down.
+func Encode(w io.Writer, geoms ...geom.Geometry) error {
+	return NewEncoder(w).Encode(geoms...)
+}
+
+// EncodeString returns an SVG document of the geometries, with y
+// increasing going down.
+func EncodeString(geoms ...geom.Geometry) (string, error) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, geoms...); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
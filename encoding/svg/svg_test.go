@@ -0,0 +1,105 @@
+package svg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestPathData(t *testing.T) {
+	type tcase struct {
+		g   geom.Geometry
+		yUp bool
+		exp string
+		err error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := PathData(tc.g, tc.yUp)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if got != tc.exp {
+				t.Errorf("path data, expected %q got %q", tc.exp, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			g:   geom.Point{1, 2.5},
+			exp: "M1 2.5 Z",
+		},
+		"linestring": {
+			g:   geom.LineString{{0, 0}, {10, 0}, {10, 10}},
+			exp: "M0 0 L10 0 L10 10",
+		},
+		"polygon with hole": {
+			g:   geom.Polygon{{{0, 0}, {10, 0}, {10, 10}}, {{5, 2}, {8, 2}, {8, 5}}},
+			exp: "M0 0 L10 0 L10 10 Z M5 2 L8 2 L8 5 Z",
+		},
+		"y up": {
+			g:   geom.MultiPoint{{1, 2}, {3, -4}},
+			yUp: true,
+			exp: "M1 -2 Z M3 4 Z",
+		},
+		"z": {
+			g:   geom.LineStringZ{{0, 0, 5}, {1, 1, 5}},
+			exp: "M0 0 L1 1",
+		},
+		"collection": {
+			g:   geom.Collection{geom.Point{1, 1}, geom.LineString{{0, 0}, {1, 0}}},
+			exp: "M1 1 Z M0 0 L1 0",
+		},
+		"unknown": {
+			g:   "POINT (1 2)",
+			err: geom.ErrUnknownGeometry{Geom: "POINT (1 2)"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestEncode(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.SetYUp(true)
+	enc.SetWidth(200)
+	enc.SetStyle(func(g geom.Geometry) Style {
+		if _, ok := g.(geom.Point); ok {
+			return Style{Stroke: "red"}
+		}
+		return Style{}
+	})
+	err := enc.Encode(
+		geom.Polygon{{{0, 0}, {100, 0}, {100, 50}, {0, 50}}},
+		geom.Collection{geom.Point{50, 25}, geom.LineString{{0, 0}, {100, 50}}},
+	)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	exp := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="-5 -55 110 60" width="200" height="109.0909090909091">
+<path d="M0 0 L100 0 L100 -50 L0 -50 Z" fill="#88aaee" fill-opacity="0.5" fill-rule="evenodd" stroke="#224488" stroke-width="1" vector-effect="non-scaling-stroke"/>
+<path d="M50 -25 Z" fill="none" stroke-linecap="round" stroke="red" stroke-width="6" vector-effect="non-scaling-stroke"/>
+<path d="M0 0 L100 -50" fill="none" stroke="#224488" stroke-width="1" vector-effect="non-scaling-stroke"/>
+</svg>
+`
+	if got := buf.String(); got != exp {
+		t.Errorf("svg, expected\n%v\ngot\n%v", exp, got)
+	}
+
+	// a single point is shown with a margin
+	s, err := EncodeString(geom.Point{3, 4})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if !strings.Contains(s, `viewBox="2 3 2 2"`) {
+		t.Errorf("view box, expected 2 3 2 2 got %v", s)
+	}
+}
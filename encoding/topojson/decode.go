@@ -0,0 +1,190 @@
+package topojson
+
+import (
+	"encoding/json"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/geojson"
+)
+
+type decoder struct {
+	transform *Transform
+	// arcs are the coordinates of the arcs, after the transform
+	arcs [][][2]float64
+}
+
+func decodeTopology(topo *topology) (map[string]geojson.FeatureCollection, error) {
+	if topo.Type != topologyType {
+		return nil, ErrInvalidTopology
+	}
+	dec := decoder{transform: topo.Transform, arcs: make([][][2]float64, len(topo.Arcs))}
+	for i, arc := range topo.Arcs {
+		pts := make([][2]float64, len(arc))
+		var x, y float64
+		for j, q := range arc {
+			if topo.Transform != nil {
+				// the positions of quantized arcs are delta encoded
+				x, y = x+q[0], y+q[1]
+				q = [2]float64{x, y}
+			}
+			pts[j] = topo.Transform.position(q)
+		}
+		dec.arcs[i] = pts
+	}
+
+	objects := make(map[string]geojson.FeatureCollection, len(topo.Objects))
+	for name, obj := range topo.Objects {
+		if obj == nil {
+			return nil, ErrInvalidTopology
+		}
+		var fc geojson.FeatureCollection
+		members := []*geometryObject{obj}
+		if obj.Type == geometryCollectionType && obj.ID == nil && obj.Properties == nil {
+			// the usual object, with a geometry for each feature
+			members = obj.Geometries
+		}
+		for _, m := range members {
+			if m == nil {
+				return nil, ErrInvalidTopology
+			}
+			g, err := dec.geometry(m)
+			if err != nil {
+				return nil, err
+			}
+			f := geojson.Feature{
+				BBox:       m.BBox,
+				Geometry:   geojson.Geometry{Geometry: g},
+				Properties: m.Properties,
+			}
+			f.SetID(m.ID)
+			fc.Features = append(fc.Features, f)
+		}
+		objects[name] = fc
+	}
+	return objects, nil
+}
+
+// line returns the points of the arcs joined into a line. Negative
+// indexes are the ones complement of the index of an arc to reverse.
+func (dec *decoder) line(refs []int) ([][2]float64, error) {
+	var pts [][2]float64
+	for _, ref := range refs {
+		i := ref
+		if ref < 0 {
+			i = ^ref
+		}
+		if i >= len(dec.arcs) {
+			return nil, ErrInvalidArc
+		}
+		arc := dec.arcs[i]
+		if ref < 0 {
+			arc = reverse(arc)
+		}
+		if len(pts) > 0 && len(arc) > 0 {
+			// the arcs share their ends
+			arc = arc[1:]
+		}
+		pts = append(pts, arc...)
+	}
+	return pts, nil
+}
+
+func (dec *decoder) lines(refs [][]int) ([][][2]float64, error) {
+	lns := make([][][2]float64, len(refs))
+	for i := range refs {
+		var err error
+		if lns[i], err = dec.line(refs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return lns, nil
+}
+
+func (dec *decoder) geometry(obj *geometryObject) (geom.Geometry, error) {
+	switch obj.Type {
+	case "Point":
+		var pt [2]float64
+		if err := json.Unmarshal(obj.Coordinates, &pt); err != nil {
+			return nil, err
+		}
+		return geom.Point(dec.transform.position(pt)), nil
+
+	case "MultiPoint":
+		var pts [][2]float64
+		if err := json.Unmarshal(obj.Coordinates, &pts); err != nil {
+			return nil, err
+		}
+		for i := range pts {
+			pts[i] = dec.transform.position(pts[i])
+		}
+		return geom.MultiPoint(pts), nil
+
+	case "LineString":
+		var refs []int
+		if err := json.Unmarshal(obj.Arcs, &refs); err != nil {
+			return nil, err
+		}
+		ln, err := dec.line(refs)
+		return geom.LineString(ln), err
+
+	case "MultiLineString":
+		var refs [][]int
+		if err := json.Unmarshal(obj.Arcs, &refs); err != nil {
+			return nil, err
+		}
+		lns, err := dec.lines(refs)
+		return geom.MultiLineString(lns), err
+
+	case "Polygon":
+		var refs [][]int
+		if err := json.Unmarshal(obj.Arcs, &refs); err != nil {
+			return nil, err
+		}
+		rings, err := dec.lines(refs)
+		return geom.Polygon(rings), err
+
+	case "MultiPolygon":
+		var refs [][][]int
+		if err := json.Unmarshal(obj.Arcs, &refs); err != nil {
+			return nil, err
+		}
+		mply := make(geom.MultiPolygon, len(refs))
+		for i := range refs {
+			rings, err := dec.lines(refs[i])
+			if err != nil {
+				return nil, err
+			}
+			mply[i] = rings
+		}
+		return mply, nil
+
+	case geometryCollectionType:
+		col := make(geom.Collection, len(obj.Geometries))
+		for i, m := range obj.Geometries {
+			if m == nil {
+				return nil, ErrInvalidTopology
+			}
+			g, err := dec.geometry(m)
+			if err != nil {
+				return nil, err
+			}
+			col[i] = g
+		}
+		return col, nil
+
+	case "":
+		// a feature without a geometry
+		return nil, nil
+
+	default:
+		return nil, ErrInvalidTopology
+	}
+}
+
+func reverse(pts [][2]float64) [][2]float64 {
+	ret := make([][2]float64, len(pts))
+	for i, pt := range pts {
+		ret[len(pts)-1-i] = pt
+	}
+	return ret
+}
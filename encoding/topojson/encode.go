@@ -0,0 +1,363 @@
+package topojson
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/geojson"
+)
+
+// arcKey identifies an arc by its first edge, last point and number of
+// points
+type arcKey struct {
+	first, second, last [2]float64
+	n                   int
+}
+
+type encoder struct {
+	transform *Transform
+	// nodes are the points the lines and rings are cut into arcs at
+	nodes map[[2]float64]bool
+	arcs  [][][2]float64
+	index map[arcKey]int
+}
+
+func encodeTopology(objects map[string]geojson.FeatureCollection, o encodeOptions) (*topology, error) {
+	if o.quantization != 0 && o.quantization < 2 {
+		return nil, ErrInvalidQuantization
+	}
+	enc := encoder{
+		nodes: make(map[[2]float64]bool),
+		index: make(map[arcKey]int),
+	}
+
+	ext := new(geom.Extent)
+	empty := true
+	for _, fc := range objects {
+		for _, f := range fc.Features {
+			if f.Geometry.Geometry == nil {
+				continue
+			}
+			if err := geom.Walk(f.Geometry.Geometry, func(pt [2]float64) error {
+				if empty {
+					ext, empty = geom.NewExtent(pt), false
+				} else {
+					ext.AddPoints(pt)
+				}
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	topo := &topology{
+		Type:    topologyType,
+		Objects: make(map[string]*geometryObject, len(objects)),
+		Arcs:    [][][2]float64{},
+	}
+	if !empty {
+		topo.BBox = ext[:]
+	}
+	if o.quantization != 0 && !empty {
+		scale := func(span float64) float64 {
+			if span == 0 {
+				return 1
+			}
+			return span / float64(o.quantization-1)
+		}
+		enc.transform = &Transform{
+			Scale:     [2]float64{scale(ext.XSpan()), scale(ext.YSpan())},
+			Translate: [2]float64{ext.MinX(), ext.MinY()},
+		}
+		topo.Transform = enc.transform
+	}
+
+	// the geometries in the positions they are encoded with, and the
+	// nodes of all of them
+	geoms := make(map[string][]geom.Geometry, len(objects))
+	edges := make(map[[2][2]float64]bool)
+	degree := make(map[[2]float64]int)
+	addEdge := func(a, b [2]float64) {
+		if a == b {
+			return
+		}
+		if less(b, a) {
+			a, b = b, a
+		}
+		if edges[[2][2]float64{a, b}] {
+			return
+		}
+		edges[[2][2]float64{a, b}] = true
+		degree[a]++
+		degree[b]++
+	}
+	for name, fc := range objects {
+		gs := make([]geom.Geometry, len(fc.Features))
+		for i, f := range fc.Features {
+			if f.Geometry.Geometry == nil {
+				continue
+			}
+			g, err := geom.Map(f.Geometry.Geometry, func(pt [2]float64) ([2]float64, error) {
+				return enc.quantize(pt), nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			gs[i] = g
+			walkLines(g, func(pts [][2]float64, closed bool) {
+				if len(pts) == 0 {
+					return
+				}
+				for j := 1; j < len(pts); j++ {
+					addEdge(pts[j-1], pts[j])
+				}
+				if closed {
+					addEdge(pts[len(pts)-1], pts[0])
+					return
+				}
+				enc.nodes[pts[0]] = true
+				enc.nodes[pts[len(pts)-1]] = true
+			})
+		}
+		geoms[name] = gs
+	}
+	for pt, d := range degree {
+		if d > 2 {
+			enc.nodes[pt] = true
+		}
+	}
+
+	for name, fc := range objects {
+		obj := &geometryObject{
+			Type:       geometryCollectionType,
+			Geometries: make([]*geometryObject, len(fc.Features)),
+		}
+		for i, f := range fc.Features {
+			m, err := enc.geometry(geoms[name][i])
+			if err != nil {
+				return nil, err
+			}
+			m.ID, m.Properties, m.BBox = f.IDValue(), f.Properties, f.BBox
+			obj.Geometries[i] = m
+		}
+		topo.Objects[name] = obj
+	}
+
+	for _, arc := range enc.arcs {
+		if enc.transform != nil {
+			// delta encode the positions
+			delta := make([][2]float64, len(arc))
+			var prev [2]float64
+			for i, q := range arc {
+				delta[i] = [2]float64{q[0] - prev[0], q[1] - prev[1]}
+				prev = q
+			}
+			arc = delta
+		}
+		topo.Arcs = append(topo.Arcs, arc)
+	}
+	return topo, nil
+}
+
+// quantize returns the position of the point on the grid of the
+// transform, or the point if there is no transform
+func (enc *encoder) quantize(pt [2]float64) [2]float64 {
+	t := enc.transform
+	if t == nil {
+		return pt
+	}
+	return [2]float64{
+		math.Round((pt[0] - t.Translate[0]) / t.Scale[0]),
+		math.Round((pt[1] - t.Translate[1]) / t.Scale[1]),
+	}
+}
+
+// walkLines calls fn with the rings and lines of the geometry, without
+// repeated points
+func walkLines(g geom.Geometry, fn func(pts [][2]float64, closed bool)) {
+	switch gg := g.(type) {
+	case geom.Collectioner:
+		for _, c := range gg.Geometries() {
+			walkLines(c, fn)
+		}
+	case geom.MultiPolygoner:
+		for _, ply := range gg.Polygons() {
+			for _, r := range ply {
+				fn(dedup(r, true), true)
+			}
+		}
+	case geom.Polygoner:
+		for _, r := range gg.LinearRings() {
+			fn(dedup(r, true), true)
+		}
+	case geom.MultiLineStringer:
+		for _, ls := range gg.LineStrings() {
+			fn(dedup(ls, false), false)
+		}
+	case geom.LineStringer:
+		fn(dedup(gg.Vertices(), false), false)
+	}
+}
+
+// dedup removes repeated points, and the last point of a ring if it is
+// the same as the first
+func dedup(pts [][2]float64, closed bool) [][2]float64 {
+	ret := make([][2]float64, 0, len(pts))
+	for _, pt := range pts {
+		if len(ret) > 0 && ret[len(ret)-1] == pt {
+			continue
+		}
+		ret = append(ret, pt)
+	}
+	if closed && len(ret) > 1 && ret[0] == ret[len(ret)-1] {
+		ret = ret[:len(ret)-1]
+	}
+	return ret
+}
+
+func (enc *encoder) geometry(g geom.Geometry) (*geometryObject, error) {
+	coords := func(typ geometryType, v interface{}) (*geometryObject, error) {
+		b, err := json.Marshal(v)
+		return &geometryObject{Type: typ, Coordinates: b}, err
+	}
+	arcs := func(typ geometryType, v interface{}) (*geometryObject, error) {
+		b, err := json.Marshal(v)
+		return &geometryObject{Type: typ, Arcs: b}, err
+	}
+
+	switch gg := g.(type) {
+	case nil:
+		return &geometryObject{}, nil
+
+	case geom.Pointer:
+		return coords("Point", gg.XY())
+
+	case geom.MultiPointer:
+		return coords("MultiPoint", gg.Points())
+
+	case geom.LineStringer:
+		return arcs("LineString", enc.line(gg.Vertices()))
+
+	case geom.MultiLineStringer:
+		var refs [][]int
+		for _, ls := range gg.LineStrings() {
+			refs = append(refs, enc.line(ls))
+		}
+		return arcs("MultiLineString", refs)
+
+	case geom.Polygoner:
+		return arcs("Polygon", enc.rings(gg.LinearRings()))
+
+	case geom.MultiPolygoner:
+		var refs [][][]int
+		for _, ply := range gg.Polygons() {
+			refs = append(refs, enc.rings(ply))
+		}
+		return arcs("MultiPolygon", refs)
+
+	case geom.Collectioner:
+		obj := &geometryObject{Type: geometryCollectionType}
+		for _, c := range gg.Geometries() {
+			m, err := enc.geometry(c)
+			if err != nil {
+				return nil, err
+			}
+			obj.Geometries = append(obj.Geometries, m)
+		}
+		return obj, nil
+
+	default:
+		return nil, geom.ErrUnknownGeometry{Geom: g}
+	}
+}
+
+// line returns the arcs of the line, the ends of which are nodes
+func (enc *encoder) line(ln [][2]float64) []int {
+	return enc.split(dedup(ln, false))
+}
+
+func (enc *encoder) rings(rings [][][2]float64) [][]int {
+	refs := make([][]int, len(rings))
+	for i, r := range rings {
+		refs[i] = enc.ring(dedup(r, true))
+	}
+	return refs
+}
+
+// ring returns the arcs of the unclosed ring, starting from its first
+// node. Rings without nodes start from their smallest point, so rings that
+// are shared entirely are the same arc.
+func (enc *encoder) ring(ring [][2]float64) []int {
+	if len(ring) == 0 {
+		return []int{}
+	}
+	start := -1
+	for i, pt := range ring {
+		if enc.nodes[pt] {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		start = 0
+		for i, pt := range ring {
+			if less(pt, ring[start]) {
+				start = i
+			}
+		}
+	}
+	pts := make([][2]float64, 0, len(ring)+1)
+	pts = append(pts, ring[start:]...)
+	pts = append(pts, ring[:start+1]...)
+	return enc.split(pts)
+}
+
+// split cuts the line at its nodes, returning the arcs
+func (enc *encoder) split(ln [][2]float64) []int {
+	refs := []int{}
+	if len(ln) == 1 {
+		// a line of one point is an arc of one point
+		return append(refs, enc.arc(ln))
+	}
+	start := 0
+	for i := 1; i < len(ln); i++ {
+		if i < len(ln)-1 && !enc.nodes[ln[i]] {
+			continue
+		}
+		refs = append(refs, enc.arc(ln[start:i+1]))
+		start = i
+	}
+	return refs
+}
+
+// arc returns the index of the arc of the section, adding it if it is
+// new. Arcs go the way that starts with the smaller end, so a section used
+// by two lines or rings going opposite ways is the same arc. The index of
+// an arc used backwards is the ones complement of its index.
+func (enc *encoder) arc(sec [][2]float64) int {
+	n := len(sec)
+	reversed := n > 1 && (less(sec[n-1], sec[0]) || (sec[n-1] == sec[0] && less(sec[n-2], sec[1])))
+	if reversed {
+		sec = reverse(sec)
+	}
+	key := arcKey{first: sec[0], last: sec[n-1], n: n}
+	if n > 1 {
+		key.second = sec[1]
+	}
+	i, ok := enc.index[key]
+	if !ok {
+		i = len(enc.arcs)
+		enc.index[key] = i
+		enc.arcs = append(enc.arcs, append([][2]float64(nil), sec...))
+	}
+	if reversed {
+		return ^i
+	}
+	return i
+}
+
+func less(a, b [2]float64) bool {
+	return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+}
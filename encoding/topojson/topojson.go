@@ -0,0 +1,136 @@
+// Package topojson is for encoding and decoding TopoJSON, the extension of
+// GeoJSON that encodes topology. Specification at
+// https://github.com/topojson/topojson-specification
+//
+// The lines and rings of the geometries of a topology are made of arcs,
+// which are shared between the geometries wherever they have the same
+// points, so shared boundaries are only encoded once; polygons simplified
+// with the planar/coverage package still share theirs. Coordinates may be
+// quantized to integers on a grid over the bounding box, see
+// WithQuantization, and those of the arcs delta encoded.
+//
+// The objects of a topology are decoded into geojson.FeatureCollections
+// and encoded from them. The rings of polygons are closed when decoded.
+// Only geometries of two dimensions are supported.
+package topojson
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/go-spatial/geom/encoding/geojson"
+)
+
+var (
+	// ErrInvalidTopology is returned when decoding data that is not a
+	// TopoJSON topology.
+	ErrInvalidTopology = errors.New("topojson: invalid topology")
+	// ErrInvalidArc is returned when decoding a geometry that refers to an
+	// arc the topology does not have.
+	ErrInvalidArc = errors.New("topojson: invalid arc index")
+	// ErrInvalidQuantization is returned when encoding with a quantization
+	// of less than two.
+	ErrInvalidQuantization = errors.New("topojson: invalid quantization")
+)
+
+const (
+	topologyType           = "Topology"
+	geometryCollectionType = "GeometryCollection"
+)
+
+// Transform maps the quantized positions of a topology to coordinates,
+// multiplying them by Scale then adding Translate.
+type Transform struct {
+	Scale     [2]float64 `json:"scale"`
+	Translate [2]float64 `json:"translate"`
+}
+
+// position returns the coordinate of the quantized position
+func (t *Transform) position(q [2]float64) [2]float64 {
+	if t == nil {
+		return q
+	}
+	return [2]float64{q[0]*t.Scale[0] + t.Translate[0], q[1]*t.Scale[1] + t.Translate[1]}
+}
+
+// topology is the JSON of a topology
+type topology struct {
+	Type      string                     `json:"type"`
+	BBox      []float64                  `json:"bbox,omitempty"`
+	Transform *Transform                 `json:"transform,omitempty"`
+	Objects   map[string]*geometryObject `json:"objects"`
+	Arcs      [][][2]float64             `json:"arcs"`
+}
+
+// geometryType is the type of a geometry object, which is null for
+// objects without a geometry
+type geometryType string
+
+func (t geometryType) MarshalJSON() ([]byte, error) {
+	if t == "" {
+		return []byte("null"), nil
+	}
+	return json.Marshal(string(t))
+}
+
+func (t *geometryType) UnmarshalJSON(b []byte) error {
+	var s *string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*t = ""
+	if s != nil {
+		*t = geometryType(*s)
+	}
+	return nil
+}
+
+// geometryObject is the JSON of a geometry of a topology. Arcs holds the
+// arc indexes, nested as the coordinates of the GeoJSON geometry would be.
+type geometryObject struct {
+	Type        geometryType           `json:"type"`
+	ID          interface{}            `json:"id,omitempty"`
+	Properties  map[string]interface{} `json:"properties,omitempty"`
+	BBox        []float64              `json:"bbox,omitempty"`
+	Coordinates json.RawMessage        `json:"coordinates,omitempty"`
+	Arcs        json.RawMessage        `json:"arcs,omitempty"`
+	Geometries  []*geometryObject      `json:"geometries,omitempty"`
+}
+
+// EncodeOption is an option for encoding.
+type EncodeOption func(*encodeOptions)
+
+type encodeOptions struct {
+	quantization int
+}
+
+// WithQuantization quantizes the coordinates to a grid of n by n points
+// over the bounding box of the topology, and delta encodes the arcs, which
+// makes the topology much smaller. Points that are quantized to the same
+// place are merged. n must be at least two, 1e4 or 1e5 are usual.
+func WithQuantization(n int) EncodeOption {
+	return func(o *encodeOptions) { o.quantization = n }
+}
+
+// Decode reads a topology, returning its objects by name.
+func Decode(r io.Reader) (map[string]geojson.FeatureCollection, error) {
+	var topo topology
+	if err := json.NewDecoder(r).Decode(&topo); err != nil {
+		return nil, err
+	}
+	return decodeTopology(&topo)
+}
+
+// Encode writes the topology of the objects, which are named by their key.
+func Encode(w io.Writer, objects map[string]geojson.FeatureCollection, opts ...EncodeOption) error {
+	var o encodeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	topo, err := encodeTopology(objects, o)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(topo)
+}
@@ -0,0 +1,224 @@
+package topojson
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/geojson"
+)
+
+func uint64Ptr(u uint64) *uint64 { return &u }
+
+func TestDecode(t *testing.T) {
+	type tcase struct {
+		topo string
+		exp  map[string]geojson.FeatureCollection
+		err  error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Decode(strings.NewReader(tc.topo))
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("objects, expected %v got %v", tc.exp, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		// the example of the specification
+		"example": {
+			topo: `{
+				"type": "Topology",
+				"transform": {"scale": [0.0005000500050005, 0.00010001000100010001], "translate": [100, 0]},
+				"objects": {
+					"example": {
+						"type": "GeometryCollection",
+						"geometries": [
+							{"type": "Point", "properties": {"prop0": "value0"}, "coordinates": [4000, 5000]},
+							{"type": "LineString", "properties": {"prop0": "value0", "prop1": 0}, "arcs": [0]},
+							{"type": "Polygon", "properties": {"prop0": "value0", "prop1": {"this": "that"}}, "arcs": [[-2]]}
+						]
+					}
+				},
+				"arcs": [
+					[[4000, 0], [1999, 9999], [2000, -9999], [2000, 9999]],
+					[[0, 0], [0, 9999], [2000, 0], [0, -9999], [-2000, 0]]
+				]
+			}`,
+			exp: map[string]geojson.FeatureCollection{
+				"example": {Features: []geojson.Feature{
+					{
+						Geometry:   geojson.Geometry{Geometry: geom.Point{102.000200020002, 0.5000500050005001}},
+						Properties: map[string]interface{}{"prop0": "value0"},
+					},
+					{
+						Geometry: geojson.Geometry{Geometry: geom.LineString{
+							{102.000200020002, 0}, {102.999799979998, 1},
+							{103.999899989999, 0}, {105, 1},
+						}},
+						Properties: map[string]interface{}{"prop0": "value0", "prop1": float64(0)},
+					},
+					{
+						Geometry: geojson.Geometry{Geometry: geom.Polygon{{
+							{100, 0}, {101.000100010001, 0}, {101.000100010001, 1},
+							{100, 1}, {100, 0},
+						}}},
+						Properties: map[string]interface{}{"prop0": "value0", "prop1": map[string]interface{}{"this": "that"}},
+					},
+				}},
+			},
+		},
+		"not a topology": {
+			topo: `{"type": "FeatureCollection", "features": []}`,
+			err:  ErrInvalidTopology,
+		},
+		"invalid arc": {
+			topo: `{"type": "Topology", "objects": {"a": {"type": "LineString", "arcs": [1]}}, "arcs": [[[0, 0], [1, 1]]]}`,
+			err:  ErrInvalidArc,
+		},
+		"null geometry": {
+			topo: `{"type": "Topology", "objects": {"a": {"type": null, "id": 7}}, "arcs": []}`,
+			exp: map[string]geojson.FeatureCollection{
+				"a": {Features: []geojson.Feature{{ID: uint64Ptr(7)}}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	type tcase struct {
+		objects map[string]geojson.FeatureCollection
+		opts    []EncodeOption
+		// arcs is the expected number of arcs
+		arcs int
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Encode(&buf, tc.objects, tc.opts...); err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			var topo topology
+			if err := json.Unmarshal(buf.Bytes(), &topo); err != nil {
+				t.Fatalf("unmarshal error, expected nil got %v", err)
+			}
+			if len(topo.Arcs) != tc.arcs {
+				t.Errorf("arcs, expected %v got %v", tc.arcs, len(topo.Arcs))
+			}
+
+			got, err := Decode(&buf)
+			if err != nil {
+				t.Fatalf("decode error, expected nil got %v", err)
+			}
+			for name, fc := range tc.objects {
+				gfc := got[name]
+				if len(gfc.Features) != len(fc.Features) {
+					t.Fatalf("features of %v, expected %v got %v", name, len(fc.Features), len(gfc.Features))
+				}
+				for i, f := range fc.Features {
+					g := gfc.Features[i]
+					if exp, got := normalize(f.Geometry.Geometry), normalize(g.Geometry.Geometry); !reflect.DeepEqual(got, exp) {
+						t.Errorf("geometry %v, expected %v got %v", i, exp, got)
+					}
+					// ids and properties are decoded as json does
+					exp, _ := json.Marshal([]interface{}{f.IDValue(), f.Properties})
+					got, _ := json.Marshal([]interface{}{g.IDValue(), g.Properties})
+					if string(got) != string(exp) {
+						t.Errorf("id and properties %v, expected %s got %s", i, exp, got)
+					}
+				}
+			}
+		}
+	}
+
+	// two squares sharing a side, a line along the top of both and a point
+	left := geom.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}}
+	right := geom.Polygon{{{1, 0}, {2, 0}, {2, 1}, {1, 1}}}
+	features := geojson.FeatureCollection{Features: []geojson.Feature{
+		{OtherID: "left", Geometry: geojson.Geometry{Geometry: left}, Properties: map[string]interface{}{"n": 1}},
+		{OtherID: "right", Geometry: geojson.Geometry{Geometry: geom.MultiPolygon{right}}},
+		{Geometry: geojson.Geometry{Geometry: geom.LineString{{0, 1}, {1, 1}, {2, 1}}}},
+		{Geometry: geojson.Geometry{Geometry: geom.Collection{geom.Point{0.5, 0.5}, geom.MultiPoint{{1, 0}}}}},
+		{},
+	}}
+	island := geojson.FeatureCollection{Features: []geojson.Feature{
+		// a square with a hole filled by another
+		{Geometry: geojson.Geometry{Geometry: geom.Polygon{
+			{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+			{{2, 2}, {2, 8}, {8, 8}, {8, 2}},
+		}}},
+		{Geometry: geojson.Geometry{Geometry: geom.Polygon{{{2, 2}, {8, 2}, {8, 8}, {2, 8}}}}},
+	}}
+
+	tests := map[string]tcase{
+		// the shared side, the tops, and the rest of each square
+		"shared": {
+			objects: map[string]geojson.FeatureCollection{"squares": features},
+			arcs:    5,
+		},
+		"quantized": {
+			objects: map[string]geojson.FeatureCollection{"squares": features},
+			opts:    []EncodeOption{WithQuantization(5)},
+			arcs:    5,
+		},
+		"island": {
+			objects: map[string]geojson.FeatureCollection{"island": island},
+			arcs:    2,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+// normalize returns the geometry with its rings unclosed and starting
+// from their smallest point, as rings may start from any of their points
+func normalize(g geom.Geometry) geom.Geometry {
+	rings := func(ply [][][2]float64) geom.Polygon {
+		ret := make(geom.Polygon, len(ply))
+		for i, r := range ply {
+			r = dedup(r, true)
+			start := 0
+			for j := range r {
+				if less(r[j], r[start]) {
+					start = j
+				}
+			}
+			ret[i] = append(append([][2]float64(nil), r[start:]...), r[:start]...)
+		}
+		return ret
+	}
+	switch gg := g.(type) {
+	case geom.Polygon:
+		return rings(gg)
+	case geom.MultiPolygon:
+		ret := make(geom.MultiPolygon, len(gg))
+		for i := range gg {
+			ret[i] = rings(gg[i])
+		}
+		return ret
+	default:
+		return g
+	}
+}
+
+func TestEncodeQuantization(t *testing.T) {
+	err := Encode(&bytes.Buffer{}, nil, WithQuantization(1))
+	if err != ErrInvalidQuantization {
+		t.Errorf("error, expected %v got %v", ErrInvalidQuantization, err)
+	}
+}
@@ -0,0 +1,220 @@
+package twkb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// maxPrealloc is the largest number of parts allocated before they are
+// read, so a corrupt count can not exhaust memory
+const maxPrealloc = 1 << 12
+
+// Decoder reads TWKB geometries from an input stream.
+type Decoder struct {
+	r io.ByteReader
+}
+
+// NewDecoder returns a decoder that reads from r. If r is not an
+// io.ByteReader the decoder buffers its reads, and may read data from r
+// beyond the geometries requested.
+func NewDecoder(r io.Reader) *Decoder {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &Decoder{r: br}
+}
+
+// Decode reads the next geometry from the stream. At the end of the stream
+// it returns io.EOF; a stream that ends in the middle of a geometry returns
+// io.ErrUnexpectedEOF. ID lists of multi geometries are skipped.
+func (d *Decoder) Decode() (geom.Geometry, error) {
+	s, err := d.readShape()
+	if err != nil {
+		return nil, err
+	}
+	return s.geometry(), nil
+}
+
+// readShape reads a geometry, starting at its header
+func (d *Decoder) readShape() (s shape, err error) {
+	header, err := d.r.ReadByte()
+	if err != nil {
+		return s, err
+	}
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+
+	s.typ = header & 0x0f
+	if s.typ < Point || s.typ > Collection {
+		return s, ErrUnknownGeometryType{Typ: s.typ}
+	}
+	precision := unzigzag4(header >> 4)
+
+	flags, err := d.r.ReadByte()
+	if err != nil {
+		return s, err
+	}
+	var zPrecision, mPrecision int
+	if flags&flagExtended != 0 {
+		ext, err := d.r.ReadByte()
+		if err != nil {
+			return s, err
+		}
+		s.hasZ, s.hasM = ext&1 != 0, ext&2 != 0
+		zPrecision, mPrecision = int(ext>>2&7), int(ext>>5)
+	}
+	if flags&flagSize != 0 {
+		if _, err = binary.ReadUvarint(d.r); err != nil {
+			return s, err
+		}
+	}
+	if flags&flagEmpty != 0 {
+		return s, nil
+	}
+	if flags&flagBBox != 0 {
+		for i := 0; i < 2*s.ordinates(); i++ {
+			if _, err = binary.ReadVarint(d.r); err != nil {
+				return s, err
+			}
+		}
+	}
+
+	sr := shapeReader{r: d.r, precisions: []int{precision, precision}}
+	if s.hasZ {
+		sr.precisions = append(sr.precisions, zPrecision)
+	}
+	if s.hasM {
+		sr.precisions = append(sr.precisions, mPrecision)
+	}
+
+	switch s.typ {
+	case Point:
+		s.pts, err = sr.points(1)
+		return s, err
+	case LineString:
+		s.pts, err = sr.countedPoints()
+		return s, err
+	case Polygon:
+		s.lines, err = sr.lines()
+		return s, err
+	}
+
+	// the multi geometries and collections may have an id list
+	n, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return s, err
+	}
+	if flags&flagIDList != 0 {
+		for i := uint64(0); i < n; i++ {
+			if _, err = binary.ReadVarint(d.r); err != nil {
+				return s, err
+			}
+		}
+	}
+
+	switch s.typ {
+	case MultiPoint:
+		s.pts, err = sr.points(n)
+	case MultiLineString:
+		s.lines = make([][][]float64, 0, prealloc(n))
+		for i := uint64(0); i < n && err == nil; i++ {
+			var ln [][]float64
+			ln, err = sr.countedPoints()
+			s.lines = append(s.lines, ln)
+		}
+	case MultiPolygon:
+		s.polys = make([][][][]float64, 0, prealloc(n))
+		for i := uint64(0); i < n && err == nil; i++ {
+			var ply [][][]float64
+			ply, err = sr.lines()
+			s.polys = append(s.polys, ply)
+		}
+	case Collection:
+		s.geoms = make([]geom.Geometry, 0, prealloc(n))
+		for i := uint64(0); i < n && err == nil; i++ {
+			var ms shape
+			if ms, err = d.readShape(); err == nil {
+				s.geoms = append(s.geoms, ms.geometry())
+			}
+		}
+	}
+	return s, err
+}
+
+// shapeReader reads the body of a shape, each coordinate a delta from the
+// one before
+type shapeReader struct {
+	r          io.ByteReader
+	precisions []int
+	prev       [4]int64
+}
+
+func (sr *shapeReader) points(n uint64) ([][]float64, error) {
+	pts := make([][]float64, 0, prealloc(n))
+	for i := uint64(0); i < n; i++ {
+		pt := make([]float64, len(sr.precisions))
+		for j, p := range sr.precisions {
+			d, err := binary.ReadVarint(sr.r)
+			if err != nil {
+				return pts, err
+			}
+			sr.prev[j] += d
+			pt[j] = unscale(sr.prev[j], p)
+		}
+		pts = append(pts, pt)
+	}
+	return pts, nil
+}
+
+func (sr *shapeReader) countedPoints() ([][]float64, error) {
+	n, err := binary.ReadUvarint(sr.r)
+	if err != nil {
+		return nil, err
+	}
+	return sr.points(n)
+}
+
+func (sr *shapeReader) lines() ([][][]float64, error) {
+	n, err := binary.ReadUvarint(sr.r)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([][][]float64, 0, prealloc(n))
+	for i := uint64(0); i < n; i++ {
+		ln, err := sr.countedPoints()
+		if err != nil {
+			return lines, err
+		}
+		lines = append(lines, ln)
+	}
+	return lines, nil
+}
+
+func prealloc(n uint64) uint64 {
+	if n > maxPrealloc {
+		return maxPrealloc
+	}
+	return n
+}
+
+// unscale returns the value of v stored with the precision. Dividing by
+// the exact power of ten gives the closest float to the decimal value.
+func unscale(v int64, precision int) float64 {
+	if precision >= 0 {
+		return float64(v) / math.Pow10(precision)
+	}
+	return float64(v) * math.Pow10(-precision)
+}
+
+// unzigzag4 decodes the precision in the four bits of the header
+func unzigzag4(b byte) int {
+	return int(b>>1) ^ -int(b&1)
+}
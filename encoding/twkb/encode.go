@@ -0,0 +1,328 @@
+package twkb
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// maxValue is the largest scaled ordinate that is encoded, so the deltas
+// between ordinates can not overflow
+const maxValue = 1 << 62
+
+// Encoder writes geometries as TWKB to an output stream.
+type Encoder struct {
+	w io.Writer
+	// precision of the x and y, z and m values
+	precision  int
+	zPrecision int
+	mPrecision int
+	bbox       bool
+	size       bool
+}
+
+// NewEncoder returns an encoder that writes to w. The coordinates are
+// rounded to whole numbers, and the bounding box and size headers are not
+// written, until changed with the Set methods.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// SetPrecision sets the number of decimal places kept of the x and y
+// values, and of the z and m values. The xy precision may be negative
+// to round to tens, hundreds, etc. ErrInvalidPrecision is returned if xy
+// is not between MinPrecision and MaxPrecision, or z or m is not between
+// zero and MaxZMPrecision.
+func (enc *Encoder) SetPrecision(xy, z, m int) error {
+	if xy < MinPrecision || xy > MaxPrecision ||
+		z < 0 || z > MaxZMPrecision ||
+		m < 0 || m > MaxZMPrecision {
+		return ErrInvalidPrecision
+	}
+	enc.precision, enc.zPrecision, enc.mPrecision = xy, z, m
+	return nil
+}
+
+// SetBBox sets whether the bounding box of the geometries is written.
+func (enc *Encoder) SetBBox(on bool) { enc.bbox = on }
+
+// SetSize sets whether the size, in bytes, of the geometries is written,
+// which allows readers to skip over them.
+func (enc *Encoder) SetSize(on bool) { enc.size = on }
+
+// Encode writes the TWKB encoding of the geometry.
+func (enc *Encoder) Encode(g geom.Geometry) error {
+	s, err := newShape(g)
+	if err != nil {
+		return err
+	}
+	buf, _, err := enc.appendShape(nil, s)
+	if err != nil {
+		return err
+	}
+	_, err = enc.w.Write(buf)
+	return err
+}
+
+// appendShape appends the encoding of the shape to buf. The returned
+// bounding box holds the minimum and maximum scaled x, y, z and m values,
+// it is nil if the shape has no coordinates.
+func (enc *Encoder) appendShape(buf []byte, s shape) ([]byte, *bbox, error) {
+	sw := shapeWriter{slots: slots(s)}
+	for _, slot := range sw.slots {
+		sw.scales = append(sw.scales, math.Pow10(enc.slotPrecision(slot)))
+	}
+	var err error
+	switch s.typ {
+	case Point:
+		err = sw.points(s.pts, false)
+	case LineString:
+		err = sw.points(s.pts, true)
+	case MultiPoint:
+		err = sw.points(s.pts, true)
+	case Polygon:
+		err = sw.rings(s.lines)
+	case MultiLineString:
+		err = sw.lines(s.lines)
+	case MultiPolygon:
+		sw.uvarint(uint64(len(s.polys)))
+		for _, ply := range s.polys {
+			if err = sw.rings(ply); err != nil {
+				break
+			}
+		}
+	case Collection:
+		sw.uvarint(uint64(len(s.geoms)))
+		for _, g := range s.geoms {
+			var ms shape
+			if ms, err = newShape(g); err != nil {
+				break
+			}
+			var mb *bbox
+			if sw.body, mb, err = enc.appendShape(sw.body, ms); err != nil {
+				break
+			}
+			sw.extend(mb)
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// header: the type and the precision
+	buf = append(buf, s.typ|zigzag4(enc.precision)<<4)
+
+	var flags byte
+	empty := s.isEmpty()
+	switch {
+	case empty:
+		flags |= flagEmpty
+	case enc.bbox && sw.bbox != nil:
+		flags |= flagBBox
+	}
+	if enc.size {
+		flags |= flagSize
+	}
+	if s.hasZ || s.hasM {
+		flags |= flagExtended
+	}
+	buf = append(buf, flags)
+
+	if flags&flagExtended != 0 {
+		var ext byte
+		if s.hasZ {
+			ext |= 1 | byte(enc.zPrecision)<<2
+		}
+		if s.hasM {
+			ext |= 2 | byte(enc.mPrecision)<<5
+		}
+		buf = append(buf, ext)
+	}
+
+	var head []byte
+	if flags&flagBBox != 0 {
+		for _, slot := range sw.slots {
+			head = appendVarint(head, sw.bbox.min[slot])
+			head = appendVarint(head, sw.bbox.max[slot]-sw.bbox.min[slot])
+		}
+	}
+	if empty {
+		sw.body = nil
+	}
+	if enc.size {
+		buf = appendUvarint(buf, uint64(len(head)+len(sw.body)))
+	}
+	buf = append(buf, head...)
+	buf = append(buf, sw.body...)
+
+	if empty {
+		return buf, nil, nil
+	}
+	return buf, sw.bbox, nil
+}
+
+// slotPrecision returns the precision of the values in the slot
+func (enc *Encoder) slotPrecision(slot int) int {
+	switch slot {
+	case slotZ:
+		return enc.zPrecision
+	case slotM:
+		return enc.mPrecision
+	default:
+		return enc.precision
+	}
+}
+
+// the slots of the x, y, z and m values
+const (
+	slotX = iota
+	slotY
+	slotZ
+	slotM
+)
+
+// slots returns the slot of each ordinate of the coordinates of the shape
+func slots(s shape) []int {
+	sl := []int{slotX, slotY}
+	if s.hasZ {
+		sl = append(sl, slotZ)
+	}
+	if s.hasM {
+		sl = append(sl, slotM)
+	}
+	return sl
+}
+
+// bbox is the range of the scaled values of each slot
+type bbox struct {
+	min, max [4]int64
+}
+
+// shapeWriter writes the body of a shape, delta encoding each coordinate
+// from the one before
+type shapeWriter struct {
+	slots  []int
+	scales []float64
+	prev   [4]int64
+	bbox   *bbox
+	body   []byte
+}
+
+func (sw *shapeWriter) uvarint(v uint64) {
+	sw.body = appendUvarint(sw.body, v)
+}
+
+// extend grows the bounding box to include b
+func (sw *shapeWriter) extend(b *bbox) {
+	if b == nil {
+		return
+	}
+	if sw.bbox == nil {
+		bb := *b
+		sw.bbox = &bb
+		return
+	}
+	for _, slot := range sw.slots {
+		if b.min[slot] < sw.bbox.min[slot] {
+			sw.bbox.min[slot] = b.min[slot]
+		}
+		if b.max[slot] > sw.bbox.max[slot] {
+			sw.bbox.max[slot] = b.max[slot]
+		}
+	}
+}
+
+// points writes the coordinates, preceded by their count if count is true
+func (sw *shapeWriter) points(pts [][]float64, count bool) error {
+	if count {
+		sw.uvarint(uint64(len(pts)))
+	}
+	for _, pt := range pts {
+		if err := sw.point(pt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sw *shapeWriter) point(pt []float64) error {
+	var v [4]int64
+	for i, slot := range sw.slots {
+		f := math.Round(pt[i] * sw.scales[i])
+		if math.IsNaN(f) || math.Abs(f) > maxValue {
+			return ErrInvalidCoordinate
+		}
+		v[slot] = int64(f)
+	}
+	sw.extend(&bbox{min: v, max: v})
+	for _, slot := range sw.slots {
+		sw.body = appendVarint(sw.body, v[slot]-sw.prev[slot])
+	}
+	sw.prev = v
+	return nil
+}
+
+func (sw *shapeWriter) lines(lines [][][]float64) error {
+	sw.uvarint(uint64(len(lines)))
+	for _, ln := range lines {
+		if err := sw.points(ln, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rings writes the rings of a polygon, closing them if needed
+func (sw *shapeWriter) rings(rings [][][]float64) error {
+	sw.uvarint(uint64(len(rings)))
+	for _, r := range rings {
+		closed := len(r) == 0 || float64sEqual(r[0], r[len(r)-1])
+		if closed {
+			if err := sw.points(r, true); err != nil {
+				return err
+			}
+			continue
+		}
+		sw.uvarint(uint64(len(r) + 1))
+		if err := sw.points(r, false); err != nil {
+			return err
+		}
+		if err := sw.point(r[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func float64sEqual(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// zigzag4 returns the zig-zag encoding of the precision in the four bits
+// of the header
+func zigzag4(p int) byte {
+	return byte((p<<1)^(p>>31)) & 0x0f
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	return append(buf, tmp[:n]...)
+}
+
+// appendVarint appends the zig-zag varint encoding of v
+func appendVarint(buf []byte, v int64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(tmp[:], v)
+	return append(buf, tmp[:n]...)
+}
@@ -0,0 +1,463 @@
+package twkb
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// shape is a geometry with its coordinates as slices of two to four
+// ordinates: x, y, then z and m if the geometry has them.
+type shape struct {
+	typ        byte
+	hasZ, hasM bool
+
+	// pts are the coordinates of a Point, LineString or MultiPoint. An empty
+	// point has no coordinates.
+	pts [][]float64
+	// lines are the rings of a Polygon or the lines of a MultiLineString
+	lines [][][]float64
+	// polys are the polygons of a MultiPolygon
+	polys [][][][]float64
+	// geoms are the geometries of a Collection
+	geoms []geom.Geometry
+}
+
+// ordinates returns the number of values in each coordinate
+func (s shape) ordinates() int {
+	n := 2
+	if s.hasZ {
+		n++
+	}
+	if s.hasM {
+		n++
+	}
+	return n
+}
+
+// isEmpty reports whether the shape has no parts
+func (s shape) isEmpty() bool {
+	switch s.typ {
+	case Point, LineString, MultiPoint:
+		return len(s.pts) == 0
+	case Polygon, MultiLineString:
+		return len(s.lines) == 0
+	case MultiPolygon:
+		return len(s.polys) == 0
+	default:
+		return len(s.geoms) == 0
+	}
+}
+
+func isNaNCoord(c []float64) bool {
+	for _, v := range c {
+		if math.IsNaN(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// pointShape returns the shape of a point, a point with NaN ordinates is
+// empty
+func pointShape(c []float64, hasZ, hasM bool) shape {
+	s := shape{typ: Point, hasZ: hasZ, hasM: hasM}
+	if !isNaNCoord(c) {
+		s.pts = [][]float64{c}
+	}
+	return s
+}
+
+// newShape returns the shape of the geometry
+func newShape(g geom.Geometry) (shape, error) {
+	switch gg := g.(type) {
+	case geom.Pointer:
+		xy := gg.XY()
+		return pointShape(xy[:], false, false), nil
+	case geom.MultiPointer:
+		return shape{typ: MultiPoint, pts: coords2(gg.Points())}, nil
+	case geom.LineStringer:
+		return shape{typ: LineString, pts: coords2(gg.Vertices())}, nil
+	case geom.MultiLineStringer:
+		return shape{typ: MultiLineString, lines: lines2(gg.LineStrings())}, nil
+	case geom.Polygoner:
+		return shape{typ: Polygon, lines: lines2(gg.LinearRings())}, nil
+	case geom.MultiPolygoner:
+		plys := gg.Polygons()
+		s := shape{typ: MultiPolygon, polys: make([][][][]float64, len(plys))}
+		for i := range plys {
+			s.polys[i] = lines2(plys[i])
+		}
+		return s, nil
+	case geom.Collectioner:
+		s := shape{typ: Collection, geoms: gg.Geometries()}
+		// the collection only has the dimensions all of its geometries
+		// have
+		s.hasZ, s.hasM = len(s.geoms) > 0, len(s.geoms) > 0
+		for _, g := range s.geoms {
+			ms, err := newShape(g)
+			if err != nil {
+				return s, err
+			}
+			s.hasZ = s.hasZ && ms.hasZ
+			s.hasM = s.hasM && ms.hasM
+		}
+		return s, nil
+
+	case geom.PointZ:
+		return pointShape(gg[:], true, false), nil
+	case geom.PointM:
+		return pointShape(gg[:], false, true), nil
+	case geom.PointZM:
+		return pointShape(gg[:], true, true), nil
+	case geom.MultiPointZ:
+		return shape{typ: MultiPoint, hasZ: true, pts: coords3(gg)}, nil
+	case geom.MultiPointM:
+		return shape{typ: MultiPoint, hasM: true, pts: coords3(gg)}, nil
+	case geom.MultiPointZM:
+		return shape{typ: MultiPoint, hasZ: true, hasM: true, pts: coords4(gg)}, nil
+	case geom.LineStringZ:
+		return shape{typ: LineString, hasZ: true, pts: coords3(gg)}, nil
+	case geom.LineStringM:
+		return shape{typ: LineString, hasM: true, pts: coords3(gg)}, nil
+	case geom.LineStringZM:
+		return shape{typ: LineString, hasZ: true, hasM: true, pts: coords4(gg)}, nil
+	case geom.MultiLineStringZ:
+		return shape{typ: MultiLineString, hasZ: true, lines: lines3(gg)}, nil
+	case geom.MultiLineStringM:
+		return shape{typ: MultiLineString, hasM: true, lines: lines3(gg)}, nil
+	case geom.MultiLineStringZM:
+		return shape{typ: MultiLineString, hasZ: true, hasM: true, lines: lines4(gg)}, nil
+	case geom.PolygonZ:
+		return shape{typ: Polygon, hasZ: true, lines: lines3(gg)}, nil
+	case geom.PolygonM:
+		return shape{typ: Polygon, hasM: true, lines: lines3(gg)}, nil
+	case geom.PolygonZM:
+		return shape{typ: Polygon, hasZ: true, hasM: true, lines: lines4(gg)}, nil
+	case geom.MultiPolygonZ:
+		s := shape{typ: MultiPolygon, hasZ: true, polys: make([][][][]float64, len(gg))}
+		for i := range gg {
+			s.polys[i] = lines3(gg[i])
+		}
+		return s, nil
+	case geom.MultiPolygonM:
+		s := shape{typ: MultiPolygon, hasM: true, polys: make([][][][]float64, len(gg))}
+		for i := range gg {
+			s.polys[i] = lines3(gg[i])
+		}
+		return s, nil
+	case geom.MultiPolygonZM:
+		s := shape{typ: MultiPolygon, hasZ: true, hasM: true, polys: make([][][][]float64, len(gg))}
+		for i := range gg {
+			s.polys[i] = lines4(gg[i])
+		}
+		return s, nil
+
+	// a nil pointer is an empty geometry
+	case *geom.PointZ:
+		if gg == nil {
+			return shape{typ: Point, hasZ: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.PointM:
+		if gg == nil {
+			return shape{typ: Point, hasM: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.PointZM:
+		if gg == nil {
+			return shape{typ: Point, hasZ: true, hasM: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.MultiPointZ:
+		if gg == nil {
+			return shape{typ: MultiPoint, hasZ: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.MultiPointM:
+		if gg == nil {
+			return shape{typ: MultiPoint, hasM: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.MultiPointZM:
+		if gg == nil {
+			return shape{typ: MultiPoint, hasZ: true, hasM: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.LineStringZ:
+		if gg == nil {
+			return shape{typ: LineString, hasZ: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.LineStringM:
+		if gg == nil {
+			return shape{typ: LineString, hasM: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.LineStringZM:
+		if gg == nil {
+			return shape{typ: LineString, hasZ: true, hasM: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.MultiLineStringZ:
+		if gg == nil {
+			return shape{typ: MultiLineString, hasZ: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.MultiLineStringM:
+		if gg == nil {
+			return shape{typ: MultiLineString, hasM: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.MultiLineStringZM:
+		if gg == nil {
+			return shape{typ: MultiLineString, hasZ: true, hasM: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.PolygonZ:
+		if gg == nil {
+			return shape{typ: Polygon, hasZ: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.PolygonM:
+		if gg == nil {
+			return shape{typ: Polygon, hasM: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.PolygonZM:
+		if gg == nil {
+			return shape{typ: Polygon, hasZ: true, hasM: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.MultiPolygonZ:
+		if gg == nil {
+			return shape{typ: MultiPolygon, hasZ: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.MultiPolygonM:
+		if gg == nil {
+			return shape{typ: MultiPolygon, hasM: true}, nil
+		}
+		return newShape(*gg)
+	case *geom.MultiPolygonZM:
+		if gg == nil {
+			return shape{typ: MultiPolygon, hasZ: true, hasM: true}, nil
+		}
+		return newShape(*gg)
+
+	default:
+		return shape{}, geom.ErrUnknownGeometry{Geom: g}
+	}
+}
+
+// geometry returns the geom type of the shape. Empty points have NaN
+// ordinates.
+func (s shape) geometry() geom.Geometry {
+	if s.typ == Collection {
+		if s.geoms == nil {
+			return geom.Collection{}
+		}
+		return geom.Collection(s.geoms)
+	}
+
+	switch {
+	case s.hasZ && s.hasM:
+		return s.geometryZM()
+	case s.hasZ || s.hasM:
+		return s.geometry3()
+	}
+
+	switch s.typ {
+	case Point:
+		if len(s.pts) == 0 {
+			return geom.Point{math.NaN(), math.NaN()}
+		}
+		return geom.Point{s.pts[0][0], s.pts[0][1]}
+	case LineString:
+		return geom.LineString(points2(s.pts))
+	case MultiPoint:
+		return geom.MultiPoint(points2(s.pts))
+	case Polygon:
+		return geom.Polygon(rings2(s.lines))
+	case MultiLineString:
+		return geom.MultiLineString(rings2(s.lines))
+	case MultiPolygon:
+		mply := make(geom.MultiPolygon, len(s.polys))
+		for i := range s.polys {
+			mply[i] = rings2(s.polys[i])
+		}
+		return mply
+	default:
+		return geom.Collection(s.geoms)
+	}
+}
+
+// geometry3 returns the geom type of a shape with z or m values
+func (s shape) geometry3() geom.Geometry {
+	switch s.typ {
+	case Point:
+		pt := [3]float64{math.NaN(), math.NaN(), math.NaN()}
+		if len(s.pts) > 0 {
+			copy(pt[:], s.pts[0])
+		}
+		if s.hasZ {
+			return geom.PointZ(pt)
+		}
+		return geom.PointM(pt)
+	case LineString:
+		if s.hasZ {
+			return geom.LineStringZ(points3(s.pts))
+		}
+		return geom.LineStringM(points3(s.pts))
+	case MultiPoint:
+		if s.hasZ {
+			return geom.MultiPointZ(points3(s.pts))
+		}
+		return geom.MultiPointM(points3(s.pts))
+	case Polygon:
+		if s.hasZ {
+			return geom.PolygonZ(rings3(s.lines))
+		}
+		return geom.PolygonM(rings3(s.lines))
+	case MultiLineString:
+		if s.hasZ {
+			return geom.MultiLineStringZ(rings3(s.lines))
+		}
+		return geom.MultiLineStringM(rings3(s.lines))
+	case MultiPolygon:
+		mply := make([][][][3]float64, len(s.polys))
+		for i := range s.polys {
+			mply[i] = rings3(s.polys[i])
+		}
+		if s.hasZ {
+			return geom.MultiPolygonZ(mply)
+		}
+		return geom.MultiPolygonM(mply)
+	default:
+		return geom.Collection(s.geoms)
+	}
+}
+
+// geometryZM returns the geom type of a shape with z and m values
+func (s shape) geometryZM() geom.Geometry {
+	switch s.typ {
+	case Point:
+		pt := geom.PointZM{math.NaN(), math.NaN(), math.NaN(), math.NaN()}
+		if len(s.pts) > 0 {
+			copy(pt[:], s.pts[0])
+		}
+		return pt
+	case LineString:
+		return geom.LineStringZM(points4(s.pts))
+	case MultiPoint:
+		return geom.MultiPointZM(points4(s.pts))
+	case Polygon:
+		return geom.PolygonZM(rings4(s.lines))
+	case MultiLineString:
+		return geom.MultiLineStringZM(rings4(s.lines))
+	case MultiPolygon:
+		mply := make(geom.MultiPolygonZM, len(s.polys))
+		for i := range s.polys {
+			mply[i] = rings4(s.polys[i])
+		}
+		return mply
+	default:
+		return geom.Collection(s.geoms)
+	}
+}
+
+func coords2(pts [][2]float64) [][]float64 {
+	c := make([][]float64, len(pts))
+	for i := range pts {
+		c[i] = pts[i][:]
+	}
+	return c
+}
+
+func coords3(pts [][3]float64) [][]float64 {
+	c := make([][]float64, len(pts))
+	for i := range pts {
+		c[i] = pts[i][:]
+	}
+	return c
+}
+
+func coords4(pts [][4]float64) [][]float64 {
+	c := make([][]float64, len(pts))
+	for i := range pts {
+		c[i] = pts[i][:]
+	}
+	return c
+}
+
+func lines2(lines [][][2]float64) [][][]float64 {
+	c := make([][][]float64, len(lines))
+	for i := range lines {
+		c[i] = coords2(lines[i])
+	}
+	return c
+}
+
+func lines3(lines [][][3]float64) [][][]float64 {
+	c := make([][][]float64, len(lines))
+	for i := range lines {
+		c[i] = coords3(lines[i])
+	}
+	return c
+}
+
+func lines4(lines [][][4]float64) [][][]float64 {
+	c := make([][][]float64, len(lines))
+	for i := range lines {
+		c[i] = coords4(lines[i])
+	}
+	return c
+}
+
+func points2(c [][]float64) [][2]float64 {
+	pts := make([][2]float64, len(c))
+	for i := range c {
+		copy(pts[i][:], c[i])
+	}
+	return pts
+}
+
+func points3(c [][]float64) [][3]float64 {
+	pts := make([][3]float64, len(c))
+	for i := range c {
+		copy(pts[i][:], c[i])
+	}
+	return pts
+}
+
+func points4(c [][]float64) [][4]float64 {
+	pts := make([][4]float64, len(c))
+	for i := range c {
+		copy(pts[i][:], c[i])
+	}
+	return pts
+}
+
+func rings2(c [][][]float64) [][][2]float64 {
+	rings := make([][][2]float64, len(c))
+	for i := range c {
+		rings[i] = points2(c[i])
+	}
+	return rings
+}
+
+func rings3(c [][][]float64) [][][3]float64 {
+	rings := make([][][3]float64, len(c))
+	for i := range c {
+		rings[i] = points3(c[i])
+	}
+	return rings
+}
+
+func rings4(c [][][]float64) [][][4]float64 {
+	rings := make([][][4]float64, len(c))
+	for i := range c {
+		rings[i] = points4(c[i])
+	}
+	return rings
+}
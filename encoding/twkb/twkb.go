@@ -0,0 +1,88 @@
+// Package twkb is for encoding and decoding geometries as Tiny Well Known
+// Binary (TWKB), the compressed format produced by PostGIS's ST_AsTWKB.
+// Specification at https://github.com/TWKB/Specification
+//
+// Coordinates are stored as integers, scaled by a power of ten given by the
+// precision, and delta encoded as zig-zag varints. Encoding a geometry
+// rounds its coordinates to the precision.
+package twkb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-spatial/geom"
+)
+
+// geometry types
+const (
+	Point           byte = 1
+	LineString      byte = 2
+	Polygon         byte = 3
+	MultiPoint      byte = 4
+	MultiLineString byte = 5
+	MultiPolygon    byte = 6
+	Collection      byte = 7
+)
+
+// metadata header flags
+const (
+	flagBBox     byte = 1 << 0
+	flagSize     byte = 1 << 1
+	flagIDList   byte = 1 << 2
+	flagExtended byte = 1 << 3
+	flagEmpty    byte = 1 << 4
+)
+
+// precision limits
+const (
+	MinPrecision   = -8
+	MaxPrecision   = 7
+	MaxZMPrecision = 7
+)
+
+var (
+	// ErrInvalidPrecision is returned when a precision is outside of the
+	// range that can be stored in the header.
+	ErrInvalidPrecision = errors.New("twkb: invalid precision")
+	// ErrInvalidCoordinate is returned when a coordinate is NaN or can not
+	// be stored as an integer at the precision.
+	ErrInvalidCoordinate = errors.New("twkb: invalid coordinate")
+)
+
+// ErrUnknownGeometryType is returned when decoding a geometry with a type
+// that is not one of the TWKB geometry types.
+type ErrUnknownGeometryType struct {
+	Typ byte
+}
+
+func (e ErrUnknownGeometryType) Error() string {
+	return fmt.Sprintf("twkb: unknown geometry type %v", e.Typ)
+}
+
+// Encode writes the geometry to w using the default encoder; see NewEncoder.
+func Encode(w io.Writer, g geom.Geometry) error {
+	return NewEncoder(w).Encode(g)
+}
+
+// EncodeBytes returns the geometry encoded by the default encoder; see
+// NewEncoder.
+func EncodeBytes(g geom.Geometry) ([]byte, error) {
+	buff := new(bytes.Buffer)
+	if err := Encode(buff, g); err != nil {
+		return nil, err
+	}
+	return buff.Bytes(), nil
+}
+
+// Decode decodes a single geometry from r.
+func Decode(r io.Reader) (geom.Geometry, error) {
+	return NewDecoder(r).Decode()
+}
+
+// DecodeBytes decodes a single geometry from b.
+func DecodeBytes(b []byte) (geom.Geometry, error) {
+	return Decode(bytes.NewReader(b))
+}
@@ -0,0 +1,234 @@
+package twkb_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/twkb"
+)
+
+func TestEncode(t *testing.T) {
+	type tcase struct {
+		geom      geom.Geometry
+		precision int
+		bbox      bool
+		size      bool
+		// hex encoding of the expected bytes
+		exp string
+		err error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var buff bytes.Buffer
+			enc := twkb.NewEncoder(&buff)
+			if err := enc.SetPrecision(tc.precision, 0, 0); err != nil {
+				t.Fatalf("precision, expected nil got %v", err)
+			}
+			enc.SetBBox(tc.bbox)
+			enc.SetSize(tc.size)
+			err := enc.Encode(tc.geom)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if got := hex.EncodeToString(buff.Bytes()); got != tc.exp {
+				t.Errorf("encode, expected %v got %v", tc.exp, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			geom: geom.Point{1, 2},
+			exp:  "01000204",
+		},
+		"linestring": {
+			geom: geom.LineString{{1, 1}, {5, 5}},
+			exp:  "02000202020808",
+		},
+		"linestring bbox": {
+			geom: geom.LineString{{1, 1}, {5, 5}},
+			bbox: true,
+			exp:  "0201020802080202020808",
+		},
+		"linestring size": {
+			geom: geom.LineString{{1, 1}, {5, 5}},
+			size: true,
+			exp:  "0202050202020808",
+		},
+		"linestring precision": {
+			geom:      geom.LineString{{0.1, 0.1}, {0.5, 0.5}},
+			precision: 1,
+			exp:       "22000202020808",
+		},
+		"linestring negative precision": {
+			geom:      geom.LineString{{100, 100}, {500, 500}},
+			precision: -2,
+			exp:       "32000202020808",
+		},
+		"polygon closes rings": {
+			geom: geom.Polygon{{{0, 0}, {1, 0}, {1, 1}}},
+			exp:  "030001040000020000020101",
+		},
+		"empty point": {
+			geom: geom.Point{math.NaN(), math.NaN()},
+			exp:  "0110",
+		},
+		"empty linestring": {
+			geom: geom.LineString{},
+			bbox: true,
+			exp:  "0210",
+		},
+		"point z": {
+			geom: geom.PointZ{1, 2, 3},
+			exp:  "010801020406",
+		},
+		"nan": {
+			geom: geom.LineString{{1, 1}, {math.NaN(), 5}},
+			exp:  "",
+			err:  twkb.ErrInvalidCoordinate,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	type tcase struct {
+		geom geom.Geometry
+		exp  geom.Geometry
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			for _, opts := range [][2]bool{{false, false}, {true, false}, {false, true}, {true, true}} {
+				var buff bytes.Buffer
+				enc := twkb.NewEncoder(&buff)
+				if err := enc.SetPrecision(3, 2, 1); err != nil {
+					t.Fatalf("precision, expected nil got %v", err)
+				}
+				enc.SetBBox(opts[0])
+				enc.SetSize(opts[1])
+				if err := enc.Encode(tc.geom); err != nil {
+					t.Fatalf("encode, expected nil got %v", err)
+				}
+				got, err := twkb.Decode(&buff)
+				if err != nil {
+					t.Fatalf("decode, expected nil got %v", err)
+				}
+				exp := tc.exp
+				if exp == nil {
+					exp = tc.geom
+				}
+				// NaN != NaN, so compare the printed values
+				if fmt.Sprint(got) != fmt.Sprint(exp) || reflect.TypeOf(got) != reflect.TypeOf(exp) {
+					t.Errorf("bbox %v size %v, expected %v got %v", opts[0], opts[1], exp, got)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			geom: geom.Point{1.2345, -2.5},
+			exp:  geom.Point{1.235, -2.5},
+		},
+		"empty point": {
+			geom: geom.Point{math.NaN(), math.NaN()},
+		},
+		"multipoint": {
+			geom: geom.MultiPoint{{1, 2}, {3, 4}},
+		},
+		"linestring": {
+			geom: geom.LineString{{0.001, 0.002}, {-100.5, 200.25}},
+		},
+		"multilinestring": {
+			geom: geom.MultiLineString{{{1, 2}, {3, 4}}, {}, {{5, 6}, {7, 8}}},
+		},
+		"polygon": {
+			geom: geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 0}}, {{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+		},
+		"multipolygon": {
+			geom: geom.MultiPolygon{{{{0, 0}, {10, 0}, {10, 10}, {0, 0}}}, {{{20, 20}, {30, 20}, {30, 30}, {20, 20}}}},
+		},
+		"collection": {
+			geom: geom.Collection{geom.Point{1, 2}, geom.LineString{{3, 4}, {5, 6}}, geom.Collection{}},
+		},
+		"empty collection": {
+			geom: geom.Collection{},
+		},
+		"linestring z": {
+			geom: geom.LineStringZ{{1, 2, 3.25}, {4, 5, 6.5}},
+		},
+		"linestring m": {
+			geom: geom.LineStringM{{1, 2, 3.5}, {4, 5, 6.25}},
+			exp:  geom.LineStringM{{1, 2, 3.5}, {4, 5, 6.3}},
+		},
+		"multipolygon zm": {
+			geom: geom.MultiPolygonZM{{{{0, 0, 1, 1}, {10, 0, 2, 2}, {10, 10, 3, 3}, {0, 0, 1, 1}}}},
+		},
+		"nil point zm": {
+			geom: (*geom.PointZM)(nil),
+			exp:  geom.PointZM{math.NaN(), math.NaN(), math.NaN(), math.NaN()},
+		},
+		"collection z": {
+			geom: geom.Collection{geom.PointZ{1, 2, 3}, geom.PointZM{4, 5, 6, 7}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	// a stream of a point, a multipoint with an id list, then a truncated
+	// linestring
+	bs, err := hex.DecodeString("01000204" + "0404020a0c02040406" + "020002020208")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := twkb.NewDecoder(bytes.NewReader(bs))
+	for i, exp := range []geom.Geometry{
+		geom.Point{1, 2},
+		geom.MultiPoint{{1, 2}, {3, 5}},
+	} {
+		got, err := dec.Decode()
+		if err != nil {
+			t.Fatalf("%v: error, expected nil got %v", i, err)
+		}
+		if !reflect.DeepEqual(got, exp) {
+			t.Errorf("%v: decode, expected %v got %v", i, exp, got)
+		}
+	}
+	if _, err := dec.Decode(); err != io.ErrUnexpectedEOF {
+		t.Errorf("truncated, expected %v got %v", io.ErrUnexpectedEOF, err)
+	}
+
+	if _, err := twkb.DecodeBytes(nil); err != io.EOF {
+		t.Errorf("empty, expected %v got %v", io.EOF, err)
+	}
+	if _, err := twkb.DecodeBytes([]byte{0x08, 0x00}); err != (twkb.ErrUnknownGeometryType{Typ: 8}) {
+		t.Errorf("unknown type, expected %v got %v", twkb.ErrUnknownGeometryType{Typ: 8}, err)
+	}
+}
+
+func TestSetPrecision(t *testing.T) {
+	enc := twkb.NewEncoder(nil)
+	for _, p := range [][3]int{{8, 0, 0}, {-9, 0, 0}, {0, -1, 0}, {0, 0, 8}} {
+		if err := enc.SetPrecision(p[0], p[1], p[2]); err != twkb.ErrInvalidPrecision {
+			t.Errorf("%v, expected %v got %v", p, twkb.ErrInvalidPrecision, err)
+		}
+	}
+	if err := enc.SetPrecision(-8, 7, 7); err != nil {
+		t.Errorf("expected nil got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package wkb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/wkb/internal/decode"
+)
+
+// Decoder reads and decodes WKB geometries from an input stream one at a
+// time. The stream may hold any number of geometries one after another.
+//
+// The members of a (2D) geometry collection are returned one at a time
+// instead of the collection, so a large collection is never held in
+// memory at once; nested collections are flattened in the same way. All
+// other geometries, including ZM collections, are decoded whole.
+type Decoder struct {
+	r *bufio.Reader
+	// dr reads from r, keeping the offset for the errors of DecodeStrict
+	dr *decode.Reader
+	// the number of members still to be read of each open collection,
+	// innermost last
+	remaining []uint32
+	srid      uint32
+	err       error
+}
+
+// NewDecoder returns a new decoder that reads from r. The decoder buffers
+// its reads, and may read data from r beyond the geometries requested. The
+// offsets of the errors of DecodeStrict are from the start of the stream.
+func NewDecoder(r io.Reader, opts ...DecodeOption) *Decoder {
+	br := bufio.NewReader(r)
+	return &Decoder{r: br, dr: newReader(br, opts)}
+}
+
+// More reports whether there is another geometry in the stream. Errors
+// reading the stream are reported by the following call to Decode.
+func (d *Decoder) More() bool {
+	if d.err != nil {
+		return false
+	}
+	d.closeCollections()
+	if len(d.remaining) > 0 {
+		return true
+	}
+	_, err := d.r.Peek(1)
+	return err == nil
+}
+
+// Decode returns the next geometry in the stream. At the end of the stream
+// it returns io.EOF; a stream that ends in the middle of a geometry returns
+// io.ErrUnexpectedEOF. Once an error has been returned, all following
+// calls return the same error.
+func (d *Decoder) Decode() (geom.Geometry, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	geo, err := d.next()
+	if err != nil {
+		if err == io.EOF && len(d.remaining) > 0 {
+			err = io.ErrUnexpectedEOF
+		}
+		d.err = err
+		return nil, err
+	}
+	return geo, nil
+}
+
+func (d *Decoder) next() (geom.Geometry, error) {
+	for {
+		d.closeCollections()
+		if len(d.remaining) > 0 {
+			d.remaining[len(d.remaining)-1]--
+		}
+
+		bom, typ, srid, err := decode.ByteOrderTypeSRID(d.dr)
+		if err != nil {
+			return nil, err
+		}
+		if len(d.remaining) == 0 {
+			d.srid = srid
+		}
+		if typ != Collection {
+			geo, err := decodeGeometry(d.dr, bom, typ)
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return geo, err
+		}
+
+		var num uint32
+		if err = binary.Read(d.dr, bom, &num); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		d.remaining = append(d.remaining, num)
+	}
+}
+
+// SRID returns the SRID of the EWKB geometry last returned by Decode, or of
+// the collection it is a member of; the SRID is 0 if there is none.
+func (d *Decoder) SRID() uint32 { return d.srid }
+
+// closeCollections drops the collections whose members have all been read
+func (d *Decoder) closeCollections() {
+	for len(d.remaining) > 0 && d.remaining[len(d.remaining)-1] == 0 {
+		d.remaining = d.remaining[:len(d.remaining)-1]
+	}
+}
@@ -0,0 +1,280 @@
+package wkb_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+	"github.com/go-spatial/geom/encoding/wkb"
+)
+
+func TestDecoder(t *testing.T) {
+	type tcase struct {
+		geoms []geom.Geometry
+		// truncate is the number of bytes to remove from the end of the stream
+		truncate int
+		exp      []geom.Geometry
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var buff bytes.Buffer
+			for _, g := range tc.geoms {
+				if err := wkb.Encode(&buff, g); err != nil {
+					t.Fatalf("encode, expected nil got %v", err)
+				}
+			}
+			buff.Truncate(buff.Len() - tc.truncate)
+
+			dec := wkb.NewDecoder(&buff)
+			var got []geom.Geometry
+			for dec.More() {
+				g, err := dec.Decode()
+				if err != nil {
+					if err != tc.err {
+						t.Fatalf("error, expected %v got %v", tc.err, err)
+					}
+					break
+				}
+				got = append(got, g)
+			}
+			if _, err := dec.Decode(); tc.err == nil && err != io.EOF {
+				t.Errorf("error at end, expected %v got %v", io.EOF, err)
+			}
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("geometries, expected %v got %v", tc.exp, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {},
+		"concatenated": {
+			geoms: []geom.Geometry{
+				geom.Point{1, 2},
+				geom.LineString{{0, 0}, {1, 1}},
+				geom.PointZ{1, 2, 3},
+			},
+			exp: []geom.Geometry{
+				geom.Point{1, 2},
+				geom.LineString{{0, 0}, {1, 1}},
+				geom.PointZ{1, 2, 3},
+			},
+		},
+		"collection": {
+			geoms: []geom.Geometry{
+				geom.Collection{
+					geom.Point{1, 2},
+					geom.Collection{},
+					geom.Collection{geom.Point{3, 4}, geom.MultiPoint{{5, 6}}},
+				},
+				geom.Point{7, 8},
+			},
+			exp: []geom.Geometry{
+				geom.Point{1, 2},
+				geom.Point{3, 4},
+				geom.MultiPoint{{5, 6}},
+				geom.Point{7, 8},
+			},
+		},
+		"truncated geometry": {
+			geoms:    []geom.Geometry{geom.Point{1, 2}, geom.Point{3, 4}},
+			truncate: 4,
+			exp:      []geom.Geometry{geom.Point{1, 2}},
+			err:      io.ErrUnexpectedEOF,
+		},
+		"truncated collection": {
+			geoms:    []geom.Geometry{geom.Collection{geom.Point{1, 2}, geom.Point{3, 4}}},
+			truncate: 21,
+			exp:      []geom.Geometry{geom.Point{1, 2}},
+			err:      io.ErrUnexpectedEOF,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDecoderSRID(t *testing.T) {
+	var buff bytes.Buffer
+	if err := wkb.Encode(&buff, geom.Collection{geom.Point{1, 2}, geom.Point{3, 4}}, wkb.WithSRID(4326)); err != nil {
+		t.Fatalf("encode, expected nil got %v", err)
+	}
+	if err := wkb.Encode(&buff, geom.Point{5, 6}); err != nil {
+		t.Fatalf("encode, expected nil got %v", err)
+	}
+
+	dec := wkb.NewDecoder(&buff)
+	for _, exp := range []uint32{4326, 4326, 0} {
+		if _, err := dec.Decode(); err != nil {
+			t.Fatalf("decode, expected nil got %v", err)
+		}
+		if dec.SRID() != exp {
+			t.Errorf("srid, expected %v got %v", exp, dec.SRID())
+		}
+	}
+}
+
+func TestDecodeMode(t *testing.T) {
+	type tcase struct {
+		// hex encoding of the little endian wkb
+		wkb  string
+		mode wkb.DecodeMode
+		exp  geom.Geometry
+		err  error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			b, err := hex.DecodeString(tc.wkb)
+			if err != nil {
+				t.Fatalf("hex, expected nil got %v", err)
+			}
+			got, err := wkb.DecodeBytes(b, wkb.WithDecodeMode(tc.mode))
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				return
+			}
+			if !cmp.GeometryEqual(got, tc.exp) {
+				t.Errorf("decode, expected %v got %v", tc.exp, got)
+			}
+		}
+	}
+
+	const (
+		zero = "0000000000000000"
+		one  = "000000000000f03f"
+		nan  = "000000000000f87f"
+		inf  = "000000000000f07f"
+	)
+	var (
+		unclosedRing = "0103000000" + "01000000" + "04000000" + zero + zero + one + zero + one + one + zero + one
+		infLine      = "0102000000" + "03000000" + zero + zero + inf + zero + one + one
+	)
+	tests := map[string]tcase{
+		"default unclosed ring": {
+			wkb: unclosedRing,
+			exp: geom.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}},
+		},
+		"strict unclosed ring": {
+			wkb:  unclosedRing,
+			mode: wkb.DecodeStrict,
+			err:  wkb.ErrInvalid{Offset: 9, Issue: "ring not closed"},
+		},
+		"strict short ring": {
+			wkb:  "0103000000" + "01000000" + "03000000" + zero + zero + one + zero + zero + zero,
+			mode: wkb.DecodeStrict,
+			err:  wkb.ErrInvalid{Offset: 9, Issue: "ring of fewer than four points"},
+		},
+		"strict line string of one point": {
+			wkb:  "0102000000" + "01000000" + one + one,
+			mode: wkb.DecodeStrict,
+			err:  wkb.ErrInvalid{Offset: 5, Issue: "line string of one point"},
+		},
+		"strict infinite coordinate": {
+			wkb:  infLine,
+			mode: wkb.DecodeStrict,
+			err:  wkb.ErrInvalid{Offset: 25, Issue: "coordinate not finite"},
+		},
+		"strict empty point": {
+			wkb:  "0101000000" + nan + nan,
+			mode: wkb.DecodeStrict,
+			exp:  geom.Point{math.NaN(), math.NaN()},
+		},
+		"lenient infinite coordinate": {
+			wkb:  infLine,
+			mode: wkb.DecodeLenient,
+			exp:  geom.LineString{{0, 0}, {1, 1}},
+		},
+		"lenient point": {
+			wkb:  "0101000000" + nan + one,
+			mode: wkb.DecodeLenient,
+			exp:  geom.Point{math.NaN(), math.NaN()},
+		},
+		"lenient multipoint": {
+			wkb:  "0104000000" + "02000000" + "0101000000" + inf + one + "0101000000" + one + one,
+			mode: wkb.DecodeLenient,
+			exp:  geom.MultiPoint{{1, 1}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDecodeCount(t *testing.T) {
+	type tcase struct {
+		// hex encoding of the little endian wkb
+		wkb string
+		err error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			b, err := hex.DecodeString(tc.wkb)
+			if err != nil {
+				t.Fatalf("hex, expected nil got %v", err)
+			}
+			_, err = wkb.DecodeBytes(b)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+			}
+			// a reader that does not know its length can not check the
+			// counts, but must still not allocate for them
+			_, err = wkb.Decode(struct{ io.Reader }{bytes.NewReader(b)})
+			if err == nil {
+				t.Errorf("reader error, expected error got nil")
+			}
+		}
+	}
+
+	const huge = "ffffff7f"
+	tests := map[string]tcase{
+		"multipoint": {
+			wkb: "0104000000" + huge,
+			err: wkb.ErrCount{Offset: 5, Count: 0x7fffffff},
+		},
+		"line string": {
+			wkb: "0102000000" + huge + "000000000000f03f",
+			err: wkb.ErrCount{Offset: 5, Count: 0x7fffffff},
+		},
+		"line string one point short": {
+			wkb: "0102000000" + "02000000" + "000000000000f03f" + "000000000000f03f",
+			err: wkb.ErrCount{Offset: 5, Count: 2},
+		},
+		"polygon ring": {
+			wkb: "0103000000" + "01000000" + huge,
+			err: wkb.ErrCount{Offset: 9, Count: 0x7fffffff},
+		},
+		"multipolygon": {
+			wkb: "0106000000" + huge,
+			err: wkb.ErrCount{Offset: 5, Count: 0x7fffffff},
+		},
+		"collection": {
+			wkb: "0107000000" + huge,
+			err: wkb.ErrCount{Offset: 5, Count: 0x7fffffff},
+		},
+		"line string z": {
+			wkb: "01ea030000" + huge,
+			err: wkb.ErrCount{Offset: 5, Count: 0x7fffffff},
+		},
+		"multipoint zm": {
+			wkb: "01bc0b0000" + huge,
+			err: wkb.ErrCount{Offset: 5, Count: 0x7fffffff},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -11,3 +11,21 @@ const (
 	MultiPolygon    uint32 = 6
 	Collection      uint32 = 7
 )
+
+// ISO offsets added to the geometry types for geometries that
+// carry z and/or m values. i.e. a PointZ is Point + Z = 1001
+const (
+	Z  uint32 = 1000
+	M  uint32 = 2000
+	ZM uint32 = 3000
+)
+
+// EWKB flags, used by PostGIS, set in the geometry type instead of the ISO
+// offsets; the SRID flag means the type is followed by the SRID.
+const (
+	EWKBZ    uint32 = 0x80000000
+	EWKBM    uint32 = 0x40000000
+	EWKBSRID uint32 = 0x20000000
+	// EWKBType masks the flags off the geometry type
+	EWKBType uint32 = 0x0fffffff
+)
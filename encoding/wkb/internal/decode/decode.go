@@ -23,11 +23,22 @@ func (e ErrInvalidType) Error() string {
 	return fmt.Sprintf("decode: invalid type for %v", e.Primary)
 }
 
-func ByteOrderType(r io.Reader) (byteOrder binary.ByteOrder, typ uint32, err error) {
+// ByteOrderType reads the byte order marker and the geometry type. EWKB
+// types are returned as the ISO types, and an SRID following them is
+// skipped.
+func ByteOrderType(r *Reader) (byteOrder binary.ByteOrder, typ uint32, err error) {
+	byteOrder, typ, _, err = ByteOrderTypeSRID(r)
+	return byteOrder, typ, err
+}
+
+// ByteOrderTypeSRID reads the byte order marker and the geometry type, and
+// the SRID if the type is an EWKB type with one; otherwise the SRID is 0.
+// EWKB types are returned as the ISO types.
+func ByteOrderTypeSRID(r *Reader) (byteOrder binary.ByteOrder, typ uint32, srid uint32, err error) {
 	var bom = make([]byte, 1, 1)
 	// the bom is the first byte
 	if _, err = r.Read(bom); err != nil {
-		return byteOrder, typ, err
+		return byteOrder, typ, srid, err
 	}
 
 	// the bom should be either 0 or 1
@@ -37,27 +48,53 @@ func ByteOrderType(r io.Reader) (byteOrder binary.ByteOrder, typ uint32, err err
 	case 1:
 		byteOrder = binary.LittleEndian
 	default:
-		return byteOrder, typ, ErrBadBOM(bom[0])
+		return byteOrder, typ, srid, ErrBadBOM(bom[0])
 	}
 
 	// Reading the type which is 4 bytes
-	err = binary.Read(r, byteOrder, &typ)
-	return byteOrder, typ, err
+	if err = binary.Read(r, byteOrder, &typ); err != nil {
+		return byteOrder, typ, srid, err
+	}
+	if typ&^consts.EWKBType == 0 {
+		return byteOrder, typ, srid, nil
+	}
+
+	iso := typ & consts.EWKBType
+	if typ&consts.EWKBZ != 0 {
+		iso += consts.Z
+	}
+	if typ&consts.EWKBM != 0 {
+		iso += consts.M
+	}
+	if typ&consts.EWKBSRID != 0 {
+		if err = binary.Read(r, byteOrder, &srid); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return byteOrder, iso, srid, err
+		}
+	}
+	return byteOrder, iso, srid, nil
 }
 
-func Point(r io.Reader, bom binary.ByteOrder) (pt geom.Point, err error) {
-	err = binary.Read(r, bom, &pt)
-	return pt, err
+func Point(r *Reader, bom binary.ByteOrder) (pt geom.Point, err error) {
+	off := r.Offset
+	if err = binary.Read(r, bom, &pt); err != nil {
+		return pt, err
+	}
+	return pt, r.point(off, pt[:])
 }
-func MultiPoint(r io.Reader, bom binary.ByteOrder) (pts geom.MultiPoint, err error) {
-	var num, typ uint32 // Number of points
-	err = binary.Read(r, bom, &num)
+
+func MultiPoint(r *Reader, bom binary.ByteOrder) (pts geom.MultiPoint, err error) {
+	var typ uint32
+	// each point is a byte order, type and coordinate
+	num, prealloc, err := r.count(bom, 21)
 	if err != nil {
 		return pts, err
 	}
 
-	pts = make([][2]float64, num)
-	for i := range pts {
+	pts = make([][2]float64, 0, prealloc)
+	for i := 0; i < num; i++ {
 
 		bom, typ, err = ByteOrderType(r)
 		if err != nil {
@@ -66,35 +103,62 @@ func MultiPoint(r io.Reader, bom binary.ByteOrder) (pts geom.MultiPoint, err err
 		if typ != consts.Point {
 			return pts, ErrInvalidType{"multipoint", typ}
 		}
-		err = binary.Read(r, bom, &pts[i])
+		off := r.Offset
+		var pt [2]float64
+		err = binary.Read(r, bom, &pt)
 		if err != nil {
 			return pts, err
 		}
+		keep, err := r.keepMember(off, pt[:])
+		if err != nil {
+			return pts, err
+		}
+		if keep {
+			pts = append(pts, pt)
+		}
 	}
 	return pts, err
 }
 
-func LineString(r io.Reader, bom binary.ByteOrder) (ln geom.LineString, err error) {
-	var num uint32 // Number of points
-	if err = binary.Read(r, bom, &num); err != nil {
-		return ln, err
+// points reads a count followed by that many coordinates, checking them
+// with the mode of the reader
+func points(r *Reader, bom binary.ByteOrder) (pts [][2]float64, err error) {
+	num, prealloc, err := r.count(bom, 16)
+	if err != nil {
+		return pts, err
 	}
-	ln = make([][2]float64, num)
-	for i := range ln {
-		if err = binary.Read(r, bom, &ln[i]); err != nil {
-			return ln, err
+	off := r.Offset
+	pts = make([][2]float64, 0, prealloc)
+	for i := 0; i < num; i++ {
+		var pt [2]float64
+		if err = binary.Read(r, bom, &pt); err != nil {
+			return pts, err
 		}
+		pts = append(pts, pt)
 	}
-	return ln, err
+	n, err := r.line(off, 16, len(pts),
+		func(i int) []float64 { return pts[i][:] },
+		func(to, from int) { pts[to] = pts[from] },
+	)
+	return pts[:n], err
 }
 
-func MultiLineString(r io.Reader, bom binary.ByteOrder) (lns geom.MultiLineString, err error) {
-	var num uint32
-	if err = binary.Read(r, bom, &num); err != nil {
+func LineString(r *Reader, bom binary.ByteOrder) (ln geom.LineString, err error) {
+	off := r.Offset
+	if ln, err = points(r, bom); err != nil {
+		return ln, err
+	}
+	return ln, r.lineString(off, len(ln))
+}
+
+func MultiLineString(r *Reader, bom binary.ByteOrder) (lns geom.MultiLineString, err error) {
+	// each line string is at least a byte order, type and count
+	num, prealloc, err := r.count(bom, 9)
+	if err != nil {
 		return lns, err
 	}
-	lns = make([][][2]float64, num)
-	for i := range lns {
+	lns = make([][][2]float64, 0, prealloc)
+	for i := 0; i < num; i++ {
 		bom, typ, err := ByteOrderType(r)
 		if err != nil {
 			return lns, err
@@ -102,55 +166,58 @@ func MultiLineString(r io.Reader, bom binary.ByteOrder) (lns geom.MultiLineStrin
 		if typ != consts.LineString {
 			return lns, ErrInvalidType{"multilinestring", typ}
 		}
-		if lns[i], err = LineString(r, bom); err != nil {
+		ln, err := LineString(r, bom)
+		lns = append(lns, ln)
+		if err != nil {
 			return lns, err
 		}
 	}
 	return lns, err
 }
 
-func LinerRing(r io.Reader, bom binary.ByteOrder) (rn [][2]float64, err error) {
-	var num uint32 // Number of points
-	if err = binary.Read(r, bom, &num); err != nil {
+func LinerRing(r *Reader, bom binary.ByteOrder) (rn [][2]float64, err error) {
+	off := r.Offset
+	if rn, err = points(r, bom); err != nil {
 		return rn, err
 	}
-	rn = make([][2]float64, num)
-	for i := range rn {
-		if err = binary.Read(r, bom, &rn[i]); err != nil {
-			return rn, err
-		}
+	num := len(rn)
+	closed := num > 1 && rn[0] == rn[num-1]
+	if err = r.ring(off, num, closed); err != nil {
+		return rn, err
 	}
-	if num > 1 {
+	if closed {
 		// Remove the last point if it is the same.
-		if rn[0][0] == rn[num-1][0] && rn[0][1] == rn[num-1][1] {
-			rn = rn[:num-1]
-		}
+		rn = rn[:num-1]
 	}
 
 	return rn, err
 }
 
-func Polygon(r io.Reader, bom binary.ByteOrder) (ply geom.Polygon, err error) {
-	var num uint32
-	if err = binary.Read(r, bom, &num); err != nil {
+func Polygon(r *Reader, bom binary.ByteOrder) (ply geom.Polygon, err error) {
+	// each ring is at least a count
+	num, prealloc, err := r.count(bom, 4)
+	if err != nil {
 		return ply, err
 	}
-	ply = make([][][2]float64, num)
-	for i := range ply {
-		if ply[i], err = LinerRing(r, bom); err != nil {
+	ply = make([][][2]float64, 0, prealloc)
+	for i := 0; i < num; i++ {
+		rn, err := LinerRing(r, bom)
+		ply = append(ply, rn)
+		if err != nil {
 			return ply, err
 		}
 	}
 	return ply, err
 }
 
-func MultiPolygon(r io.Reader, bom binary.ByteOrder) (plys geom.MultiPolygon, err error) {
-	var num uint32
-	if err = binary.Read(r, bom, &num); err != nil {
+func MultiPolygon(r *Reader, bom binary.ByteOrder) (plys geom.MultiPolygon, err error) {
+	// each polygon is at least a byte order, type and count
+	num, prealloc, err := r.count(bom, 9)
+	if err != nil {
 		return plys, err
 	}
-	plys = make([][][][2]float64, num)
-	for i := range plys {
+	plys = make([][][][2]float64, 0, prealloc)
+	for i := 0; i < num; i++ {
 		bom, typ, err := ByteOrderType(r)
 		if err != nil {
 			return plys, err
@@ -158,24 +225,28 @@ func MultiPolygon(r io.Reader, bom binary.ByteOrder) (plys geom.MultiPolygon, er
 		if typ != consts.Polygon {
 			return plys, ErrInvalidType{"multipolygon", typ}
 		}
-		if plys[i], err = Polygon(r, bom); err != nil {
+		ply, err := Polygon(r, bom)
+		plys = append(plys, ply)
+		if err != nil {
 			return plys, err
 		}
 	}
 	return plys, err
 }
 
-func Collection(r io.Reader, bom binary.ByteOrder) (col geom.Collection, err error) {
-	var num uint32
-	if err = binary.Read(r, bom, &num); err != nil {
+func Collection(r *Reader, bom binary.ByteOrder) (col geom.Collection, err error) {
+	// each geometry is at least a byte order, type and count
+	num, prealloc, err := r.count(bom, 9)
+	if err != nil {
 		return col, err
 	}
-	col = make(geom.Collection, num)
-	for i := range col {
+	col = make(geom.Collection, 0, prealloc)
+	for i := 0; i < num; i++ {
 		bom, typ, err := ByteOrderType(r)
 		if err != nil {
 			return col, err
 		}
+		col = append(col, nil)
 		switch typ {
 		case consts.Point:
 			col[i], err = Point(r, bom)
@@ -192,6 +263,10 @@ func Collection(r io.Reader, bom binary.ByteOrder) (col geom.Collection, err err
 		case consts.Collection:
 			col[i], err = Collection(r, bom)
 		default:
+			if IsZM(typ) {
+				col[i], err = GeometryZM(r, bom, typ)
+				break
+			}
 			err = ErrInvalidType{"collection", typ}
 		}
 		if err != nil {
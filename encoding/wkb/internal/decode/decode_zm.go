@@ -0,0 +1,316 @@
+package decode
+
+import (
+	"encoding/binary"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/wkb/internal/consts"
+)
+
+// IsZM returns whether the type is one of the ISO Z, M or ZM geometry types
+func IsZM(typ uint32) bool {
+	switch typ - typ%consts.Z {
+	case consts.Z, consts.M, consts.ZM:
+		base := typ % consts.Z
+		return base >= consts.Point && base <= consts.Collection
+	default:
+		return false
+	}
+}
+
+// points3 reads a count followed by that many coordinates with three ordinates.
+func points3(r *Reader, bom binary.ByteOrder) (pts [][3]float64, err error) {
+	num, prealloc, err := r.count(bom, 24)
+	if err != nil {
+		return pts, err
+	}
+	off := r.Offset
+	pts = make([][3]float64, 0, prealloc)
+	for i := 0; i < num; i++ {
+		var pt [3]float64
+		if err = binary.Read(r, bom, &pt); err != nil {
+			return pts, err
+		}
+		pts = append(pts, pt)
+	}
+	n, err := r.line(off, 24, len(pts),
+		func(i int) []float64 { return pts[i][:] },
+		func(to, from int) { pts[to] = pts[from] },
+	)
+	return pts[:n], err
+}
+
+// points4 reads a count followed by that many coordinates with four ordinates.
+func points4(r *Reader, bom binary.ByteOrder) (pts [][4]float64, err error) {
+	num, prealloc, err := r.count(bom, 32)
+	if err != nil {
+		return pts, err
+	}
+	off := r.Offset
+	pts = make([][4]float64, 0, prealloc)
+	for i := 0; i < num; i++ {
+		var pt [4]float64
+		if err = binary.Read(r, bom, &pt); err != nil {
+			return pts, err
+		}
+		pts = append(pts, pt)
+	}
+	n, err := r.line(off, 32, len(pts),
+		func(i int) []float64 { return pts[i][:] },
+		func(to, from int) { pts[to] = pts[from] },
+	)
+	return pts[:n], err
+}
+
+// lineString3 reads a line string with three ordinates.
+func lineString3(r *Reader, bom binary.ByteOrder) (pts [][3]float64, err error) {
+	off := r.Offset
+	if pts, err = points3(r, bom); err != nil {
+		return pts, err
+	}
+	return pts, r.lineString(off, len(pts))
+}
+
+// lineString4 reads a line string with four ordinates.
+func lineString4(r *Reader, bom binary.ByteOrder) (pts [][4]float64, err error) {
+	off := r.Offset
+	if pts, err = points4(r, bom); err != nil {
+		return pts, err
+	}
+	return pts, r.lineString(off, len(pts))
+}
+
+func rings3(r *Reader, bom binary.ByteOrder) (rings [][][3]float64, err error) {
+	// each ring is at least a count
+	num, prealloc, err := r.count(bom, 4)
+	if err != nil {
+		return rings, err
+	}
+	rings = make([][][3]float64, 0, prealloc)
+	for i := 0; i < num; i++ {
+		off := r.Offset
+		rn, err := points3(r, bom)
+		rings = append(rings, rn)
+		if err != nil {
+			return rings, err
+		}
+		n := len(rings[i])
+		closed := n > 1 && rings[i][0] == rings[i][n-1]
+		if err = r.ring(off, n, closed); err != nil {
+			return rings, err
+		}
+		// Remove the last point if it is the same.
+		if closed {
+			rings[i] = rings[i][:n-1]
+		}
+	}
+	return rings, nil
+}
+
+func rings4(r *Reader, bom binary.ByteOrder) (rings [][][4]float64, err error) {
+	// each ring is at least a count
+	num, prealloc, err := r.count(bom, 4)
+	if err != nil {
+		return rings, err
+	}
+	rings = make([][][4]float64, 0, prealloc)
+	for i := 0; i < num; i++ {
+		off := r.Offset
+		rn, err := points4(r, bom)
+		rings = append(rings, rn)
+		if err != nil {
+			return rings, err
+		}
+		n := len(rings[i])
+		closed := n > 1 && rings[i][0] == rings[i][n-1]
+		if err = r.ring(off, n, closed); err != nil {
+			return rings, err
+		}
+		// Remove the last point if it is the same.
+		if closed {
+			rings[i] = rings[i][:n-1]
+		}
+	}
+	return rings, nil
+}
+
+// members reads the number of members of a multi geometry, calling fn for each
+// member after checking that the member is of the expected type.
+func members(r *Reader, bom binary.ByteOrder, primary string, expected uint32, fn func(i int, bom binary.ByteOrder) error) (num int, err error) {
+	// each member is at least a byte order, type and count
+	if num, _, err = r.count(bom, 9); err != nil {
+		return num, err
+	}
+	for i := 0; i < num; i++ {
+		mbom, typ, err := ByteOrderType(r)
+		if err != nil {
+			return num, err
+		}
+		if typ != expected {
+			return num, ErrInvalidType{primary, typ}
+		}
+		if err = fn(i, mbom); err != nil {
+			return num, err
+		}
+	}
+	return num, nil
+}
+
+// membersZM reads the members of a Z, M or ZM collection, calling fn for
+// each member after checking that it has the dimension dim.
+func membersZM(r *Reader, bom binary.ByteOrder, dim uint32, fn func(bom binary.ByteOrder, typ uint32) error) (num int, err error) {
+	// each member is at least a byte order, type and count
+	if num, _, err = r.count(bom, 9); err != nil {
+		return num, err
+	}
+	for i := 0; i < num; i++ {
+		mbom, typ, err := ByteOrderType(r)
+		if err != nil {
+			return num, err
+		}
+		if !IsZM(typ) || typ-typ%consts.Z != dim {
+			return num, ErrInvalidType{"collection", typ}
+		}
+		if err = fn(mbom, typ); err != nil {
+			return num, err
+		}
+	}
+	return num, nil
+}
+
+// GeometryZM decodes the Z, M and ZM variants of the geometry types.
+func GeometryZM(r *Reader, bom binary.ByteOrder, typ uint32) (geo geom.Geometry, err error) {
+	switch typ {
+
+	case consts.Point + consts.Z:
+		var pt geom.PointZ
+		off := r.Offset
+		if err = binary.Read(r, bom, &pt); err != nil {
+			return pt, err
+		}
+		return pt, r.point(off, pt[:])
+	case consts.Point + consts.M:
+		var pt geom.PointM
+		off := r.Offset
+		if err = binary.Read(r, bom, &pt); err != nil {
+			return pt, err
+		}
+		return pt, r.point(off, pt[:])
+	case consts.Point + consts.ZM:
+		var pt geom.PointZM
+		off := r.Offset
+		if err = binary.Read(r, bom, &pt); err != nil {
+			return pt, err
+		}
+		return pt, r.point(off, pt[:])
+
+	case consts.LineString + consts.Z:
+		pts, err := lineString3(r, bom)
+		return geom.LineStringZ(pts), err
+	case consts.LineString + consts.M:
+		pts, err := lineString3(r, bom)
+		return geom.LineStringM(pts), err
+	case consts.LineString + consts.ZM:
+		pts, err := lineString4(r, bom)
+		return geom.LineStringZM(pts), err
+
+	case consts.Polygon + consts.Z:
+		rings, err := rings3(r, bom)
+		return geom.PolygonZ(rings), err
+	case consts.Polygon + consts.M:
+		rings, err := rings3(r, bom)
+		return geom.PolygonM(rings), err
+	case consts.Polygon + consts.ZM:
+		rings, err := rings4(r, bom)
+		return geom.PolygonZM(rings), err
+
+	case consts.MultiPoint + consts.Z, consts.MultiPoint + consts.M:
+		pts := [][3]float64{}
+		_, err = members(r, bom, "multipoint", typ-consts.MultiPoint+consts.Point, func(_ int, bom binary.ByteOrder) error {
+			var pt [3]float64
+			off := r.Offset
+			if err := binary.Read(r, bom, &pt); err != nil {
+				return err
+			}
+			keep, err := r.keepMember(off, pt[:])
+			if keep {
+				pts = append(pts, pt)
+			}
+			return err
+		})
+		if typ == consts.MultiPoint+consts.Z {
+			return geom.MultiPointZ(pts), err
+		}
+		return geom.MultiPointM(pts), err
+	case consts.MultiPoint + consts.ZM:
+		pts := [][4]float64{}
+		_, err = members(r, bom, "multipoint", consts.Point+consts.ZM, func(_ int, bom binary.ByteOrder) error {
+			var pt [4]float64
+			off := r.Offset
+			if err := binary.Read(r, bom, &pt); err != nil {
+				return err
+			}
+			keep, err := r.keepMember(off, pt[:])
+			if keep {
+				pts = append(pts, pt)
+			}
+			return err
+		})
+		return geom.MultiPointZM(pts), err
+
+	case consts.MultiLineString + consts.Z, consts.MultiLineString + consts.M:
+		lns := [][][3]float64{}
+		_, err = members(r, bom, "multilinestring", typ-consts.MultiLineString+consts.LineString, func(_ int, bom binary.ByteOrder) error {
+			ln, err := lineString3(r, bom)
+			lns = append(lns, ln)
+			return err
+		})
+		if typ == consts.MultiLineString+consts.Z {
+			return geom.MultiLineStringZ(lns), err
+		}
+		return geom.MultiLineStringM(lns), err
+	case consts.MultiLineString + consts.ZM:
+		lns := [][][4]float64{}
+		_, err = members(r, bom, "multilinestring", consts.LineString+consts.ZM, func(_ int, bom binary.ByteOrder) error {
+			ln, err := lineString4(r, bom)
+			lns = append(lns, ln)
+			return err
+		})
+		return geom.MultiLineStringZM(lns), err
+
+	case consts.MultiPolygon + consts.Z, consts.MultiPolygon + consts.M:
+		plys := [][][][3]float64{}
+		_, err = members(r, bom, "multipolygon", typ-consts.MultiPolygon+consts.Polygon, func(_ int, bom binary.ByteOrder) error {
+			ply, err := rings3(r, bom)
+			plys = append(plys, ply)
+			return err
+		})
+		if typ == consts.MultiPolygon+consts.Z {
+			return geom.MultiPolygonZ(plys), err
+		}
+		return geom.MultiPolygonM(plys), err
+	case consts.MultiPolygon + consts.ZM:
+		plys := [][][][4]float64{}
+		_, err = members(r, bom, "multipolygon", consts.Polygon+consts.ZM, func(_ int, bom binary.ByteOrder) error {
+			ply, err := rings4(r, bom)
+			plys = append(plys, ply)
+			return err
+		})
+		return geom.MultiPolygonZM(plys), err
+
+	case consts.Collection + consts.Z, consts.Collection + consts.M, consts.Collection + consts.ZM:
+		// The members carry their own type, which must have the same
+		// dimension as the collection.
+		dim := typ - consts.Collection
+		col := geom.Collection{}
+		_, err = membersZM(r, bom, dim, func(mbom binary.ByteOrder, mtyp uint32) error {
+			g, err := GeometryZM(r, mbom, mtyp)
+			col = append(col, g)
+			return err
+		})
+		return col, err
+
+	default:
+		return nil, ErrInvalidType{"geometry", typ}
+	}
+}
@@ -0,0 +1,183 @@
+package decode
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Mode is how tolerant decoding is of invalid geometries.
+type Mode uint8
+
+const (
+	// Default decodes geometries as they are.
+	Default Mode = iota
+	// Strict rejects rings that are not closed or have fewer than four
+	// points, line strings of one point, and coordinates that are not
+	// finite, but for empty points.
+	Strict
+	// Lenient drops the points with coordinates that are not finite, but
+	// for empty points, and makes points with them empty.
+	Lenient
+)
+
+// Reader is what geometries are decoded from. It keeps the offset of the
+// bytes read, for the errors of the Strict mode.
+type Reader struct {
+	R    io.Reader
+	Mode Mode
+	// Offset is the number of bytes read from R
+	Offset int64
+	// Size is the number of bytes of R, counted from the same start as
+	// Offset, or 0 if it is not known
+	Size int64
+}
+
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.R.Read(p)
+	r.Offset += int64(n)
+	return n, err
+}
+
+// ErrCount is a number of points, rings or members read from the input
+// that the rest of the input is too short to hold.
+type ErrCount struct {
+	// Offset is the offset of the number, from the start of the decoding
+	Offset int64
+	Count  uint32
+}
+
+func (e ErrCount) Error() string {
+	return fmt.Sprintf("decode: count %v at byte %v is larger than the input", e.Count, e.Offset)
+}
+
+// maxPrealloc is the most elements preallocated for a count read from an
+// input of unknown size; the rest are appended as they are read.
+const maxPrealloc = 1024
+
+// count reads the number of the elements that follow, each of at least
+// size bytes, returning it with the number of elements to preallocate.
+// The count is not trusted: an ErrCount is returned if the input is known
+// to be too short for it, and the preallocation is capped otherwise.
+func (r *Reader) count(bom binary.ByteOrder, size int64) (num int, prealloc int, err error) {
+	off := r.Offset
+	var n uint32
+	if err = binary.Read(r, bom, &n); err != nil {
+		return 0, 0, err
+	}
+	switch {
+	case r.Size > 0 && int64(n)*size > r.Size-r.Offset:
+		return 0, 0, ErrCount{Offset: off, Count: n}
+	case r.Size <= 0 && n > maxPrealloc:
+		return int(n), maxPrealloc, nil
+	}
+	return int(n), int(n), nil
+}
+
+// ErrInvalid is an invalid geometry found decoding in the Strict mode.
+type ErrInvalid struct {
+	// Offset is the offset of the invalid coordinate, ring or line
+	// string, from the start of the decoding
+	Offset int64
+	Issue  string
+}
+
+func (e ErrInvalid) Error() string {
+	return fmt.Sprintf("decode: %v at byte %v", e.Issue, e.Offset)
+}
+
+// isEmpty returns whether the coordinate is of an empty point, which has
+// NaN ordinates
+func isEmpty(c []float64) bool {
+	for _, v := range c {
+		if !math.IsNaN(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func isFinite(c []float64) bool {
+	for _, v := range c {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// point checks the coordinate of a point read from off, making it empty if
+// it is invalid and the mode is Lenient.
+func (r *Reader) point(off int64, c []float64) error {
+	if r.Mode == Default || isFinite(c) || isEmpty(c) {
+		return nil
+	}
+	if r.Mode == Strict {
+		return ErrInvalid{Offset: off, Issue: "coordinate not finite"}
+	}
+	for i := range c {
+		c[i] = math.NaN()
+	}
+	return nil
+}
+
+// keepMember checks the coordinate of a member of a multipoint read from
+// off, returning whether it is to be kept.
+func (r *Reader) keepMember(off int64, c []float64) (bool, error) {
+	if r.Mode == Default || isFinite(c) || isEmpty(c) {
+		return true, nil
+	}
+	if r.Mode == Strict {
+		return false, ErrInvalid{Offset: off, Issue: "coordinate not finite"}
+	}
+	return false, nil
+}
+
+// line checks the n coordinates of a line string or ring read from off,
+// each of size bytes, moving those that are kept to the front with move.
+// It returns the number kept.
+func (r *Reader) line(off int64, size int64, n int, coord func(i int) []float64, move func(to, from int)) (int, error) {
+	if r.Mode == Default {
+		return n, nil
+	}
+	kept := 0
+	for i := 0; i < n; i++ {
+		if !isFinite(coord(i)) {
+			if r.Mode == Strict {
+				return 0, ErrInvalid{Offset: off + int64(i)*size, Issue: "coordinate not finite"}
+			}
+			continue
+		}
+		if kept != i {
+			move(kept, i)
+		}
+		kept++
+	}
+	return kept, nil
+}
+
+// lineString checks the number of points of a line string read from off,
+// which is the offset of its number of points.
+func (r *Reader) lineString(off int64, n int) error {
+	if r.Mode == Strict && n == 1 {
+		return ErrInvalid{Offset: off, Issue: "line string of one point"}
+	}
+	return nil
+}
+
+// ring checks a ring of n points read from off, of which the first and
+// last are the same if closed. off is the offset of the ring's number of
+// points.
+func (r *Reader) ring(off int64, n int, closed bool) error {
+	if r.Mode != Strict {
+		return nil
+	}
+	if n < 4 {
+		return ErrInvalid{Offset: off, Issue: "ring of fewer than four points"}
+	}
+	if !closed {
+		return ErrInvalid{Offset: off, Issue: "ring not closed"}
+	}
+	return nil
+}
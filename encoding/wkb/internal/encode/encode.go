@@ -3,36 +3,78 @@ package encode
 import (
 	"encoding/binary"
 	"errors"
-	"io"
+	"math"
 
 	"github.com/go-spatial/geom"
 	"github.com/go-spatial/geom/encoding/wkb/internal/consts"
 )
 
+// Encoder appends the binary data of geometries to Buf, without
+// reflection or allocating other than to grow Buf.
 type Encoder struct {
-	// W is the writer to which the binary data will be written to.
-	W io.Writer
+	// Buf is the buffer the binary data is appended to
+	Buf []byte
 	// ByteOrder is the Byte Order Marker, it defaults to binary.LittleEndian
 	ByteOrder binary.ByteOrder
-	err       error
+	// EWKB writes the geometry types with the EWKB flags instead of the
+	// ISO offsets
+	EWKB bool
+	// SRID, if not zero and EWKB is set, is written after the type of the
+	// outermost geometry
+	SRID     uint32
+	sridDone bool
+	err      error
 }
 
 var EncoderIsNilErr = errors.New("Encoder can not be nil")
 
 func (en *Encoder) conti() bool { return !(en == nil || en.err != nil) }
 
-func (en *Encoder) Write(data ...interface{}) *Encoder {
+// Reset starts encoding a new geometry, appending to buf.
+func (en *Encoder) Reset(buf []byte) {
+	en.Buf, en.sridDone, en.err = buf, false, nil
+}
+
+func (en *Encoder) byteOrder() binary.ByteOrder {
+	if en.ByteOrder == nil {
+		en.ByteOrder = binary.LittleEndian
+	}
+	return en.ByteOrder
+}
+
+// Byte appends a byte
+func (en *Encoder) Byte(b byte) *Encoder {
 	if !en.conti() {
 		return en
 	}
-	if en.ByteOrder == nil {
-		en.ByteOrder = binary.LittleEndian
+	en.Buf = append(en.Buf, b)
+	return en
+}
+
+// Uint32 appends the values in the byte order
+func (en *Encoder) Uint32(vs ...uint32) *Encoder {
+	if !en.conti() {
+		return en
 	}
-	for i := range data {
-		en.err = binary.Write(en.W, en.ByteOrder, data[i])
-		if en.err != nil {
-			return en
-		}
+	bo := en.byteOrder()
+	for _, v := range vs {
+		n := len(en.Buf)
+		en.Buf = append(en.Buf, 0, 0, 0, 0)
+		bo.PutUint32(en.Buf[n:], v)
+	}
+	return en
+}
+
+// Float64 appends the values in the byte order
+func (en *Encoder) Float64(vs ...float64) *Encoder {
+	if !en.conti() {
+		return en
+	}
+	bo := en.byteOrder()
+	for _, v := range vs {
+		n := len(en.Buf)
+		en.Buf = append(en.Buf, 0, 0, 0, 0, 0, 0, 0, 0)
+		bo.PutUint64(en.Buf[n:], math.Float64bits(v))
 	}
 	return en
 }
@@ -49,39 +91,60 @@ func (en *Encoder) BOM() *Encoder {
 		return en
 	}
 	if en.ByteOrder != nil && en.ByteOrder == binary.BigEndian {
-		en.Write(byte(0))
-		return en
+		return en.Byte(0)
 	}
-	en.Write(byte(1))
-	return en
+	return en.Byte(1)
+}
+
+// Type writes the byte order marker and the geometry type, which is one of
+// the consts types plus the ISO offset for z and m values.
+func (en *Encoder) Type(typ uint32) *Encoder {
+	en.BOM()
+	if !en.EWKB {
+		return en.Uint32(typ)
+	}
+	ewkb := typ % consts.Z
+	switch typ - ewkb {
+	case consts.Z:
+		ewkb |= consts.EWKBZ
+	case consts.M:
+		ewkb |= consts.EWKBM
+	case consts.ZM:
+		ewkb |= consts.EWKBZ | consts.EWKBM
+	}
+	if en.SRID == 0 || en.sridDone {
+		return en.Uint32(ewkb)
+	}
+	en.sridDone = true
+	return en.Uint32(ewkb|consts.EWKBSRID, en.SRID)
 }
 
 func (en *Encoder) Point(pt [2]float64) {
-	en.BOM().Write(consts.Point, pt[0], pt[1])
+	en.Type(consts.Point).Float64(pt[0], pt[1])
 }
 func (en *Encoder) MultiPoint(pts [][2]float64) {
-	en.BOM().Write(consts.MultiPoint, uint32(len(pts)))
+	en.Type(consts.MultiPoint).Uint32(uint32(len(pts)))
 
 	for _, p := range pts {
 		en.Point(p)
 	}
 }
 func (en *Encoder) LineString(ln [][2]float64) {
-	en.BOM().Write(consts.LineString, uint32(len(ln)))
+	en.Type(consts.LineString).Uint32(uint32(len(ln)))
 	for _, p := range ln {
-		en.Write(p[0], p[1])
+		en.Float64(p[0], p[1])
 	}
 }
 
 func (en *Encoder) MultiLineString(lns [][][2]float64) {
-	en.BOM().Write(consts.MultiLineString, uint32(len(lns)))
+	en.Type(consts.MultiLineString).Uint32(uint32(len(lns)))
 	for _, l := range lns {
 		en.LineString(l)
 	}
 }
 
 func (en *Encoder) Polygon(ply [][][2]float64) {
-	en.BOM().Write(consts.Polygon, uint32(len(ply)))
+	en.Type(consts.Polygon).Uint32(uint32(len(ply)))
 	for _, r := range ply {
 		// close definition is:
 		// •  Verify that the line segments close (z coordinates at start and endpoints must also be the same) and don't cross.
@@ -94,18 +157,18 @@ func (en *Encoder) Polygon(ply [][][2]float64) {
 			length += 1
 			needToClose = true
 		}
-		en.Write(length)
+		en.Uint32(length)
 		for _, pt := range r {
-			en.Write(pt[0], pt[1])
+			en.Float64(pt[0], pt[1])
 		}
 		if needToClose {
-			en.Write(r[0][0], r[0][1])
+			en.Float64(r[0][0], r[0][1])
 		}
 	}
 }
 
 func (en *Encoder) MultiPolygon(mply [][][][2]float64) {
-	en.BOM().Write(consts.MultiPolygon, uint32(len(mply)))
+	en.Type(consts.MultiPolygon).Uint32(uint32(len(mply)))
 	for _, p := range mply {
 		en.Polygon(p)
 	}
@@ -115,7 +178,7 @@ func (en *Encoder) Collection(geoms []geom.Geometry) {
 	if !en.conti() {
 		return
 	}
-	en.BOM().Write(consts.Collection, uint32(len(geoms)))
+	en.Type(consts.Collection).Uint32(uint32(len(geoms)))
 	for _, gg := range geoms {
 		en.Geometry(gg)
 		if !en.conti() {
@@ -144,6 +207,8 @@ func (en *Encoder) Geometry(g geom.Geometry) {
 	case geom.Collectioner:
 		en.Collection(geo.Geometries())
 	default:
-		en.err = geom.ErrUnknownGeometry{Geom: g}
+		if !en.geometryZM(g) {
+			en.err = geom.ErrUnknownGeometry{Geom: g}
+		}
 	}
 }
@@ -0,0 +1,279 @@
+package encode
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/wkb/internal/consts"
+)
+
+func (en *Encoder) coords3(pts [][3]float64) {
+	for _, p := range pts {
+		en.Float64(p[0], p[1], p[2])
+	}
+}
+
+func (en *Encoder) coords4(pts [][4]float64) {
+	for _, p := range pts {
+		en.Float64(p[0], p[1], p[2], p[3])
+	}
+}
+
+func (en *Encoder) ring3(r [][3]float64) {
+	length := uint32(len(r))
+	// See Polygon for the closing rule.
+	needToClose := length > 0 && r[0] != r[length-1]
+	if needToClose {
+		length++
+	}
+	en.Uint32(length)
+	en.coords3(r)
+	if needToClose {
+		en.coords3(r[:1])
+	}
+}
+
+func (en *Encoder) ring4(r [][4]float64) {
+	length := uint32(len(r))
+	// See Polygon for the closing rule.
+	needToClose := length > 0 && r[0] != r[length-1]
+	if needToClose {
+		length++
+	}
+	en.Uint32(length)
+	en.coords4(r)
+	if needToClose {
+		en.coords4(r[:1])
+	}
+}
+
+// Point3 encodes a point with three ordinates; dim should be consts.Z or consts.M
+func (en *Encoder) Point3(dim uint32, pt [3]float64) {
+	en.Type(consts.Point+dim).Float64(pt[0], pt[1], pt[2])
+}
+
+// Point4 encodes a point with z and m values
+func (en *Encoder) Point4(pt [4]float64) {
+	en.Type(consts.Point+consts.ZM).Float64(pt[0], pt[1], pt[2], pt[3])
+}
+
+// MultiPoint3 encodes a multipoint with three ordinates; dim should be consts.Z or consts.M
+func (en *Encoder) MultiPoint3(dim uint32, pts [][3]float64) {
+	en.Type(consts.MultiPoint + dim).Uint32(uint32(len(pts)))
+	for _, p := range pts {
+		en.Point3(dim, p)
+	}
+}
+
+// MultiPoint4 encodes a multipoint with z and m values
+func (en *Encoder) MultiPoint4(pts [][4]float64) {
+	en.Type(consts.MultiPoint + consts.ZM).Uint32(uint32(len(pts)))
+	for _, p := range pts {
+		en.Point4(p)
+	}
+}
+
+// LineString3 encodes a linestring with three ordinates; dim should be consts.Z or consts.M
+func (en *Encoder) LineString3(dim uint32, ln [][3]float64) {
+	en.Type(consts.LineString + dim).Uint32(uint32(len(ln)))
+	en.coords3(ln)
+}
+
+// LineString4 encodes a linestring with z and m values
+func (en *Encoder) LineString4(ln [][4]float64) {
+	en.Type(consts.LineString + consts.ZM).Uint32(uint32(len(ln)))
+	en.coords4(ln)
+}
+
+// MultiLineString3 encodes a multilinestring with three ordinates; dim should be consts.Z or consts.M
+func (en *Encoder) MultiLineString3(dim uint32, lns [][][3]float64) {
+	en.Type(consts.MultiLineString + dim).Uint32(uint32(len(lns)))
+	for _, l := range lns {
+		en.LineString3(dim, l)
+	}
+}
+
+// MultiLineString4 encodes a multilinestring with z and m values
+func (en *Encoder) MultiLineString4(lns [][][4]float64) {
+	en.Type(consts.MultiLineString + consts.ZM).Uint32(uint32(len(lns)))
+	for _, l := range lns {
+		en.LineString4(l)
+	}
+}
+
+// Polygon3 encodes a polygon with three ordinates; dim should be consts.Z or consts.M
+func (en *Encoder) Polygon3(dim uint32, ply [][][3]float64) {
+	en.Type(consts.Polygon + dim).Uint32(uint32(len(ply)))
+	for _, r := range ply {
+		en.ring3(r)
+	}
+}
+
+// Polygon4 encodes a polygon with z and m values
+func (en *Encoder) Polygon4(ply [][][4]float64) {
+	en.Type(consts.Polygon + consts.ZM).Uint32(uint32(len(ply)))
+	for _, r := range ply {
+		en.ring4(r)
+	}
+}
+
+// MultiPolygon3 encodes a multipolygon with three ordinates; dim should be consts.Z or consts.M
+func (en *Encoder) MultiPolygon3(dim uint32, mply [][][][3]float64) {
+	en.Type(consts.MultiPolygon + dim).Uint32(uint32(len(mply)))
+	for _, p := range mply {
+		en.Polygon3(dim, p)
+	}
+}
+
+// MultiPolygon4 encodes a multipolygon with z and m values
+func (en *Encoder) MultiPolygon4(mply [][][][4]float64) {
+	en.Type(consts.MultiPolygon + consts.ZM).Uint32(uint32(len(mply)))
+	for _, p := range mply {
+		en.Polygon4(p)
+	}
+}
+
+// geometryZM encodes the Z, M and ZM geometries, returning false if g is not one
+// of those types.
+// derefZM returns the value a pointer to a Z, M or ZM geometry points
+// to. A nil pointer is returned as an empty geometry.
+func derefZM(g geom.Geometry) geom.Geometry {
+	switch gg := g.(type) {
+	case *geom.PointZ:
+		if gg == nil {
+			return geom.PointZ{math.NaN(), math.NaN(), math.NaN()}
+		}
+		return *gg
+	case *geom.PointM:
+		if gg == nil {
+			return geom.PointM{math.NaN(), math.NaN(), math.NaN()}
+		}
+		return *gg
+	case *geom.PointZM:
+		if gg == nil {
+			return geom.PointZM{math.NaN(), math.NaN(), math.NaN(), math.NaN()}
+		}
+		return *gg
+	case *geom.MultiPointZ:
+		if gg == nil {
+			return geom.MultiPointZ(nil)
+		}
+		return *gg
+	case *geom.MultiPointM:
+		if gg == nil {
+			return geom.MultiPointM(nil)
+		}
+		return *gg
+	case *geom.MultiPointZM:
+		if gg == nil {
+			return geom.MultiPointZM(nil)
+		}
+		return *gg
+	case *geom.LineStringZ:
+		if gg == nil {
+			return geom.LineStringZ(nil)
+		}
+		return *gg
+	case *geom.LineStringM:
+		if gg == nil {
+			return geom.LineStringM(nil)
+		}
+		return *gg
+	case *geom.LineStringZM:
+		if gg == nil {
+			return geom.LineStringZM(nil)
+		}
+		return *gg
+	case *geom.MultiLineStringZ:
+		if gg == nil {
+			return geom.MultiLineStringZ(nil)
+		}
+		return *gg
+	case *geom.MultiLineStringM:
+		if gg == nil {
+			return geom.MultiLineStringM(nil)
+		}
+		return *gg
+	case *geom.MultiLineStringZM:
+		if gg == nil {
+			return geom.MultiLineStringZM(nil)
+		}
+		return *gg
+	case *geom.PolygonZ:
+		if gg == nil {
+			return geom.PolygonZ(nil)
+		}
+		return *gg
+	case *geom.PolygonM:
+		if gg == nil {
+			return geom.PolygonM(nil)
+		}
+		return *gg
+	case *geom.PolygonZM:
+		if gg == nil {
+			return geom.PolygonZM(nil)
+		}
+		return *gg
+	case *geom.MultiPolygonZ:
+		if gg == nil {
+			return geom.MultiPolygonZ(nil)
+		}
+		return *gg
+	case *geom.MultiPolygonM:
+		if gg == nil {
+			return geom.MultiPolygonM(nil)
+		}
+		return *gg
+	case *geom.MultiPolygonZM:
+		if gg == nil {
+			return geom.MultiPolygonZM(nil)
+		}
+		return *gg
+	default:
+		return g
+	}
+}
+
+func (en *Encoder) geometryZM(g geom.Geometry) bool {
+	switch geo := derefZM(g).(type) {
+	case geom.PointZ:
+		en.Point3(consts.Z, geo)
+	case geom.PointM:
+		en.Point3(consts.M, geo)
+	case geom.PointZM:
+		en.Point4(geo)
+	case geom.MultiPointZ:
+		en.MultiPoint3(consts.Z, geo)
+	case geom.MultiPointM:
+		en.MultiPoint3(consts.M, geo)
+	case geom.MultiPointZM:
+		en.MultiPoint4(geo)
+	case geom.LineStringZ:
+		en.LineString3(consts.Z, geo)
+	case geom.LineStringM:
+		en.LineString3(consts.M, geo)
+	case geom.LineStringZM:
+		en.LineString4(geo)
+	case geom.MultiLineStringZ:
+		en.MultiLineString3(consts.Z, geo)
+	case geom.MultiLineStringM:
+		en.MultiLineString3(consts.M, geo)
+	case geom.MultiLineStringZM:
+		en.MultiLineString4(geo)
+	case geom.PolygonZ:
+		en.Polygon3(consts.Z, geo)
+	case geom.PolygonM:
+		en.Polygon3(consts.M, geo)
+	case geom.PolygonZM:
+		en.Polygon4(geo)
+	case geom.MultiPolygonZ:
+		en.MultiPolygon3(consts.Z, geo)
+	case geom.MultiPolygonM:
+		en.MultiPolygon3(consts.M, geo)
+	case geom.MultiPolygonZM:
+		en.MultiPolygon4(geo)
+	default:
+		return false
+	}
+	return true
+}
@@ -633,6 +633,16 @@ Loop:
 			}
 			geo = append(geo, py)
 
+		case symbol.Letter:
+			if !started {
+				return nil, fmt.Errorf("Expected '((' found a possible Z, M or ZM geometry.")
+			}
+			g, err := t.ParseZMField()
+			if err != nil {
+				return nil, err
+			}
+			geo = append(geo, g)
+
 		case symbol.Cdpren:
 			t.Scan()
 			return geo, nil
@@ -718,6 +728,10 @@ Loop:
 			}
 			return py, nil
 
+		case symbol.Letter:
+			// Z, M or ZM geometry.
+			return t.ParseZMField()
+
 		default:
 			break Loop
 		}
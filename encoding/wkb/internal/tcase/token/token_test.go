@@ -434,6 +434,77 @@ func TestParseCollection(t *testing.T) {
 	}
 }
 
+func TestParseZMField(t *testing.T) {
+	type tcase struct {
+		input string
+		exp   geom.Geometry
+		err   string
+	}
+	fn := func(t *testing.T, tc tcase) {
+		tt := NewT(strings.NewReader(tc.input))
+		g, err := tt.ParseZMField()
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+			}
+			return
+		}
+		if err != nil {
+			t.Errorf("error, expected nil got %v", err)
+			return
+		}
+		if !reflect.DeepEqual(tc.exp, g) {
+			t.Errorf("parse zm, \nexpected (%#v) %[1]v \ngot      (%#v) %[2]v", tc.exp, g)
+		}
+	}
+	tests := map[string]tcase{
+		"point z": {
+			input: "Z 1,2,3",
+			exp:   geom.PointZ{1, 2, 3},
+		},
+		"multipoint m": {
+			input: "M ( 1,2,3 4,5,6 )",
+			exp:   geom.MultiPointM{{1, 2, 3}, {4, 5, 6}},
+		},
+		"linestring zm": {
+			input: "ZM [ 1,2,3,4 5,6,7,8 ]",
+			exp:   geom.LineStringZM{{1, 2, 3, 4}, {5, 6, 7, 8}},
+		},
+		"multilinestring z": {
+			input: "Z [[ [ 1,2,3 4,5,6 ] ]]",
+			exp:   geom.MultiLineStringZ{{{1, 2, 3}, {4, 5, 6}}},
+		},
+		"polygon m": {
+			input: "M { [ 0,0,1 10,0,2 10,10,3 ] }",
+			exp:   geom.PolygonM{{{0, 0, 1}, {10, 0, 2}, {10, 10, 3}}},
+		},
+		"multipolygon zm": {
+			input: "ZM {{ { [ 0,0,1,1 10,0,2,2 10,10,3,3 ] } }}",
+			exp:   geom.MultiPolygonZM{{{{0, 0, 1, 1}, {10, 0, 2, 2}, {10, 10, 3, 3}}}},
+		},
+		"empty linestring z": {
+			input: "Z [ ]",
+			exp:   geom.LineStringZ{},
+		},
+		"collection z": {
+			input: "Z (( 1,2,3 [ 1,2,3 4,5,6 ] ))",
+			exp:   geom.Collection{geom.PointZ{1, 2, 3}, geom.LineStringZ{{1, 2, 3}, {4, 5, 6}}},
+		},
+		"bad dimension": {
+			input: "Q 1,2,3",
+			err:   "Expected “Z”, “M” or “ZM” not 'Q'",
+		},
+		"too few ordinates": {
+			input: "ZM 1,2,3",
+			err:   "expected a ',' not ''",
+		},
+	}
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
 func TestParseBinary(t *testing.T) {
 	type tcase struct {
 		input string
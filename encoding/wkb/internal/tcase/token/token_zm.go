@@ -0,0 +1,278 @@
+package token
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/wkb/internal/tcase/symbol"
+)
+
+// ParseZMField parses a Z, M or ZM geometry. The geometry is written as a
+// 2D geometry with the dimension in front of it, and the extra ordinates
+// added to each coordinate:
+//	Z [ 1,2,3 4,5,6 ]
+//	ZM {{ { [ 0,0,1,1 10,0,2,2 10,10,3,3 ] } }}
+// The members of a collection have the same dimension as the collection.
+func (t *T) ParseZMField() (geom.Geometry, error) {
+	t.EatCommentsAndSpaces()
+	if t.Peek() != symbol.Letter {
+		return nil, fmt.Errorf("Expected “Z”, “M” or “ZM” not '%v'", t.NextText())
+	}
+	dim := strings.ToLower(t.NextText())
+	switch dim {
+	case "z", "m", "zm":
+	default:
+		return nil, fmt.Errorf("Expected “Z”, “M” or “ZM” not '%v'", t.NextText())
+	}
+	t.Scan()
+	return t.parseZMGeometry(dim)
+}
+
+func (t *T) parseZMGeometry(dim string) (geom.Geometry, error) {
+	n := 3
+	if dim == "zm" {
+		n = 4
+	}
+
+	t.EatCommentsAndSpaces()
+	switch t.Peek() {
+	case symbol.Digit, symbol.Dot, symbol.Dash, symbol.Plus:
+		pt, err := t.parseCoord(n)
+		if err != nil {
+			return nil, err
+		}
+		switch dim {
+		case "z":
+			return geom.PointZ(coords3([][]float64{pt})[0]), nil
+		case "m":
+			return geom.PointM(coords3([][]float64{pt})[0]), nil
+		default:
+			return geom.PointZM(coords4([][]float64{pt})[0]), nil
+		}
+
+	case symbol.Pren:
+		pts, err := t.parseCoords(n, symbol.Pren, symbol.Cpren)
+		if err != nil {
+			return nil, err
+		}
+		switch dim {
+		case "z":
+			return geom.MultiPointZ(coords3(pts)), nil
+		case "m":
+			return geom.MultiPointM(coords3(pts)), nil
+		default:
+			return geom.MultiPointZM(coords4(pts)), nil
+		}
+
+	case symbol.Bracket:
+		pts, err := t.parseCoords(n, symbol.Bracket, symbol.Cbracket)
+		if err != nil {
+			return nil, err
+		}
+		switch dim {
+		case "z":
+			return geom.LineStringZ(coords3(pts)), nil
+		case "m":
+			return geom.LineStringM(coords3(pts)), nil
+		default:
+			return geom.LineStringZM(coords4(pts)), nil
+		}
+
+	case symbol.Dbracket:
+		lns, err := t.parseCoordLists(n, symbol.Dbracket, symbol.Cdbracket)
+		if err != nil {
+			return nil, err
+		}
+		switch dim {
+		case "z":
+			return geom.MultiLineStringZ(lines3(lns)), nil
+		case "m":
+			return geom.MultiLineStringM(lines3(lns)), nil
+		default:
+			return geom.MultiLineStringZM(lines4(lns)), nil
+		}
+
+	case symbol.Brace:
+		lns, err := t.parseCoordLists(n, symbol.Brace, symbol.Cbrace)
+		if err != nil {
+			return nil, err
+		}
+		switch dim {
+		case "z":
+			return geom.PolygonZ(lines3(lns)), nil
+		case "m":
+			return geom.PolygonM(lines3(lns)), nil
+		default:
+			return geom.PolygonZM(lines4(lns)), nil
+		}
+
+	case symbol.Dbrace:
+		t.Scan()
+		var plys [][][][]float64
+		for {
+			t.EatCommentsAndSpaces()
+			if t.Peek() == symbol.Cdbrace {
+				t.Scan()
+				break
+			}
+			lns, err := t.parseCoordLists(n, symbol.Brace, symbol.Cbrace)
+			if err != nil {
+				return nil, err
+			}
+			plys = append(plys, lns)
+		}
+		switch dim {
+		case "z":
+			mply := make(geom.MultiPolygonZ, len(plys))
+			for i := range plys {
+				mply[i] = lines3(plys[i])
+			}
+			return mply, nil
+		case "m":
+			mply := make(geom.MultiPolygonM, len(plys))
+			for i := range plys {
+				mply[i] = lines3(plys[i])
+			}
+			return mply, nil
+		default:
+			mply := make(geom.MultiPolygonZM, len(plys))
+			for i := range plys {
+				mply[i] = lines4(plys[i])
+			}
+			return mply, nil
+		}
+
+	case symbol.Dpren:
+		t.Scan()
+		col := geom.Collection{}
+		for {
+			t.EatCommentsAndSpaces()
+			if t.Peek() == symbol.Cdpren {
+				t.Scan()
+				return col, nil
+			}
+			if t.AtEnd() {
+				return nil, fmt.Errorf("Expected geometry or '))' not end of file.")
+			}
+			g, err := t.parseZMGeometry(dim)
+			if err != nil {
+				return nil, err
+			}
+			col = append(col, g)
+		}
+
+	default:
+		return nil, fmt.Errorf("Expected point, polygon, linestring or the multivations not '%v'", t.NextText())
+	}
+}
+
+// parseCoord parses n comma separated ordinates.
+func (t *T) parseCoord(n int) (pt []float64, err error) {
+	for i := 0; i < n; i++ {
+		t.EatSpace()
+		if i != 0 {
+			if t.Peek() != symbol.Comma {
+				return nil, fmt.Errorf("expected a ',' not '%v'", t.NextText())
+			}
+			t.Scan()
+			t.EatSpace()
+		}
+		switch t.Peek() {
+		case symbol.Digit, symbol.Dot, symbol.Dash, symbol.Plus:
+		default:
+			return nil, fmt.Errorf("expected a number not '%v'", t.NextText())
+		}
+		f, err := t.ParseFloat64()
+		if err != nil {
+			return nil, err
+		}
+		pt = append(pt, f)
+	}
+	return pt, nil
+}
+
+// parseCoords parses the coordinates between the open and close symbols.
+func (t *T) parseCoords(n int, open, close byte) ([][]float64, error) {
+	t.EatCommentsAndSpaces()
+	if t.Peek() != open {
+		return nil, fmt.Errorf("Expected start of coordinates not '%v'", t.NextText())
+	}
+	t.Scan()
+	pts := [][]float64{}
+	for {
+		t.EatCommentsAndSpaces()
+		switch t.Peek() {
+		case close:
+			t.Scan()
+			return pts, nil
+		case symbol.Digit, symbol.Dot, symbol.Dash, symbol.Plus:
+			pt, err := t.parseCoord(n)
+			if err != nil {
+				return nil, err
+			}
+			pts = append(pts, pt)
+		default:
+			return nil, fmt.Errorf("Expected point or end of coordinates not '%v'", t.NextText())
+		}
+	}
+}
+
+// parseCoordLists parses the linestrings, in '[' and ']', between the
+// open and close symbols.
+func (t *T) parseCoordLists(n int, open, close byte) ([][][]float64, error) {
+	t.EatCommentsAndSpaces()
+	if t.Peek() != open {
+		return nil, fmt.Errorf("Expected start of linestrings not '%v'", t.NextText())
+	}
+	t.Scan()
+	lns := [][][]float64{}
+	for {
+		t.EatCommentsAndSpaces()
+		switch t.Peek() {
+		case close:
+			t.Scan()
+			return lns, nil
+		case symbol.Bracket:
+			ln, err := t.parseCoords(n, symbol.Bracket, symbol.Cbracket)
+			if err != nil {
+				return nil, err
+			}
+			lns = append(lns, ln)
+		default:
+			return nil, fmt.Errorf("Expected linestring or end of linestrings not '%v'", t.NextText())
+		}
+	}
+}
+
+func coords3(pts [][]float64) [][3]float64 {
+	c := make([][3]float64, len(pts))
+	for i := range pts {
+		copy(c[i][:], pts[i])
+	}
+	return c
+}
+
+func coords4(pts [][]float64) [][4]float64 {
+	c := make([][4]float64, len(pts))
+	for i := range pts {
+		copy(c[i][:], pts[i])
+	}
+	return c
+}
+
+func lines3(lns [][][]float64) [][][3]float64 {
+	c := make([][][3]float64, len(lns))
+	for i := range lns {
+		c[i] = coords3(lns[i])
+	}
+	return c
+}
+
+func lines4(lns [][][]float64) [][][4]float64 {
+	c := make([][][4]float64, len(lns))
+	for i := range lns {
+		c[i] = coords4(lns[i])
+	}
+	return c
+}
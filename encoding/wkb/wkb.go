@@ -38,19 +38,93 @@ const (
 	Collection      = consts.Collection
 )
 
+// DecodeMode is how tolerant decoding is of invalid geometries.
+type DecodeMode = decode.Mode
+
+const (
+	// DecodeDefault decodes geometries as they are.
+	DecodeDefault = decode.Default
+	// DecodeStrict rejects rings that are not closed or have fewer than
+	// four points, line strings of one point, and coordinates that are not
+	// finite, with an ErrInvalid giving the offset of the problem. Points
+	// of NaNs are empty points, which are not rejected.
+	DecodeStrict = decode.Strict
+	// DecodeLenient drops the points with coordinates that are not finite
+	// from line strings, rings and multipoints, and makes points with them
+	// empty. Rings that are not closed are taken as closed, as they are
+	// with DecodeDefault.
+	DecodeLenient = decode.Lenient
+)
+
+// ErrInvalid is returned for an invalid geometry decoded with DecodeStrict.
+// Offset is the offset of the invalid coordinate, or of the number of
+// points of the invalid ring or line string, from the start of the
+// decoding.
+type ErrInvalid = decode.ErrInvalid
+
+// ErrCount is returned for a number of points, rings or members larger
+// than the rest of the input can hold. The size of the input is known when
+// decoding from a byte slice, or from a reader with a Len method such as
+// *bytes.Reader; otherwise the input ends before the count is reached.
+// Offset is the offset of the number, from the start of the decoding.
+type ErrCount = decode.ErrCount
+
+// DecodeOption changes how geometries are decoded.
+type DecodeOption func(*decode.Reader)
+
+// WithDecodeMode decodes geometries with the mode, instead of
+// DecodeDefault.
+func WithDecodeMode(mode DecodeMode) DecodeOption {
+	return func(r *decode.Reader) { r.Mode = mode }
+}
+
+func newReader(r io.Reader, opts []DecodeOption) *decode.Reader {
+	dr := &decode.Reader{R: r}
+	// readers of bytes in memory know how many are left, so counts in
+	// the input larger than it can hold are rejected
+	if l, ok := r.(interface{ Len() int }); ok {
+		dr.Size = int64(l.Len())
+	}
+	for _, opt := range opts {
+		opt(dr)
+	}
+	return dr
+}
+
 // DecodeBytes will attempt to decode a geometry encoded as WKB into a geom.Geometry.
-func DecodeBytes(b []byte) (geo geom.Geometry, err error) {
+func DecodeBytes(b []byte, opts ...DecodeOption) (geo geom.Geometry, err error) {
 	buff := bytes.NewReader(b)
-	return Decode(buff)
+	return Decode(buff, opts...)
 }
 
 // Decode will attempt to decode a geometry encoded as WKB into a geom.Geometry.
-func Decode(r io.Reader) (geo geom.Geometry, err error) {
+// EWKB, as written by PostGIS, is decoded as well; any SRID is dropped, use
+// DecodeWithSRID to get it.
+func Decode(r io.Reader, opts ...DecodeOption) (geo geom.Geometry, err error) {
+	geo, _, err = DecodeWithSRID(r, opts...)
+	return geo, err
+}
+
+// DecodeBytesWithSRID is DecodeWithSRID for a geometry held in b.
+func DecodeBytesWithSRID(b []byte, opts ...DecodeOption) (geo geom.Geometry, srid uint32, err error) {
+	return DecodeWithSRID(bytes.NewReader(b), opts...)
+}
 
-	bom, typ, err := decode.ByteOrderType(r)
+// DecodeWithSRID decodes a geometry encoded as WKB or EWKB, returning the
+// SRID of the EWKB geometry; the SRID is 0 if there is none.
+func DecodeWithSRID(r io.Reader, opts ...DecodeOption) (geo geom.Geometry, srid uint32, err error) {
+	dr := newReader(r, opts)
+	bom, typ, srid, err := decode.ByteOrderTypeSRID(dr)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
+	geo, err = decodeGeometry(dr, bom, typ)
+	return geo, srid, err
+}
+
+// decodeGeometry decodes the body of a geometry of the given type whose byte
+// order and type have already been read.
+func decodeGeometry(r *decode.Reader, bom binary.ByteOrder, typ uint32) (geom.Geometry, error) {
 	switch typ {
 	case Point:
 		pt, err := decode.Point(r, bom)
@@ -74,24 +148,111 @@ func Decode(r io.Reader) (geo geom.Geometry, err error) {
 		col, err := decode.Collection(r, bom)
 		return col, err
 	default:
+		if decode.IsZM(typ) {
+			return decode.GeometryZM(r, bom, typ)
+		}
 		return nil, ErrUnknownGeometryType{typ}
 	}
 }
 
-func EncodeBytes(g geom.Geometry) (bs []byte, err error) {
-	buff := new(bytes.Buffer)
-	if err = Encode(buff, g); err != nil {
-		return nil, err
-	}
-	return buff.Bytes(), nil
+// EncodeOption changes how a geometry is encoded.
+type EncodeOption func(*encode.Encoder)
+
+// WithByteOrder encodes the geometry in the byte order, instead of little
+// endian.
+func WithByteOrder(byteOrder binary.ByteOrder) EncodeOption {
+	return func(en *encode.Encoder) { en.ByteOrder = byteOrder }
 }
 
-func Encode(w io.Writer, g geom.Geometry) error {
-	return EncodeWithByteOrder(binary.LittleEndian, w, g)
+// WithEWKB encodes the geometry as EWKB, as used by PostGIS, instead of
+// ISO WKB. The two only differ for geometries with z or m values.
+func WithEWKB() EncodeOption {
+	return func(en *encode.Encoder) { en.EWKB = true }
+}
+
+// WithSRID encodes the geometry as EWKB with the SRID. An SRID of 0 is the
+// same as WithEWKB.
+func WithSRID(srid uint32) EncodeOption {
+	return func(en *encode.Encoder) { en.EWKB, en.SRID = true, srid }
+}
+
+func EncodeBytes(g geom.Geometry, opts ...EncodeOption) (bs []byte, err error) {
+	return NewEncoder(opts...).Append(nil, g)
+}
+
+func Encode(w io.Writer, g geom.Geometry, opts ...EncodeOption) error {
+	return NewEncoder(opts...).Encode(w, g)
 }
 
 func EncodeWithByteOrder(byteOrder binary.ByteOrder, w io.Writer, g geom.Geometry) error {
-	en := encode.Encoder{W: w, ByteOrder: byteOrder}
+	return Encode(w, g, WithByteOrder(byteOrder))
+}
+
+// AppendGeometry appends the little endian ISO WKB of the geometry to dst,
+// returning the extended buffer. It only allocates if dst has to grow, so
+// reusing the buffer, such as dst[:0], encodes many geometries without
+// allocating. dst is returned as it was if there is an error.
+func AppendGeometry(dst []byte, g geom.Geometry) ([]byte, error) {
+	en := encode.Encoder{Buf: dst, ByteOrder: binary.LittleEndian}
 	en.Geometry(g)
-	return en.Err()
+	if err := en.Err(); err != nil {
+		return dst, err
+	}
+	return en.Buf, nil
+}
+
+// Encoder encodes geometries with the options it is made with, using a
+// scratch buffer it keeps between calls, so once the buffer has grown to
+// the size of the geometries it does not allocate. An Encoder is not safe
+// to use from more than one goroutine at a time.
+type Encoder struct {
+	en      encode.Encoder
+	scratch []byte
+}
+
+// NewEncoder returns an Encoder of geometries, as little endian ISO WKB
+// unless the options change it.
+func NewEncoder(opts ...EncodeOption) *Encoder {
+	e := &Encoder{en: encode.Encoder{ByteOrder: binary.LittleEndian}}
+	for _, opt := range opts {
+		opt(&e.en)
+	}
+	return e
+}
+
+// Append appends the WKB of the geometry to dst, returning the extended
+// buffer; see AppendGeometry.
+func (e *Encoder) Append(dst []byte, g geom.Geometry) ([]byte, error) {
+	e.en.Reset(dst)
+	e.en.Geometry(g)
+	buf, err := e.en.Buf, e.en.Err()
+	// let go of dst
+	e.en.Reset(nil)
+	if err != nil {
+		return dst, err
+	}
+	return buf, nil
+}
+
+// Bytes returns the WKB of the geometry in the scratch buffer of the
+// Encoder, which is overwritten by the next call; copy the bytes to keep
+// them.
+func (e *Encoder) Bytes(g geom.Geometry) ([]byte, error) {
+	buf, err := e.Append(e.scratch[:0], g)
+	if err != nil {
+		return nil, err
+	}
+	e.scratch = buf
+	return buf, nil
+}
+
+// Encode writes the WKB of the geometry to w, nothing being written if
+// there is an error encoding it.
+func (e *Encoder) Encode(w io.Writer, g geom.Geometry) error {
+	buf, err := e.Bytes(g)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
 }
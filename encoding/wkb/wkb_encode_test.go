@@ -1,10 +1,13 @@
 package wkb_test
 
 import (
+	"bytes"
+	"encoding/binary"
 	"log"
 	"reflect"
 	"testing"
 
+	"github.com/go-spatial/geom"
 	"github.com/go-spatial/geom/encoding/wkb"
 	"github.com/go-spatial/geom/encoding/wkb/internal/tcase"
 )
@@ -22,7 +25,13 @@ func TestWKBEncode(t *testing.T) {
 			t.Skip("instructed to skip.")
 		}
 
-		bs, err := wkb.EncodeBytes(tc.Expected)
+		bom := tc.BOM
+		if bom == nil {
+			bom = binary.LittleEndian
+		}
+		buff := new(bytes.Buffer)
+		err := wkb.EncodeWithByteOrder(bom, buff, tc.Expected)
+		bs := buff.Bytes()
 		if err != nil {
 			log.Println("TestCase:", tc)
 			t.Errorf("error, expected nil got %v", err)
@@ -31,6 +40,16 @@ func TestWKBEncode(t *testing.T) {
 		if !reflect.DeepEqual(bs, tc.Bytes) {
 			t.Errorf(" encoded geometry, expected %v got %v", tcase.SprintBinary(tc.Bytes, "\t"), tcase.SprintBinary(bs, "\t"))
 		}
+
+		// appended after what is in the buffer already
+		prefix := []byte{0xff}
+		bs, err = wkb.NewEncoder(wkb.WithByteOrder(bom)).Append(prefix, tc.Expected)
+		if err != nil {
+			t.Fatalf("append error, expected nil got %v", err)
+		}
+		if bs[0] != 0xff || !reflect.DeepEqual(bs[1:], tc.Bytes) {
+			t.Errorf("appended geometry, expected %v got %v", tcase.SprintBinary(tc.Bytes, "\t"), tcase.SprintBinary(bs, "\t"))
+		}
 	}
 
 	for _, fname = range fnames {
@@ -47,3 +66,165 @@ func TestWKBEncode(t *testing.T) {
 		})
 	}
 }
+
+func TestEWKB(t *testing.T) {
+	type ecase struct {
+		g    geom.Geometry
+		opts []wkb.EncodeOption
+		srid uint32
+		bs   []byte
+	}
+
+	fn := func(tc ecase) func(*testing.T) {
+		return func(t *testing.T) {
+			bs, err := wkb.EncodeBytes(tc.g, tc.opts...)
+			if err != nil {
+				t.Fatalf("encode error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(bs, tc.bs) {
+				t.Errorf("encoded geometry, expected %v got %v", tcase.SprintBinary(tc.bs, "\t"), tcase.SprintBinary(bs, "\t"))
+			}
+
+			g, srid, err := wkb.DecodeBytesWithSRID(tc.bs)
+			if err != nil {
+				t.Fatalf("decode error, expected nil got %v", err)
+			}
+			if srid != tc.srid {
+				t.Errorf("srid, expected %v got %v", tc.srid, srid)
+			}
+			if !reflect.DeepEqual(g, tc.g) {
+				t.Errorf("decoded geometry, expected %v got %v", tc.g, g)
+			}
+		}
+	}
+
+	tests := map[string]ecase{
+		"point srid": {
+			g:    geom.Point{1, 2},
+			opts: []wkb.EncodeOption{wkb.WithSRID(4326)},
+			srid: 4326,
+			bs: []byte{
+				0x01, 0x01, 0x00, 0x00, 0x20, 0xe6, 0x10, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40,
+			},
+		},
+		"point srid big endian": {
+			g:    geom.Point{1, 2},
+			opts: []wkb.EncodeOption{wkb.WithSRID(4326), wkb.WithByteOrder(binary.BigEndian)},
+			srid: 4326,
+			bs: []byte{
+				0x00, 0x20, 0x00, 0x00, 0x01, 0x00, 0x00, 0x10, 0xe6,
+				0x3f, 0xf0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		"point z": {
+			g:    geom.PointZ{1, 2, 3},
+			opts: []wkb.EncodeOption{wkb.WithEWKB()},
+			bs: []byte{
+				0x01, 0x01, 0x00, 0x00, 0x80,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x40,
+			},
+		},
+		"multipoint m srid": {
+			// only the outer geometry has the srid
+			g:    geom.MultiPointM{{1, 2, 3}},
+			opts: []wkb.EncodeOption{wkb.WithSRID(3857)},
+			srid: 3857,
+			bs: []byte{
+				0x01, 0x04, 0x00, 0x00, 0x60, 0x11, 0x0f, 0x00, 0x00,
+				0x01, 0x00, 0x00, 0x00,
+				0x01, 0x01, 0x00, 0x00, 0x40,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x40,
+			},
+		},
+		"collection srid": {
+			g:    geom.Collection{geom.Point{1, 2}},
+			opts: []wkb.EncodeOption{wkb.WithSRID(4326)},
+			srid: 4326,
+			bs: []byte{
+				0x01, 0x07, 0x00, 0x00, 0x20, 0xe6, 0x10, 0x00, 0x00,
+				0x01, 0x00, 0x00, 0x00,
+				0x01, 0x01, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestAppendGeometry(t *testing.T) {
+	geoms := []geom.Geometry{
+		geom.Point{1, 2},
+		geom.LineString{{0, 0}, {1, 1}, {2, 0}},
+		geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+		geom.Collection{geom.Point{3, 4}, geom.MultiPoint{{5, 6}}},
+		geom.LineStringZ{{0, 0, 1}, {1, 1, 2}},
+	}
+
+	// the encoders agree
+	var buf []byte
+	enc := wkb.NewEncoder()
+	for _, g := range geoms {
+		expected, err := wkb.EncodeBytes(g)
+		if err != nil {
+			t.Fatalf("encode error, expected nil got %v", err)
+		}
+		buf, err = wkb.AppendGeometry(buf[:0], g)
+		if err != nil {
+			t.Fatalf("append error, expected nil got %v", err)
+		}
+		if !bytes.Equal(buf, expected) {
+			t.Errorf("appended %v, expected %v got %v", g, expected, buf)
+		}
+		bs, err := enc.Bytes(g)
+		if err != nil {
+			t.Fatalf("encoder error, expected nil got %v", err)
+		}
+		if !bytes.Equal(bs, expected) {
+			t.Errorf("encoder %v, expected %v got %v", g, expected, bs)
+		}
+	}
+
+	// dst is returned as it was for a geometry that can not be encoded
+	dst := []byte{1, 2, 3}
+	got, err := wkb.AppendGeometry(dst, geom.Collection{geom.Point{1, 1}, struct{}{}})
+	if err == nil {
+		t.Errorf("error, expected an error got nil")
+	}
+	if !bytes.Equal(got, []byte{1, 2, 3}) {
+		t.Errorf("dst, expected %v got %v", []byte{1, 2, 3}, got)
+	}
+
+	// once the buffers have grown, encoding does not allocate
+	for _, g := range geoms {
+		if allocs := testing.AllocsPerRun(100, func() {
+			buf, _ = wkb.AppendGeometry(buf[:0], g)
+		}); allocs != 0 {
+			t.Errorf("append %v allocations, expected 0 got %v", g, allocs)
+		}
+		if allocs := testing.AllocsPerRun(100, func() {
+			_, _ = enc.Bytes(g)
+		}); allocs != 0 {
+			t.Errorf("encoder %v allocations, expected 0 got %v", g, allocs)
+		}
+	}
+}
+
+func BenchmarkAppendGeometry(b *testing.B) {
+	var ply geom.Geometry = geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, {{2, 2}, {2, 4}, {4, 4}, {4, 2}}}
+	var buf []byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf, _ = wkb.AppendGeometry(buf[:0], ply)
+	}
+}
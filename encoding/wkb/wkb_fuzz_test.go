@@ -0,0 +1,37 @@
+//go:build go1.18
+// +build go1.18
+
+package wkb_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/go-spatial/geom/encoding/wkb"
+)
+
+func FuzzDecodeBytes(f *testing.F) {
+	for _, seed := range []string{
+		"0101000000" + "000000000000f03f" + "0000000000000040",
+		"0102000000" + "02000000" + "0000000000000000" + "0000000000000000" + "000000000000f03f" + "000000000000f03f",
+		"0103000000" + "01000000" + "04000000" + "0000000000000000" + "0000000000000000" + "000000000000f03f" + "0000000000000000" + "000000000000f03f" + "000000000000f03f",
+		// a multipoint of 0x7fffffff points in nine bytes
+		"0104000000" + "ffffff7f",
+		"0107000000" + "ffffff7f",
+		"01ea030000" + "ffffff7f",
+	} {
+		b, err := hex.DecodeString(seed)
+		if err != nil {
+			f.Fatalf("hex, expected nil got %v", err)
+		}
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		// any input must decode or fail without panicking or running out
+		// of memory, in every mode
+		for _, mode := range []wkb.DecodeMode{wkb.DecodeDefault, wkb.DecodeStrict, wkb.DecodeLenient} {
+			wkb.DecodeBytes(b, wkb.WithDecodeMode(mode))
+		}
+	})
+}
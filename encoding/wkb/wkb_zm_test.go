@@ -0,0 +1,44 @@
+package wkb_test
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/wkb"
+)
+
+func TestWKBZMPointers(t *testing.T) {
+	type tcase struct {
+		geo geom.Geometry
+		exp geom.Geometry
+	}
+	nan := math.NaN()
+	tests := map[string]tcase{
+		"point z":            {geo: &geom.PointZ{1, 2, 3}, exp: geom.PointZ{1, 2, 3}},
+		"nil point zm":       {geo: (*geom.PointZM)(nil), exp: geom.PointZM{nan, nan, nan, nan}},
+		"linestring z":       {geo: &geom.LineStringZ{{1, 2, 3}, {4, 5, 6}}, exp: geom.LineStringZ{{1, 2, 3}, {4, 5, 6}}},
+		"nil linestring m":   {geo: (*geom.LineStringM)(nil), exp: geom.LineStringM{}},
+		"multipolygon zm":    {geo: &geom.MultiPolygonZM{{{{0, 0, 1, 1}, {10, 0, 2, 2}, {10, 10, 3, 3}}}}, exp: geom.MultiPolygonZM{{{{0, 0, 1, 1}, {10, 0, 2, 2}, {10, 10, 3, 3}}}}},
+		"nil multipolygon z": {geo: (*geom.MultiPolygonZ)(nil), exp: geom.MultiPolygonZ{}},
+	}
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			bs, err := wkb.EncodeBytes(tc.geo)
+			if err != nil {
+				t.Fatalf("encode, expected nil got %v", err)
+			}
+			got, err := wkb.DecodeBytes(bs)
+			if err != nil {
+				t.Fatalf("decode, expected nil got %v", err)
+			}
+			// NaN != NaN, so compare the printed values
+			if fmt.Sprint(got) != fmt.Sprint(tc.exp) || reflect.TypeOf(got) != reflect.TypeOf(tc.exp) {
+				t.Errorf("decode, expected %v got %v", tc.exp, got)
+			}
+		})
+	}
+}
@@ -45,6 +45,7 @@ const (
 	Empty              // EMPTY
 	ZM                 // ZM
 	M                  // M
+	Z                  // Z
 	GeometryCollection // GEOMETRYCOLLECTION
 	Point              // POINT
 	Multipoint         // MULTIPOINT
@@ -58,6 +59,7 @@ const (
 var keywordMap = map[string]byte{
 	"zm":                 ZM,
 	"m":                  M,
+	"z":                  Z,
 	"empty":              Empty,
 	"point":              Point,
 	"multipoint":         Multipoint,
@@ -141,82 +141,135 @@ func (t *T) parsePointValue() (pt []float64, err error) {
 	return pt, err
 }
 
-func (t *T) ParsePoint() (*geom.Point, error) {
-	// POINT ( xxx yyy )
+// parseDimension parses the optional “Z”, “M” or “ZM” following a
+// geometry keyword. zm is 0 if none was given.
+func (t *T) parseDimension() (zm byte, err error) {
+	for {
+		t.EatSpace()
+		switch t.Peek() {
+		case symbol.ZM, symbol.M, symbol.Z:
+			if zm != 0 {
+				return 0, fmt.Errorf("”Z”, ”ZM” or ”M” can only appear once")
+			}
+			zm = t.Peek()
+			t.Scan()
+		default:
+			return zm, nil
+		}
+	}
+}
+
+// checkDimension checks that the point has the number of coordinates
+// required by zm.
+func checkDimension(zm byte, pt []float64) error {
+	if len(pt) < 2 {
+		return fmt.Errorf("expected to have at least 2 coordinates in a POINT")
+	}
+	if len(pt) > 4 {
+		return fmt.Errorf("expected to have no more then 4 coordinates in a POINT")
+	}
+	switch zm {
+	case symbol.M:
+		if len(pt) != 3 {
+			return fmt.Errorf("M POINT should have 3 coordinates")
+		}
+	case symbol.Z:
+		if len(pt) != 3 {
+			return fmt.Errorf("Z POINT should have 3 coordinates")
+		}
+	case symbol.ZM:
+		if len(pt) != 4 {
+			return fmt.Errorf("ZM POINT should have 4 coordinates")
+		}
+	default:
+		if len(pt) != 2 {
+			return fmt.Errorf("POINT should only have 2 coordinates")
+		}
+	}
+	return nil
+}
+
+func (t *T) parsePoint() (pt []float64, zm byte, err error) {
+	// POINT [ Z | M | ZM ] ( xxx yyy [ zzz ] [ mmm ] )
 	t.EatSpace()
 	// First expect to see POINT
 	if t.Peek() != symbol.Point {
-		return nil, fmt.Errorf("expected to find “POINT”.")
+		return nil, 0, fmt.Errorf("expected to find “POINT”.")
 	}
 	t.Scan()
 
-	var zm byte
-LOOP:
-	t.EatSpace()
+	zm, err = t.parseDimension()
+	if err != nil {
+		return nil, 0, err
+	}
 	switch t.Peek() {
 	case symbol.LeftPren:
 		t.Scan()
 	case symbol.Empty:
 		t.Scan()
 		// It's a empty point.
-		return nil, nil
-	case symbol.ZM, symbol.M:
-		if zm != 0 {
-			return nil, fmt.Errorf("”ZM” or ”M” can only appear once")
-		}
-		zm = t.Peek()
-		t.Scan()
-		goto LOOP
-
+		return nil, zm, nil
 	default:
-		return nil, fmt.Errorf("expected to find “(” or “EMPTY”")
+		return nil, 0, fmt.Errorf("expected to find “(” or “EMPTY”")
 	}
-	pt, err := t.parsePointValue()
+	pt, err = t.parsePointValue()
 	// First We need to see if there is a '('
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	t.EatSpace()
 	if t.Peek() != symbol.RightPren {
-		return nil, fmt.Errorf("expected to find “)”")
+		return nil, 0, fmt.Errorf("expected to find “)”")
 	}
 	t.Scan()
-	if len(pt) < 2 {
-		return nil, fmt.Errorf("expected to have at least 2 coordinates in a POINT")
+	if err = checkDimension(zm, pt); err != nil {
+		return nil, 0, err
 	}
-	if len(pt) > 4 {
-		return nil, fmt.Errorf("expected to have no more then 4 coordinates in a POINT")
+	return pt, zm, nil
+}
+
+// ParsePoint parses a POINT, dropping any z or m values.
+func (t *T) ParsePoint() (*geom.Point, error) {
+	pt, _, err := t.parsePoint()
+	if err != nil || pt == nil {
+		return nil, err
+	}
+	return &geom.Point{pt[0], pt[1]}, nil
+}
+
+// ParsePointZM parses a POINT keeping the z and m values. The returned
+// geometry is a geom.Point, geom.PointZ, geom.PointM or geom.PointZM.
+func (t *T) ParsePointZM() (geom.Geometry, error) {
+	pt, zm, err := t.parsePoint()
+	if err != nil || pt == nil {
+		return nil, err
 	}
 	switch zm {
+	case symbol.Z:
+		return geom.PointZ{pt[0], pt[1], pt[2]}, nil
 	case symbol.M:
-		if len(pt) != 3 {
-			return nil, fmt.Errorf("M POINT should have 3 coordinates")
-		}
+		return geom.PointM{pt[0], pt[1], pt[2]}, nil
 	case symbol.ZM:
-		if len(pt) != 4 {
-			return nil, fmt.Errorf("ZM POINT should have 4 coordinates")
-		}
+		return geom.PointZM{pt[0], pt[1], pt[2], pt[3]}, nil
 	default:
-		if len(pt) != 2 {
-			return nil, fmt.Errorf("POINT should only have 2 coordinates")
-		}
+		return geom.Point{pt[0], pt[1]}, nil
 	}
-
-	//TODO: Later will need to support the POINTM and POINTZ and POINTMZ variants.
-
-	return &geom.Point{pt[0], pt[1]}, nil
 }
 
-func (t *T) ParseMultiPoint() (pts geom.MultiPoint, err error) {
+func (t *T) parseMultiPoint() (pts [][]float64, zm byte, err error) {
 	// MULTIPOINT (XXX YYY, XXX YYY )
 	// MULTIPOINT ((XXX YYY), (XXX YYY))
+	// MULTIPOINT Z ((XXX YYY ZZZ), (XXX YYY ZZZ))
 	t.EatSpace()
 	// First expect to see POINT
 	if t.Peek() != symbol.Multipoint {
-		return nil, fmt.Errorf("expected to find “MULTIPOINT”.")
+		return nil, 0, fmt.Errorf("expected to find “MULTIPOINT”.")
 	}
 	t.Scan()
-	t.EatSpace()
+	zm, err = t.parseDimension()
+	if err != nil {
+		return nil, 0, err
+	}
 	switch t.Peek() {
 	case symbol.LeftPren:
 		t.Scan()
@@ -229,10 +282,10 @@ func (t *T) ParseMultiPoint() (pts geom.MultiPoint, err error) {
 			log.Println("found Empty")
 		}
 		// It's a empty point.
-		return nil, nil
+		return nil, zm, nil
 
 	default:
-		return nil, fmt.Errorf("expected to find “(” or “EMPTY”")
+		return nil, 0, fmt.Errorf("expected to find “(” or “EMPTY”")
 	}
 	for {
 		t.EatSpace()
@@ -250,14 +303,16 @@ func (t *T) ParseMultiPoint() (pts geom.MultiPoint, err error) {
 
 		pt, err := t.parsePointValue()
 		if err != nil {
-			return nil, err
+			return nil, 0, err
+		}
+		if err = checkDimension(zm, pt); err != nil {
+			return nil, 0, err
 		}
-		//TODO: Only supporting standard points and M and ZM.
-		pts = append(pts, [2]float64{pt[0], pt[1]})
+		pts = append(pts, pt)
 		t.EatSpace()
 		if needRightPren {
 			if t.Peek() != symbol.RightPren {
-				return nil, fmt.Errorf("expected to find “)”")
+				return nil, 0, fmt.Errorf("expected to find “)”")
 			}
 			t.Scan()
 			if debug {
@@ -272,10 +327,10 @@ func (t *T) ParseMultiPoint() (pts geom.MultiPoint, err error) {
 			if debug {
 				log.Println("found right pren. ending.")
 			}
-			// return the single point.
-			return pts, nil
+			// return the points.
+			return pts, zm, nil
 		default:
-			return nil, fmt.Errorf("expected to find “,” or “)”")
+			return nil, 0, fmt.Errorf("expected to find “,” or “)”")
 		case symbol.Comma:
 			t.Scan()
 			if debug {
@@ -284,11 +339,52 @@ func (t *T) ParseMultiPoint() (pts geom.MultiPoint, err error) {
 			// Let's loop and get more points.
 		}
 	}
-	if debug {
-		log.Println("Returning empty point.")
+}
+
+// ParseMultiPoint parses a MULTIPOINT, dropping any z or m values.
+func (t *T) ParseMultiPoint() (pts geom.MultiPoint, err error) {
+	mpts, _, err := t.parseMultiPoint()
+	if err != nil || mpts == nil {
+		return nil, err
+	}
+	pts = make(geom.MultiPoint, len(mpts))
+	for i := range mpts {
+		pts[i] = [2]float64{mpts[i][0], mpts[i][1]}
 	}
+	return pts, nil
+}
 
-	return nil, nil
+// ParseMultiPointZM parses a MULTIPOINT keeping the z and m values. The
+// returned geometry is a geom.MultiPoint, geom.MultiPointZ,
+// geom.MultiPointM or geom.MultiPointZM.
+func (t *T) ParseMultiPointZM() (geom.Geometry, error) {
+	mpts, zm, err := t.parseMultiPoint()
+	if err != nil || mpts == nil {
+		return nil, err
+	}
+	switch zm {
+	case symbol.Z, symbol.M:
+		pts := make([][3]float64, len(mpts))
+		for i := range mpts {
+			copy(pts[i][:], mpts[i])
+		}
+		if zm == symbol.Z {
+			return geom.MultiPointZ(pts), nil
+		}
+		return geom.MultiPointM(pts), nil
+	case symbol.ZM:
+		pts := make(geom.MultiPointZM, len(mpts))
+		for i := range mpts {
+			copy(pts[i][:], mpts[i])
+		}
+		return pts, nil
+	default:
+		pts := make(geom.MultiPoint, len(mpts))
+		for i := range mpts {
+			copy(pts[i][:], mpts[i])
+		}
+		return pts, nil
+	}
 }
 
 /*
@@ -174,6 +174,115 @@ func TestParseMultiPointe(t *testing.T) {
 	}
 }
 
+func TestParsePointZM(t *testing.T) {
+	type tcase struct {
+		input string
+		exp   geom.Geometry
+		err   error
+	}
+	fn := func(tc tcase) (string, func(t *testing.T)) {
+		return tc.input, func(t *testing.T) {
+			tt := NewT(strings.NewReader(tc.input))
+			pt, err := tt.ParsePointZM()
+			if msg, expstr, gotstr, ok := assertError(tc.err, err); !ok {
+				if msg != "" {
+					t.Errorf("%v, expected %v got %v", msg, expstr, gotstr)
+				}
+				return
+			}
+			if !reflect.DeepEqual(tc.exp, pt) {
+				t.Errorf("point values, expected %v got %v", tc.exp, pt)
+			}
+		}
+	}
+	tests := [...]tcase{
+		{
+			input: "POINT Z EMPTY",
+		},
+		{
+			input: "POINT ( 1 2 )",
+			exp:   geom.Point{1, 2},
+		},
+		{
+			input: "POINT Z ( 1 2 3 )",
+			exp:   geom.PointZ{1, 2, 3},
+		},
+		{
+			input: "POINT M ( 1 2 3 )",
+			exp:   geom.PointM{1, 2, 3},
+		},
+		{
+			input: " POINT ZM ( 1 2 3 4 ) ",
+			exp:   geom.PointZM{1, 2, 3, 4},
+		},
+		{
+			input: "POINT Z ( 1 2 )",
+			err:   fmt.Errorf("Z POINT should have 3 coordinates"),
+		},
+		{
+			input: "POINT Z M ( 1 2 3 )",
+			err:   fmt.Errorf("”Z”, ”ZM” or ”M” can only appear once"),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(fn(tc))
+	}
+}
+
+func TestParseMultiPointZM(t *testing.T) {
+	type tcase struct {
+		input string
+		exp   geom.Geometry
+		err   error
+	}
+
+	fn := func(tc tcase) (string, func(t *testing.T)) {
+		return tc.input, func(t *testing.T) {
+			t.Parallel()
+			tt := NewT(strings.NewReader(tc.input))
+			mpt, err := tt.ParseMultiPointZM()
+			if msg, expstr, gotstr, ok := assertError(tc.err, err); !ok {
+				if msg != "" {
+					t.Errorf("%v, expected %v got %v", msg, expstr, gotstr)
+				}
+				return
+			}
+			if !reflect.DeepEqual(tc.exp, mpt) {
+				t.Errorf("did not get correct multipoint values, expected %v got %v", tc.exp, mpt)
+			}
+		}
+
+	}
+	tests := [...]tcase{
+		{
+			input: "MULTIPOINT ZM EMPTY",
+		},
+		{
+			input: "MULTIPOINT ( 10 10, 12 12 )",
+			exp:   geom.MultiPoint{{10, 10}, {12, 12}},
+		},
+		{
+			input: "MULTIPOINT Z ( (10 10 1), (12 12 2) )",
+			exp:   geom.MultiPointZ{{10, 10, 1}, {12, 12, 2}},
+		},
+		{
+			input: "MULTIPOINT M ( 10 10 1, 12 12 2 )",
+			exp:   geom.MultiPointM{{10, 10, 1}, {12, 12, 2}},
+		},
+		{
+			input: "MULTIPOINT ZM ( (10 10 1 5), (12 12 2 6) )",
+			exp:   geom.MultiPointZM{{10, 10, 1, 5}, {12, 12, 2, 6}},
+		},
+		{
+			input: "MULTIPOINT ZM ( (10 10 1), (12 12 2 6) )",
+			err:   fmt.Errorf("ZM POINT should have 4 coordinates"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(fn(test))
+	}
+}
+
 func TestParseFloat64(t *testing.T) {
 	type tcase struct {
 		input string
@@ -8,18 +8,18 @@ import (
 	"github.com/go-spatial/geom"
 )
 
-func Encode(w io.Writer, geo geom.Geometry) error {
-	return NewDefaultEncoder(w).Encode(geo)
+func Encode(w io.Writer, geo geom.Geometry, opts ...EncodeOption) error {
+	return NewDefaultEncoder(w, opts...).Encode(geo)
 }
 
-func EncodeBytes(geo geom.Geometry) ([]byte, error) {
+func EncodeBytes(geo geom.Geometry, opts ...EncodeOption) ([]byte, error) {
 	buf := &bytes.Buffer{}
-	err := Encode(buf, geo)
+	err := Encode(buf, geo, opts...)
 	return buf.Bytes(), err
 }
 
-func EncodeString(geo geom.Geometry) (string, error) {
-	byt, err := EncodeBytes(geo)
+func EncodeString(geo geom.Geometry, opts ...EncodeOption) (string, error) {
+	byt, err := EncodeBytes(geo, opts...)
 	return string(byt), err
 }
 
@@ -4,16 +4,43 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"math"
 	"strconv"
+	"strings"
 	"unicode"
 
 	"github.com/go-spatial/geom"
-	"github.com/go-spatial/geom/cmp"
 )
 
+// DecodeMode is how tolerant a Decoder is of invalid geometries.
+type DecodeMode uint8
+
+const (
+	// DecodeDefault decodes keywords of any case and rejects coordinates
+	// that are not finite and rings that are not closed.
+	DecodeDefault DecodeMode = iota
+	// DecodeStrict is DecodeDefault, but rejects keywords of mixed case,
+	// such as Point, and reports NaN and Inf coordinates as not finite
+	// rather than as unparsable.
+	DecodeStrict
+	// DecodeLenient is DecodeDefault, but closes the rings that are not
+	// closed and drops the points with coordinates that are NaN or Inf,
+	// making POINTs with them empty.
+	DecodeLenient
+)
+
+// DecodeOption changes how geometries are decoded.
+type DecodeOption func(*Decoder)
+
+// WithDecodeMode decodes in the mode.
+func WithDecodeMode(mode DecodeMode) DecodeOption {
+	return func(d *Decoder) { d.mode = mode }
+}
+
 type Decoder struct {
 	src                        *bufio.Reader
 	row, col, lastRow, lastCol int
+	mode                       DecodeMode
 }
 
 func (d *Decoder) peekByte() (byte, error) {
@@ -65,6 +92,39 @@ func (d *Decoder) readWhitespace() (bool, error) {
 	return read, nil
 }
 
+// peekEmpty reports if the next word is EMPTY, without reading it
+func (d *Decoder) peekEmpty() bool {
+	arr, _ := d.src.Peek(len("empty") + 1)
+	if len(arr) < len("empty") || !strings.EqualFold(string(arr[:len("empty")]), "empty") {
+		return false
+	}
+	if len(arr) == len("empty") {
+		// eof
+		return true
+	}
+	return !unicode.IsLetter(rune(arr[len("empty")]))
+}
+
+// readEmpty reads the EMPTY keyword, it returns true iff the
+// keyword was read
+func (d *Decoder) readEmpty() (bool, error) {
+	if !d.peekEmpty() {
+		return false, nil
+	}
+	word := make([]byte, 0, len("empty"))
+	for range "empty" {
+		b, err := d.readByte()
+		if err != nil {
+			return false, err
+		}
+		word = append(word, b)
+	}
+	if d.mode == DecodeStrict && string(word) != "EMPTY" && string(word) != "empty" {
+		return false, d.syntaxErr("GEOMETRY", "mixed case keyword %q", word)
+	}
+	return true, nil
+}
+
 func (d *Decoder) expected(chars string) error {
 	d.unreadByte()
 	b, err := d.readByte()
@@ -94,10 +154,15 @@ func (d *Decoder) readFloat() (float64, error) {
 	isNumeric := func(b byte) bool {
 		return (b >= '0' && b <= '9') ||
 			b == '-' ||
+			b == '+' ||
 			b == '.' ||
 			// b == ',' || // technically part of the spec,
 			// but even postgis does not support it
-			b == 'E'
+			b == 'E' ||
+			b == 'e' ||
+			// NaN, Inf and Infinity, which only the strict and
+			// lenient modes read
+			(d.mode != DecodeDefault && isAlpha(b))
 	}
 
 	token := []byte{}
@@ -116,35 +181,102 @@ func (d *Decoder) readFloat() (float64, error) {
 	d.unreadByte()
 
 	ret, err := strconv.ParseFloat(string(token), 64)
+	if nerr, ok := err.(*strconv.NumError); ok && nerr.Err == strconv.ErrRange && d.mode != DecodeDefault {
+		// out of range values are ±Inf
+		err = nil
+	}
 	if err != nil {
 		return 0, d.syntaxErr("float", "cannot parse %q", token)
 	}
+	if d.mode == DecodeStrict && (math.IsNaN(ret) || math.IsInf(ret, 0)) {
+		return 0, d.syntaxErr("float", "not finite %q", token)
+	}
 	return ret, nil
 }
 
-// readPoint reads a space separated tuple of floats, the inside
-// of a wkt POINT
-func (d *Decoder) readPoint() (pt [2]float64, err error) {
-	pt[0], err = d.readFloat()
-	if err != nil {
-		return pt, err
+// isFloatStart returns whether b can start a float, in the mode
+func (d *Decoder) isFloatStart(b byte) bool {
+	return (b >= '0' && b <= '9') || b == '-' || b == '+' || b == '.' ||
+		(d.mode != DecodeDefault && (b == 'n' || b == 'N' || b == 'i' || b == 'I'))
+}
+
+func isAlpha(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+// isFinite returns whether all the ordinates of the point are finite
+func isFinite(pt []float64) bool {
+	for _, v := range pt {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
 	}
+	return true
+}
 
-	// we need white space here
-	didRead, err := d.readWhitespace()
-	if err != nil {
-		return pt, err
+// dropNotFinite removes the points with ordinates that are not finite, which
+// are only read in the lenient mode.
+func (d *Decoder) dropNotFinite(pts [][]float64) [][]float64 {
+	if d.mode != DecodeLenient {
+		return pts
+	}
+	kept := pts[:0]
+	for _, pt := range pts {
+		if isFinite(pt) {
+			kept = append(kept, pt)
+		}
 	}
-	if !didRead {
-		return pt, d.expected("WHITESPACE")
+	return kept
+}
+
+// readPoint reads a space separated tuple of two to four floats, the
+// inside of a wkt POINT
+func (d *Decoder) readPoint() (pt []float64, err error) {
+	x, err := d.readFloat()
+	if err != nil {
+		return nil, err
 	}
+	pt = append(pt, x)
 
-	pt[1], err = d.readFloat()
+	for {
+		// we need white space between the values
+		didRead, err := d.readWhitespace()
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := d.readByte()
+		if err != nil {
+			return nil, err
+		}
+		d.unreadByte()
 
-	return pt, err
+		if !d.isFloatStart(b) {
+			if len(pt) < 2 {
+				// a point needs at least an x and y
+				return nil, d.expected("WHITESPACE")
+			}
+			return pt, nil
+		}
+		if !didRead {
+			return nil, d.expected("WHITESPACE")
+		}
+		if len(pt) == 4 {
+			return nil, d.syntaxErr("POINT", "too many ordinates")
+		}
+
+		f, err := d.readFloat()
+		if err != nil {
+			return nil, err
+		}
+		pt = append(pt, f)
+	}
 }
 
-func (d *Decoder) readPoints() (pts [][2]float64, err error) {
+// readPoints reads a parenthesized, comma separated list of points. If
+// wrapped is true each point may also be in its own set of parentheses,
+// as is allowed for MULTIPOINT.
+func (d *Decoder) readPoints(wrapped bool) (pts [][]float64, err error) {
 	b, err := d.readByte()
 	if err != nil {
 		return nil, err
@@ -167,7 +299,7 @@ func (d *Decoder) readPoints() (pts [][2]float64, err error) {
 	d.unreadByte()
 
 	for {
-		pt, err := d.readPoint()
+		pt, err := d.readWrappedPoint(wrapped)
 		if err != nil {
 			return nil, err
 		}
@@ -198,13 +330,52 @@ func (d *Decoder) readPoints() (pts [][2]float64, err error) {
 	}
 }
 
-func (d *Decoder) readTag() (string, error) {
+func (d *Decoder) readWrappedPoint(wrapped bool) ([]float64, error) {
+	if !wrapped {
+		return d.readPoint()
+	}
+
+	// an empty point is returned as no ordinates, to be filled in once
+	// the dimension is known
+	empty, err := d.readEmpty()
+	if err != nil || empty {
+		return nil, err
+	}
 
-	isAlpha := func(b byte) bool {
-		return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+	b, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+	if b != '(' {
+		d.unreadByte()
+		return d.readPoint()
 	}
 
+	_, err = d.readWhitespace()
+	if err != nil {
+		return nil, err
+	}
+	pt, err := d.readPoint()
+	if err != nil {
+		return nil, err
+	}
+	_, err = d.readWhitespace()
+	if err != nil {
+		return nil, err
+	}
+	b, err = d.readByte()
+	if err != nil {
+		return nil, err
+	}
+	if b != ')' {
+		return nil, d.expected(")")
+	}
+	return pt, nil
+}
+
+func (d *Decoder) readTag() (string, error) {
 	token := []byte{}
+	upper, lower := false, false
 
 	var err error
 	var b byte
@@ -217,7 +388,10 @@ func (d *Decoder) readTag() (string, error) {
 	for b, err = d.readByte(); isAlpha(b) && err == nil; b, err = d.readByte() {
 		// to lower
 		if b < 'a' {
+			upper = true
 			b += 'a' - 'A'
+		} else {
+			lower = true
 		}
 		token = append(token, b)
 	}
@@ -228,10 +402,14 @@ func (d *Decoder) readTag() (string, error) {
 
 	d.unreadByte()
 
+	if d.mode == DecodeStrict && upper && lower {
+		return "", d.syntaxErr("GEOMETRY", "mixed case keyword %q", token)
+	}
+
 	return string(token), nil
 }
 
-func (d *Decoder) readLines() ([][][2]float64, error) {
+func (d *Decoder) readLines() ([][][]float64, error) {
 	b, err := d.readByte()
 	if err != nil {
 		return nil, err
@@ -255,13 +433,19 @@ func (d *Decoder) readLines() ([][][2]float64, error) {
 	}
 	d.unreadByte()
 
-	lines := [][][2]float64{}
+	lines := [][][]float64{}
 
 	for {
-		pts, err := d.readPoints()
+		pts := [][]float64{}
+		empty, err := d.readEmpty()
 		if err != nil {
 			return nil, err
 		}
+		if !empty {
+			if pts, err = d.readPoints(false); err != nil {
+				return nil, err
+			}
+		}
 
 		lines = append(lines, pts)
 
@@ -291,7 +475,7 @@ func (d *Decoder) readLines() ([][][2]float64, error) {
 	}
 }
 
-func (d *Decoder) readPolys() ([][][][2]float64, error) {
+func (d *Decoder) readPolys() ([][][][]float64, error) {
 	b, err := d.readByte()
 	if err != nil {
 		return nil, err
@@ -315,12 +499,18 @@ func (d *Decoder) readPolys() ([][][][2]float64, error) {
 	}
 	d.unreadByte()
 
-	polys := [][][][2]float64{}
+	polys := [][][][]float64{}
 	for {
-		lines, err := d.readLines()
+		lines := [][][]float64{}
+		empty, err := d.readEmpty()
 		if err != nil {
 			return nil, err
 		}
+		if !empty {
+			if lines, err = d.readLines(); err != nil {
+				return nil, err
+			}
+		}
 
 		polys = append(polys, lines)
 
@@ -350,19 +540,49 @@ func (d *Decoder) readPolys() ([][][][2]float64, error) {
 }
 
 func (d *Decoder) readGeometry() (geom.Geometry, error) {
+	return d.readGeometryDim(dimUnknown)
+}
+
+// readGeometryDim reads a geometry, dim is the dimension of the
+// enclosing geometry collection, or dimUnknown.
+func (d *Decoder) readGeometryDim(dim dimension) (geom.Geometry, error) {
 	tag, err := d.readTag()
 	if err != nil {
 		return nil, err
 	}
 
+	tag, tagDim := splitDimension(tag)
+
 	_, err = d.readWhitespace()
 	if err != nil {
 		return nil, err
 	}
 
+	if tagDim == dimUnknown {
+		tagDim, err = d.readDimension()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if tagDim != dimUnknown {
+		if dim != dimUnknown && dim != tagDim {
+			return nil, d.syntaxErr("GEOMETRYCOLLECTION", "mixed dimensions %v and %v", dim, tagDim)
+		}
+		dim = tagDim
+	}
+
+	empty, err := d.readEmpty()
+	if err != nil {
+		return nil, err
+	}
+	if empty {
+		return d.emptyDim(tag, dim)
+	}
+
 	switch tag {
 	case "point":
-		pts, err := d.readPoints()
+		pts, err := d.readPoints(false)
 		if err != nil {
 			return nil, err
 		}
@@ -371,30 +591,51 @@ func (d *Decoder) readGeometry() (geom.Geometry, error) {
 		case 0:
 			return nil, d.syntaxErr("POINT", "cannot be empty")
 		case 1:
-			return geom.Point(pts[0]), nil
+			if dim, err = d.coordsDimension("POINT", dim, pts); err != nil {
+				return nil, err
+			}
+			if len(d.dropNotFinite(pts)) == 0 {
+				return pointDim(emptyCoord(dim), dim), nil
+			}
+			return pointDim(pts[0], dim), nil
 		default:
 			return nil, d.syntaxErr("POINT", "too many points %d", len(pts))
 		}
 
 	case "multipoint":
-		pts, err := d.readPoints()
+		pts, err := d.readPoints(true)
 		if err != nil {
 			return nil, err
 		}
+		pts = d.dropNotFinite(pts)
+
+		if dim, err = d.coordsDimension("MULTIPOINT", dim, pts); err != nil {
+			return nil, err
+		}
+		for i := range pts {
+			if len(pts[i]) == 0 {
+				pts[i] = emptyCoord(dim)
+			}
+		}
 
-		return geom.MultiPoint(pts), nil
+		return multiPointDim(pts, dim), nil
 
 	case "linestring":
-		pts, err := d.readPoints()
+		pts, err := d.readPoints(false)
 		if err != nil {
 			return nil, err
 		}
+		pts = d.dropNotFinite(pts)
 
 		if len(pts) < 2 {
 			return nil, d.syntaxErr("LINESTRING", "not enough points %d", len(pts))
 		}
 
-		return geom.LineString(pts), nil
+		if dim, err = d.coordsDimension("LINESTRING", dim, pts); err != nil {
+			return nil, err
+		}
+
+		return lineStringDim(pts, dim), nil
 
 	case "multilinestring":
 		lines, err := d.readLines()
@@ -407,12 +648,21 @@ func (d *Decoder) readGeometry() (geom.Geometry, error) {
 		}
 
 		for i, v := range lines {
+			v = d.dropNotFinite(v)
+			lines[i] = v
+			if len(v) == 0 {
+				// EMPTY
+				continue
+			}
 			if len(v) < 2 {
 				return nil, d.syntaxErr("MULTILINESTRING", "not enough points in LINESTRING[%d], %d", i, len(v))
 			}
+			if dim, err = d.coordsDimension("MULTILINESTRING", dim, v); err != nil {
+				return nil, err
+			}
 		}
 
-		return geom.MultiLineString(lines), nil
+		return multiLineStringDim(lines, dim), nil
 
 	case "polygon":
 		lines, err := d.readLines()
@@ -425,12 +675,17 @@ func (d *Decoder) readGeometry() (geom.Geometry, error) {
 		}
 
 		for i, v := range lines {
+			v = d.ring(v)
 			if len(v) < 4 {
 				return nil, d.syntaxErr("POLYGON", "not enough points in linear-ring[%d], %d", i, len(v))
 			}
 
+			if dim, err = d.coordsDimension("POLYGON", dim, v); err != nil {
+				return nil, err
+			}
+
 			// part of the spec
-			if !cmp.PointEqual(v[0], v[len(v)-1]) {
+			if !coordEqual(v[0], v[len(v)-1]) {
 				return nil, d.syntaxErr("POLYGON", "linear-ring[%d] not closed", i)
 			}
 
@@ -438,7 +693,7 @@ func (d *Decoder) readGeometry() (geom.Geometry, error) {
 			lines[i] = v[:len(v)-1]
 		}
 
-		return geom.Polygon(lines), nil
+		return polygonDim(lines, dim), nil
 
 	case "multipolygon":
 		polys, err := d.readPolys()
@@ -452,12 +707,17 @@ func (d *Decoder) readGeometry() (geom.Geometry, error) {
 
 		for ii, vv := range polys {
 			for i, v := range vv {
+				v = d.ring(v)
 				if len(v) < 4 {
 					return nil, d.syntaxErr("MULTIPOLYGON", "not enough points in polygon[%d] linear-ring[%d], %d", ii, i, len(v))
 				}
 
+				if dim, err = d.coordsDimension("MULTIPOLYGON", dim, v); err != nil {
+					return nil, err
+				}
+
 				// part of the spec
-				if !cmp.PointEqual(v[0], v[len(v)-1]) {
+				if !coordEqual(v[0], v[len(v)-1]) {
 					return nil, d.syntaxErr("MULTIPOLYGON", "polygon[%d] linear-ring[%v] not closed", i, ii)
 				}
 
@@ -466,7 +726,7 @@ func (d *Decoder) readGeometry() (geom.Geometry, error) {
 			}
 		}
 
-		return geom.MultiPolygon(polys), err
+		return multiPolygonDim(polys, dim), err
 	case "geometrycollection":
 		b, err := d.readByte()
 		if err != nil {
@@ -485,7 +745,7 @@ func (d *Decoder) readGeometry() (geom.Geometry, error) {
 		for b, err = d.readByte(); b != ')' && err == nil; b, err = d.readByte() {
 			d.unreadByte()
 
-			geo, err := d.readGeometry()
+			geo, err := d.readGeometryDim(dim)
 			if err != nil {
 				return nil, err
 			}
@@ -535,12 +795,33 @@ func (d *Decoder) readGeometry() (geom.Geometry, error) {
 	}
 }
 
+// ring drops the points of a linear-ring that are not finite and closes
+// it if it is not closed, in the lenient mode.
+func (d *Decoder) ring(v [][]float64) [][]float64 {
+	if d.mode != DecodeLenient {
+		return v
+	}
+	v = d.dropNotFinite(v)
+	if len(v) < 3 {
+		return v
+	}
+	first, last := v[0], v[len(v)-1]
+	if len(first) == len(last) && coordEqual(first, last) {
+		return v
+	}
+	return append(v, first)
+}
+
 func (d *Decoder) Decode() (geom.Geometry, error) {
 	return d.readGeometry()
 }
 
-func NewDecoder(r io.Reader) *Decoder {
-	return &Decoder{
+func NewDecoder(r io.Reader, opts ...DecodeOption) *Decoder {
+	d := &Decoder{
 		src: bufio.NewReader(r),
 	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
@@ -1,6 +1,9 @@
 package wkt
 
 import (
+	"fmt"
+	"math"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -110,6 +113,14 @@ func TestDecode(t *testing.T) {
 			in:  "POINT(1.3E100 2.3E-35)",
 			out: geom.Point{1.3e100, 2.3e-35},
 		},
+		"point 9": {
+			in:  "POINT(1.3e+2 -2.5e-1)",
+			out: geom.Point{130, -0.25},
+		},
+		"point 10": {
+			in:  "POINT(+1 +.5)",
+			out: geom.Point{1, 0.5},
+		},
 		"multipoint 0": {
 			in:  "MULTIPOINT()",
 			out: geom.MultiPoint{},
@@ -243,3 +254,360 @@ func TestDecode(t *testing.T) {
 		t.Run(k, fn(v))
 	}
 }
+
+func TestDecodeZM(t *testing.T) {
+	type tcase struct {
+		in  string
+		out geom.Geometry
+		err error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			out, err := DecodeString(tc.in)
+			if (err == nil) != (tc.err == nil) {
+				t.Errorf("error, expected %v, got %v", tc.err, err)
+				return
+			}
+			if err != nil {
+				eerr, ok := err.(ErrSyntax)
+				if !ok {
+					t.Errorf("error, expected %v, got %v", tc.err, err)
+					return
+				}
+				tcerr := tc.err.(ErrSyntax)
+				if eerr.Issue != tcerr.Issue || eerr.Type != tcerr.Type {
+					t.Errorf("error, expected %v:%v got %v:%v", tcerr.Type, tcerr.Issue, eerr.Type, eerr.Issue)
+				}
+				return
+			}
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf("geometry, expected %v, got %v", tc.out, out)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"point z": {
+			in:  "POINT Z (1 2 3)",
+			out: geom.PointZ{1, 2, 3},
+		},
+		"point z no space": {
+			in:  "POINTZ(1 2 3)",
+			out: geom.PointZ{1, 2, 3},
+		},
+		"point z inferred": {
+			in:  "POINT(1 2 3)",
+			out: geom.PointZ{1, 2, 3},
+		},
+		"point m": {
+			in:  "point m (1 2 3)",
+			out: geom.PointM{1, 2, 3},
+		},
+		"point zm": {
+			in:  "POINT ZM (1 2 3 4)",
+			out: geom.PointZM{1, 2, 3, 4},
+		},
+		"point zm inferred": {
+			in:  "POINT(1 2 3 4)",
+			out: geom.PointZM{1, 2, 3, 4},
+		},
+		"point z too few": {
+			in: "POINT Z (1 2)",
+			err: ErrSyntax{
+				Type:  "POINT",
+				Issue: "expected 3 ordinates got 2",
+			},
+		},
+		"point too many ordinates": {
+			in: "POINT(1 2 3 4 5)",
+			err: ErrSyntax{
+				Type:  "POINT",
+				Issue: "too many ordinates",
+			},
+		},
+		"unknown dimension": {
+			in: "POINT Q (1 2)",
+			err: ErrSyntax{
+				Type:  "GEOMETRY",
+				Issue: `unknown dimension "q"`,
+			},
+		},
+		"multipoint m": {
+			in:  "MULTIPOINT M ((1 2 3),(4 5 6))",
+			out: geom.MultiPointM{{1, 2, 3}, {4, 5, 6}},
+		},
+		"multipoint zm": {
+			in:  "MULTIPOINT ZM (1 2 3 4, 5 6 7 8)",
+			out: geom.MultiPointZM{{1, 2, 3, 4}, {5, 6, 7, 8}},
+		},
+		"linestring z": {
+			in:  "LINESTRING Z (1 2 3, 4 5 6)",
+			out: geom.LineStringZ{{1, 2, 3}, {4, 5, 6}},
+		},
+		"linestring mixed": {
+			in: "LINESTRING (1 2 3, 4 5)",
+			err: ErrSyntax{
+				Type:  "LINESTRING",
+				Issue: "expected 3 ordinates got 2",
+			},
+		},
+		"multilinestring zm": {
+			in:  "MULTILINESTRING ZM ((1 2 3 4, 5 6 7 8),(0 0 0 0, 1 1 1 1))",
+			out: geom.MultiLineStringZM{{{1, 2, 3, 4}, {5, 6, 7, 8}}, {{0, 0, 0, 0}, {1, 1, 1, 1}}},
+		},
+		"polygon z": {
+			in:  "POLYGON Z ((0 0 1, 1 1 1, 1 0 1, 0 0 1))",
+			out: geom.PolygonZ{{{0, 0, 1}, {1, 1, 1}, {1, 0, 1}}},
+		},
+		"polygon z not closed": {
+			in: "POLYGON Z ((0 0 1, 1 1 1, 1 0 1, 0 0 2))",
+			err: ErrSyntax{
+				Type:  "POLYGON",
+				Issue: "linear-ring[0] not closed",
+			},
+		},
+		"multipolygon m": {
+			in:  "MULTIPOLYGON M (((0 0 1, 1 1 2, 1 0 3, 0 0 1)))",
+			out: geom.MultiPolygonM{{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}}},
+		},
+		"collection m": {
+			in:  "GEOMETRYCOLLECTION(POINT M (1 2 3), POINT(4 5))",
+			out: geom.Collection{geom.PointM{1, 2, 3}, geom.Point{4, 5}},
+		},
+		"collection zm": {
+			in:  "GEOMETRYCOLLECTION ZM (POINT(1 2 3 4), LINESTRING ZM (1 2 3 4, 5 6 7 8))",
+			out: geom.Collection{geom.PointZM{1, 2, 3, 4}, geom.LineStringZM{{1, 2, 3, 4}, {5, 6, 7, 8}}},
+		},
+		"collection z mixed": {
+			in: "GEOMETRYCOLLECTION Z (POINT M (1 2 3))",
+			err: ErrSyntax{
+				Type:  "GEOMETRYCOLLECTION",
+				Issue: "mixed dimensions Z and M",
+			},
+		},
+	}
+
+	for k, v := range tcases {
+		t.Run(k, fn(v))
+	}
+}
+
+func TestDecodeEmpty(t *testing.T) {
+	type tcase struct {
+		in  string
+		out geom.Geometry
+		err error
+	}
+
+	nan := math.NaN()
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			out, err := DecodeString(tc.in)
+			if (err == nil) != (tc.err == nil) {
+				t.Errorf("error, expected %v, got %v", tc.err, err)
+				return
+			}
+			if err != nil {
+				eerr, ok := err.(ErrSyntax)
+				if !ok {
+					t.Errorf("error, expected %v, got %v", tc.err, err)
+					return
+				}
+				tcerr := tc.err.(ErrSyntax)
+				if eerr.Issue != tcerr.Issue || eerr.Type != tcerr.Type {
+					t.Errorf("error, expected %v:%v got %v:%v", tcerr.Type, tcerr.Issue, eerr.Type, eerr.Issue)
+				}
+				return
+			}
+			// empty points are NaNs, which DeepEqual never finds equal
+			if fmt.Sprintf("%#v", out) != fmt.Sprintf("%#v", tc.out) {
+				t.Errorf("geometry, expected %#v, got %#v", tc.out, out)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"point": {
+			in:  "POINT EMPTY",
+			out: geom.Point{nan, nan},
+		},
+		"point lower case": {
+			in:  "point empty",
+			out: geom.Point{nan, nan},
+		},
+		"point z": {
+			in:  "POINT Z EMPTY",
+			out: geom.PointZ{nan, nan, nan},
+		},
+		"point zm no space": {
+			in:  "POINTZM EMPTY",
+			out: geom.PointZM{nan, nan, nan, nan},
+		},
+		"multipoint": {
+			in:  "MULTIPOINT EMPTY",
+			out: geom.MultiPoint{},
+		},
+		"multipoint empty point": {
+			in:  "MULTIPOINT (EMPTY, (1 2), 3 4)",
+			out: geom.MultiPoint{{nan, nan}, {1, 2}, {3, 4}},
+		},
+		"multipoint z empty point": {
+			in:  "MULTIPOINT Z ((1 2 3), EMPTY)",
+			out: geom.MultiPointZ{{1, 2, 3}, {nan, nan, nan}},
+		},
+		"linestring": {
+			in:  "LINESTRING EMPTY",
+			out: geom.LineString{},
+		},
+		"linestring m": {
+			in:  "LINESTRING M EMPTY",
+			out: geom.LineStringM{},
+		},
+		"multilinestring empty line": {
+			in:  "MULTILINESTRING ((1 2, 3 4), EMPTY)",
+			out: geom.MultiLineString{{{1, 2}, {3, 4}}, {}},
+		},
+		"polygon": {
+			in:  "POLYGON EMPTY",
+			out: geom.Polygon{},
+		},
+		"polygon empty ring": {
+			in: "POLYGON ((0 0, 1 0, 1 1, 0 0), EMPTY)",
+			err: ErrSyntax{
+				Type:  "POLYGON",
+				Issue: "not enough points in linear-ring[1], 0",
+			},
+		},
+		"multipolygon empty polygon": {
+			in:  "MULTIPOLYGON (EMPTY, ((0 0, 1 0, 1 1, 0 0)))",
+			out: geom.MultiPolygon{{}, {{{0, 0}, {1, 0}, {1, 1}}}},
+		},
+		"collection": {
+			in:  "GEOMETRYCOLLECTION EMPTY",
+			out: geom.Collection{},
+		},
+		"nested collection": {
+			in: "GEOMETRYCOLLECTION (POINT EMPTY, GEOMETRYCOLLECTION (LINESTRING EMPTY, GEOMETRYCOLLECTION EMPTY), POINT (1 2))",
+			out: geom.Collection{
+				geom.Point{nan, nan},
+				geom.Collection{geom.LineString{}, geom.Collection{}},
+				geom.Point{1, 2},
+			},
+		},
+		"collection z": {
+			in: "GEOMETRYCOLLECTION Z (POINT EMPTY, POINT (1 2 3))",
+			out: geom.Collection{
+				geom.PointZ{nan, nan, nan},
+				geom.PointZ{1, 2, 3},
+			},
+		},
+	}
+
+	for k, v := range tcases {
+		t.Run(k, fn(v))
+	}
+}
+
+func TestDecodeMode(t *testing.T) {
+	type tcase struct {
+		in   string
+		mode DecodeMode
+		out  geom.Geometry
+		err  error
+	}
+
+	nan := math.NaN()
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			out, err := NewDecoder(strings.NewReader(tc.in), WithDecodeMode(tc.mode)).Decode()
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v, got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			// empty points are NaNs, which DeepEqual never finds equal
+			if fmt.Sprintf("%#v", out) != fmt.Sprintf("%#v", tc.out) {
+				t.Errorf("geometry, expected %#v, got %#v", tc.out, out)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"default mixed case": {
+			in:  "Point (1 2)",
+			out: geom.Point{1, 2},
+		},
+		"default nan": {
+			in:  "POINT (1 nan)",
+			err: ErrSyntax{Line: 0, Char: 10, Type: "expected", Issue: "one of `\"WHITESPACE\"` got 'n'"},
+		},
+		"strict mixed case": {
+			in:   "Point (1 2)",
+			mode: DecodeStrict,
+			err:  ErrSyntax{Line: 0, Char: 5, Type: "GEOMETRY", Issue: `mixed case keyword "point"`},
+		},
+		"strict mixed case empty": {
+			in:   "POINT Empty",
+			mode: DecodeStrict,
+			err:  ErrSyntax{Line: 0, Char: 11, Type: "GEOMETRY", Issue: `mixed case keyword "Empty"`},
+		},
+		"strict lower case": {
+			in:   "point z (1 2 3)",
+			mode: DecodeStrict,
+			out:  geom.PointZ{1, 2, 3},
+		},
+		"strict nan": {
+			in:   "LINESTRING (0 0,\n1 nan)",
+			mode: DecodeStrict,
+			err:  ErrSyntax{Line: 1, Char: 5, Type: "float", Issue: `not finite "nan"`},
+		},
+		"strict out of range": {
+			in:   "POINT (1e400 0)",
+			mode: DecodeStrict,
+			err:  ErrSyntax{Line: 0, Char: 12, Type: "float", Issue: `not finite "1e400"`},
+		},
+		"strict unclosed ring": {
+			in:   "POLYGON ((0 0, 1 0, 1 1, 0 1))",
+			mode: DecodeStrict,
+			err:  ErrSyntax{Line: 0, Char: 30, Type: "POLYGON", Issue: "linear-ring[0] not closed"},
+		},
+		"lenient point": {
+			in:   "POINT (1 Infinity)",
+			mode: DecodeLenient,
+			out:  geom.Point{nan, nan},
+		},
+		"lenient multipoint": {
+			in:   "MULTIPOINT ((NaN 0), EMPTY, (1 2))",
+			mode: DecodeLenient,
+			out:  geom.MultiPoint{{nan, nan}, {1, 2}},
+		},
+		"lenient linestring": {
+			in:   "LINESTRING (0 0, -inf 1, 1 1)",
+			mode: DecodeLenient,
+			out:  geom.LineString{{0, 0}, {1, 1}},
+		},
+		"lenient unclosed ring": {
+			in:   "POLYGON ((0 0, 1 0, 1 1, 0 1))",
+			mode: DecodeLenient,
+			out:  geom.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}},
+		},
+		"lenient triangle": {
+			in:   "MULTIPOLYGON (((0 0, 1 0, nan nan, 1 1)))",
+			mode: DecodeLenient,
+			out:  geom.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}}}},
+		},
+		"lenient closed ring": {
+			in:   "POLYGON ((0 0, 1 0, 1 1, 0 0))",
+			mode: DecodeLenient,
+			out:  geom.Polygon{{{0, 0}, {1, 0}, {1, 1}}},
+		},
+	}
+
+	for k, v := range tcases {
+		t.Run(k, fn(v))
+	}
+}
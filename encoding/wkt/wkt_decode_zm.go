@@ -0,0 +1,323 @@
+package wkt
+
+import (
+	"math"
+	"strings"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+)
+
+// dimension is the coordinate dimension of a wkt geometry
+type dimension uint8
+
+const (
+	// dimUnknown is used when the dimension has not been given and
+	// should be inferred from the coordinates
+	dimUnknown dimension = iota
+	dimXY
+	dimXYZ
+	dimXYM
+	dimXYZM
+)
+
+func (dim dimension) String() string {
+	switch dim {
+	case dimXY:
+		return "XY"
+	case dimXYZ:
+		return "Z"
+	case dimXYM:
+		return "M"
+	case dimXYZM:
+		return "ZM"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ordinates returns the number of values in each coordinate
+func (dim dimension) ordinates() int {
+	switch dim {
+	case dimXY:
+		return 2
+	case dimXYZ, dimXYM:
+		return 3
+	case dimXYZM:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// dimensionKeywords maps the wkt dimension keywords to a dimension
+var dimensionKeywords = map[string]dimension{
+	"z":  dimXYZ,
+	"m":  dimXYM,
+	"zm": dimXYZM,
+}
+
+// splitDimension splits the dimension suffix from a tag written
+// without a space, eg. "pointzm"
+func splitDimension(tag string) (string, dimension) {
+	for _, suffix := range [...]string{"zm", "z", "m"} {
+		if !strings.HasSuffix(tag, suffix) {
+			continue
+		}
+		switch base := strings.TrimSuffix(tag, suffix); base {
+		case "point", "multipoint", "linestring", "multilinestring",
+			"polygon", "multipolygon", "geometrycollection":
+			return base, dimensionKeywords[suffix]
+		}
+	}
+	return tag, dimUnknown
+}
+
+// readDimension reads the optional Z, M or ZM keyword following the
+// geometry tag
+func (d *Decoder) readDimension() (dimension, error) {
+	b, err := d.readByte()
+	if err != nil {
+		return dimUnknown, err
+	}
+	d.unreadByte()
+
+	if !((b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')) || d.peekEmpty() {
+		return dimUnknown, nil
+	}
+
+	keyword, err := d.readTag()
+	if err != nil {
+		return dimUnknown, err
+	}
+
+	dim, ok := dimensionKeywords[keyword]
+	if !ok {
+		return dimUnknown, d.syntaxErr("GEOMETRY", "unknown dimension %q", keyword)
+	}
+
+	_, err = d.readWhitespace()
+	return dim, err
+}
+
+// coordsDimension checks that all the coordinates have the number of
+// ordinates dim requires. If dim is dimUnknown it is inferred from
+// the first coordinate. Empty coordinates, from EMPTY points, are
+// skipped.
+func (d *Decoder) coordsDimension(typ string, dim dimension, pts [][]float64) (dimension, error) {
+	for _, pt := range pts {
+		if len(pt) == 0 {
+			continue
+		}
+		if dim == dimUnknown {
+			switch len(pt) {
+			case 2:
+				dim = dimXY
+			case 3:
+				dim = dimXYZ
+			default:
+				dim = dimXYZM
+			}
+		}
+
+		if len(pt) != dim.ordinates() {
+			return dim, d.syntaxErr(typ, "expected %d ordinates got %d", dim.ordinates(), len(pt))
+		}
+	}
+	return dim, nil
+}
+
+// coordEqual compares the x and y with the default tolerance, and the
+// remaining ordinates exactly.
+func coordEqual(c1, c2 []float64) bool {
+	if !cmp.PointEqual([2]float64{c1[0], c1[1]}, [2]float64{c2[0], c2[1]}) {
+		return false
+	}
+	return float64sEqual(c1[2:], c2[2:])
+}
+
+func coordsXY(pts [][]float64) [][2]float64 {
+	if pts == nil {
+		return nil
+	}
+	ret := make([][2]float64, len(pts))
+	for i := range pts {
+		copy(ret[i][:], pts[i])
+	}
+	return ret
+}
+
+func coordsXYZ(pts [][]float64) [][3]float64 {
+	if pts == nil {
+		return nil
+	}
+	ret := make([][3]float64, len(pts))
+	for i := range pts {
+		copy(ret[i][:], pts[i])
+	}
+	return ret
+}
+
+func coordsXYZM(pts [][]float64) [][4]float64 {
+	if pts == nil {
+		return nil
+	}
+	ret := make([][4]float64, len(pts))
+	for i := range pts {
+		copy(ret[i][:], pts[i])
+	}
+	return ret
+}
+
+func linesXY(lines [][][]float64) [][][2]float64 {
+	ret := make([][][2]float64, len(lines))
+	for i := range lines {
+		ret[i] = coordsXY(lines[i])
+	}
+	return ret
+}
+
+func linesXYZ(lines [][][]float64) [][][3]float64 {
+	ret := make([][][3]float64, len(lines))
+	for i := range lines {
+		ret[i] = coordsXYZ(lines[i])
+	}
+	return ret
+}
+
+func linesXYZM(lines [][][]float64) [][][4]float64 {
+	ret := make([][][4]float64, len(lines))
+	for i := range lines {
+		ret[i] = coordsXYZM(lines[i])
+	}
+	return ret
+}
+
+// emptyCoord returns the coordinate of an empty point, all NaNs.
+func emptyCoord(dim dimension) []float64 {
+	if dim == dimUnknown {
+		dim = dimXY
+	}
+	pt := make([]float64, dim.ordinates())
+	for i := range pt {
+		pt[i] = math.NaN()
+	}
+	return pt
+}
+
+// emptyDim returns the empty geometry of the type, empty points
+// have NaN ordinates as the encoder expects.
+func (d *Decoder) emptyDim(tag string, dim dimension) (geom.Geometry, error) {
+	switch tag {
+	case "point":
+		return pointDim(emptyCoord(dim), dim), nil
+	case "multipoint":
+		return multiPointDim([][]float64{}, dim), nil
+	case "linestring":
+		return lineStringDim([][]float64{}, dim), nil
+	case "multilinestring":
+		return multiLineStringDim([][][]float64{}, dim), nil
+	case "polygon":
+		return polygonDim([][][]float64{}, dim), nil
+	case "multipolygon":
+		return multiPolygonDim([][][][]float64{}, dim), nil
+	case "geometrycollection":
+		return geom.Collection{}, nil
+	default:
+		return nil, d.syntaxErr("GEOMETRY", "unknown type %q", tag)
+	}
+}
+
+func pointDim(pt []float64, dim dimension) geom.Geometry {
+	switch dim {
+	case dimXYZ:
+		return geom.PointZ(coordsXYZ([][]float64{pt})[0])
+	case dimXYM:
+		return geom.PointM(coordsXYZ([][]float64{pt})[0])
+	case dimXYZM:
+		return geom.PointZM(coordsXYZM([][]float64{pt})[0])
+	default:
+		return geom.Point(coordsXY([][]float64{pt})[0])
+	}
+}
+
+func multiPointDim(pts [][]float64, dim dimension) geom.Geometry {
+	switch dim {
+	case dimXYZ:
+		return geom.MultiPointZ(coordsXYZ(pts))
+	case dimXYM:
+		return geom.MultiPointM(coordsXYZ(pts))
+	case dimXYZM:
+		return geom.MultiPointZM(coordsXYZM(pts))
+	default:
+		return geom.MultiPoint(coordsXY(pts))
+	}
+}
+
+func lineStringDim(pts [][]float64, dim dimension) geom.Geometry {
+	switch dim {
+	case dimXYZ:
+		return geom.LineStringZ(coordsXYZ(pts))
+	case dimXYM:
+		return geom.LineStringM(coordsXYZ(pts))
+	case dimXYZM:
+		return geom.LineStringZM(coordsXYZM(pts))
+	default:
+		return geom.LineString(coordsXY(pts))
+	}
+}
+
+func multiLineStringDim(lines [][][]float64, dim dimension) geom.Geometry {
+	switch dim {
+	case dimXYZ:
+		return geom.MultiLineStringZ(linesXYZ(lines))
+	case dimXYM:
+		return geom.MultiLineStringM(linesXYZ(lines))
+	case dimXYZM:
+		return geom.MultiLineStringZM(linesXYZM(lines))
+	default:
+		return geom.MultiLineString(linesXY(lines))
+	}
+}
+
+func polygonDim(lines [][][]float64, dim dimension) geom.Geometry {
+	switch dim {
+	case dimXYZ:
+		return geom.PolygonZ(linesXYZ(lines))
+	case dimXYM:
+		return geom.PolygonM(linesXYZ(lines))
+	case dimXYZM:
+		return geom.PolygonZM(linesXYZM(lines))
+	default:
+		return geom.Polygon(linesXY(lines))
+	}
+}
+
+func multiPolygonDim(polys [][][][]float64, dim dimension) geom.Geometry {
+	switch dim {
+	case dimXYZ:
+		ret := make(geom.MultiPolygonZ, len(polys))
+		for i := range polys {
+			ret[i] = linesXYZ(polys[i])
+		}
+		return ret
+	case dimXYM:
+		ret := make(geom.MultiPolygonM, len(polys))
+		for i := range polys {
+			ret[i] = linesXYZ(polys[i])
+		}
+		return ret
+	case dimXYZM:
+		ret := make(geom.MultiPolygonZM, len(polys))
+		for i := range polys {
+			ret[i] = linesXYZM(polys[i])
+		}
+		return ret
+	default:
+		ret := make(geom.MultiPolygon, len(polys))
+		for i := range polys {
+			ret[i] = linesXY(polys[i])
+		}
+		return ret
+	}
+}
@@ -19,12 +19,21 @@ type Encoder struct {
 	strict    bool
 	precision int
 	fmt       byte
+	// trim, lower and sortParts are set by the EncodeOptions
+	trim      bool
+	lower     bool
+	sortParts bool
 }
 
 // NewDefaultEncoder creates a new encoder that writes to w using the
-// defaults of strict = false, precision = 10, and fmt = 'g'.
-func NewDefaultEncoder(w io.Writer) Encoder {
-	return NewEncoder(w, false, 10, 'g')
+// defaults of strict = false, precision = 10, and fmt = 'g', changed by
+// the options.
+func NewDefaultEncoder(w io.Writer, opts ...EncodeOption) Encoder {
+	enc := NewEncoder(w, false, 10, 'g')
+	for _, opt := range opts {
+		opt(&enc)
+	}
+	return enc
 }
 
 // NewEncoder creates a new encoder that writes to w
@@ -61,13 +70,20 @@ func (enc Encoder) byte(b byte) error {
 	return err
 }
 
+// string writes a keyword
 func (enc Encoder) string(s string) error {
+	if enc.lower {
+		s = strings.ToLower(s)
+	}
 	_, err := enc.w.Write([]byte(s))
 	return err
 }
 
 func (enc Encoder) formatFloat(f float64) error {
 	buf := strconv.AppendFloat(enc.fbuf[:0], f, enc.fmt, enc.precision, 64)
+	if enc.trim {
+		buf = trimZeros(buf)
+	}
 	_, err := enc.w.Write(buf)
 	return err
 }
@@ -460,7 +476,7 @@ func (enc Encoder) encode(geo geom.Geometry) error {
 			return err
 		}
 
-		return enc.encodePolys(g, len(g)-1)
+		return enc.encodePolys(enc.orderPolys(g), len(g)-1)
 
 	case *geom.MultiPolygon:
 		err := enc.string("MULTIPOLYGON ")
@@ -472,7 +488,7 @@ func (enc Encoder) encode(geo geom.Geometry) error {
 			return enc.string("EMPTY")
 		}
 
-		return enc.encodePolys(g.Polygons(), len(*g)-1)
+		return enc.encodePolys(enc.orderPolys(g.Polygons()), len(*g)-1)
 
 	case geom.Collection:
 		if len(g) == 0 {
@@ -572,6 +588,12 @@ func (enc Encoder) encode(geo geom.Geometry) error {
 		return enc.encode(geom.Polygon{})
 
 	default:
+		if ok, err := enc.encodeZM(geo); ok {
+			return err
+		}
+		if col, ok := geo.(geom.Collectioner); ok {
+			return enc.encode(geom.Collection(col.Geometries()))
+		}
 		return fmt.Errorf("unknown geometry: %T", geo)
 	}
 }
@@ -0,0 +1,148 @@
+package wkt
+
+import (
+	"bytes"
+	"sort"
+)
+
+// EncodeOption changes how a geometry is encoded.
+type EncodeOption func(*Encoder)
+
+// WithPrecision encodes the coordinates with the number of digits after
+// the decimal point, instead of the 10 significant digits of the default
+// encoder. A negative number of digits uses as many as are needed to
+// decode the same value.
+func WithPrecision(digits int) EncodeOption {
+	return func(enc *Encoder) { enc.fmt, enc.precision = 'f', digits }
+}
+
+// WithTrimZeros removes the zeros at the end of the fractions of the
+// coordinates, and the decimal point if there is no fraction left, so
+// 1.500 is encoded as 1.5 and 2.000 as 2. Zeros that are trimmed to -0 are
+// encoded as 0.
+func WithTrimZeros() EncodeOption {
+	return func(enc *Encoder) { enc.trim = true }
+}
+
+// KeywordCase is the case of the keywords, such as POINT and EMPTY.
+type KeywordCase uint8
+
+const (
+	// Uppercase keywords, which is the default
+	Uppercase KeywordCase = iota
+	// Lowercase keywords
+	Lowercase
+)
+
+// WithKeywordCase encodes the keywords in the case.
+func WithKeywordCase(c KeywordCase) EncodeOption {
+	return func(enc *Encoder) { enc.lower = c == Lowercase }
+}
+
+// WithSortedParts encodes the polygons of multipolygons in order of their
+// coordinates, comparing the x then the y of each point of each ring in
+// turn, so multipolygons with the same polygons in a different order are
+// encoded the same.
+func WithSortedParts() EncodeOption {
+	return func(enc *Encoder) { enc.sortParts = true }
+}
+
+// trimZeros removes the zeros at the end of the fraction of the formatted
+// number, keeping any exponent
+func trimZeros(b []byte) []byte {
+	end := bytes.IndexAny(b, "eE")
+	if end == -1 {
+		end = len(b)
+	}
+	if bytes.IndexByte(b[:end], '.') == -1 {
+		return b
+	}
+	i := end
+	for b[i-1] == '0' {
+		i--
+	}
+	if b[i-1] == '.' {
+		i--
+	}
+	b = append(b[:i], b[end:]...)
+	if string(b) == "-0" {
+		b = b[1:]
+	}
+	return b
+}
+
+// orderPolys returns the polygons sorted if the parts are to be sorted
+func (enc Encoder) orderPolys(polys [][][][2]float64) [][][][2]float64 {
+	if !enc.sortParts {
+		return polys
+	}
+	polys = append([][][][2]float64(nil), polys...)
+	sort.SliceStable(polys, func(i, j int) bool {
+		return comparePolys(polys[i], polys[j]) < 0
+	})
+	return polys
+}
+
+// orderCoordPolys is orderPolys for polygons with z or m values
+func (enc Encoder) orderCoordPolys(polys [][][][]float64) [][][][]float64 {
+	if !enc.sortParts {
+		return polys
+	}
+	polys = append([][][][]float64(nil), polys...)
+	sort.SliceStable(polys, func(i, j int) bool {
+		return compareCoordPolys(polys[i], polys[j]) < 0
+	})
+	return polys
+}
+
+// comparePolys compares the polygons point by point, a polygon that is the
+// start of the other is first
+func comparePolys(a, b [][][2]float64) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		for j := 0; j < len(a[i]) && j < len(b[i]); j++ {
+			if c := compareCoords(a[i][j][:], b[i][j][:]); c != 0 {
+				return c
+			}
+		}
+		if c := compareLen(len(a[i]), len(b[i])); c != 0 {
+			return c
+		}
+	}
+	return compareLen(len(a), len(b))
+}
+
+func compareCoordPolys(a, b [][][]float64) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		for j := 0; j < len(a[i]) && j < len(b[i]); j++ {
+			if c := compareCoords(a[i][j], b[i][j]); c != 0 {
+				return c
+			}
+		}
+		if c := compareLen(len(a[i]), len(b[i])); c != 0 {
+			return c
+		}
+	}
+	return compareLen(len(a), len(b))
+}
+
+func compareCoords(a, b []float64) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return compareLen(len(a), len(b))
+}
+
+func compareLen(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
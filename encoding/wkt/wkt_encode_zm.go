@@ -0,0 +1,334 @@
+package wkt
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+)
+
+// encodeOrdinates writes the ordinates of a single coordinate separated by spaces
+func (enc Encoder) encodeOrdinates(c []float64) error {
+	for i := range c {
+		if i != 0 {
+			if err := enc.byte(' '); err != nil {
+				return err
+			}
+		}
+		if err := enc.formatFloat(c[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeCoordList writes a parenthesized list of coordinates, closing it if
+// isRing is true and the first and last coordinates differ.
+func (enc Encoder) encodeCoordList(pts [][]float64, isRing bool) error {
+	if len(pts) == 0 {
+		return enc.string("EMPTY")
+	}
+	if err := enc.byte('('); err != nil {
+		return err
+	}
+	for i := range pts {
+		if i != 0 {
+			if err := enc.byte(','); err != nil {
+				return err
+			}
+		}
+		if err := enc.encodeOrdinates(pts[i]); err != nil {
+			return err
+		}
+	}
+	if isRing && !float64sEqual(pts[0], pts[len(pts)-1]) {
+		if err := enc.byte(','); err != nil {
+			return err
+		}
+		if err := enc.encodeOrdinates(pts[0]); err != nil {
+			return err
+		}
+	}
+	return enc.byte(')')
+}
+
+func (enc Encoder) encodeCoordLists(lines [][][]float64, isRing bool) error {
+	if len(lines) == 0 {
+		return enc.string("EMPTY")
+	}
+	if err := enc.byte('('); err != nil {
+		return err
+	}
+	for i := range lines {
+		if i != 0 {
+			if err := enc.byte(','); err != nil {
+				return err
+			}
+		}
+		if err := enc.encodeCoordList(lines[i], isRing); err != nil {
+			return err
+		}
+	}
+	return enc.byte(')')
+}
+
+func (enc Encoder) encodeCoordPolys(polys [][][][]float64) error {
+	if len(polys) == 0 {
+		return enc.string("EMPTY")
+	}
+	if err := enc.byte('('); err != nil {
+		return err
+	}
+	for i := range polys {
+		if i != 0 {
+			if err := enc.byte(','); err != nil {
+				return err
+			}
+		}
+		if err := enc.encodeCoordLists(polys[i], true); err != nil {
+			return err
+		}
+	}
+	return enc.byte(')')
+}
+
+// encodePointZM writes a single parenthesized coordinate, or EMPTY if
+// the x and y are NaN.
+func (enc Encoder) encodePointZM(c []float64) error {
+	if cmp.IsEmptyPoint([2]float64{c[0], c[1]}) {
+		return enc.string("EMPTY")
+	}
+	return enc.encodeCoordList([][]float64{c}, false)
+}
+
+// encodeMultiPointZM writes each point in its own set of parentheses.
+func (enc Encoder) encodeMultiPointZM(pts [][]float64) error {
+	if len(pts) == 0 {
+		return enc.string("EMPTY")
+	}
+	lines := make([][][]float64, len(pts))
+	for i := range pts {
+		lines[i] = [][]float64{pts[i]}
+	}
+	return enc.encodeCoordLists(lines, false)
+}
+
+func float64sEqual(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func coords3(pts [][3]float64) [][]float64 {
+	c := make([][]float64, len(pts))
+	for i := range pts {
+		c[i] = pts[i][:]
+	}
+	return c
+}
+
+func coords4(pts [][4]float64) [][]float64 {
+	c := make([][]float64, len(pts))
+	for i := range pts {
+		c[i] = pts[i][:]
+	}
+	return c
+}
+
+func lines3(lines [][][3]float64) [][][]float64 {
+	c := make([][][]float64, len(lines))
+	for i := range lines {
+		c[i] = coords3(lines[i])
+	}
+	return c
+}
+
+func lines4(lines [][][4]float64) [][][]float64 {
+	c := make([][][]float64, len(lines))
+	for i := range lines {
+		c[i] = coords4(lines[i])
+	}
+	return c
+}
+
+func polys3(polys [][][][3]float64) [][][][]float64 {
+	c := make([][][][]float64, len(polys))
+	for i := range polys {
+		c[i] = lines3(polys[i])
+	}
+	return c
+}
+
+func polys4(polys [][][][4]float64) [][][][]float64 {
+	c := make([][][][]float64, len(polys))
+	for i := range polys {
+		c[i] = lines4(polys[i])
+	}
+	return c
+}
+
+// derefZM returns the value a pointer to a Z, M or ZM geometry points
+// to. A nil pointer is returned as an empty geometry.
+func derefZM(g geom.Geometry) geom.Geometry {
+	switch gg := g.(type) {
+	case *geom.PointZ:
+		if gg == nil {
+			return geom.PointZ{math.NaN(), math.NaN(), math.NaN()}
+		}
+		return *gg
+	case *geom.PointM:
+		if gg == nil {
+			return geom.PointM{math.NaN(), math.NaN(), math.NaN()}
+		}
+		return *gg
+	case *geom.PointZM:
+		if gg == nil {
+			return geom.PointZM{math.NaN(), math.NaN(), math.NaN(), math.NaN()}
+		}
+		return *gg
+	case *geom.MultiPointZ:
+		if gg == nil {
+			return geom.MultiPointZ(nil)
+		}
+		return *gg
+	case *geom.MultiPointM:
+		if gg == nil {
+			return geom.MultiPointM(nil)
+		}
+		return *gg
+	case *geom.MultiPointZM:
+		if gg == nil {
+			return geom.MultiPointZM(nil)
+		}
+		return *gg
+	case *geom.LineStringZ:
+		if gg == nil {
+			return geom.LineStringZ(nil)
+		}
+		return *gg
+	case *geom.LineStringM:
+		if gg == nil {
+			return geom.LineStringM(nil)
+		}
+		return *gg
+	case *geom.LineStringZM:
+		if gg == nil {
+			return geom.LineStringZM(nil)
+		}
+		return *gg
+	case *geom.MultiLineStringZ:
+		if gg == nil {
+			return geom.MultiLineStringZ(nil)
+		}
+		return *gg
+	case *geom.MultiLineStringM:
+		if gg == nil {
+			return geom.MultiLineStringM(nil)
+		}
+		return *gg
+	case *geom.MultiLineStringZM:
+		if gg == nil {
+			return geom.MultiLineStringZM(nil)
+		}
+		return *gg
+	case *geom.PolygonZ:
+		if gg == nil {
+			return geom.PolygonZ(nil)
+		}
+		return *gg
+	case *geom.PolygonM:
+		if gg == nil {
+			return geom.PolygonM(nil)
+		}
+		return *gg
+	case *geom.PolygonZM:
+		if gg == nil {
+			return geom.PolygonZM(nil)
+		}
+		return *gg
+	case *geom.MultiPolygonZ:
+		if gg == nil {
+			return geom.MultiPolygonZ(nil)
+		}
+		return *gg
+	case *geom.MultiPolygonM:
+		if gg == nil {
+			return geom.MultiPolygonM(nil)
+		}
+		return *gg
+	case *geom.MultiPolygonZM:
+		if gg == nil {
+			return geom.MultiPolygonZM(nil)
+		}
+		return *gg
+	default:
+		return g
+	}
+}
+
+// encodeZM encodes the Z, M and ZM geometries, or pointers to them. ok is
+// false if geo is not one of those types.
+func (enc Encoder) encodeZM(geo geom.Geometry) (ok bool, err error) {
+	var (
+		keyword string
+		body    func() error
+	)
+
+	switch g := derefZM(geo).(type) {
+	case geom.PointZ:
+		keyword, body = "POINT Z ", func() error { return enc.encodePointZM(g[:]) }
+	case geom.PointM:
+		keyword, body = "POINT M ", func() error { return enc.encodePointZM(g[:]) }
+	case geom.PointZM:
+		keyword, body = "POINT ZM ", func() error { return enc.encodePointZM(g[:]) }
+
+	case geom.MultiPointZ:
+		keyword, body = "MULTIPOINT Z ", func() error { return enc.encodeMultiPointZM(coords3(g)) }
+	case geom.MultiPointM:
+		keyword, body = "MULTIPOINT M ", func() error { return enc.encodeMultiPointZM(coords3(g)) }
+	case geom.MultiPointZM:
+		keyword, body = "MULTIPOINT ZM ", func() error { return enc.encodeMultiPointZM(coords4(g)) }
+
+	case geom.LineStringZ:
+		keyword, body = "LINESTRING Z ", func() error { return enc.encodeCoordList(coords3(g), false) }
+	case geom.LineStringM:
+		keyword, body = "LINESTRING M ", func() error { return enc.encodeCoordList(coords3(g), false) }
+	case geom.LineStringZM:
+		keyword, body = "LINESTRING ZM ", func() error { return enc.encodeCoordList(coords4(g), false) }
+
+	case geom.MultiLineStringZ:
+		keyword, body = "MULTILINESTRING Z ", func() error { return enc.encodeCoordLists(lines3(g), false) }
+	case geom.MultiLineStringM:
+		keyword, body = "MULTILINESTRING M ", func() error { return enc.encodeCoordLists(lines3(g), false) }
+	case geom.MultiLineStringZM:
+		keyword, body = "MULTILINESTRING ZM ", func() error { return enc.encodeCoordLists(lines4(g), false) }
+
+	case geom.PolygonZ:
+		keyword, body = "POLYGON Z ", func() error { return enc.encodeCoordLists(lines3(g), true) }
+	case geom.PolygonM:
+		keyword, body = "POLYGON M ", func() error { return enc.encodeCoordLists(lines3(g), true) }
+	case geom.PolygonZM:
+		keyword, body = "POLYGON ZM ", func() error { return enc.encodeCoordLists(lines4(g), true) }
+
+	case geom.MultiPolygonZ:
+		keyword, body = "MULTIPOLYGON Z ", func() error { return enc.encodeCoordPolys(enc.orderCoordPolys(polys3(g))) }
+	case geom.MultiPolygonM:
+		keyword, body = "MULTIPOLYGON M ", func() error { return enc.encodeCoordPolys(enc.orderCoordPolys(polys3(g))) }
+	case geom.MultiPolygonZM:
+		keyword, body = "MULTIPOLYGON ZM ", func() error { return enc.encodeCoordPolys(enc.orderCoordPolys(polys4(g))) }
+
+	default:
+		return false, nil
+	}
+
+	if err = enc.string(keyword); err != nil {
+		return true, err
+	}
+	return true, body()
+}
@@ -10,6 +10,11 @@ import (
 	gtesting "github.com/go-spatial/geom/testing"
 )
 
+// testCollectioner is a collection that is not a geom.Collection
+type testCollectioner []geom.Geometry
+
+func (c testCollectioner) Geometries() []geom.Geometry { return c }
+
 func TestEncode(t *testing.T) {
 	type tcase struct {
 		Geom   geom.Geometry
@@ -448,6 +453,17 @@ func TestEncode(t *testing.T) {
 				},
 				Rep: "GEOMETRYCOLLECTION (POINT (10 10),LINESTRING (11 11,22 22))",
 			},
+			{
+				Geom: geom.Collection{
+					geom.Point{10, 10},
+					&geom.Collection{geom.Collection{geom.LineString{{11, 11}, {22, 22}}}},
+				},
+				Rep: "GEOMETRYCOLLECTION (POINT (10 10),GEOMETRYCOLLECTION (GEOMETRYCOLLECTION (LINESTRING (11 11,22 22))))",
+			},
+			{
+				Geom: testCollectioner{geom.Point{10, 10}},
+				Rep:  "GEOMETRYCOLLECTION (POINT (10 10))",
+			},
 		},
 		"MultiLine": {
 			{
@@ -461,6 +477,44 @@ func TestEncode(t *testing.T) {
 				Rep: "MULTILINESTRING ((0 20,10 0),(0 10,0 20),(0 10,10 0),(10 0,0 0),(0 0,0 10))",
 			},
 		},
+		"ZM": {
+			{
+				Geom: geom.PointZ{1, 2, 3},
+				Rep:  "POINT Z (1 2 3)",
+			},
+			{
+				Geom: &geom.PointM{1, 2, 3},
+				Rep:  "POINT M (1 2 3)",
+			},
+			{
+				Geom: (*geom.PointZM)(nil),
+				Rep:  "POINT ZM EMPTY",
+			},
+			{
+				Geom: geom.MultiPointZ{{1, 2, 3}, {4, 5, 6}},
+				Rep:  "MULTIPOINT Z ((1 2 3),(4 5 6))",
+			},
+			{
+				Geom: &geom.LineStringZ{{1, 2, 3}, {4, 5, 6}},
+				Rep:  "LINESTRING Z (1 2 3,4 5 6)",
+			},
+			{
+				Geom: (*geom.LineStringM)(nil),
+				Rep:  "LINESTRING M EMPTY",
+			},
+			{
+				Geom: geom.MultiLineStringZM{{{1, 2, 3, 4}, {5, 6, 7, 8}}},
+				Rep:  "MULTILINESTRING ZM ((1 2 3 4,5 6 7 8))",
+			},
+			{
+				Geom: geom.PolygonZ{{{0, 0, 1}, {1, 1, 1}, {1, 0, 1}}},
+				Rep:  "POLYGON Z ((0 0 1,1 1 1,1 0 1,0 0 1))",
+			},
+			{
+				Geom: &geom.MultiPolygonM{{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}}},
+				Rep:  "MULTIPOLYGON M (((0 0 1,1 1 2,1 0 3,0 0 1)))",
+			},
+		},
 	}
 	for name, subtests := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -482,3 +536,84 @@ func BenchmarkEncodeSin1000(b *testing.B) {
 		EncodeBytes(gtesting.SinLineString(1.0, 0.0, 100.0, 1000))
 	}
 }
+
+func TestEncodeOptions(t *testing.T) {
+	type tcase struct {
+		geom geom.Geometry
+		opts []EncodeOption
+		rep  string
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			rep, err := EncodeString(tc.geom, tc.opts...)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if rep != tc.rep {
+				t.Errorf("representation, expected ‘%v’ got ‘%v’", tc.rep, rep)
+			}
+		}
+	}
+
+	mpoly := geom.MultiPolygon{
+		{{{10, 10}, {11, 10}, {11, 11}}},
+		{{{0, 0}, {1, 0}, {1, 1}}},
+		{{{0, 0}, {1, 0}, {0, 1}}},
+	}
+	tests := map[string]tcase{
+		"default": {
+			geom: geom.Point{1.5, -2},
+			rep:  "POINT (1.5 -2)",
+		},
+		"precision": {
+			geom: geom.Point{1.23456, -2},
+			opts: []EncodeOption{WithPrecision(3)},
+			rep:  "POINT (1.235 -2.000)",
+		},
+		"precision trimmed": {
+			geom: geom.LineString{{1.23456, -2}, {-0.0001, 10.5}},
+			opts: []EncodeOption{WithPrecision(3), WithTrimZeros()},
+			rep:  "LINESTRING (1.235 -2,0 10.5)",
+		},
+		"trimmed exponent": {
+			geom: geom.Point{1.5e20, 2},
+			opts: []EncodeOption{WithTrimZeros()},
+			rep:  "POINT (1.5e+20 2)",
+		},
+		"lowercase": {
+			geom: geom.Collection{geom.Point{1, 2}, (*geom.LineString)(nil)},
+			opts: []EncodeOption{WithKeywordCase(Lowercase)},
+			rep:  "geometrycollection (point (1 2),linestring empty)",
+		},
+		"uppercase": {
+			geom: geom.PointZ{1, 2, 3},
+			opts: []EncodeOption{WithKeywordCase(Uppercase)},
+			rep:  "POINT Z (1 2 3)",
+		},
+		"unsorted parts": {
+			geom: mpoly,
+			rep:  "MULTIPOLYGON (((10 10,11 10,11 11,10 10)),((0 0,1 0,1 1,0 0)),((0 0,1 0,0 1,0 0)))",
+		},
+		"sorted parts": {
+			geom: mpoly,
+			opts: []EncodeOption{WithSortedParts()},
+			rep:  "MULTIPOLYGON (((0 0,1 0,0 1,0 0)),((0 0,1 0,1 1,0 0)),((10 10,11 10,11 11,10 10)))",
+		},
+		"sorted parts z": {
+			geom: geom.MultiPolygonZ{
+				{{{0, 0, 2}, {1, 0, 2}, {1, 1, 2}}},
+				{{{0, 0, 1}, {1, 0, 1}, {1, 1, 1}}},
+			},
+			opts: []EncodeOption{WithSortedParts()},
+			rep:  "MULTIPOLYGON Z (((0 0 1,1 0 1,1 1 1,0 0 1)),((0 0 2,1 0 2,1 1 2,0 0 2)))",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	// the multipolygon is not sorted in place
+	if mpoly[0][0][0] != [2]float64{10, 10} {
+		t.Errorf("multipolygon, expected unchanged got %v", mpoly)
+	}
+}
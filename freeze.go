@@ -0,0 +1,118 @@
+package geom
+
+import (
+	"errors"
+	"math"
+	"reflect"
+)
+
+// ErrMutated is returned, or panicked with, when a frozen geometry has
+// been changed.
+var ErrMutated = errors.New("geom: frozen geometry was changed")
+
+// Frozen guards a geometry that is shared, such as between the stages of a
+// pipeline, against being changed. Go can not stop the slices of a
+// geometry being written to, so Frozen keeps a copy of the geometry and
+// panics when the geometry is next used if it no longer matches: a change
+// through a slice sharing its backing array is caught where the geometry
+// is used, instead of corrupting what is made from it.
+//
+// Comparing the geometry with the copy takes as long as cloning it, so
+// Frozen is for finding where geometries are changed, rather than for
+// every geometry of a pipeline.
+type Frozen struct {
+	g    Geometry
+	copy Geometry
+}
+
+// Freeze returns the geometry frozen. The geometry is not copied, so it
+// must not be changed after it is frozen; see Frozen. The geometry types
+// are those of Clone.
+func Freeze(g Geometry) (*Frozen, error) {
+	c, err := Clone(g)
+	if err != nil {
+		return nil, err
+	}
+	return &Frozen{g: g, copy: c}, nil
+}
+
+// Geometry returns the frozen geometry, which must not be changed. It
+// panics with ErrMutated if the geometry has been changed since it was
+// frozen.
+func (f *Frozen) Geometry() Geometry {
+	if err := f.Check(); err != nil {
+		panic(err)
+	}
+	return f.g
+}
+
+// Clone returns a copy of the frozen geometry, which may be changed. It
+// panics with ErrMutated if the geometry has been changed since it was
+// frozen.
+func (f *Frozen) Clone() Geometry {
+	g := f.Geometry()
+	// the geometry was cloned when it was frozen, so can be again
+	c, _ := Clone(g)
+	return c
+}
+
+// Check returns ErrMutated if the geometry has been changed since it was
+// frozen.
+func (f *Frozen) Check() error {
+	if !sameBits(reflect.ValueOf(f.g), reflect.ValueOf(f.copy)) {
+		return ErrMutated
+	}
+	return nil
+}
+
+// sameBits reports whether the values are the same, with floats compared
+// bit for bit, so NaNs are the same as themselves.
+func sameBits(a, b reflect.Value) bool {
+	if a.IsValid() != b.IsValid() {
+		return false
+	}
+	if !a.IsValid() {
+		return true
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return math.Float64bits(a.Float()) == math.Float64bits(b.Float())
+	case reflect.Slice:
+		if a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+			return false
+		}
+		fallthrough
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			if !sameBits(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Interface, reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return sameBits(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !sameBits(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.String:
+		return a.String() == b.String()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,87 @@
+package geom_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestFreeze(t *testing.T) {
+	type tcase struct {
+		g geom.Geometry
+		// mutate changes the geometry, if it is to be changed
+		mutate func(g geom.Geometry)
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			f, err := geom.Freeze(tc.g)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if err := f.Check(); err != nil {
+				t.Fatalf("check, expected nil got %v", err)
+			}
+			// a clone may be changed
+			if c := f.Clone(); tc.mutate != nil {
+				tc.mutate(c)
+				if err := f.Check(); err != nil {
+					t.Fatalf("check after changing a clone, expected nil got %v", err)
+				}
+			}
+			if tc.mutate == nil {
+				return
+			}
+
+			tc.mutate(tc.g)
+			if err := f.Check(); err != geom.ErrMutated {
+				t.Errorf("check, expected %v got %v", geom.ErrMutated, err)
+			}
+			defer func() {
+				if r := recover(); r != geom.ErrMutated {
+					t.Errorf("panic, expected %v got %v", geom.ErrMutated, r)
+				}
+			}()
+			f.Geometry()
+		}
+	}
+
+	tests := map[string]tcase{
+		"unchanged": {
+			g: geom.Polygon{{{0, 0}, {1, 0}, {1, 1}}},
+		},
+		"nan": {
+			g: geom.LineString{{0, math.NaN()}, {1, 1}},
+		},
+		"point changed": {
+			g: geom.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}}}},
+			mutate: func(g geom.Geometry) {
+				g.(geom.MultiPolygon)[0][0][1][0] = 2
+			},
+		},
+		"appended to": {
+			g: &geom.LineString{{0, 0}, {1, 1}},
+			mutate: func(g geom.Geometry) {
+				ls := g.(*geom.LineString)
+				*ls = append(*ls, [2]float64{2, 2})
+			},
+		},
+		"set": {
+			g: &geom.Polygon{{{0, 0}, {1, 0}, {1, 1}}},
+			mutate: func(g geom.Geometry) {
+				_ = g.(*geom.Polygon).SetLinearRings([][][2]float64{{{5, 5}, {6, 5}, {6, 6}}})
+			},
+		},
+		"in a collection": {
+			g: geom.Collection{geom.Point{1, 1}, geom.MultiPointZ{{1, 2, 3}}},
+			mutate: func(g geom.Geometry) {
+				g.(geom.Collection)[1].(geom.MultiPointZ)[0][2] = 4
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -62,6 +62,9 @@ func getCoordinates(g Geometry, pts *[]Point) error {
 
 	default:
 
+		if xy, ok := XYGeometry(g); ok {
+			return getCoordinates(xy, pts)
+		}
 		return ErrUnknownGeometry{g}
 
 	case Pointer:
@@ -141,6 +144,9 @@ func getExtent(g Geometry, e *Extent) error {
 
 	default:
 
+		if xy, ok := XYGeometry(g); ok {
+			return getExtent(xy, e)
+		}
 		return ErrUnknownGeometry{g}
 
 	case Pointer:
@@ -201,6 +207,9 @@ func extractLines(g Geometry, lines *[]Line) error {
 
 	default:
 
+		if xy, ok := XYGeometry(g); ok {
+			return extractLines(xy, lines)
+		}
 		return ErrUnknownGeometry{g}
 
 	case Pointer:
@@ -317,6 +326,9 @@ func IsEmpty(geo Geometry) bool {
 	case Collectioner:
 		return len(g.Geometries()) == 0
 	default:
+		if xy, ok := XYGeometry(geo); ok {
+			return IsEmpty(xy)
+		}
 		return true
 	}
 }
@@ -253,3 +253,101 @@ func TestExtractLines(t *testing.T) {
 		t.Run(strconv.FormatInt(int64(i), 10), func(t *testing.T) { fn(t, tc) })
 	}
 }
+
+func TestXYGeometry(t *testing.T) {
+
+	type tcase struct {
+		geom   Geometry
+		coords []Point
+		lines  []Line
+		extent *Extent
+		empty  bool
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		coords, err := GetCoordinates(tc.geom)
+		if err != nil {
+			t.Errorf("coordinates error, expected nil got %v", err)
+		}
+		if !reflect.DeepEqual(coords, tc.coords) {
+			t.Errorf("coordinates, expected %v got %v", tc.coords, coords)
+		}
+
+		lines, err := ExtractLines(tc.geom)
+		if err != nil {
+			t.Errorf("lines error, expected nil got %v", err)
+		}
+		if !(len(lines) == 0 && len(tc.lines) == 0) && !reflect.DeepEqual(lines, tc.lines) {
+			t.Errorf("lines, expected %v got %v", tc.lines, lines)
+		}
+
+		if tc.extent != nil {
+			extent, err := NewExtentFromGeometry(tc.geom)
+			if err != nil {
+				t.Errorf("extent error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(extent, tc.extent) {
+				t.Errorf("extent, expected %v got %v", tc.extent, extent)
+			}
+		}
+
+		if empty := IsEmpty(tc.geom); empty != tc.empty {
+			t.Errorf("is empty, expected %v got %v", tc.empty, empty)
+		}
+	}
+
+	testcases := map[string]tcase{
+		"point z": {
+			geom:   PointZ{10, 20, 30},
+			coords: []Point{{10, 20}},
+			extent: &Extent{10, 20, 10, 20},
+		},
+		"point zm pointer": {
+			geom:   &PointZM{10, 20, 30, 40},
+			coords: []Point{{10, 20}},
+			extent: &Extent{10, 20, 10, 20},
+		},
+		"multipoint m": {
+			geom:   MultiPointM{{10, 20, 1}, {30, 40, 2}},
+			coords: []Point{{10, 20}, {30, 40}},
+			extent: &Extent{10, 20, 30, 40},
+		},
+		"linestring z pointer": {
+			geom:   &LineStringZ{{10, 20, 1}, {30, 40, 2}},
+			coords: []Point{{10, 20}, {30, 40}},
+			lines:  []Line{{{10, 20}, {30, 40}}},
+			extent: &Extent{10, 20, 30, 40},
+		},
+		"multilinestring zm": {
+			geom:   MultiLineStringZM{{{10, 20, 1, 1}, {30, 40, 2, 2}}},
+			coords: []Point{{10, 20}, {30, 40}},
+			lines:  []Line{{{10, 20}, {30, 40}}},
+			extent: &Extent{10, 20, 30, 40},
+		},
+		"polygon m": {
+			geom:   PolygonM{{{0, 0, 1}, {10, 0, 2}, {10, 10, 3}}},
+			coords: []Point{{0, 0}, {10, 0}, {10, 10}},
+			lines:  []Line{{{0, 0}, {10, 0}}, {{10, 0}, {10, 10}}, {{10, 10}, {0, 0}}},
+			extent: &Extent{0, 0, 10, 10},
+		},
+		"multipolygon z pointer": {
+			geom:   &MultiPolygonZ{{{{0, 0, 1}, {10, 0, 2}, {10, 10, 3}}}},
+			coords: []Point{{0, 0}, {10, 0}, {10, 10}},
+			lines:  []Line{{{0, 0}, {10, 0}}, {{10, 0}, {10, 10}}, {{10, 10}, {0, 0}}},
+			extent: &Extent{0, 0, 10, 10},
+		},
+		"empty linestring m": {
+			geom:  LineStringM{},
+			empty: true,
+		},
+		"nil polygon zm": {
+			geom:  (*PolygonZM)(nil),
+			empty: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
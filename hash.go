@@ -0,0 +1,182 @@
+package geom
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+)
+
+// the kinds of geometries, written before each geometry hashed
+const (
+	hashNil byte = iota
+	hashPoint
+	hashMultiPoint
+	hashLineString
+	hashMultiLineString
+	hashPolygon
+	hashMultiPolygon
+	hashCollection
+	hashExtent
+)
+
+// Hash returns a 64 bit hash of the geometry, for finding duplicates and
+// as a key for caches. The hash is of the normalized geometry, see
+// Normalize, so geometries that only differ in the order of their points
+// or parts have the same hash, as do a Line and a LineString, and a
+// Triangle and a Polygon, with the same points. Geometries with different
+// hashes are different, but different geometries may, rarely, have the
+// same hash.
+//
+// The hash is FNV-1a of the kinds, sizes and coordinates of the
+// geometries, so it is the same from run to run and on every platform. It
+// is the same for 0 and -0, and for all NaNs. Geometries with Z or M
+// values are not supported, as by Normalize.
+func Hash(g Geometry) (uint64, error) {
+	return HashWithPrecision(g, PrecisionModel{})
+}
+
+// HashWithPrecision is Hash of the geometry with its points rounded to the
+// grid of the precision model, so geometries whose points are close enough
+// to round to the same places have the same hash.
+func HashWithPrecision(g Geometry, pm PrecisionModel) (uint64, error) {
+	h := fnv.New64a()
+	if err := writeHash(h, g, pm); err != nil {
+		return 0, err
+	}
+	return h.Sum64(), nil
+}
+
+// Hash128 is Hash returning a 128 bit hash, for when there are enough
+// geometries that 64 bit hashes are likely to be the same.
+func Hash128(g Geometry) ([16]byte, error) {
+	return Hash128WithPrecision(g, PrecisionModel{})
+}
+
+// Hash128WithPrecision is HashWithPrecision returning a 128 bit hash.
+func Hash128WithPrecision(g Geometry, pm PrecisionModel) (sum [16]byte, err error) {
+	h := fnv.New128a()
+	if err := writeHash(h, g, pm); err != nil {
+		return sum, err
+	}
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
+
+// writeHash writes the normalized geometry, rounded to the grid of the
+// precision model, to the hash
+func writeHash(h hash.Hash, g Geometry, pm PrecisionModel) error {
+	ng, err := Normalize(g)
+	if err != nil {
+		return err
+	}
+	if !pm.IsFloating() {
+		if e, ok := ng.(Extent); ok {
+			for i := range e {
+				e[i] = pm.MakePrecise(e[i])
+			}
+			ng = e
+		} else if reflect.ValueOf(ng).Kind() != reflect.Ptr {
+			// the only pointers Normalize returns are nil; rounding can
+			// change the order of the points, so normalize again
+			if ng, err = SnapToGrid(ng, pm); err != nil {
+				return err
+			}
+			if ng, err = Normalize(ng); err != nil {
+				return err
+			}
+		}
+	}
+	hw := hashWriter{h: h}
+	hw.geometry(ng)
+	return nil
+}
+
+// hashWriter writes normalized geometries to a hash
+type hashWriter struct {
+	h   hash.Hash
+	buf [8]byte
+}
+
+func (hw *hashWriter) kind(k byte, n int) {
+	hw.h.Write([]byte{k})
+	binary.LittleEndian.PutUint64(hw.buf[:], uint64(n))
+	hw.h.Write(hw.buf[:])
+}
+
+func (hw *hashWriter) float(f float64) {
+	var bits uint64
+	switch {
+	case f == 0:
+		// -0 is the same as 0
+	case math.IsNaN(f):
+		bits = 0x7ff8000000000001
+	default:
+		bits = math.Float64bits(f)
+	}
+	binary.LittleEndian.PutUint64(hw.buf[:], bits)
+	hw.h.Write(hw.buf[:])
+}
+
+func (hw *hashWriter) points(pts [][2]float64) {
+	binary.LittleEndian.PutUint64(hw.buf[:], uint64(len(pts)))
+	hw.h.Write(hw.buf[:])
+	for _, pt := range pts {
+		hw.float(pt[0])
+		hw.float(pt[1])
+	}
+}
+
+func (hw *hashWriter) geometry(g Geometry) {
+	switch g := g.(type) {
+	case Point:
+		hw.kind(hashPoint, 1)
+		hw.float(g[0])
+		hw.float(g[1])
+	case MultiPoint:
+		hw.kind(hashMultiPoint, len(g))
+		hw.points(g)
+	case Line:
+		hw.kind(hashLineString, 1)
+		hw.points(g[:])
+	case LineString:
+		hw.kind(hashLineString, 1)
+		hw.points(g)
+	case MultiLineString:
+		hw.kind(hashMultiLineString, len(g))
+		for _, ln := range g {
+			hw.points(ln)
+		}
+	case Triangle:
+		hw.kind(hashPolygon, 1)
+		hw.points(g[:])
+	case Polygon:
+		hw.kind(hashPolygon, len(g))
+		for _, r := range g {
+			hw.points(r)
+		}
+	case MultiPolygon:
+		hw.kind(hashMultiPolygon, len(g))
+		for _, ply := range g {
+			binary.LittleEndian.PutUint64(hw.buf[:], uint64(len(ply)))
+			hw.h.Write(hw.buf[:])
+			for _, r := range ply {
+				hw.points(r)
+			}
+		}
+	case Collection:
+		hw.kind(hashCollection, len(g))
+		for _, cg := range g {
+			hw.geometry(cg)
+		}
+	case Extent:
+		hw.kind(hashExtent, 1)
+		for _, f := range g {
+			hw.float(f)
+		}
+	default:
+		// the nil pointers
+		hw.kind(hashNil, 0)
+	}
+}
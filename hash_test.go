@@ -0,0 +1,107 @@
+package geom
+
+import "testing"
+
+func TestHash(t *testing.T) {
+	type tcase struct {
+		g1, g2 Geometry
+		pm     PrecisionModel
+		same   bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			h1, err := HashWithPrecision(tc.g1, tc.pm)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			h2, err := HashWithPrecision(tc.g2, tc.pm)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if (h1 == h2) != tc.same {
+				t.Errorf("same hash, expected %v got %v (%x, %x)", tc.same, h1 == h2, h1, h2)
+			}
+
+			l1, err := Hash128WithPrecision(tc.g1, tc.pm)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			l2, err := Hash128WithPrecision(tc.g2, tc.pm)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if (l1 == l2) != tc.same {
+				t.Errorf("same 128 bit hash, expected %v got %v", tc.same, l1 == l2)
+			}
+		}
+	}
+
+	square := Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}
+	tests := map[string]tcase{
+		"same": {
+			g1: square, g2: Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			same: true,
+		},
+		"rotated and reversed": {
+			g1: square, g2: &Polygon{{{10, 10}, {10, 0}, {0, 0}, {0, 10}}},
+			same: true,
+		},
+		"different": {
+			g1: square, g2: Polygon{{{0, 0}, {10, 0}, {10, 11}, {0, 10}}},
+		},
+		"kinds": {
+			g1: MultiPoint{{0, 0}, {1, 1}}, g2: LineString{{0, 0}, {1, 1}},
+		},
+		"line and linestring": {
+			g1: Line{{1, 1}, {0, 0}}, g2: LineString{{0, 0}, {1, 1}},
+			same: true,
+		},
+		"negative zero": {
+			g1: Point{0, 0}, g2: Point{negativeZero(), 0},
+			same: true,
+		},
+		"part order": {
+			g1:   MultiLineString{{{0, 0}, {1, 1}}, {{2, 2}, {3, 3}}},
+			g2:   MultiLineString{{{3, 3}, {2, 2}}, {{1, 1}, {0, 0}}},
+			same: true,
+		},
+		"parts split differently": {
+			g1: MultiLineString{{{0, 0}, {1, 1}, {2, 2}}},
+			g2: MultiLineString{{{0, 0}}, {{1, 1}, {2, 2}}},
+		},
+		"rounded": {
+			g1:   MultiPoint{{0.1, 5}, {0.2, 1}},
+			g2:   MultiPoint{{0, 1}, {0, 5}},
+			pm:   PrecisionModel{Scale: 1},
+			same: true,
+		},
+		"not rounded": {
+			g1: MultiPoint{{0.1, 5}, {0.2, 1}},
+			g2: MultiPoint{{0, 1}, {0, 5}},
+		},
+		"collection": {
+			g1:   Collection{Point{1, 2}, square},
+			g2:   Collection{Point{1, 2}, Polygon{{{0, 10}, {10, 10}, {10, 0}, {0, 0}}}},
+			same: true,
+		},
+		"nil": {
+			g1: (*LineString)(nil), g2: (*Polygon)(nil),
+			same: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	if _, err := Hash(PointZ{1, 2, 3}); err == nil {
+		t.Errorf("unknown geometry error, expected an error got nil")
+	}
+}
+
+// negativeZero returns -0, which can not be written as a constant
+func negativeZero() float64 {
+	var z float64
+	return -z
+}
@@ -0,0 +1,69 @@
+package geom
+
+import "errors"
+
+// ErrNilLineStringZ is thrown when a LineStringZ is nil but shouldn't be
+var ErrNilLineStringZ = errors.New("geom: nil LineStringZ")
+
+// LineStringZ is a LineString made up of 3D coordinates.
+type LineStringZ [][3]float64
+
+// Vertices returns a slice of 3D coordinates
+func (ls LineStringZ) Vertices() [][3]float64 { return ls }
+
+// LineString returns the 2D linestring, dropping the z values
+func (ls LineStringZ) LineString() LineString { return LineString(xyOf3(ls)) }
+
+// SetVertices modifies the array of 3D coordinates
+func (ls *LineStringZ) SetVertices(input [][3]float64) (err error) {
+	if ls == nil {
+		return ErrNilLineStringZ
+	}
+
+	*ls = append((*ls)[:0], input...)
+	return
+}
+
+// ErrNilLineStringM is thrown when a LineStringM is nil but shouldn't be
+var ErrNilLineStringM = errors.New("geom: nil LineStringM")
+
+// LineStringM is a LineString made up of 2D coordinates and measures.
+type LineStringM [][3]float64
+
+// Vertices returns a slice of 2D coordinates and measures
+func (ls LineStringM) Vertices() [][3]float64 { return ls }
+
+// LineString returns the 2D linestring, dropping the m values
+func (ls LineStringM) LineString() LineString { return LineString(xyOf3(ls)) }
+
+// SetVertices modifies the array of 2D coordinates and measures
+func (ls *LineStringM) SetVertices(input [][3]float64) (err error) {
+	if ls == nil {
+		return ErrNilLineStringM
+	}
+
+	*ls = append((*ls)[:0], input...)
+	return
+}
+
+// ErrNilLineStringZM is thrown when a LineStringZM is nil but shouldn't be
+var ErrNilLineStringZM = errors.New("geom: nil LineStringZM")
+
+// LineStringZM is a LineString made up of 3D coordinates and measures.
+type LineStringZM [][4]float64
+
+// Vertices returns a slice of 3D coordinates and measures
+func (ls LineStringZM) Vertices() [][4]float64 { return ls }
+
+// LineString returns the 2D linestring, dropping the z and m values
+func (ls LineStringZM) LineString() LineString { return LineString(xyOf4(ls)) }
+
+// SetVertices modifies the array of 3D coordinates and measures
+func (ls *LineStringZM) SetVertices(input [][4]float64) (err error) {
+	if ls == nil {
+		return ErrNilLineStringZM
+	}
+
+	*ls = append((*ls)[:0], input...)
+	return
+}
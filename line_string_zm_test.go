@@ -0,0 +1,121 @@
+package geom_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestLineStringZMSetter(t *testing.T) {
+	type tcase struct {
+		set      func() error
+		setter   geom.Geometry
+		expected geom.Geometry
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			err := tc.set()
+			if tc.err == nil && err != nil {
+				t.Errorf("error, expected nil got %v", err)
+				return
+			}
+			if tc.err != nil {
+				if err == nil || tc.err.Error() != err.Error() {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+
+			// compare the results
+			if !reflect.DeepEqual(tc.expected, tc.setter) {
+				t.Errorf("setter, expected %v got %v", tc.expected, tc.setter)
+			}
+		}
+	}
+
+	tests := map[string]func() tcase{
+		"LineStringZ": func() tcase {
+			g := &geom.LineStringZ{}
+			return tcase{
+				set:      func() error { return g.SetVertices([][3]float64{{1, 2, 3}, {4, 5, 6}}) },
+				setter:   g,
+				expected: &geom.LineStringZ{{1, 2, 3}, {4, 5, 6}},
+			}
+		},
+		"nil LineStringZ": func() tcase {
+			var g *geom.LineStringZ
+			return tcase{
+				set: func() error { return g.SetVertices([][3]float64{{1, 2, 3}, {4, 5, 6}}) },
+				err: geom.ErrNilLineStringZ,
+			}
+		},
+		"LineStringM": func() tcase {
+			g := &geom.LineStringM{{0, 0, 0}}
+			return tcase{
+				set:      func() error { return g.SetVertices([][3]float64{{1, 2, 3}, {4, 5, 6}}) },
+				setter:   g,
+				expected: &geom.LineStringM{{1, 2, 3}, {4, 5, 6}},
+			}
+		},
+		"nil LineStringM": func() tcase {
+			var g *geom.LineStringM
+			return tcase{
+				set: func() error { return g.SetVertices([][3]float64{{1, 2, 3}, {4, 5, 6}}) },
+				err: geom.ErrNilLineStringM,
+			}
+		},
+		"LineStringZM": func() tcase {
+			g := &geom.LineStringZM{}
+			return tcase{
+				set:      func() error { return g.SetVertices([][4]float64{{1, 2, 3, 4}, {5, 6, 7, 8}}) },
+				setter:   g,
+				expected: &geom.LineStringZM{{1, 2, 3, 4}, {5, 6, 7, 8}},
+			}
+		},
+		"nil LineStringZM": func() tcase {
+			var g *geom.LineStringZM
+			return tcase{
+				set: func() error { return g.SetVertices([][4]float64{{1, 2, 3, 4}, {5, 6, 7, 8}}) },
+				err: geom.ErrNilLineStringZM,
+			}
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc()))
+	}
+}
+
+func TestLineStringZMProjection(t *testing.T) {
+	type tcase struct {
+		got      geom.LineString
+		expected geom.LineString
+	}
+
+	tests := map[string]tcase{
+		"LineStringZ": {
+			got:      geom.LineStringZ{{1, 2, 3}, {4, 5, 6}}.LineString(),
+			expected: geom.LineString{{1, 2}, {4, 5}},
+		},
+		"LineStringM": {
+			got:      geom.LineStringM{{1, 2, 3}, {4, 5, 6}}.LineString(),
+			expected: geom.LineString{{1, 2}, {4, 5}},
+		},
+		"LineStringZM": {
+			got:      geom.LineStringZM{{1, 2, 3, 4}, {5, 6, 7, 8}}.LineString(),
+			expected: geom.LineString{{1, 2}, {5, 6}},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.expected, tc.got) {
+				t.Errorf("%v, expected %v got %v", name, tc.expected, tc.got)
+			}
+		})
+	}
+}
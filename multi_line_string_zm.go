@@ -0,0 +1,75 @@
+package geom
+
+import "errors"
+
+// ErrNilMultiLineStringZ is thrown when a MultiLineStringZ is nil but shouldn't be
+var ErrNilMultiLineStringZ = errors.New("geom: nil MultiLineStringZ")
+
+// MultiLineStringZ is a geometry with multiple LineStringZs.
+type MultiLineStringZ [][][3]float64
+
+// LineStrings returns the coordinates for the linestrings
+func (mls MultiLineStringZ) LineStrings() [][][3]float64 { return mls }
+
+// MultiLineString returns the 2D multilinestring, dropping the z values
+func (mls MultiLineStringZ) MultiLineString() MultiLineString {
+	return MultiLineString(xyOfLines3(mls))
+}
+
+// SetLineStrings modifies the array of 3D coordinates
+func (mls *MultiLineStringZ) SetLineStrings(input [][][3]float64) (err error) {
+	if mls == nil {
+		return ErrNilMultiLineStringZ
+	}
+
+	*mls = append((*mls)[:0], input...)
+	return
+}
+
+// ErrNilMultiLineStringM is thrown when a MultiLineStringM is nil but shouldn't be
+var ErrNilMultiLineStringM = errors.New("geom: nil MultiLineStringM")
+
+// MultiLineStringM is a geometry with multiple LineStringMs.
+type MultiLineStringM [][][3]float64
+
+// LineStrings returns the coordinates for the linestrings
+func (mls MultiLineStringM) LineStrings() [][][3]float64 { return mls }
+
+// MultiLineString returns the 2D multilinestring, dropping the m values
+func (mls MultiLineStringM) MultiLineString() MultiLineString {
+	return MultiLineString(xyOfLines3(mls))
+}
+
+// SetLineStrings modifies the array of 2D coordinates and measures
+func (mls *MultiLineStringM) SetLineStrings(input [][][3]float64) (err error) {
+	if mls == nil {
+		return ErrNilMultiLineStringM
+	}
+
+	*mls = append((*mls)[:0], input...)
+	return
+}
+
+// ErrNilMultiLineStringZM is thrown when a MultiLineStringZM is nil but shouldn't be
+var ErrNilMultiLineStringZM = errors.New("geom: nil MultiLineStringZM")
+
+// MultiLineStringZM is a geometry with multiple LineStringZMs.
+type MultiLineStringZM [][][4]float64
+
+// LineStrings returns the coordinates for the linestrings
+func (mls MultiLineStringZM) LineStrings() [][][4]float64 { return mls }
+
+// MultiLineString returns the 2D multilinestring, dropping the z and m values
+func (mls MultiLineStringZM) MultiLineString() MultiLineString {
+	return MultiLineString(xyOfLines4(mls))
+}
+
+// SetLineStrings modifies the array of 3D coordinates and measures
+func (mls *MultiLineStringZM) SetLineStrings(input [][][4]float64) (err error) {
+	if mls == nil {
+		return ErrNilMultiLineStringZM
+	}
+
+	*mls = append((*mls)[:0], input...)
+	return
+}
@@ -0,0 +1,121 @@
+package geom_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestMultiLineStringZMSetter(t *testing.T) {
+	type tcase struct {
+		set      func() error
+		setter   geom.Geometry
+		expected geom.Geometry
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			err := tc.set()
+			if tc.err == nil && err != nil {
+				t.Errorf("error, expected nil got %v", err)
+				return
+			}
+			if tc.err != nil {
+				if err == nil || tc.err.Error() != err.Error() {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+
+			// compare the results
+			if !reflect.DeepEqual(tc.expected, tc.setter) {
+				t.Errorf("setter, expected %v got %v", tc.expected, tc.setter)
+			}
+		}
+	}
+
+	tests := map[string]func() tcase{
+		"MultiLineStringZ": func() tcase {
+			g := &geom.MultiLineStringZ{}
+			return tcase{
+				set:      func() error { return g.SetLineStrings([][][3]float64{{{1, 2, 3}, {4, 5, 6}}}) },
+				setter:   g,
+				expected: &geom.MultiLineStringZ{{{1, 2, 3}, {4, 5, 6}}},
+			}
+		},
+		"nil MultiLineStringZ": func() tcase {
+			var g *geom.MultiLineStringZ
+			return tcase{
+				set: func() error { return g.SetLineStrings([][][3]float64{{{1, 2, 3}, {4, 5, 6}}}) },
+				err: geom.ErrNilMultiLineStringZ,
+			}
+		},
+		"MultiLineStringM": func() tcase {
+			g := &geom.MultiLineStringM{}
+			return tcase{
+				set:      func() error { return g.SetLineStrings([][][3]float64{{{1, 2, 3}, {4, 5, 6}}}) },
+				setter:   g,
+				expected: &geom.MultiLineStringM{{{1, 2, 3}, {4, 5, 6}}},
+			}
+		},
+		"nil MultiLineStringM": func() tcase {
+			var g *geom.MultiLineStringM
+			return tcase{
+				set: func() error { return g.SetLineStrings([][][3]float64{{{1, 2, 3}, {4, 5, 6}}}) },
+				err: geom.ErrNilMultiLineStringM,
+			}
+		},
+		"MultiLineStringZM": func() tcase {
+			g := &geom.MultiLineStringZM{}
+			return tcase{
+				set:      func() error { return g.SetLineStrings([][][4]float64{{{1, 2, 3, 4}, {5, 6, 7, 8}}}) },
+				setter:   g,
+				expected: &geom.MultiLineStringZM{{{1, 2, 3, 4}, {5, 6, 7, 8}}},
+			}
+		},
+		"nil MultiLineStringZM": func() tcase {
+			var g *geom.MultiLineStringZM
+			return tcase{
+				set: func() error { return g.SetLineStrings([][][4]float64{{{1, 2, 3, 4}, {5, 6, 7, 8}}}) },
+				err: geom.ErrNilMultiLineStringZM,
+			}
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc()))
+	}
+}
+
+func TestMultiLineStringZMProjection(t *testing.T) {
+	type tcase struct {
+		got      geom.MultiLineString
+		expected geom.MultiLineString
+	}
+
+	tests := map[string]tcase{
+		"MultiLineStringZ": {
+			got:      geom.MultiLineStringZ{{{1, 2, 3}, {4, 5, 6}}}.MultiLineString(),
+			expected: geom.MultiLineString{{{1, 2}, {4, 5}}},
+		},
+		"MultiLineStringM": {
+			got:      geom.MultiLineStringM{{{1, 2, 3}, {4, 5, 6}}, {{7, 8, 9}, {10, 11, 12}}}.MultiLineString(),
+			expected: geom.MultiLineString{{{1, 2}, {4, 5}}, {{7, 8}, {10, 11}}},
+		},
+		"MultiLineStringZM": {
+			got:      geom.MultiLineStringZM{{{1, 2, 3, 4}, {5, 6, 7, 8}}}.MultiLineString(),
+			expected: geom.MultiLineString{{{1, 2}, {5, 6}}},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.expected, tc.got) {
+				t.Errorf("%v, expected %v got %v", name, tc.expected, tc.got)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package geom
+
+import "errors"
+
+// ErrNilMultiPointZ is thrown when a MultiPointZ is nil but shouldn't be
+var ErrNilMultiPointZ = errors.New("geom: nil MultiPointZ")
+
+// MultiPointZ is a geometry with multiple points, each with 3D coordinates.
+type MultiPointZ [][3]float64
+
+// Points returns the slice of 3D coordinates
+func (mp MultiPointZ) Points() [][3]float64 { return mp }
+
+// MultiPoint returns the 2D multipoint, dropping the z values
+func (mp MultiPointZ) MultiPoint() MultiPoint { return MultiPoint(xyOf3(mp)) }
+
+// SetPoints modifies the array of 3D coordinates
+func (mp *MultiPointZ) SetPoints(input [][3]float64) (err error) {
+	if mp == nil {
+		return ErrNilMultiPointZ
+	}
+
+	*mp = append((*mp)[:0], input...)
+	return
+}
+
+// ErrNilMultiPointM is thrown when a MultiPointM is nil but shouldn't be
+var ErrNilMultiPointM = errors.New("geom: nil MultiPointM")
+
+// MultiPointM is a geometry with multiple points, each with 2D coordinates and a measure.
+type MultiPointM [][3]float64
+
+// Points returns the slice of 2D coordinates and measures
+func (mp MultiPointM) Points() [][3]float64 { return mp }
+
+// MultiPoint returns the 2D multipoint, dropping the m values
+func (mp MultiPointM) MultiPoint() MultiPoint { return MultiPoint(xyOf3(mp)) }
+
+// SetPoints modifies the array of 2D coordinates and measures
+func (mp *MultiPointM) SetPoints(input [][3]float64) (err error) {
+	if mp == nil {
+		return ErrNilMultiPointM
+	}
+
+	*mp = append((*mp)[:0], input...)
+	return
+}
+
+// ErrNilMultiPointZM is thrown when a MultiPointZM is nil but shouldn't be
+var ErrNilMultiPointZM = errors.New("geom: nil MultiPointZM")
+
+// MultiPointZM is a geometry with multiple points, each with 3D coordinates and a measure.
+type MultiPointZM [][4]float64
+
+// Points returns the slice of 3D coordinates and measures
+func (mp MultiPointZM) Points() [][4]float64 { return mp }
+
+// MultiPoint returns the 2D multipoint, dropping the z and m values
+func (mp MultiPointZM) MultiPoint() MultiPoint { return MultiPoint(xyOf4(mp)) }
+
+// SetPoints modifies the array of 3D coordinates and measures
+func (mp *MultiPointZM) SetPoints(input [][4]float64) (err error) {
+	if mp == nil {
+		return ErrNilMultiPointZM
+	}
+
+	*mp = append((*mp)[:0], input...)
+	return
+}
@@ -0,0 +1,125 @@
+package geom_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestMultiPointZMSetter(t *testing.T) {
+	type tcase struct {
+		set      func() error
+		setter   geom.Geometry
+		expected geom.Geometry
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			err := tc.set()
+			if tc.err == nil && err != nil {
+				t.Errorf("error, expected nil got %v", err)
+				return
+			}
+			if tc.err != nil {
+				if err == nil || tc.err.Error() != err.Error() {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+
+			// compare the results
+			if !reflect.DeepEqual(tc.expected, tc.setter) {
+				t.Errorf("setter, expected %v got %v", tc.expected, tc.setter)
+			}
+		}
+	}
+
+	tests := map[string]func() tcase{
+		"MultiPointZ": func() tcase {
+			g := &geom.MultiPointZ{}
+			return tcase{
+				set:      func() error { return g.SetPoints([][3]float64{{1, 2, 3}, {4, 5, 6}}) },
+				setter:   g,
+				expected: &geom.MultiPointZ{{1, 2, 3}, {4, 5, 6}},
+			}
+		},
+		"nil MultiPointZ": func() tcase {
+			var g *geom.MultiPointZ
+			return tcase{
+				set: func() error { return g.SetPoints([][3]float64{{1, 2, 3}, {4, 5, 6}}) },
+				err: geom.ErrNilMultiPointZ,
+			}
+		},
+		"MultiPointM": func() tcase {
+			g := &geom.MultiPointM{{9, 9, 9}}
+			return tcase{
+				set:      func() error { return g.SetPoints([][3]float64{{1, 2, 3}}) },
+				setter:   g,
+				expected: &geom.MultiPointM{{1, 2, 3}},
+			}
+		},
+		"nil MultiPointM": func() tcase {
+			var g *geom.MultiPointM
+			return tcase{
+				set: func() error { return g.SetPoints([][3]float64{{1, 2, 3}}) },
+				err: geom.ErrNilMultiPointM,
+			}
+		},
+		"MultiPointZM": func() tcase {
+			g := &geom.MultiPointZM{}
+			return tcase{
+				set:      func() error { return g.SetPoints([][4]float64{{1, 2, 3, 4}}) },
+				setter:   g,
+				expected: &geom.MultiPointZM{{1, 2, 3, 4}},
+			}
+		},
+		"nil MultiPointZM": func() tcase {
+			var g *geom.MultiPointZM
+			return tcase{
+				set: func() error { return g.SetPoints([][4]float64{{1, 2, 3, 4}}) },
+				err: geom.ErrNilMultiPointZM,
+			}
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc()))
+	}
+}
+
+func TestMultiPointZMProjection(t *testing.T) {
+	type tcase struct {
+		got      geom.MultiPoint
+		expected geom.MultiPoint
+	}
+
+	tests := map[string]tcase{
+		"MultiPointZ": {
+			got:      geom.MultiPointZ{{1, 2, 3}, {4, 5, 6}}.MultiPoint(),
+			expected: geom.MultiPoint{{1, 2}, {4, 5}},
+		},
+		"MultiPointM": {
+			got:      geom.MultiPointM{{1, 2, 3}}.MultiPoint(),
+			expected: geom.MultiPoint{{1, 2}},
+		},
+		"MultiPointZM": {
+			got:      geom.MultiPointZM{{1, 2, 3, 4}}.MultiPoint(),
+			expected: geom.MultiPoint{{1, 2}},
+		},
+		"MultiPointZ nil": {
+			got:      geom.MultiPointZ(nil).MultiPoint(),
+			expected: geom.MultiPoint(nil),
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.expected, tc.got) {
+				t.Errorf("%v, expected %v got %v", name, tc.expected, tc.got)
+			}
+		})
+	}
+}
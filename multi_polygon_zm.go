@@ -0,0 +1,96 @@
+package geom
+
+import "errors"
+
+// ErrNilMultiPolygonZ is thrown when a MultiPolygonZ is nil but shouldn't be
+var ErrNilMultiPolygonZ = errors.New("geom: nil MultiPolygonZ")
+
+// MultiPolygonZ is a geometry of multiple PolygonZs.
+type MultiPolygonZ [][][][3]float64
+
+// Polygons returns the array of polygons.
+func (mp MultiPolygonZ) Polygons() [][][][3]float64 { return mp }
+
+// MultiPolygon returns the 2D multipolygon, dropping the z values
+func (mp MultiPolygonZ) MultiPolygon() MultiPolygon {
+	if mp == nil {
+		return nil
+	}
+	mply := make(MultiPolygon, len(mp))
+	for i := range mp {
+		mply[i] = xyOfLines3(mp[i])
+	}
+	return mply
+}
+
+// SetPolygons modifies the array of 3D coordinates
+func (mp *MultiPolygonZ) SetPolygons(input [][][][3]float64) (err error) {
+	if mp == nil {
+		return ErrNilMultiPolygonZ
+	}
+
+	*mp = append((*mp)[:0], input...)
+	return
+}
+
+// ErrNilMultiPolygonM is thrown when a MultiPolygonM is nil but shouldn't be
+var ErrNilMultiPolygonM = errors.New("geom: nil MultiPolygonM")
+
+// MultiPolygonM is a geometry of multiple PolygonMs.
+type MultiPolygonM [][][][3]float64
+
+// Polygons returns the array of polygons.
+func (mp MultiPolygonM) Polygons() [][][][3]float64 { return mp }
+
+// MultiPolygon returns the 2D multipolygon, dropping the m values
+func (mp MultiPolygonM) MultiPolygon() MultiPolygon {
+	if mp == nil {
+		return nil
+	}
+	mply := make(MultiPolygon, len(mp))
+	for i := range mp {
+		mply[i] = xyOfLines3(mp[i])
+	}
+	return mply
+}
+
+// SetPolygons modifies the array of 2D coordinates and measures
+func (mp *MultiPolygonM) SetPolygons(input [][][][3]float64) (err error) {
+	if mp == nil {
+		return ErrNilMultiPolygonM
+	}
+
+	*mp = append((*mp)[:0], input...)
+	return
+}
+
+// ErrNilMultiPolygonZM is thrown when a MultiPolygonZM is nil but shouldn't be
+var ErrNilMultiPolygonZM = errors.New("geom: nil MultiPolygonZM")
+
+// MultiPolygonZM is a geometry of multiple PolygonZMs.
+type MultiPolygonZM [][][][4]float64
+
+// Polygons returns the array of polygons.
+func (mp MultiPolygonZM) Polygons() [][][][4]float64 { return mp }
+
+// MultiPolygon returns the 2D multipolygon, dropping the z and m values
+func (mp MultiPolygonZM) MultiPolygon() MultiPolygon {
+	if mp == nil {
+		return nil
+	}
+	mply := make(MultiPolygon, len(mp))
+	for i := range mp {
+		mply[i] = xyOfLines4(mp[i])
+	}
+	return mply
+}
+
+// SetPolygons modifies the array of 3D coordinates and measures
+func (mp *MultiPolygonZM) SetPolygons(input [][][][4]float64) (err error) {
+	if mp == nil {
+		return ErrNilMultiPolygonZM
+	}
+
+	*mp = append((*mp)[:0], input...)
+	return
+}
@@ -0,0 +1,121 @@
+package geom_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestMultiPolygonZMSetter(t *testing.T) {
+	type tcase struct {
+		set      func() error
+		setter   geom.Geometry
+		expected geom.Geometry
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			err := tc.set()
+			if tc.err == nil && err != nil {
+				t.Errorf("error, expected nil got %v", err)
+				return
+			}
+			if tc.err != nil {
+				if err == nil || tc.err.Error() != err.Error() {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+
+			// compare the results
+			if !reflect.DeepEqual(tc.expected, tc.setter) {
+				t.Errorf("setter, expected %v got %v", tc.expected, tc.setter)
+			}
+		}
+	}
+
+	tests := map[string]func() tcase{
+		"MultiPolygonZ": func() tcase {
+			g := &geom.MultiPolygonZ{}
+			return tcase{
+				set:      func() error { return g.SetPolygons([][][][3]float64{{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}}}) },
+				setter:   g,
+				expected: &geom.MultiPolygonZ{{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}}},
+			}
+		},
+		"nil MultiPolygonZ": func() tcase {
+			var g *geom.MultiPolygonZ
+			return tcase{
+				set: func() error { return g.SetPolygons([][][][3]float64{{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}}}) },
+				err: geom.ErrNilMultiPolygonZ,
+			}
+		},
+		"MultiPolygonM": func() tcase {
+			g := &geom.MultiPolygonM{}
+			return tcase{
+				set:      func() error { return g.SetPolygons([][][][3]float64{{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}}}) },
+				setter:   g,
+				expected: &geom.MultiPolygonM{{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}}},
+			}
+		},
+		"nil MultiPolygonM": func() tcase {
+			var g *geom.MultiPolygonM
+			return tcase{
+				set: func() error { return g.SetPolygons([][][][3]float64{{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}}}) },
+				err: geom.ErrNilMultiPolygonM,
+			}
+		},
+		"MultiPolygonZM": func() tcase {
+			g := &geom.MultiPolygonZM{}
+			return tcase{
+				set:      func() error { return g.SetPolygons([][][][4]float64{{{{0, 0, 1, 1}, {1, 1, 2, 2}, {1, 0, 3, 3}}}}) },
+				setter:   g,
+				expected: &geom.MultiPolygonZM{{{{0, 0, 1, 1}, {1, 1, 2, 2}, {1, 0, 3, 3}}}},
+			}
+		},
+		"nil MultiPolygonZM": func() tcase {
+			var g *geom.MultiPolygonZM
+			return tcase{
+				set: func() error { return g.SetPolygons([][][][4]float64{{{{0, 0, 1, 1}, {1, 1, 2, 2}, {1, 0, 3, 3}}}}) },
+				err: geom.ErrNilMultiPolygonZM,
+			}
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc()))
+	}
+}
+
+func TestMultiPolygonZMProjection(t *testing.T) {
+	type tcase struct {
+		got      geom.MultiPolygon
+		expected geom.MultiPolygon
+	}
+
+	tests := map[string]tcase{
+		"MultiPolygonZ": {
+			got:      geom.MultiPolygonZ{{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}}}.MultiPolygon(),
+			expected: geom.MultiPolygon{{{{0, 0}, {1, 1}, {1, 0}}}},
+		},
+		"MultiPolygonM": {
+			got:      geom.MultiPolygonM{{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}}}.MultiPolygon(),
+			expected: geom.MultiPolygon{{{{0, 0}, {1, 1}, {1, 0}}}},
+		},
+		"MultiPolygonZM": {
+			got:      geom.MultiPolygonZM{{{{0, 0, 1, 1}, {1, 1, 2, 2}, {1, 0, 3, 3}}}}.MultiPolygon(),
+			expected: geom.MultiPolygon{{{{0, 0}, {1, 1}, {1, 0}}}},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.expected, tc.got) {
+				t.Errorf("%v, expected %v got %v", name, tc.expected, tc.got)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package geom
+
+import "sort"
+
+// Normalize returns a copy of the geometry, of the same type, with its
+// points and parts in a canonical order, so that geometries that only
+// differ in the order of their points or parts are the same once
+// normalized; the geometry given is not modified.
+//
+//   - the points of a MultiPoint are sorted by x then y;
+//   - a LineString or Line goes from the smaller of its ends, comparing
+//     the points in order from each end;
+//   - the rings of a Polygon or Triangle start at their smallest point, by
+//     x then y, with the outer ring counter clockwise and the holes
+//     clockwise, with y going up, and the holes sorted. Closed rings are
+//     kept closed;
+//   - the lines of a MultiLineString and the polygons of a MultiPolygon
+//     are sorted, after being normalized;
+//   - the geometries of a Collection are normalized and kept in order.
+//
+// Pointers to geometries are normalized as the geometries they point to,
+// and returned as values. Points and Extents are returned as they are.
+// Geometries with Z or M values are not supported, ErrUnknownGeometry is
+// returned for them, as dropping the values would make geometries that
+// differ in them the same.
+func Normalize(g Geometry) (Geometry, error) {
+	switch gg := g.(type) {
+	case Point, Extent:
+		return gg, nil
+	case *Point:
+		if gg == nil {
+			return gg, nil
+		}
+		return *gg, nil
+	case *Extent:
+		if gg == nil {
+			return gg, nil
+		}
+		return *gg, nil
+	case MultiPoint:
+		mp := append(MultiPoint(nil), gg...)
+		sort.Slice(mp, func(i, j int) bool { return comparePoints(mp[i], mp[j]) < 0 })
+		return mp, nil
+	case *MultiPoint:
+		if gg == nil {
+			return gg, nil
+		}
+		return Normalize(*gg)
+	case Line:
+		if comparePoints(gg[1], gg[0]) < 0 {
+			gg[0], gg[1] = gg[1], gg[0]
+		}
+		return gg, nil
+	case *Line:
+		if gg == nil {
+			return gg, nil
+		}
+		return Normalize(*gg)
+	case LineString:
+		return LineString(normalizeLine(gg)), nil
+	case *LineString:
+		if gg == nil {
+			return gg, nil
+		}
+		return Normalize(*gg)
+	case MultiLineString:
+		mls := make(MultiLineString, len(gg))
+		for i := range gg {
+			mls[i] = normalizeLine(gg[i])
+		}
+		sort.Slice(mls, func(i, j int) bool { return compareLines(mls[i], mls[j]) < 0 })
+		return mls, nil
+	case *MultiLineString:
+		if gg == nil {
+			return gg, nil
+		}
+		return Normalize(*gg)
+	case Triangle:
+		ring := normalizeRing(gg[:], true)
+		return Triangle{ring[0], ring[1], ring[2]}, nil
+	case *Triangle:
+		if gg == nil {
+			return gg, nil
+		}
+		return Normalize(*gg)
+	case Polygon:
+		return Polygon(normalizePolygon(gg)), nil
+	case *Polygon:
+		if gg == nil {
+			return gg, nil
+		}
+		return Normalize(*gg)
+	case MultiPolygon:
+		mp := make(MultiPolygon, len(gg))
+		for i := range gg {
+			mp[i] = normalizePolygon(gg[i])
+		}
+		sort.Slice(mp, func(i, j int) bool { return comparePolygons(mp[i], mp[j]) < 0 })
+		return mp, nil
+	case *MultiPolygon:
+		if gg == nil {
+			return gg, nil
+		}
+		return Normalize(*gg)
+	case Collection:
+		col := make(Collection, len(gg))
+		for i := range gg {
+			var err error
+			if col[i], err = Normalize(gg[i]); err != nil {
+				return nil, err
+			}
+		}
+		return col, nil
+	case *Collection:
+		if gg == nil {
+			return gg, nil
+		}
+		return Normalize(*gg)
+	default:
+		return nil, ErrUnknownGeometry{g}
+	}
+}
+
+// normalizeLine returns a copy of the line going from the smaller of its
+// ends
+func normalizeLine(ln [][2]float64) [][2]float64 {
+	cp := append([][2]float64(nil), ln...)
+	for i, j := 0, len(cp)-1; i < j; i, j = i+1, j-1 {
+		if c := comparePoints(cp[i], cp[j]); c < 0 {
+			break
+		} else if c > 0 {
+			reversePoints(cp)
+			break
+		}
+	}
+	return cp
+}
+
+// normalizePolygon returns a copy of the polygon with normalized rings, and
+// the holes sorted
+func normalizePolygon(ply [][][2]float64) [][][2]float64 {
+	cp := make([][][2]float64, len(ply))
+	for i := range ply {
+		cp[i] = normalizeRing(ply[i], i == 0)
+	}
+	if len(cp) > 1 {
+		holes := cp[1:]
+		sort.Slice(holes, func(i, j int) bool { return compareLines(holes[i], holes[j]) < 0 })
+	}
+	return cp
+}
+
+// normalizeRing returns a copy of the ring starting at its smallest point,
+// counter clockwise with y going up for outer rings and clockwise for
+// holes. If the ring is closed the copy is too.
+func normalizeRing(ring [][2]float64, outer bool) [][2]float64 {
+	closed := len(ring) > 1 && ring[0] == ring[len(ring)-1]
+	if closed {
+		ring = ring[:len(ring)-1]
+	}
+	cp := append([][2]float64(nil), ring...)
+
+	var area float64
+	for i := range cp {
+		j := (i + 1) % len(cp)
+		area += cp[i][0]*cp[j][1] - cp[j][0]*cp[i][1]
+	}
+	if (outer && area < 0) || (!outer && area > 0) {
+		reversePoints(cp)
+	}
+
+	min := 0
+	for i := range cp {
+		if comparePoints(cp[i], cp[min]) < 0 {
+			min = i
+		}
+	}
+	rotated := make([][2]float64, 0, len(cp)+1)
+	rotated = append(append(rotated, cp[min:]...), cp[:min]...)
+	if closed {
+		rotated = append(rotated, rotated[0])
+	}
+	return rotated
+}
+
+func reversePoints(pts [][2]float64) {
+	for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+		pts[i], pts[j] = pts[j], pts[i]
+	}
+}
+
+// comparePoints compares the points by x then y, returning -1, 0 or 1
+func comparePoints(a, b [2]float64) int {
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// compareLines compares the lines point by point, with a shorter line
+// that is the start of a longer one being smaller
+func compareLines(a, b [][2]float64) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := comparePoints(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+// comparePolygons compares the polygons ring by ring
+func comparePolygons(a, b [][][2]float64) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareLines(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+package geom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	type tcase struct {
+		g        Geometry
+		expected Geometry
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Normalize(tc.g)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("geometry, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			g:        &Point{1, 2},
+			expected: Point{1, 2},
+		},
+		"multipoint": {
+			g:        MultiPoint{{2, 1}, {1, 2}, {1, 1}},
+			expected: MultiPoint{{1, 1}, {1, 2}, {2, 1}},
+		},
+		"line": {
+			g:        Line{{2, 2}, {1, 1}},
+			expected: Line{{1, 1}, {2, 2}},
+		},
+		"linestring": {
+			g:        LineString{{2, 0}, {1, 1}, {0, 0}},
+			expected: LineString{{0, 0}, {1, 1}, {2, 0}},
+		},
+		"linestring same ends": {
+			g:        LineString{{0, 0}, {2, 1}, {1, 1}, {0, 0}},
+			expected: LineString{{0, 0}, {1, 1}, {2, 1}, {0, 0}},
+		},
+		"multilinestring": {
+			g:        MultiLineString{{{3, 3}, {2, 2}}, {{1, 1}, {0, 0}}},
+			expected: MultiLineString{{{0, 0}, {1, 1}}, {{2, 2}, {3, 3}}},
+		},
+		"polygon": {
+			g: Polygon{
+				{{10, 10}, {10, 0}, {0, 0}, {0, 10}},
+				{{6, 6}, {6, 8}, {8, 8}, {8, 6}},
+				{{2, 2}, {4, 2}, {4, 4}, {2, 4}},
+			},
+			expected: Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{2, 2}, {2, 4}, {4, 4}, {4, 2}},
+				{{6, 6}, {6, 8}, {8, 8}, {8, 6}},
+			},
+		},
+		"closed polygon": {
+			g:        Polygon{{{1, 1}, {0, 1}, {0, 0}, {1, 0}, {1, 1}}},
+			expected: Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		},
+		"triangle": {
+			g:        Triangle{{1, 1}, {0, 0}, {0, 1}},
+			expected: Triangle{{0, 0}, {1, 1}, {0, 1}},
+		},
+		"multipolygon": {
+			g: MultiPolygon{
+				{{{5, 5}, {6, 5}, {6, 6}}},
+				{{{0, 0}, {1, 0}, {1, 1}}},
+			},
+			expected: MultiPolygon{
+				{{{0, 0}, {1, 0}, {1, 1}}},
+				{{{5, 5}, {6, 5}, {6, 6}}},
+			},
+		},
+		"collection": {
+			g:        Collection{MultiPoint{{1, 1}, {0, 0}}, Point{3, 3}},
+			expected: Collection{MultiPoint{{0, 0}, {1, 1}}, Point{3, 3}},
+		},
+		"unknown": {
+			g:   PointZ{1, 2, 3},
+			err: ErrUnknownGeometry{PointZ{1, 2, 3}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestNormalizeDoesNotModify(t *testing.T) {
+	ply := Polygon{{{1, 1}, {1, 0}, {0, 0}}}
+	if _, err := Normalize(ply); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if exp := (Polygon{{{1, 1}, {1, 0}, {0, 0}}}); !reflect.DeepEqual(ply, exp) {
+		t.Errorf("polygon, expected %v got %v", exp, ply)
+	}
+}
@@ -0,0 +1,301 @@
+// Package antimeridian cuts geometries in longitude and latitude where they
+// cross the antimeridian, and wraps their longitudes into a range.
+//
+// The edges of the geometries are taken to be straight lines in longitude
+// and latitude, as GeoJSON (RFC 7946) takes them, and an edge going more
+// than 180° east or west is taken to go the shorter way round, across the
+// antimeridian.
+package antimeridian
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+)
+
+// ErrInvalidRange is returned when the range of longitudes to wrap to is
+// not 360° wide.
+var ErrInvalidRange = errors.New("antimeridian: range of longitudes is not 360° wide")
+
+var (
+	// Range180 is the range of longitudes from -180 to 180
+	Range180 = [2]float64{-180, 180}
+	// Range360 is the range of longitudes from 0 to 360
+	Range360 = [2]float64{0, 360}
+)
+
+// Wrap returns a copy of the geometry with every longitude moved into the
+// range by adding or taking away multiples of 360. Longitudes already in the
+// range, including its ends, are left as they are. The range must be 360°
+// wide, or ErrInvalidRange is returned.
+//
+// Wrap does not cut the geometry, so lines crossing the ends of the range
+// will go the long way round after it; see Cut.
+func Wrap(g geom.Geometry, lonRange [2]float64) (geom.Geometry, error) {
+	if lonRange[1]-lonRange[0] != 360 {
+		return nil, ErrInvalidRange
+	}
+	return geom.Map(g, func(pt [2]float64) ([2]float64, error) {
+		return [2]float64{wrapLon(pt[0], lonRange), pt[1]}, nil
+	})
+}
+
+// wrapLon returns the longitude moved into the range
+func wrapLon(lon float64, lonRange [2]float64) float64 {
+	if lon >= lonRange[0] && lon <= lonRange[1] {
+		return lon
+	}
+	return lon - 360*math.Floor((lon-lonRange[0])/360)
+}
+
+// Cut returns the geometry cut into parts that do not cross the
+// antimeridian, with longitudes between -180 and 180. Parts that end on
+// the antimeridian end at 180 on the east side of it, and -180 on the west.
+//
+// Points are wrapped. A LineString that crosses the antimeridian becomes a
+// MultiLineString, and a Polygon a MultiPolygon; geometries that do not
+// cross it keep their type. The polygons of the result are those of
+// planar.Intersection, with rings that are not closed. A ring that goes
+// all the way round the world encloses a pole, the north pole if the ring
+// is mostly in the northern hemisphere and the south pole if it is not,
+// and its polygon is extended to the pole. Collections and multi
+// geometries are cut part by part.
+func Cut(g geom.Geometry) (geom.Geometry, error) {
+	switch g := g.(type) {
+	case nil:
+		return nil, nil
+
+	case geom.Point:
+		return geom.Point{wrapLon(g[0], Range180), g[1]}, nil
+
+	case geom.MultiPoint:
+		mp := make(geom.MultiPoint, len(g))
+		for i, pt := range g {
+			mp[i] = [2]float64{wrapLon(pt[0], Range180), pt[1]}
+		}
+		return mp, nil
+
+	case geom.LineString:
+		parts := cutLine(g)
+		if len(parts) == 1 {
+			return geom.LineString(parts[0]), nil
+		}
+		return geom.MultiLineString(parts), nil
+
+	case geom.MultiLineString:
+		var mls geom.MultiLineString
+		for _, ln := range g {
+			mls = append(mls, cutLine(ln)...)
+		}
+		return mls, nil
+
+	case geom.Polygon:
+		mp, err := cutPolygon(g)
+		if err != nil {
+			return nil, err
+		}
+		if len(mp) == 1 {
+			return geom.Polygon(mp[0]), nil
+		}
+		return mp, nil
+
+	case geom.MultiPolygon:
+		var mp geom.MultiPolygon
+		for _, ply := range g {
+			parts, err := cutPolygon(ply)
+			if err != nil {
+				return nil, err
+			}
+			mp = append(mp, parts...)
+		}
+		return mp, nil
+
+	case geom.Collection:
+		col := make(geom.Collection, len(g))
+		for i, gg := range g {
+			c, err := Cut(gg)
+			if err != nil {
+				return nil, err
+			}
+			col[i] = c
+		}
+		return col, nil
+
+	default:
+		return nil, geom.ErrUnknownGeometry{Geom: g}
+	}
+}
+
+// unwrap returns the points with the longitudes changed by multiples of 360
+// so that no two points one after the other are more than 180° apart. The
+// first point keeps its longitude, wrapped.
+func unwrap(pts [][2]float64) [][2]float64 {
+	if len(pts) == 0 {
+		return nil
+	}
+	out := make([][2]float64, len(pts))
+	out[0] = [2]float64{wrapLon(pts[0][0], Range180), pts[0][1]}
+	for i := 1; i < len(pts); i++ {
+		prev := out[i-1][0]
+		lon := pts[i][0] + 360*math.Round((prev-pts[i][0])/360)
+		out[i] = [2]float64{lon, pts[i][1]}
+	}
+	return out
+}
+
+// cutLine returns the parts of the line between the crossings of the
+// antimeridian, moved into the longitudes between -180 and 180.
+func cutLine(ln [][2]float64) [][][2]float64 {
+	pts := unwrap(ln)
+	if len(pts) == 0 {
+		return nil
+	}
+
+	var (
+		parts [][][2]float64
+		part  = [][2]float64{pts[0]}
+		// k is the number of the strip the part is in, the 360° of
+		// longitude around 360*k; the first point is in strip 0
+		k int
+	)
+	// shift moves the points of the part in strip k to between -180 and 180
+	shift := func(part [][2]float64, k int) [][2]float64 {
+		for i := range part {
+			part[i][0] -= float64(360 * k)
+		}
+		return part
+	}
+	for _, pt := range pts[1:] {
+		prev := part[len(part)-1]
+		// points on the edges of the strip are still in it
+		for pt[0] > float64(360*k+180) || pt[0] < float64(360*k-180) {
+			// the meridian the line crosses on its way out of strip k
+			east := pt[0] > prev[0]
+			edge := float64(360*k + 180)
+			if !east {
+				edge = float64(360*k - 180)
+			}
+			lat := prev[1] + (pt[1]-prev[1])*(edge-prev[0])/(pt[0]-prev[0])
+			if prev[0] != edge {
+				part = append(part, [2]float64{edge, lat})
+			}
+			if len(part) > 1 {
+				parts = append(parts, shift(part, k))
+			}
+			if east {
+				k++
+			} else {
+				k--
+			}
+			prev = [2]float64{edge, lat}
+			part = [][2]float64{prev}
+		}
+		part = append(part, pt)
+	}
+	if len(part) > 1 || len(parts) == 0 {
+		parts = append(parts, shift(part, k))
+	}
+	return parts
+}
+
+// cutPolygon returns the parts of the polygon in each 360° strip it covers,
+// moved into the longitudes between -180 and 180.
+func cutPolygon(ply [][][2]float64) (geom.MultiPolygon, error) {
+	if len(ply) == 0 || len(ply[0]) == 0 {
+		return nil, nil
+	}
+
+	var (
+		rings          = make(geom.Polygon, 0, len(ply))
+		minLon, maxLon = math.Inf(1), math.Inf(-1)
+		shellMin       float64
+	)
+	for i, ring := range ply {
+		pts := unwrap(ring)
+		if len(pts) == 0 {
+			continue
+		}
+		if i > 0 {
+			// holes go in the same strips as the shell
+			lo := pts[0][0]
+			for _, pt := range pts {
+				lo = math.Min(lo, pt[0])
+			}
+			d := 360 * math.Floor((lo-shellMin)/360)
+			for j := range pts {
+				pts[j][0] -= d
+			}
+		}
+		pts = closePole(pts)
+		for _, pt := range pts {
+			minLon, maxLon = math.Min(minLon, pt[0]), math.Max(maxLon, pt[0])
+		}
+		if i == 0 {
+			shellMin = minLon
+		}
+		rings = append(rings, pts)
+	}
+
+	// the strips, the 360° of longitude around 360*k, the inside of the
+	// polygon is in
+	first := int(math.Floor((minLon-180)/360)) + 1
+	last := int(math.Ceil((maxLon+180)/360)) - 1
+	if last < first {
+		last = first
+	}
+	if first == last {
+		// the polygon is in one strip, so it only needs moving
+		for _, ring := range rings {
+			for j := range ring {
+				ring[j][0] -= float64(360 * first)
+			}
+		}
+		return geom.MultiPolygon{rings}, nil
+	}
+
+	var mp geom.MultiPolygon
+	for k := first; k <= last; k++ {
+		west, east := float64(360*k-180), float64(360*k+180)
+		box := geom.Polygon{{{west, -90}, {east, -90}, {east, 90}, {west, 90}}}
+		parts, err := planar.Intersection(context.Background(), rings, box)
+		if err != nil {
+			return nil, err
+		}
+		for _, part := range parts {
+			for _, ring := range part {
+				for j := range ring {
+					ring[j][0] -= float64(360 * k)
+				}
+			}
+			mp = append(mp, part)
+		}
+	}
+	return mp, nil
+}
+
+// closePole returns the ring, unwrapped, closed through a pole if it goes
+// all the way round the world: the ring ends 360° east or west of where it
+// starts, and is closed along the edge of the map at the pole.
+func closePole(pts [][2]float64) [][2]float64 {
+	first, last := pts[0], pts[len(pts)-1]
+	// the edge closing the ring, back to the first point
+	end := first[0] + 360*math.Round((last[0]-first[0])/360)
+	if end == first[0] {
+		return pts
+	}
+	if end != last[0] || first[1] != last[1] {
+		pts = append(pts, [2]float64{end, first[1]})
+	}
+	var sum float64
+	for _, pt := range pts {
+		sum += pt[1]
+	}
+	pole := 90.0
+	if sum < 0 {
+		pole = -90
+	}
+	return append(pts, [2]float64{end, pole}, [2]float64{first[0], pole})
+}
@@ -0,0 +1,238 @@
+package antimeridian
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+)
+
+func TestWrap(t *testing.T) {
+	type tcase struct {
+		g        geom.Geometry
+		lonRange [2]float64
+		expected geom.Geometry
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Wrap(tc.g, tc.lonRange)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("geometry, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"in range": {
+			g:        geom.LineString{{-180, 0}, {10, 5}, {180, 10}},
+			lonRange: Range180,
+			expected: geom.LineString{{-180, 0}, {10, 5}, {180, 10}},
+		},
+		"out of range": {
+			g:        geom.MultiPoint{{190, 1}, {-190, 2}, {540, 3}, {-900, 4}},
+			lonRange: Range180,
+			expected: geom.MultiPoint{{-170, 1}, {170, 2}, {-180, 3}, {-180, 4}},
+		},
+		"to 360": {
+			g:        geom.Polygon{{{-10, 0}, {10, 0}, {10, 10}, {-10, 10}}},
+			lonRange: Range360,
+			expected: geom.Polygon{{{350, 0}, {10, 0}, {10, 10}, {350, 10}}},
+		},
+		"bad range": {
+			g:        geom.Point{0, 0},
+			lonRange: [2]float64{-90, 90},
+			err:      ErrInvalidRange,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestCutLine(t *testing.T) {
+	type tcase struct {
+		g        geom.Geometry
+		expected geom.Geometry
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Cut(tc.g)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("geometry, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"short way round": {
+			g:        geom.LineString{{170, 0}, {-170, 0}, {-160, 10}},
+			expected: geom.MultiLineString{{{170, 0}, {180, 0}}, {{-180, 0}, {-170, 0}, {-160, 10}}},
+		},
+		"crossing": {
+			g:        geom.LineString{{170, 0}, {-170, 10}},
+			expected: geom.MultiLineString{{{170, 0}, {180, 5}}, {{-180, 5}, {-170, 10}}},
+		},
+		"crossing west": {
+			g:        geom.LineString{{-175, 0}, {175, 10}, {170, 10}},
+			expected: geom.MultiLineString{{{-175, 0}, {-180, 5}}, {{180, 5}, {175, 10}, {170, 10}}},
+		},
+		"inside": {
+			g:        geom.LineString{{10, 0}, {20, 0}, {200, 0}},
+			expected: geom.LineString{{10, 0}, {20, 0}, {-160, 0}},
+		},
+		"on the antimeridian": {
+			g:        geom.LineString{{170, 0}, {180, 0}, {-170, 0}},
+			expected: geom.MultiLineString{{{170, 0}, {180, 0}}, {{-180, 0}, {-170, 0}}},
+		},
+		"point": {
+			g:        geom.Point{190, 5},
+			expected: geom.Point{-170, 5},
+		},
+		"collection": {
+			g: geom.Collection{
+				geom.MultiLineString{{{0, 0}, {10, 0}}, {{179, 0}, {-179, 2}}},
+			},
+			expected: geom.Collection{
+				geom.MultiLineString{{{0, 0}, {10, 0}}, {{179, 0}, {180, 1}}, {{-180, 1}, {-179, 2}}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestCutPolygon(t *testing.T) {
+	type tcase struct {
+		g geom.Geometry
+		// parts is the number of polygons of the result
+		parts int
+		area  float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Cut(tc.g)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			var mp geom.MultiPolygon
+			switch g := got.(type) {
+			case geom.Polygon:
+				mp = geom.MultiPolygon{g}
+			case geom.MultiPolygon:
+				mp = g
+			default:
+				t.Fatalf("type, expected polygon got %T", got)
+			}
+			if len(mp) != tc.parts {
+				t.Fatalf("parts, expected %v got %v: %v", tc.parts, len(mp), mp)
+			}
+			var area float64
+			for _, ply := range mp {
+				for _, ring := range ply {
+					for _, pt := range ring {
+						if pt[0] < -180 || pt[0] > 180 {
+							t.Errorf("longitude, expected between -180 and 180 got %v", pt)
+						}
+					}
+				}
+				report, err := planar.Validate(geom.Polygon(ply))
+				if err != nil {
+					t.Fatalf("validate error, expected nil got %v", err)
+				}
+				if !report.Valid() {
+					t.Errorf("polygon %v, expected valid got %v", ply, report)
+				}
+				area += polygonArea(ply)
+			}
+			if math.Abs(area-tc.area) > 1e-9 {
+				t.Errorf("area, expected %v got %v", tc.area, area)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"not crossing": {
+			g:     geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			parts: 1,
+			area:  100,
+		},
+		"crossing": {
+			g:     geom.Polygon{{{170, 0}, {-170, 0}, {-170, 10}, {170, 10}}},
+			parts: 2,
+			area:  200,
+		},
+		"crossing with hole": {
+			g: geom.Polygon{
+				{{170, 0}, {-170, 0}, {-170, 10}, {170, 10}},
+				{{178, 2}, {178, 8}, {-178, 8}, {-178, 2}},
+			},
+			parts: 2,
+			area:  200 - 24,
+		},
+		"hole east of the antimeridian": {
+			g: geom.Polygon{
+				{{170, 0}, {-170, 0}, {-170, 10}, {170, 10}},
+				{{-178, 2}, {-178, 8}, {-175, 8}, {-175, 2}},
+			},
+			parts: 2,
+			area:  200 - 18,
+		},
+		"around the north pole": {
+			g:     geom.Polygon{{{-180, 80}, {-60, 80}, {60, 80}, {180, 80}}},
+			parts: 1,
+			area:  360 * 10,
+		},
+		"around the south pole": {
+			// starting at 0, the ring is cut at the antimeridian
+			g:     geom.Polygon{{{0, -70}, {120, -70}, {-120, -70}}},
+			parts: 2,
+			area:  360 * 20,
+		},
+		"multipolygon": {
+			g: geom.MultiPolygon{
+				{{{170, 0}, {-170, 0}, {-170, 10}, {170, 10}}},
+				{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			},
+			parts: 3,
+			area:  300,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+// polygonArea returns the area of the polygon, whichever way its rings
+// are wound
+func polygonArea(ply [][][2]float64) float64 {
+	var area float64
+	for i, ring := range ply {
+		var a float64
+		for j := range ring {
+			p, q := ring[j], ring[(j+1)%len(ring)]
+			a += p[0]*q[1] - q[0]*p[1]
+		}
+		a = math.Abs(a / 2)
+		if i > 0 {
+			a = -a
+		}
+		area += a
+	}
+	return area
+}
@@ -0,0 +1,160 @@
+package planar
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// ErrInvalidBufferDistance is returned when the buffer distance is NaN or
+// infinite.
+var ErrInvalidBufferDistance = errors.New("planar: invalid buffer distance")
+
+// JoinStyle is how the offset lines of two segments are joined at an
+// outer corner.
+type JoinStyle uint8
+
+const (
+	// JoinRound joins the segments with an arc
+	JoinRound JoinStyle = iota
+	// JoinMiter extends the offset lines until they meet, unless that is
+	// further than the miter limit, in which case it is beveled
+	JoinMiter
+	// JoinBevel joins the ends of the offset lines with a line
+	JoinBevel
+)
+
+// CapStyle is how the ends of a line, and points, are buffered.
+type CapStyle uint8
+
+const (
+	// CapRound ends the line with a half circle
+	CapRound CapStyle = iota
+	// CapFlat ends the line at the end point, points have no buffer
+	CapFlat
+	// CapSquare ends the line with a half square
+	CapSquare
+)
+
+const (
+	// DefaultBufferQuadrantSegments is the number of segments used to
+	// approximate a quarter circle
+	DefaultBufferQuadrantSegments = 8
+	// DefaultBufferMiterLimit is the ratio of the miter length to the distance
+	// above which a miter join is beveled
+	DefaultBufferMiterLimit = 5.0
+)
+
+type bufferOptions struct {
+	join             JoinStyle
+	cap              CapStyle
+	quadrantSegments int
+	miterLimit       float64
+}
+
+// BufferOption changes how the buffer is built.
+type BufferOption func(*bufferOptions)
+
+// WithJoinStyle sets the join style, the default is JoinRound.
+func WithJoinStyle(join JoinStyle) BufferOption {
+	return func(o *bufferOptions) { o.join = join }
+}
+
+// WithCapStyle sets the end cap style, the default is CapRound.
+func WithCapStyle(cap CapStyle) BufferOption {
+	return func(o *bufferOptions) { o.cap = cap }
+}
+
+// WithQuadrantSegments sets the number of segments used to approximate a
+// quarter circle. Values less than one are ignored.
+func WithQuadrantSegments(n int) BufferOption {
+	return func(o *bufferOptions) {
+		if n > 0 {
+			o.quadrantSegments = n
+		}
+	}
+}
+
+// WithMiterLimit sets the miter limit. Values less than one are ignored.
+func WithMiterLimit(limit float64) BufferOption {
+	return func(o *bufferOptions) {
+		if limit >= 1 {
+			o.miterLimit = limit
+		}
+	}
+}
+
+// Buffer returns the area within distance of the geometry. Points, lines
+// and polygons, and collections of them, are supported.
+//
+// The buffer is built as the union of simple pieces: the polygons of the
+// geometry, a rectangle along each segment, and a piece for each join and
+// end cap. The edges of the pieces are split where they cross, and the
+// edges with the buffer on only one side are linked into rings.
+//
+// A negative distance erodes the polygons of the geometry; points and lines
+// have no area to erode so do not contribute to the result. A distance of
+// zero returns the area of the polygons. Points with NaN or infinite
+// coordinates, such as the empty point, are left out, and an empty
+// geometry has an empty buffer. The rings of the result are wound and
+// rounded as the Options of the context say.
+func Buffer(ctx context.Context, g geom.Geometry, distance float64, opts ...BufferOption) (geom.MultiPolygon, error) {
+	o := bufferOptions{
+		join:             JoinRound,
+		cap:              CapRound,
+		quadrantSegments: DefaultBufferQuadrantSegments,
+		miterLimit:       DefaultBufferMiterLimit,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if math.IsNaN(distance) || math.IsInf(distance, 0) {
+		return nil, ErrInvalidBufferDistance
+	}
+
+	b := bufferBuilder{
+		bufferOptions: o,
+		distance:      math.Abs(distance),
+		erode:         distance < 0,
+	}
+	if err := b.add(g); err != nil {
+		return nil, err
+	}
+	return b.multiPolygon(ctx)
+}
+
+// bufferBuilder collects the pieces of a buffer
+type bufferBuilder struct {
+	bufferOptions
+
+	distance float64
+	erode    bool
+
+	// polygons of the geometry
+	polygons []piece
+	// pieces along the boundaries, joins and caps
+	pieces []piece
+	// the edges of the pieces and polygons
+	segments []geom.Line
+}
+
+// covers reports whether the points just off pt, in the direction dir,
+// are in the buffer
+func (b *bufferBuilder) covers(pt, dir [2]float64, tol float64) bool {
+	inPolygon := coveredBy(b.polygons, pt, dir, tol)
+	if b.erode {
+		return inPolygon && !coveredBy(b.pieces, pt, dir, tol)
+	}
+	return inPolygon || coveredBy(b.pieces, pt, dir, tol)
+}
+
+func (b *bufferBuilder) multiPolygon(ctx context.Context) (geom.MultiPolygon, error) {
+	if len(b.segments) == 0 || (b.erode && len(b.polygons) == 0) {
+		// nothing to buffer, such as an empty geometry
+		return nil, nil
+	}
+	return buildPolygons(ctx, b.segments, FromContext(ctx).PrecisionModel(), b.covers)
+}
@@ -0,0 +1,279 @@
+package planar
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// add adds the pieces for the geometry
+func (b *bufferBuilder) add(g geom.Geometry) error {
+	switch gg := g.(type) {
+
+	case geom.Collectioner:
+		for _, g := range gg.Geometries() {
+			if err := b.add(g); err != nil {
+				return err
+			}
+		}
+
+	case geom.MultiPolygoner:
+		for _, ply := range gg.Polygons() {
+			b.addPolygon(ply)
+		}
+
+	case geom.Polygoner:
+		b.addPolygon(gg.LinearRings())
+
+	case geom.MultiLineStringer:
+		for _, ln := range gg.LineStrings() {
+			b.addLine(ln, false)
+		}
+
+	case geom.LineStringer:
+		b.addLine(gg.Vertices(), false)
+
+	case geom.MultiPointer:
+		for _, pt := range gg.Points() {
+			b.addPoint(pt)
+		}
+
+	case geom.Pointer:
+		b.addPoint(gg.XY())
+
+	default:
+		return geom.ErrUnknownGeometry{Geom: g}
+	}
+	return nil
+}
+
+// addRing adds the ring as a piece and its edges to the segments
+func (b *bufferBuilder) addRing(ring [][2]float64) convexPiece {
+	c := newConvexPiece(ring...)
+	b.addSegments(ring, true)
+	return c
+}
+
+func (b *bufferBuilder) addSegments(pts [][2]float64, closed bool) {
+	for i := 0; i < len(pts)-1; i++ {
+		b.segments = append(b.segments, geom.Line{pts[i], pts[i+1]})
+	}
+	if closed && len(pts) > 2 {
+		b.segments = append(b.segments, geom.Line{pts[len(pts)-1], pts[0]})
+	}
+}
+
+func (b *bufferBuilder) addPolygon(rings [][][2]float64) {
+	var ply polygonPiece
+	for _, ring := range rings {
+		ring = dedupPoints(finitePoints(ring), true)
+		if len(ring) < 3 {
+			continue
+		}
+		ply.rings = append(ply.rings, ring)
+		b.addSegments(ring, true)
+		b.addLine(ring, true)
+	}
+	if len(ply.rings) == 0 {
+		return
+	}
+	// the extent of all the rings, as the rings are covered even-odd and
+	// the holes of invalid polygons may be outside of the shell
+	ply.ext = geom.NewExtent(ply.rings[0]...)
+	for _, ring := range ply.rings[1:] {
+		ply.ext.AddPoints(ring...)
+	}
+	b.polygons = append(b.polygons, ply)
+}
+
+func (b *bufferBuilder) addPoint(pt [2]float64) {
+	if b.distance == 0 || b.erode || !isFinite(pt) {
+		return
+	}
+	switch b.cap {
+	case CapRound:
+		b.pieces = append(b.pieces, b.addRing(b.circle(pt)))
+	case CapSquare:
+		d := b.distance
+		b.pieces = append(b.pieces, b.addRing([][2]float64{
+			{pt[0] - d, pt[1] - d},
+			{pt[0] + d, pt[1] - d},
+			{pt[0] + d, pt[1] + d},
+			{pt[0] - d, pt[1] + d},
+		}))
+	}
+}
+
+// addLine adds the pieces along the line. If the line is closed the
+// first and last points are joined, otherwise the ends are capped.
+func (b *bufferBuilder) addLine(pts [][2]float64, closed bool) {
+	if b.distance == 0 {
+		return
+	}
+	pts = dedupPoints(finitePoints(pts), closed)
+	if len(pts) == 1 {
+		b.addPoint(pts[0])
+		return
+	}
+	if len(pts) < 2 {
+		return
+	}
+	if closed && len(pts) < 3 {
+		closed = false
+	}
+	if !closed && b.erode {
+		// lines have no area to erode
+		return
+	}
+
+	n := len(pts)
+	for i := 0; i < n-1; i++ {
+		b.addSegmentPiece(pts[i], pts[i+1])
+	}
+	if closed {
+		b.addSegmentPiece(pts[n-1], pts[0])
+		for i := range pts {
+			b.addJoin(pts[(i+n-1)%n], pts[i], pts[(i+1)%n])
+		}
+		return
+	}
+	for i := 1; i < n-1; i++ {
+		b.addJoin(pts[i-1], pts[i], pts[i+1])
+	}
+	b.addCap(pts[1], pts[0], false)
+	b.addCap(pts[n-2], pts[n-1], false)
+}
+
+// leftNormal returns the unit normal, to the left, of the segment a, b
+func leftNormal(a, b [2]float64) [2]float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	l := math.Hypot(dx, dy)
+	return [2]float64{-dy / l, dx / l}
+}
+
+func offsetPoint(pt, n [2]float64, d float64) [2]float64 {
+	return [2]float64{pt[0] + n[0]*d, pt[1] + n[1]*d}
+}
+
+// addSegmentPiece adds the rectangle along the segment
+func (b *bufferBuilder) addSegmentPiece(p1, p2 [2]float64) {
+	n, d := leftNormal(p1, p2), b.distance
+	b.pieces = append(b.pieces, b.addRing([][2]float64{
+		offsetPoint(p1, n, d),
+		offsetPoint(p1, n, -d),
+		offsetPoint(p2, n, -d),
+		offsetPoint(p2, n, d),
+	}))
+}
+
+// addJoin adds the join at the corner pt, between the segments from prev
+// and to next
+func (b *bufferBuilder) addJoin(prev, pt, next [2]float64) {
+	n1, n2 := leftNormal(prev, pt), leftNormal(pt, next)
+	cross := (pt[0]-prev[0])*(next[1]-pt[1]) - (pt[1]-prev[1])*(next[0]-pt[0])
+	dot := n1[0]*n2[0] + n1[1]*n2[1]
+	if cross == 0 && dot > 0 {
+		// the segments are in a straight line
+		return
+	}
+
+	if cross == 0 {
+		// the line turns back on itself, there is no outer side
+		if b.join == JoinRound {
+			b.addCap(prev, pt, true)
+		}
+		return
+	}
+
+	// The outer side of a left turn is on the right.
+	d := b.distance
+	if cross > 0 {
+		d = -d
+	}
+	o1, o2 := offsetPoint(pt, n1, d), offsetPoint(pt, n2, d)
+
+	if b.join == JoinRound {
+		// the normals turn the same way as the line
+		sweep := math.Atan2(n1[0]*n2[1]-n1[1]*n2[0], n1[0]*n2[0]+n1[1]*n2[1])
+		b.pieces = append(b.pieces, b.addRing(append([][2]float64{pt}, b.arc(pt, o1, o2, sweep)...)))
+		return
+	}
+	if b.join == JoinMiter {
+		// the miter point is along the bisector of the normals
+		bx, by := n1[0]+n2[0], n1[1]+n2[1]
+		bl := math.Hypot(bx, by)
+		// ratio of the miter length to the distance
+		ratio := 2 / bl
+		if bl != 0 && ratio <= b.miterLimit {
+			m := offsetPoint(pt, [2]float64{bx / bl, by / bl}, d*ratio)
+			b.pieces = append(b.pieces, b.addRing([][2]float64{pt, o1, m, o2}))
+			return
+		}
+	}
+	b.pieces = append(b.pieces, b.addRing([][2]float64{pt, o1, o2}))
+}
+
+// addCap adds the cap at end of the segment from prev. If round is true
+// a round cap is added whatever the cap style is.
+func (b *bufferBuilder) addCap(prev, end [2]float64, round bool) {
+	n, d := leftNormal(prev, end), b.distance
+	switch {
+	case round || b.cap == CapRound:
+		// the half circle from the left side round to the right
+		b.pieces = append(b.pieces, b.addRing(b.arc(end, offsetPoint(end, n, d), offsetPoint(end, n, -d), -math.Pi)))
+	case b.cap == CapSquare:
+		// the direction of the segment
+		t := [2]float64{n[1], -n[0]}
+		b.pieces = append(b.pieces, b.addRing([][2]float64{
+			offsetPoint(end, n, d),
+			offsetPoint(end, n, -d),
+			offsetPoint(offsetPoint(end, n, -d), t, d),
+			offsetPoint(offsetPoint(end, n, d), t, d),
+		}))
+	}
+}
+
+// arc returns the points on the arc around center, from the point from
+// turning by sweep radians to the point to. The ends of the arc are from and
+// to so that they are exactly the same as the points of the other pieces.
+func (b *bufferBuilder) arc(center, from, to [2]float64, sweep float64) [][2]float64 {
+	step := math.Pi / 2 / float64(b.quadrantSegments)
+	n := int(math.Ceil(math.Abs(sweep)/step - nodingTolerance))
+	if n < 1 {
+		n = 1
+	}
+	start := math.Atan2(from[1]-center[1], from[0]-center[0])
+	pts := make([][2]float64, 0, n+1)
+	pts = append(pts, from)
+	for i := 1; i < n; i++ {
+		a := start + sweep*float64(i)/float64(n)
+		pts = append(pts, [2]float64{
+			center[0] + b.distance*math.Cos(a),
+			center[1] + b.distance*math.Sin(a),
+		})
+	}
+	return append(pts, to)
+}
+
+// circle returns the polygon approximating the circle of the buffer
+// distance around the point
+func (b *bufferBuilder) circle(pt [2]float64) [][2]float64 {
+	c := geom.Circle{Center: pt, Radius: b.distance}
+	return c.AsPolygon(4 * b.quadrantSegments)[0]
+}
+
+// dedup removes repeated points, and the last point of a closed line if it
+// is the same as the first.
+func dedupPoints(pts [][2]float64, closed bool) [][2]float64 {
+	ret := make([][2]float64, 0, len(pts))
+	for i, pt := range pts {
+		if i > 0 && pt == ret[len(ret)-1] {
+			continue
+		}
+		ret = append(ret, pt)
+	}
+	if closed && len(ret) > 1 && ret[0] == ret[len(ret)-1] {
+		ret = ret[:len(ret)-1]
+	}
+	return ret
+}
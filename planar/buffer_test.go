@@ -0,0 +1,252 @@
+package planar
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func testRingArea(ring [][2]float64) float64 {
+	var a float64
+	for i := range ring {
+		j := (i + 1) % len(ring)
+		a += ring[i][0]*ring[j][1] - ring[j][0]*ring[i][1]
+	}
+	return math.Abs(a / 2)
+}
+
+func testArea(mply geom.MultiPolygon) float64 {
+	var a float64
+	for _, ply := range mply {
+		for i, ring := range ply {
+			if i == 0 {
+				a += testRingArea(ring)
+				continue
+			}
+			a -= testRingArea(ring)
+		}
+	}
+	return a
+}
+
+func TestBuffer(t *testing.T) {
+	type tcase struct {
+		geom     geom.Geometry
+		distance float64
+		opts     []BufferOption
+		area     float64
+		// polygons is the expected number of polygons
+		polygons int
+		err      error
+	}
+
+	// area of the circle with a radius of 1 approximated with the default
+	// number of segments
+	n := 4 * DefaultBufferQuadrantSegments
+	circle := float64(n) / 2 * math.Sin(2*math.Pi/float64(n))
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Buffer(context.Background(), tc.geom, tc.distance, tc.opts...)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if len(got) != tc.polygons {
+				t.Errorf("polygons, expected %v got %v", tc.polygons, len(got))
+			}
+			if a := testArea(got); math.Abs(a-tc.area) > 1e-3 {
+				t.Errorf("area, expected %v got %v", tc.area, a)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			geom:     geom.Point{5, 5},
+			distance: 1,
+			area:     circle,
+			polygons: 1,
+		},
+		"point square": {
+			geom:     geom.Point{5, 5},
+			distance: 1,
+			opts:     []BufferOption{WithCapStyle(CapSquare)},
+			area:     4,
+			polygons: 1,
+		},
+		"point flat": {
+			geom:     geom.Point{5, 5},
+			distance: 1,
+			opts:     []BufferOption{WithCapStyle(CapFlat)},
+		},
+		"multipoint": {
+			geom:     geom.MultiPoint{{0, 0}, {10, 0}},
+			distance: 1,
+			area:     2 * circle,
+			polygons: 2,
+		},
+		"line flat": {
+			geom:     geom.LineString{{0, 0}, {10, 0}},
+			distance: 1,
+			opts:     []BufferOption{WithCapStyle(CapFlat)},
+			area:     20,
+			polygons: 1,
+		},
+		"line square": {
+			geom:     geom.LineString{{0, 0}, {10, 0}},
+			distance: 1,
+			opts:     []BufferOption{WithCapStyle(CapSquare)},
+			area:     24,
+			polygons: 1,
+		},
+		"line round": {
+			geom:     geom.LineString{{0, 0}, {10, 0}},
+			distance: 1,
+			area:     20 + circle,
+			polygons: 1,
+		},
+		"corner miter": {
+			geom:     geom.LineString{{0, 0}, {10, 0}, {10, 10}},
+			distance: 1,
+			opts:     []BufferOption{WithCapStyle(CapFlat), WithJoinStyle(JoinMiter)},
+			area:     40,
+			polygons: 1,
+		},
+		"corner bevel": {
+			geom:     geom.LineString{{0, 0}, {10, 0}, {10, 10}},
+			distance: 1,
+			opts:     []BufferOption{WithCapStyle(CapFlat), WithJoinStyle(JoinBevel)},
+			area:     39.5,
+			polygons: 1,
+		},
+		"corner miter limit": {
+			geom:     geom.LineString{{0, 0}, {10, 0}, {10, 10}},
+			distance: 1,
+			opts:     []BufferOption{WithCapStyle(CapFlat), WithJoinStyle(JoinMiter), WithMiterLimit(1.2)},
+			area:     39.5,
+			polygons: 1,
+		},
+		"square miter": {
+			geom:     geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			distance: 1,
+			opts:     []BufferOption{WithJoinStyle(JoinMiter)},
+			area:     144,
+			polygons: 1,
+		},
+		"square bevel": {
+			geom:     geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			distance: 1,
+			opts:     []BufferOption{WithJoinStyle(JoinBevel)},
+			area:     142,
+			polygons: 1,
+		},
+		"square erode": {
+			geom:     geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			distance: -1,
+			area:     64,
+			polygons: 1,
+		},
+		"square erode away": {
+			geom:     geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			distance: -6,
+		},
+		"hole erode miter": {
+			geom: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{4, 4}, {4, 6}, {6, 6}, {6, 4}},
+			},
+			distance: -1,
+			opts:     []BufferOption{WithJoinStyle(JoinMiter)},
+			area:     48,
+			polygons: 1,
+		},
+		"square zero": {
+			geom:     geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			distance: 0,
+			area:     100,
+			polygons: 1,
+		},
+		"line zero": {
+			geom:     geom.LineString{{0, 0}, {10, 0}},
+			distance: 0,
+		},
+		"line erode": {
+			geom:     geom.LineString{{0, 0}, {10, 0}},
+			distance: -1,
+		},
+		"collection": {
+			geom: geom.Collection{
+				geom.Point{20, 20},
+				geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			},
+			distance: 1,
+			opts:     []BufferOption{WithJoinStyle(JoinMiter)},
+			area:     144 + circle,
+			polygons: 2,
+		},
+		"nan": {
+			geom:     geom.Point{0, 0},
+			distance: math.NaN(),
+			err:      ErrInvalidBufferDistance,
+		},
+		"empty point": {
+			geom:     geom.Point{math.NaN(), math.NaN()},
+			distance: 1,
+		},
+		"empty collection": {
+			geom:     geom.Collection{},
+			distance: 1,
+		},
+		"multipoint with empty point": {
+			geom:     geom.MultiPoint{{math.NaN(), math.NaN()}, {5, 5}},
+			distance: 1,
+			area:     circle,
+			polygons: 1,
+		},
+		"line with infinite vertex": {
+			geom:     geom.LineString{{0, 0}, {math.Inf(1), 0}, {10, 0}},
+			distance: 1,
+			opts:     []BufferOption{WithCapStyle(CapFlat)},
+			area:     20,
+			polygons: 1,
+		},
+		"invalid hole outside of shell": {
+			// the rings are covered even-odd, so the hole is an area of
+			// its own, as it is for Overlay
+			geom: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{20, 0}, {20, 10}, {30, 10}, {30, 0}},
+			},
+			distance: 0,
+			area:     200,
+			polygons: 2,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestBufferLine(t *testing.T) {
+	// a long line with many small turns, the buffers of the segments
+	// overlap each other at narrow angles
+	var ls geom.LineString
+	for i := 0; i < 300; i++ {
+		ls = append(ls, [2]float64{float64(i * 3), 10 * math.Sin(float64(i))})
+	}
+
+	for _, join := range []JoinStyle{JoinRound, JoinMiter, JoinBevel} {
+		for _, d := range []float64{0.5, 2, 7} {
+			got, err := Buffer(context.Background(), ls, d, WithJoinStyle(join))
+			if err != nil {
+				t.Fatalf("join %v distance %v: error, expected nil got %v", join, d, err)
+			}
+			if len(got) != 1 || len(got[0]) != 1 {
+				t.Errorf("join %v distance %v: expected one polygon without holes got %v polygons", join, d, len(got))
+			}
+		}
+	}
+}
@@ -1,5 +1,12 @@
 package planar
 
+import (
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+)
+
 // PointsCentriod returns the center of the given pts
 func PointsCentriod(pts ...[2]float64) (center [2]float64) {
 	if len(pts) == 0 {
@@ -20,3 +27,195 @@ func PointsCentriod(pts ...[2]float64) (center [2]float64) {
 	cy = cy / (3 * aa)
 	return [2]float64{cx, cy}
 }
+
+// Centroid returns the center of mass of the geometry, of its parts of
+// the highest dimension: the center of the area of its polygons, weighted
+// by area, with holes taking away from it; or if it has none, the center
+// of its lines, weighted by length; or if it has none, the average of its
+// points. Polygons with no area are taken as lines, and lines with no
+// length as points. The centroid of a shape that is not convex may be
+// outside it; see PointOnSurface.
+//
+// Points, lines, polygons, their Multi forms and collections of them are
+// supported; z and m values are ignored. ErrEmptyGeometry is returned if
+// the geometry has no points.
+func Centroid(g geom.Geometry) (geom.Point, error) {
+	var parts geomParts
+	if err := parts.add(g); err != nil {
+		return geom.Point{}, err
+	}
+	pts := parts.vertices()
+	if len(pts) == 0 {
+		return geom.Point{}, ErrEmptyGeometry
+	}
+	// the sums are of points relative to the first, for precision
+	origin := pts[0]
+	rel := func(pt [2]float64) [2]float64 { return vsub(pt, origin) }
+	at := func(sx, sy, w float64) geom.Point {
+		return geom.Point{origin[0] + sx/w, origin[1] + sy/w}
+	}
+
+	// the area of each ring, with the sign of the outer ring of its polygon
+	var area, ax, ay float64
+	for _, ply := range parts.polys {
+		var sign float64
+		for i, ring := range ply {
+			var a, cx, cy float64
+			for j := range ring {
+				p, q := rel(ring[j]), rel(ring[(j+1)%len(ring)])
+				c := vcross(p, q)
+				a += c
+				cx += (p[0] + q[0]) * c
+				cy += (p[1] + q[1]) * c
+			}
+			if i == 0 {
+				sign = 1
+				if a < 0 {
+					sign = -1
+				}
+			} else if a*sign > 0 {
+				// a hole wound the same way as its outer ring
+				a, cx, cy = -a, -cx, -cy
+			}
+			area += sign * a / 2
+			ax += sign * cx / 6
+			ay += sign * cy / 6
+		}
+	}
+	if area != 0 {
+		return at(ax, ay, area), nil
+	}
+
+	// the rings, if there was no area, and the lines
+	lines := parts.lines
+	for _, ply := range parts.polys {
+		for _, ring := range ply {
+			lines = append(lines, append(append([][2]float64(nil), ring...), ring[0]))
+		}
+	}
+	var length, lx, ly float64
+	for _, ln := range lines {
+		for i := 1; i < len(ln); i++ {
+			p, q := rel(ln[i-1]), rel(ln[i])
+			l := math.Hypot(q[0]-p[0], q[1]-p[1])
+			length += l
+			lx += l * (p[0] + q[0]) / 2
+			ly += l * (p[1] + q[1]) / 2
+		}
+	}
+	if length != 0 {
+		return at(lx, ly, length), nil
+	}
+
+	var sx, sy float64
+	for _, pt := range pts {
+		p := rel(pt)
+		sx, sy = sx+p[0], sy+p[1]
+	}
+	return at(sx, sy, float64(len(pts))), nil
+}
+
+// PointOnSurface returns a point that is on the geometry, of its parts of
+// the highest dimension, to use as an anchor for a label. For polygons it
+// is inside one of them, not on its boundary: the middle of the widest
+// part of a polygon along a horizontal line through the middle of its
+// extent. For lines it is the vertex nearest the centroid, that is not an
+// end of a line if there are such vertices; and for points it is the
+// point nearest the centroid.
+//
+// Points, lines, polygons, their Multi forms and collections of them are
+// supported; z and m values are ignored. ErrEmptyGeometry is returned if
+// the geometry has no points.
+func PointOnSurface(g geom.Geometry) (geom.Point, error) {
+	var parts geomParts
+	if err := parts.add(g); err != nil {
+		return geom.Point{}, err
+	}
+	c, err := Centroid(g)
+	if err != nil {
+		return c, err
+	}
+
+	widest := -1.0
+	var best [2]float64
+	for _, ply := range parts.polys {
+		if pt, width, ok := scanInterior(ply); ok && width > widest {
+			best, widest = pt, width
+		}
+	}
+	if widest >= 0 {
+		return geom.Point(best), nil
+	}
+
+	nearest := func(pts [][2]float64) ([2]float64, bool) {
+		var (
+			near [2]float64
+			min  = math.Inf(1)
+		)
+		for _, pt := range pts {
+			if d := math.Hypot(pt[0]-c[0], pt[1]-c[1]); d < min {
+				near, min = pt, d
+			}
+		}
+		return near, len(pts) > 0
+	}
+
+	var inner, ends [][2]float64
+	for _, ln := range parts.lines {
+		inner = append(inner, ln[1:len(ln)-1]...)
+		ends = append(ends, ln[0], ln[len(ln)-1])
+	}
+	for _, ply := range parts.polys {
+		// polygons with no area
+		inner = append(inner, ply[0]...)
+	}
+	for _, pts := range [][][2]float64{inner, ends, parts.points} {
+		if pt, ok := nearest(pts); ok {
+			return geom.Point(pt), nil
+		}
+	}
+	return c, nil
+}
+
+// scanInterior returns the middle of the widest part inside the polygon of
+// a horizontal line near the middle of its extent that does not go through
+// any of its vertices, and its width. It is not ok if the polygon has no
+// area.
+func scanInterior(ply [][][2]float64) (pt [2]float64, width float64, ok bool) {
+	ext := geom.NewExtent(ply[0]...)
+	mid := (ext.MinY() + ext.MaxY()) / 2
+	// the line is half way between the vertices nearest the middle
+	lo, hi := math.Inf(-1), math.Inf(1)
+	for _, ring := range ply {
+		for _, v := range ring {
+			if v[1] <= mid && v[1] > lo {
+				lo = v[1]
+			}
+			if v[1] > mid && v[1] < hi {
+				hi = v[1]
+			}
+		}
+	}
+	if math.IsInf(lo, 0) || math.IsInf(hi, 0) {
+		return pt, 0, false
+	}
+	y := (lo + hi) / 2
+
+	var xs []float64
+	for _, ring := range ply {
+		for i := range ring {
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			if (a[1] < y) != (b[1] < y) {
+				xs = append(xs, a[0]+(y-a[1])*(b[0]-a[0])/(b[1]-a[1]))
+			}
+		}
+	}
+	sort.Float64s(xs)
+	// the line is inside the polygon between each pair of crossings
+	for i := 0; i+1 < len(xs); i += 2 {
+		if w := xs[i+1] - xs[i]; w > width {
+			pt, width, ok = [2]float64{(xs[i] + xs[i+1]) / 2, y}, w, true
+		}
+	}
+	return pt, width, ok
+}
@@ -0,0 +1,144 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestCentroid(t *testing.T) {
+	type tcase struct {
+		g        geom.Geometry
+		expected geom.Point
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Centroid(tc.g)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if math.Abs(got[0]-tc.expected[0]) > 1e-9 || math.Abs(got[1]-tc.expected[1]) > 1e-9 {
+				t.Errorf("centroid, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"points": {
+			g:        geom.MultiPoint{{0, 0}, {2, 0}, {4, 6}},
+			expected: geom.Point{2, 2},
+		},
+		"polygon z": {
+			g:        geom.PolygonZ{{{0, 0, 5}, {2, 0, 5}, {2, 2, 7}, {0, 2, 7}}},
+			expected: geom.Point{1, 1},
+		},
+		"line": {
+			// the long segment weighs more
+			g:        geom.LineString{{0, 0}, {3, 0}, {3, 1}},
+			expected: geom.Point{(3*1.5 + 1*3) / 4.0, 0.5 / 4},
+		},
+		"square": {
+			g:        geom.Polygon{{{0, 0}, {0, 2}, {2, 2}, {2, 0}}},
+			expected: geom.Point{1, 1},
+		},
+		"hole": {
+			g: geom.Polygon{
+				{{0, 0}, {4, 0}, {4, 4}, {0, 4}},
+				// wound the same way as the outer ring
+				{{2, 0}, {4, 0}, {4, 4}, {2, 4}},
+			},
+			expected: geom.Point{1, 2},
+		},
+		"highest dimension": {
+			g: geom.Collection{
+				geom.Point{100, 100},
+				geom.LineString{{-50, 0}, {50, 0}},
+				geom.Polygon{{{10, 10}, {12, 10}, {12, 12}, {10, 12}}},
+			},
+			expected: geom.Point{11, 11},
+		},
+		"nested collection": {
+			g: geom.Collection{
+				geom.Point{100, 100},
+				&geom.Collection{
+					geom.Collection{geom.Polygon{{{10, 10}, {12, 10}, {12, 12}, {10, 12}}}},
+				},
+			},
+			expected: geom.Point{11, 11},
+		},
+		"polygon with no area": {
+			g:        geom.Polygon{{{0, 0}, {2, 0}, {4, 0}}},
+			expected: geom.Point{2, 0},
+		},
+		"empty": {
+			g:   geom.MultiPoint{},
+			err: ErrEmptyGeometry,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestPointOnSurface(t *testing.T) {
+	type tcase struct {
+		g        geom.Geometry
+		expected geom.Point
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := PointOnSurface(tc.g)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("point, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"points": {
+			g:        geom.MultiPoint{{0, 0}, {2, 0}, {4, 6}},
+			expected: geom.Point{2, 0},
+		},
+		"line": {
+			g:        geom.MultiLineString{{{0, 0}, {1, 1}, {7, 1}, {9, 0}}},
+			expected: geom.Point{7, 1},
+		},
+		"segment": {
+			g:        geom.LineString{{0, 0}, {6, 0}},
+			expected: geom.Point{0, 0},
+		},
+		"u shape": {
+			// the centroid is outside
+			g: geom.Polygon{{
+				{0, 0}, {10, 0}, {10, 10}, {8, 10}, {8, 2}, {2, 2}, {2, 10}, {0, 10},
+			}},
+			expected: geom.Point{1, 6},
+		},
+		"donut": {
+			g: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{2, 2}, {8, 2}, {8, 8}, {2, 8}},
+			},
+			expected: geom.Point{1, 5},
+		},
+		"widest polygon": {
+			g: geom.MultiPolygon{
+				{{{0, 0}, {1, 0}, {1, 4}, {0, 4}}},
+				{{{5, 0}, {9, 0}, {9, 4}, {5, 4}}},
+			},
+			expected: geom.Point{7, 2},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
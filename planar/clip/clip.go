@@ -14,7 +14,10 @@ func (_ dclipper) Clip(ctx context.Context, geo geom.Geometry, clipbox *geom.Ext
 
 var Default dclipper
 
-// Geometry will return the clipped version of the given geometry.
+// Geometry will return the clipped version of the given geometry. Points
+// and MultiPoints are clipped to MultiPoints, lines to MultiLineStrings,
+// polygons to Polygons and MultiPolygons to MultiPolygons; see the function
+// for each type.
 func Geometry(ctx context.Context, geo geom.Geometry, clipbox *geom.Extent) (geom.Geometry, error) {
 	if clipbox.IsUniverse() {
 		return geo, nil
@@ -37,7 +40,52 @@ func Geometry(ctx context.Context, geo geom.Geometry, clipbox *geom.Extent) (geo
 		return LineStringer(ctx, g, clipbox)
 	case geom.MultiLineStringer:
 		return MultiLineStringer(ctx, g, clipbox)
+	case geom.Polygoner:
+		return Polygoner(ctx, g, clipbox)
+	case geom.MultiPolygoner:
+		return MultiPolygoner(ctx, g, clipbox)
+	case geom.Collectioner:
+		return Collectioner(ctx, g, clipbox)
 	default:
 		return geo, ErrUnsupportedGeometry
 	}
 }
+
+// Collectioner will clip each of the geometries of the collection to the
+// clipbox, removing the ones that are outside it.
+func Collectioner(ctx context.Context, collectioner geom.Collectioner, clipbox *geom.Extent) (geom.Collection, error) {
+	geos := collectioner.Geometries()
+	if clipbox.IsUniverse() {
+		return geom.Collection(geos), nil
+	}
+	var col geom.Collection
+	for _, geo := range geos {
+		cgeo, err := Geometry(ctx, geo, clipbox)
+		if err != nil {
+			return nil, err
+		}
+		if !isEmpty(cgeo) {
+			col = append(col, cgeo)
+		}
+	}
+	return col, nil
+}
+
+// isEmpty reports whether the clipped geometry has nothing in it
+func isEmpty(geo geom.Geometry) bool {
+	switch g := geo.(type) {
+	case nil:
+		return true
+	case geom.MultiPoint:
+		return len(g) == 0
+	case geom.MultiLineString:
+		return len(g) == 0
+	case geom.Polygon:
+		return len(g) == 0
+	case geom.MultiPolygon:
+		return len(g) == 0
+	case geom.Collection:
+		return len(g) == 0
+	}
+	return false
+}
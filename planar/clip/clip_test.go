@@ -0,0 +1,68 @@
+package clip
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestClipCollection(t *testing.T) {
+	type tcase struct {
+		geom     geom.Geometry
+		expected geom.Geometry
+	}
+
+	extent := &geom.Extent{0, 0, 10, 10}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Geometry(context.Background(), tc.geom, extent)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("clip, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"members": {
+			geom: geom.Collection{
+				geom.Point{1, 1},
+				geom.Point{20, 20},
+				geom.LineString{{-5, 5}, {5, 5}},
+			},
+			expected: geom.Collection{
+				geom.Point{1, 1},
+				geom.MultiLineString{{{0, 5}, {5, 5}}},
+			},
+		},
+		"nested": {
+			geom: geom.Collection{
+				geom.Collection{
+					geom.Point{20, 20},
+					&geom.Collection{geom.MultiPoint{{1, 1}, {20, 1}}},
+				},
+			},
+			expected: geom.Collection{
+				geom.Collection{
+					geom.Collection{geom.MultiPoint{{1, 1}}},
+				},
+			},
+		},
+		"nothing inside": {
+			geom: geom.Collection{
+				geom.Point{20, 20},
+				geom.Collection{geom.LineString{{20, 0}, {20, 10}}},
+			},
+			expected: geom.Collection(nil),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,54 @@
+package clip
+
+import "github.com/go-spatial/geom"
+
+// Line will clip the segment to the clipbox using the Liang–Barsky
+// algorithm, returning false if no part of it is in the clipbox. A segment
+// that only touches the clipbox is clipped to the point it touches.
+func Line(ln geom.Line, clipbox *geom.Extent) (geom.Line, bool) {
+	if clipbox.IsUniverse() {
+		return ln, true
+	}
+	dx, dy := ln[1][0]-ln[0][0], ln[1][1]-ln[0][1]
+	// the part of the segment in the clipbox, as fractions of its length
+	t0, t1 := 0.0, 1.0
+	// each side is p·t <= q for the points of the segment inside it
+	sides := [...][2]float64{
+		{-dx, ln[0][0] - clipbox.MinX()},
+		{dx, clipbox.MaxX() - ln[0][0]},
+		{-dy, ln[0][1] - clipbox.MinY()},
+		{dy, clipbox.MaxY() - ln[0][1]},
+	}
+	for _, s := range sides {
+		p, q := s[0], s[1]
+		switch {
+		case p == 0:
+			// parallel to the side
+			if q < 0 {
+				return geom.Line{}, false
+			}
+		case p < 0:
+			// entering
+			if r := q / p; r > t0 {
+				t0 = r
+			}
+		default:
+			// leaving
+			if r := q / p; r < t1 {
+				t1 = r
+			}
+		}
+		if t0 > t1 {
+			return geom.Line{}, false
+		}
+	}
+
+	clipped := ln
+	if t0 > 0 {
+		clipped[0] = [2]float64{ln[0][0] + t0*dx, ln[0][1] + t0*dy}
+	}
+	if t1 < 1 {
+		clipped[1] = [2]float64{ln[0][0] + t1*dx, ln[0][1] + t1*dy}
+	}
+	return clipped, true
+}
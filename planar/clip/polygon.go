@@ -0,0 +1,154 @@
+package clip
+
+import (
+	"context"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/winding"
+)
+
+// Polygoner will clip the rings of the polygon to the clipbox using the
+// Sutherland–Hodgman algorithm. Rings that are outside the clipbox are
+// removed, and if the outer ring is removed so is the polygon, in which
+// case nil is returned. Rings are closed in the result if they were closed
+// in the polygon.
+//
+// The parts of a concave ring that are inside the clipbox are kept in one
+// ring, joined by edges along the clipbox, so the result can have edges
+// that touch. This is fine for drawing, such as for tiles; use
+// planar.Intersection for polygons that are valid.
+func Polygoner(ctx context.Context, polygoner geom.Polygoner, clipbox *geom.Extent) (geom.Polygon, error) {
+	return polygon(ctx, polygoner.LinearRings(), clipbox)
+}
+
+func polygon(ctx context.Context, rings [][][2]float64, clipbox *geom.Extent) (geom.Polygon, error) {
+	if clipbox.IsUniverse() {
+		return geom.Polygon(rings), nil
+	}
+	var ply geom.Polygon
+	var holesArea float64
+	for i, ring := range rings {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		cring := linearRing(ring, clipbox)
+		if cring == nil {
+			if i == 0 {
+				return nil, nil
+			}
+			continue
+		}
+		if i > 0 {
+			holesArea += math.Abs(winding.SignedArea(cring...))
+		}
+		ply = append(ply, cring)
+	}
+	// the clipbox is in the holes
+	if len(ply) > 1 && holesArea >= math.Abs(winding.SignedArea(ply[0]...)) {
+		return nil, nil
+	}
+	return ply, nil
+}
+
+// MultiPolygoner will clip each of the polygons to the clipbox, removing
+// the ones that are outside it; see Polygoner.
+func MultiPolygoner(ctx context.Context, multipolygoner geom.MultiPolygoner, clipbox *geom.Extent) (geom.MultiPolygon, error) {
+	plys := multipolygoner.Polygons()
+	if clipbox.IsUniverse() {
+		return geom.MultiPolygon(plys), nil
+	}
+	var mply geom.MultiPolygon
+	for _, p := range plys {
+		ply, err := polygon(ctx, p, clipbox)
+		if err != nil {
+			return nil, err
+		}
+		if ply != nil {
+			mply = append(mply, ply)
+		}
+	}
+	return mply, nil
+}
+
+// linearRing returns the part of the ring inside the clipbox, nil if it has
+// no area there
+func linearRing(ring [][2]float64, clipbox *geom.Extent) [][2]float64 {
+	closed := len(ring) > 1 && ring[0] == ring[len(ring)-1]
+	if closed {
+		ring = ring[:len(ring)-1]
+	}
+	if len(ring) < 3 {
+		return nil
+	}
+
+	minx, miny, maxx, maxy := clipbox.MinX(), clipbox.MinY(), clipbox.MaxX(), clipbox.MaxY()
+	// for each edge of the clipbox, whether a point is inside it and where
+	// a segment crosses it
+	edges := [...]struct {
+		in    func(pt [2]float64) bool
+		cross func(a, b [2]float64) [2]float64
+	}{
+		{
+			in:    func(pt [2]float64) bool { return pt[0] >= minx },
+			cross: func(a, b [2]float64) [2]float64 { return [2]float64{minx, crossAt(a[0], a[1], b[0], b[1], minx)} },
+		},
+		{
+			in:    func(pt [2]float64) bool { return pt[0] <= maxx },
+			cross: func(a, b [2]float64) [2]float64 { return [2]float64{maxx, crossAt(a[0], a[1], b[0], b[1], maxx)} },
+		},
+		{
+			in:    func(pt [2]float64) bool { return pt[1] >= miny },
+			cross: func(a, b [2]float64) [2]float64 { return [2]float64{crossAt(a[1], a[0], b[1], b[0], miny), miny} },
+		},
+		{
+			in:    func(pt [2]float64) bool { return pt[1] <= maxy },
+			cross: func(a, b [2]float64) [2]float64 { return [2]float64{crossAt(a[1], a[0], b[1], b[0], maxy), maxy} },
+		},
+	}
+
+	out := ring
+	for _, e := range edges {
+		in := out
+		out = make([][2]float64, 0, len(in)+4)
+		for i, cur := range in {
+			prev := in[(i+len(in)-1)%len(in)]
+			switch curIn, prevIn := e.in(cur), e.in(prev); {
+			case curIn && !prevIn:
+				out = appendPoint(out, e.cross(prev, cur))
+				out = appendPoint(out, cur)
+			case curIn:
+				out = appendPoint(out, cur)
+			case prevIn:
+				out = appendPoint(out, e.cross(prev, cur))
+			}
+		}
+		if len(out) > 1 && out[0] == out[len(out)-1] {
+			out = out[:len(out)-1]
+		}
+		if len(out) < 3 {
+			return nil
+		}
+	}
+	if winding.SignedArea(out...) == 0 {
+		return nil
+	}
+	if closed {
+		out = append(out, out[0])
+	}
+	return out
+}
+
+// crossAt returns the v at which the segment from (u1, v1) to (u2, v2)
+// crosses u
+func crossAt(u1, v1, u2, v2, u float64) float64 {
+	return v1 + (v2-v1)*(u-u1)/(u2-u1)
+}
+
+// appendPoint appends the point if it is not the same as the last point
+func appendPoint(pts [][2]float64, pt [2]float64) [][2]float64 {
+	if len(pts) > 0 && pts[len(pts)-1] == pt {
+		return pts
+	}
+	return append(pts, pt)
+}
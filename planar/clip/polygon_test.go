@@ -0,0 +1,167 @@
+package clip
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+)
+
+func TestClipPolygon(t *testing.T) {
+	type tcase struct {
+		extent   *geom.Extent
+		polygon  geom.Polygon
+		expected geom.Polygon
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Polygoner(context.Background(), tc.polygon, tc.extent)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if (got == nil) != (tc.expected == nil) || !cmp.PolygonEqual(got, tc.expected) {
+				t.Errorf("polygon, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"inside": {
+			extent:   testExtents[0],
+			polygon:  geom.Polygon{{{1, 1}, {5, 1}, {5, 5}, {1, 5}}},
+			expected: geom.Polygon{{{1, 1}, {5, 1}, {5, 5}, {1, 5}}},
+		},
+		"outside": {
+			extent:  testExtents[0],
+			polygon: geom.Polygon{{{11, 11}, {15, 11}, {15, 15}, {11, 15}}},
+		},
+		"overlapping a corner": {
+			extent:   testExtents[0],
+			polygon:  geom.Polygon{{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}},
+			expected: geom.Polygon{{{5, 10}, {5, 5}, {10, 5}, {10, 10}, {5, 10}}},
+		},
+		"triangle across": {
+			extent:   testExtents[0],
+			polygon:  geom.Polygon{{{-5, 5}, {15, 0}, {15, 10}}},
+			expected: geom.Polygon{{{0, 3.75}, {10, 1.25}, {10, 8.75}, {0, 6.25}}},
+		},
+		"covering the clipbox": {
+			extent:   testExtents[6],
+			polygon:  geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			expected: geom.Polygon{{{5, 1}, {7, 1}, {7, 3}, {5, 3}}},
+		},
+		"hole outside": {
+			extent:   testExtents[1],
+			polygon:  geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, {{0.5, 0.5}, {0.5, 1}, {1, 1}}},
+			expected: geom.Polygon{{{2, 2}, {9, 2}, {9, 9}, {2, 9}}},
+		},
+		"hole across": {
+			extent: testExtents[1],
+			polygon: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{1, 4}, {1, 6}, {4, 6}, {4, 4}},
+			},
+			expected: geom.Polygon{
+				{{2, 2}, {9, 2}, {9, 9}, {2, 9}},
+				{{2, 4}, {2, 6}, {4, 6}, {4, 4}},
+			},
+		},
+		"clipbox in a hole": {
+			extent: testExtents[6],
+			polygon: geom.Polygon{
+				{{-10, -10}, {20, -10}, {20, 20}, {-10, 20}},
+				{{0, 0}, {0, 10}, {10, 10}, {10, 0}},
+			},
+		},
+		"flat along an edge": {
+			extent:  testExtents[0],
+			polygon: geom.Polygon{{{10, 0}, {15, 0}, {15, 5}, {10, 5}}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestClipLine(t *testing.T) {
+	type tcase struct {
+		line     geom.Line
+		expected geom.Line
+		ok       bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, ok := Line(tc.line, testExtents[0])
+			if ok != tc.ok {
+				t.Fatalf("ok, expected %v got %v", tc.ok, ok)
+			}
+			if got != tc.expected {
+				t.Errorf("line, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"inside": {
+			line:     geom.Line{{1, 1}, {9, 2}},
+			expected: geom.Line{{1, 1}, {9, 2}},
+			ok:       true,
+		},
+		"across": {
+			line:     geom.Line{{-5, 5}, {15, 5}},
+			expected: geom.Line{{0, 5}, {10, 5}},
+			ok:       true,
+		},
+		"diagonal": {
+			line:     geom.Line{{12, 12}, {-2, -2}},
+			expected: geom.Line{{10, 10}, {0, 0}},
+			ok:       true,
+		},
+		"one end inside": {
+			line:     geom.Line{{5, 5}, {5, 20}},
+			expected: geom.Line{{5, 5}, {5, 10}},
+			ok:       true,
+		},
+		"outside": {
+			line: geom.Line{{11, 0}, {11, 10}},
+		},
+		"past the corner": {
+			line: geom.Line{{9, 12}, {12, 9}},
+		},
+		"touching": {
+			line:     geom.Line{{5, 15}, {15, 5}},
+			expected: geom.Line{{10, 10}, {10, 10}},
+			ok:       true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestClipGeometry(t *testing.T) {
+	col := geom.Collection{
+		geom.Point{20, 20},
+		geom.MultiPolygon{
+			{{{5, 5}, {15, 5}, {15, 15}, {5, 15}}},
+			{{{20, 20}, {30, 20}, {30, 30}}},
+		},
+		geom.LineString{{5, 5}, {15, 5}},
+	}
+	got, err := Geometry(context.Background(), col, testExtents[0])
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	expected := geom.Collection{
+		geom.MultiPolygon{{{{5, 5}, {10, 5}, {10, 10}, {5, 10}}}},
+		geom.MultiLineString{{{5, 5}, {10, 5}}},
+	}
+	if !cmp.GeometryEqual(got, expected) {
+		t.Errorf("collection, expected %v got %v", expected, got)
+	}
+}
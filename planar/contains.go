@@ -0,0 +1,137 @@
+package planar
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/predicates"
+)
+
+// PolygonContains reports whether the point is inside the polygon or on its
+// boundary. Points inside a hole are not contained, but points on the
+// boundary of a hole are. The rings may be in either direction and closed
+// or not.
+func PolygonContains(poly geom.Polygon, pt geom.Point) bool {
+	inside := false
+	for _, ring := range poly {
+		for i := range ring {
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			cross, on := crossesRay(a, b, pt)
+			if on {
+				return true
+			}
+			if cross {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// crossesRay reports whether the edge from a to b crosses the ray going
+// right from the point, and whether the point is on the edge. Edges include
+// their lower end and not their upper end, so a ray through a vertex is
+// crossed once by the two edges meeting there, or not at all.
+func crossesRay(a, b, pt [2]float64) (cross, on bool) {
+	up, down := a[1] <= pt[1] && b[1] > pt[1], b[1] <= pt[1] && a[1] > pt[1]
+	if !up && !down && (a[1] != pt[1] || b[1] != pt[1]) {
+		// the edge is above or below the point, and not horizontal through
+		// it, but may end at the point
+		return false, pt == a || pt == b
+	}
+	o := predicates.Orient2D(a, b, pt)
+	if o == 0 {
+		return false, math.Min(a[0], b[0]) <= pt[0] && pt[0] <= math.Max(a[0], b[0]) &&
+			math.Min(a[1], b[1]) <= pt[1] && pt[1] <= math.Max(a[1], b[1])
+	}
+	return (up && o > 0) || (down && o < 0), false
+}
+
+// PreparedPolygon is a polygon with its edges indexed, for testing many
+// points against it. It is safe for concurrent use.
+type PreparedPolygon struct {
+	ext   geom.Extent
+	bandH float64
+	// bands holds the edges crossing each horizontal band of the extent
+	bands [][][2][2]float64
+}
+
+// NewPreparedPolygon returns the prepared polygon. The extent of the
+// polygon is split into horizontal bands, each holding the edges that
+// cross it, so only a few edges are looked at for each point.
+func NewPreparedPolygon(poly geom.Polygon) *PreparedPolygon {
+	var n int
+	for _, ring := range poly {
+		n += len(ring)
+	}
+	pp := &PreparedPolygon{ext: geom.Extent{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}}
+	if n == 0 {
+		return pp
+	}
+	for _, ring := range poly {
+		for _, pt := range ring {
+			pp.ext = geom.Extent{
+				math.Min(pp.ext[0], pt[0]), math.Min(pp.ext[1], pt[1]),
+				math.Max(pp.ext[2], pt[0]), math.Max(pp.ext[3], pt[1]),
+			}
+		}
+	}
+
+	pp.bands = make([][][2][2]float64, n)
+	pp.bandH = (pp.ext[3] - pp.ext[1]) / float64(n)
+	for _, ring := range poly {
+		for i := range ring {
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			if a == b {
+				continue
+			}
+			lo, hi := pp.band(math.Min(a[1], b[1])), pp.band(math.Max(a[1], b[1]))
+			for j := lo; j <= hi; j++ {
+				pp.bands[j] = append(pp.bands[j], [2][2]float64{a, b})
+			}
+		}
+	}
+	return pp
+}
+
+// band returns the index of the band holding y, which must be in the
+// extent
+func (pp *PreparedPolygon) band(y float64) int {
+	if pp.bandH == 0 {
+		return 0
+	}
+	i := int((y - pp.ext[1]) / pp.bandH)
+	if i >= len(pp.bands) {
+		i = len(pp.bands) - 1
+	}
+	return i
+}
+
+// Contains reports whether the point is inside the polygon or on its
+// boundary, as PolygonContains does.
+func (pp *PreparedPolygon) Contains(pt geom.Point) bool {
+	if len(pp.bands) == 0 || !pp.ext.ContainsPoint(pt) {
+		return false
+	}
+	inside := false
+	for _, e := range pp.bands[pp.band(pt[1])] {
+		cross, on := crossesRay(e[0], e[1], pt)
+		if on {
+			return true
+		}
+		if cross {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// ContainsPoints reports whether each of the points is inside the polygon
+// or on its boundary.
+func (pp *PreparedPolygon) ContainsPoints(pts []geom.Point) []bool {
+	ret := make([]bool, len(pts))
+	for i, pt := range pts {
+		ret[i] = pp.Contains(pt)
+	}
+	return ret
+}
@@ -0,0 +1,78 @@
+package planar
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestPolygonContains(t *testing.T) {
+	square := geom.Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+		{{2, 2}, {2, 4}, {4, 4}, {4, 2}},
+	}
+	// a clockwise, closed ring with vertices level with the test points
+	zigzag := geom.Polygon{{{0, 0}, {0, 10}, {5, 5}, {10, 10}, {10, 0}, {5, 5}, {0, 0}}}
+
+	type tcase struct {
+		poly     geom.Polygon
+		pt       geom.Point
+		expected bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if got := PolygonContains(tc.poly, tc.pt); got != tc.expected {
+				t.Errorf("contains, expected %v got %v", tc.expected, got)
+			}
+			if got := NewPreparedPolygon(tc.poly).Contains(tc.pt); got != tc.expected {
+				t.Errorf("prepared contains, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"inside":              {poly: square, pt: geom.Point{5, 5}, expected: true},
+		"outside":             {poly: square, pt: geom.Point{15, 5}},
+		"in the hole":         {poly: square, pt: geom.Point{3, 3}},
+		"on the edge":         {poly: square, pt: geom.Point{10, 5}, expected: true},
+		"on a vertex":         {poly: square, pt: geom.Point{0, 10}, expected: true},
+		"on the hole":         {poly: square, pt: geom.Point{2, 3}, expected: true},
+		"left of a vertex":    {poly: square, pt: geom.Point{-1, 10}},
+		"level with the hole": {poly: square, pt: geom.Point{1, 4}, expected: true},
+		"level with a vertex": {poly: zigzag, pt: geom.Point{2, 5}, expected: true},
+		"in the notch":        {poly: zigzag, pt: geom.Point{5, 8}},
+		"on the notch":        {poly: zigzag, pt: geom.Point{5, 5}, expected: true},
+		"empty":               {poly: geom.Polygon{}, pt: geom.Point{0, 0}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestPreparedPolygonContainsPoints(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var ring [][2]float64
+	for i := 0; i < 200; i++ {
+		ring = append(ring, [2]float64{rnd.Float64() * 100, rnd.Float64() * 100})
+	}
+	poly := geom.Polygon{ring, {{40, 40}, {40, 60}, {60, 60}, {60, 40}}}
+
+	pts := make([]geom.Point, 10000)
+	for i := range pts {
+		// use a coarse grid so points fall on the same y as the vertices
+		pts[i] = geom.Point{float64(rnd.Intn(120) - 10), ring[rnd.Intn(len(ring))][1]}
+		if i%2 == 0 {
+			pts[i][1] = float64(rnd.Intn(120) - 10)
+		}
+	}
+
+	got := NewPreparedPolygon(poly).ContainsPoints(pts)
+	for i, pt := range pts {
+		if expected := PolygonContains(poly, pt); got[i] != expected {
+			t.Fatalf("contains %v, expected %v got %v", pt, expected, got[i])
+		}
+	}
+}
@@ -0,0 +1,574 @@
+// Package contour traces the contours of a regular grid of values, such as
+// heights, by marching squares: isolines, along which the values are a
+// level, and isobands, the areas in which the values are between two
+// levels.
+//
+// The values are taken to change linearly along the edges of the cells of
+// the grid, and a value equal to a level is taken to be above it. Where
+// the corners of a cell at opposite corners are above a level and the
+// others below it, the average of the four decides whether the corners
+// above are joined through the middle of the cell. Isolines and isobands
+// are traced the same way, so the edges of the bands are the isolines of
+// their levels.
+//
+// The grid of interpolate.Grid, of cols by rows over an extent, has its
+// rows going down from the top, and values at the centers of the cells:
+//
+//	dx, dy := ext.XSpan()/float64(cols), ext.YSpan()/float64(rows)
+//	g := contour.Grid{
+//		Values:   values,
+//		Origin:   [2]float64{ext.MinX() + dx/2, ext.MaxY() - dy/2},
+//		CellSize: [2]float64{dx, -dy},
+//	}
+package contour
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/winding"
+)
+
+var (
+	// ErrInvalidGrid is returned when the grid has fewer than two rows or
+	// columns, rows of different lengths, or a cell size of 0.
+	ErrInvalidGrid = errors.New("contour: invalid grid")
+	// ErrInvalidLevels is returned when the levels of isobands do not
+	// increase.
+	ErrInvalidLevels = errors.New("contour: levels must increase")
+)
+
+// Grid is a regular grid of values, at points spaced evenly in x and y.
+type Grid struct {
+	// Values are the values at the points of the grid, in rows. The cells
+	// with a NaN value at a corner have no contours.
+	Values [][]float64
+	// Origin is the point of Values[0][0]
+	Origin [2]float64
+	// CellSize is the step in x from one column to the next, and in y from
+	// one row to the next. Either can be negative, as y is for rows going
+	// down from the top.
+	CellSize [2]float64
+}
+
+// validate returns ErrInvalidGrid if the grid is not valid.
+func (g Grid) validate() error {
+	if len(g.Values) < 2 || len(g.Values[0]) < 2 || g.CellSize[0] == 0 || g.CellSize[1] == 0 {
+		return ErrInvalidGrid
+	}
+	for _, row := range g.Values {
+		if len(row) != len(g.Values[0]) {
+			return ErrInvalidGrid
+		}
+	}
+	return nil
+}
+
+// flipped returns whether the grid's columns and rows, going up in x and
+// y, make a left handed system, so turning counter clockwise in the grid
+// is clockwise in its coordinates.
+func (g Grid) flipped() bool { return (g.CellSize[0] < 0) != (g.CellSize[1] < 0) }
+
+// toXY returns the coordinates of the position in the grid, in columns and
+// rows.
+func (g Grid) toXY(pt [2]float64) [2]float64 {
+	return [2]float64{g.Origin[0] + pt[0]*g.CellSize[0], g.Origin[1] + pt[1]*g.CellSize[1]}
+}
+
+// eachCell calls fn with each of the cells of the grid with a value at each
+// corner.
+func (g Grid) eachCell(ctx context.Context, fn func(cl *cell)) error {
+	cols := len(g.Values[0])
+	var cl cell
+	for r := 0; r+1 < len(g.Values); r++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	cells:
+		for c := 0; c+1 < cols; c++ {
+			cl = cell{c: c, r: r, cols: cols}
+			for k, off := range cornerOffsets {
+				v := g.Values[r+off[1]][c+off[0]]
+				if math.IsNaN(v) {
+					continue cells
+				}
+				cl.v[k] = v
+			}
+			fn(&cl)
+		}
+	}
+	return nil
+}
+
+// Isolines returns the lines along which the values of the grid are each
+// of the levels. The lines are directed with the higher values on their
+// left, looking with the y axis up, and the lines that close on themselves
+// end with their first point.
+func Isolines(ctx context.Context, g Grid, levels []float64) ([]geom.MultiLineString, error) {
+	if err := g.validate(); err != nil {
+		return nil, err
+	}
+	lines := make([]geom.MultiLineString, len(levels))
+	for i, level := range levels {
+		var (
+			// the crossings of the edges of the grid, by the index of
+			// the edge, and the next crossing along the lines
+			pts  = make(map[int][2]float64)
+			next = make(map[int]int)
+			prev = make(map[int]bool)
+			// the crossings that start segments, in the order found
+			starts []int
+		)
+		err := g.eachCell(ctx, func(cl *cell) {
+			for _, ch := range cl.chords(level) {
+				from, to := cl.edgeIndex(ch[0]), cl.edgeIndex(ch[1])
+				pts[from], _ = cl.crossing(ch[0], level)
+				pts[to], _ = cl.crossing(ch[1], level)
+				next[from] = to
+				prev[to] = true
+				starts = append(starts, from)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var mls geom.MultiLineString
+		visited := make(map[int]bool)
+		trace := func(start int, closed bool) {
+			var line [][2]float64
+			for e, ok := start, true; ok && !visited[e]; e, ok = next[e] {
+				visited[e] = true
+				line = appendPoint(line, g.toXY(pts[e]))
+			}
+			if closed && len(line) > 2 {
+				line = append(line, line[0])
+			}
+			if len(line) < 2 {
+				return
+			}
+			if g.flipped() {
+				reverse(line)
+			}
+			mls = append(mls, line)
+		}
+		// the lines that end at the edge of the grid, or of the cells
+		// without values, and then those that close on themselves
+		for _, e := range starts {
+			if !prev[e] {
+				trace(e, false)
+			}
+		}
+		for _, e := range starts {
+			if !visited[e] {
+				trace(e, true)
+			}
+		}
+		lines[i] = mls
+	}
+	return lines, nil
+}
+
+// Isobands returns the areas in which the values of the grid are between
+// each pair of the levels, which must increase: the first band is of the
+// values at least levels[0] and less than levels[1], and there is one
+// band fewer than there are levels. The outer rings of the polygons are
+// clockwise, and the holes counter clockwise, by winding.Order{}.
+func Isobands(ctx context.Context, g Grid, levels []float64) ([]geom.MultiPolygon, error) {
+	if err := g.validate(); err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(levels); i++ {
+		if !(levels[i-1] < levels[i]) {
+			return nil, ErrInvalidLevels
+		}
+	}
+	if len(levels) < 2 {
+		return nil, nil
+	}
+	bands := make([]geom.MultiPolygon, len(levels)-1)
+	for i := range bands {
+		var faces [][][2]float64
+		err := g.eachCell(ctx, func(cl *cell) {
+			faces = append(faces, cl.bandFaces(levels[i], levels[i+1])...)
+		})
+		if err != nil {
+			return nil, err
+		}
+		bands[i] = g.polygons(dissolve(faces))
+	}
+	return bands, nil
+}
+
+// polygons returns the polygons, in the coordinates of the grid, of the
+// rings in positions in the grid: the outer rings counter clockwise and the
+// holes clockwise.
+func (g Grid) polygons(rings [][][2]float64) geom.MultiPolygon {
+	var (
+		outers, holes [][][2]float64
+		areas         []float64
+	)
+	for _, ring := range rings {
+		a := winding.SignedArea(ring...)
+		if a > 0 {
+			outers = append(outers, ring)
+			areas = append(areas, a)
+		} else {
+			holes = append(holes, ring)
+		}
+	}
+	plys := make([][][][2]float64, len(outers))
+	for i, ring := range outers {
+		plys[i] = [][][2]float64{ring}
+	}
+	for _, hole := range holes {
+		// the smallest outer ring around the middle of an edge of the
+		// hole, which is not on an outer ring
+		pt := [2]float64{(hole[0][0] + hole[1][0]) / 2, (hole[0][1] + hole[1][1]) / 2}
+		in := -1
+		for i, ring := range outers {
+			if (in < 0 || areas[i] < areas[in]) && inRing(ring, pt) {
+				in = i
+			}
+		}
+		if in >= 0 {
+			plys[in] = append(plys[in], hole)
+		}
+	}
+
+	mp := make(geom.MultiPolygon, len(plys))
+	for i, ply := range plys {
+		for _, ring := range ply {
+			xys := make([][2]float64, len(ring))
+			for j, pt := range ring {
+				xys[j] = g.toXY(pt)
+			}
+			if g.flipped() {
+				reverse(xys)
+			}
+			mp[i] = append(mp[i], xys)
+		}
+	}
+	return mp
+}
+
+// cornerOffsets are the columns and rows of the corners of a cell from its
+// first corner, counter clockwise with the rows going up
+var cornerOffsets = [4][2]int{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+
+// edgeCorners are the corners of the edges of a cell, edge k going from
+// corner k to the next one, in the direction of the columns and rows, so
+// the cells on either side of an edge find the same crossings along it
+var edgeCorners = [4][2]int{{0, 1}, {1, 2}, {3, 2}, {0, 3}}
+
+// cell is a cell of the grid with values at its corners
+type cell struct {
+	c, r, cols int
+	v          [4]float64
+}
+
+// corner returns the position of the corner in the grid.
+func (cl *cell) corner(k int) [2]float64 {
+	return [2]float64{float64(cl.c + cornerOffsets[k][0]), float64(cl.r + cornerOffsets[k][1])}
+}
+
+// edgeIndex returns the index in the grid of the edge of the cell; the
+// edges along the rows are even and those along the columns odd.
+func (cl *cell) edgeIndex(k int) int {
+	from := cornerOffsets[edgeCorners[k][0]]
+	i := 2 * ((cl.r+from[1])*cl.cols + cl.c + from[0])
+	if k%2 == 1 {
+		i++
+	}
+	return i
+}
+
+// crossing returns the position in the grid along the edge of the cell at
+// which the values are the level, or false if they are not.
+func (cl *cell) crossing(k int, level float64) ([2]float64, bool) {
+	from, to := edgeCorners[k][0], edgeCorners[k][1]
+	a, b := cl.v[from], cl.v[to]
+	if (a >= level) == (b >= level) {
+		return [2]float64{}, false
+	}
+	pt := cl.corner(from)
+	pt[k%2] += (level - a) / (b - a)
+	return pt, true
+}
+
+// chords returns the pairs of edges of the cell the isolines of the level
+// cross it between, from the edge at which going counter clockwise round
+// the cell leaves the values above the level to the edge at which it comes
+// back, so the values above are on the left of the isoline.
+func (cl *cell) chords(level float64) [][2]int {
+	var exits, entries []int
+	for k := 0; k < 4; k++ {
+		a, b := cl.v[k] >= level, cl.v[(k+1)%4] >= level
+		switch {
+		case a && !b:
+			exits = append(exits, k)
+		case !a && b:
+			entries = append(entries, k)
+		}
+	}
+	switch len(exits) {
+	case 0:
+		return nil
+	case 1:
+		return [][2]int{{exits[0], entries[0]}}
+	}
+
+	// a saddle, the corners above are joined through the middle of the
+	// cell if it is above, so the corners below are cut off, or else
+	// they are cut off
+	joined := (cl.v[0]+cl.v[1]+cl.v[2]+cl.v[3])/4 >= level
+	chords := make([][2]int, 2)
+	for i, k := range exits {
+		if joined {
+			chords[i] = [2]int{k, (k + 1) % 4}
+		} else {
+			chords[i] = [2]int{k, (k + 3) % 4}
+		}
+	}
+	return chords
+}
+
+// bandFaces returns the parts of the cell in which the values are at
+// least low and less than high, as counter clockwise rings in the positions
+// of the grid.
+func (cl *cell) bandFaces(low, high float64) [][][2]float64 {
+	type boundaryPoint struct {
+		pt [2]float64
+		// band is whether the boundary of the cell going on from the point
+		// is in the band
+		band bool
+		// partner is the other end of the isoline the crossing is on, or
+		// -1 for corners
+		partner int
+	}
+	inBand := func(v float64) bool { return v >= low && v < high }
+
+	var (
+		pts []boundaryPoint
+		// the points of the crossings of the edges, of low and high
+		crossings [2][4]int
+		touched   bool
+	)
+	for k := 0; k < 4; k++ {
+		band := inBand(cl.v[k])
+		touched = touched || band
+		pts = append(pts, boundaryPoint{pt: cl.corner(k), band: band, partner: -1})
+		// going up the values the crossing of low comes first
+		order := [2]int{0, 1}
+		if cl.v[k] > cl.v[(k+1)%4] {
+			order = [2]int{1, 0}
+		}
+		for _, l := range order {
+			pt, ok := cl.crossing(k, [2]float64{low, high}[l])
+			if !ok {
+				continue
+			}
+			// every crossing is into or out of the band
+			band = !band
+			touched = true
+			crossings[l][k] = len(pts)
+			pts = append(pts, boundaryPoint{pt: pt, band: band, partner: -1})
+		}
+	}
+	if !touched {
+		return nil
+	}
+	for l, level := range [2]float64{low, high} {
+		for _, ch := range cl.chords(level) {
+			a, b := crossings[l][ch[0]], crossings[l][ch[1]]
+			pts[a].partner, pts[b].partner = b, a
+		}
+	}
+
+	// walk round the band, along the boundary of the cell while it is in
+	// the band and along the isolines while it is not
+	var faces [][][2]float64
+	visited := make([]bool, len(pts))
+	for start := range pts {
+		if !pts[start].band || visited[start] {
+			continue
+		}
+		var face [][2]float64
+		for i, steps := start, 0; steps < len(pts); steps++ {
+			visited[i] = true
+			face = append(face, pts[i].pt)
+			i = (i + 1) % len(pts)
+			if !pts[i].band {
+				face = append(face, pts[i].pt)
+				i = pts[i].partner
+			}
+			if i == start || i < 0 || !pts[i].band {
+				break
+			}
+		}
+		faces = append(faces, face)
+	}
+	return faces
+}
+
+// edge is a directed edge of a ring
+type edge [2][2]float64
+
+// dissolve returns the rings of the union of the faces, which do not
+// overlap and are split the same way along the edges they share: the outer
+// rings counter clockwise and the holes clockwise.
+func dissolve(faces [][][2]float64) [][][2]float64 {
+	count := make(map[edge]int)
+	var edges []edge
+	for _, face := range faces {
+		var ring [][2]float64
+		for _, pt := range face {
+			ring = appendPoint(ring, pt)
+		}
+		for len(ring) > 1 && ring[len(ring)-1] == ring[0] {
+			ring = ring[:len(ring)-1]
+		}
+		if len(ring) < 3 || winding.SignedArea(ring...) == 0 {
+			continue
+		}
+		for i := range ring {
+			e := edge{ring[i], ring[(i+1)%len(ring)]}
+			// an edge shared with another face is inside of the union
+			if rev := (edge{e[1], e[0]}); count[rev] > 0 {
+				count[rev]--
+				continue
+			}
+			count[e]++
+			edges = append(edges, e)
+		}
+	}
+
+	var remaining []edge
+	out := make(map[[2]float64][]edge)
+	for _, e := range edges {
+		if count[e] > 0 {
+			count[e]--
+			remaining = append(remaining, e)
+			out[e[0]] = append(out[e[0]], e)
+		}
+	}
+
+	var rings [][][2]float64
+	used := make(map[edge]bool)
+	for _, e := range remaining {
+		if used[e] {
+			continue
+		}
+		var ring [][2]float64
+		for ok := true; ok && !used[e]; {
+			used[e] = true
+			ring = append(ring, e[0])
+			e, ok = leftmost(e, out[e[1]], used)
+		}
+		for _, loop := range splitRing(ring) {
+			if loop = clean(loop); len(loop) >= 3 && winding.SignedArea(loop...) != 0 {
+				rings = append(rings, loop)
+			}
+		}
+	}
+	return rings
+}
+
+// splitRing returns the loops of the ring between the points it passes
+// through more than once. Tracing the boundary of a band that closes on
+// itself at a point gives one ring round both the outside and the hole,
+// which are its loops.
+func splitRing(ring [][2]float64) [][][2]float64 {
+	var (
+		loops [][][2]float64
+		stack [][2]float64
+		at    = make(map[[2]float64]int)
+	)
+	for _, pt := range ring {
+		if i, ok := at[pt]; ok {
+			loop := append([][2]float64(nil), stack[i:]...)
+			loops = append(loops, loop)
+			for _, p := range stack[i+1:] {
+				delete(at, p)
+			}
+			stack = stack[:i+1]
+			continue
+		}
+		at[pt] = len(stack)
+		stack = append(stack, pt)
+	}
+	return append(loops, stack)
+}
+
+// leftmost returns the edge of next, not yet used, that turns most to the
+// left from e, so rings that touch at a point are traced apart.
+func leftmost(e edge, next []edge, used map[edge]bool) (edge, bool) {
+	var (
+		best  edge
+		angle = math.Inf(-1)
+	)
+	d := sub(e[1], e[0])
+	for _, n := range next {
+		if used[n] {
+			continue
+		}
+		dn := sub(n[1], n[0])
+		if a := math.Atan2(cross(d, dn), d[0]*dn[0]+d[1]*dn[1]); a > angle {
+			best, angle = n, a
+		}
+	}
+	return best, !math.IsInf(angle, -1)
+}
+
+// clean returns the ring without points on the line between the points on
+// either side of them.
+func clean(ring [][2]float64) [][2]float64 {
+	for changed := true; changed; {
+		changed = false
+		for i := 0; i < len(ring) && len(ring) >= 3; {
+			prev, next := ring[(i+len(ring)-1)%len(ring)], ring[(i+1)%len(ring)]
+			if cross(sub(ring[i], prev), sub(next, ring[i])) == 0 {
+				ring = append(ring[:i], ring[i+1:]...)
+				changed = true
+				continue
+			}
+			i++
+		}
+	}
+	return ring
+}
+
+// appendPoint appends the point to the points, if it is not the same as the
+// last of them.
+func appendPoint(pts [][2]float64, pt [2]float64) [][2]float64 {
+	if len(pts) > 0 && pts[len(pts)-1] == pt {
+		return pts
+	}
+	return append(pts, pt)
+}
+
+func reverse(pts [][2]float64) {
+	for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+		pts[i], pts[j] = pts[j], pts[i]
+	}
+}
+
+func sub(a, b [2]float64) [2]float64 { return [2]float64{a[0] - b[0], a[1] - b[1]} }
+
+func cross(a, b [2]float64) float64 { return a[0]*b[1] - a[1]*b[0] }
+
+// inRing returns whether the point is inside of the ring, by the even odd
+// rule.
+func inRing(ring [][2]float64, pt [2]float64) bool {
+	in := false
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		if (a[1] > pt[1]) != (b[1] > pt[1]) &&
+			pt[0] < a[0]+(pt[1]-a[1])/(b[1]-a[1])*(b[0]-a[0]) {
+			in = !in
+		}
+	}
+	return in
+}
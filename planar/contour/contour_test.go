@@ -0,0 +1,217 @@
+package contour
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/winding"
+)
+
+func TestIsolines(t *testing.T) {
+	type tcase struct {
+		grid  Grid
+		level float64
+		lines geom.MultiLineString
+		err   error
+	}
+
+	columns := [][]float64{{0, 1, 2}, {0, 1, 2}, {0, 1, 2}}
+	peak := [][]float64{{0, 0, 0}, {0, 1, 0}, {0, 0, 0}}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Isolines(context.Background(), tc.grid, []float64{tc.level})
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got[0], tc.lines) {
+				t.Errorf("lines, expected %v got %v", tc.lines, got[0])
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"line": {
+			grid:  Grid{Values: columns, CellSize: [2]float64{1, 1}},
+			level: 0.5,
+			lines: geom.MultiLineString{{{0.5, 2}, {0.5, 1}, {0.5, 0}}},
+		},
+		"rows going down": {
+			grid:  Grid{Values: columns, Origin: [2]float64{10, 0}, CellSize: [2]float64{1, -1}},
+			level: 0.5,
+			lines: geom.MultiLineString{{{10.5, 0}, {10.5, -1}, {10.5, -2}}},
+		},
+		"closed": {
+			grid:  Grid{Values: peak, CellSize: [2]float64{2, 2}},
+			level: 0.5,
+			lines: geom.MultiLineString{{{1, 2}, {2, 1}, {3, 2}, {2, 3}, {1, 2}}},
+		},
+		"saddle joined": {
+			grid:  Grid{Values: [][]float64{{1, 0}, {0, 1}}, CellSize: [2]float64{1, 1}},
+			level: 0.5,
+			lines: geom.MultiLineString{{{0.5, 0}, {1, 0.5}}, {{0.5, 1}, {0, 0.5}}},
+		},
+		"saddle apart": {
+			grid:  Grid{Values: [][]float64{{1, 0}, {0, 1}}, CellSize: [2]float64{1, 1}},
+			level: 0.6,
+			lines: geom.MultiLineString{{{0.4, 0}, {0, 0.4}}, {{0.6, 1}, {1, 0.6}}},
+		},
+		"no values": {
+			grid:  Grid{Values: [][]float64{{0, 1}, {math.NaN(), 1}}, CellSize: [2]float64{1, 1}},
+			level: 0.5,
+		},
+		"one row": {
+			grid: Grid{Values: [][]float64{{0, 1}}, CellSize: [2]float64{1, 1}},
+			err:  ErrInvalidGrid,
+		},
+		"ragged": {
+			grid: Grid{Values: [][]float64{{0, 1}, {1}}, CellSize: [2]float64{1, 1}},
+			err:  ErrInvalidGrid,
+		},
+		"no cell size": {
+			grid: Grid{Values: columns},
+			err:  ErrInvalidGrid,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestIsobands(t *testing.T) {
+	type tcase struct {
+		grid   Grid
+		levels []float64
+		// the number of rings of each polygon of each band
+		rings [][]int
+		err   error
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	random := func(rows, cols int, value func() float64) [][]float64 {
+		vs := make([][]float64, rows)
+		for r := range vs {
+			vs[r] = make([]float64, cols)
+			for c := range vs[r] {
+				vs[r][c] = value()
+			}
+		}
+		return vs
+	}
+	levels := []float64{0, 1, 2, 3, 4, 5}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			bands, err := Isobands(context.Background(), tc.grid, tc.levels)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(bands) != len(tc.levels)-1 {
+				t.Fatalf("bands, expected %v got %v", len(tc.levels)-1, len(bands))
+			}
+
+			var total float64
+			for i, band := range bands {
+				// the polygons of a band can touch at more than one point
+				// where the values are the levels, so only they are valid
+				for j, ply := range band {
+					report, err := planar.Validate(geom.Polygon(ply))
+					if err != nil || !report.Valid() {
+						t.Errorf("band %v polygon %v not valid: %v %v", i, j, err, report)
+					}
+				}
+				// the bands cover the grid, without overlapping
+				for _, ply := range band {
+					for j, ring := range ply {
+						a := winding.SignedArea(ring...)
+						if (j == 0) != (a > 0) {
+							t.Errorf("band %v ring %v area %v, expected outer rings positive and holes negative", i, j, a)
+						}
+						total += a
+					}
+				}
+			}
+			rows, cols := len(tc.grid.Values), len(tc.grid.Values[0])
+			want := float64((rows-1)*(cols-1)) * math.Abs(tc.grid.CellSize[0]*tc.grid.CellSize[1])
+			if math.Abs(total-want) > 1e-9*want {
+				t.Errorf("area, expected %v got %v", want, total)
+			}
+
+			if tc.rings == nil {
+				return
+			}
+			var rings [][]int
+			for _, band := range bands {
+				var rs []int
+				for _, ply := range band {
+					rs = append(rs, len(ply))
+				}
+				rings = append(rings, rs)
+			}
+			if !reflect.DeepEqual(rings, tc.rings) {
+				t.Errorf("rings, expected %v got %v", tc.rings, rings)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"annulus": {
+			grid: Grid{
+				Values: [][]float64{
+					{0, 0, 0, 0, 0},
+					{0, 2, 2, 2, 0},
+					{0, 2, 0, 2, 0},
+					{0, 2, 2, 2, 0},
+					{0, 0, 0, 0, 0},
+				},
+				CellSize: [2]float64{1, 1},
+			},
+			levels: []float64{0, 1, 3},
+			rings:  [][]int{{2, 1}, {2}},
+		},
+		"saddles": {
+			grid: Grid{
+				Values:   [][]float64{{1, 0, 1}, {0, 1, 0}, {1, 0, 1}},
+				CellSize: [2]float64{1, 1},
+			},
+			levels: []float64{0, 0.5, 2},
+			rings:  [][]int{{1, 1, 1, 1}, {1}},
+		},
+		"random": {
+			grid: Grid{
+				Values:   random(20, 30, func() float64 { return rng.Float64() * 5 }),
+				Origin:   [2]float64{100, 200},
+				CellSize: [2]float64{2, 3},
+			},
+			levels: levels,
+		},
+		"random at the levels": {
+			grid: Grid{
+				Values:   random(20, 30, func() float64 { return float64(rng.Intn(5)) }),
+				CellSize: [2]float64{1, -1},
+			},
+			levels: levels,
+		},
+		"not increasing": {
+			grid:   Grid{Values: [][]float64{{0, 1}, {0, 1}}, CellSize: [2]float64{1, 1}},
+			levels: []float64{0, 1, 1},
+			err:    ErrInvalidLevels,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,73 @@
+package planar
+
+import (
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+	"github.com/go-spatial/geom/planar/predicates"
+)
+
+// ConvexHull returns the smallest convex geometry containing all the points
+// of the geometry, found with Andrew's monotone chain. It is a Polygon
+// whose ring is counter clockwise, with y going up, and not closed; or a
+// LineString between the ends, if all the points are on a line; or a
+// Point, if all the points are the same. It is nil if the geometry has no
+// points.
+//
+// Every kind of geometry is supported, including collections and
+// geometries with Z or M values, whose Z and M values are ignored. Points
+// on the edges of the hull are not vertices of it.
+func ConvexHull(g geom.Geometry) (geom.Geometry, error) {
+	var pts [][2]float64
+	err := geom.Walk(g, func(pt [2]float64) error {
+		pts = append(pts, pt)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pts) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(pts, func(i, j int) bool { return cmp.PointLess(pts[i], pts[j]) })
+	uniq := pts[:1]
+	for _, pt := range pts[1:] {
+		if pt != uniq[len(uniq)-1] {
+			uniq = append(uniq, pt)
+		}
+	}
+	pts = uniq
+	switch len(pts) {
+	case 1:
+		return geom.Point(pts[0]), nil
+	case 2:
+		return geom.LineString{pts[0], pts[1]}, nil
+	}
+
+	// the lower hull from left to right, then the upper hull back, only
+	// keeping the points where the hull turns left
+	hull := make([][2]float64, 0, 2*len(pts))
+	for pass, lower := 0, 0; pass < 2; pass++ {
+		lower = len(hull)
+		for k := range pts {
+			pt := pts[k]
+			if pass == 1 {
+				pt = pts[len(pts)-1-k]
+			}
+			for len(hull) >= lower+2 && predicates.Orient2D(hull[len(hull)-2], hull[len(hull)-1], pt) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, pt)
+		}
+		// the last point is the first of the next pass
+		hull = hull[:len(hull)-1]
+	}
+
+	if len(hull) < 3 {
+		// all the points are on a line between the first and last
+		return geom.LineString{pts[0], pts[len(pts)-1]}, nil
+	}
+	return geom.Polygon{hull}, nil
+}
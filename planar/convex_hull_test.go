@@ -0,0 +1,74 @@
+package planar
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestConvexHull(t *testing.T) {
+	type tcase struct {
+		g        geom.Geometry
+		expected geom.Geometry
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := ConvexHull(tc.g)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("hull, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {
+			g: geom.MultiPoint{},
+		},
+		"point": {
+			g:        geom.MultiPoint{{1, 2}, {1, 2}},
+			expected: geom.Point{1, 2},
+		},
+		"two points": {
+			g:        geom.MultiPoint{{3, 3}, {1, 2}},
+			expected: geom.LineString{{1, 2}, {3, 3}},
+		},
+		"on a line": {
+			g:        geom.LineString{{2, 2}, {0, 0}, {1, 1}, {3, 3}},
+			expected: geom.LineString{{0, 0}, {3, 3}},
+		},
+		"square": {
+			g: geom.MultiPoint{
+				{0, 0}, {10, 10}, {5, 5}, {10, 0}, {0, 10}, {5, 0}, {2, 7},
+			},
+			expected: geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+		},
+		"polygon with a hole": {
+			g: geom.Polygon{
+				{{0, 0}, {0, 4}, {2, 6}, {4, 4}, {3, 2}, {4, 0}},
+				{{1, 1}, {2, 1}, {2, 2}},
+			},
+			expected: geom.Polygon{{{0, 0}, {4, 0}, {4, 4}, {2, 6}, {0, 4}}},
+		},
+		"collection": {
+			g: geom.Collection{
+				geom.Point{0, 0},
+				geom.LineString{{4, 0}, {4, 4}},
+				geom.PointZ{0, 4, 9},
+			},
+			expected: geom.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 4}}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	if _, err := ConvexHull(struct{}{}); err == nil {
+		t.Errorf("unknown geometry error, expected an error got nil")
+	}
+}
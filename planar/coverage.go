@@ -0,0 +1,77 @@
+package planar
+
+import (
+	"context"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/winding"
+)
+
+// CoverageUnion returns the union of the polygons of the geometries, which
+// must be a clean coverage: the polygons do not overlap, and where they
+// share a boundary they have exactly the same vertices along it. The edges
+// shared by two polygons are inside the union and dropped, and the rest are
+// linked into its rings, so it takes time near linear in the number of
+// vertices, far less than Union, which finds where every edge crosses. It
+// is the way to dissolve the polygons of administrative boundaries and
+// other partitions.
+//
+// If the polygons are not a clean coverage the result is not their union.
+// Points and lines are ignored. The rings of the result are wound as the
+// Options of the context say.
+func CoverageUnion(ctx context.Context, geoms []geom.Geometry) (geom.MultiPolygon, error) {
+	var parts geomParts
+	for _, g := range geoms {
+		if err := parts.add(g); err != nil {
+			return nil, err
+		}
+	}
+
+	// the edges are directed with the polygon on the left, so an edge
+	// shared by two polygons is there once in each direction
+	var (
+		edges []geom.Line
+		count = make(map[geom.Line]int)
+	)
+	for _, ply := range parts.polys {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for i, ring := range ply {
+			// shells are counter-clockwise and holes clockwise
+			reverse := (winding.SignedArea(ring...) < 0) == (i == 0)
+			for j := range ring {
+				e := geom.Line{ring[j], ring[(j+1)%len(ring)]}
+				if reverse {
+					e[0], e[1] = e[1], e[0]
+				}
+				if rev := (geom.Line{e[1], e[0]}); count[rev] > 0 {
+					count[rev]--
+					continue
+				}
+				count[e]++
+				edges = append(edges, e)
+			}
+		}
+	}
+	bnd := edges[:0]
+	for _, e := range edges {
+		if count[e] > 0 {
+			count[e]--
+			bnd = append(bnd, e)
+		}
+	}
+	if len(bnd) == 0 {
+		return nil, nil
+	}
+
+	rngs, err := linkRings(ctx, bnd)
+	if err != nil {
+		return nil, err
+	}
+	mply := assemblePolygons(newGridSnapper(bnd, FromContext(ctx).PrecisionModel()), rngs)
+	if len(mply) == 0 {
+		return nil, nil
+	}
+	return FromContext(ctx).OrientMultiPolygon(mply), nil
+}
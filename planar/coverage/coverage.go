@@ -0,0 +1,316 @@
+// Package coverage works with the shared boundaries of coverages, sets of
+// polygons that do not overlap and have the same vertices where they
+// touch, such as administrative boundaries. The boundaries are cut into
+// arcs between the points where three or more polygons meet, so a boundary
+// between two polygons is one arc used by both, and changing an arc
+// changes both polygons the same way.
+package coverage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/winding"
+)
+
+// ErrNotPolygonal is returned for geometries that are not polygons or
+// multipolygons.
+var ErrNotPolygonal = errors.New("coverage: geometry is not polygonal")
+
+// None is the side of an arc with none of the geometries on it.
+const None = -1
+
+// Arc is a boundary between two of the geometries, or a geometry and the
+// outside of the coverage.
+type Arc struct {
+	Points [][2]float64
+	// Left and Right are the indexes of the geometries to the left and
+	// right of the arc, going along it, or None
+	Left, Right int
+}
+
+// ArcRef is an arc of a ring. Reversed is whether the ring goes along
+// the arc from its last point to its first.
+type ArcRef struct {
+	Arc      int
+	Reversed bool
+}
+
+// Coverage is the arcs of a set of geometries, and the arcs that make up
+// the rings of each of them.
+type Coverage struct {
+	Arcs []Arc
+	// Polygons are the polygons of each of the geometries, the rings of
+	// each polygon and the arcs of each ring. The outer rings go counter
+	// clockwise and the holes clockwise, so the polygon is to their left.
+	Polygons [][][][]ArcRef
+
+	// multi is whether each of the geometries is a multipolygon
+	multi []bool
+}
+
+// arcKey identifies an arc by its first edge, last point and number of
+// points
+type arcKey struct {
+	first, second, last [2]float64
+	n                   int
+}
+
+// New returns the coverage of the geometries, which are Polygons or
+// MultiPolygons. For the arcs to be shared the polygons must have the same
+// vertices where they touch.
+func New(geoms []geom.Geometry) (*Coverage, error) {
+	cov := &Coverage{
+		Polygons: make([][][][]ArcRef, len(geoms)),
+		multi:    make([]bool, len(geoms)),
+	}
+
+	// the rings of the polygons, without repeated points, wound with the
+	// polygon on the left
+	rings := make([][][][][2]float64, len(geoms))
+	for i, g := range geoms {
+		var plys [][][][2]float64
+		switch gg := g.(type) {
+		case geom.MultiPolygoner:
+			plys = gg.Polygons()
+			cov.multi[i] = true
+		case geom.Polygoner:
+			plys = [][][][2]float64{gg.LinearRings()}
+		default:
+			return nil, ErrNotPolygonal
+		}
+		for _, ply := range plys {
+			var rs [][][2]float64
+			for j, r := range ply {
+				r = dedup(r)
+				if len(r) < 3 {
+					continue
+				}
+				if (winding.SignedArea(r...) > 0) != (j == 0) {
+					r = reverse(r)
+				}
+				rs = append(rs, r)
+			}
+			if len(rs) > 0 {
+				rings[i] = append(rings[i], rs)
+			}
+		}
+	}
+
+	// the nodes are where three or more edges meet
+	edges := make(map[[2][2]float64]bool)
+	degree := make(map[[2]float64]int)
+	for _, plys := range rings {
+		for _, ply := range plys {
+			for _, r := range ply {
+				for j := range r {
+					a, b := r[j], r[(j+1)%len(r)]
+					if less(b, a) {
+						a, b = b, a
+					}
+					if edges[[2][2]float64{a, b}] {
+						continue
+					}
+					edges[[2][2]float64{a, b}] = true
+					degree[a]++
+					degree[b]++
+				}
+			}
+		}
+	}
+	nodes := make(map[[2]float64]bool)
+	for pt, d := range degree {
+		if d > 2 {
+			nodes[pt] = true
+		}
+	}
+
+	arcs := make(map[arcKey]int)
+	for i, plys := range rings {
+		cov.Polygons[i] = make([][][]ArcRef, len(plys))
+		for j, ply := range plys {
+			cov.Polygons[i][j] = make([][]ArcRef, len(ply))
+			for k, r := range ply {
+				cov.Polygons[i][j][k] = cov.addRing(arcs, nodes, i, r)
+			}
+		}
+	}
+	return cov, nil
+}
+
+// addRing adds the arcs of the ring of the geometry i, returning them.
+// The ring starts from its first node, or its smallest point if it has
+// none, so rings that are shared entirely are cut the same way.
+func (cov *Coverage) addRing(arcs map[arcKey]int, nodes map[[2]float64]bool, i int, ring [][2]float64) []ArcRef {
+	start := -1
+	for j, pt := range ring {
+		if nodes[pt] {
+			start = j
+			break
+		}
+	}
+	if start < 0 {
+		start = 0
+		for j, pt := range ring {
+			if less(pt, ring[start]) {
+				start = j
+			}
+		}
+	}
+	pts := make([][2]float64, 0, len(ring)+1)
+	pts = append(pts, ring[start:]...)
+	pts = append(pts, ring[:start+1]...)
+
+	var refs []ArcRef
+	from := 0
+	for j := 1; j < len(pts); j++ {
+		if j < len(pts)-1 && !nodes[pts[j]] {
+			continue
+		}
+		refs = append(refs, cov.addSection(arcs, i, pts[from:j+1])...)
+		from = j
+	}
+	return refs
+}
+
+// addSection adds the section of a ring of the geometry i between two
+// nodes, returning its arcs. Arcs go the way that starts with the smaller
+// end, so a section shared by two rings going opposite ways is the same
+// arc. A loop is two arcs, so neither has the same ends.
+func (cov *Coverage) addSection(arcs map[arcKey]int, i int, sec [][2]float64) []ArcRef {
+	n := len(sec)
+	if sec[0] == sec[n-1] {
+		// split the loop in the middle going the way that starts with the
+		// smaller edge, so rings going either way split it at the same point
+		mid := n / 2
+		if less(sec[n-2], sec[1]) {
+			canon := reverse(sec)
+			return append(cov.addSection(arcs, i, reverse(canon[mid:])), cov.addSection(arcs, i, reverse(canon[:mid+1]))...)
+		}
+		return append(cov.addSection(arcs, i, sec[:mid+1]), cov.addSection(arcs, i, sec[mid:])...)
+	}
+
+	reversed := less(sec[n-1], sec[0])
+	if reversed {
+		sec = reverse(sec)
+	}
+
+	key := arcKey{first: sec[0], second: sec[1], last: sec[n-1], n: n}
+	idx, ok := arcs[key]
+	if !ok {
+		idx = len(cov.Arcs)
+		arcs[key] = idx
+		cov.Arcs = append(cov.Arcs, Arc{
+			Points: append([][2]float64(nil), sec...),
+			Left:   None,
+			Right:  None,
+		})
+	}
+	// the geometry is to the left of its rings
+	if reversed {
+		cov.Arcs[idx].Right = i
+	} else {
+		cov.Arcs[idx].Left = i
+	}
+	return []ArcRef{{Arc: idx, Reversed: reversed}}
+}
+
+// Ring returns the points of the ring made of the arcs, unclosed.
+func (cov *Coverage) Ring(refs []ArcRef) [][2]float64 {
+	var ring [][2]float64
+	for _, ref := range refs {
+		pts := cov.Arcs[ref.Arc].Points
+		if ref.Reversed {
+			pts = reverse(pts)
+		}
+		if len(ring) > 0 {
+			pts = pts[1:]
+		}
+		ring = append(ring, pts...)
+	}
+	if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+		ring = ring[:len(ring)-1]
+	}
+	return ring
+}
+
+// Geometries returns the geometries from the arcs, as Polygons or
+// MultiPolygons as they were given to New. Holes that have collapsed, to
+// fewer than three points or no area, are removed, as are polygons whose
+// outer ring has collapsed.
+func (cov *Coverage) Geometries() []geom.Geometry {
+	geoms := make([]geom.Geometry, len(cov.Polygons))
+	for i, plys := range cov.Polygons {
+		var mply geom.MultiPolygon
+		for _, ply := range plys {
+			var p geom.Polygon
+			for j, refs := range ply {
+				r := dedup(cov.Ring(refs))
+				if len(r) < 3 || winding.SignedArea(r...) == 0 {
+					if j == 0 {
+						break
+					}
+					continue
+				}
+				p = append(p, r)
+			}
+			if len(p) > 0 {
+				mply = append(mply, p)
+			}
+		}
+		switch {
+		case cov.multi[i]:
+			geoms[i] = mply
+		case len(mply) > 0:
+			geoms[i] = geom.Polygon(mply[0])
+		default:
+			geoms[i] = geom.Polygon{}
+		}
+	}
+	return geoms
+}
+
+// Simplify simplifies the geometries of a coverage, simplifying each of
+// the arcs once with the simplifier, see Coverage.Simplify, so no gaps or
+// overlaps open up between them. The geometries are returned as
+// Coverage.Geometries does.
+func Simplify(ctx context.Context, geoms []geom.Geometry, simplifier planar.Simplifer) ([]geom.Geometry, error) {
+	cov, err := New(geoms)
+	if err != nil {
+		return nil, err
+	}
+	if err = cov.Simplify(ctx, simplifier); err != nil {
+		return nil, err
+	}
+	return cov.Geometries(), nil
+}
+
+// dedup removes repeated points, and the last point if it is the same as
+// the first
+func dedup(ring [][2]float64) [][2]float64 {
+	ret := make([][2]float64, 0, len(ring))
+	for _, pt := range ring {
+		if len(ret) > 0 && ret[len(ret)-1] == pt {
+			continue
+		}
+		ret = append(ret, pt)
+	}
+	if len(ret) > 1 && ret[0] == ret[len(ret)-1] {
+		ret = ret[:len(ret)-1]
+	}
+	return ret
+}
+
+func less(a, b [2]float64) bool {
+	return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+}
+
+func reverse(pts [][2]float64) [][2]float64 {
+	ret := make([][2]float64, len(pts))
+	for i, pt := range pts {
+		ret[len(pts)-1-i] = pt
+	}
+	return ret
+}
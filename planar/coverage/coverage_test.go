@@ -0,0 +1,169 @@
+package coverage
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/simplify"
+	"github.com/go-spatial/geom/winding"
+)
+
+func area(g geom.Geometry) float64 {
+	var plys [][][][2]float64
+	switch gg := g.(type) {
+	case geom.Polygon:
+		plys = [][][][2]float64{gg}
+	case geom.MultiPolygon:
+		plys = gg
+	}
+	var a float64
+	for _, ply := range plys {
+		for _, r := range ply {
+			a += winding.SignedArea(r...)
+		}
+	}
+	return a
+}
+
+func TestNew(t *testing.T) {
+	geoms := []geom.Geometry{
+		geom.Polygon{{{0, 0}, {1, 0}, {1, 0.5}, {1, 1}, {0, 1}}},
+		// clockwise, and closed
+		geom.MultiPolygon{{{{1, 0}, {1, 0.5}, {1, 1}, {2, 1}, {2, 0}, {1, 0}}}},
+	}
+	cov, err := New(geoms)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if len(cov.Arcs) != 3 {
+		t.Fatalf("arcs, expected 3 got %v", cov.Arcs)
+	}
+	shared := Arc{Points: [][2]float64{{1, 0}, {1, 0.5}, {1, 1}}, Left: 0, Right: 1}
+	found := false
+	for _, arc := range cov.Arcs {
+		if reflect.DeepEqual(arc, shared) {
+			found = true
+			continue
+		}
+		if (arc.Left == None) == (arc.Right == None) {
+			t.Errorf("arc %v, expected one side outside", arc)
+		}
+	}
+	if !found {
+		t.Errorf("arcs, expected %v in %v", shared, cov.Arcs)
+	}
+
+	got := cov.Geometries()
+	if _, ok := got[1].(geom.MultiPolygon); !ok {
+		t.Errorf("geometry 1, expected a multipolygon got %T", got[1])
+	}
+	for i := range geoms {
+		if a := area(got[i]); a != 1 {
+			t.Errorf("area %v, expected 1 got %v", i, a)
+		}
+	}
+
+	if _, err = New([]geom.Geometry{geom.Point{1, 1}}); err != ErrNotPolygonal {
+		t.Errorf("error, expected %v got %v", ErrNotPolygonal, err)
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	type tcase struct {
+		geoms     []geom.Geometry
+		tolerance float64
+		// vertices is the expected number of vertices of the rings of
+		// each geometry
+		vertices []int
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctx := context.Background()
+			got, err := Simplify(ctx, tc.geoms, simplify.DouglasPeucker{Tolerance: tc.tolerance})
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			var sum, exp float64
+			for i, g := range got {
+				var n int
+				for _, r := range g.(geom.Polygon) {
+					n += len(r)
+				}
+				if n != tc.vertices[i] {
+					t.Errorf("vertices of %v, expected %v got %v: %v", i, tc.vertices[i], n, g)
+				}
+				sum += area(g)
+				exp += area(tc.geoms[i])
+			}
+			// the shared boundaries match, so the union is one polygon with
+			// the area of all of them
+			union, err := planar.CoverageUnion(ctx, got)
+			if err != nil {
+				t.Fatalf("union error, expected nil got %v", err)
+			}
+			if len(union) != 1 || math.Abs(area(union)-sum) > 1e-9 {
+				t.Errorf("union, expected one polygon of area %v got %v", sum, union)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"wiggly": {
+			// the shared boundary wiggles, within the tolerance
+			geoms: []geom.Geometry{
+				geom.Polygon{{{0, 0}, {5, 0}, {5.1, 2}, {4.9, 4}, {5.1, 6}, {5, 8}, {5, 10}, {0, 10}}},
+				geom.Polygon{{{5, 0}, {10, 0}, {10, 10}, {5, 10}, {5, 8}, {5.1, 6}, {4.9, 4}, {5.1, 2}}},
+			},
+			tolerance: 0.5,
+			vertices:  []int{4, 4},
+		},
+		"crossing hole": {
+			// simplifying away the peak of the top would cross the hole
+			geoms: []geom.Geometry{
+				geom.Polygon{
+					{{0, 0}, {10, 0}, {10, 10}, {5, 12}, {0, 10}},
+					{{3, 8}, {5, 11.5}, {7, 8}, {5, 6}},
+				},
+				geom.Polygon{{{10, 0}, {20, 0}, {20, 10}, {10, 10}}},
+			},
+			tolerance: 2.5,
+			vertices:  []int{8, 4},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestCross(t *testing.T) {
+	type tcase struct {
+		a, b geom.Line
+		exp  bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if got := cross(tc.a, tc.b); got != tc.exp {
+				t.Errorf("cross, expected %v got %v", tc.exp, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"crossing":   {a: geom.Line{{0, 0}, {2, 2}}, b: geom.Line{{0, 2}, {2, 0}}, exp: true},
+		"shared end": {a: geom.Line{{0, 0}, {2, 2}}, b: geom.Line{{2, 2}, {4, 0}}},
+		"touching":   {a: geom.Line{{0, 0}, {2, 0}}, b: geom.Line{{1, 0}, {1, 2}}, exp: true},
+		"overlap":    {a: geom.Line{{0, 0}, {2, 0}}, b: geom.Line{{0, 0}, {1, 0}}, exp: true},
+		"apart":      {a: geom.Line{{0, 0}, {2, 0}}, b: geom.Line{{0, 1}, {2, 1}}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
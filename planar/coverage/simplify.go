@@ -0,0 +1,136 @@
+package coverage
+
+import (
+	"context"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/index/rtree"
+)
+
+// Simplify simplifies each of the arcs with the simplifier, which must
+// keep the end points of the lines it is given, as the simplify package's
+// DouglasPeucker and VisvalingamWhyatt do. As the arcs are shared no gaps
+// or overlaps open up between the geometries. Arcs whose simplified form
+// would cross another arc, or itself, are kept as they were, so no slivers
+// appear where simplified boundaries cross.
+func (cov *Coverage) Simplify(ctx context.Context, simplifier planar.Simplifer) error {
+	simplified := make([][][2]float64, len(cov.Arcs))
+	for i, arc := range cov.Arcs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if len(arc.Points) <= 2 {
+			simplified[i] = arc.Points
+			continue
+		}
+		pts, err := simplifier.Simplify(ctx, arc.Points, false)
+		if err != nil {
+			return err
+		}
+		simplified[i] = pts
+	}
+
+	// keep the arcs that cross as they were until none do, the original
+	// arcs do not cross each other
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		crossing := crossingArcs(simplified)
+		if len(crossing) == 0 {
+			break
+		}
+		for i := range crossing {
+			simplified[i] = cov.Arcs[i].Points
+		}
+	}
+	for i := range cov.Arcs {
+		cov.Arcs[i].Points = simplified[i]
+	}
+	return nil
+}
+
+// segmentRef is a segment of an arc
+type segmentRef struct {
+	arc, seg int
+	line     geom.Line
+}
+
+// crossingArcs returns the arcs that cross another or themselves
+func crossingArcs(arcs [][][2]float64) map[int]bool {
+	var items []rtree.Item
+	for i, pts := range arcs {
+		for j := 1; j < len(pts); j++ {
+			ln := geom.Line{pts[j-1], pts[j]}
+			items = append(items, rtree.Item{
+				Extent: *geom.NewExtent(ln[:]...),
+				Data:   segmentRef{arc: i, seg: j - 1, line: ln},
+			})
+		}
+	}
+	tree := rtree.New(rtree.DefaultMaxEntries)
+	tree.Load(items)
+
+	crossing := make(map[int]bool)
+	for _, it := range items {
+		a := it.Data.(segmentRef)
+		tree.Search(it.Extent, func(other rtree.Item) bool {
+			b := other.Data.(segmentRef)
+			if b.arc < a.arc || (b.arc == a.arc && b.seg <= a.seg) {
+				// each pair once
+				return true
+			}
+			if b.arc == a.arc && b.seg == a.seg+1 {
+				// the next segment shares an end
+				return true
+			}
+			if cross(a.line, b.line) {
+				crossing[a.arc] = true
+				crossing[b.arc] = true
+			}
+			return true
+		})
+	}
+	return crossing
+}
+
+func orient(a, b, c [2]float64) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+// onSegment reports whether the point, which is on the line through the
+// segment, is inside it rather than at or beyond its ends
+func onSegment(pt [2]float64, s geom.Line) bool {
+	if pt == s[0] || pt == s[1] {
+		return false
+	}
+	return pt[0] >= min(s[0][0], s[1][0]) && pt[0] <= max(s[0][0], s[1][0]) &&
+		pt[1] >= min(s[0][1], s[1][1]) && pt[1] <= max(s[0][1], s[1][1])
+}
+
+// cross reports whether the segments cross or touch anywhere but at
+// shared ends
+func cross(a, b geom.Line) bool {
+	d1, d2 := orient(b[0], b[1], a[0]), orient(b[0], b[1], a[1])
+	d3, d4 := orient(a[0], a[1], b[0]), orient(a[0], a[1], b[1])
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (d1 == 0 && onSegment(a[0], b)) || (d2 == 0 && onSegment(a[1], b)) ||
+		(d3 == 0 && onSegment(b[0], a)) || (d4 == 0 && onSegment(b[1], a))
+}
+
+func min(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,114 @@
+package planar
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+// testGrid returns the unit squares of an n by n grid, but for those skip
+// says to leave out
+func testGrid(n int, skip func(x, y int) bool) []geom.Geometry {
+	var geoms []geom.Geometry
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			if skip != nil && skip(x, y) {
+				continue
+			}
+			fx, fy := float64(x), float64(y)
+			geoms = append(geoms, geom.Polygon{{{fx, fy}, {fx + 1, fy}, {fx + 1, fy + 1}, {fx, fy + 1}}})
+		}
+	}
+	return geoms
+}
+
+func TestCoverageUnion(t *testing.T) {
+	type tcase struct {
+		geoms []geom.Geometry
+		area  float64
+		// polygons and rings are the expected number of polygons and
+		// rings of all of them
+		polygons, rings int
+		// vertices is the expected number of vertices of all the rings
+		vertices int
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := CoverageUnion(context.Background(), tc.geoms)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if len(got) != tc.polygons {
+				t.Errorf("polygons, expected %v got %v", tc.polygons, len(got))
+			}
+			var rings, vertices int
+			for _, ply := range got {
+				rings += len(ply)
+				for _, r := range ply {
+					vertices += len(r)
+				}
+			}
+			if rings != tc.rings {
+				t.Errorf("rings, expected %v got %v", tc.rings, rings)
+			}
+			if vertices != tc.vertices {
+				t.Errorf("vertices, expected %v got %v", tc.vertices, vertices)
+			}
+			if a := testArea(got); math.Abs(a-tc.area) > 1e-9 {
+				t.Errorf("area, expected %v got %v", tc.area, a)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {},
+		"grid": {
+			geoms:    testGrid(3, nil),
+			area:     9,
+			polygons: 1,
+			rings:    1,
+			vertices: 4,
+		},
+		"hole": {
+			geoms:    testGrid(3, func(x, y int) bool { return x == 1 && y == 1 }),
+			area:     8,
+			polygons: 1,
+			rings:    2,
+			vertices: 8,
+		},
+		"apart": {
+			geoms:    testGrid(3, func(x, y int) bool { return x == 1 }),
+			area:     6,
+			polygons: 2,
+			rings:    2,
+			vertices: 8,
+		},
+		"multipolygon and clockwise": {
+			geoms: []geom.Geometry{
+				geom.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}}, {{{2, 0}, {3, 0}, {3, 1}, {2, 1}}}},
+				geom.Polygon{{{1, 0}, {1, 1}, {2, 1}, {2, 0}}},
+			},
+			area:     3,
+			polygons: 1,
+			rings:    1,
+			vertices: 4,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func BenchmarkCoverageUnion(b *testing.B) {
+	geoms := testGrid(100, nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := CoverageUnion(context.Background(), geoms); err != nil {
+			b.Fatalf("error, expected nil got %v", err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package planar
+
+import (
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/spherical"
+)
+
+// ErrInvalidSegmentLength is returned by Densify for lengths that are not
+// positive.
+var ErrInvalidSegmentLength = errors.New("planar: invalid segment length")
+
+// DensifyOption is an option for Densify.
+type DensifyOption func(*densifyOptions)
+
+type densifyOptions struct {
+	ellipsoid *spherical.Ellipsoid
+}
+
+// WithGeodesics makes Densify follow the geodesics of the ellipsoid
+// between the points, which are longitudes and latitudes in degrees, with
+// lengths in the units of the ellipsoid, such as meters for
+// spherical.WGS84. On a sphere the geodesics are great circles.
+func WithGeodesics(e spherical.Ellipsoid) DensifyOption {
+	return func(o *densifyOptions) { o.ellipsoid = &e }
+}
+
+// Densify returns a copy of the geometry with points added along its
+// segments, so none is longer than maxSegmentLength. Each segment is split
+// into the fewest equal parts needed. The segments are straight lines, or
+// with WithGeodesics, geodesics; densifying lines that follow geodesics
+// before projecting them keeps them following the geodesics afterwards.
+//
+// The geometry given is not modified. Lines, LineStrings, Triangles,
+// Polygons, their Multi forms and collections of them are supported, and
+// returned as LineStrings, Polygons, MultiLineStrings, MultiPolygons and
+// Collections; points are returned as they are. The segments closing
+// rings that are not closed are densified too. Geometries with z or m
+// values are not supported, ErrUnknownGeometry is returned for them, as
+// the values of the added points are not known. ErrInvalidSegmentLength
+// is returned if maxSegmentLength is not positive.
+func Densify(g geom.Geometry, maxSegmentLength float64, opts ...DensifyOption) (geom.Geometry, error) {
+	if !(maxSegmentLength > 0) {
+		return nil, ErrInvalidSegmentLength
+	}
+	d := densifier{max: maxSegmentLength}
+	for _, opt := range opts {
+		opt(&d.densifyOptions)
+	}
+	return d.geometry(g)
+}
+
+type densifier struct {
+	densifyOptions
+	max float64
+}
+
+func (d densifier) geometry(g geom.Geometry) (geom.Geometry, error) {
+	switch gg := g.(type) {
+	case geom.Pointer, geom.MultiPointer:
+		return gg, nil
+	case geom.LineStringer:
+		ln, err := d.points(gg.Vertices(), false)
+		return geom.LineString(ln), err
+	case geom.MultiLineStringer:
+		lns, err := d.lines(gg.LineStrings(), false)
+		return geom.MultiLineString(lns), err
+	case geom.Polygoner:
+		rings, err := d.lines(gg.LinearRings(), true)
+		return geom.Polygon(rings), err
+	case geom.MultiPolygoner:
+		plys := gg.Polygons()
+		mply := make(geom.MultiPolygon, len(plys))
+		for i := range plys {
+			var err error
+			if mply[i], err = d.lines(plys[i], true); err != nil {
+				return nil, err
+			}
+		}
+		return mply, nil
+	case geom.Collectioner:
+		geos := gg.Geometries()
+		col := make(geom.Collection, len(geos))
+		for i := range geos {
+			var err error
+			if col[i], err = d.geometry(geos[i]); err != nil {
+				return nil, err
+			}
+		}
+		return col, nil
+	default:
+		return nil, geom.ErrUnknownGeometry{Geom: g}
+	}
+}
+
+func (d densifier) lines(lns [][][2]float64, closed bool) ([][][2]float64, error) {
+	ret := make([][][2]float64, len(lns))
+	for i := range lns {
+		var err error
+		if ret[i], err = d.points(lns[i], closed); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// points returns the densified points, including the segment from the
+// last point back to the first if closed
+func (d densifier) points(pts [][2]float64, closed bool) ([][2]float64, error) {
+	if len(pts) == 0 {
+		return nil, nil
+	}
+	ret := make([][2]float64, 0, len(pts))
+	n := len(pts) - 1
+	if closed && pts[0] != pts[n] {
+		n++
+	}
+	for i := 0; i < n; i++ {
+		ret = append(ret, pts[i])
+		var err error
+		if ret, err = d.segment(ret, pts[i], pts[(i+1)%len(pts)]); err != nil {
+			return nil, err
+		}
+	}
+	if n < len(pts) {
+		ret = append(ret, pts[n])
+	}
+	return ret, nil
+}
+
+// segment appends the points between a and b
+func (d densifier) segment(pts [][2]float64, a, b [2]float64) ([][2]float64, error) {
+	if d.ellipsoid == nil {
+		parts := math.Ceil(math.Hypot(b[0]-a[0], b[1]-a[1]) / d.max)
+		for k := 1.0; k < parts; k++ {
+			t := k / parts
+			pts = append(pts, [2]float64{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])})
+		}
+		return pts, nil
+	}
+
+	dist, bearing, _, err := d.ellipsoid.Inverse(a, b)
+	if err != nil {
+		return nil, err
+	}
+	parts := math.Ceil(dist / d.max)
+	for k := 1.0; k < parts; k++ {
+		pt, _ := d.ellipsoid.Destination(a, bearing, dist*k/parts)
+		pts = append(pts, pt)
+	}
+	return pts, nil
+}
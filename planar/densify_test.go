@@ -0,0 +1,130 @@
+package planar
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/spherical"
+)
+
+func TestDensify(t *testing.T) {
+	type tcase struct {
+		g        geom.Geometry
+		max      float64
+		expected geom.Geometry
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Densify(tc.g, tc.max)
+			if err != tc.err {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+				return
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("densify, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			g:        geom.Point{1, 2},
+			max:      1,
+			expected: geom.Point{1, 2},
+		},
+		"line": {
+			g:        geom.Line{{0, 0}, {4, 0}},
+			max:      1.5,
+			expected: geom.LineString{{0, 0}, {4.0 / 3, 0}, {8.0 / 3, 0}, {4, 0}},
+		},
+		"short segments": {
+			g:        geom.LineString{{0, 0}, {1, 0}, {1, 1}},
+			max:      1,
+			expected: geom.LineString{{0, 0}, {1, 0}, {1, 1}},
+		},
+		"multi line string": {
+			g:        geom.MultiLineString{{{0, 0}, {0, 2}}, {{1, 1}}},
+			max:      1,
+			expected: geom.MultiLineString{{{0, 0}, {0, 1}, {0, 2}}, {{1, 1}}},
+		},
+		"polygon not closed": {
+			g:        geom.Polygon{{{0, 0}, {2, 0}, {2, 2}}},
+			max:      2,
+			expected: geom.Polygon{{{0, 0}, {2, 0}, {2, 2}, {1, 1}}},
+		},
+		"polygon closed": {
+			g:        geom.Polygon{{{0, 0}, {2, 0}, {0, 2}, {0, 0}}},
+			max:      1.5,
+			expected: geom.Polygon{{{0, 0}, {1, 0}, {2, 0}, {1, 1}, {0, 2}, {0, 1}, {0, 0}}},
+		},
+		"collection": {
+			g:   geom.Collection{geom.MultiPoint{{0, 0}}, geom.MultiPolygon{{{{0, 0}, {2, 0}, {0, 2}}}}},
+			max: 2,
+			expected: geom.Collection{
+				geom.MultiPoint{{0, 0}},
+				geom.MultiPolygon{{{{0, 0}, {2, 0}, {1, 1}, {0, 2}}}},
+			},
+		},
+		"zero length": {
+			g:   geom.Line{{0, 0}, {1, 0}},
+			max: 0,
+			err: ErrInvalidSegmentLength,
+		},
+		"nan length": {
+			g:   geom.Line{{0, 0}, {1, 0}},
+			max: math.NaN(),
+			err: ErrInvalidSegmentLength,
+		},
+		"point z": {
+			g:   geom.PointZ{1, 2, 3},
+			max: 1,
+			err: geom.ErrUnknownGeometry{Geom: geom.PointZ{1, 2, 3}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDensifyWithGeodesics(t *testing.T) {
+	e := spherical.Sphere(6371000)
+	// along the equator, the great circle is the same as the straight line
+	got, err := Densify(geom.Line{{0, 0}, {90, 0}}, 2600000, WithGeodesics(e))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	ln := got.(geom.LineString)
+	if len(ln) != 5 {
+		t.Fatalf("points, expected 5 got %v", len(ln))
+	}
+	for i, pt := range ln {
+		if math.Abs(pt[0]-22.5*float64(i)) > 1e-9 || math.Abs(pt[1]) > 1e-9 {
+			t.Errorf("point %v, expected %v got %v", i, [2]float64{22.5 * float64(i), 0}, pt)
+		}
+	}
+
+	// between points at the same latitude, the great circle goes nearer the pole
+	got, err = Densify(geom.Line{{-45, 45}, {45, 45}}, 1000000, WithGeodesics(e))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	ln = got.(geom.LineString)
+	if len(ln) < 3 {
+		t.Fatalf("points, expected more than 2 got %v", len(ln))
+	}
+	for i := 1; i < len(ln); i++ {
+		d, _ := e.Distance(ln[i-1], ln[i])
+		if d > 1000000*(1+1e-9) {
+			t.Errorf("segment %v, expected at most 1000000 got %v", i, d)
+		}
+	}
+	mid := ln[len(ln)/2]
+	if len(ln)%2 == 1 && (math.Abs(mid[0]) > 1e-9 || mid[1] <= 54) {
+		t.Errorf("middle, expected north of 54 got %v", mid)
+	}
+}
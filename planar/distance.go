@@ -1,6 +1,7 @@
 package planar
 
 import (
+	"errors"
 	"math"
 
 	"github.com/go-spatial/geom"
@@ -48,3 +49,108 @@ func DistanceToLineSegment(p geom.Pointer, v geom.Pointer, w geom.Pointer) float
 	t = math.Max(0, math.Min(1, t))
 	return PointDistance(p, geom.Point{vx + t*(wx-vx), vy + t*(wy-vy)})
 }
+
+// ErrEmptyGeometry is returned when a distance is asked for between
+// geometries and one of them has no points, or a point is asked for of a
+// geometry with no points.
+var ErrEmptyGeometry = errors.New("planar: empty geometry")
+
+// vertices returns the vertices of the geometry, in order, without repeated
+// points
+func vertices(g geom.Geometry) ([][2]float64, error) {
+	var parts geomParts
+	if err := parts.add(g); err != nil {
+		return nil, err
+	}
+	pts := parts.vertices()
+	if len(pts) == 0 {
+		return nil, ErrEmptyGeometry
+	}
+	return pts, nil
+}
+
+// HausdorffDistance returns the discrete Hausdorff distance between the
+// geometries: the furthest any vertex of one of them is from the nearest
+// vertex of the other. It is how far the geometries are from matching.
+// Only the vertices are compared, so the distance between the shapes
+// themselves may be less; geometries with long segments should be
+// densified first.
+//
+// Points, lines, polygons, their Multi forms and collections of them are
+// supported; z and m values are ignored. ErrEmptyGeometry is returned if
+// either has no points.
+func HausdorffDistance(a, b geom.Geometry) (float64, error) {
+	apts, err := vertices(a)
+	if err != nil {
+		return 0, err
+	}
+	bpts, err := vertices(b)
+	if err != nil {
+		return 0, err
+	}
+	return math.Sqrt(math.Max(directedHausdorff2(apts, bpts), directedHausdorff2(bpts, apts))), nil
+}
+
+// directedHausdorff2 returns the square of the furthest any of the points
+// of a is from its nearest point of b
+func directedHausdorff2(a, b [][2]float64) float64 {
+	var max float64
+	for _, p := range a {
+		min := math.Inf(1)
+		for _, q := range b {
+			d := (p[0]-q[0])*(p[0]-q[0]) + (p[1]-q[1])*(p[1]-q[1])
+			if d < min {
+				min = d
+			}
+			if min <= max {
+				// this point can not be the furthest
+				break
+			}
+		}
+		if min > max {
+			max = min
+		}
+	}
+	return max
+}
+
+// FrechetDistance returns the discrete Fréchet distance between the
+// vertices of the geometries, taken in order: the shortest leash needed
+// to walk along both, from the first vertex to the last, without going
+// backwards on either. Unlike HausdorffDistance it takes the direction of
+// the geometries into account, which makes it suited to comparing
+// trajectories, or a line and its simplification.
+//
+// The vertices of geometries other than lines are taken in the order they
+// are in the geometry, with the rings of polygons not closed; z and m
+// values are ignored. ErrEmptyGeometry is returned if either has no points.
+func FrechetDistance(a, b geom.Geometry) (float64, error) {
+	apts, err := vertices(a)
+	if err != nil {
+		return 0, err
+	}
+	bpts, err := vertices(b)
+	if err != nil {
+		return 0, err
+	}
+
+	// the leash needed to reach a[i] and b[j], one row of i at a time
+	prev, cur := make([]float64, len(bpts)), make([]float64, len(bpts))
+	for i, p := range apts {
+		for j, q := range bpts {
+			d := math.Hypot(p[0]-q[0], p[1]-q[1])
+			switch {
+			case i == 0 && j == 0:
+				cur[j] = d
+			case i == 0:
+				cur[j] = math.Max(cur[j-1], d)
+			case j == 0:
+				cur[j] = math.Max(prev[j], d)
+			default:
+				cur[j] = math.Max(math.Min(math.Min(prev[j], prev[j-1]), cur[j-1]), d)
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(bpts)-1], nil
+}
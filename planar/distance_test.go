@@ -2,6 +2,7 @@ package planar
 
 import (
 	"math"
+	"reflect"
 	"strconv"
 	"testing"
 
@@ -76,3 +77,77 @@ func TestPointDistance(t *testing.T) {
 		t.Run(strconv.FormatInt(int64(i), 10), func(t *testing.T) { fn(t, tc) })
 	}
 }
+
+func TestHausdorffFrechetDistance(t *testing.T) {
+	type tcase struct {
+		a, b      geom.Geometry
+		hausdorff float64
+		frechet   float64
+		err       error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			d, err := HausdorffDistance(tc.a, tc.b)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("hausdorff error, expected %v got %v", tc.err, err)
+			}
+			if math.Abs(d-tc.hausdorff) > 1e-9 {
+				t.Errorf("hausdorff, expected %v got %v", tc.hausdorff, d)
+			}
+			d, err = FrechetDistance(tc.a, tc.b)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("frechet error, expected %v got %v", tc.err, err)
+			}
+			if math.Abs(d-tc.frechet) > 1e-9 {
+				t.Errorf("frechet, expected %v got %v", tc.frechet, d)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"same": {
+			a: geom.LineString{{0, 0}, {1, 1}, {2, 0}},
+			b: geom.LineString{{0, 0}, {1, 1}, {2, 0}},
+		},
+		"parallel": {
+			a:         geom.LineString{{0, 0}, {1, 0}, {2, 0}},
+			b:         geom.LineString{{0, 1}, {1, 1}, {2, 1}},
+			hausdorff: 1,
+			frechet:   1,
+		},
+		"reversed": {
+			a:         geom.LineString{{0, 0}, {1, 0}, {2, 0}},
+			b:         geom.LineString{{2, 0}, {1, 0}, {0, 0}},
+			hausdorff: 0,
+			frechet:   2,
+		},
+		"simplified": {
+			a:         geom.LineString{{0, 0}, {1, 0.5}, {2, 0}, {3, 0.5}, {4, 0}},
+			b:         geom.LineString{{0, 0}, {4, 0}},
+			hausdorff: 2,
+			frechet:   2,
+		},
+		"points": {
+			a:         geom.Point{0, 0},
+			b:         geom.MultiPoint{{0, 0}, {3, 4}},
+			hausdorff: 5,
+			frechet:   5,
+		},
+		"empty": {
+			a:   geom.Point{0, 0},
+			b:   geom.LineString{},
+			err: ErrEmptyGeometry,
+		},
+		"z": {
+			a:         geom.PointZ{0, 0, 5},
+			b:         geom.Point{3, 4},
+			hausdorff: 5,
+			frechet:   5,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,288 @@
+// Package generalize prepares geometries for drawing at a map zoom, such as
+// for a tile, by clipping them, simplifying them with a tolerance from the
+// zoom, dropping the lines and polygons too small to see, and snapping
+// them to a grid, in that order.
+//
+// Sizes are given in pixels, and turned into the units of the geometries
+// by the size of a pixel at the zoom, which is that of Web Mercator meters
+// unless it is set.
+package generalize
+
+import (
+	"context"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/clip"
+	"github.com/go-spatial/geom/planar/simplify"
+	"github.com/go-spatial/geom/proj/webmercator"
+	"github.com/go-spatial/geom/slippy"
+	"github.com/go-spatial/geom/winding"
+)
+
+// Rule is how the geometries of a type are generalized. Sizes are in
+// pixels; zero turns the step off.
+type Rule struct {
+	// Tolerance is the tolerance the lines and rings are simplified with
+	Tolerance float64
+	// MinLength is the length lines must be to be kept
+	MinLength float64
+	// MinArea is the area, in square pixels, polygons and holes must be to
+	// be kept
+	MinArea float64
+	// GridSize is the size of the grid the points are snapped to
+	GridSize float64
+	// Simplifier returns the simplifier for the tolerance in the units of
+	// the geometries; nil is simplify.DouglasPeucker.
+	Simplifier func(tolerance float64) planar.Simplifer
+}
+
+// Generalizer generalizes geometries for a zoom.
+type Generalizer struct {
+	// Zoom is the zoom the geometries are generalized for, which may be
+	// fractional
+	Zoom float64
+	// PixelSize is the size of a pixel at zoom 0 in the units of the
+	// geometries; zero is that of Web Mercator,
+	// webmercator.ResolutionAtZoom(0).
+	PixelSize float64
+	// Clip is the extent the geometries are clipped to; nil does not clip
+	Clip *geom.Extent
+
+	// Points is the rule for Points and MultiPoints, of which only
+	// GridSize is used
+	Points Rule
+	// Lines is the rule for LineStrings and MultiLineStrings, of which
+	// MinArea is not used
+	Lines Rule
+	// Polygons is the rule for Polygons and MultiPolygons, of which
+	// MinLength is not used
+	Polygons Rule
+}
+
+// DefaultRule simplifies with a tolerance of a pixel, drops what is
+// smaller than a pixel, and snaps to the 4096 by 4096 grid of a 256 pixel
+// vector tile.
+var DefaultRule = Rule{
+	Tolerance: 1,
+	MinLength: 1,
+	MinArea:   1,
+	GridSize:  1.0 / 16,
+}
+
+// New returns a Generalizer for Web Mercator geometries at the zoom,
+// clipped to the extent, with the DefaultRule for every type.
+func New(zoom float64, clipbox *geom.Extent) Generalizer {
+	return Generalizer{
+		Zoom:     zoom,
+		Clip:     clipbox,
+		Points:   DefaultRule,
+		Lines:    DefaultRule,
+		Polygons: DefaultRule,
+	}
+}
+
+// ForTile returns a Generalizer for Web Mercator geometries for the tile,
+// clipped to its extent grown by the buffer in pixels, with the
+// DefaultRule for every type.
+func ForTile(tile *slippy.Tile, buffer float64) Generalizer {
+	g := New(float64(tile.Z), nil)
+	g.Clip = tile.Extent3857().ExpandBy(buffer * g.pixel())
+	return g
+}
+
+// pixel returns the size of a pixel at the zoom in the units of the
+// geometries
+func (g Generalizer) pixel() float64 {
+	size := g.PixelSize
+	if size == 0 {
+		size = webmercator.ResolutionAtZoom(0)
+	}
+	return size / math.Exp2(g.Zoom)
+}
+
+// Generalize returns the geometry generalized. Points and MultiPoints are
+// returned as clip.Geometry returns them, lines as MultiLineStrings, and
+// Polygons and MultiPolygons keep their types; collections are generalized
+// geometry by geometry, dropping those that are left empty. A nil
+// geometry is returned if nothing of it is left. Geometries with Z or M
+// values are not supported.
+func (g Generalizer) Generalize(ctx context.Context, geo geom.Geometry) (geom.Geometry, error) {
+	if geo == nil {
+		return nil, nil
+	}
+	if col, ok := geo.(geom.Collectioner); ok {
+		var out geom.Collection
+		for _, gg := range col.Geometries() {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			gen, err := g.Generalize(ctx, gg)
+			if err != nil {
+				return nil, err
+			}
+			if gen != nil {
+				out = append(out, gen)
+			}
+		}
+		if len(out) == 0 {
+			return nil, nil
+		}
+		return out, nil
+	}
+
+	clipped, err := clip.Geometry(ctx, geo, g.Clip)
+	if err != nil {
+		return nil, err
+	}
+	pixel := g.pixel()
+
+	switch c := clipped.(type) {
+	case nil:
+		return nil, nil
+
+	case geom.Point:
+		return geom.Point(snap(g.Points, pixel, c)), nil
+
+	case geom.MultiPoint:
+		if len(c) == 0 {
+			return nil, nil
+		}
+		mp := make(geom.MultiPoint, len(c))
+		for i, pt := range c {
+			mp[i] = snap(g.Points, pixel, pt)
+		}
+		return mp, nil
+
+	case geom.LineString:
+		mls, err := g.lines(ctx, pixel, [][][2]float64{c})
+		if err != nil || len(mls) == 0 {
+			return nil, err
+		}
+		return mls, nil
+
+	case geom.MultiLineString:
+		mls, err := g.lines(ctx, pixel, c)
+		if err != nil || len(mls) == 0 {
+			return nil, err
+		}
+		return mls, nil
+
+	case geom.Polygon:
+		ply, err := g.polygon(ctx, pixel, c)
+		if err != nil || ply == nil {
+			return nil, err
+		}
+		return ply, nil
+
+	case geom.MultiPolygon:
+		var mp geom.MultiPolygon
+		for _, p := range c {
+			ply, err := g.polygon(ctx, pixel, p)
+			if err != nil {
+				return nil, err
+			}
+			if ply != nil {
+				mp = append(mp, ply)
+			}
+		}
+		if len(mp) == 0 {
+			return nil, nil
+		}
+		return mp, nil
+
+	default:
+		return nil, geom.ErrUnknownGeometry{Geom: geo}
+	}
+}
+
+// lines returns the lines simplified, without those too short, and snapped
+func (g Generalizer) lines(ctx context.Context, pixel float64, lines [][][2]float64) (geom.MultiLineString, error) {
+	var mls geom.MultiLineString
+	for _, ln := range lines {
+		ln, err := simplifyLine(ctx, g.Lines, pixel, ln, false)
+		if err != nil {
+			return nil, err
+		}
+		if length(ln) < g.Lines.MinLength*pixel {
+			continue
+		}
+		if ln = snapLine(g.Lines, pixel, ln); len(ln) >= 2 {
+			mls = append(mls, ln)
+		}
+	}
+	return mls, nil
+}
+
+// polygon returns the polygon simplified, without the rings too small,
+// and snapped; nil is returned if the outer ring is dropped.
+func (g Generalizer) polygon(ctx context.Context, pixel float64, ply [][][2]float64) (geom.Polygon, error) {
+	var out geom.Polygon
+	for i, ring := range ply {
+		ring, err := simplifyLine(ctx, g.Polygons, pixel, ring, true)
+		if err != nil {
+			return nil, err
+		}
+		ring = snapLine(g.Polygons, pixel, ring)
+		// a closed ring repeats its first point at the end
+		if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+			ring = ring[:len(ring)-1]
+		}
+		if len(ring) < 3 || math.Abs(winding.SignedArea(ring...)) < g.Polygons.MinArea*pixel*pixel {
+			if i == 0 {
+				return nil, nil
+			}
+			continue
+		}
+		out = append(out, ring)
+	}
+	return out, nil
+}
+
+// simplifyLine returns the line simplified with the tolerance of the rule
+func simplifyLine(ctx context.Context, r Rule, pixel float64, ln [][2]float64, closed bool) ([][2]float64, error) {
+	if r.Tolerance <= 0 {
+		return ln, nil
+	}
+	tol := r.Tolerance * pixel
+	var s planar.Simplifer = simplify.DouglasPeucker{Tolerance: tol}
+	if r.Simplifier != nil {
+		s = r.Simplifier(tol)
+	}
+	return s.Simplify(ctx, ln, closed)
+}
+
+// snap returns the point snapped to the grid of the rule
+func snap(r Rule, pixel float64, pt [2]float64) [2]float64 {
+	if r.GridSize <= 0 {
+		return pt
+	}
+	return geom.NewPrecisionModelGridSize(r.GridSize * pixel).SnapPoint(pt)
+}
+
+// snapLine returns the points snapped to the grid of the rule, without
+// the points snapped onto the one before them.
+func snapLine(r Rule, pixel float64, ln [][2]float64) [][2]float64 {
+	if r.GridSize <= 0 {
+		return ln
+	}
+	out := make([][2]float64, 0, len(ln))
+	for _, pt := range ln {
+		pt = snap(r, pixel, pt)
+		if len(out) > 0 && out[len(out)-1] == pt {
+			continue
+		}
+		out = append(out, pt)
+	}
+	return out
+}
+
+// length returns the length of the line
+func length(ln [][2]float64) float64 {
+	var l float64
+	for i := 1; i < len(ln); i++ {
+		l += math.Hypot(ln[i][0]-ln[i-1][0], ln[i][1]-ln[i-1][1])
+	}
+	return l
+}
@@ -0,0 +1,163 @@
+package generalize
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/simplify"
+	"github.com/go-spatial/geom/slippy"
+)
+
+func TestGeneralize(t *testing.T) {
+	type tcase struct {
+		gen      Generalizer
+		g        geom.Geometry
+		expected geom.Geometry
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := tc.gen.Generalize(context.Background(), tc.g)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("geometry, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	// pixels the size of the units of the geometries, clipped to 0,0 10,10
+	unit := func(rule Rule) Generalizer {
+		return Generalizer{
+			PixelSize: 1,
+			Clip:      &geom.Extent{0, 0, 10, 10},
+			Points:    rule,
+			Lines:     rule,
+			Polygons:  rule,
+		}
+	}
+	zoomed := unit(DefaultRule)
+	zoomed.Zoom = 2
+	vw := unit(Rule{
+		Tolerance: 2,
+		Simplifier: func(tol float64) planar.Simplifer {
+			return simplify.VisvalingamWhyatt{Tolerance: tol}
+		},
+	})
+
+	tests := map[string]tcase{
+		"nil": {
+			gen: unit(DefaultRule),
+		},
+		"point snapped": {
+			gen:      unit(Rule{GridSize: 0.5}),
+			g:        geom.Point{1.2, 3.3},
+			expected: geom.Point{1, 3.5},
+		},
+		"point outside": {
+			gen: unit(DefaultRule),
+			g:   geom.Point{11, 3},
+		},
+		"line clipped and simplified": {
+			gen:      unit(Rule{Tolerance: 0.5}),
+			g:        geom.LineString{{-5, 5}, {2, 5}, {4, 5.1}, {6, 5}, {15, 5}},
+			expected: geom.MultiLineString{{{0, 5}, {10, 5}}},
+		},
+		"short line dropped": {
+			gen:      unit(Rule{MinLength: 2}),
+			g:        geom.MultiLineString{{{1, 1}, {2, 1}}, {{1, 2}, {4, 2}}},
+			expected: geom.MultiLineString{{{1, 2}, {4, 2}}},
+		},
+		"all lines dropped": {
+			gen: unit(Rule{MinLength: 2}),
+			g:   geom.MultiLineString{{{1, 1}, {2, 1}}},
+		},
+		"zoomed in": {
+			// a pixel is a quarter of a unit at zoom 2
+			gen:      zoomed,
+			g:        geom.MultiLineString{{{1, 1}, {1.5, 1}}, {{1, 2}, {1.1, 2}}},
+			expected: geom.MultiLineString{{{1, 1}, {1.5, 1}}},
+		},
+		"line collapsed by snapping": {
+			gen: unit(Rule{GridSize: 1}),
+			g:   geom.LineString{{1.1, 1.1}, {1.2, 1.3}},
+		},
+		"small hole dropped": {
+			gen: unit(Rule{MinArea: 2}),
+			g: geom.Polygon{
+				{{1, 1}, {9, 1}, {9, 9}, {1, 9}},
+				{{2, 2}, {2, 3}, {3, 3}, {3, 2}},
+				{{5, 5}, {5, 7}, {7, 7}, {7, 5}},
+			},
+			expected: geom.Polygon{
+				{{1, 1}, {9, 1}, {9, 9}, {1, 9}},
+				{{5, 5}, {5, 7}, {7, 7}, {7, 5}},
+			},
+		},
+		"small polygon dropped": {
+			gen: unit(Rule{MinArea: 2}),
+			g: geom.MultiPolygon{
+				{{{1, 1}, {2, 1}, {2, 2}, {1, 2}}},
+				{{{3, 3}, {6, 3}, {6, 6}, {3, 6}}},
+			},
+			expected: geom.MultiPolygon{
+				{{{3, 3}, {6, 3}, {6, 6}, {3, 6}}},
+			},
+		},
+		"polygon dropped": {
+			gen: unit(Rule{MinArea: 2}),
+			g:   geom.Polygon{{{1, 1}, {2, 1}, {2, 2}, {1, 2}}},
+		},
+		"custom simplifier": {
+			gen:      vw,
+			g:        geom.LineString{{1, 1}, {2, 1.5}, {3, 1}, {5, 5}},
+			expected: geom.MultiLineString{{{1, 1}, {3, 1}, {5, 5}}},
+		},
+		"collection": {
+			gen: unit(Rule{MinLength: 2}),
+			g: geom.Collection{
+				geom.Point{1, 1},
+				geom.LineString{{1, 1}, {2, 1}},
+				geom.Point{20, 20},
+			},
+			expected: geom.Collection{geom.Point{1, 1}},
+		},
+		"z values": {
+			gen: Generalizer{PixelSize: 1},
+			g:   geom.PointZ{1, 2, 3},
+			err: geom.ErrUnknownGeometry{Geom: geom.PointZ{1, 2, 3}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestForTile(t *testing.T) {
+	tile := slippy.NewTile(3, 4, 2)
+	g := ForTile(tile, 16)
+	if g.Zoom != 3 {
+		t.Errorf("zoom, expected 3 got %v", g.Zoom)
+	}
+	if g.Lines.Tolerance != DefaultRule.Tolerance || g.Polygons.MinArea != DefaultRule.MinArea {
+		t.Errorf("rules, expected the default got %+v and %+v", g.Lines, g.Polygons)
+	}
+	// 16 pixels of a 256 pixel tile, to within the rounding of
+	// slippy.WebMercatorMax
+	ext := tile.Extent3857()
+	buffer := ext.XSpan() / 16
+	expected := geom.Extent{ext.MinX() - buffer, ext.MinY() - buffer, ext.MaxX() + buffer, ext.MaxY() + buffer}
+	for i := range expected {
+		if math.Abs(g.Clip[i]-expected[i]) > 1e-3 {
+			t.Errorf("clip, expected %v got %v", expected, *g.Clip)
+			break
+		}
+	}
+}
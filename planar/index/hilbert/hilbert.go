@@ -0,0 +1,137 @@
+// Package hilbert orders points and extents along a Hilbert curve. Points
+// that are close along the curve are close in the plane, so items sorted
+// by their Hilbert index are grouped by where they are, which is what
+// packing an index, inserting into a triangulation or cutting features
+// into tiles want.
+package hilbert
+
+import (
+	"sort"
+
+	"github.com/go-spatial/geom"
+)
+
+// Order is the number of bits of each axis of the grid the curve is over,
+// so the curve has 2^Order by 2^Order cells.
+const Order = 16
+
+// cells is the number of cells along each axis of the grid
+const cells = 1 << Order
+
+// cell returns the cell of v along an axis from min to max
+func cell(v, min, max float64) uint32 {
+	if !(max > min) {
+		return 0
+	}
+	c := (v - min) / (max - min) * cells
+	if c >= cells-1 {
+		return cells - 1
+	}
+	if !(c > 0) {
+		return 0
+	}
+	return uint32(c)
+}
+
+// Index returns the distance along the Hilbert curve over the extent of
+// the cell the point is in. The curve starts at the minimum corner of the
+// extent and ends at the maximum x, minimum y corner. Points outside the
+// extent are in the nearest cell.
+func Index(ext *geom.Extent, pt [2]float64) uint64 {
+	x := cell(pt[0], ext.MinX(), ext.MaxX())
+	y := cell(pt[1], ext.MinY(), ext.MaxY())
+
+	var d uint64
+	for s := uint32(cells / 2); s > 0; s /= 2 {
+		var rx, ry uint32
+		if x&s > 0 {
+			rx = 1
+		}
+		if y&s > 0 {
+			ry = 1
+		}
+		d += uint64(s) * uint64(s) * uint64((3*rx)^ry)
+		// rotate the quadrant, so the curve in it starts and ends next to
+		// the neighbouring quadrants
+		if ry == 0 {
+			if rx == 1 {
+				x = cells - 1 - x
+				y = cells - 1 - y
+			}
+			x, y = y, x
+		}
+	}
+	return d
+}
+
+// IndexOfExtent returns the index of the center of the extent e.
+func IndexOfExtent(ext *geom.Extent, e geom.Extent) uint64 {
+	return Index(ext, center(e))
+}
+
+// Sort sorts the n entries by the Hilbert index of their points, over the
+// extent of the points, and those with the same index by x then y. The
+// order only depends on the points, not on the order they were in.
+func Sort(n int, point func(i int) [2]float64, swap func(i, j int)) {
+	if n < 2 {
+		return
+	}
+	s := sorter{
+		idx:  make([]uint64, n),
+		pts:  make([][2]float64, n),
+		swap: swap,
+	}
+	for i := range s.pts {
+		s.pts[i] = point(i)
+	}
+	ext := geom.NewExtent(s.pts...)
+	for i := range s.idx {
+		s.idx[i] = Index(ext, s.pts[i])
+	}
+	sort.Sort(s)
+}
+
+// SortPoints sorts the points by their Hilbert index, see Sort.
+func SortPoints(pts [][2]float64) {
+	Sort(len(pts),
+		func(i int) [2]float64 { return pts[i] },
+		func(i, j int) { pts[i], pts[j] = pts[j], pts[i] },
+	)
+}
+
+// SortExtents sorts the extents by the Hilbert index of their centers,
+// see Sort.
+func SortExtents(exts []geom.Extent) {
+	Sort(len(exts),
+		func(i int) [2]float64 { return center(exts[i]) },
+		func(i, j int) { exts[i], exts[j] = exts[j], exts[i] },
+	)
+}
+
+func center(e geom.Extent) [2]float64 {
+	return [2]float64{(e[0] + e[2]) / 2, (e[1] + e[3]) / 2}
+}
+
+// sorter sorts the entries by their index then point, swapping the
+// entries with swap as it swaps its own
+type sorter struct {
+	idx  []uint64
+	pts  [][2]float64
+	swap func(i, j int)
+}
+
+func (s sorter) Len() int { return len(s.idx) }
+func (s sorter) Less(i, j int) bool {
+	if s.idx[i] != s.idx[j] {
+		return s.idx[i] < s.idx[j]
+	}
+	if s.pts[i][0] != s.pts[j][0] {
+		return s.pts[i][0] < s.pts[j][0]
+	}
+	return s.pts[i][1] < s.pts[j][1]
+}
+func (s sorter) Swap(i, j int) {
+	s.idx[i], s.idx[j] = s.idx[j], s.idx[i]
+	s.pts[i], s.pts[j] = s.pts[j], s.pts[i]
+	s.swap(i, j)
+}
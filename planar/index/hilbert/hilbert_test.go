@@ -0,0 +1,92 @@
+package hilbert
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestIndex(t *testing.T) {
+	type tcase struct {
+		pt geom.Point
+		// the index is from min up to but not including max
+		min, max uint64
+	}
+
+	ext := geom.NewExtent([2]float64{0, 0}, [2]float64{1, 1})
+	quarter := uint64(cells) * cells / 4
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if got := Index(ext, tc.pt); got < tc.min || got >= tc.max {
+				t.Errorf("index, expected from %v to %v got %v", tc.min, tc.max, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"min":         {pt: geom.Point{0, 0}, min: 0, max: 1},
+		"max x min y": {pt: geom.Point{1, 0}, min: 4*quarter - 1, max: 4 * quarter},
+		"outside":     {pt: geom.Point{-5, -5}, min: 0, max: 1},
+		// the curve goes through the quadrants from the min corner up,
+		// across and down
+		"first quadrant":  {pt: geom.Point{0.25, 0.25}, min: 0, max: quarter},
+		"second quadrant": {pt: geom.Point{0.25, 0.75}, min: quarter, max: 2 * quarter},
+		"third quadrant":  {pt: geom.Point{0.75, 0.75}, min: 2 * quarter, max: 3 * quarter},
+		"fourth quadrant": {pt: geom.Point{0.75, 0.25}, min: 3 * quarter, max: 4 * quarter},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestIndexNeighbours(t *testing.T) {
+	// consecutive indexes are neighbouring cells
+	ext := geom.NewExtent([2]float64{0, 0}, [2]float64{cells, cells})
+	const n = 16
+	cellOf := make(map[uint64][2]int)
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			// a small grid in the corner of the curve is a curve of its own
+			d := Index(ext, [2]float64{float64(x) + 0.5, float64(y) + 0.5})
+			cellOf[d] = [2]int{x, y}
+		}
+	}
+	for d := uint64(1); d < n*n; d++ {
+		a, aok := cellOf[d-1]
+		b, bok := cellOf[d]
+		if !aok || !bok {
+			t.Fatalf("cells, expected indexes %v and %v in the corner", d-1, d)
+		}
+		dx, dy := a[0]-b[0], a[1]-b[1]
+		if dx*dx+dy*dy != 1 {
+			t.Errorf("cells %v and %v of indexes %v and %v, expected neighbours", a, b, d-1, d)
+		}
+	}
+}
+
+func TestSortPoints(t *testing.T) {
+	pts := [][2]float64{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}, {1, 1}}
+	exp := [][2]float64{{0, 0}, {0, 0}, {1, 1}, {0, 10}, {10, 10}, {10, 0}}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		r.Shuffle(len(pts), func(i, j int) { pts[i], pts[j] = pts[j], pts[i] })
+		SortPoints(pts)
+		if !reflect.DeepEqual(pts, exp) {
+			t.Fatalf("sorted, expected %v got %v", exp, pts)
+		}
+	}
+}
+
+func TestSortExtents(t *testing.T) {
+	exts := []geom.Extent{{9, 0, 11, 2}, {0, 9, 2, 11}, {0, 0, 2, 2}}
+	exp := []geom.Extent{{0, 0, 2, 2}, {0, 9, 2, 11}, {9, 0, 11, 2}}
+	SortExtents(exts)
+	if !reflect.DeepEqual(exts, exp) {
+		t.Errorf("sorted, expected %v got %v", exp, exts)
+	}
+}
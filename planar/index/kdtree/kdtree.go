@@ -3,6 +3,7 @@ package kdtree
 
 import (
 	"errors"
+	"sort"
 
 	"github.com/go-spatial/geom"
 )
@@ -12,8 +13,8 @@ KdTree is an index for 2 dimensional point data.
 
 Limitations:
 
-* Bulk inserts and balancing are not supported. If you have a large amount of data to insert it is
-  best to randomize the data before inserting.
+* Points inserted one at a time are not balanced. If you have a large amount of data it is best
+  to use Build, which builds a balanced tree, or to randomize the data before inserting.
 * Duplicate points are not supported and will return an error.
 
 See the *_iterator.go files for how to query data out of the kd-tree.
@@ -75,3 +76,57 @@ func (kdt *KdTree) Insert(p geom.Pointer) (*KdNode, error) {
 
 	return node, nil
 }
+
+/*
+Build returns a balanced kd-tree of the points, built by splitting them at the median of each
+dimension in turn. Points can be inserted afterwards as usual.
+
+If there are duplicate points ErrDuplicateNode is returned.
+*/
+func Build(pts []geom.Point) (*KdTree, error) {
+	pts = append([]geom.Point(nil), pts...)
+	root, err := build(pts, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &KdTree{root: root}, nil
+}
+
+// build returns the node at the median of the points in the dimension d,
+// with the points before it on the left and the ones after on the right
+func build(pts []geom.Point, d int) (*KdNode, error) {
+	if len(pts) == 0 {
+		return nil, nil
+	}
+	sort.Slice(pts, func(i, j int) bool {
+		if pts[i][d] != pts[j][d] {
+			return pts[i][d] < pts[j][d]
+		}
+		return pts[i][d^1] < pts[j][d^1]
+	})
+
+	// points the same as the median in d must be on the right, as they are
+	// for Insert
+	m := len(pts) / 2
+	for m > 0 && pts[m-1][d] == pts[m][d] {
+		m--
+	}
+	if m+1 < len(pts) && pts[m+1] == pts[m] {
+		return nil, ErrDuplicateNode
+	}
+
+	node := NewKdNode(pts[m])
+	var err error
+	if node.left, err = build(pts[:m], d^1); err != nil {
+		return nil, err
+	}
+	if node.right, err = build(pts[m+1:], d^1); err != nil {
+		return nil, err
+	}
+	for _, c := range []*KdNode{node.left, node.right} {
+		if c != nil {
+			node.bbox.Add(&c.bbox)
+		}
+	}
+	return node, nil
+}
@@ -2,6 +2,9 @@ package kdtree
 
 import (
 	"encoding/json"
+	"math"
+	"math/rand"
+	"sort"
 	"testing"
 
 	"github.com/go-spatial/geom"
@@ -77,3 +80,113 @@ func TestInsertPoints(t *testing.T) {
 		t.Run(name, func(t *testing.T) { fn(t, tc) })
 	}
 }
+
+func TestBuild(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	pts := make([]geom.Point, 500)
+	for i := range pts {
+		// on a grid, so there are many points with the same x or y
+		pts[i] = geom.Point{float64(i % 25), float64(i / 25)}
+	}
+	rng.Shuffle(len(pts), func(i, j int) { pts[i], pts[j] = pts[j], pts[i] })
+
+	kdt, err := Build(pts)
+	if err != nil {
+		t.Fatalf("build error, expected nil got %v", err)
+	}
+
+	// check the points are on the sides of each node Insert would put them,
+	// the bounding boxes hold the children, and the tree is balanced
+	var check func(n *KdNode, d int) (count, depth int)
+	check = func(n *KdNode, d int) (int, int) {
+		if n == nil {
+			return 0, 0
+		}
+		if n.left != nil && n.left.bbox.MaxX() >= n.p.XY()[0] && d == 0 ||
+			n.left != nil && n.left.bbox.MaxY() >= n.p.XY()[1] && d == 1 {
+			t.Errorf("left of %v, expected less in dimension %v", n.p, d)
+		}
+		if n.right != nil && n.right.bbox.MinX() < n.p.XY()[0] && d == 0 ||
+			n.right != nil && n.right.bbox.MinY() < n.p.XY()[1] && d == 1 {
+			t.Errorf("right of %v, expected the same or more in dimension %v", n.p, d)
+		}
+		lc, ld := check(n.left, d^1)
+		rc, rd := check(n.right, d^1)
+		for _, c := range []*KdNode{n.left, n.right} {
+			if c != nil && !n.bbox.Contains(&c.bbox) {
+				t.Errorf("bbox %v, expected to contain %v", n.bbox, c.bbox)
+			}
+		}
+		if rd > ld {
+			ld = rd
+		}
+		return lc + rc + 1, ld + 1
+	}
+	count, depth := check(kdt.root, 0)
+	if count != len(pts) {
+		t.Errorf("count, expected %v got %v", len(pts), count)
+	}
+	// a grid is not split evenly where there are ties, but the tree
+	// should be far from the 500 of a list
+	if depth > 20 {
+		t.Errorf("depth, expected at most 20 got %v", depth)
+	}
+
+	// the tree can be added to
+	if _, err := kdt.Insert(geom.Point{100, 100}); err != nil {
+		t.Errorf("insert error, expected nil got %v", err)
+	}
+	if _, err := kdt.Insert(geom.Point{3, 4}); err != ErrDuplicateNode {
+		t.Errorf("insert duplicate error, expected %v got %v", ErrDuplicateNode, err)
+	}
+
+	if _, err := Build([]geom.Point{{1, 2}, {3, 4}, {1, 2}}); err != ErrDuplicateNode {
+		t.Errorf("build duplicate error, expected %v got %v", ErrDuplicateNode, err)
+	}
+}
+
+func TestNearestQueries(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	pts := make([]geom.Point, 300)
+	for i := range pts {
+		pts[i] = geom.Point{rng.Float64() * 100, rng.Float64() * 100}
+	}
+	kdt, err := Build(pts)
+	if err != nil {
+		t.Fatalf("build error, expected nil got %v", err)
+	}
+
+	dist := func(a, b geom.Pointer) float64 {
+		return EuclideanDistance(a, geom.NewExtentFromPoints(b.XY()))
+	}
+	for i := 0; i < 20; i++ {
+		from := geom.Point{rng.Float64() * 120, rng.Float64() * 120}
+		ds := make([]float64, len(pts))
+		for j, pt := range pts {
+			ds[j] = dist(from, pt)
+		}
+		sort.Float64s(ds)
+
+		if n, d := kdt.Nearest(from); d != ds[0] || dist(from, n) != ds[0] {
+			t.Errorf("nearest, expected distance %v got %v", ds[0], d)
+		}
+		knn := kdt.KNearest(from, 5)
+		if len(knn) != 5 {
+			t.Fatalf("k nearest, expected 5 points got %v", len(knn))
+		}
+		for j, n := range knn {
+			if dist(from, n) != ds[j] {
+				t.Errorf("k nearest %v, expected distance %v got %v", j, ds[j], dist(from, n))
+			}
+		}
+		r := 15.0
+		within := sort.SearchFloat64s(ds, math.Nextafter(r, math.Inf(1)))
+		if got := kdt.Radius(from, r); len(got) != within {
+			t.Errorf("radius, expected %v points got %v", within, len(got))
+		}
+	}
+
+	if n, _ := new(KdTree).Nearest(geom.Point{0, 0}); n != nil {
+		t.Errorf("nearest in empty tree, expected nil got %v", n)
+	}
+}
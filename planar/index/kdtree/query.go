@@ -0,0 +1,49 @@
+package kdtree
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+/*
+Nearest returns the point in the tree nearest to p and its distance, using EuclideanDistance. If the
+tree is empty nil is returned.
+*/
+func (kdt *KdTree) Nearest(p geom.Pointer) (geom.Pointer, float64) {
+	nnit := NewNearestNeighborIterator(p, kdt, EuclideanDistance)
+	if !nnit.Next() {
+		return nil, math.Inf(1)
+	}
+	return nnit.Value()
+}
+
+/*
+KNearest returns up to k points in the tree nearest to p, nearest first, using EuclideanDistance.
+*/
+func (kdt *KdTree) KNearest(p geom.Pointer, k int) []geom.Pointer {
+	var ret []geom.Pointer
+	nnit := NewNearestNeighborIterator(p, kdt, EuclideanDistance)
+	for len(ret) < k && nnit.Next() {
+		n, _ := nnit.Value()
+		ret = append(ret, n)
+	}
+	return ret
+}
+
+/*
+Radius returns the points in the tree within the distance r of p, including those at exactly r,
+nearest first.
+*/
+func (kdt *KdTree) Radius(p geom.Pointer, r float64) []geom.Pointer {
+	var ret []geom.Pointer
+	nnit := NewNearestNeighborIterator(p, kdt, EuclideanDistance)
+	for nnit.Next() {
+		n, d := nnit.Value()
+		if d > r {
+			break
+		}
+		ret = append(ret, n)
+	}
+	return ret
+}
@@ -0,0 +1,419 @@
+// Package quadtree is a quadtree index of points and small extents. It is
+// lighter than an R-tree when most of the items are points: nodes split
+// into four equal quadrants when they fill up, and are joined again when
+// they empty, so inserting or removing an item moves at most one node's
+// worth of items.
+//
+// An item is held by the smallest node whose quadrant contains its
+// extent, so extents that straddle the lines between quadrants are held
+// higher up the tree; the tree suits items that are small compared to the
+// area they are spread over.
+package quadtree
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// DefaultMaxItems is the number of items a node holds before it splits,
+// used when the maximum given to New is too small.
+const DefaultMaxItems = 8
+
+// maxDepth stops nodes splitting, so many items at the same point do not
+// split the tree forever
+const maxDepth = 32
+
+// Item is a value indexed by its extent. Points have extents with the
+// same minimum and maximum, see PointItem.
+type Item struct {
+	Extent geom.Extent
+	Data   interface{}
+}
+
+// PointItem returns the item for a value at the point.
+func PointItem(pt [2]float64, data interface{}) Item {
+	return Item{Extent: geom.Extent{pt[0], pt[1], pt[0], pt[1]}, Data: data}
+}
+
+// Quadtree is a quadtree of Items.
+type Quadtree struct {
+	root     *node
+	size     int
+	maxItems int
+	// nonFinite are the items with NaN or infinite extents
+	nonFinite []Item
+}
+
+// node is a quadrant of its parent; its items are the ones that do not fit
+// in one of its children, or all of its items if it is a leaf
+type node struct {
+	ext      geom.Extent
+	items    []Item
+	children *[4]*node
+	// count is the number of items in the node and its children
+	count int
+}
+
+// New returns an empty tree covering the bounds, whose nodes split when
+// they hold more than maxItems items. If maxItems is less than one
+// DefaultMaxItems is used. The tree grows to hold items outside the
+// bounds, but is quicker if they are not.
+func New(bounds geom.Extent, maxItems int) *Quadtree {
+	if maxItems < 1 {
+		maxItems = DefaultMaxItems
+	}
+	// the quadrants are kept square
+	side := math.Max(bounds[2]-bounds[0], bounds[3]-bounds[1])
+	if !(side > 0) || math.IsInf(side, 0) {
+		side = 1
+	}
+	if !finite(bounds) {
+		bounds = geom.Extent{}
+	}
+	return &Quadtree{
+		root:     &node{ext: geom.Extent{bounds[0], bounds[1], bounds[0] + side, bounds[1] + side}},
+		maxItems: maxItems,
+	}
+}
+
+// Len returns the number of items in the tree.
+func (q *Quadtree) Len() int { return q.size }
+
+// Insert adds the item to the tree. Items with NaN or infinite extents are
+// held apart from the tree, and only found by All.
+func (q *Quadtree) Insert(item Item) {
+	q.size++
+	if !finite(item.Extent) {
+		q.nonFinite = append(q.nonFinite, item)
+		return
+	}
+	for !contains(q.root.ext, item.Extent) {
+		q.grow(item.Extent)
+	}
+	q.insert(q.root, item, 0)
+}
+
+// grow doubles the size of the root toward the extent
+func (q *Quadtree) grow(ext geom.Extent) {
+	old := q.root.ext
+	side := old[2] - old[0]
+	nxt := old
+	// the quadrant of the new root the old root is
+	quad := 0
+	if ext[0] < old[0] {
+		nxt[0] -= side
+		quad |= 1
+	} else {
+		nxt[2] += side
+	}
+	if ext[1] < old[1] {
+		nxt[1] -= side
+		quad |= 2
+	} else {
+		nxt[3] += side
+	}
+
+	root := &node{ext: nxt, count: q.root.count}
+	root.split()
+	root.children[quad] = q.root
+	q.root = root
+}
+
+func (q *Quadtree) insert(n *node, item Item, depth int) {
+	n.count++
+	if n.children == nil {
+		n.items = append(n.items, item)
+		if len(n.items) > q.maxItems && depth < maxDepth {
+			items := n.items
+			n.items = nil
+			n.split()
+			for _, it := range items {
+				if c := n.child(it.Extent); c != nil {
+					c.items = append(c.items, it)
+					c.count++
+				} else {
+					n.items = append(n.items, it)
+				}
+			}
+		}
+		return
+	}
+	if c := n.child(item.Extent); c != nil {
+		q.insert(c, item, depth+1)
+		return
+	}
+	n.items = append(n.items, item)
+}
+
+// split gives the node four empty children
+func (n *node) split() {
+	midX, midY := (n.ext[0]+n.ext[2])/2, (n.ext[1]+n.ext[3])/2
+	n.children = &[4]*node{
+		{ext: geom.Extent{midX, midY, n.ext[2], n.ext[3]}},
+		{ext: geom.Extent{n.ext[0], midY, midX, n.ext[3]}},
+		{ext: geom.Extent{midX, n.ext[1], n.ext[2], midY}},
+		{ext: geom.Extent{n.ext[0], n.ext[1], midX, midY}},
+	}
+}
+
+// child returns the child containing the extent, or nil if there is none
+func (n *node) child(ext geom.Extent) *node {
+	if n.children == nil {
+		return nil
+	}
+	for _, c := range n.children {
+		if contains(c.ext, ext) {
+			return c
+		}
+	}
+	return nil
+}
+
+// Remove removes an item with the same extent and data from the tree,
+// reporting whether there was one. The data must be comparable.
+func (q *Quadtree) Remove(item Item) bool {
+	if !finite(item.Extent) {
+		for i, it := range q.nonFinite {
+			if sameItem(it, item) {
+				q.nonFinite = append(q.nonFinite[:i], q.nonFinite[i+1:]...)
+				q.size--
+				return true
+			}
+		}
+		return false
+	}
+	if !q.remove(q.root, item) {
+		return false
+	}
+	q.size--
+	return true
+}
+
+// sameItem reports whether the items have the same extent and data; NaNs
+// are the same as each other
+func sameItem(a, b Item) bool {
+	for i := range a.Extent {
+		if a.Extent[i] != b.Extent[i] && !(math.IsNaN(a.Extent[i]) && math.IsNaN(b.Extent[i])) {
+			return false
+		}
+	}
+	return a.Data == b.Data
+}
+
+func (q *Quadtree) remove(n *node, item Item) bool {
+	found := false
+	if n.children != nil {
+		// an item on the line between quadrants may be in either
+		for _, c := range n.children {
+			if contains(c.ext, item.Extent) && q.remove(c, item) {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		for i, it := range n.items {
+			if sameItem(it, item) {
+				n.items = append(n.items[:i], n.items[i+1:]...)
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return false
+	}
+	n.count--
+	if n.children != nil && n.count <= q.maxItems {
+		// join the children back into the node
+		var items []Item
+		n.all(func(it Item) bool {
+			items = append(items, it)
+			return true
+		})
+		n.items, n.children = items, nil
+	}
+	return true
+}
+
+// Search calls fn for each item whose extent intersects ext, until fn
+// returns false. Extents that only touch intersect.
+func (q *Quadtree) Search(ext geom.Extent, fn func(Item) bool) {
+	q.root.search(ext, fn)
+}
+
+// SearchAll returns the items whose extents intersect ext.
+func (q *Quadtree) SearchAll(ext geom.Extent) []Item {
+	var items []Item
+	q.Search(ext, func(item Item) bool {
+		items = append(items, item)
+		return true
+	})
+	return items
+}
+
+// SearchCircle calls fn for each item whose extent is within the radius
+// of the center of the circle, until fn returns false. It is the radius
+// search of Search; the items are not in order of their distance, for
+// which see NearestFunc.
+func (q *Quadtree) SearchCircle(c geom.Circle, fn func(Item) bool) {
+	q.Search(geom.Extent(c.Extent()), func(item Item) bool {
+		if distance(c.Center, item.Extent) > c.Radius {
+			return true
+		}
+		return fn(item)
+	})
+}
+
+// SearchCircleAll returns the items whose extents are within the radius of
+// the center of the circle.
+func (q *Quadtree) SearchCircleAll(c geom.Circle) []Item {
+	var items []Item
+	q.SearchCircle(c, func(item Item) bool {
+		items = append(items, item)
+		return true
+	})
+	return items
+}
+
+// All calls fn for each item in the tree, until fn returns false.
+func (q *Quadtree) All(fn func(Item) bool) {
+	for _, item := range q.nonFinite {
+		if !fn(item) {
+			return
+		}
+	}
+	q.root.all(fn)
+}
+
+func (n *node) search(ext geom.Extent, fn func(Item) bool) bool {
+	if n.count == 0 {
+		return true
+	}
+	if contains(ext, n.ext) {
+		return n.all(fn)
+	}
+	for _, item := range n.items {
+		if intersects(ext, item.Extent) && !fn(item) {
+			return false
+		}
+	}
+	if n.children == nil {
+		return true
+	}
+	for _, c := range n.children {
+		if intersects(ext, c.ext) && !c.search(ext, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *node) all(fn func(Item) bool) bool {
+	for _, item := range n.items {
+		if !fn(item) {
+			return false
+		}
+	}
+	if n.children == nil {
+		return true
+	}
+	for _, c := range n.children {
+		if c.count > 0 && !c.all(fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// nearestEntry is a node or an item waiting to be visited by Nearest
+type nearestEntry struct {
+	d    float64
+	n    *node
+	item Item
+}
+
+// nearestHeap orders the entries by their distance, nearest first
+type nearestHeap []nearestEntry
+
+func (h nearestHeap) Len() int            { return len(h) }
+func (h nearestHeap) Less(i, j int) bool  { return h[i].d < h[j].d }
+func (h nearestHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *nearestHeap) Push(x interface{}) { *h = append(*h, x.(nearestEntry)) }
+func (h *nearestHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// Nearest returns up to k items nearest to the point, nearest first. The
+// distance to an item is the distance to its extent, which is zero for
+// extents containing the point.
+func (q *Quadtree) Nearest(pt [2]float64, k int) []Item {
+	if k <= 0 {
+		return nil
+	}
+	var items []Item
+	q.NearestFunc(pt, func(item Item, _ float64) bool {
+		items = append(items, item)
+		return len(items) < k
+	})
+	return items
+}
+
+// NearestFunc calls fn with the items in order of their distance from the
+// point, nearest first, until fn returns false. Items that are not finite
+// are not visited.
+func (q *Quadtree) NearestFunc(pt [2]float64, fn func(item Item, d float64) bool) {
+	h := nearestHeap{{d: distance(pt, q.root.ext), n: q.root}}
+	for h.Len() > 0 {
+		e := heap.Pop(&h).(nearestEntry)
+		if e.n == nil {
+			if !fn(e.item, e.d) {
+				return
+			}
+			continue
+		}
+		for _, item := range e.n.items {
+			heap.Push(&h, nearestEntry{d: distance(pt, item.Extent), item: item})
+		}
+		if e.n.children == nil {
+			continue
+		}
+		for _, c := range e.n.children {
+			if c.count > 0 {
+				heap.Push(&h, nearestEntry{d: distance(pt, c.ext), n: c})
+			}
+		}
+	}
+}
+
+// distance returns the distance from the point to the extent
+func distance(pt [2]float64, e geom.Extent) float64 {
+	dx := math.Max(0, math.Max(e[0]-pt[0], pt[0]-e[2]))
+	dy := math.Max(0, math.Max(e[1]-pt[1], pt[1]-e[3]))
+	return math.Hypot(dx, dy)
+}
+
+// finite reports whether none of the extent is NaN or infinite
+func finite(e geom.Extent) bool {
+	for _, v := range e {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// intersects reports whether the extents overlap or touch
+func intersects(a, b geom.Extent) bool {
+	return a[0] <= b[2] && b[0] <= a[2] && a[1] <= b[3] && b[1] <= a[3]
+}
+
+// contains reports whether a contains b
+func contains(a, b geom.Extent) bool {
+	return a[0] <= b[0] && b[2] <= a[2] && a[1] <= b[1] && b[3] <= a[3]
+}
@@ -0,0 +1,201 @@
+package quadtree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func randomItems(r *rand.Rand, n int) []Item {
+	items := make([]Item, n)
+	for i := range items {
+		x, y := r.Float64()*1000, r.Float64()*1000
+		if i%4 == 0 {
+			// a small extent, some of them outside the bounds of the tree
+			items[i] = Item{
+				Extent: geom.Extent{x - 100, y - 100, x - 100 + r.Float64()*5, y - 100 + r.Float64()*5},
+				Data:   i,
+			}
+			continue
+		}
+		items[i] = PointItem([2]float64{x, y}, i)
+	}
+	return items
+}
+
+// checkNode checks the extents of the nodes hold their items and the
+// counts add up, returning the count
+func checkNode(t *testing.T, n *node) int {
+	t.Helper()
+	count := len(n.items)
+	for _, item := range n.items {
+		if !contains(n.ext, item.Extent) {
+			t.Errorf("node extent %v does not contain %v", n.ext, item.Extent)
+		}
+	}
+	if n.children != nil {
+		for _, c := range n.children {
+			count += checkNode(t, c)
+		}
+	}
+	if count != n.count {
+		t.Errorf("node count, expected %v got %v", count, n.count)
+	}
+	return count
+}
+
+func dataOf(items []Item) []int {
+	ret := make([]int, len(items))
+	for i, item := range items {
+		ret[i] = item.Data.(int)
+	}
+	sort.Ints(ret)
+	return ret
+}
+
+func sameInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestQuadtree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	items := randomItems(r, 2000)
+	q := New(geom.Extent{0, 0, 1000, 1000}, 0)
+	for _, item := range items {
+		q.Insert(item)
+	}
+	if q.Len() != len(items) {
+		t.Fatalf("len, expected %v got %v", len(items), q.Len())
+	}
+	checkNode(t, q.root)
+
+	for i := 0; i < 50; i++ {
+		x, y := r.Float64()*1000, r.Float64()*1000
+		ext := geom.Extent{x, y, x + r.Float64()*200, y + r.Float64()*200}
+		var exp []Item
+		for _, item := range items {
+			if intersects(ext, item.Extent) {
+				exp = append(exp, item)
+			}
+		}
+		if got := q.SearchAll(ext); !sameInts(dataOf(got), dataOf(exp)) {
+			t.Errorf("search %v, expected %v items got %v", ext, len(exp), len(got))
+		}
+	}
+
+	// remove half of the items
+	for i, item := range items {
+		if i%2 == 0 && !q.Remove(item) {
+			t.Errorf("remove %v, expected true got false", item)
+		}
+	}
+	if q.Remove(items[0]) {
+		t.Errorf("remove again, expected false got true")
+	}
+	if q.Len() != len(items)/2 {
+		t.Errorf("len, expected %v got %v", len(items)/2, q.Len())
+	}
+	checkNode(t, q.root)
+	var exp []Item
+	for i, item := range items {
+		if i%2 == 1 {
+			exp = append(exp, item)
+		}
+	}
+	var got []Item
+	q.All(func(item Item) bool {
+		got = append(got, item)
+		return true
+	})
+	if !sameInts(dataOf(got), dataOf(exp)) {
+		t.Errorf("all, expected %v items got %v", len(exp), len(got))
+	}
+}
+
+func TestQuadtreeNearest(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	items := randomItems(r, 1000)
+	q := New(geom.Extent{0, 0, 1000, 1000}, 4)
+	for _, item := range items {
+		q.Insert(item)
+	}
+
+	for i := 0; i < 50; i++ {
+		pt := [2]float64{r.Float64()*1200 - 100, r.Float64()*1200 - 100}
+		got := q.Nearest(pt, 10)
+		if len(got) != 10 {
+			t.Fatalf("nearest, expected 10 items got %v", len(got))
+		}
+		ds := make([]float64, len(items))
+		for j, item := range items {
+			ds[j] = distance(pt, item.Extent)
+		}
+		sort.Float64s(ds)
+		for j, item := range got {
+			if d := distance(pt, item.Extent); d != ds[j] {
+				t.Errorf("nearest %v to %v, expected distance %v got %v", j, pt, ds[j], d)
+			}
+		}
+	}
+
+	if got := New(geom.Extent{}, 0).Nearest([2]float64{0, 0}, 3); len(got) != 0 {
+		t.Errorf("nearest in empty tree, expected none got %v", got)
+	}
+}
+
+func TestQuadtreeSearchCircle(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	items := randomItems(r, 1000)
+	q := New(geom.Extent{0, 0, 1000, 1000}, 4)
+	for _, item := range items {
+		q.Insert(item)
+	}
+
+	for i := 0; i < 50; i++ {
+		c := geom.Circle{
+			Center: [2]float64{r.Float64()*1200 - 100, r.Float64()*1200 - 100},
+			Radius: r.Float64() * 150,
+		}
+		var exp []Item
+		for _, item := range items {
+			if distance(c.Center, item.Extent) <= c.Radius {
+				exp = append(exp, item)
+			}
+		}
+		got := q.SearchCircleAll(c)
+		if !sameInts(dataOf(got), dataOf(exp)) {
+			t.Errorf("search circle %v, expected %v got %v", c, dataOf(exp), dataOf(got))
+		}
+	}
+}
+
+func TestQuadtreeSamePoint(t *testing.T) {
+	// many items at the same point do not split forever
+	q := New(geom.Extent{0, 0, 1, 1}, 2)
+	for i := 0; i < 100; i++ {
+		q.Insert(PointItem([2]float64{0.5, 0.5}, i))
+	}
+	nan := Item{Extent: geom.Extent{math.NaN(), 0, 0, 0}, Data: "nan"}
+	q.Insert(nan)
+	if q.Len() != 101 {
+		t.Errorf("len, expected 101 got %v", q.Len())
+	}
+	if got := q.SearchAll(geom.Extent{0, 0, 1, 1}); len(got) != 100 {
+		t.Errorf("search, expected 100 items got %v", len(got))
+	}
+	if !q.Remove(nan) {
+		t.Errorf("remove nan, expected true got false")
+	}
+	checkNode(t, q.root)
+}
@@ -0,0 +1,118 @@
+package rtree
+
+import (
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom/planar/index/hilbert"
+)
+
+// pack builds a tree of the items using sort tile recursive packing: the
+// entries are sorted into vertical slices by x, each slice sorted by y and
+// cut into full nodes, then the same is done to the nodes until there is
+// only one.
+func (t *RTree) pack(items []Item) *node {
+	items = append([]Item(nil), items...)
+	var nodes []*node
+	tile(len(items), t.maxEntries,
+		func(i, j int) bool { return centerX(items[i].Extent) < centerX(items[j].Extent) },
+		func(i, j int) bool { return centerY(items[i].Extent) < centerY(items[j].Extent) },
+		func(i, j int) { items[i], items[j] = items[j], items[i] },
+		func(i, j int) {
+			n := &node{leaf: true, items: items[i:j:j]}
+			n.ext = n.extentOf(0, n.count())
+			nodes = append(nodes, n)
+		},
+	)
+
+	for len(nodes) > 1 {
+		level := nodes
+		nodes = nil
+		tile(len(level), t.maxEntries,
+			func(i, j int) bool { return centerX(level[i].ext) < centerX(level[j].ext) },
+			func(i, j int) bool { return centerY(level[i].ext) < centerY(level[j].ext) },
+			func(i, j int) { level[i], level[j] = level[j], level[i] },
+			func(i, j int) {
+				n := &node{children: level[i:j:j]}
+				n.ext = n.extentOf(0, n.count())
+				nodes = append(nodes, n)
+			},
+		)
+	}
+	return nodes[0]
+}
+
+// packHilbert builds a tree of the items sorted along a Hilbert curve,
+// each run of entries filling a node, then the same is done to the nodes
+// until there is only one. The nodes keep the order of the curve, so they
+// do not need sorting again.
+func (t *RTree) packHilbert(items []Item) *node {
+	items = append([]Item(nil), items...)
+	hilbert.Sort(len(items),
+		func(i int) [2]float64 { return [2]float64{centerX(items[i].Extent), centerY(items[i].Extent)} },
+		func(i, j int) { items[i], items[j] = items[j], items[i] },
+	)
+	var nodes []*node
+	for i := 0; i < len(items); i += t.maxEntries {
+		j := i + t.maxEntries
+		if j > len(items) {
+			j = len(items)
+		}
+		n := &node{leaf: true, items: items[i:j:j]}
+		n.ext = n.extentOf(0, n.count())
+		nodes = append(nodes, n)
+	}
+
+	for len(nodes) > 1 {
+		level := nodes
+		nodes = nil
+		for i := 0; i < len(level); i += t.maxEntries {
+			j := i + t.maxEntries
+			if j > len(level) {
+				j = len(level)
+			}
+			n := &node{children: level[i:j:j]}
+			n.ext = n.extentOf(0, n.count())
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes[0]
+}
+
+// tile sorts the n entries into slices by x, then each slice by y, and
+// calls group with the ranges of entries that make up each node
+func tile(n, maxEntries int, lessX, lessY func(i, j int) bool, swap func(i, j int), group func(i, j int)) {
+	nodes := int(math.Ceil(float64(n) / float64(maxEntries)))
+	slices := int(math.Ceil(math.Sqrt(float64(nodes))))
+	sliceSize := slices * maxEntries
+
+	sort.Sort(sorter{n: n, less: lessX, swap: swap})
+	for s := 0; s < n; s += sliceSize {
+		e := s + sliceSize
+		if e > n {
+			e = n
+		}
+		sort.Sort(sorter{off: s, n: e - s, less: lessY, swap: swap})
+		for i := s; i < e; i += maxEntries {
+			j := i + maxEntries
+			if j > e {
+				j = e
+			}
+			group(i, j)
+		}
+	}
+}
+
+// sorter sorts the n entries starting at off with the given functions
+type sorter struct {
+	off, n int
+	less   func(i, j int) bool
+	swap   func(i, j int)
+}
+
+func (s sorter) Len() int           { return s.n }
+func (s sorter) Less(i, j int) bool { return s.less(i+s.off, j+s.off) }
+func (s sorter) Swap(i, j int)      { s.swap(i+s.off, j+s.off) }
+
+func centerX(e [4]float64) float64 { return (e[0] + e[2]) / 2 }
+func centerY(e [4]float64) float64 { return (e[1] + e[3]) / 2 }
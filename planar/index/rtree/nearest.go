@@ -0,0 +1,77 @@
+package rtree
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// nearestEntry is a node or an item waiting to be visited by Nearest
+type nearestEntry struct {
+	d    float64
+	n    *node
+	item Item
+}
+
+// nearestHeap orders the entries by their distance, nearest first
+type nearestHeap []nearestEntry
+
+func (h nearestHeap) Len() int            { return len(h) }
+func (h nearestHeap) Less(i, j int) bool  { return h[i].d < h[j].d }
+func (h nearestHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *nearestHeap) Push(x interface{}) { *h = append(*h, x.(nearestEntry)) }
+func (h *nearestHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// Nearest returns up to k items nearest to the point, nearest first. The
+// distance to an item is the distance to its extent, which is zero for
+// extents containing the point.
+func (t *RTree) Nearest(pt [2]float64, k int) []Item {
+	if k <= 0 {
+		return nil
+	}
+	var items []Item
+	t.NearestFunc(pt, func(item Item, _ float64) bool {
+		items = append(items, item)
+		return len(items) < k
+	})
+	return items
+}
+
+// NearestFunc calls fn with the items in order of their distance from the
+// point, nearest first, until fn returns false.
+func (t *RTree) NearestFunc(pt [2]float64, fn func(item Item, d float64) bool) {
+	if t.size == 0 {
+		return
+	}
+	h := nearestHeap{{d: distance(pt, t.root.ext), n: t.root}}
+	for h.Len() > 0 {
+		e := heap.Pop(&h).(nearestEntry)
+		switch {
+		case e.n == nil:
+			if !fn(e.item, e.d) {
+				return
+			}
+		case e.n.leaf:
+			for _, item := range e.n.items {
+				heap.Push(&h, nearestEntry{d: distance(pt, item.Extent), item: item})
+			}
+		default:
+			for _, c := range e.n.children {
+				heap.Push(&h, nearestEntry{d: distance(pt, c.ext), n: c})
+			}
+		}
+	}
+}
+
+// distance returns the distance from the point to the extent
+func distance(pt [2]float64, e geom.Extent) float64 {
+	dx := math.Max(0, math.Max(e[0]-pt[0], pt[0]-e[2]))
+	dy := math.Max(0, math.Max(e[1]-pt[1], pt[1]-e[3]))
+	return math.Hypot(dx, dy)
+}
@@ -0,0 +1,365 @@
+// Package rtree is an R-tree index of extents. Trees can be bulk loaded,
+// using sort tile recursive (STR) packing, and items inserted one at a
+// time afterwards.
+package rtree
+
+import (
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+)
+
+// DefaultMaxEntries is the number of entries in a node used when the
+// maximum given to New is too small.
+const DefaultMaxEntries = 16
+
+// Item is a value indexed by its extent.
+type Item struct {
+	Extent geom.Extent
+	Data   interface{}
+}
+
+// RTree is an R-tree of Items.
+type RTree struct {
+	root       *node
+	size       int
+	maxEntries int
+	minEntries int
+}
+
+// node is a node of the tree, leaf nodes hold items and the other nodes
+// hold child nodes
+type node struct {
+	ext      geom.Extent
+	leaf     bool
+	children []*node
+	items    []Item
+}
+
+// New returns an empty tree whose nodes have at most maxEntries entries.
+// If maxEntries is less than four DefaultMaxEntries is used.
+func New(maxEntries int) *RTree {
+	if maxEntries < 4 {
+		maxEntries = DefaultMaxEntries
+	}
+	return &RTree{
+		root:       &node{leaf: true, ext: emptyExtent()},
+		maxEntries: maxEntries,
+		// 40% of the maximum is a good minimum for the split
+		minEntries: int(math.Max(2, math.Ceil(float64(maxEntries)*0.4))),
+	}
+}
+
+// Len returns the number of items in the tree.
+func (t *RTree) Len() int { return t.size }
+
+// Extent returns the extent of all the items in the tree, or nil if the
+// tree is empty.
+func (t *RTree) Extent() *geom.Extent {
+	if t.size == 0 {
+		return nil
+	}
+	ext := t.root.ext
+	return &ext
+}
+
+// Insert adds the item to the tree.
+func (t *RTree) Insert(item Item) {
+	// find the leaf needing the least enlargement to hold the item
+	path := []*node{t.root}
+	n := t.root
+	for !n.leaf {
+		var best *node
+		bestEnlargement, bestArea := math.Inf(1), math.Inf(1)
+		for _, c := range n.children {
+			area := c.ext.Area()
+			enlargement := unionArea(c.ext, item.Extent) - area
+			if enlargement < bestEnlargement || (enlargement == bestEnlargement && area < bestArea) {
+				best, bestEnlargement, bestArea = c, enlargement, area
+			}
+		}
+		n = best
+		path = append(path, n)
+	}
+
+	n.items = append(n.items, item)
+	for _, p := range path {
+		p.ext = union(p.ext, item.Extent)
+	}
+	t.size++
+
+	// split the nodes that are too full, from the leaf up
+	for i := len(path) - 1; i >= 0 && path[i].count() > t.maxEntries; i-- {
+		sibling := t.split(path[i])
+		if i == 0 {
+			t.root = &node{
+				children: []*node{path[0], sibling},
+				ext:      union(path[0].ext, sibling.ext),
+			}
+			break
+		}
+		path[i-1].children = append(path[i-1].children, sibling)
+	}
+}
+
+// Load adds the items to the tree. If the tree is empty it is packed with
+// the items, which gives a better tree faster than inserting them one at a
+// time.
+func (t *RTree) Load(items []Item) {
+	if len(items) == 0 {
+		return
+	}
+	if t.size > 0 {
+		for _, item := range items {
+			t.Insert(item)
+		}
+		return
+	}
+	t.root = t.pack(items)
+	t.size = len(items)
+}
+
+// LoadHilbert adds the items to the tree like Load, but packs an empty
+// tree with the items sorted along a Hilbert curve instead of with sort
+// tile recursive packing. It is quicker to pack, and the nodes of items
+// clustered in places overlap less.
+func (t *RTree) LoadHilbert(items []Item) {
+	if len(items) == 0 {
+		return
+	}
+	if t.size > 0 {
+		for _, item := range items {
+			t.Insert(item)
+		}
+		return
+	}
+	t.root = t.packHilbert(items)
+	t.size = len(items)
+}
+
+// Search calls fn for each item whose extent intersects ext, until fn
+// returns false. Extents that only touch intersect.
+func (t *RTree) Search(ext geom.Extent, fn func(Item) bool) {
+	if t.size == 0 {
+		return
+	}
+	t.root.search(ext, fn)
+}
+
+// SearchAll returns the items whose extents intersect ext.
+func (t *RTree) SearchAll(ext geom.Extent) []Item {
+	var items []Item
+	t.Search(ext, func(item Item) bool {
+		items = append(items, item)
+		return true
+	})
+	return items
+}
+
+// SearchCircle calls fn for each item whose extent is within the radius
+// of the center of the circle, until fn returns false. It is the radius
+// search of Search; the items are not in order of their distance, for
+// which see NearestFunc.
+func (t *RTree) SearchCircle(c geom.Circle, fn func(Item) bool) {
+	t.Search(geom.Extent(c.Extent()), func(item Item) bool {
+		if distance(c.Center, item.Extent) > c.Radius {
+			return true
+		}
+		return fn(item)
+	})
+}
+
+// SearchCircleAll returns the items whose extents are within the radius of
+// the center of the circle.
+func (t *RTree) SearchCircleAll(c geom.Circle) []Item {
+	var items []Item
+	t.SearchCircle(c, func(item Item) bool {
+		items = append(items, item)
+		return true
+	})
+	return items
+}
+
+// All calls fn for each item in the tree, until fn returns false.
+func (t *RTree) All(fn func(Item) bool) {
+	t.root.all(fn)
+}
+
+func (n *node) search(ext geom.Extent, fn func(Item) bool) bool {
+	if n.leaf {
+		for _, item := range n.items {
+			if intersects(ext, item.Extent) && !fn(item) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, c := range n.children {
+		if !intersects(ext, c.ext) {
+			continue
+		}
+		var ok bool
+		if contains(ext, c.ext) {
+			ok = c.all(fn)
+		} else {
+			ok = c.search(ext, fn)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *node) all(fn func(Item) bool) bool {
+	if n.leaf {
+		for _, item := range n.items {
+			if !fn(item) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, c := range n.children {
+		if !c.all(fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// count returns the number of entries of the node
+func (n *node) count() int {
+	if n.leaf {
+		return len(n.items)
+	}
+	return len(n.children)
+}
+
+// entryExtent returns the extent of the i-th entry of the node
+func (n *node) entryExtent(i int) geom.Extent {
+	if n.leaf {
+		return n.items[i].Extent
+	}
+	return n.children[i].ext
+}
+
+// extentOf returns the extent of the entries i through j-1
+func (n *node) extentOf(i, j int) geom.Extent {
+	ext := emptyExtent()
+	for ; i < j; i++ {
+		ext = union(ext, n.entryExtent(i))
+	}
+	return ext
+}
+
+// slice returns a node with the entries i through j-1
+func (n *node) slice(i, j int) *node {
+	nn := &node{leaf: n.leaf, ext: n.extentOf(i, j)}
+	if n.leaf {
+		nn.items = append([]Item(nil), n.items[i:j]...)
+	} else {
+		nn.children = append([]*node(nil), n.children[i:j]...)
+	}
+	return nn
+}
+
+// byAxis sorts the entries of a node by their minimum and then maximum
+// along an axis
+type byAxis struct {
+	n    *node
+	axis int
+}
+
+func (s byAxis) Len() int { return s.n.count() }
+func (s byAxis) Less(i, j int) bool {
+	ei, ej := s.n.entryExtent(i), s.n.entryExtent(j)
+	if ei[s.axis] != ej[s.axis] {
+		return ei[s.axis] < ej[s.axis]
+	}
+	return ei[s.axis+2] < ej[s.axis+2]
+}
+func (s byAxis) Swap(i, j int) {
+	if s.n.leaf {
+		s.n.items[i], s.n.items[j] = s.n.items[j], s.n.items[i]
+		return
+	}
+	s.n.children[i], s.n.children[j] = s.n.children[j], s.n.children[i]
+}
+
+// split moves the entries of the overfull node into two nodes, keeping
+// one in n and returning the other. The split is along the axis with the
+// smallest total margin, at the point with the least overlap.
+func (t *RTree) split(n *node) *node {
+	total, m := n.count(), t.minEntries
+
+	axis, bestMargin := 0, math.Inf(1)
+	for a := 0; a < 2; a++ {
+		sort.Sort(byAxis{n, a})
+		var margin float64
+		for k := m; k <= total-m; k++ {
+			margin += perimeter(n.extentOf(0, k)) + perimeter(n.extentOf(k, total))
+		}
+		if margin < bestMargin {
+			axis, bestMargin = a, margin
+		}
+	}
+	sort.Sort(byAxis{n, axis})
+
+	split := m
+	bestOverlap, bestArea := math.Inf(1), math.Inf(1)
+	for k := m; k <= total-m; k++ {
+		e1, e2 := n.extentOf(0, k), n.extentOf(k, total)
+		overlap := intersectionArea(e1, e2)
+		area := e1.Area() + e2.Area()
+		if overlap < bestOverlap || (overlap == bestOverlap && area < bestArea) {
+			split, bestOverlap, bestArea = k, overlap, area
+		}
+	}
+
+	sibling := n.slice(split, total)
+	kept := n.slice(0, split)
+	*n = *kept
+	return sibling
+}
+
+// emptyExtent returns an extent that any extent added to replaces
+func emptyExtent() geom.Extent {
+	return geom.Extent{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+}
+
+func union(a, b geom.Extent) geom.Extent {
+	return geom.Extent{
+		math.Min(a[0], b[0]), math.Min(a[1], b[1]),
+		math.Max(a[2], b[2]), math.Max(a[3], b[3]),
+	}
+}
+
+func unionArea(a, b geom.Extent) float64 {
+	u := union(a, b)
+	return u.Area()
+}
+
+func intersectionArea(a, b geom.Extent) float64 {
+	w := math.Min(a[2], b[2]) - math.Max(a[0], b[0])
+	h := math.Min(a[3], b[3]) - math.Max(a[1], b[1])
+	if w <= 0 || h <= 0 {
+		return 0
+	}
+	return w * h
+}
+
+func perimeter(e geom.Extent) float64 {
+	return 2 * ((e[2] - e[0]) + (e[3] - e[1]))
+}
+
+// intersects reports whether the extents overlap or touch
+func intersects(a, b geom.Extent) bool {
+	return a[0] <= b[2] && b[0] <= a[2] && a[1] <= b[3] && b[1] <= a[3]
+}
+
+// contains reports whether a contains b
+func contains(a, b geom.Extent) bool {
+	return a[0] <= b[0] && b[2] <= a[2] && a[1] <= b[1] && b[3] <= a[3]
+}
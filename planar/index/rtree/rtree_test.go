@@ -0,0 +1,172 @@
+package rtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func randomItems(r *rand.Rand, n int) []Item {
+	items := make([]Item, n)
+	for i := range items {
+		x, y := r.Float64()*1000, r.Float64()*1000
+		items[i] = Item{
+			Extent: geom.Extent{x, y, x + r.Float64()*20, y + r.Float64()*20},
+			Data:   i,
+		}
+	}
+	return items
+}
+
+// checkNode checks the extents of the nodes hold their entries, and that
+// all the leaves are at the same depth, returning the depth
+func checkNode(t *testing.T, n *node, root bool, maxEntries int) int {
+	t.Helper()
+	if n.count() > maxEntries {
+		t.Errorf("node has %v entries, expected at most %v", n.count(), maxEntries)
+	}
+	if !root && n.count() == 0 {
+		t.Errorf("node has no entries")
+	}
+	for i := 0; i < n.count(); i++ {
+		if !contains(n.ext, n.entryExtent(i)) {
+			t.Errorf("node extent %v does not contain %v", n.ext, n.entryExtent(i))
+		}
+	}
+	if n.leaf {
+		return 1
+	}
+	depth := -1
+	for _, c := range n.children {
+		d := checkNode(t, c, false, maxEntries)
+		if depth != -1 && d != depth {
+			t.Errorf("leaves at depths %v and %v", depth, d)
+		}
+		depth = d
+	}
+	return depth + 1
+}
+
+func sortedData(items []Item) []int {
+	ids := make([]int, len(items))
+	for i := range items {
+		ids[i] = items[i].Data.(int)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func TestRTree(t *testing.T) {
+	type tcase struct {
+		n       int
+		loaded  int
+		hilbert bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := rand.New(rand.NewSource(int64(tc.n)))
+			items := randomItems(r, tc.n)
+
+			tree := New(8)
+			if tc.hilbert {
+				tree.LoadHilbert(items[:tc.loaded])
+			} else {
+				tree.Load(items[:tc.loaded])
+			}
+			for _, item := range items[tc.loaded:] {
+				tree.Insert(item)
+			}
+			if tree.Len() != tc.n {
+				t.Errorf("len, expected %v got %v", tc.n, tree.Len())
+			}
+			checkNode(t, tree.root, true, 8)
+
+			for i := 0; i < 20; i++ {
+				x, y := r.Float64()*1000, r.Float64()*1000
+				ext := geom.Extent{x, y, x + r.Float64()*200, y + r.Float64()*200}
+				var exp []Item
+				for _, item := range items {
+					if intersects(ext, item.Extent) {
+						exp = append(exp, item)
+					}
+				}
+				got := tree.SearchAll(ext)
+				if len(got) != len(exp) {
+					t.Fatalf("search %v, expected %v items got %v", ext, len(exp), len(got))
+				}
+				ge, gg := sortedData(exp), sortedData(got)
+				for i := range ge {
+					if ge[i] != gg[i] {
+						t.Fatalf("search %v, expected %v got %v", ext, ge, gg)
+					}
+				}
+
+				pt := [2]float64{x, y}
+				nearest := tree.Nearest(pt, 5)
+				if tc.n >= 5 && len(nearest) != 5 {
+					t.Fatalf("nearest, expected 5 items got %v", len(nearest))
+				}
+				sort.Slice(items, func(i, j int) bool {
+					return distance(pt, items[i].Extent) < distance(pt, items[j].Extent)
+				})
+				for i := range nearest {
+					if d, ed := distance(pt, nearest[i].Extent), distance(pt, items[i].Extent); d != ed {
+						t.Fatalf("nearest %v, expected distance %v got %v", i, ed, d)
+					}
+				}
+
+				c := geom.Circle{Center: pt, Radius: r.Float64() * 100}
+				var within []Item
+				for _, item := range items {
+					if distance(c.Center, item.Extent) <= c.Radius {
+						within = append(within, item)
+					}
+				}
+				ge, gg = sortedData(within), sortedData(tree.SearchCircleAll(c))
+				if len(ge) != len(gg) {
+					t.Fatalf("search circle %v, expected %v items got %v", c, len(ge), len(gg))
+				}
+				for i := range ge {
+					if ge[i] != gg[i] {
+						t.Fatalf("search circle %v, expected %v got %v", c, ge, gg)
+					}
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty":           {},
+		"one":             {n: 1},
+		"inserted":        {n: 1000},
+		"loaded":          {n: 1000, loaded: 1000},
+		"loaded small":    {n: 7, loaded: 7},
+		"loaded, insert":  {n: 1000, loaded: 500},
+		"hilbert":         {n: 1000, loaded: 1000, hilbert: true},
+		"hilbert small":   {n: 7, loaded: 7, hilbert: true},
+		"hilbert, insert": {n: 1000, loaded: 500, hilbert: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestSearchStop(t *testing.T) {
+	tree := New(4)
+	tree.Load(randomItems(rand.New(rand.NewSource(1)), 100))
+	var n int
+	tree.Search(geom.Extent{0, 0, 1000, 1000}, func(Item) bool {
+		n++
+		return n < 10
+	})
+	if n != 10 {
+		t.Errorf("search, expected 10 calls got %v", n)
+	}
+	if ext := New(4).Extent(); ext != nil {
+		t.Errorf("extent of empty tree, expected nil got %v", ext)
+	}
+}
@@ -0,0 +1,86 @@
+package planar
+
+import (
+	"context"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/winding"
+)
+
+const (
+	// insetSearchSteps is the number of times the distance is halved
+	// looking for the largest inset of a polygon that collapses
+	insetSearchSteps = 20
+	// insetMinArea is the area, as a fraction of the square of the
+	// distance, below which the polygons of an inset are slivers left by
+	// the parts that collapsed
+	insetMinArea = 1e-6
+)
+
+// InsetPolygon returns the polygon shrunk by the distance d, the points of
+// the polygon at least d from its outline, which is where a label can be
+// placed without running into the outline.
+//
+// Parts of the polygon thinner than twice d collapse, so the inset can be
+// several polygons where the polygon narrows, and slivers left by the
+// collapse are dropped. If all of the polygon collapses the inset by the
+// largest distance less than d that leaves some of it is returned instead,
+// which is the regions deepest inside the polygon, so the result is only
+// empty if the polygon has no area. The options are those of Buffer.
+func InsetPolygon(ctx context.Context, poly geom.Polygoner, d float64, opts ...BufferOption) (geom.MultiPolygon, error) {
+	if poly == nil {
+		return nil, geom.ErrNilPolygon
+	}
+	if math.IsNaN(d) || math.IsInf(d, 0) || d < 0 {
+		return nil, ErrInvalidBufferDistance
+	}
+	ply := geom.Polygon(poly.LinearRings())
+
+	inset := func(d float64) (geom.MultiPolygon, error) {
+		mply, err := Buffer(ctx, ply, -d, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return dropSlivers(mply, d*d*insetMinArea), nil
+	}
+
+	mply, err := inset(d)
+	if err != nil || len(mply) > 0 || d == 0 {
+		return mply, err
+	}
+
+	// everything collapsed, look for the largest distance that does not
+	lo, hi := 0.0, d
+	for i := 0; i < insetSearchSteps; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		mid := (lo + hi) / 2
+		m, err := inset(mid)
+		if err != nil {
+			return nil, err
+		}
+		if len(m) == 0 {
+			hi = mid
+			continue
+		}
+		lo, mply = mid, m
+	}
+	if mply == nil {
+		return inset(0)
+	}
+	return mply, nil
+}
+
+// dropSlivers returns the polygons with an area more than minArea
+func dropSlivers(mply geom.MultiPolygon, minArea float64) geom.MultiPolygon {
+	var kept geom.MultiPolygon
+	for _, ply := range mply {
+		if len(ply) == 0 || math.Abs(winding.SignedArea(ply[0]...)) <= minArea {
+			continue
+		}
+		kept = append(kept, ply)
+	}
+	return kept
+}
@@ -0,0 +1,87 @@
+package planar
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestInsetPolygon(t *testing.T) {
+	type tcase struct {
+		poly     geom.Polygoner
+		distance float64
+		// area is the expected area, within tolerance
+		area, tolerance float64
+		// polygons is the expected number of polygons
+		polygons int
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := InsetPolygon(context.Background(), tc.poly, tc.distance, WithJoinStyle(JoinMiter))
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if len(got) != tc.polygons {
+				t.Errorf("polygons, expected %v got %v", tc.polygons, len(got))
+			}
+			if a := testArea(got); math.Abs(a-tc.area) > tc.tolerance {
+				t.Errorf("area, expected %v got %v", tc.area, a)
+			}
+		}
+	}
+
+	// two 10 by 10 squares joined by a corridor 10 long and 1 wide
+	dumbbell := geom.Polygon{{
+		{0, 0}, {10, 0}, {10, 4.5}, {20, 4.5}, {20, 0}, {30, 0},
+		{30, 10}, {20, 10}, {20, 5.5}, {10, 5.5}, {10, 10}, {0, 10},
+	}}
+
+	tests := map[string]tcase{
+		"rectangle": {
+			poly:      geom.Polygon{{{0, 0}, {10, 0}, {10, 2}, {0, 2}}},
+			distance:  0.5,
+			area:      9,
+			tolerance: 1e-3,
+			polygons:  1,
+		},
+		"zero": {
+			poly:      geom.Polygon{{{0, 0}, {10, 0}, {10, 2}, {0, 2}}},
+			area:      20,
+			tolerance: 1e-3,
+			polygons:  1,
+		},
+		"dumbbell": {
+			poly:      dumbbell,
+			distance:  1,
+			area:      128,
+			tolerance: 1e-3,
+			polygons:  2,
+		},
+		"collapsed": {
+			// no point is more than 1 from the outline, so what is left is
+			// the middle of the rectangle
+			poly:      geom.Polygon{{{0, 0}, {10, 0}, {10, 2}, {0, 2}}},
+			distance:  5,
+			area:      0,
+			tolerance: 1e-3,
+			polygons:  1,
+		},
+		"negative": {
+			poly:     geom.Polygon{{{0, 0}, {10, 0}, {10, 2}, {0, 2}}},
+			distance: -1,
+			err:      ErrInvalidBufferDistance,
+		},
+		"nil": {
+			distance: 1,
+			err:      geom.ErrNilPolygon,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,183 @@
+// Package interpolate estimates values between sites with known values,
+// such as heights, from the Delaunay triangulation of the sites.
+package interpolate
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/predicates"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay/subdivision"
+)
+
+var (
+	// ErrTooFewSites is returned when there are not three sites that are
+	// not on a line to interpolate between.
+	ErrTooFewSites = errors.New("interpolate: too few sites")
+	// ErrInvalidGrid is returned when the grid has no cells.
+	ErrInvalidGrid = errors.New("interpolate: invalid grid")
+)
+
+// TIN is a triangulated irregular network: the Delaunay triangulation of
+// sites with values. Only points in the triangles of the sites have
+// values, which is most of the convex hull of the sites; see
+// subdivision.NewForPoints, whose frame can hide flat triangles along the
+// outside. It is safe to use from more than one goroutine.
+type TIN struct {
+	mesh []subdivision.MeshTriangle
+	// values are the values of the sites; the points of the frame of the
+	// triangulation are not in it
+	values map[geom.Point]float64
+}
+
+// New returns the TIN of the sites, whose Z is their value. The sites are
+// rounded as subdivision.NewForPoints rounds them; where sites are
+// rounded to the same point the value of the first is used.
+func New(ctx context.Context, sites []geom.PointZ) (*TIN, error) {
+	if len(sites) < 3 {
+		return nil, ErrTooFewSites
+	}
+	xys := make([][2]float64, len(sites))
+	for i, s := range sites {
+		xys[i] = [2]float64{s[0], s[1]}
+	}
+	sd, err := subdivision.NewForPoints(ctx, xys)
+	if err != nil {
+		return nil, err
+	}
+
+	// the points have been rounded in place
+	tin := &TIN{values: make(map[geom.Point]float64, len(sites))}
+	for i, xy := range xys {
+		if _, ok := tin.values[xy]; !ok {
+			tin.values[xy] = sites[i][2]
+		}
+	}
+
+	if tin.mesh, err = sd.Mesh(ctx, true); err != nil {
+		return nil, err
+	}
+	for _, tri := range tin.mesh {
+		if tin.isSite(tri.Points[0]) && tin.isSite(tri.Points[1]) && tin.isSite(tri.Points[2]) {
+			return tin, nil
+		}
+	}
+	return nil, ErrTooFewSites
+}
+
+func (tin *TIN) isSite(pt geom.Point) bool {
+	_, ok := tin.values[pt]
+	return ok
+}
+
+// locate returns the index of the triangle containing the point, walking
+// toward it from the triangle at start; -1 if it is outside the frame
+func (tin *TIN) locate(pt [2]float64, start int) int {
+	t := start
+	for steps := 0; steps <= len(tin.mesh); steps++ {
+		tri := tin.mesh[t]
+		next := t
+		for j := range tri.Points {
+			if predicates.Orient2D(tri.Points[j], tri.Points[(j+1)%3], pt) < 0 {
+				next = tri.Neighbors[j]
+				break
+			}
+		}
+		switch next {
+		case t:
+			return t
+		case -1:
+			return -1
+		}
+		t = next
+	}
+	// the walk went round in circles, look at each of the triangles
+	for i, tri := range tin.mesh {
+		if predicates.Orient2D(tri.Points[0], tri.Points[1], pt) >= 0 &&
+			predicates.Orient2D(tri.Points[1], tri.Points[2], pt) >= 0 &&
+			predicates.Orient2D(tri.Points[2], tri.Points[0], pt) >= 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// locateSites returns the index of the triangle containing the point,
+// preferring a triangle of the sites to one on the frame for points on the
+// edges between them
+func (tin *TIN) locateSites(pt [2]float64) int {
+	t := tin.locate(pt, 0)
+	if t < 0 {
+		return t
+	}
+	tri := tin.mesh[t]
+	if tin.isSite(tri.Points[0]) && tin.isSite(tri.Points[1]) && tin.isSite(tri.Points[2]) {
+		return t
+	}
+	for j, n := range tri.Neighbors {
+		if n < 0 || predicates.Orient2D(tri.Points[j], tri.Points[(j+1)%3], pt) != 0 {
+			continue
+		}
+		if p := tin.mesh[n].Points; tin.isSite(p[0]) && tin.isSite(p[1]) && tin.isSite(p[2]) {
+			return n
+		}
+	}
+	return t
+}
+
+// Linear returns the value at the point from the plane through the
+// triangle it is in, and false if it is not in a triangle of the sites.
+func (tin *TIN) Linear(pt [2]float64) (float64, bool) {
+	return tin.linear(pt, tin.locateSites(pt))
+}
+
+func (tin *TIN) linear(pt [2]float64, t int) (float64, bool) {
+	if t < 0 {
+		return 0, false
+	}
+	tri := tin.mesh[t].Points
+	var z [3]float64
+	for i, p := range tri {
+		v, ok := tin.values[p]
+		if !ok {
+			return 0, false
+		}
+		z[i] = v
+	}
+	area := predicates.Orient2D(tri[0], tri[1], tri[2])
+	// the barycentric weights of the points are the areas of the triangles
+	// the point makes with the opposite edges
+	w0 := predicates.Orient2D(tri[1], tri[2], pt) / area
+	w1 := predicates.Orient2D(tri[2], tri[0], pt) / area
+	w2 := 1 - w0 - w1
+	return w0*z[0] + w1*z[1] + w2*z[2], true
+}
+
+// Grid returns the values of fn, such as tin.Linear or
+// tin.NaturalNeighbor, at the centers of the cells of a grid of cols by
+// rows over the extent. The rows go from the top, at the maximum y, to the
+// bottom, like the rows of an image. Cells without a value are NaN.
+func Grid(ctx context.Context, ext *geom.Extent, cols, rows int, fn func(pt [2]float64) (float64, bool)) ([][]float64, error) {
+	if ext == nil || cols < 1 || rows < 1 {
+		return nil, ErrInvalidGrid
+	}
+	dx, dy := ext.XSpan()/float64(cols), ext.YSpan()/float64(rows)
+	grid := make([][]float64, rows)
+	for r := range grid {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		grid[r] = make([]float64, cols)
+		y := ext.MaxY() - (float64(r)+0.5)*dy
+		for c := range grid[r] {
+			v, ok := fn([2]float64{ext.MinX() + (float64(c)+0.5)*dx, y})
+			if !ok {
+				v = math.NaN()
+			}
+			grid[r][c] = v
+		}
+	}
+	return grid, nil
+}
@@ -0,0 +1,119 @@
+package interpolate
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestInterpolate(t *testing.T) {
+	ctx := context.Background()
+	plane := func(pt [2]float64) float64 { return 2*pt[0] - 3*pt[1] + 1 }
+
+	rng := rand.New(rand.NewSource(1))
+	sites := []geom.PointZ{{0, 0}, {100, 0}, {100, 100}, {0, 100}}
+	for i := 0; i < 200; i++ {
+		sites = append(sites, geom.PointZ{
+			math.Round(rng.Float64()*100000) / 1000,
+			math.Round(rng.Float64()*100000) / 1000,
+		})
+	}
+	for i := range sites {
+		sites[i][2] = plane([2]float64{sites[i][0], sites[i][1]})
+	}
+	tin, err := New(ctx, sites)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	type tcase struct {
+		pt [2]float64
+		ok bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			// both reproduce a plane
+			methods := map[string]func([2]float64) (float64, bool){
+				"linear":           tin.Linear,
+				"natural neighbor": tin.NaturalNeighbor,
+			}
+			for name, m := range methods {
+				v, ok := m(tc.pt)
+				if ok != tc.ok {
+					t.Fatalf("%v ok, expected %v got %v", name, tc.ok, ok)
+				}
+				if ok && math.Abs(v-plane(tc.pt)) > 1e-6 {
+					t.Errorf("%v value, expected %v got %v", name, plane(tc.pt), v)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"inside":    {pt: [2]float64{42.5, 17.25}, ok: true},
+		"on a site": {pt: [2]float64{sites[10][0], sites[10][1]}, ok: true},
+		"corner":    {pt: [2]float64{100, 100}, ok: true},
+		"outside":   {pt: [2]float64{-1, 50}},
+		"far away":  {pt: [2]float64{1e9, -1e9}},
+	}
+	for i := 0; i < 50; i++ {
+		pt := [2]float64{rng.Float64() * 100, rng.Float64() * 100}
+		tests["random "+string(rune('a'+i%26))+string(rune('a'+i/26))] = tcase{pt: pt, ok: true}
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestNaturalNeighbor(t *testing.T) {
+	// four sites on a square, the center is equally weighted between them
+	// by natural neighbors, but by a triangle by linear
+	tin, err := New(context.Background(), []geom.PointZ{{0, 0, 0}, {10, 0, 0}, {10, 10, 4}, {0, 10, 0}})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	v, ok := tin.NaturalNeighbor([2]float64{5, 5})
+	if !ok || math.Abs(v-1) > 1e-9 {
+		t.Errorf("center, expected 1 got %v %v", v, ok)
+	}
+
+	if _, err := New(context.Background(), []geom.PointZ{{0, 0, 1}, {1, 1, 1}, {2, 2, 1}}); err != ErrTooFewSites {
+		t.Errorf("sites on a line error, expected %v got %v", ErrTooFewSites, err)
+	}
+}
+
+func TestGrid(t *testing.T) {
+	ctx := context.Background()
+	tin, err := New(ctx, []geom.PointZ{{0, 0, 0}, {10, 0, 10}, {10, 10, 20}, {0, 10, 10}})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	grid, err := Grid(ctx, geom.NewExtent([2]float64{0, 0}, [2]float64{20, 10}), 4, 2, tin.Linear)
+	if err != nil {
+		t.Fatalf("grid error, expected nil got %v", err)
+	}
+	expected := [][]float64{
+		{10, 15, math.NaN(), math.NaN()},
+		{5, 10, math.NaN(), math.NaN()},
+	}
+	if len(grid) != len(expected) {
+		t.Fatalf("rows, expected %v got %v", len(expected), len(grid))
+	}
+	for r := range expected {
+		for c, exp := range expected[r] {
+			got := grid[r][c]
+			if math.IsNaN(exp) != math.IsNaN(got) || (!math.IsNaN(exp) && math.Abs(got-exp) > 1e-9) {
+				t.Errorf("cell %v %v, expected %v got %v", r, c, exp, got)
+			}
+		}
+	}
+
+	if _, err := Grid(ctx, geom.NewExtent([2]float64{0, 0}, [2]float64{1, 1}), 0, 2, tin.Linear); err != ErrInvalidGrid {
+		t.Errorf("no columns error, expected %v got %v", ErrInvalidGrid, err)
+	}
+}
@@ -0,0 +1,122 @@
+package interpolate
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom/planar/predicates"
+	"github.com/go-spatial/geom/winding"
+)
+
+// NaturalNeighbor returns the value at the point by Sibson's natural
+// neighbor interpolation: the average of the values of the sites whose
+// Voronoi cells would shrink if the point were added as a site, weighted by
+// the area each would lose. It is smooth between the sites, except at the
+// sites themselves, and is the same as Linear on the outside edges of the
+// triangles. False is returned if the point is not in a triangle of the
+// sites.
+func (tin *TIN) NaturalNeighbor(pt [2]float64) (float64, bool) {
+	t := tin.locateSites(pt)
+	if t < 0 {
+		return 0, false
+	}
+	for _, p := range tin.mesh[t].Points {
+		if p == pt {
+			v, ok := tin.values[p]
+			return v, ok
+		}
+	}
+
+	// the triangles whose circumcircles the point is in, which adding it
+	// would remove
+	cavity := map[int]bool{t: true}
+	stack := []int{t}
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, n := range tin.mesh[c].Neighbors {
+			if n < 0 || cavity[n] {
+				continue
+			}
+			p := tin.mesh[n].Points
+			if predicates.InCircle(p[0], p[1], p[2], pt) > 0 {
+				cavity[n] = true
+				stack = append(stack, n)
+			}
+		}
+	}
+
+	var sum, total float64
+	for c := range cavity {
+		tri := tin.mesh[c]
+		for j, n := range tri.Neighbors {
+			if n >= 0 && cavity[n] {
+				continue
+			}
+			// the edge is on the outside of the cavity, weight the value
+			// of the point at its end
+			z, ok := tin.values[tri.Points[(j+1)%3]]
+			if !ok {
+				// the point is by the frame
+				return tin.linear(pt, t)
+			}
+			area, ok := tin.stolenArea(pt, c, j)
+			if !ok {
+				return tin.linear(pt, t)
+			}
+			sum += area * z
+			total += area
+		}
+	}
+	if !(total > 0) {
+		return tin.linear(pt, t)
+	}
+	return sum / total, true
+}
+
+// stolenArea returns the area of the Voronoi cell of the point v, at the
+// end of the edge j of the triangle c on the outside of the cavity, that
+// is in the cell of pt: the polygon through the circumcenters of the new
+// triangles either side of v and the old triangles around v in the cavity
+func (tin *TIN) stolenArea(pt [2]float64, c, j int) (float64, bool) {
+	tri := tin.mesh[c]
+	u, v := tri.Points[j], tri.Points[(j+1)%3]
+	ring := [][2]float64{circumcenter(pt, u, v)}
+
+	// turn around v through the triangles of the cavity until an edge on
+	// the outside of it
+	k := (j + 1) % 3
+	for steps := 0; steps <= len(tin.mesh); steps++ {
+		ring = append(ring, circumcenter(tri.Points[0], tri.Points[1], tri.Points[2]))
+		w := tri.Points[(k+1)%3]
+		n := tri.Neighbors[k]
+		if n < 0 || !tin.inCircle(n, pt) {
+			ring = append(ring, circumcenter(pt, v, w))
+			return math.Abs(winding.SignedArea(ring...)), true
+		}
+		// the edge v, w is w, v in the neighbor, the next edge around v
+		// is the one starting at v
+		tri = tin.mesh[n]
+		for i, p := range tri.Points {
+			if p == v {
+				k = i
+			}
+		}
+	}
+	return 0, false
+}
+
+// inCircle reports whether the point is in the circumcircle of the
+// triangle t
+func (tin *TIN) inCircle(t int, pt [2]float64) bool {
+	p := tin.mesh[t].Points
+	return predicates.InCircle(p[0], p[1], p[2], pt) > 0
+}
+
+// circumcenter returns the center of the circle through the points
+func circumcenter(a, b, c [2]float64) [2]float64 {
+	bx, by := b[0]-a[0], b[1]-a[1]
+	cx, cy := c[0]-a[0], c[1]-a[1]
+	d := 2 * (bx*cy - by*cx)
+	b2, c2 := bx*bx+by*by, cx*cx+cy*cy
+	return [2]float64{a[0] + (cy*b2-by*c2)/d, a[1] + (bx*c2-cx*b2)/d}
+}
@@ -227,3 +227,123 @@ func TestAreLinesColinear(t *testing.T) {
 	}
 
 }
+
+func TestIntersectSegments(t *testing.T) {
+	type tcase struct {
+		l1, l2 geom.Line
+		exp    SegmentIntersection
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got := IntersectSegments(tc.l1, tc.l2)
+			if got.Kind != tc.exp.Kind {
+				t.Fatalf("kind, expected %v got %v", tc.exp.Kind, got.Kind)
+			}
+			if got.Kind == NoIntersection {
+				return
+			}
+			if !cmp.LineStringEqual(got.Segment[:], tc.exp.Segment[:]) {
+				t.Errorf("segment, expected %v got %v", tc.exp.Segment, got.Segment)
+			}
+			if !cmp.PointEqual(got.T, tc.exp.T) || !cmp.PointEqual(got.U, tc.exp.U) {
+				t.Errorf("parameters, expected %v %v got %v %v", tc.exp.T, tc.exp.U, got.T, got.U)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"proper": {
+			l1: geom.Line{{-10, 0}, {10, 0}},
+			l2: geom.Line{{0, 10}, {0, -30}},
+			exp: SegmentIntersection{
+				Kind:    ProperIntersection,
+				Segment: geom.Line{{0, 0}, {0, 0}},
+				T:       [2]float64{0.5, 0.5},
+				U:       [2]float64{0.25, 0.25},
+			},
+		},
+		"apart": {
+			l1:  geom.Line{{-10, 0}, {-1, 0}},
+			l2:  geom.Line{{0, 10}, {0, -10}},
+			exp: SegmentIntersection{Kind: NoIntersection},
+		},
+		"parallel": {
+			l1:  geom.Line{{-10, 0}, {10, 0}},
+			l2:  geom.Line{{-10, 1}, {10, 1}},
+			exp: SegmentIntersection{Kind: NoIntersection},
+		},
+		"colinear apart": {
+			l1:  geom.Line{{0, 0}, {1, 1}},
+			l2:  geom.Line{{2, 2}, {3, 3}},
+			exp: SegmentIntersection{Kind: NoIntersection},
+		},
+		"end touches middle": {
+			l1: geom.Line{{0, 0}, {10, 0}},
+			l2: geom.Line{{5, 5}, {5, 0}},
+			exp: SegmentIntersection{
+				Kind:    TouchIntersection,
+				Segment: geom.Line{{5, 0}, {5, 0}},
+				T:       [2]float64{0.5, 0.5},
+				U:       [2]float64{1, 1},
+			},
+		},
+		"ends touch": {
+			l1: geom.Line{{0, 0}, {10, 0}},
+			l2: geom.Line{{10, 0}, {10, 10}},
+			exp: SegmentIntersection{
+				Kind:    TouchIntersection,
+				Segment: geom.Line{{10, 0}, {10, 0}},
+				T:       [2]float64{1, 1},
+				U:       [2]float64{0, 0},
+			},
+		},
+		"colinear ends touch": {
+			l1: geom.Line{{0, 0}, {10, 0}},
+			l2: geom.Line{{20, 0}, {10, 0}},
+			exp: SegmentIntersection{
+				Kind:    TouchIntersection,
+				Segment: geom.Line{{10, 0}, {10, 0}},
+				T:       [2]float64{1, 1},
+				U:       [2]float64{1, 1},
+			},
+		},
+		"overlap": {
+			l1: geom.Line{{0, 0}, {10, 0}},
+			l2: geom.Line{{15, 0}, {5, 0}},
+			exp: SegmentIntersection{
+				Kind:    OverlapIntersection,
+				Segment: geom.Line{{5, 0}, {10, 0}},
+				T:       [2]float64{0.5, 1},
+				U:       [2]float64{1, 0.5},
+			},
+		},
+		"contained": {
+			l1: geom.Line{{0, 0}, {8, 8}},
+			l2: geom.Line{{2, 2}, {4, 4}},
+			exp: SegmentIntersection{
+				Kind:    OverlapIntersection,
+				Segment: geom.Line{{2, 2}, {4, 4}},
+				T:       [2]float64{0.25, 0.5},
+				U:       [2]float64{0, 1},
+			},
+		},
+		"point on segment": {
+			l1: geom.Line{{0, 0}, {10, 0}},
+			l2: geom.Line{{2, 0}, {2, 0}},
+			exp: SegmentIntersection{
+				Kind:    TouchIntersection,
+				Segment: geom.Line{{2, 0}, {2, 0}},
+				T:       [2]float64{0.2, 0.2},
+			},
+		},
+		"point off segment": {
+			l1:  geom.Line{{0, 0}, {10, 0}},
+			l2:  geom.Line{{2, 1}, {2, 1}},
+			exp: SegmentIntersection{Kind: NoIntersection},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,184 @@
+// Package linear does linear referencing on line strings: finding the
+// point a fraction or distance along a line, how far along a line a point
+// is, and the part of a line between two places along it, as the PostGIS
+// functions ST_LineInterpolatePoint, ST_LineLocatePoint and
+// ST_LineSubstring do.
+//
+// The functions taking fractions go from 0 at the start of the line to 1
+// at its end; the ones ending in At take distances along the line, from 0
+// to its length.
+package linear
+
+import (
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+var (
+	// ErrEmptyLine is returned for lines with no points.
+	ErrEmptyLine = errors.New("linear: empty line")
+	// ErrInvalidFraction is returned for fractions outside 0 to 1, or
+	// starts after ends.
+	ErrInvalidFraction = errors.New("linear: invalid fraction")
+	// ErrInvalidDistance is returned for distances outside 0 to the
+	// length of the line, or starts after ends.
+	ErrInvalidDistance = errors.New("linear: invalid distance")
+)
+
+// Length returns the length of the line.
+func Length(ls geom.LineString) float64 {
+	var l float64
+	for i := 1; i < len(ls); i++ {
+		l += segmentLength(ls[i-1], ls[i])
+	}
+	return l
+}
+
+// LineInterpolatePoint returns the point the fraction of the length of
+// the line along it.
+func LineInterpolatePoint(ls geom.LineString, fraction float64) (geom.Point, error) {
+	if err := checkFractions(fraction, fraction); err != nil {
+		return geom.Point{}, err
+	}
+	return LineInterpolatePointAt(ls, fraction*Length(ls))
+}
+
+// LineInterpolatePointAt returns the point the distance along the line.
+func LineInterpolatePointAt(ls geom.LineString, distance float64) (geom.Point, error) {
+	if len(ls) == 0 {
+		return geom.Point{}, ErrEmptyLine
+	}
+	if err := checkDistances(ls, distance, distance); err != nil {
+		return geom.Point{}, err
+	}
+	i, t := locate(ls, distance)
+	return geom.Point(lerp(ls, i, t)), nil
+}
+
+// LineLocatePoint returns the fraction of the length of the line along it
+// of the point of the line nearest the point; 0 for lines with no length.
+func LineLocatePoint(ls geom.LineString, pt [2]float64) (float64, error) {
+	d, err := LineLocatePointAt(ls, pt)
+	if err != nil {
+		return 0, err
+	}
+	l := Length(ls)
+	if l == 0 {
+		return 0, nil
+	}
+	return math.Min(1, d/l), nil
+}
+
+// LineLocatePointAt returns the distance along the line of the point of
+// the line nearest the point. If more than one point of the line is
+// nearest, the first along it is used.
+func LineLocatePointAt(ls geom.LineString, pt [2]float64) (float64, error) {
+	if len(ls) == 0 {
+		return 0, ErrEmptyLine
+	}
+	var (
+		best     float64
+		bestDist = math.Hypot(pt[0]-ls[0][0], pt[1]-ls[0][1])
+		along    float64
+	)
+	for i := 1; i < len(ls); i++ {
+		a, b := ls[i-1], ls[i]
+		l := segmentLength(a, b)
+		if l > 0 {
+			dx, dy := b[0]-a[0], b[1]-a[1]
+			t := math.Max(0, math.Min(1, ((pt[0]-a[0])*dx+(pt[1]-a[1])*dy)/(l*l)))
+			near := [2]float64{a[0] + t*dx, a[1] + t*dy}
+			if d := math.Hypot(pt[0]-near[0], pt[1]-near[1]); d < bestDist {
+				best, bestDist = along+t*l, d
+			}
+		}
+		along += l
+	}
+	return best, nil
+}
+
+// LineSubstring returns the part of the line between the fractions of its
+// length along it. If start and end are the same, the line has that point
+// twice.
+func LineSubstring(ls geom.LineString, start, end float64) (geom.LineString, error) {
+	if err := checkFractions(start, end); err != nil {
+		return nil, err
+	}
+	l := Length(ls)
+	return LineSubstringAt(ls, start*l, end*l)
+}
+
+// LineSubstringAt returns the part of the line between the distances along
+// it. If start and end are the same, the line has that point twice.
+func LineSubstringAt(ls geom.LineString, start, end float64) (geom.LineString, error) {
+	if len(ls) == 0 {
+		return nil, ErrEmptyLine
+	}
+	if err := checkDistances(ls, start, end); err != nil {
+		return nil, err
+	}
+	si, st := locate(ls, start)
+	ei, et := locate(ls, end)
+
+	sub := geom.LineString{lerp(ls, si, st)}
+	for i := si + 1; i <= ei; i++ {
+		if ls[i] != sub[len(sub)-1] {
+			sub = append(sub, ls[i])
+		}
+	}
+	if pt := lerp(ls, ei, et); pt != sub[len(sub)-1] || len(sub) == 1 {
+		sub = append(sub, pt)
+	}
+	return sub, nil
+}
+
+// locate returns the segment, from point i to i+1, the distance along the
+// line is on, and how far along the segment it is, from 0 to 1. Distances
+// at the end of the line are at the end of the last segment.
+func locate(ls geom.LineString, distance float64) (i int, t float64) {
+	var along float64
+	for i = 0; i+1 < len(ls); i++ {
+		l := segmentLength(ls[i], ls[i+1])
+		if l > 0 && distance <= along+l {
+			return i, math.Max(0, (distance-along)/l)
+		}
+		along += l
+	}
+	// past the last segment with a length, or a line with one point
+	if len(ls) > 1 {
+		return len(ls) - 2, 1
+	}
+	return 0, 0
+}
+
+// lerp returns the point t along the segment from point i to i+1
+func lerp(ls geom.LineString, i int, t float64) [2]float64 {
+	if t == 0 || i+1 >= len(ls) {
+		return ls[i]
+	}
+	if t == 1 {
+		return ls[i+1]
+	}
+	a, b := ls[i], ls[i+1]
+	return [2]float64{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])}
+}
+
+func segmentLength(a, b [2]float64) float64 { return math.Hypot(b[0]-a[0], b[1]-a[1]) }
+
+func checkFractions(start, end float64) error {
+	if !(0 <= start && start <= end && end <= 1) {
+		return ErrInvalidFraction
+	}
+	return nil
+}
+
+func checkDistances(ls geom.LineString, start, end float64) error {
+	// allow for the rounding of fractions of the length
+	l := Length(ls) * (1 + 1e-12)
+	if !(0 <= start && start <= end && end <= l) {
+		return ErrInvalidDistance
+	}
+	return nil
+}
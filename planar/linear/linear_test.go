@@ -0,0 +1,135 @@
+package linear
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+// route is 10 long, 4 along x then 6 along y
+var route = geom.LineString{{0, 0}, {4, 0}, {4, 6}}
+
+func TestLineInterpolatePoint(t *testing.T) {
+	type tcase struct {
+		ls       geom.LineString
+		fraction float64
+		expected geom.Point
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := LineInterpolatePoint(tc.ls, tc.fraction)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if got != tc.expected {
+				t.Errorf("point, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"start":         {ls: route, fraction: 0, expected: geom.Point{0, 0}},
+		"first segment": {ls: route, fraction: 0.2, expected: geom.Point{2, 0}},
+		"vertex":        {ls: route, fraction: 0.4, expected: geom.Point{4, 0}},
+		"last segment":  {ls: route, fraction: 0.7, expected: geom.Point{4, 3}},
+		"end":           {ls: route, fraction: 1, expected: geom.Point{4, 6}},
+		"no length":     {ls: geom.LineString{{1, 1}, {1, 1}}, fraction: 0.5, expected: geom.Point{1, 1}},
+		"too far":       {ls: route, fraction: 1.5, err: ErrInvalidFraction},
+		"empty":         {fraction: 0.5, err: ErrEmptyLine},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestLineLocatePoint(t *testing.T) {
+	type tcase struct {
+		pt       [2]float64
+		fraction float64
+		distance float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			f, err := LineLocatePoint(route, tc.pt)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if f != tc.fraction {
+				t.Errorf("fraction, expected %v got %v", tc.fraction, f)
+			}
+			d, err := LineLocatePointAt(route, tc.pt)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if d != tc.distance {
+				t.Errorf("distance, expected %v got %v", tc.distance, d)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"on the line": {pt: [2]float64{2, 0}, fraction: 0.2, distance: 2},
+		"beside":      {pt: [2]float64{6, 3}, fraction: 0.7, distance: 7},
+		"before":      {pt: [2]float64{-3, -1}, fraction: 0, distance: 0},
+		"after":       {pt: [2]float64{4, 9}, fraction: 1, distance: 10},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	if _, err := LineLocatePoint(nil, [2]float64{}); err != ErrEmptyLine {
+		t.Errorf("empty error, expected %v got %v", ErrEmptyLine, err)
+	}
+}
+
+func TestLineSubstring(t *testing.T) {
+	type tcase struct {
+		start, end float64
+		expected   geom.LineString
+		err        error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := LineSubstring(route, tc.start, tc.end)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("line, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"all":      {start: 0, end: 1, expected: route},
+		"across":   {start: 0.2, end: 0.7, expected: geom.LineString{{2, 0}, {4, 0}, {4, 3}}},
+		"within":   {start: 0.5, end: 0.7, expected: geom.LineString{{4, 1}, {4, 3}}},
+		"vertices": {start: 0.4, end: 1, expected: geom.LineString{{4, 0}, {4, 6}}},
+		"point":    {start: 0.2, end: 0.2, expected: geom.LineString{{2, 0}, {2, 0}}},
+		"reversed": {start: 0.7, end: 0.2, err: ErrInvalidFraction},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestLineSubstringAt(t *testing.T) {
+	got, err := LineSubstringAt(route, 1, 5)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if exp := (geom.LineString{{1, 0}, {4, 0}, {4, 1}}); !reflect.DeepEqual(got, exp) {
+		t.Errorf("line, expected %v got %v", exp, got)
+	}
+	if _, err := LineSubstringAt(route, 1, 11); err != ErrInvalidDistance {
+		t.Errorf("too far error, expected %v got %v", ErrInvalidDistance, err)
+	}
+}
@@ -0,0 +1,120 @@
+package planar
+
+import (
+	"github.com/go-spatial/geom"
+)
+
+// LineMerge joins the lines end to end, where the ends of only two lines
+// meet, into the longest lines it can. Lines are not joined where three or
+// more ends meet, nor where they cross or touch other than at their ends.
+//
+// Lines may be reversed to join them; each merged line goes in the
+// direction of most of the lines it is made from. Lines that join up into
+// a ring are returned closed. Lines with fewer than two points are
+// dropped.
+func LineMerge(lines []geom.LineString) []geom.LineString {
+	// an end of a line, last is true for its last point
+	type end struct {
+		line int
+		last bool
+	}
+	var lns []geom.LineString
+	for _, ln := range lines {
+		if len(ln) >= 2 {
+			lns = append(lns, ln)
+		}
+	}
+	at := make(map[[2]float64][]end)
+	for i, ln := range lns {
+		at[ln[0]] = append(at[ln[0]], end{line: i})
+		at[ln[len(ln)-1]] = append(at[ln[len(ln)-1]], end{line: i, last: true})
+	}
+
+	used := make([]bool, len(lns))
+	// walk joins the lines, starting from the end e, until it gets to a
+	// point where other than two ends meet, or back to where it started
+	walk := func(e end) geom.LineString {
+		ln := lns[e.line]
+		start := ln[0]
+		if e.last {
+			start = ln[len(ln)-1]
+		}
+		merged := geom.LineString{start}
+		var count, forwards int
+		for {
+			used[e.line] = true
+			count++
+			ln = lns[e.line]
+			if e.last {
+				for i := len(ln) - 2; i >= 0; i-- {
+					merged = append(merged, ln[i])
+				}
+			} else {
+				merged = append(merged, ln[1:]...)
+				forwards++
+			}
+
+			pt := merged[len(merged)-1]
+			ends := at[pt]
+			if len(ends) != 2 || pt == start {
+				break
+			}
+			// the end that is not the one the walk got to
+			next := ends[0]
+			if next == (end{line: e.line, last: !e.last}) {
+				next = ends[1]
+			}
+			if used[next.line] {
+				break
+			}
+			e = next
+		}
+		if 2*forwards < count {
+			for i, j := 0, len(merged)-1; i < j; i, j = i+1, j-1 {
+				merged[i], merged[j] = merged[j], merged[i]
+			}
+		}
+		return merged
+	}
+
+	var merged []geom.LineString
+	for i, ln := range lns {
+		for _, e := range []end{{line: i}, {line: i, last: true}} {
+			pt := ln[0]
+			if e.last {
+				pt = ln[len(ln)-1]
+			}
+			if !used[i] && len(at[pt]) != 2 {
+				merged = append(merged, walk(e))
+			}
+		}
+	}
+	// the rings, where only two ends meet at every end
+	for i := range lns {
+		if !used[i] {
+			merged = append(merged, walk(end{line: i}))
+		}
+	}
+	return merged
+}
+
+// DissolveLines returns the lines with the segments that are in them more
+// than once, in either direction, only kept the first time, merged with
+// LineMerge. Segments of no length are removed.
+func DissolveLines(lines []geom.LineString) []geom.LineString {
+	var (
+		segs []geom.LineString
+		seen = make(map[geom.Line]bool)
+	)
+	for _, ln := range lines {
+		for i := 1; i < len(ln); i++ {
+			a, b := ln[i-1], ln[i]
+			if a == b || seen[geom.Line{a, b}] || seen[geom.Line{b, a}] {
+				continue
+			}
+			seen[geom.Line{a, b}] = true
+			segs = append(segs, geom.LineString{a, b})
+		}
+	}
+	return LineMerge(segs)
+}
@@ -0,0 +1,79 @@
+package planar
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestLineMerge(t *testing.T) {
+	type tcase struct {
+		lines    []geom.LineString
+		expected []geom.LineString
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got := LineMerge(tc.lines)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("lines, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"end to end": {
+			lines:    []geom.LineString{{{0, 0}, {1, 0}}, {{1, 0}, {2, 1}}, {{2, 1}, {3, 1}}},
+			expected: []geom.LineString{{{0, 0}, {1, 0}, {2, 1}, {3, 1}}},
+		},
+		"out of order": {
+			lines:    []geom.LineString{{{2, 1}, {3, 1}}, {{0, 0}, {1, 0}}, {{1, 0}, {2, 1}}},
+			expected: []geom.LineString{{{0, 0}, {1, 0}, {2, 1}, {3, 1}}},
+		},
+		"reversed": {
+			lines:    []geom.LineString{{{0, 0}, {1, 0}}, {{2, 0}, {1, 0}}, {{2, 0}, {3, 0}}},
+			expected: []geom.LineString{{{0, 0}, {1, 0}, {2, 0}, {3, 0}}},
+		},
+		"mostly reversed": {
+			lines:    []geom.LineString{{{0, 0}, {1, 0}}, {{2, 0}, {1, 0}}, {{3, 0}, {2, 0}}},
+			expected: []geom.LineString{{{3, 0}, {2, 0}, {1, 0}, {0, 0}}},
+		},
+		"junction": {
+			lines: []geom.LineString{
+				{{0, 0}, {1, 0}}, {{1, 0}, {2, 0}}, {{2, 0}, {3, 0}},
+				{{2, 0}, {2, 1}}, {{2, 1}, {2, 2}},
+			},
+			expected: []geom.LineString{
+				{{0, 0}, {1, 0}, {2, 0}}, {{2, 0}, {3, 0}}, {{2, 0}, {2, 1}, {2, 2}},
+			},
+		},
+		"ring": {
+			lines:    []geom.LineString{{{0, 0}, {1, 0}}, {{1, 0}, {1, 1}}, {{1, 1}, {0, 0}}},
+			expected: []geom.LineString{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		},
+		"crossing": {
+			lines:    []geom.LineString{{{0, 0}, {2, 2}}, {{0, 2}, {2, 0}}},
+			expected: []geom.LineString{{{0, 0}, {2, 2}}, {{0, 2}, {2, 0}}},
+		},
+		"short": {
+			lines: []geom.LineString{{{0, 0}}, {}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDissolveLines(t *testing.T) {
+	got := DissolveLines([]geom.LineString{
+		{{0, 0}, {1, 0}, {2, 0}},
+		{{2, 0}, {1, 0}},
+		{{2, 0}, {2, 0}, {3, 0}},
+	})
+	expected := []geom.LineString{{{0, 0}, {1, 0}, {2, 0}, {3, 0}}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("lines, expected %v got %v", expected, got)
+	}
+}
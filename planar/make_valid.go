@@ -0,0 +1,84 @@
+package planar
+
+import (
+	"context"
+
+	"github.com/go-spatial/geom"
+)
+
+// MakeValid repairs the polygons of the geometry, returning valid polygons
+// covering the same area. Polygons, MultiPolygons and collections of them
+// are supported.
+//
+// The area of a polygon is the area inside an odd number of its rings, so
+// self-intersecting rings are split into their lobes, the direction of the
+// rings does not matter, rings that are repeated are only counted once and
+// edges that double back on themselves are removed. The area of a
+// MultiPolygon is the union of the areas of its polygons.
+//
+// The rings of the result are not closed; the outer rings are counter
+// clockwise and the holes clockwise, with y going up, unless the Options of
+// the context say otherwise.
+func MakeValid(ctx context.Context, g geom.Geometry) (geom.MultiPolygon, error) {
+	return MakeValidWithPrecision(ctx, g, FromContext(ctx).PrecisionModel())
+}
+
+// MakeValidWithPrecision is MakeValid with the points of the result
+// rounded to the grid of the precision model; see OverlayWithPrecision.
+func MakeValidWithPrecision(ctx context.Context, g geom.Geometry, pm geom.PrecisionModel) (geom.MultiPolygon, error) {
+	var o overlayBuilder
+	plys, err := o.add(nil, g)
+	if err != nil {
+		return nil, err
+	}
+	for i := range plys {
+		ply := plys[i].(polygonPiece)
+		ply.rings = uniqueRings(ply.rings)
+		plys[i] = ply
+	}
+	return buildPolygons(ctx, o.segments, pm, func(pt, dir [2]float64, tol float64) bool {
+		return coveredBy(plys, pt, dir, tol)
+	})
+}
+
+// uniqueRings removes the rings that have the same points as an earlier
+// ring, in either direction
+func uniqueRings(rings [][][2]float64) [][][2]float64 {
+	ret := rings[:0:0]
+	for _, r := range rings {
+		dup := false
+		for _, u := range ret {
+			if sameRing(r, u) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
+
+// sameRing reports whether the rings have the same points in the same
+// cyclic order, in either direction
+func sameRing(r1, r2 [][2]float64) bool {
+	n := len(r1)
+	if n != len(r2) || n == 0 {
+		return false
+	}
+	for off := range r2 {
+		if r2[off] != r1[0] {
+			continue
+		}
+		fwd, bwd := true, true
+		for i := 0; i < n && (fwd || bwd); i++ {
+			fwd = fwd && r1[i] == r2[(off+i)%n]
+			bwd = bwd && r1[i] == r2[(off-i+n)%n]
+		}
+		if fwd || bwd {
+			return true
+		}
+	}
+	return false
+}
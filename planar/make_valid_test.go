@@ -0,0 +1,123 @@
+package planar
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/winding"
+)
+
+func TestMakeValid(t *testing.T) {
+	type tcase struct {
+		geom geom.Geometry
+		area float64
+		// polygons is the expected number of polygons
+		polygons int
+		// holes is the expected number of holes in all the polygons
+		holes int
+		err   error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := MakeValid(context.Background(), tc.geom)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if len(got) != tc.polygons {
+				t.Errorf("polygons, expected %v got %v", tc.polygons, len(got))
+			}
+			var holes int
+			for _, ply := range got {
+				holes += len(ply) - 1
+				for i, ring := range ply {
+					// outer rings are counter clockwise, holes clockwise
+					if (winding.SignedArea(ring...) > 0) != (i == 0) {
+						t.Errorf("ring %v has the wrong direction", i)
+					}
+				}
+			}
+			if holes != tc.holes {
+				t.Errorf("holes, expected %v got %v", tc.holes, holes)
+			}
+			if a := testArea(got); math.Abs(a-tc.area) > 1e-9 {
+				t.Errorf("area, expected %v got %v", tc.area, a)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"valid": {
+			geom:     geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			area:     100,
+			polygons: 1,
+		},
+		"clockwise": {
+			geom:     geom.Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}},
+			area:     100,
+			polygons: 1,
+		},
+		"bowtie": {
+			geom:     geom.Polygon{{{0, 0}, {10, 10}, {10, 0}, {0, 10}}},
+			area:     50,
+			polygons: 2,
+		},
+		"figure eight ring": {
+			geom:     geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {20, 10}, {20, 20}, {10, 20}, {10, 10}, {0, 10}}},
+			area:     200,
+			polygons: 2,
+		},
+		"spike": {
+			geom:     geom.Polygon{{{0, 0}, {10, 0}, {10, 5}, {15, 5}, {10, 5}, {10, 10}, {0, 10}}},
+			area:     100,
+			polygons: 1,
+		},
+		"duplicate ring": {
+			geom: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{2, 2}, {2, 4}, {4, 4}, {4, 2}},
+				{{4, 2}, {4, 4}, {2, 4}, {2, 2}},
+			},
+			area:     96,
+			polygons: 1,
+			holes:    1,
+		},
+		"hole outside the shell": {
+			geom: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{20, 0}, {20, 5}, {25, 5}, {25, 0}},
+			},
+			area:     125,
+			polygons: 2,
+		},
+		"hole crossing the shell": {
+			geom: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{5, 2}, {5, 8}, {15, 8}, {15, 2}},
+			},
+			area:     100 - 30 + 30,
+			polygons: 2,
+		},
+		"overlapping polygons": {
+			geom: geom.MultiPolygon{
+				{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+				{{{5, 5}, {15, 5}, {15, 15}, {5, 15}}},
+			},
+			area:     175,
+			polygons: 1,
+		},
+		"empty": {
+			geom: geom.MultiPolygon{},
+		},
+		"line": {
+			geom: geom.Point{1, 2},
+			err:  geom.ErrUnknownGeometry{Geom: geom.Point{1, 2}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
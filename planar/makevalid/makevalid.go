@@ -12,9 +12,9 @@ import (
 	"github.com/go-spatial/geom"
 	pkgcmp "github.com/go-spatial/geom/cmp"
 	"github.com/go-spatial/geom/planar"
-	"github.com/go-spatial/geom/planar/intersect"
 	"github.com/go-spatial/geom/planar/makevalid/hitmap"
 	"github.com/go-spatial/geom/planar/makevalid/walker"
+	"github.com/go-spatial/geom/planar/sweep"
 )
 
 type Makevalid struct {
@@ -112,12 +112,15 @@ func Destructure(ctx context.Context, cmp pkgcmp.Compare, clipbox *geom.Extent,
 	ipts := make(map[int][][2]float64)
 
 	// Lets find all the places we need to split the lines on.
-	eq := intersect.NewEventQueue(segments)
-	eq.FindIntersects(ctx, true, func(src, dest int, pt [2]float64) error {
-		ipts[src] = append(ipts[src], pt)
-		ipts[dest] = append(ipts[dest], pt)
-		return nil
-	})
+	isects, err := sweep.Intersections(ctx, segments)
+	if err != nil {
+		return nil, err
+	}
+	for _, is := range isects {
+		for _, i := range is.Segments {
+			ipts[i] = append(ipts[i], is.Point)
+		}
+	}
 
 	// Time to start splitting lines. if we have a clip box we can ignore the first 4 (0,1,2,3) lines.
 
@@ -226,6 +229,17 @@ func (mv *Makevalid) Makevalid(ctx context.Context, geo geom.Geometry, clipbox *
 			log.Printf("Returning on MultiPolygon: %T", vmp)
 		}
 		return vmp, true, nil
+	case geom.Collectioner:
+		geos := g.Geometries()
+		col := make(geom.Collection, len(geos))
+		for i := range geos {
+			cg, clipped, err := mv.Makevalid(ctx, geos[i], clipbox)
+			if err != nil {
+				return nil, false, err
+			}
+			col[i], didClip = cg, didClip || clipped
+		}
+		return col, didClip, nil
 	}
 	if debug {
 		log.Printf("Got an unknown geometry %T", geo)
@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -183,3 +184,21 @@ func checkMakeValid(tb testing.TB) {
 		}
 	}
 }
+
+func TestMakeValidCollection(t *testing.T) {
+	col := geom.Collection{
+		geom.Point{1, 2},
+		geom.Collection{geom.LineString{{0, 0}, {1, 1}}},
+	}
+	mv := &Makevalid{}
+	got, didClip, err := mv.Makevalid(context.Background(), col, nil)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if didClip {
+		t.Errorf("did clip, expected false got true")
+	}
+	if !reflect.DeepEqual(got, col) {
+		t.Errorf("makevalid, expected %v got %v", col, got)
+	}
+}
@@ -0,0 +1,158 @@
+package planar
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/go-spatial/geom"
+)
+
+// boundingTolerance is how far, relative to the radius, a point may be
+// outside a circle and still be in it, for the rounding of the center
+const boundingTolerance = 1e-12
+
+// MinimumBoundingCircle returns the smallest circle containing all the
+// points of the geometry, found with Welzl's algorithm. Every kind of
+// geometry is supported, as for ConvexHull. The circle is the zero Circle
+// if the geometry has no points, and has no radius if it has one.
+func MinimumBoundingCircle(g geom.Geometry) (geom.Circle, error) {
+	hull, err := ConvexHull(g)
+	if err != nil || hull == nil {
+		return geom.Circle{}, err
+	}
+	// only the points of the hull can be on the circle
+	var pts [][2]float64
+	switch h := hull.(type) {
+	case geom.Point:
+		return geom.Circle{Center: h}, nil
+	case geom.LineString:
+		pts = h
+	case geom.Polygon:
+		pts = append(pts, h[0]...)
+	}
+
+	// the points are shuffled so the expected time is linear, in the same
+	// way each time so the results are too
+	rnd := rand.New(rand.NewSource(1))
+	rnd.Shuffle(len(pts), func(i, j int) { pts[i], pts[j] = pts[j], pts[i] })
+
+	in := func(c geom.Circle, pt [2]float64) bool {
+		return math.Hypot(pt[0]-c.Center[0], pt[1]-c.Center[1]) <= c.Radius*(1+boundingTolerance)
+	}
+	c := geom.Circle{Center: pts[0]}
+	for i := 1; i < len(pts); i++ {
+		if in(c, pts[i]) {
+			continue
+		}
+		c = diameterCircle(pts[i], pts[0])
+		for j := 1; j < i; j++ {
+			if in(c, pts[j]) {
+				continue
+			}
+			c = diameterCircle(pts[i], pts[j])
+			for k := 0; k < j; k++ {
+				if !in(c, pts[k]) {
+					c = circumcircle(pts[i], pts[j], pts[k])
+				}
+			}
+		}
+	}
+	return c, nil
+}
+
+// diameterCircle returns the circle with a and b at either end of a
+// diameter
+func diameterCircle(a, b [2]float64) geom.Circle {
+	return geom.Circle{
+		Center: [2]float64{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2},
+		Radius: math.Hypot(b[0]-a[0], b[1]-a[1]) / 2,
+	}
+}
+
+// circumcircle returns the circle through the three points, or if they are
+// on a line, the circle through the two furthest apart
+func circumcircle(a, b, c [2]float64) geom.Circle {
+	bx, by := b[0]-a[0], b[1]-a[1]
+	cx, cy := c[0]-a[0], c[1]-a[1]
+	d := 2 * (bx*cy - by*cx)
+	if d == 0 {
+		circ := diameterCircle(a, b)
+		for _, o := range []geom.Circle{diameterCircle(a, c), diameterCircle(b, c)} {
+			if o.Radius > circ.Radius {
+				circ = o
+			}
+		}
+		return circ
+	}
+	b2, c2 := bx*bx+by*by, cx*cx+cy*cy
+	ux, uy := (cy*b2-by*c2)/d, (bx*c2-cx*b2)/d
+	return geom.Circle{
+		Center: [2]float64{a[0] + ux, a[1] + uy},
+		Radius: math.Hypot(ux, uy),
+	}
+}
+
+// MinimumRotatedRectangle returns the rectangle of the least area, at any
+// angle, containing all the points of the geometry, found by rotating
+// calipers around its convex hull. Every kind of geometry is supported, as
+// for ConvexHull. The rectangle is a Polygon whose ring is counter
+// clockwise, with y going up, and not closed, with one of its sides along
+// a side of the hull. If the hull has no area, it is returned as it is: a
+// LineString, Point or nil.
+func MinimumRotatedRectangle(g geom.Geometry) (geom.Geometry, error) {
+	hull, err := ConvexHull(g)
+	if err != nil {
+		return nil, err
+	}
+	ply, ok := hull.(geom.Polygon)
+	if !ok {
+		return hull, nil
+	}
+	ring, n := ply[0], len(ply[0])
+
+	// the calipers: the points furthest along the side, back along it and
+	// to its left. As the side goes round the hull they only go forwards.
+	var (
+		best              geom.Polygon
+		bestArea          = math.Inf(1)
+		front, back, left int
+	)
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%n]
+		l := math.Hypot(b[0]-a[0], b[1]-a[1])
+		// the directions along the side and to its left
+		u := [2]float64{(b[0] - a[0]) / l, (b[1] - a[1]) / l}
+		v := [2]float64{-u[1], u[0]}
+		along := func(j int) float64 { return vdot(vsub(ring[j%n], a), u) }
+		across := func(j int) float64 { return vdot(vsub(ring[j%n], a), v) }
+
+		if front < i+1 {
+			front = i + 1
+		}
+		for along(front+1) >= along(front) && front < i+n {
+			front++
+		}
+		if left < front {
+			left = front
+		}
+		for across(left+1) >= across(left) && left < i+n {
+			left++
+		}
+		if back < left {
+			back = left
+		}
+		for along(back+1) <= along(back) && back < i+n {
+			back++
+		}
+
+		minU, maxU, maxV := along(back), along(front), across(left)
+		if area := (maxU - minU) * maxV; area < bestArea {
+			at := func(pu, pv float64) [2]float64 {
+				return [2]float64{a[0] + pu*u[0] + pv*v[0], a[1] + pu*u[1] + pv*v[1]}
+			}
+			bestArea = area
+			best = geom.Polygon{{at(minU, 0), at(maxU, 0), at(maxU, maxV), at(minU, maxV)}}
+		}
+	}
+	return best, nil
+}
@@ -0,0 +1,142 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/winding"
+)
+
+func TestMinimumBoundingCircle(t *testing.T) {
+	type tcase struct {
+		g        geom.Geometry
+		expected geom.Circle
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := MinimumBoundingCircle(tc.g)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if math.Abs(got.Center[0]-tc.expected.Center[0]) > 1e-9 ||
+				math.Abs(got.Center[1]-tc.expected.Center[1]) > 1e-9 ||
+				math.Abs(got.Radius-tc.expected.Radius) > 1e-9 {
+				t.Errorf("circle, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {g: geom.MultiPoint{}},
+		"point": {
+			g:        geom.Point{1, 2},
+			expected: geom.Circle{Center: [2]float64{1, 2}},
+		},
+		"line": {
+			g:        geom.LineString{{0, 0}, {1, 1}, {4, 4}},
+			expected: geom.Circle{Center: [2]float64{2, 2}, Radius: math.Sqrt(8)},
+		},
+		"square": {
+			g:        geom.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}}},
+			expected: geom.Circle{Center: [2]float64{1, 1}, Radius: math.Sqrt2},
+		},
+		"obtuse triangle": {
+			// the long side is a diameter
+			g:        geom.MultiPoint{{0, 0}, {10, 0}, {5, 1}},
+			expected: geom.Circle{Center: [2]float64{5, 0}, Radius: 5},
+		},
+		"circumcircle": {
+			g:        geom.MultiPoint{{0, 0}, {4, 0}, {2, 4}, {2, 1}},
+			expected: geom.Circle{Center: [2]float64{2, 1.5}, Radius: 2.5},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestMinimumRotatedRectangle(t *testing.T) {
+	type tcase struct {
+		g    geom.Geometry
+		area float64
+		// side is the length of a side of the rectangle
+		side float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := MinimumRotatedRectangle(tc.g)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			ply, ok := got.(geom.Polygon)
+			if !ok || len(ply) != 1 || len(ply[0]) != 4 {
+				t.Fatalf("rectangle, expected a polygon with 4 points got %v", got)
+			}
+			if a := winding.SignedArea(ply[0]...); math.Abs(a-tc.area) > 1e-9 {
+				t.Errorf("area, expected %v got %v", tc.area, a)
+			}
+			found := false
+			for i := range ply[0] {
+				a, b := ply[0][i], ply[0][(i+1)%4]
+				if math.Abs(math.Hypot(b[0]-a[0], b[1]-a[1])-tc.side) < 1e-9 {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("side, expected one of length %v got %v", tc.side, ply)
+			}
+			// all the points are in the rectangle
+			err = geom.Walk(tc.g, func(pt [2]float64) error {
+				for i := range ply[0] {
+					a, b := ply[0][i], ply[0][(i+1)%4]
+					if vcross(vsub(b, a), vsub(pt, a)) < -1e-9 {
+						t.Errorf("point %v, expected in the rectangle %v", pt, ply)
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("walk error, expected nil got %v", err)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"square": {
+			g:    geom.MultiPoint{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {1, 1}},
+			area: 4,
+			side: 2,
+		},
+		"rotated": {
+			g:    geom.Polygon{{{0, 0}, {3, 3}, {2, 4}, {-1, 1}}},
+			area: 3 * math.Sqrt2 * math.Sqrt2,
+			side: 3 * math.Sqrt2,
+		},
+		"triangle": {
+			g:    geom.Polygon{{{0, 0}, {4, 0}, {0, 3}}},
+			area: 12,
+			side: 4,
+		},
+		"parallelogram": {
+			g:    geom.Polygon{{{0, 0}, {10, 0}, {11, 1}, {1, 1}}},
+			area: 11,
+			side: 11,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	got, err := MinimumRotatedRectangle(geom.LineString{{0, 0}, {2, 2}})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if _, ok := got.(geom.LineString); !ok {
+		t.Errorf("line, expected a line string got %v", got)
+	}
+}
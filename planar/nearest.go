@@ -0,0 +1,163 @@
+package planar
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/index/rtree"
+	"github.com/go-spatial/geom/planar/predicates"
+)
+
+// Distance returns the shortest distance between the geometries: zero if
+// they touch or overlap, including if one is inside a polygon of the
+// other.
+//
+// Points, lines, polygons, their Multi forms and collections of them are
+// supported; z and m values are ignored. ErrEmptyGeometry is returned if
+// either has no points.
+func Distance(a, b geom.Geometry) (float64, error) {
+	pa, pb, err := NearestPoints(a, b)
+	if err != nil {
+		return 0, err
+	}
+	return math.Hypot(pb[0]-pa[0], pb[1]-pa[1]), nil
+}
+
+// NearestPoints returns the points of each geometry that are nearest each
+// other; they are the same point if the geometries touch or overlap. If
+// there is more than one such pair, any of them is returned. The edges of
+// the larger geometry are put in an R-tree, so only the edges near each
+// edge of the other are looked at.
+//
+// Points, lines, polygons, their Multi forms and collections of them are
+// supported; z and m values are ignored. ErrEmptyGeometry is returned if
+// either has no points.
+func NearestPoints(a, b geom.Geometry) (pa, pb geom.Point, err error) {
+	var parts [2]geomParts
+	for i, g := range []geom.Geometry{a, b} {
+		if err := parts[i].add(g); err != nil {
+			return pa, pb, err
+		}
+		if len(parts[i].vertices()) == 0 {
+			return pa, pb, ErrEmptyGeometry
+		}
+	}
+
+	// a part of one inside a polygon of the other, without crossing its
+	// edges, has all its points inside
+	for i := range parts {
+		firsts := append([][2]float64(nil), parts[i].points...)
+		for _, ln := range parts[i].lines {
+			firsts = append(firsts, ln[0])
+		}
+		for _, ply := range parts[i].polys {
+			firsts = append(firsts, ply[0][0])
+		}
+		for _, pt := range firsts {
+			for _, ply := range parts[1-i].polys {
+				if PolygonContains(ply, pt) {
+					return geom.Point(pt), geom.Point(pt), nil
+				}
+			}
+		}
+	}
+
+	segs := [2][]geom.Line{nearestSegments(parts[0]), nearestSegments(parts[1])}
+	swapped := len(segs[0]) > len(segs[1])
+	if swapped {
+		segs[0], segs[1] = segs[1], segs[0]
+	}
+
+	items := make([]rtree.Item, len(segs[1]))
+	for i, s := range segs[1] {
+		items[i] = rtree.Item{Extent: *geom.NewExtent(s[0], s[1]), Data: i}
+	}
+	tree := rtree.New(0)
+	tree.Load(items)
+
+	// any pair of points is as far apart as the nearest
+	best := [2][2]float64{segs[0][0][0], segs[1][0][0]}
+	bestD := math.Hypot(best[1][0]-best[0][0], best[1][1]-best[0][1])
+	for _, s := range segs[0] {
+		if bestD == 0 {
+			break
+		}
+		ext := geom.NewExtent(s[0], s[1]).ExpandBy(bestD)
+		tree.Search(*ext, func(item rtree.Item) bool {
+			p, q := segmentNearest(s, segs[1][item.Data.(int)])
+			if d := math.Hypot(q[0]-p[0], q[1]-p[1]); d < bestD {
+				best, bestD = [2][2]float64{p, q}, d
+			}
+			return bestD > 0
+		})
+	}
+	if swapped {
+		best[0], best[1] = best[1], best[0]
+	}
+	return geom.Point(best[0]), geom.Point(best[1]), nil
+}
+
+// nearestSegments returns the segments of the lines and rings of the
+// parts, and the points as segments with no length
+func nearestSegments(p geomParts) []geom.Line {
+	var segs []geom.Line
+	for _, pt := range p.points {
+		segs = append(segs, geom.Line{pt, pt})
+	}
+	for _, ln := range p.lines {
+		for i := 1; i < len(ln); i++ {
+			segs = append(segs, geom.Line{ln[i-1], ln[i]})
+		}
+	}
+	for _, ply := range p.polys {
+		for _, ring := range ply {
+			for i := range ring {
+				segs = append(segs, geom.Line{ring[i], ring[(i+1)%len(ring)]})
+			}
+		}
+	}
+	return segs
+}
+
+// segmentNearest returns the points of each segment nearest the other
+func segmentNearest(s1, s2 geom.Line) (p, q [2]float64) {
+	o1, o2 := predicates.Orient2D(s1[0], s1[1], s2[0]), predicates.Orient2D(s1[0], s1[1], s2[1])
+	o3, o4 := predicates.Orient2D(s2[0], s2[1], s1[0]), predicates.Orient2D(s2[0], s2[1], s1[1])
+	if o1*o2 < 0 && o3*o4 < 0 {
+		// they cross
+		r, s := vsub(s1[1], s1[0]), vsub(s2[1], s2[0])
+		t := vcross(vsub(s2[0], s1[0]), s) / vcross(r, s)
+		pt := [2]float64{s1[0][0] + t*r[0], s1[0][1] + t*r[1]}
+		return pt, pt
+	}
+
+	// otherwise the nearest points include an end of one of them
+	bestD := math.Inf(1)
+	try := func(pt, a, b [2]float64, ofFirst bool) {
+		near := nearestOnSegment(a, b, pt)
+		if d := math.Hypot(near[0]-pt[0], near[1]-pt[1]); d < bestD {
+			bestD = d
+			if ofFirst {
+				p, q = pt, near
+			} else {
+				p, q = near, pt
+			}
+		}
+	}
+	try(s1[0], s2[0], s2[1], true)
+	try(s1[1], s2[0], s2[1], true)
+	try(s2[0], s1[0], s1[1], false)
+	try(s2[1], s1[0], s1[1], false)
+	return p, q
+}
+
+// nearestOnSegment returns the point of the segment a, b nearest pt
+func nearestOnSegment(a, b, pt [2]float64) [2]float64 {
+	ab := vsub(b, a)
+	l := vdot(ab, ab)
+	if l == 0 {
+		return a
+	}
+	t := math.Max(0, math.Min(1, vdot(vsub(pt, a), ab)/l))
+	return [2]float64{a[0] + t*ab[0], a[1] + t*ab[1]}
+}
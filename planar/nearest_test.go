@@ -0,0 +1,113 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestNearestPoints(t *testing.T) {
+	type tcase struct {
+		a, b     geom.Geometry
+		pa, pb   geom.Point
+		distance float64
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			pa, pb, err := NearestPoints(tc.a, tc.b)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if pa != tc.pa || pb != tc.pb {
+				t.Errorf("points, expected %v %v got %v %v", tc.pa, tc.pb, pa, pb)
+			}
+			d, err := Distance(tc.a, tc.b)
+			if err != nil {
+				t.Fatalf("distance error, expected nil got %v", err)
+			}
+			if math.Abs(d-tc.distance) > 1e-12 {
+				t.Errorf("distance, expected %v got %v", tc.distance, d)
+			}
+		}
+	}
+
+	square := geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}
+	tests := map[string]tcase{
+		"points": {
+			a: geom.Point{0, 0}, b: geom.MultiPoint{{3, 4}, {6, 8}},
+			pa: geom.Point{0, 0}, pb: geom.Point{3, 4},
+			distance: 5,
+		},
+		"point and line": {
+			a: geom.Point{5, 5}, b: geom.LineString{{0, 0}, {10, 0}},
+			pa: geom.Point{5, 5}, pb: geom.Point{5, 0},
+			distance: 5,
+		},
+		"lines": {
+			a: geom.LineString{{0, 2}, {10, 2}}, b: geom.LineString{{4, 5}, {5, 4}, {6, 5}},
+			pa: geom.Point{5, 2}, pb: geom.Point{5, 4},
+			distance: 2,
+		},
+		"z and m": {
+			a: geom.PointZ{5, 5, 100}, b: &geom.LineStringM{{0, 0, 1}, {10, 0, 2}},
+			pa: geom.Point{5, 5}, pb: geom.Point{5, 0},
+			distance: 5,
+		},
+		"crossing lines": {
+			a: geom.LineString{{0, 0}, {4, 4}}, b: geom.LineString{{0, 4}, {4, 0}},
+			pa: geom.Point{2, 2}, pb: geom.Point{2, 2},
+		},
+		"point in polygon": {
+			a: square, b: geom.Point{2, 3},
+			pa: geom.Point{2, 3}, pb: geom.Point{2, 3},
+		},
+		"polygon in polygon": {
+			a: geom.Polygon{{{2, 2}, {3, 2}, {3, 3}}}, b: square,
+			pa: geom.Point{2, 2}, pb: geom.Point{2, 2},
+		},
+		"point in hole": {
+			a: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{2, 2}, {2, 8}, {8, 8}, {8, 2}},
+			},
+			b:  geom.Point{5, 3},
+			pa: geom.Point{5, 2}, pb: geom.Point{5, 3},
+			distance: 1,
+		},
+		"polygons": {
+			a: square, b: geom.Polygon{{{13, 14}, {20, 14}, {20, 20}}},
+			pa: geom.Point{10, 10}, pb: geom.Point{13, 14},
+			distance: 5,
+		},
+		"empty": {
+			a: square, b: geom.MultiPoint{},
+			err: ErrEmptyGeometry,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestNearestPointsMany(t *testing.T) {
+	// a circle of many segments and a point outside it, so the index is used
+	var ring [][2]float64
+	for i := 0; i < 1000; i++ {
+		a := 2 * math.Pi * float64(i) / 1000
+		ring = append(ring, [2]float64{10 * math.Cos(a), 10 * math.Sin(a)})
+	}
+	d, err := Distance(geom.Point{20, 0}, geom.Polygon{ring})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if d != 10 {
+		t.Errorf("distance, expected 10 got %v", d)
+	}
+}
@@ -0,0 +1,106 @@
+package planar
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// nodingTolerance is used, relative to the size of the geometry, to decide
+// when lines are parallel and ends of segments touch
+const nodingTolerance = 1e-12
+
+// gridSnapper rounds points to a grid so that the nodes found from
+// different segments are the same point. The grid is relative to the size
+// of the geometry, the grid noding.Node uses, unless a precision model is
+// given
+type gridSnapper struct {
+	pm geom.PrecisionModel
+	// tol is the distance within which a point is on a line, it is above
+	// the distance points are moved by snapping
+	tol float64
+}
+
+func newGridSnapper(segs []geom.Line, pm geom.PrecisionModel) gridSnapper {
+	ext := new(geom.Extent)
+	for _, s := range segs {
+		ext.AddPoints(s[0], s[1])
+	}
+	span := math.Max(ext.XSpan(), ext.YSpan())
+	if span == 0 || math.IsInf(span, 0) {
+		span = 1
+	}
+	// a power of two, so snapping does not move points already on the grid
+	grid := math.Pow(2, math.Floor(math.Log2(span*1e-10)))
+	tol := 4 * grid
+	if pm.IsFloating() {
+		pm = geom.PrecisionModel{Scale: 1 / grid}
+	} else {
+		// rounding moves points up to half the diagonal of a grid cell
+		tol += pm.GridSize()
+	}
+	return gridSnapper{pm: pm, tol: tol}
+}
+
+func (s gridSnapper) tolerance() float64 { return s.tol }
+
+func (s gridSnapper) snap(pt [2]float64) [2]float64 { return s.pm.SnapPoint(pt) }
+
+func vcross(a, b [2]float64) float64 { return a[0]*b[1] - a[1]*b[0] }
+
+func vsub(a, b [2]float64) [2]float64 { return [2]float64{a[0] - b[0], a[1] - b[1]} }
+
+func vdot(a, b [2]float64) float64 { return a[0]*b[0] + a[1]*b[1] }
+
+// segmentTouches returns the points where the segments cross or touch,
+// those in the first segment and those in the second; where they overlap
+// these are the ends of each segment that are in the other
+func segmentTouches(s1, s2 geom.Line) (pts1, pts2 [][2]float64) {
+	r, q := vsub(s1[1], s1[0]), vsub(s2[1], s2[0])
+	rr, qq := vdot(r, r), vdot(q, q)
+	if rr == 0 || qq == 0 {
+		return nil, nil
+	}
+	w := vsub(s2[0], s1[0])
+	denom := vcross(r, q)
+
+	if math.Abs(denom) > nodingTolerance*math.Sqrt(rr*qq) {
+		t := vcross(w, q) / denom
+		u := vcross(w, r) / denom
+		if t < -nodingTolerance || t > 1+nodingTolerance || u < -nodingTolerance || u > 1+nodingTolerance {
+			return nil, nil
+		}
+		// use the end point if the segments touch at one
+		var pt [2]float64
+		switch {
+		case t <= nodingTolerance:
+			pt = s1[0]
+		case t >= 1-nodingTolerance:
+			pt = s1[1]
+		case u <= nodingTolerance:
+			pt = s2[0]
+		case u >= 1-nodingTolerance:
+			pt = s2[1]
+		default:
+			pt = [2]float64{s1[0][0] + t*r[0], s1[0][1] + t*r[1]}
+		}
+		return [][2]float64{pt}, [][2]float64{pt}
+	}
+
+	// parallel, check they are on the same line
+	if math.Abs(vcross(w, r)) > nodingTolerance*rr {
+		return nil, nil
+	}
+	// the ends of each segment that are in the other
+	for _, pt := range s2 {
+		if t := vdot(vsub(pt, s1[0]), r) / rr; t > 0 && t < 1 {
+			pts1 = append(pts1, pt)
+		}
+	}
+	for _, pt := range s1 {
+		if u := vdot(vsub(pt, s2[0]), q) / qq; u > 0 && u < 1 {
+			pts2 = append(pts2, pt)
+		}
+	}
+	return pts1, pts2
+}
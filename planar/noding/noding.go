@@ -0,0 +1,262 @@
+// Package noding splits lines where they cross or touch, so that all the
+// points where they meet are vertices: a noded arrangement of the lines,
+// as needed for overlay and polygonization.
+//
+// The lines are snap rounded to a grid. Every vertex and crossing is
+// rounded to the center of its cell of the grid, a hot pixel, and every
+// segment that passes through a hot pixel is bent to go through its
+// center. Rounding this way does not make new crossings, so the result is
+// noded even though its points have moved.
+//
+// Reference: Hobby, Practical segment intersection with finite precision
+// output, 1999.
+package noding
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/index/quadtree"
+	"github.com/go-spatial/geom/planar/sweep"
+)
+
+// relativeGrid is the size of the grid, relative to the size of the lines,
+// used for floating precision models
+const relativeGrid = 1e-10
+
+// Node returns the lines split at every point where they cross or touch
+// each other or themselves, as lines that only meet at their ends. The
+// points are rounded to the grid of the precision model; for a floating
+// precision model a grid of a power of two about 1e-10 of the size of the
+// lines is used.
+//
+// Parts of lines that overlap, after rounding, are only returned once. The
+// lines are split at their ends and at points where three or more of them
+// meet; where only two meet, at a vertex that is not the end of a line,
+// they are joined. Parts that collapse to a point when rounded are
+// removed.
+func Node(ctx context.Context, lines []geom.LineString, pm geom.PrecisionModel) ([]geom.LineString, error) {
+	edges, ends, err := node(ctx, lines, pm)
+	if err != nil {
+		return nil, err
+	}
+	return chain(edges, ends), nil
+}
+
+// Segments is Node returning each segment of the noded lines on its own.
+// Each segment goes from its smaller point, by x then y, to its larger.
+func Segments(ctx context.Context, lines []geom.LineString, pm geom.PrecisionModel) ([]geom.Line, error) {
+	edges, _, err := node(ctx, lines, pm)
+	return edges, err
+}
+
+// node returns the unique edges of the snap rounded lines, and the rounded
+// ends of the lines
+func node(ctx context.Context, lines []geom.LineString, pm geom.PrecisionModel) ([]geom.Line, map[[2]float64]bool, error) {
+	var (
+		segs []geom.Line
+		ext  *geom.Extent
+	)
+	for _, ln := range lines {
+		for i := 1; i < len(ln); i++ {
+			if ln[i-1] != ln[i] {
+				segs = append(segs, geom.Line{ln[i-1], ln[i]})
+			}
+		}
+		if len(ln) == 0 {
+			continue
+		}
+		if ext == nil {
+			ext = geom.NewExtent(ln[0])
+		}
+		ext.AddPoints(ln...)
+	}
+	if len(segs) == 0 {
+		return nil, nil, nil
+	}
+	if pm.IsFloating() {
+		pm = relativePrecision(ext)
+	}
+
+	isects, err := sweep.Intersections(ctx, segs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the hot pixels, by their centers
+	hot := quadtree.New(*ext, 0)
+	isHot := make(map[[2]float64]bool)
+	addHot := func(pt [2]float64) {
+		c := pm.SnapPoint(pt)
+		if isHot[c] {
+			return
+		}
+		isHot[c] = true
+		hot.Insert(quadtree.PointItem(c, nil))
+	}
+	for _, s := range segs {
+		addHot(s[0])
+		addHot(s[1])
+	}
+	for _, is := range isects {
+		addHot(is.Point)
+	}
+
+	ends := make(map[[2]float64]bool, 2*len(lines))
+	for _, ln := range lines {
+		if len(ln) > 1 {
+			ends[pm.SnapPoint(ln[0])] = true
+			ends[pm.SnapPoint(ln[len(ln)-1])] = true
+		}
+	}
+
+	half := pm.GridSize() / 2
+	var edges []geom.Line
+	seen := make(map[geom.Line]bool)
+	for _, s := range segs {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		// the hot pixels the segment goes through, between its ends
+		start, end := pm.SnapPoint(s[0]), pm.SnapPoint(s[1])
+		var mids [][2]float64
+		sext := geom.NewExtent(s[0], s[1]).ExpandBy(half)
+		hot.Search(*sext, func(item quadtree.Item) bool {
+			c := item.Extent.Min()
+			if c != start && c != end && crossesPixel(s, c, half) {
+				mids = append(mids, c)
+			}
+			return true
+		})
+		d := vsub(s[1], s[0])
+		sort.Slice(mids, func(i, j int) bool {
+			return vdot(vsub(mids[i], s[0]), d) < vdot(vsub(mids[j], s[0]), d)
+		})
+		pts := append(append([][2]float64{start}, mids...), end)
+
+		for i := 1; i < len(pts); i++ {
+			a, b := pts[i-1], pts[i]
+			if a == b {
+				continue
+			}
+			if less(b, a) {
+				a, b = b, a
+			}
+			e := geom.Line{a, b}
+			if seen[e] {
+				continue
+			}
+			seen[e] = true
+			edges = append(edges, e)
+		}
+	}
+	return edges, ends, nil
+}
+
+// relativePrecision returns a precision model with a grid of a power of
+// two near relativeGrid of the size of the extent
+func relativePrecision(ext *geom.Extent) geom.PrecisionModel {
+	span := math.Max(ext.XSpan(), ext.YSpan())
+	if span == 0 || math.IsInf(span, 0) || math.IsNaN(span) {
+		span = 1
+	}
+	grid := math.Pow(2, math.Floor(math.Log2(span*relativeGrid)))
+	return geom.PrecisionModel{Scale: 1 / grid}
+}
+
+// crossesPixel reports whether the segment goes through the square of
+// half size half around c, by clipping the segment to it
+func crossesPixel(s geom.Line, c [2]float64, half float64) bool {
+	t0, t1 := 0.0, 1.0
+	d := vsub(s[1], s[0])
+	for i := 0; i < 2; i++ {
+		// the distances inside each side of the square along the axis
+		for _, pq := range [2][2]float64{
+			{-d[i], s[0][i] - (c[i] - half)},
+			{d[i], (c[i] + half) - s[0][i]},
+		} {
+			p, q := pq[0], pq[1]
+			if p == 0 {
+				if q < 0 {
+					return false
+				}
+				continue
+			}
+			t := q / p
+			if p < 0 {
+				t0 = math.Max(t0, t)
+			} else {
+				t1 = math.Min(t1, t)
+			}
+		}
+	}
+	return t0 <= t1
+}
+
+// chain joins the edges into lines through the points where only two edges
+// meet that are not ends
+func chain(edges []geom.Line, ends map[[2]float64]bool) []geom.LineString {
+	at := make(map[[2]float64][]int)
+	for i, e := range edges {
+		at[e[0]] = append(at[e[0]], i)
+		at[e[1]] = append(at[e[1]], i)
+	}
+	isNode := func(pt [2]float64) bool { return ends[pt] || len(at[pt]) != 2 }
+
+	used := make([]bool, len(edges))
+	walk := func(start [2]float64, i int) geom.LineString {
+		ln := geom.LineString{start}
+		pt := start
+		for {
+			used[i] = true
+			if edges[i][0] == pt {
+				pt = edges[i][1]
+			} else {
+				pt = edges[i][0]
+			}
+			ln = append(ln, pt)
+			if isNode(pt) || pt == start {
+				return ln
+			}
+			next := at[pt][0]
+			if next == i {
+				next = at[pt][1]
+			}
+			if used[next] {
+				return ln
+			}
+			i = next
+		}
+	}
+
+	var lines []geom.LineString
+	for _, e := range edges {
+		for _, pt := range e {
+			if !isNode(pt) {
+				continue
+			}
+			for _, i := range at[pt] {
+				if !used[i] {
+					lines = append(lines, walk(pt, i))
+				}
+			}
+		}
+	}
+	// rings with no nodes on them
+	for i, e := range edges {
+		if !used[i] {
+			lines = append(lines, walk(e[0], i))
+		}
+	}
+	return lines
+}
+
+func less(a, b [2]float64) bool {
+	return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+}
+
+func vsub(a, b [2]float64) [2]float64 { return [2]float64{a[0] - b[0], a[1] - b[1]} }
+
+func vdot(a, b [2]float64) float64 { return a[0]*b[0] + a[1]*b[1] }
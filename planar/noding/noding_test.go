@@ -0,0 +1,151 @@
+package noding
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+// normalize orders the lines, and the points of each line, so lines can be
+// compared regardless of their order and direction
+func normalize(lines []geom.LineString) []string {
+	strs := make([]string, len(lines))
+	for i, ln := range lines {
+		if less(ln[len(ln)-1], ln[0]) {
+			rev := make(geom.LineString, len(ln))
+			for j := range ln {
+				rev[len(ln)-1-j] = ln[j]
+			}
+			ln = rev
+		}
+		strs[i] = fmt.Sprint(ln)
+	}
+	sort.Strings(strs)
+	return strs
+}
+
+func TestNode(t *testing.T) {
+	type tcase struct {
+		lines    []geom.LineString
+		pm       geom.PrecisionModel
+		expected []geom.LineString
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Node(context.Background(), tc.lines, tc.pm)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if exp, g := normalize(tc.expected), normalize(got); !reflect.DeepEqual(exp, g) {
+				t.Errorf("lines, expected %v got %v", exp, g)
+			}
+		}
+	}
+
+	grid := geom.PrecisionModel{Scale: 1}
+	tests := map[string]tcase{
+		"cross": {
+			lines: []geom.LineString{{{0, 0}, {10, 10}}, {{0, 10}, {10, 0}}},
+			pm:    grid,
+			expected: []geom.LineString{
+				{{0, 0}, {5, 5}}, {{5, 5}, {10, 10}},
+				{{0, 10}, {5, 5}}, {{5, 5}, {10, 0}},
+			},
+		},
+		"overlap": {
+			lines: []geom.LineString{{{0, 0}, {10, 0}}, {{5, 0}, {15, 0}}},
+			pm:    grid,
+			expected: []geom.LineString{
+				{{0, 0}, {5, 0}}, {{5, 0}, {10, 0}}, {{10, 0}, {15, 0}},
+			},
+		},
+		"touch": {
+			lines: []geom.LineString{{{0, 0}, {10, 0}}, {{5, 0}, {5, 5}}},
+			pm:    grid,
+			expected: []geom.LineString{
+				{{0, 0}, {5, 0}}, {{5, 0}, {10, 0}}, {{5, 0}, {5, 5}},
+			},
+		},
+		"snapped to a near vertex": {
+			lines: []geom.LineString{{{0, 0}, {10, 0}}, {{5, 0.3}, {5, 5}}},
+			pm:    grid,
+			expected: []geom.LineString{
+				{{0, 0}, {5, 0}}, {{5, 0}, {10, 0}}, {{5, 0}, {5, 5}},
+			},
+		},
+		"joined at vertices": {
+			lines: []geom.LineString{{{0, 0}, {5, 1}, {10, 0}}, {{0, 5}, {10, 5}}},
+			pm:    grid,
+			expected: []geom.LineString{
+				{{0, 0}, {5, 1}, {10, 0}}, {{0, 5}, {10, 5}},
+			},
+		},
+		"ring": {
+			lines:    []geom.LineString{{{0, 0}, {10, 0}, {10, 10}, {0, 0}}},
+			pm:       grid,
+			expected: []geom.LineString{{{0, 0}, {10, 0}, {10, 10}, {0, 0}}},
+		},
+		"self crossing": {
+			lines: []geom.LineString{{{0, 0}, {10, 10}, {10, 0}, {0, 10}}},
+			pm:    grid,
+			expected: []geom.LineString{
+				{{0, 0}, {5, 5}}, {{5, 5}, {10, 10}, {10, 0}, {5, 5}}, {{5, 5}, {0, 10}},
+			},
+		},
+		"collapsed": {
+			lines:    []geom.LineString{{{0, 0}, {0.2, 0.1}}, {{0, 0}, {4, 0}}},
+			pm:       grid,
+			expected: []geom.LineString{{{0, 0}, {4, 0}}},
+		},
+		"floating": {
+			lines: []geom.LineString{{{0, 0}, {4, 4}}, {{0, 4}, {4, 0}}},
+			expected: []geom.LineString{
+				{{0, 0}, {2, 2}}, {{2, 2}, {4, 4}},
+				{{0, 4}, {2, 2}}, {{2, 2}, {4, 0}},
+			},
+		},
+		"empty": {},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestSegments(t *testing.T) {
+	got, err := Segments(context.Background(),
+		[]geom.LineString{{{0, 0}, {1, 3}}, {{0, 1}, {1, 0}}},
+		geom.PrecisionModel{},
+	)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if len(got) != 4 {
+		t.Fatalf("segments, expected 4 got %v", got)
+	}
+	for _, s := range got {
+		if !less(s[0], s[1]) {
+			t.Errorf("segment, expected smaller point first got %v", s)
+		}
+	}
+	// all the segments meet at the crossing
+	at := make(map[[2]float64]int)
+	for _, s := range got {
+		at[s[0]]++
+		at[s[1]]++
+	}
+	var crossings int
+	for _, n := range at {
+		if n == 4 {
+			crossings++
+		}
+	}
+	if crossings != 1 {
+		t.Errorf("crossings, expected 1 got %v in %v", crossings, got)
+	}
+}
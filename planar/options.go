@@ -0,0 +1,120 @@
+package planar
+
+import (
+	"context"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/winding"
+)
+
+// Options are the settings shared by the planar and triangulate functions
+// that take a context: the direction of the y axis and winding of the
+// results, and the tolerance and precision of their points. They are
+// carried by the context, see NewContext.
+//
+// The zero Options are for tile and screen coordinates, where y increases
+// going down, with outer rings and triangles wound clockwise. Those rings
+// are counter clockwise when y increases going up, so the zero Options
+// give the same rings as GeographicOptions.
+type Options struct {
+	// YUp is true when y increases going up, as for longitude and
+	// latitude, and false when it increases going down, as for tiles. It
+	// decides which way round is clockwise.
+	YUp bool
+	// Winding is the winding of the outer rings of polygons and of
+	// triangles, holes are wound the other way. Colinear is the same as
+	// Clockwise.
+	Winding winding.Winding
+	// Tolerance is the distance within which points are the same. If it is
+	// positive and Precision is floating, the points of the results are
+	// rounded to a grid of that size.
+	Tolerance float64
+	// Precision is the precision model the points of the results are
+	// rounded to.
+	Precision geom.PrecisionModel
+}
+
+// GeographicOptions are the options for coordinates where y increases
+// going up, with the outer rings counter clockwise, as in GeoJSON.
+var GeographicOptions = Options{YUp: true, Winding: winding.CounterClockwise}
+
+type optionsKey struct{}
+
+// NewContext returns a context carrying the options.
+func NewContext(ctx context.Context, o Options) context.Context {
+	return context.WithValue(ctx, optionsKey{}, o)
+}
+
+// FromContext returns the options carried by the context, or the zero
+// Options if there are none.
+func FromContext(ctx context.Context) Options {
+	o, _ := ctx.Value(optionsKey{}).(Options)
+	return o
+}
+
+// Order returns the winding order for the direction of the y axis.
+func (o Options) Order() winding.Order {
+	// the zero winding.Order is clockwise with y going down
+	return winding.Order{YPositiveDown: o.YUp}
+}
+
+// PrecisionModel returns the precision model of the results: Precision, or
+// a grid of Tolerance if Precision is floating.
+func (o Options) PrecisionModel() geom.PrecisionModel {
+	if o.Precision.IsFloating() && o.Tolerance > 0 {
+		return geom.NewPrecisionModelGridSize(o.Tolerance)
+	}
+	return o.Precision
+}
+
+// outer returns the winding of outer rings
+func (o Options) outer() winding.Winding {
+	if o.Winding == winding.CounterClockwise {
+		return winding.CounterClockwise
+	}
+	return winding.Clockwise
+}
+
+// orientRing reverses the ring in place if it is not wound w
+func (o Options) orientRing(ring [][2]float64, w winding.Winding) {
+	if o.Order().OfPoints(ring...) != w.Not() {
+		return
+	}
+	for i, j := 0, len(ring)-1; i < j; i, j = i+1, j-1 {
+		ring[i], ring[j] = ring[j], ring[i]
+	}
+}
+
+// OrientPolygon reverses the rings of the polygon, in place, that are not
+// wound as the options say. The first ring is the outer ring. It returns
+// the polygon.
+func (o Options) OrientPolygon(ply geom.Polygon) geom.Polygon {
+	for i := range ply {
+		w := o.outer()
+		if i > 0 {
+			w = w.Not()
+		}
+		o.orientRing(ply[i], w)
+	}
+	return ply
+}
+
+// OrientMultiPolygon reverses the rings of the polygons, in place, that
+// are not wound as the options say. It returns the multipolygon.
+func (o Options) OrientMultiPolygon(mply geom.MultiPolygon) geom.MultiPolygon {
+	for i := range mply {
+		o.OrientPolygon(mply[i])
+	}
+	return mply
+}
+
+// OrientTriangles reverses the triangles, in place, that are not wound as
+// the options say. It returns the triangles.
+func (o Options) OrientTriangles(tris []geom.Triangle) []geom.Triangle {
+	for i := range tris {
+		if o.Order().OfPoints(tris[i][:]...) == o.outer().Not() {
+			tris[i][1], tris[i][2] = tris[i][2], tris[i][1]
+		}
+	}
+	return tris
+}
@@ -0,0 +1,118 @@
+package planar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/winding"
+)
+
+func TestOptionsContext(t *testing.T) {
+	ctx := context.Background()
+	if o := FromContext(ctx); o != (Options{}) {
+		t.Errorf("no options, expected zero options got %v", o)
+	}
+	if o := FromContext(NewContext(ctx, GeographicOptions)); o != GeographicOptions {
+		t.Errorf("options, expected %v got %v", GeographicOptions, o)
+	}
+
+	if pm := (Options{Tolerance: 0.5}).PrecisionModel(); pm.GridSize() != 0.5 {
+		t.Errorf("tolerance grid size, expected 0.5 got %v", pm.GridSize())
+	}
+	if pm := (Options{Tolerance: 0.5, Precision: geom.PrecisionModel{Scale: 10}}).PrecisionModel(); pm.GridSize() != 0.1 {
+		t.Errorf("precision grid size, expected 0.1 got %v", pm.GridSize())
+	}
+	if pm := (Options{}).PrecisionModel(); !pm.IsFloating() {
+		t.Errorf("precision, expected floating got %v", pm)
+	}
+}
+
+func TestOptionsOrient(t *testing.T) {
+	type tcase struct {
+		opts Options
+		// outer is the sign of the area of the outer ring, with y going up
+		outer float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			check := func(name string, ply geom.Polygon) {
+				t.Helper()
+				if len(ply) != 2 {
+					t.Fatalf("%v rings, expected 2 got %v", name, len(ply))
+				}
+				if a := winding.SignedArea(ply[0]...); a*tc.outer <= 0 {
+					t.Errorf("%v outer ring, expected area with sign %v got %v", name, tc.outer, a)
+				}
+				if a := winding.SignedArea(ply[1]...); a*tc.outer >= 0 {
+					t.Errorf("%v hole, expected area with sign %v got %v", name, -tc.outer, a)
+				}
+			}
+
+			// both rings the wrong way round for each other
+			ply := tc.opts.OrientPolygon(geom.Polygon{
+				{{0, 0}, {0, 10}, {10, 10}, {10, 0}},
+				{{2, 2}, {8, 2}, {8, 8}, {2, 8}},
+			})
+			check("oriented", ply)
+
+			ctx := NewContext(context.Background(), tc.opts)
+			donut := geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{2, 2}, {2, 8}, {8, 8}, {8, 2}},
+			}
+			mply, err := MakeValid(ctx, donut)
+			if err != nil {
+				t.Fatalf("make valid error, expected nil got %v", err)
+			}
+			if len(mply) != 1 {
+				t.Fatalf("make valid polygons, expected 1 got %v", len(mply))
+			}
+			check("make valid", mply[0])
+
+			tris := tc.opts.OrientTriangles([]geom.Triangle{
+				{{0, 0}, {1, 0}, {0, 1}},
+				{{0, 0}, {0, 1}, {1, 0}},
+			})
+			for i, tri := range tris {
+				if a := winding.SignedArea(tri[:]...); a*tc.outer <= 0 {
+					t.Errorf("triangle %v, expected area with sign %v got %v", i, tc.outer, a)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"zero":                 {outer: 1},
+		"geographic":           {opts: GeographicOptions, outer: 1},
+		"y up clockwise":       {opts: Options{YUp: true, Winding: winding.Clockwise}, outer: -1},
+		"y down anticlockwise": {opts: Options{Winding: winding.CounterClockwise}, outer: -1},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestOverlayOptionsTolerance(t *testing.T) {
+	ctx := NewContext(context.Background(), Options{Tolerance: 1})
+	got, err := Union(ctx,
+		geom.Polygon{{{0.2, 0.1}, {4.9, 0}, {5.1, 4.8}, {0, 5.2}}},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if len(got) != 1 || len(got[0]) != 1 {
+		t.Fatalf("polygons, expected one ring got %v", got)
+	}
+	for _, pt := range got[0][0] {
+		if pt[0] != float64(int(pt[0])) || pt[1] != float64(int(pt[1])) {
+			t.Errorf("point, expected on the grid got %v", pt)
+		}
+	}
+	if a := winding.SignedArea(got[0][0]...); a != 25 {
+		t.Errorf("area, expected 25 got %v", a)
+	}
+}
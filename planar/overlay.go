@@ -0,0 +1,159 @@
+package planar
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-spatial/geom"
+)
+
+// ErrInvalidOverlayOp is returned when the overlay operation is not one of
+// the OverlayOp constants.
+var ErrInvalidOverlayOp = errors.New("planar: invalid overlay operation")
+
+// OverlayOp is a boolean operation on the areas of two geometries.
+type OverlayOp uint8
+
+const (
+	// OpUnion is the area covered by either geometry
+	OpUnion OverlayOp = iota
+	// OpIntersection is the area covered by both geometries
+	OpIntersection
+	// OpDifference is the area covered by the first geometry but not the
+	// second
+	OpDifference
+	// OpSymmetricDifference is the area covered by exactly one of the
+	// geometries
+	OpSymmetricDifference
+)
+
+// Overlay returns the area of the operation on the polygons of a and b.
+// Polygons, MultiPolygons and collections of them are supported; the
+// polygons of a geometry may overlap each other, the area of the geometry
+// is the union of them. A nil geometry has no area.
+//
+// The edges of both geometries are split where they cross, and the edges
+// with the result on only one side are linked into rings. The rings of
+// the result are not closed, and are wound and rounded as the Options of
+// the context say.
+func Overlay(ctx context.Context, op OverlayOp, a, b geom.Geometry) (geom.MultiPolygon, error) {
+	return OverlayWithPrecision(ctx, op, a, b, FromContext(ctx).PrecisionModel())
+}
+
+// OverlayWithPrecision is Overlay with the points of the result rounded
+// to the grid of the precision model, including the points where the
+// edges of a and b cross. Edges that become shorter than the grid size
+// are removed, so parts of the area narrower than it may collapse. A
+// floating precision model is the same as Overlay without a precision in
+// the Options of the context.
+func OverlayWithPrecision(ctx context.Context, op OverlayOp, a, b geom.Geometry, pm geom.PrecisionModel) (geom.MultiPolygon, error) {
+	var o overlayBuilder
+	var err error
+	if o.a, err = o.add(nil, a); err != nil {
+		return nil, err
+	}
+	if o.b, err = o.add(nil, b); err != nil {
+		return nil, err
+	}
+
+	var covers coverFunc
+	switch op {
+	case OpUnion:
+		covers = func(pt, dir [2]float64, tol float64) bool {
+			return coveredBy(o.a, pt, dir, tol) || coveredBy(o.b, pt, dir, tol)
+		}
+	case OpIntersection:
+		covers = func(pt, dir [2]float64, tol float64) bool {
+			return coveredBy(o.a, pt, dir, tol) && coveredBy(o.b, pt, dir, tol)
+		}
+	case OpDifference:
+		covers = func(pt, dir [2]float64, tol float64) bool {
+			return coveredBy(o.a, pt, dir, tol) && !coveredBy(o.b, pt, dir, tol)
+		}
+	case OpSymmetricDifference:
+		covers = func(pt, dir [2]float64, tol float64) bool {
+			return coveredBy(o.a, pt, dir, tol) != coveredBy(o.b, pt, dir, tol)
+		}
+	default:
+		return nil, ErrInvalidOverlayOp
+	}
+	return buildPolygons(ctx, o.segments, pm, covers)
+}
+
+// Union returns the area covered by either a or b.
+func Union(ctx context.Context, a, b geom.Geometry) (geom.MultiPolygon, error) {
+	return Overlay(ctx, OpUnion, a, b)
+}
+
+// Intersection returns the area covered by both a and b.
+func Intersection(ctx context.Context, a, b geom.Geometry) (geom.MultiPolygon, error) {
+	return Overlay(ctx, OpIntersection, a, b)
+}
+
+// Difference returns the area covered by a but not by b.
+func Difference(ctx context.Context, a, b geom.Geometry) (geom.MultiPolygon, error) {
+	return Overlay(ctx, OpDifference, a, b)
+}
+
+// SymmetricDifference returns the area covered by exactly one of a and b.
+func SymmetricDifference(ctx context.Context, a, b geom.Geometry) (geom.MultiPolygon, error) {
+	return Overlay(ctx, OpSymmetricDifference, a, b)
+}
+
+// overlayBuilder collects the polygons of the geometries of an overlay
+type overlayBuilder struct {
+	a, b []piece
+	// the edges of the polygons of both geometries
+	segments []geom.Line
+}
+
+// add appends the polygons of the geometry to plys
+func (o *overlayBuilder) add(plys []piece, g geom.Geometry) ([]piece, error) {
+	var err error
+	switch gg := g.(type) {
+	case nil:
+
+	case geom.Collectioner:
+		for _, g := range gg.Geometries() {
+			if plys, err = o.add(plys, g); err != nil {
+				return nil, err
+			}
+		}
+
+	case geom.MultiPolygoner:
+		for _, ply := range gg.Polygons() {
+			plys = o.addPolygon(plys, ply)
+		}
+
+	case geom.Polygoner:
+		plys = o.addPolygon(plys, gg.LinearRings())
+
+	default:
+		return nil, geom.ErrUnknownGeometry{Geom: g}
+	}
+	return plys, nil
+}
+
+func (o *overlayBuilder) addPolygon(plys []piece, rings [][][2]float64) []piece {
+	var ply polygonPiece
+	for _, ring := range rings {
+		ring = dedupPoints(ring, true)
+		if len(ring) < 3 {
+			continue
+		}
+		ply.rings = append(ply.rings, ring)
+		for i := range ring {
+			o.segments = append(o.segments, geom.Line{ring[i], ring[(i+1)%len(ring)]})
+		}
+	}
+	if len(ply.rings) == 0 {
+		return plys
+	}
+	// the extent of all the rings, as invalid polygons may have holes
+	// outside of the outer ring
+	ply.ext = geom.NewExtent(ply.rings[0]...)
+	for _, ring := range ply.rings[1:] {
+		ply.ext.AddPoints(ring...)
+	}
+	return append(plys, ply)
+}
@@ -0,0 +1,226 @@
+package planar
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestOverlay(t *testing.T) {
+	type tcase struct {
+		op   OverlayOp
+		a, b geom.Geometry
+		area float64
+		// polygons is the expected number of polygons
+		polygons int
+		// holes is the expected number of holes in all the polygons
+		holes int
+		err   error
+	}
+
+	square := func(x, y, size float64) geom.Polygon {
+		return geom.Polygon{{{x, y}, {x + size, y}, {x + size, y + size}, {x, y + size}}}
+	}
+	donut := geom.Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+		{{3, 3}, {3, 7}, {7, 7}, {7, 3}},
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Overlay(context.Background(), tc.op, tc.a, tc.b)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if len(got) != tc.polygons {
+				t.Errorf("polygons, expected %v got %v", tc.polygons, len(got))
+			}
+			var holes int
+			for _, ply := range got {
+				holes += len(ply) - 1
+			}
+			if holes != tc.holes {
+				t.Errorf("holes, expected %v got %v", tc.holes, holes)
+			}
+			if a := testArea(got); math.Abs(a-tc.area) > 1e-9 {
+				t.Errorf("area, expected %v got %v", tc.area, a)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"union overlapping": {
+			op:       OpUnion,
+			a:        square(0, 0, 10),
+			b:        square(5, 5, 10),
+			area:     175,
+			polygons: 1,
+		},
+		"intersection overlapping": {
+			op:       OpIntersection,
+			a:        square(0, 0, 10),
+			b:        square(5, 5, 10),
+			area:     25,
+			polygons: 1,
+		},
+		"difference overlapping": {
+			op:       OpDifference,
+			a:        square(0, 0, 10),
+			b:        square(5, 5, 10),
+			area:     75,
+			polygons: 1,
+		},
+		"symmetric difference overlapping": {
+			op:       OpSymmetricDifference,
+			a:        square(0, 0, 10),
+			b:        square(5, 5, 10),
+			area:     150,
+			polygons: 2,
+		},
+		"union disjoint": {
+			op:       OpUnion,
+			a:        square(0, 0, 10),
+			b:        square(20, 0, 10),
+			area:     200,
+			polygons: 2,
+		},
+		"intersection disjoint": {
+			op: OpIntersection,
+			a:  square(0, 0, 10),
+			b:  square(20, 0, 10),
+		},
+		"union shared edge": {
+			op:       OpUnion,
+			a:        square(0, 0, 10),
+			b:        square(10, 0, 10),
+			area:     200,
+			polygons: 1,
+		},
+		"difference makes a hole": {
+			op:       OpDifference,
+			a:        square(0, 0, 10),
+			b:        square(3, 3, 4),
+			area:     84,
+			polygons: 1,
+			holes:    1,
+		},
+		"union fills a hole": {
+			op:       OpUnion,
+			a:        donut,
+			b:        square(2, 2, 6),
+			area:     100,
+			polygons: 1,
+		},
+		"intersection with a hole": {
+			op:       OpIntersection,
+			a:        donut,
+			b:        square(2, 2, 6),
+			area:     20,
+			polygons: 1,
+			holes:    1,
+		},
+		"difference inside a hole": {
+			op:       OpDifference,
+			a:        square(4, 4, 2),
+			b:        donut,
+			area:     4,
+			polygons: 1,
+		},
+		"union multipolygon": {
+			op:       OpUnion,
+			a:        geom.MultiPolygon{square(0, 0, 10), square(5, 0, 10)},
+			b:        square(20, 0, 10),
+			area:     250,
+			polygons: 2,
+		},
+		"difference nil": {
+			op:       OpDifference,
+			a:        square(0, 0, 10),
+			area:     100,
+			polygons: 1,
+		},
+		"point": {
+			op:  OpUnion,
+			a:   square(0, 0, 10),
+			b:   geom.Point{5, 5},
+			err: geom.ErrUnknownGeometry{Geom: geom.Point{5, 5}},
+		},
+		"invalid op": {
+			op:  OverlayOp(10),
+			a:   square(0, 0, 10),
+			b:   square(5, 5, 10),
+			err: ErrInvalidOverlayOp,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestOverlayWithPrecision(t *testing.T) {
+	type tcase struct {
+		op       OverlayOp
+		a, b     geom.Geometry
+		pm       geom.PrecisionModel
+		area     float64
+		polygons int
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := OverlayWithPrecision(context.Background(), tc.op, tc.a, tc.b, tc.pm)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if len(got) != tc.polygons {
+				t.Errorf("polygons, expected %v got %v", tc.polygons, len(got))
+			}
+			if a := testArea(got); math.Abs(a-tc.area) > 1e-9 {
+				t.Errorf("area, expected %v got %v", tc.area, a)
+			}
+			for _, ply := range got {
+				for _, ring := range ply {
+					for _, pt := range ring {
+						if tc.pm.SnapPoint(pt) != pt {
+							t.Errorf("point %v, expected on the grid", pt)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"crossing off the grid": {
+			op:       OpIntersection,
+			a:        geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			b:        geom.Polygon{{{5, -1}, {11, 2}, {5, 11}}},
+			pm:       geom.NewPrecisionModelGridSize(0.01),
+			area:     33.6775,
+			polygons: 1,
+		},
+		"gap narrower than the grid": {
+			op:       OpUnion,
+			a:        geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			b:        geom.Polygon{{{10.001, 0}, {20, 0}, {20, 10}, {10.001, 10}}},
+			pm:       geom.NewPrecisionModelGridSize(0.01),
+			area:     200,
+			polygons: 1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	got, err := MakeValidWithPrecision(context.Background(), geom.Polygon{{{0, 0}, {10, 10}, {10, 0}, {0, 10}}}, geom.PrecisionModel{Scale: 1})
+	if err != nil {
+		t.Fatalf("make valid error, expected nil got %v", err)
+	}
+	if len(got) != 2 || testArea(got) != 50 {
+		t.Errorf("make valid, expected 2 polygons of area 50 got %v", got)
+	}
+}
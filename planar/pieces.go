@@ -0,0 +1,120 @@
+package planar
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/winding"
+)
+
+// piece is an area used to build the polygons of a buffer or overlay
+type piece interface {
+	extent() *geom.Extent
+	// covers reports whether the points just off pt, in the direction
+	// dir, are inside the piece. Points within tol of the boundary of the
+	// piece are on it.
+	covers(pt, dir [2]float64, tol float64) bool
+}
+
+func coveredBy(pieces []piece, pt, dir [2]float64, tol float64) bool {
+	for _, p := range pieces {
+		ext := p.extent()
+		if pt[0] < ext.MinX()-tol || pt[0] > ext.MaxX()+tol ||
+			pt[1] < ext.MinY()-tol || pt[1] > ext.MaxY()+tol {
+			continue
+		}
+		if p.covers(pt, dir, tol) {
+			return true
+		}
+	}
+	return false
+}
+
+// distanceLeft returns the distance of pt to the left of the line through
+// a and b, it is negative if pt is to the right
+func distanceLeft(a, b, pt [2]float64) float64 {
+	ab := vsub(b, a)
+	return vcross(ab, vsub(pt, a)) / math.Hypot(ab[0], ab[1])
+}
+
+// onSegment reports whether pt is within tol of the segment a, b
+func onSegment(a, b, pt [2]float64, tol float64) bool {
+	if math.Abs(distanceLeft(a, b, pt)) > tol {
+		return false
+	}
+	ab := vsub(b, a)
+	l := math.Hypot(ab[0], ab[1])
+	t := vdot(vsub(pt, a), ab) / l
+	return t >= -tol && t <= l+tol
+}
+
+// convexPiece is a convex ring in counter-clockwise order
+type convexPiece struct {
+	ring [][2]float64
+	ext  *geom.Extent
+}
+
+func newConvexPiece(ring ...[2]float64) convexPiece {
+	// make sure the ring is counter-clockwise
+	if winding.SignedArea(ring...) < 0 {
+		for i, j := 0, len(ring)-1; i < j; i, j = i+1, j-1 {
+			ring[i], ring[j] = ring[j], ring[i]
+		}
+	}
+	return convexPiece{ring: ring, ext: geom.NewExtent(ring...)}
+}
+
+func (c convexPiece) extent() *geom.Extent { return c.ext }
+
+func (c convexPiece) covers(pt, dir [2]float64, tol float64) bool {
+	for i := range c.ring {
+		a, b := c.ring[i], c.ring[(i+1)%len(c.ring)]
+		if a == b {
+			continue
+		}
+		dist := distanceLeft(a, b, pt)
+		if dist < -tol {
+			return false
+		}
+		// on the edge, the inside is to the left
+		if dist <= tol && vcross(vsub(b, a), dir) <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// polygonPiece is a polygon of the geometry, the point is inside if it is
+// inside an odd number of rings, which does not depend on the direction of
+// the rings or on them being simple
+type polygonPiece struct {
+	rings [][][2]float64
+	ext   *geom.Extent
+}
+
+func (p polygonPiece) extent() *geom.Extent { return p.ext }
+
+func (p polygonPiece) covers(pt, dir [2]float64, tol float64) bool {
+	// count the edges the ray from the point in the direction crosses,
+	// skipping the edges the point is on. Vertices on the ray are counted
+	// as above it so an edge through one is only counted once.
+	in := false
+	for _, ring := range p.rings {
+		for i := range ring {
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			if a == b || onSegment(a, b, pt, tol) {
+				continue
+			}
+			va, vb := vcross(dir, vsub(a, pt)), vcross(dir, vsub(b, pt))
+			if (va >= 0) == (vb >= 0) {
+				continue
+			}
+			// the distance along the ray of the crossing
+			ua, ub := vdot(dir, vsub(a, pt)), vdot(dir, vsub(b, pt))
+			if ua+(ub-ua)*va/(va-vb) > 0 {
+				in = !in
+			}
+		}
+	}
+	return in
+}
@@ -0,0 +1,285 @@
+package planar
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+	"github.com/go-spatial/geom/winding"
+)
+
+// Polygonization is the result of Polygonize: the polygons formed by the
+// lines, and the lines that are not part of any of them.
+type Polygonization struct {
+	Polygons geom.MultiPolygon
+	// Dangles are the lines with an end that is not joined to any other
+	// line, and the lines joined to them that are left dangling once they
+	// are removed.
+	Dangles []geom.Line
+	// CutEdges are the lines joined at both ends that do not bound any
+	// area, such as a line between two rings, as they have the same face
+	// on both sides.
+	CutEdges []geom.Line
+}
+
+// Polygonize returns the polygons whose boundaries are made up of the
+// lines of the geometry. Lines, slices of lines, LineStrings,
+// MultiLineStrings and collections of them are supported.
+//
+// The lines must be noded, only meeting at their ends; see noding.Node.
+// Every ring of the lines that has no lines inside it is the outer ring of
+// a polygon, and the outer boundary of a set of joined lines is a hole of
+// the smallest polygon it is inside. So two rings one inside the other
+// give two polygons: the outer one with a hole, and the inner one filling
+// the hole.
+//
+// The rings of the result are not closed; the outer rings are counter
+// clockwise and the holes clockwise, with y going up, unless the Options of
+// the context say otherwise.
+func Polygonize(ctx context.Context, g geom.Geometry) (Polygonization, error) {
+	var res Polygonization
+	segs, err := polygonizeSegments(nil, g)
+	if err != nil {
+		return res, err
+	}
+	pg := newPolygonizer(segs)
+	res.Dangles = pg.removeDangles()
+
+	var rngs [][]int
+	for {
+		if rngs, err = pg.traceRings(ctx); err != nil {
+			return res, err
+		}
+		cut := pg.removeCutEdges()
+		if len(cut) == 0 {
+			break
+		}
+		res.CutEdges = append(res.CutEdges, cut...)
+	}
+
+	res.Polygons = pg.assemble(rngs)
+	if len(res.Polygons) == 0 {
+		res.Polygons = nil
+	}
+	FromContext(ctx).OrientMultiPolygon(res.Polygons)
+	return res, nil
+}
+
+// polygonizeSegments appends the segments of the lines of the geometry
+func polygonizeSegments(segs []geom.Line, g geom.Geometry) ([]geom.Line, error) {
+	addLine := func(ln [][2]float64) {
+		for i := 1; i < len(ln); i++ {
+			segs = append(segs, geom.Line{ln[i-1], ln[i]})
+		}
+	}
+	switch g := g.(type) {
+	case geom.Line:
+		segs = append(segs, g)
+	case *geom.Line:
+		if g != nil {
+			segs = append(segs, *g)
+		}
+	case []geom.Line:
+		segs = append(segs, g...)
+	case geom.LineStringer:
+		addLine(g.Vertices())
+	case geom.MultiLineStringer:
+		for _, ln := range g.LineStrings() {
+			addLine(ln)
+		}
+	case geom.Collectioner:
+		var err error
+		for _, cg := range g.Geometries() {
+			if segs, err = polygonizeSegments(segs, cg); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, geom.ErrUnknownGeometry{Geom: g}
+	}
+	return segs, nil
+}
+
+// polygonizer is the graph of the lines. Each line i has two half edges,
+// 2i going from its first point to its second, and 2i+1 going back.
+type polygonizer struct {
+	edges   []geom.Line
+	removed []bool
+	// ring is the ring each half edge was last traced into
+	ring []int
+}
+
+func newPolygonizer(segs []geom.Line) *polygonizer {
+	var edges []geom.Line
+	for _, s := range segs {
+		if s[0] != s[1] {
+			edges = append(edges, s)
+		}
+	}
+	edges = NormalizeUniqueLines(edges)
+	return &polygonizer{
+		edges:   edges,
+		removed: make([]bool, len(edges)),
+		ring:    make([]int, 2*len(edges)),
+	}
+}
+
+func (pg *polygonizer) from(h int) [2]float64 { return pg.edges[h/2][h%2] }
+func (pg *polygonizer) to(h int) [2]float64   { return pg.edges[h/2][1-h%2] }
+
+// removeDangles removes the lines with an end that no other line is joined
+// to, until there are none, and returns them
+func (pg *polygonizer) removeDangles() []geom.Line {
+	at := make(map[[2]float64][]int)
+	for i, e := range pg.edges {
+		at[e[0]] = append(at[e[0]], i)
+		at[e[1]] = append(at[e[1]], i)
+	}
+	degree := make(map[[2]float64]int, len(at))
+	var ends [][2]float64
+	for pt, es := range at {
+		degree[pt] = len(es)
+		if len(es) == 1 {
+			ends = append(ends, pt)
+		}
+	}
+	// the order of the map is random, the dangles should not be
+	sort.Slice(ends, func(i, j int) bool { return cmp.PointLess(ends[i], ends[j]) })
+
+	var dangles []geom.Line
+	for len(ends) > 0 {
+		pt := ends[len(ends)-1]
+		ends = ends[:len(ends)-1]
+		for _, i := range at[pt] {
+			if pg.removed[i] {
+				continue
+			}
+			pg.removed[i] = true
+			dangles = append(dangles, pg.edges[i])
+			degree[pg.edges[i][0]]--
+			degree[pg.edges[i][1]]--
+			other := pg.edges[i][0]
+			if other == pt {
+				other = pg.edges[i][1]
+			}
+			if degree[other] == 1 {
+				ends = append(ends, other)
+			}
+		}
+	}
+	return dangles
+}
+
+// traceRings traces the faces of the lines that have not been removed,
+// returning the half edges of each ring. Each half edge is followed by the
+// one leaving its end that is the first clockwise from going back, so the
+// face is on the left of the ring.
+func (pg *polygonizer) traceRings(ctx context.Context) ([][]int, error) {
+	out := make(map[[2]float64][]int)
+	for h := range pg.ring {
+		pg.ring[h] = -1
+		if !pg.removed[h/2] {
+			out[pg.from(h)] = append(out[pg.from(h)], h)
+		}
+	}
+	angle := func(h int) float64 {
+		a, b := pg.from(h), pg.to(h)
+		return math.Atan2(b[1]-a[1], b[0]-a[0])
+	}
+	// the position of each half edge in the counter clockwise order of the
+	// half edges leaving its start
+	pos := make([]int, len(pg.ring))
+	for _, hs := range out {
+		sort.Slice(hs, func(i, j int) bool { return angle(hs[i]) < angle(hs[j]) })
+		for i, h := range hs {
+			pos[h] = i
+		}
+	}
+	next := func(h int) int {
+		hs := out[pg.to(h)]
+		return hs[(pos[h^1]+len(hs)-1)%len(hs)]
+	}
+
+	var rngs [][]int
+	for start := range pg.ring {
+		if pg.removed[start/2] || pg.ring[start] != -1 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var rng []int
+		for h := start; pg.ring[h] == -1; h = next(h) {
+			pg.ring[h] = len(rngs)
+			rng = append(rng, h)
+		}
+		rngs = append(rngs, rng)
+	}
+	return rngs, nil
+}
+
+// removeCutEdges removes the lines that have the same ring on both sides,
+// and returns them
+func (pg *polygonizer) removeCutEdges() []geom.Line {
+	var cut []geom.Line
+	for i, e := range pg.edges {
+		if !pg.removed[i] && pg.ring[2*i] == pg.ring[2*i+1] {
+			pg.removed[i] = true
+			cut = append(cut, e)
+		}
+	}
+	return cut
+}
+
+// assemble sorts the rings into polygons. Counter-clockwise rings are the
+// outer rings, clockwise rings are the outer boundaries of sets of joined
+// lines, and are added as holes to the smallest outer ring that contains
+// them.
+func (pg *polygonizer) assemble(rngs [][]int) geom.MultiPolygon {
+	type shell struct {
+		ring [][2]float64
+		ext  *geom.Extent
+		area float64
+	}
+	var (
+		shells []shell
+		holes  [][][2]float64
+	)
+	for _, rng := range rngs {
+		ring := make([][2]float64, len(rng))
+		for i, h := range rng {
+			ring[i] = pg.from(h)
+		}
+		if a := winding.SignedArea(ring...); a > 0 {
+			shells = append(shells, shell{ring: ring, ext: geom.NewExtent(ring...), area: a})
+		} else if a < 0 {
+			holes = append(holes, ring)
+		}
+	}
+
+	mply := make(geom.MultiPolygon, len(shells))
+	for i := range shells {
+		mply[i] = [][][2]float64{shells[i].ring}
+	}
+	for _, h := range holes {
+		// the lines of different sets do not meet, so any point of the
+		// hole is either inside a shell of another set, or on the shells
+		// of its own set
+		pt := h[0]
+		idx := -1
+		for i, s := range shells {
+			if !s.ext.ContainsPoint(pt) || (idx != -1 && s.area >= shells[idx].area) {
+				continue
+			}
+			if inside, _ := inRing(s.ring, pt); inside {
+				idx = i
+			}
+		}
+		if idx != -1 {
+			mply[idx] = append(mply[idx], h)
+		}
+	}
+	return mply
+}
@@ -0,0 +1,130 @@
+package planar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/winding"
+)
+
+func TestPolygonize(t *testing.T) {
+	type tcase struct {
+		g geom.Geometry
+		// areas are the areas of the polygons, and holes their number of
+		// holes
+		areas    []float64
+		holes    []int
+		dangles  int
+		cutEdges int
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Polygonize(context.Background(), tc.g)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if len(got.Polygons) != len(tc.areas) {
+				t.Fatalf("polygons, expected %v got %v", len(tc.areas), got.Polygons)
+			}
+			for i, ply := range got.Polygons {
+				a := winding.SignedArea(ply[0]...)
+				for _, h := range ply[1:] {
+					if ha := winding.SignedArea(h...); ha >= 0 {
+						t.Errorf("polygon %v hole area, expected negative got %v", i, ha)
+					}
+					a += winding.SignedArea(h...)
+				}
+				if a != tc.areas[i] {
+					t.Errorf("polygon %v area, expected %v got %v", i, tc.areas[i], a)
+				}
+				if len(ply)-1 != tc.holes[i] {
+					t.Errorf("polygon %v holes, expected %v got %v", i, tc.holes[i], len(ply)-1)
+				}
+			}
+			if len(got.Dangles) != tc.dangles {
+				t.Errorf("dangles, expected %v got %v", tc.dangles, got.Dangles)
+			}
+			if len(got.CutEdges) != tc.cutEdges {
+				t.Errorf("cut edges, expected %v got %v", tc.cutEdges, got.CutEdges)
+			}
+		}
+	}
+
+	square := func(x, y, size float64) geom.LineString {
+		return geom.LineString{{x, y}, {x + size, y}, {x + size, y + size}, {x, y + size}, {x, y}}
+	}
+	tests := map[string]tcase{
+		"square": {
+			g:     square(0, 0, 10),
+			areas: []float64{100},
+			holes: []int{0},
+		},
+		"lines": {
+			g: []geom.Line{
+				{{0, 0}, {10, 0}}, {{10, 0}, {0, 10}}, {{0, 10}, {0, 0}},
+			},
+			areas: []float64{50},
+			holes: []int{0},
+		},
+		"donut": {
+			g:     geom.MultiLineString{square(0, 0, 10), square(2, 2, 6)},
+			areas: []float64{64, 36},
+			holes: []int{1, 0},
+		},
+		"adjacent squares": {
+			g: geom.MultiLineString{
+				square(0, 0, 10),
+				{{10, 0}, {20, 0}, {20, 10}, {10, 10}},
+			},
+			areas: []float64{100, 100},
+			holes: []int{0, 0},
+		},
+		"dangle": {
+			g: geom.MultiLineString{
+				square(0, 0, 10),
+				{{10, 10}, {15, 15}, {20, 15}},
+				{{5, 5}, {5, 8}},
+			},
+			areas:   []float64{100},
+			holes:   []int{0},
+			dangles: 3,
+		},
+		"cut edge": {
+			g: geom.MultiLineString{
+				square(0, 0, 10),
+				{{10, 10}, {15, 10}, {20, 10}},
+				square(20, 0, 10),
+			},
+			areas:    []float64{100, 100},
+			holes:    []int{0, 0},
+			cutEdges: 2,
+		},
+		"island with a bridge": {
+			g: geom.Collection{
+				square(0, 0, 20),
+				square(2, 2, 4),
+				geom.Line{{6, 6}, {10, 6}},
+				square(10, 2, 4),
+			},
+			areas:    []float64{400 - 32, 16, 16},
+			holes:    []int{2, 0, 0},
+			cutEdges: 1,
+		},
+		"no rings": {
+			g:       geom.LineString{{0, 0}, {1, 1}},
+			dangles: 1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	t.Run("unknown geometry", func(t *testing.T) {
+		if _, err := Polygonize(context.Background(), geom.Point{1, 1}); err == nil {
+			t.Errorf("error, expected an error got nil")
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package planar
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// ErrInvalidPrecision is returned when a precision is not a positive
+// number.
+var ErrInvalidPrecision = errors.New("planar: invalid precision")
+
+// PoleOfInaccessibility returns the point inside the polygon farthest from
+// its boundary, found to within precision, and its distance from the
+// boundary. It is the center of the largest circle in the polygon, the
+// best place for a label, which unlike the centroid is never outside it.
+//
+// It is found by the polylabel algorithm: the extent of the polygon is
+// split into square cells, and the cells that could hold a point farther
+// from the boundary than the best found, by more than precision, are split
+// into four in turn, the most promising first.
+//
+// ErrInvalidPrecision is returned if precision is not a positive number,
+// and ErrEmptyGeometry if the polygon has no points. For a polygon with no
+// area its first point is returned, with a distance of 0.
+func PoleOfInaccessibility(poly geom.Polygon, precision float64) (geom.Point, float64, error) {
+	if !(precision > 0) {
+		return geom.Point{}, 0, ErrInvalidPrecision
+	}
+	if len(poly) == 0 || len(poly[0]) == 0 {
+		return geom.Point{}, 0, ErrEmptyGeometry
+	}
+	ext := geom.NewExtent(poly[0]...)
+	size := math.Min(ext.XSpan(), ext.YSpan())
+	if size == 0 {
+		return geom.Point(poly[0][0]), 0, nil
+	}
+
+	newCell := func(center [2]float64, h float64) *labelCell {
+		d := boundaryDistance(poly, center)
+		return &labelCell{center: center, h: h, d: d, max: d + h*math.Sqrt2}
+	}
+
+	// the best to start with of a point inside the polygon and the center
+	// of its extent
+	best := newCell([2]float64{(ext.MinX() + ext.MaxX()) / 2, (ext.MinY() + ext.MaxY()) / 2}, 0)
+	if pt, err := PointOnSurface(poly); err == nil {
+		if c := newCell(pt, 0); c.d > best.d {
+			best = c
+		}
+	}
+
+	var cells labelCells
+	h := size / 2
+	for x := ext.MinX(); x < ext.MaxX(); x += size {
+		for y := ext.MinY(); y < ext.MaxY(); y += size {
+			cells = append(cells, newCell([2]float64{x + h, y + h}, h))
+		}
+	}
+	heap.Init(&cells)
+
+	for cells.Len() > 0 {
+		c := heap.Pop(&cells).(*labelCell)
+		if c.d > best.d {
+			best = c
+		}
+		if c.max-best.d <= precision {
+			// the cells left could not hold a better point either
+			break
+		}
+		h := c.h / 2
+		for _, off := range [4][2]float64{{-h, -h}, {h, -h}, {-h, h}, {h, h}} {
+			heap.Push(&cells, newCell([2]float64{c.center[0] + off[0], c.center[1] + off[1]}, h))
+		}
+	}
+	return geom.Point(best.center), best.d, nil
+}
+
+// boundaryDistance returns the distance of the point from the boundary of
+// the polygon, negative if it is outside it.
+func boundaryDistance(poly geom.Polygon, pt [2]float64) float64 {
+	d := math.Inf(1)
+	for _, ring := range poly {
+		for i := range ring {
+			d = math.Min(d, segmentDistance(ring[i], ring[(i+1)%len(ring)], pt))
+		}
+	}
+	if !PolygonContains(poly, pt) {
+		return -d
+	}
+	return d
+}
+
+// labelCell is a square cell searched for the pole of inaccessibility
+type labelCell struct {
+	center [2]float64
+	// h is half the size of the cell
+	h float64
+	// d is the distance of the center from the boundary of the polygon
+	d float64
+	// max is the greatest distance from the boundary a point in the cell
+	// could be
+	max float64
+}
+
+// labelCells is a heap of cells, the one that could hold the point
+// farthest from the boundary first
+type labelCells []*labelCell
+
+func (q labelCells) Len() int            { return len(q) }
+func (q labelCells) Less(i, j int) bool  { return q[i].max > q[j].max }
+func (q labelCells) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *labelCells) Push(x interface{}) { *q = append(*q, x.(*labelCell)) }
+func (q *labelCells) Pop() interface{} {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,94 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestPoleOfInaccessibility(t *testing.T) {
+	type tcase struct {
+		poly      geom.Polygon
+		precision float64
+		// distance is the distance of the pole from the boundary
+		distance float64
+		// expected is the pole, if there is only one
+		expected *geom.Point
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, d, err := PoleOfInaccessibility(tc.poly, tc.precision)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if d < tc.distance-tc.precision || d > tc.distance+1e-9 {
+				t.Errorf("distance, expected %v within %v got %v", tc.distance, tc.precision, d)
+			}
+			if bd := boundaryDistance(tc.poly, got); math.Abs(bd-d) > 1e-9 {
+				t.Errorf("distance of %v, expected %v got %v", got, d, bd)
+			}
+			if tc.expected != nil && math.Hypot(got[0]-tc.expected[0], got[1]-tc.expected[1]) > tc.precision {
+				t.Errorf("pole, expected %v got %v", *tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"square": {
+			poly:      geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			precision: 0.01,
+			distance:  5,
+			expected:  &geom.Point{5, 5},
+		},
+		"rectangle": {
+			poly:      geom.Polygon{{{0, 0}, {100, 0}, {100, 4}, {0, 4}}},
+			precision: 0.001,
+			distance:  2,
+		},
+		"u shape": {
+			// the centroid is outside, and the pole is in a corner
+			poly: geom.Polygon{{
+				{0, 0}, {10, 0}, {10, 10}, {8, 10}, {8, 2}, {2, 2}, {2, 10}, {0, 10},
+			}},
+			precision: 0.001,
+			// the circle touching the outside of a corner and the inside
+			// corner across from it
+			distance: 2 * math.Sqrt2 / (1 + math.Sqrt2),
+		},
+		"donut": {
+			poly: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{4, 4}, {4, 6}, {6, 6}, {6, 4}},
+			},
+			precision: 0.001,
+			// on a diagonal, as far from the corner of the hole as from
+			// the outside
+			distance: 4 * math.Sqrt2 / (1 + math.Sqrt2),
+		},
+		"no area": {
+			poly:      geom.Polygon{{{1, 1}, {5, 1}}},
+			precision: 1,
+			distance:  0,
+			expected:  &geom.Point{1, 1},
+		},
+		"empty": {
+			poly:      geom.Polygon{},
+			precision: 1,
+			err:       ErrEmptyGeometry,
+		},
+		"bad precision": {
+			poly: geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			err:  ErrInvalidPrecision,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,142 @@
+package predicates
+
+// expansion is an exact value held as the sum of its components, which do
+// not overlap and are in increasing order of magnitude. Zero components are
+// left out.
+type expansion []float64
+
+// twoSum returns a+b as x+y, where x is the rounded sum and y the error
+func twoSum(a, b float64) (x, y float64) {
+	x = a + b
+	bv := x - a
+	av := x - bv
+	return x, (a - av) + (b - bv)
+}
+
+// split returns a as hi+lo, each having at most 26 significant bits
+func split(a float64) (hi, lo float64) {
+	c := splitter * a
+	hi = c - (c - a)
+	return hi, a - hi
+}
+
+// twoProductParts returns a*b as x+y, where x is the rounded product and y
+// the error
+func twoProductParts(a, b float64) (x, y float64) {
+	x = a * b
+	ahi, alo := split(a)
+	bhi, blo := split(b)
+	err := x - ahi*bhi - alo*bhi - ahi*blo
+	return x, alo*blo - err
+}
+
+// twoProduct returns the exact product of a and b
+func twoProduct(a, b float64) expansion {
+	x, y := twoProductParts(a, b)
+	return expansion{y, x}.compact()
+}
+
+// twoDiff returns the exact difference of a and b
+func twoDiff(a, b float64) expansion {
+	x, y := twoSum(a, -b)
+	return expansion{y, x}.compact()
+}
+
+// compact removes the zero components
+func (e expansion) compact() expansion {
+	ret := e[:0]
+	for _, c := range e {
+		if c != 0 {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// grow returns e+b
+func (e expansion) grow(b float64) expansion {
+	ret := make(expansion, 0, len(e)+1)
+	q := b
+	for _, c := range e {
+		var h float64
+		q, h = twoSum(q, c)
+		if h != 0 {
+			ret = append(ret, h)
+		}
+	}
+	if q != 0 {
+		ret = append(ret, q)
+	}
+	return ret
+}
+
+// add returns e+f
+func (e expansion) add(f expansion) expansion {
+	for _, c := range f {
+		e = e.grow(c)
+	}
+	return e
+}
+
+// scale returns e*b
+func (e expansion) scale(b float64) expansion {
+	if len(e) == 0 || b == 0 {
+		return nil
+	}
+	ret := make(expansion, 0, 2*len(e))
+	q, h := twoProductParts(e[0], b)
+	if h != 0 {
+		ret = append(ret, h)
+	}
+	for _, c := range e[1:] {
+		p1, p0 := twoProductParts(c, b)
+		var sum float64
+		sum, h = twoSum(q, p0)
+		if h != 0 {
+			ret = append(ret, h)
+		}
+		q, h = twoSum(p1, sum)
+		if h != 0 {
+			ret = append(ret, h)
+		}
+	}
+	if q != 0 {
+		ret = append(ret, q)
+	}
+	return ret
+}
+
+// mul returns e*f
+func (e expansion) mul(f expansion) expansion {
+	var ret expansion
+	for _, c := range f {
+		ret = ret.add(e.scale(c))
+	}
+	return ret
+}
+
+// neg returns -e
+func (e expansion) neg() expansion {
+	ret := make(expansion, len(e))
+	for i, c := range e {
+		ret[i] = -c
+	}
+	return ret
+}
+
+// estimate returns the value of the expansion rounded to a float64, which
+// has the sign of the exact value
+func (e expansion) estimate() float64 {
+	if len(e) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, c := range e {
+		sum += c
+	}
+	// the largest component decides the sign
+	if last := e[len(e)-1]; (sum > 0) != (last > 0) {
+		return last
+	}
+	return sum
+}
@@ -0,0 +1,115 @@
+// Package predicates provides the orientation and in circle tests with
+// results whose sign is always correct, using Shewchuk's adaptive precision
+// floating point arithmetic.
+//
+// Each test first evaluates its determinant with ordinary floating point,
+// which is enough for all but nearly degenerate inputs. When the result is
+// too small to be trusted the determinant is evaluated again exactly, using
+// expansions: sums of non-overlapping floating point numbers.
+//
+// Reference: Jonathan Richard Shewchuk, Adaptive Precision Floating-Point
+// Arithmetic and Fast Robust Geometric Predicates, 1997.
+package predicates
+
+import "math"
+
+const (
+	// epsilon is half the distance between 1 and the next float64
+	epsilon = 1.0 / (1 << 53)
+	// splitter is used to split a float64 into two halves that can be
+	// multiplied exactly
+	splitter = 1<<27 + 1
+
+	// the bounds on the relative error of the floating point evaluations
+	orientErrBound   = (3 + 16*epsilon) * epsilon
+	inCircleErrBound = (10 + 96*epsilon) * epsilon
+)
+
+// Orient2D returns a positive value if the points a, b and c are in counter
+// clockwise order, a negative value if they are clockwise and zero if they
+// are collinear. The axes are taken to be x to the right and y up. The value
+// approximates twice the signed area of the triangle abc.
+func Orient2D(a, b, c [2]float64) float64 {
+	detLeft := (a[0] - c[0]) * (b[1] - c[1])
+	detRight := (a[1] - c[1]) * (b[0] - c[0])
+	det := detLeft - detRight
+
+	var detSum float64
+	switch {
+	case detLeft > 0:
+		if detRight <= 0 {
+			return det
+		}
+		detSum = detLeft + detRight
+	case detLeft < 0:
+		if detRight >= 0 {
+			return det
+		}
+		detSum = -detLeft - detRight
+	default:
+		return det
+	}
+	if math.Abs(det) >= orientErrBound*detSum {
+		return det
+	}
+	return orient2DExact(a, b, c)
+}
+
+// orient2DExact evaluates the orientation determinant exactly
+func orient2DExact(a, b, c [2]float64) float64 {
+	// (ax-cx)(by-cy) - (ay-cy)(bx-cx) expanded into products of the
+	// coordinates, each of which is exact as an expansion of two terms
+	var e expansion
+	e = e.add(twoProduct(a[0], b[1]))
+	e = e.add(twoProduct(-a[0], c[1]))
+	e = e.add(twoProduct(-c[0], b[1]))
+	e = e.add(twoProduct(-a[1], b[0]))
+	e = e.add(twoProduct(a[1], c[0]))
+	e = e.add(twoProduct(c[1], b[0]))
+	return e.estimate()
+}
+
+// InCircle returns a positive value if the point d is inside the circle
+// through the points a, b and c, a negative value if it is outside and zero
+// if it is on the circle, when a, b and c are in counter clockwise order.
+// The sign is reversed when they are clockwise.
+func InCircle(a, b, c, d [2]float64) float64 {
+	adx, ady := a[0]-d[0], a[1]-d[1]
+	bdx, bdy := b[0]-d[0], b[1]-d[1]
+	cdx, cdy := c[0]-d[0], c[1]-d[1]
+
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	alift := adx*adx + ady*ady
+
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	blift := bdx*bdx + bdy*bdy
+
+	adxbdy, bdxady := adx*bdy, bdx*ady
+	clift := cdx*cdx + cdy*cdy
+
+	det := alift*(bdxcdy-cdxbdy) + blift*(cdxady-adxcdy) + clift*(adxbdy-bdxady)
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*alift +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*blift +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*clift
+	if math.Abs(det) > inCircleErrBound*permanent {
+		return det
+	}
+	return inCircleExact(a, b, c, d)
+}
+
+// inCircleExact evaluates the in circle determinant exactly
+func inCircleExact(a, b, c, d [2]float64) float64 {
+	adx, ady := twoDiff(a[0], d[0]), twoDiff(a[1], d[1])
+	bdx, bdy := twoDiff(b[0], d[0]), twoDiff(b[1], d[1])
+	cdx, cdy := twoDiff(c[0], d[0]), twoDiff(c[1], d[1])
+
+	alift := adx.mul(adx).add(ady.mul(ady))
+	blift := bdx.mul(bdx).add(bdy.mul(bdy))
+	clift := cdx.mul(cdx).add(cdy.mul(cdy))
+
+	bc := bdx.mul(cdy).add(cdx.mul(bdy).neg())
+	ca := cdx.mul(ady).add(adx.mul(cdy).neg())
+	ab := adx.mul(bdy).add(bdx.mul(ady).neg())
+
+	return alift.mul(bc).add(blift.mul(ca)).add(clift.mul(ab)).estimate()
+}
@@ -0,0 +1,152 @@
+package predicates
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// rat returns the exact value of the float
+func rat(f float64) *big.Rat { return new(big.Rat).SetFloat64(f) }
+
+func sub(a, b *big.Rat) *big.Rat { return new(big.Rat).Sub(a, b) }
+func mul(a, b *big.Rat) *big.Rat { return new(big.Rat).Mul(a, b) }
+func add(a, b *big.Rat) *big.Rat { return new(big.Rat).Add(a, b) }
+
+// exactOrient returns the sign of the orientation determinant using
+// rational arithmetic
+func exactOrient(a, b, c [2]float64) int {
+	acx, bcy := sub(rat(a[0]), rat(c[0])), sub(rat(b[1]), rat(c[1]))
+	acy, bcx := sub(rat(a[1]), rat(c[1])), sub(rat(b[0]), rat(c[0]))
+	return sub(mul(acx, bcy), mul(acy, bcx)).Sign()
+}
+
+// exactInCircle returns the sign of the in circle determinant using
+// rational arithmetic
+func exactInCircle(a, b, c, d [2]float64) int {
+	diff := func(p [2]float64) (x, y, lift *big.Rat) {
+		x, y = sub(rat(p[0]), rat(d[0])), sub(rat(p[1]), rat(d[1]))
+		return x, y, add(mul(x, x), mul(y, y))
+	}
+	adx, ady, alift := diff(a)
+	bdx, bdy, blift := diff(b)
+	cdx, cdy, clift := diff(c)
+	det := mul(alift, sub(mul(bdx, cdy), mul(cdx, bdy)))
+	det = add(det, mul(blift, sub(mul(cdx, ady), mul(adx, cdy))))
+	det = add(det, mul(clift, sub(mul(adx, bdy), mul(bdx, ady))))
+	return det.Sign()
+}
+
+func sign(f float64) int {
+	switch {
+	case f > 0:
+		return 1
+	case f < 0:
+		return -1
+	}
+	return 0
+}
+
+// nudge moves the value n floats away
+func nudge(f float64, n int) float64 {
+	for ; n > 0; n-- {
+		f = math.Nextafter(f, math.Inf(1))
+	}
+	for ; n < 0; n++ {
+		f = math.Nextafter(f, math.Inf(-1))
+	}
+	return f
+}
+
+func TestOrient2D(t *testing.T) {
+	type tcase struct {
+		a, b, c [2]float64
+		sign    int
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if got := sign(Orient2D(tc.a, tc.b, tc.c)); got != tc.sign {
+				t.Errorf("sign, expected %v got %v", tc.sign, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"counter clockwise": {a: [2]float64{0, 0}, b: [2]float64{1, 0}, c: [2]float64{0, 1}, sign: 1},
+		"clockwise":         {a: [2]float64{0, 0}, b: [2]float64{0, 1}, c: [2]float64{1, 0}, sign: -1},
+		"collinear":         {a: [2]float64{0, 0}, b: [2]float64{1, 1}, c: [2]float64{2, 2}, sign: 0},
+		"nearly collinear": {
+			a:    [2]float64{nudge(0.5, 1), 0.5},
+			b:    [2]float64{12, 12},
+			c:    [2]float64{24, 24},
+			sign: exactOrient([2]float64{nudge(0.5, 1), 0.5}, [2]float64{12, 12}, [2]float64{24, 24}),
+		},
+		"collinear with inexact differences": {
+			a:    [2]float64{0.1, 0.1},
+			b:    [2]float64{0.3, 0.3},
+			c:    [2]float64{1e10 + 0.7, 1e10 + 0.7},
+			sign: 0,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	// points near the line y = x, where the floating point evaluation
+	// often gets the sign wrong
+	for i := -64; i < 64; i++ {
+		for j := -64; j < 64; j++ {
+			a := [2]float64{nudge(0.5, i), nudge(0.5, j)}
+			b, c := [2]float64{12, 12}, [2]float64{24, 24}
+			if got, expected := sign(Orient2D(a, b, c)), exactOrient(a, b, c); got != expected {
+				t.Fatalf("sign of %v %v %v, expected %v got %v", a, b, c, expected, got)
+			}
+		}
+	}
+}
+
+func TestInCircle(t *testing.T) {
+	type tcase struct {
+		a, b, c, d [2]float64
+		sign       int
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if got := sign(InCircle(tc.a, tc.b, tc.c, tc.d)); got != tc.sign {
+				t.Errorf("sign, expected %v got %v", tc.sign, got)
+			}
+		}
+	}
+
+	a, b, c := [2]float64{1, 0}, [2]float64{0, 1}, [2]float64{-1, 0}
+	tests := map[string]tcase{
+		"inside":             {a: a, b: b, c: c, d: [2]float64{0, 0}, sign: 1},
+		"outside":            {a: a, b: b, c: c, d: [2]float64{2, 2}, sign: -1},
+		"on":                 {a: a, b: b, c: c, d: [2]float64{0, -1}, sign: 0},
+		"inside clockwise":   {a: c, b: b, c: a, d: [2]float64{0, 0}, sign: -1},
+		"just inside":        {a: a, b: b, c: c, d: [2]float64{0, nudge(-1, 1)}, sign: 1},
+		"just outside":       {a: a, b: b, c: c, d: [2]float64{0, nudge(-1, -1)}, sign: -1},
+		"cocircular squares": {a: [2]float64{1e9, 1e9}, b: [2]float64{1e9 + 1, 1e9}, c: [2]float64{1e9 + 1, 1e9 + 1}, d: [2]float64{1e9, 1e9 + 1}, sign: 0},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	// nearly cocircular points
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		pt := func() [2]float64 {
+			s, c := math.Sincos(rnd.Float64() * 2 * math.Pi)
+			return [2]float64{100 + 7*c, 100 + 7*s}
+		}
+		a, b, c, d := pt(), pt(), pt(), pt()
+		if got, expected := sign(InCircle(a, b, c, d)), exactInCircle(a, b, c, d); got != expected {
+			t.Fatalf("sign of %v %v %v %v, expected %v got %v", a, b, c, d, expected, got)
+		}
+	}
+}
@@ -0,0 +1,659 @@
+package planar
+
+import (
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/winding"
+)
+
+// location is where a point is relative to a geometry
+type location int
+
+const (
+	exterior location = iota
+	boundary
+	interior
+)
+
+// edgeKind is the part of a geometry an indexed edge comes from
+type edgeKind int
+
+const (
+	// pointEdge is a point, with both ends of the edge the same
+	pointEdge edgeKind = iota
+	// lineEndEdge is the end of a line that is not closed, with both ends
+	// of the edge the same
+	lineEndEdge
+	lineEdge
+	ringEdge
+)
+
+type indexedEdge struct {
+	seg  [2][2]float64
+	kind edgeKind
+}
+
+// geomParts are the points, lines and polygons of a geometry, without
+// repeated points. The rings of the polygons are not closed. Points with
+// NaN or infinite coordinates, such as the empty point, are left out. The
+// z and m values of Z, M and ZM geometries are dropped.
+type geomParts struct {
+	points [][2]float64
+	lines  [][][2]float64
+	polys  [][][][2]float64
+}
+
+func (p *geomParts) add(g geom.Geometry) error {
+	switch gg := g.(type) {
+
+	case geom.Collectioner:
+		for _, g := range gg.Geometries() {
+			if err := p.add(g); err != nil {
+				return err
+			}
+		}
+
+	case geom.MultiPolygoner:
+		for _, ply := range gg.Polygons() {
+			p.addPolygon(ply)
+		}
+
+	case geom.Polygoner:
+		p.addPolygon(gg.LinearRings())
+
+	case geom.MultiLineStringer:
+		for _, ln := range gg.LineStrings() {
+			p.addLine(ln)
+		}
+
+	case geom.LineStringer:
+		p.addLine(gg.Vertices())
+
+	case geom.MultiPointer:
+		p.points = append(p.points, finitePoints(gg.Points())...)
+
+	case geom.Pointer:
+		p.points = append(p.points, finitePoints([][2]float64{gg.XY()})...)
+
+	default:
+		if xy, ok := geom.XYGeometry(g); ok {
+			return p.add(xy)
+		}
+		return geom.ErrUnknownGeometry{Geom: g}
+	}
+	return nil
+}
+
+func (p *geomParts) addLine(ln [][2]float64) {
+	ln = dedupPoints(finitePoints(ln), false)
+	switch len(ln) {
+	case 0:
+	case 1:
+		p.points = append(p.points, ln[0])
+	default:
+		p.lines = append(p.lines, ln)
+	}
+}
+
+func (p *geomParts) addPolygon(rings [][][2]float64) {
+	var ply [][][2]float64
+	for _, ring := range rings {
+		ring = dedupPoints(finitePoints(ring), true)
+		if len(ring) < 3 {
+			continue
+		}
+		ply = append(ply, ring)
+	}
+	if len(ply) > 0 {
+		p.polys = append(p.polys, ply)
+	}
+}
+
+// isFinite reports whether neither coordinate of the point is NaN or
+// infinite
+func isFinite(pt [2]float64) bool {
+	return !math.IsNaN(pt[0]) && !math.IsNaN(pt[1]) && !math.IsInf(pt[0], 0) && !math.IsInf(pt[1], 0)
+}
+
+// finitePoints returns the points that are finite, which are the points
+// given if they all are
+func finitePoints(pts [][2]float64) [][2]float64 {
+	for i, pt := range pts {
+		if isFinite(pt) {
+			continue
+		}
+		ret := append([][2]float64(nil), pts[:i]...)
+		for _, pt := range pts[i+1:] {
+			if isFinite(pt) {
+				ret = append(ret, pt)
+			}
+		}
+		return ret
+	}
+	return pts
+}
+
+// dim returns the dimension of the highest dimension part, or -1 if there
+// are no parts
+func (p *geomParts) dim() int {
+	switch {
+	case len(p.polys) > 0:
+		return 2
+	case len(p.lines) > 0:
+		return 1
+	case len(p.points) > 0:
+		return 0
+	}
+	return -1
+}
+
+// boundaryDim returns the dimension of the boundary of the parts, or -1
+// if there is none: the rings of the polygons, or the ends of the lines
+// that are not closed
+func (p *geomParts) boundaryDim() int {
+	if len(p.polys) > 0 {
+		return 1
+	}
+	for _, ln := range p.lines {
+		if ln[0] != ln[len(ln)-1] {
+			return 0
+		}
+	}
+	return -1
+}
+
+// vertices returns the points, and the vertices of the lines and rings
+func (p *geomParts) vertices() [][2]float64 {
+	ret := append([][2]float64(nil), p.points...)
+	for _, ln := range p.lines {
+		ret = append(ret, ln...)
+	}
+	for _, ply := range p.polys {
+		for _, ring := range ply {
+			ret = append(ret, ring...)
+		}
+	}
+	return ret
+}
+
+// edges returns the indexed edges of the parts
+func (p *geomParts) edges() []indexedEdge {
+	var ret []indexedEdge
+	for _, pt := range p.points {
+		ret = append(ret, indexedEdge{seg: [2][2]float64{pt, pt}, kind: pointEdge})
+	}
+	for _, ln := range p.lines {
+		for i := 0; i < len(ln)-1; i++ {
+			ret = append(ret, indexedEdge{seg: [2][2]float64{ln[i], ln[i+1]}, kind: lineEdge})
+		}
+		if first, last := ln[0], ln[len(ln)-1]; first != last {
+			ret = append(ret,
+				indexedEdge{seg: [2][2]float64{first, first}, kind: lineEndEdge},
+				indexedEdge{seg: [2][2]float64{last, last}, kind: lineEndEdge},
+			)
+		}
+	}
+	for _, ply := range p.polys {
+		for _, ring := range ply {
+			for i := range ring {
+				ret = append(ret, indexedEdge{seg: [2][2]float64{ring[i], ring[(i+1)%len(ring)]}, kind: ringEdge})
+			}
+		}
+	}
+	return ret
+}
+
+// PreparedGeometry is a geometry with its edges indexed and its extent and
+// an interior point worked out, for testing it against many geometries. It
+// is safe for concurrent use.
+//
+// Points are within a small tolerance, relative to the size of the
+// geometry, of the edges they are on.
+type PreparedGeometry struct {
+	parts    geomParts
+	ext      [4]float64
+	tol      float64
+	interior geom.Point
+	polys    []*PreparedPolygon
+	edges    []indexedEdge
+	// bands holds the indexes of the edges crossing each horizontal band of
+	// the extent
+	bands [][]int
+	bandH float64
+}
+
+// Prepare returns the prepared geometry. Points, lines, polygons, their
+// multi geometries and collections of them are supported; z and m values
+// are ignored.
+func Prepare(g geom.Geometry) (*PreparedGeometry, error) {
+	var p geomParts
+	if err := p.add(g); err != nil {
+		return nil, err
+	}
+	ext := pointsExtent(p.vertices())
+	span := math.Max(ext[2]-ext[0], ext[3]-ext[1])
+	if span == 0 || math.IsInf(span, 0) {
+		span = 1
+	}
+	return newPrepared(p, nodingTolerance*span), nil
+}
+
+func newPrepared(p geomParts, tol float64) *PreparedGeometry {
+	pg := &PreparedGeometry{
+		parts: p,
+		ext:   pointsExtent(p.vertices()),
+		tol:   tol,
+		edges: p.edges(),
+	}
+	pg.interior = interiorPoint(p)
+	for _, ply := range p.polys {
+		pg.polys = append(pg.polys, NewPreparedPolygon(ply))
+	}
+	if len(pg.edges) == 0 || math.IsInf(pg.ext[0], 0) {
+		// an empty geometry
+		return pg
+	}
+
+	pg.bands = make([][]int, len(pg.edges))
+	pg.bandH = (pg.ext[3] - pg.ext[1]) / float64(len(pg.edges))
+	for i, e := range pg.edges {
+		lo, hi := pg.band(math.Min(e.seg[0][1], e.seg[1][1])), pg.band(math.Max(e.seg[0][1], e.seg[1][1]))
+		for j := lo; j <= hi; j++ {
+			pg.bands[j] = append(pg.bands[j], i)
+		}
+	}
+	return pg
+}
+
+func pointsExtent(pts [][2]float64) [4]float64 {
+	ext := [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, pt := range pts {
+		ext = [4]float64{
+			math.Min(ext[0], pt[0]), math.Min(ext[1], pt[1]),
+			math.Max(ext[2], pt[0]), math.Max(ext[3], pt[1]),
+		}
+	}
+	return ext
+}
+
+// band returns the index of the band holding y, clamped to the bands
+func (pg *PreparedGeometry) band(y float64) int {
+	if pg.bandH == 0 || y <= pg.ext[1] {
+		return 0
+	}
+	i := int((y - pg.ext[1]) / pg.bandH)
+	if i >= len(pg.bands) {
+		i = len(pg.bands) - 1
+	}
+	return i
+}
+
+// visitEdges calls fn with the edges that may be within the y range, until
+// fn returns false. Each edge is visited once, in the lowest band it shares
+// with the range.
+func (pg *PreparedGeometry) visitEdges(minY, maxY float64, fn func(e indexedEdge) bool) {
+	if len(pg.bands) == 0 || maxY < pg.ext[1]-pg.tol || minY > pg.ext[3]+pg.tol {
+		return
+	}
+	lo, hi := pg.band(minY), pg.band(maxY)
+	for j := lo; j <= hi; j++ {
+		for _, i := range pg.bands[j] {
+			e := pg.edges[i]
+			if j != lo && pg.band(math.Min(e.seg[0][1], e.seg[1][1])) != j {
+				// already visited in an earlier band
+				continue
+			}
+			if !fn(e) {
+				return
+			}
+		}
+	}
+}
+
+// Extent returns the extent of the geometry, or nil if it is empty.
+func (pg *PreparedGeometry) Extent() *geom.Extent {
+	if math.IsInf(pg.ext[0], 0) {
+		return nil
+	}
+	ext := geom.Extent(pg.ext)
+	return &ext
+}
+
+// InteriorPoint returns a point in the interior of the highest dimension
+// parts of the geometry, or the origin if it is empty. For polygons it is
+// the middle of the widest span of a horizontal line through the largest
+// polygon.
+func (pg *PreparedGeometry) InteriorPoint() geom.Point { return pg.interior }
+
+// locate returns where the point is relative to the geometry
+func (pg *PreparedGeometry) locate(pt [2]float64) location {
+	loc := exterior
+	onLine, atEnd := false, false
+	pg.visitEdges(pt[1]-pg.tol, pt[1]+pg.tol, func(e indexedEdge) bool {
+		if segmentDistance(e.seg[0], e.seg[1], pt) > pg.tol {
+			return true
+		}
+		switch e.kind {
+		case pointEdge:
+			loc = interior
+		case lineEndEdge:
+			atEnd = true
+		case lineEdge:
+			onLine = true
+		case ringEdge:
+			if loc < boundary {
+				loc = boundary
+			}
+		}
+		return loc != interior
+	})
+	switch {
+	case loc == interior:
+		return loc
+	case onLine && !atEnd:
+		return interior
+	case atEnd && loc < boundary:
+		loc = boundary
+	}
+	if loc == boundary {
+		return loc
+	}
+	for _, pp := range pg.polys {
+		if pp.Contains(pt) {
+			return interior
+		}
+	}
+	return loc
+}
+
+// segmentDistance returns the distance of the point from the segment a, b
+func segmentDistance(a, b, pt [2]float64) float64 {
+	ab, ap := vsub(b, a), vsub(pt, a)
+	l := vdot(ab, ab)
+	if l == 0 {
+		return math.Hypot(ap[0], ap[1])
+	}
+	t := math.Max(0, math.Min(1, vdot(ap, ab)/l))
+	return math.Hypot(ap[0]-t*ab[0], ap[1]-t*ab[1])
+}
+
+// splitPoints returns the points where the segment touches the edges of the
+// geometry, including its ends, in order along the segment
+func (pg *PreparedGeometry) splitPoints(a, b [2]float64) [][2]float64 {
+	pts := [][2]float64{a, b}
+	seg := geom.Line{a, b}
+	pg.visitEdges(math.Min(a[1], b[1])-pg.tol, math.Max(a[1], b[1])+pg.tol, func(e indexedEdge) bool {
+		if e.seg[0] == e.seg[1] {
+			if segmentDistance(a, b, e.seg[0]) <= pg.tol {
+				pts = append(pts, e.seg[0])
+			}
+			return true
+		}
+		pts1, _ := segmentTouches(seg, geom.Line(e.seg))
+		pts = append(pts, pts1...)
+		return true
+	})
+	ab := vsub(b, a)
+	sort.Slice(pts, func(i, j int) bool {
+		return vdot(vsub(pts[i], a), ab) < vdot(vsub(pts[j], a), ab)
+	})
+	return pts
+}
+
+// touches reports whether the segment touches any edge of the geometry
+func (pg *PreparedGeometry) touches(a, b [2]float64) bool {
+	found := false
+	seg := geom.Line{a, b}
+	pg.visitEdges(math.Min(a[1], b[1])-pg.tol, math.Max(a[1], b[1])+pg.tol, func(e indexedEdge) bool {
+		if e.seg[0] == e.seg[1] {
+			found = segmentDistance(a, b, e.seg[0]) <= pg.tol
+		} else {
+			pts1, _ := segmentTouches(seg, geom.Line(e.seg))
+			found = len(pts1) > 0
+		}
+		return !found
+	})
+	return found
+}
+
+// testPoint is a point of a geometry being tested against a prepared
+// geometry, and whether it is in the interior of that geometry
+type testPoint struct {
+	pt       [2]float64
+	interior bool
+}
+
+// testPoints returns the vertices of the parts and the middle of each piece
+// of their edges between the points they touch the prepared geometry
+func (pg *PreparedGeometry) testPoints(p geomParts) []testPoint {
+	var ret []testPoint
+	for _, pt := range p.points {
+		ret = append(ret, testPoint{pt: pt, interior: true})
+	}
+	addEdges := func(pts [][2]float64, closed bool) {
+		n := len(pts) - 1
+		if closed {
+			n = len(pts)
+		}
+		for i := 0; i < n; i++ {
+			split := pg.splitPoints(pts[i], pts[(i+1)%len(pts)])
+			for j := 0; j < len(split)-1; j++ {
+				if split[j] == split[j+1] {
+					continue
+				}
+				mid := [2]float64{(split[j][0] + split[j+1][0]) / 2, (split[j][1] + split[j+1][1]) / 2}
+				ret = append(ret, testPoint{pt: mid, interior: len(p.polys) == 0})
+			}
+		}
+	}
+	for _, ln := range p.lines {
+		closed := ln[0] == ln[len(ln)-1]
+		for i, pt := range ln {
+			ret = append(ret, testPoint{pt: pt, interior: closed || (i > 0 && i < len(ln)-1)})
+		}
+		addEdges(ln, false)
+	}
+	for _, ply := range p.polys {
+		for _, ring := range ply {
+			for _, pt := range ring {
+				ret = append(ret, testPoint{pt: pt})
+			}
+			addEdges(ring, true)
+		}
+	}
+	return ret
+}
+
+// Intersects reports whether the geometry and the prepared geometry have
+// any point in common.
+func (pg *PreparedGeometry) Intersects(g geom.Geometry) (bool, error) {
+	var p geomParts
+	if err := p.add(g); err != nil {
+		return false, err
+	}
+	ext := pointsExtent(p.vertices())
+	if p.dim() < 0 || pg.parts.dim() < 0 || !pg.extentOverlaps(ext) {
+		return false, nil
+	}
+
+	// a vertex of the geometry is on or in the prepared geometry
+	for _, pt := range p.vertices() {
+		if pg.locate(pt) != exterior {
+			return true, nil
+		}
+	}
+	// an edge of the geometry touches an edge of the prepared geometry
+	for _, e := range p.edges() {
+		if e.kind != lineEndEdge && pg.touches(e.seg[0], e.seg[1]) {
+			return true, nil
+		}
+	}
+	// the prepared geometry is inside the geometry
+	if len(p.polys) == 0 {
+		return false, nil
+	}
+	q := newPrepared(p, pg.tol)
+	for _, pt := range pg.parts.points {
+		if q.locate(pt) != exterior {
+			return true, nil
+		}
+	}
+	for _, ln := range pg.parts.lines {
+		if q.locate(ln[0]) != exterior {
+			return true, nil
+		}
+	}
+	for _, ply := range pg.parts.polys {
+		if q.locate(ply[0][0]) != exterior {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Covers reports whether every point of the geometry is a point of the
+// prepared geometry. Empty geometries are not covered.
+func (pg *PreparedGeometry) Covers(g geom.Geometry) (bool, error) {
+	covers, _, err := pg.covers(g)
+	return covers, err
+}
+
+// Contains reports whether every point of the geometry is a point of the
+// prepared geometry, and the interiors of the geometries have a point in
+// common. Unlike Covers, a geometry that is only on the boundary of the
+// prepared geometry is not contained.
+func (pg *PreparedGeometry) Contains(g geom.Geometry) (bool, error) {
+	covers, interiors, err := pg.covers(g)
+	return covers && interiors, err
+}
+
+// covers reports whether the geometry is covered by the prepared geometry,
+// and whether their interiors have a point in common
+func (pg *PreparedGeometry) covers(g geom.Geometry) (covers, interiors bool, err error) {
+	var p geomParts
+	if err := p.add(g); err != nil {
+		return false, false, err
+	}
+	ext := pointsExtent(p.vertices())
+	if p.dim() < 0 || p.dim() > pg.parts.dim() || !pg.extentCovers(ext) {
+		return false, false, nil
+	}
+
+	for _, tp := range pg.testPoints(p) {
+		switch pg.locate(tp.pt) {
+		case exterior:
+			return false, false, nil
+		case interior:
+			interiors = interiors || tp.interior
+		}
+	}
+	if len(p.polys) == 0 {
+		return true, interiors, nil
+	}
+
+	// The boundary of the polygons is covered, but the polygons may also
+	// cover holes or gaps of the prepared geometry. Look for pieces of the
+	// prepared geometry's edges inside the polygons, which have outside
+	// the prepared geometry on one side.
+	q := newPrepared(p, pg.tol)
+	off := 100 * pg.tol
+	for _, tp := range q.testPoints(pg.parts) {
+		if tp.interior || q.locate(tp.pt) != interior {
+			continue
+		}
+		if pg.locate(tp.pt) == interior {
+			continue
+		}
+		for _, d := range [][2]float64{{off, 0}, {-off, 0}, {0, off}, {0, -off}} {
+			pt := [2]float64{tp.pt[0] + d[0], tp.pt[1] + d[1]}
+			if q.locate(pt) == interior && pg.locate(pt) == exterior {
+				return false, false, nil
+			}
+		}
+	}
+	// the interior of a polygon inside the prepared polygons is in their
+	// interior
+	return true, true, nil
+}
+
+func (pg *PreparedGeometry) extentOverlaps(ext [4]float64) bool {
+	return ext[0] <= pg.ext[2]+pg.tol && ext[2] >= pg.ext[0]-pg.tol &&
+		ext[1] <= pg.ext[3]+pg.tol && ext[3] >= pg.ext[1]-pg.tol
+}
+
+func (pg *PreparedGeometry) extentCovers(ext [4]float64) bool {
+	return ext[0] >= pg.ext[0]-pg.tol && ext[2] <= pg.ext[2]+pg.tol &&
+		ext[1] >= pg.ext[1]-pg.tol && ext[3] <= pg.ext[3]+pg.tol
+}
+
+// interiorPoint returns a point in the interior of the highest dimension
+// parts
+func interiorPoint(p geomParts) geom.Point {
+	switch p.dim() {
+	case 2:
+		var best [][][2]float64
+		var bestArea float64
+		for _, ply := range p.polys {
+			if a := math.Abs(winding.SignedArea(ply[0]...)); best == nil || a > bestArea {
+				best, bestArea = ply, a
+			}
+		}
+		return polygonInteriorPoint(best)
+	case 1:
+		for _, ln := range p.lines {
+			if len(ln) > 2 {
+				return ln[1]
+			}
+		}
+		a, b := p.lines[0][0], p.lines[0][1]
+		return geom.Point{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2}
+	case 0:
+		return p.points[0]
+	}
+	return geom.Point{}
+}
+
+// polygonInteriorPoint returns the middle of the widest span inside the
+// polygon of a horizontal line between the middle two distinct y values of
+// its vertices
+func polygonInteriorPoint(ply [][][2]float64) geom.Point {
+	var ys []float64
+	for _, ring := range ply {
+		for _, pt := range ring {
+			ys = append(ys, pt[1])
+		}
+	}
+	sort.Float64s(ys)
+	uniq := ys[:1]
+	for _, y := range ys[1:] {
+		if y != uniq[len(uniq)-1] {
+			uniq = append(uniq, y)
+		}
+	}
+	if len(uniq) < 2 {
+		return ply[0][0]
+	}
+	mid := len(uniq) / 2
+	y := (uniq[mid-1] + uniq[mid]) / 2
+
+	var xs []float64
+	for _, ring := range ply {
+		for i := range ring {
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			if (a[1] < y) != (b[1] < y) {
+				xs = append(xs, a[0]+(y-a[1])*(b[0]-a[0])/(b[1]-a[1]))
+			}
+		}
+	}
+	sort.Float64s(xs)
+	best := geom.Point{ply[0][0][0], ply[0][0][1]}
+	width := -1.0
+	for i := 0; i+1 < len(xs); i += 2 {
+		if w := xs[i+1] - xs[i]; w > width {
+			best, width = geom.Point{(xs[i] + xs[i+1]) / 2, y}, w
+		}
+	}
+	return best
+}
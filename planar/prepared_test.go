@@ -0,0 +1,251 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestPreparedGeometry(t *testing.T) {
+	// a square with a square hole, and a separate square
+	prepared := geom.MultiPolygon{
+		{
+			{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+			{{4, 4}, {4, 6}, {6, 6}, {6, 4}},
+		},
+		{{{20, 0}, {30, 0}, {30, 10}, {20, 10}}},
+	}
+	pg, err := Prepare(prepared)
+	if err != nil {
+		t.Fatalf("prepare, expected nil got %v", err)
+	}
+
+	type tcase struct {
+		g          geom.Geometry
+		intersects bool
+		covers     bool
+		contains   bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			intersects, err := pg.Intersects(tc.g)
+			if err != nil {
+				t.Fatalf("intersects, expected nil got %v", err)
+			}
+			if intersects != tc.intersects {
+				t.Errorf("intersects, expected %v got %v", tc.intersects, intersects)
+			}
+			covers, err := pg.Covers(tc.g)
+			if err != nil {
+				t.Fatalf("covers, expected nil got %v", err)
+			}
+			if covers != tc.covers {
+				t.Errorf("covers, expected %v got %v", tc.covers, covers)
+			}
+			contains, err := pg.Contains(tc.g)
+			if err != nil {
+				t.Fatalf("contains, expected nil got %v", err)
+			}
+			if contains != tc.contains {
+				t.Errorf("contains, expected %v got %v", tc.contains, contains)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point inside":      {g: geom.Point{1, 1}, intersects: true, covers: true, contains: true},
+		"point on boundary": {g: geom.Point{0, 5}, intersects: true, covers: true},
+		"point in hole":     {g: geom.Point{5, 5}},
+		"point outside":     {g: geom.Point{15, 5}},
+		"points both": {
+			g:          geom.MultiPoint{{1, 1}, {15, 5}},
+			intersects: true,
+		},
+		"line inside": {
+			g:          geom.LineString{{1, 1}, {3, 8}, {25, 5}},
+			intersects: true,
+		},
+		"line across hole": {
+			g:          geom.LineString{{1, 5}, {9, 5}},
+			intersects: true,
+		},
+		"line around hole": {
+			g:          geom.LineString{{1, 1}, {9, 1}, {9, 9}},
+			intersects: true, covers: true, contains: true,
+		},
+		"line on boundary": {
+			g:          geom.LineString{{0, 0}, {10, 0}},
+			intersects: true, covers: true,
+		},
+		"line through gap": {
+			g:          geom.LineString{{12, -5}, {12, 15}},
+			intersects: false,
+		},
+		"polygon inside": {
+			g:          geom.Polygon{{{1, 1}, {3, 1}, {3, 3}, {1, 3}}},
+			intersects: true, covers: true, contains: true,
+		},
+		"polygon same": {
+			g:          geom.Polygon{{{20, 0}, {30, 0}, {30, 10}, {20, 10}}},
+			intersects: true, covers: true, contains: true,
+		},
+		"polygon around hole": {
+			g:          geom.Polygon{{{2, 2}, {8, 2}, {8, 8}, {2, 8}}},
+			intersects: true,
+		},
+		"polygon in hole": {
+			g: geom.Polygon{{{4.5, 4.5}, {5.5, 4.5}, {5.5, 5.5}, {4.5, 5.5}}},
+		},
+		"polygon around everything": {
+			g:          geom.Polygon{{{-1, -1}, {31, -1}, {31, 11}, {-1, 11}}},
+			intersects: true,
+		},
+		"polygon across gap": {
+			g:          geom.Polygon{{{5, -1}, {25, -1}, {25, -2}, {5, -2}}},
+			intersects: false,
+		},
+		"polygon with hole filled": {
+			g: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{4, 4}, {4, 6}, {6, 6}, {6, 4}},
+			},
+			intersects: true, covers: true, contains: true,
+		},
+		"collection": {
+			g: geom.Collection{
+				geom.Point{1, 1},
+				geom.LineString{{21, 1}, {29, 9}},
+			},
+			intersects: true, covers: true, contains: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestPreparedGeometryLines(t *testing.T) {
+	pg, err := Prepare(geom.LineString{{0, 0}, {10, 0}, {10, 10}})
+	if err != nil {
+		t.Fatalf("prepare, expected nil got %v", err)
+	}
+
+	type tcase struct {
+		g          geom.Geometry
+		intersects bool
+		covers     bool
+		contains   bool
+	}
+	tests := map[string]tcase{
+		"point on line":   {g: geom.Point{5, 0}, intersects: true, covers: true, contains: true},
+		"point at end":    {g: geom.Point{0, 0}, intersects: true, covers: true},
+		"point off line":  {g: geom.Point{5, 1}},
+		"part of line":    {g: geom.LineString{{2, 0}, {10, 0}, {10, 3}}, intersects: true, covers: true, contains: true},
+		"crossing line":   {g: geom.LineString{{5, -1}, {5, 1}}, intersects: true},
+		"polygon":         {g: geom.Polygon{{{1, -1}, {2, -1}, {2, 1}, {1, 1}}}, intersects: true},
+		"polygon outside": {g: geom.Polygon{{{1, 1}, {2, 1}, {2, 2}, {1, 2}}}},
+		"polygon around":  {g: geom.Polygon{{{-1, -1}, {11, -1}, {11, 11}, {-1, 11}}}, intersects: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			intersects, _ := pg.Intersects(tc.g)
+			covers, _ := pg.Covers(tc.g)
+			contains, _ := pg.Contains(tc.g)
+			if intersects != tc.intersects || covers != tc.covers || contains != tc.contains {
+				t.Errorf("intersects, covers, contains, expected %v %v %v got %v %v %v",
+					tc.intersects, tc.covers, tc.contains, intersects, covers, contains)
+			}
+		})
+	}
+}
+
+func TestPreparedGeometryInteriorPoint(t *testing.T) {
+	type tcase struct {
+		g        geom.Geometry
+		expected geom.Point
+	}
+	tests := map[string]tcase{
+		"polygon": {
+			g:        geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			expected: geom.Point{5, 5},
+		},
+		"polygon with hole": {
+			g: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{2, 2}, {2, 8}, {4, 8}, {4, 2}},
+			},
+			expected: geom.Point{7, 5},
+		},
+		"line":  {g: geom.LineString{{0, 0}, {2, 2}, {4, 0}}, expected: geom.Point{2, 2}},
+		"point": {g: geom.MultiPoint{{3, 4}, {5, 6}}, expected: geom.Point{3, 4}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pg, err := Prepare(tc.g)
+			if err != nil {
+				t.Fatalf("prepare, expected nil got %v", err)
+			}
+			if got := pg.InteriorPoint(); got != tc.expected {
+				t.Errorf("interior point, expected %v got %v", tc.expected, got)
+			}
+		})
+	}
+
+	if _, err := Prepare(geom.Extent{0, 0, 1, 1}); err == nil {
+		t.Errorf("extent, expected error got nil")
+	}
+}
+
+func TestPrepareEmpty(t *testing.T) {
+	type tcase struct {
+		g geom.Geometry
+		// extent is the extent of what is left, nil if nothing is
+		extent *geom.Extent
+	}
+	nan := math.NaN()
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			pg, err := Prepare(tc.g)
+			if err != nil {
+				t.Fatalf("prepare, expected nil got %v", err)
+			}
+			ext := pg.Extent()
+			if (ext == nil) != (tc.extent == nil) || (ext != nil && *ext != *tc.extent) {
+				t.Errorf("extent, expected %v got %v", tc.extent, ext)
+			}
+			intersects, err := pg.Intersects(geom.Point{1, 1})
+			if err != nil {
+				t.Fatalf("intersects, expected nil got %v", err)
+			}
+			if intersects != (tc.extent != nil) {
+				t.Errorf("intersects, expected %v got %v", tc.extent != nil, intersects)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty point": {
+			g: geom.Point{nan, nan},
+		},
+		"multipoint with empty point": {
+			g:      geom.MultiPoint{{nan, nan}, {1, 1}},
+			extent: &geom.Extent{1, 1, 1, 1},
+		},
+		"collection with empty point": {
+			g:      geom.Collection{geom.Point{nan, nan}, geom.LineString{{0, 0}, {2, 2}}},
+			extent: &geom.Extent{0, 0, 2, 2},
+		},
+		"line with infinite vertex": {
+			g:      geom.LineString{{0, 0}, {math.Inf(1), 0}, {2, 2}},
+			extent: &geom.Extent{0, 0, 2, 2},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,280 @@
+package planar
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// Relate returns the DE-9IM intersection matrix of the geometries, as a
+// string of nine characters. The characters are the dimension of the
+// intersection of the interior, boundary and exterior of a, in that order,
+// with the interior, boundary and exterior of b: 'F' if they do not
+// intersect, or '0', '1' or '2' for points, lines or areas. For example two
+// overlapping polygons are "212101212".
+//
+// Points, lines, polygons, their Multi forms and collections of them are
+// supported; z and m values are ignored. The boundary of a line is its two
+// ends, unless it is closed; the boundary of a polygon is its rings. The
+// intersections are found from the vertices of the geometries, the points
+// where their edges touch, and points just either side of the edges of the
+// polygons; points are within a small tolerance, relative to the size of
+// the geometries, of the edges they are on. Points with NaN or infinite coordinates, such as the empty
+// point, are left out, and an empty geometry only has an exterior.
+func Relate(a, b geom.Geometry) (string, error) {
+	im, err := relate(a, b)
+	if err != nil {
+		return "", err
+	}
+	return im.String(), nil
+}
+
+// RelateMatches reports whether the intersection matrix matches the
+// pattern. Each character of the pattern matches the same character of
+// the matrix, or is 'T' to match any dimension, or '*' to match anything.
+func RelateMatches(matrix, pattern string) bool {
+	if len(matrix) != 9 || len(pattern) != 9 {
+		return false
+	}
+	for i := 0; i < 9; i++ {
+		switch p, m := pattern[i], matrix[i]; p {
+		case '*':
+		case 'T', 't':
+			if m == 'F' {
+				return false
+			}
+		case 'f':
+			if m != 'F' {
+				return false
+			}
+		default:
+			if m != p {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Disjoint reports whether the geometries have no point in common.
+func Disjoint(a, b geom.Geometry) (bool, error) {
+	return relateMatches(a, b, "FF*FF****")
+}
+
+// Touches reports whether the geometries have a point in common, but their
+// interiors do not.
+func Touches(a, b geom.Geometry) (bool, error) {
+	return relateMatches(a, b, "FT*******", "F**T*****", "F***T****")
+}
+
+// Contains reports whether no point of b is outside a, and the interiors of
+// the geometries have a point in common.
+func Contains(a, b geom.Geometry) (bool, error) {
+	return relateMatches(a, b, "T*****FF*")
+}
+
+// Within reports whether a is contained by b.
+func Within(a, b geom.Geometry) (bool, error) {
+	return relateMatches(a, b, "T*F**F***")
+}
+
+// Crosses reports whether the interiors of the geometries have some, but
+// not all, points in common, and the intersection has a lower dimension
+// than the higher dimension geometry. Points can not cross points, nor
+// polygons polygons.
+func Crosses(a, b geom.Geometry) (bool, error) {
+	im, err := relate(a, b)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case im.dimA < im.dimB:
+		return RelateMatches(im.String(), "T*T******"), nil
+	case im.dimA > im.dimB:
+		return RelateMatches(im.String(), "T*****T**"), nil
+	case im.dimA == 1:
+		return RelateMatches(im.String(), "0********"), nil
+	}
+	return false, nil
+}
+
+// Overlaps reports whether the geometries have the same dimension, and
+// their interiors have some, but not all, points in common, with the
+// intersection having that dimension too.
+func Overlaps(a, b geom.Geometry) (bool, error) {
+	im, err := relate(a, b)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case im.dimA != im.dimB || im.dimA < 0:
+		return false, nil
+	case im.dimA == 1:
+		return RelateMatches(im.String(), "1*T***T**"), nil
+	}
+	return RelateMatches(im.String(), "T*T***T**"), nil
+}
+
+// relateMatches reports whether the intersection matrix of the geometries
+// matches any of the patterns
+func relateMatches(a, b geom.Geometry, patterns ...string) (bool, error) {
+	im, err := Relate(a, b)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range patterns {
+		if RelateMatches(im, p) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// intersectionMatrix is the dimension of each intersection, -1 for none,
+// indexed by the location in each geometry, interior first
+type intersectionMatrix struct {
+	dims       [3][3]int
+	dimA, dimB int
+}
+
+func (im *intersectionMatrix) String() string {
+	var s [9]byte
+	for i := range im.dims {
+		for j, d := range im.dims[i] {
+			s[3*i+j] = "F012"[d+1]
+		}
+	}
+	return string(s[:])
+}
+
+// set raises the dimension of the intersection at the locations to dim
+func (im *intersectionMatrix) set(locA, locB location, dim int) {
+	// the locations are in the reverse order of the matrix
+	i, j := int(interior-locA), int(interior-locB)
+	if dim > im.dims[i][j] {
+		im.dims[i][j] = dim
+	}
+}
+
+func relate(a, b geom.Geometry) (*intersectionMatrix, error) {
+	var pa, pb geomParts
+	if err := pa.add(a); err != nil {
+		return nil, err
+	}
+	if err := pb.add(b); err != nil {
+		return nil, err
+	}
+
+	if pa.dim() < 0 || pb.dim() < 0 {
+		return emptyRelate(pa, pb), nil
+	}
+
+	ext := pointsExtent(append(pa.vertices(), pb.vertices()...))
+	span := math.Max(ext[2]-ext[0], ext[3]-ext[1])
+	if span == 0 || math.IsInf(span, 0) {
+		span = 1
+	}
+	tol := nodingTolerance * span
+	qa, qb := newPrepared(pa, tol), newPrepared(pb, tol)
+
+	im := &intersectionMatrix{dimA: pa.dim(), dimB: pb.dim()}
+	for i := range im.dims {
+		for j := range im.dims[i] {
+			im.dims[i][j] = -1
+		}
+	}
+	// the exteriors of bounded geometries always share most of the plane
+	im.set(exterior, exterior, 2)
+
+	sampleRelate(qa, qb, func(locA, locB location, dim int) { im.set(locA, locB, dim) })
+	sampleRelate(qb, qa, func(locB, locA location, dim int) { im.set(locA, locB, dim) })
+	return im, nil
+}
+
+// emptyRelate returns the matrix of geometries of which one or both are
+// empty, "FF*FF****" with the dimensions of the interior and boundary of
+// the other geometry meeting the exterior of the empty one
+func emptyRelate(pa, pb geomParts) *intersectionMatrix {
+	im := &intersectionMatrix{dimA: pa.dim(), dimB: pb.dim()}
+	for i := range im.dims {
+		for j := range im.dims[i] {
+			im.dims[i][j] = -1
+		}
+	}
+	im.set(exterior, exterior, 2)
+	im.set(interior, exterior, pa.dim())
+	im.set(boundary, exterior, pa.boundaryDim())
+	im.set(exterior, interior, pb.dim())
+	im.set(exterior, boundary, pb.boundaryDim())
+	return im
+}
+
+// sampleRelate calls set with the locations in from and other of points of
+// from, and the dimension of the intersection there: the points and
+// vertices of from, and the points its edges touch the edges of other; the
+// pieces of its edges between them; and the areas just either side of the
+// edges of its polygons
+func sampleRelate(from, other *PreparedGeometry, set func(locFrom, locOther location, dim int)) {
+	point := func(pt [2]float64) {
+		set(from.locate(pt), other.locate(pt), 0)
+	}
+	off := 100 * from.tol
+	edge := func(a, b [2]float64, ring bool) {
+		split := other.splitPoints(a, b)
+		for i, pt := range split {
+			point(pt)
+			if i == 0 || split[i-1] == pt {
+				continue
+			}
+			mid := [2]float64{(split[i-1][0] + pt[0]) / 2, (split[i-1][1] + pt[1]) / 2}
+			set(from.locate(mid), other.locate(mid), 1)
+			if !ring {
+				continue
+			}
+			n := leftNormal(split[i-1], pt)
+			for _, d := range []float64{off, -off} {
+				side := offsetPoint(mid, n, d)
+				locFrom, areaFrom := from.areaLocate(side)
+				locOther, areaOther := other.areaLocate(side)
+				if areaFrom && areaOther {
+					set(locFrom, locOther, 2)
+				}
+			}
+		}
+	}
+
+	for _, pt := range from.parts.points {
+		point(pt)
+	}
+	for _, ln := range from.parts.lines {
+		for i := 0; i < len(ln)-1; i++ {
+			edge(ln[i], ln[i+1], false)
+		}
+	}
+	for _, ply := range from.parts.polys {
+		for _, ring := range ply {
+			for i := range ring {
+				edge(ring[i], ring[(i+1)%len(ring)], true)
+			}
+		}
+	}
+}
+
+// areaLocate returns where the point is relative to the geometry, and
+// whether the geometry is an area around the point: it is outside the
+// geometry or in the interior of its polygons
+func (pg *PreparedGeometry) areaLocate(pt [2]float64) (location, bool) {
+	loc := pg.locate(pt)
+	switch loc {
+	case exterior:
+		return loc, true
+	case boundary:
+		return loc, false
+	}
+	for _, pp := range pg.polys {
+		if pp.Contains(pt) {
+			return loc, true
+		}
+	}
+	return loc, false
+}
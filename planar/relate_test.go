@@ -0,0 +1,245 @@
+package planar
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestRelate(t *testing.T) {
+	type tcase struct {
+		a, b     geom.Geometry
+		expected string
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			im, err := Relate(tc.a, tc.b)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if im != tc.expected {
+				t.Errorf("matrix, expected %v got %v", tc.expected, im)
+			}
+			if err != nil {
+				return
+			}
+			// the matrix the other way round is the transpose
+			im, _ = Relate(tc.b, tc.a)
+			exp := []byte(tc.expected)
+			exp[1], exp[3] = exp[3], exp[1]
+			exp[2], exp[6] = exp[6], exp[2]
+			exp[5], exp[7] = exp[7], exp[5]
+			if im != string(exp) {
+				t.Errorf("transposed matrix, expected %s got %v", exp, im)
+			}
+		}
+	}
+
+	square := geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}
+	nan := math.NaN()
+
+	tests := map[string]tcase{
+		"overlapping polygons": {
+			a:        square,
+			b:        geom.Polygon{{{5, 5}, {15, 5}, {15, 15}, {5, 15}}},
+			expected: "212101212",
+		},
+		"polygon inside": {
+			a:        square,
+			b:        geom.Polygon{{{2, 2}, {8, 2}, {8, 8}, {2, 8}}},
+			expected: "212FF1FF2",
+		},
+		"polygon in hole": {
+			a:        geom.Polygon{square[0], {{2, 2}, {2, 8}, {8, 8}, {8, 2}}},
+			b:        geom.Polygon{{{3, 3}, {7, 3}, {7, 7}, {3, 7}}},
+			expected: "FF2FF1212",
+		},
+		"polygons sharing an edge": {
+			a:        square,
+			b:        geom.Polygon{{{10, 0}, {20, 0}, {20, 10}, {10, 10}}},
+			expected: "FF2F11212",
+		},
+		"disjoint polygons": {
+			a:        square,
+			b:        geom.Polygon{{{20, 0}, {30, 0}, {30, 10}, {20, 10}}},
+			expected: "FF2FF1212",
+		},
+		"equal polygons": {
+			a:        square,
+			b:        geom.Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}},
+			expected: "2FFF1FFF2",
+		},
+		"line crossing polygon": {
+			a:        geom.LineString{{-5, 5}, {15, 5}},
+			b:        square,
+			expected: "101FF0212",
+		},
+		"line inside polygon": {
+			a:        geom.LineString{{2, 2}, {8, 8}},
+			b:        square,
+			expected: "1FF0FF212",
+		},
+		"line on boundary": {
+			a:        geom.LineString{{0, 0}, {10, 0}},
+			b:        square,
+			expected: "F1FF0F212",
+		},
+		"crossing lines": {
+			a:        geom.LineString{{0, 0}, {10, 10}},
+			b:        geom.LineString{{0, 10}, {10, 0}},
+			expected: "0F1FF0102",
+		},
+		"lines touching at ends": {
+			a:        geom.LineString{{0, 0}, {5, 5}},
+			b:        geom.LineString{{5, 5}, {10, 0}},
+			expected: "FF1F00102",
+		},
+		"overlapping lines": {
+			a:        geom.LineString{{0, 0}, {10, 0}},
+			b:        geom.LineString{{5, 0}, {15, 0}},
+			expected: "1010F0102",
+		},
+		"point in polygon": {
+			a:        geom.Point{5, 5},
+			b:        square,
+			expected: "0FFFFF212",
+		},
+		"point on polygon boundary": {
+			a:        geom.Point{0, 5},
+			b:        square,
+			expected: "F0FFFF212",
+		},
+		"equal points": {
+			a:        geom.Point{1, 2},
+			b:        geom.MultiPoint{{1, 2}},
+			expected: "0FFFFFFF2",
+		},
+		"empty": {
+			a:        geom.LineString{},
+			b:        geom.Point{1, 2},
+			expected: "FFFFFF0F2",
+		},
+		"empty point and polygon": {
+			a:        geom.Point{nan, nan},
+			b:        square,
+			expected: "FFFFFF212",
+		},
+		"empty point and line": {
+			a:        geom.Point{nan, nan},
+			b:        geom.LineString{{0, 0}, {1, 1}},
+			expected: "FFFFFF102",
+		},
+		"empty point and ring": {
+			a:        geom.Point{nan, nan},
+			b:        geom.LineString{{0, 0}, {1, 0}, {1, 1}, {0, 0}},
+			expected: "FFFFFF1F2",
+		},
+		"multipoint of empty points and point": {
+			a:        geom.MultiPoint{{nan, nan}, {nan, nan}},
+			b:        geom.Point{1, 2},
+			expected: "FFFFFF0F2",
+		},
+		"both empty": {
+			a:        geom.Point{nan, nan},
+			b:        geom.Collection{},
+			expected: "FFFFFFFF2",
+		},
+		"point z": {
+			a:        geom.PointZ{1, 2, 3},
+			b:        geom.Point{1, 2},
+			expected: "0FFFFFFF2",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestRelatePredicates(t *testing.T) {
+	type tcase struct {
+		a, b                                                   geom.Geometry
+		disjoint, touches, contains, within, crosses, overlaps bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			preds := []struct {
+				name     string
+				fn       func(a, b geom.Geometry) (bool, error)
+				expected bool
+			}{
+				{"disjoint", Disjoint, tc.disjoint},
+				{"touches", Touches, tc.touches},
+				{"contains", Contains, tc.contains},
+				{"within", Within, tc.within},
+				{"crosses", Crosses, tc.crosses},
+				{"overlaps", Overlaps, tc.overlaps},
+			}
+			for _, p := range preds {
+				got, err := p.fn(tc.a, tc.b)
+				if err != nil {
+					t.Fatalf("%v error, expected nil got %v", p.name, err)
+				}
+				if got != p.expected {
+					t.Errorf("%v, expected %v got %v", p.name, p.expected, got)
+				}
+			}
+		}
+	}
+
+	square := geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}
+
+	tests := map[string]tcase{
+		"disjoint": {
+			a:        square,
+			b:        geom.Point{20, 20},
+			disjoint: true,
+		},
+		"touching": {
+			a:       square,
+			b:       geom.LineString{{10, 5}, {20, 5}},
+			touches: true,
+		},
+		"contains": {
+			a:        square,
+			b:        geom.Point{5, 5},
+			contains: true,
+		},
+		"within": {
+			a:      geom.LineString{{2, 2}, {8, 8}},
+			b:      square,
+			within: true,
+		},
+		"crosses": {
+			a:       geom.LineString{{-5, 5}, {15, 5}},
+			b:       square,
+			crosses: true,
+		},
+		"overlaps": {
+			a:        square,
+			b:        geom.Polygon{{{5, 5}, {15, 5}, {15, 15}, {5, 15}}},
+			overlaps: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	matches := map[string]bool{
+		"T*F**F***": true,
+		"T*F**FFF*": false,
+		"1*F**F***": true,
+		"short":     false,
+	}
+	for pattern, exp := range matches {
+		if got := RelateMatches("1FF0FF212", pattern); got != exp {
+			t.Errorf("matches %v, expected %v got %v", pattern, exp, got)
+		}
+	}
+}
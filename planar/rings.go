@@ -0,0 +1,192 @@
+package planar
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/noding"
+	"github.com/go-spatial/geom/winding"
+)
+
+// coverFunc reports whether the points just off pt, in the direction dir,
+// are in an area. Points within tol of the boundary of the area are on it.
+type coverFunc func(pt, dir [2]float64, tol float64) bool
+
+// buildPolygons returns the polygons of the area described by covers, whose
+// boundary is made up of parts of the segments. The points of the polygons
+// are rounded to the grid of pm, unless it is floating, and the rings are
+// wound as the Options of the context say
+func buildPolygons(ctx context.Context, segs []geom.Line, pm geom.PrecisionModel, covers coverFunc) (geom.MultiPolygon, error) {
+	if len(segs) == 0 {
+		return nil, nil
+	}
+	snp := newGridSnapper(segs, pm)
+	lines := make([]geom.LineString, len(segs))
+	for i, s := range segs {
+		lines[i] = geom.LineString{s[0], s[1]}
+	}
+	noded, err := noding.Segments(ctx, lines, snp.pm)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := boundaryEdges(ctx, snp, noded, covers)
+	if err != nil {
+		return nil, err
+	}
+	rngs, err := linkRings(ctx, edges)
+	if err != nil {
+		return nil, err
+	}
+	mply := assemblePolygons(snp, rngs)
+	if len(mply) == 0 {
+		return nil, nil
+	}
+	return FromContext(ctx).OrientMultiPolygon(mply), nil
+}
+
+// boundaryEdges returns the edges that have the area on only one side,
+// directed so the area is on the left
+func boundaryEdges(ctx context.Context, snp gridSnapper, edges []geom.Line, covers coverFunc) ([]geom.Line, error) {
+	var bnd []geom.Line
+	for _, e := range edges {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// look at each side of the middle of the edge
+		n := leftNormal(e[0], e[1])
+		mid := [2]float64{(e[0][0] + e[1][0]) / 2, (e[0][1] + e[1][1]) / 2}
+		left := covers(mid, n, snp.tolerance())
+		right := covers(mid, [2]float64{-n[0], -n[1]}, snp.tolerance())
+		switch {
+		case left && !right:
+			bnd = append(bnd, e)
+		case right && !left:
+			bnd = append(bnd, geom.Line{e[1], e[0]})
+		}
+	}
+	return bnd, nil
+}
+
+// linkRings links the directed edges into rings, keeping the left side of the
+// edges inside the ring
+func linkRings(ctx context.Context, edges []geom.Line) ([][][2]float64, error) {
+	out := make(map[[2]float64][]int)
+	for i, e := range edges {
+		out[e[0]] = append(out[e[0]], i)
+	}
+	angle := func(e geom.Line) float64 { return math.Atan2(e[1][1]-e[0][1], e[1][0]-e[0][0]) }
+
+	// next returns the edge leaving the end of edge i that is the first
+	// clockwise from the edge going back
+	next := func(i int) int {
+		e := edges[i]
+		back := angle(geom.Line{e[1], e[0]})
+		best, bestDelta := -1, 0.0
+		for _, j := range out[e[1]] {
+			delta := back - angle(edges[j])
+			for delta <= 0 {
+				delta += 2 * math.Pi
+			}
+			if best == -1 || delta < bestDelta {
+				best, bestDelta = j, delta
+			}
+		}
+		return best
+	}
+
+	var rngs [][][2]float64
+	used := make([]bool, len(edges))
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+		var ring [][2]float64
+		for i := start; ; {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			used[i] = true
+			ring = append(ring, edges[i][0])
+			i = next(i)
+			if i == -1 || i == start || used[i] {
+				break
+			}
+		}
+		ring = removeCollinear(ring)
+		if len(ring) >= 3 {
+			rngs = append(rngs, ring)
+		}
+	}
+	return rngs, nil
+}
+
+// removeCollinear removes the points of the ring that are on the line
+// between their neighbors
+func removeCollinear(ring [][2]float64) [][2]float64 {
+	for changed := true; changed && len(ring) >= 3; {
+		changed = false
+		ret := ring[:0:0]
+		n := len(ring)
+		for i := range ring {
+			prev, pt, nxt := ring[(i+n-1)%n], ring[i], ring[(i+1)%n]
+			a, c := vsub(pt, prev), vsub(nxt, pt)
+			if math.Abs(vcross(a, c)) <= nodingTolerance*math.Sqrt(vdot(a, a)*vdot(c, c)) && vdot(a, c) > 0 {
+				changed = true
+				continue
+			}
+			ret = append(ret, pt)
+		}
+		ring = ret
+	}
+	return ring
+}
+
+// assemblePolygons sorts the rings into polygons. Counter-clockwise rings are
+// the outer rings, clockwise rings are holes and are added to the smallest
+// outer ring that contains them.
+func assemblePolygons(snp gridSnapper, rngs [][][2]float64) geom.MultiPolygon {
+	type shell struct {
+		ply  polygonPiece
+		area float64
+	}
+	var (
+		shells []shell
+		holes  [][][2]float64
+	)
+	for _, r := range rngs {
+		if a := winding.SignedArea(r...); a > 0 {
+			shells = append(shells, shell{
+				ply:  polygonPiece{rings: [][][2]float64{r}, ext: geom.NewExtent(r...)},
+				area: a,
+			})
+		} else if a < 0 {
+			holes = append(holes, r)
+		}
+	}
+	sort.SliceStable(shells, func(i, j int) bool { return shells[i].area > shells[j].area })
+
+	mply := make(geom.MultiPolygon, len(shells))
+	for i := range shells {
+		mply[i] = [][][2]float64{shells[i].ply.rings[0]}
+	}
+	for _, h := range holes {
+		// the area is to the left of the first edge of the hole
+		mid := [2]float64{(h[0][0] + h[1][0]) / 2, (h[0][1] + h[1][1]) / 2}
+		n := leftNormal(h[0], h[1])
+
+		// the shells are sorted largest first, so the last one found is
+		// the smallest
+		idx := -1
+		for i := range shells {
+			if coveredBy([]piece{shells[i].ply}, mid, n, snp.tolerance()) {
+				idx = i
+			}
+		}
+		if idx != -1 {
+			mply[idx] = append(mply[idx], h)
+		}
+	}
+	return mply
+}
@@ -0,0 +1,173 @@
+package planar
+
+import (
+	"sort"
+
+	"github.com/go-spatial/geom"
+)
+
+// IntersectionKind is how two segments intersect.
+type IntersectionKind uint8
+
+const (
+	// NoIntersection is two segments that do not meet
+	NoIntersection IntersectionKind = iota
+	// ProperIntersection is two segments that cross at a point that is not
+	// an end point of either
+	ProperIntersection
+	// TouchIntersection is two segments that meet at one point that is an
+	// end point of one or both of them
+	TouchIntersection
+	// OverlapIntersection is two colinear segments that share a sub segment
+	OverlapIntersection
+)
+
+func (k IntersectionKind) String() string {
+	switch k {
+	case NoIntersection:
+		return "none"
+	case ProperIntersection:
+		return "proper"
+	case TouchIntersection:
+		return "touch"
+	case OverlapIntersection:
+		return "overlap"
+	default:
+		return "unknown"
+	}
+}
+
+// SegmentIntersection is where two segments intersect.
+type SegmentIntersection struct {
+	Kind IntersectionKind
+	// Segment is the shared sub segment of an overlap, in the direction of
+	// the first segment. For the other kinds both of its ends are the
+	// intersection point.
+	Segment geom.Line
+	// T are the positions of the ends of Segment along the first segment,
+	// 0 at its start and 1 at its end
+	T [2]float64
+	// U are the positions of the ends of Segment along the second segment
+	U [2]float64
+}
+
+// Point returns the intersection point, or the start of the overlap.
+func (si SegmentIntersection) Point() [2]float64 { return si.Segment[0] }
+
+// IntersectSegments returns where the two segments intersect. The end
+// points of the segments that are on the other segment are given as they
+// are, rather than calculated, so the points of touches and overlaps are
+// exact. A segment with both of its ends the same is treated as a point.
+func IntersectSegments(l1, l2 geom.Line) SegmentIntersection {
+	p1, p2 := l1[0], l1[1]
+	q1, q2 := l2[0], l2[1]
+	d1 := vsub(p2, p1)
+	d2 := vsub(q2, q1)
+	point1, point2 := p1 == p2, q1 == q2
+
+	switch {
+	case point1 && point2:
+		if p1 != q1 {
+			return SegmentIntersection{}
+		}
+		return touchAt(p1, 0, 0)
+	case point1:
+		if !onSegment(q1, q2, p1, 0) {
+			return SegmentIntersection{}
+		}
+		return touchAt(p1, 0, param(p1, q1, d2))
+	case point2:
+		if !onSegment(p1, p2, q1, 0) {
+			return SegmentIntersection{}
+		}
+		return touchAt(q1, param(q1, p1, d1), 0)
+	}
+
+	// the sides of each segment the ends of the other are on
+	o1 := vcross(d1, vsub(q1, p1))
+	o2 := vcross(d1, vsub(q2, p1))
+	o3 := vcross(d2, vsub(p1, q1))
+	o4 := vcross(d2, vsub(p2, q1))
+
+	if o1 == 0 && o2 == 0 {
+		return overlap(p1, p2, q1, q2, d1, d2)
+	}
+	if sameSide(o1, o2) || sameSide(o3, o4) {
+		return SegmentIntersection{}
+	}
+
+	switch {
+	case o1 == 0:
+		return touchAt(q1, param(q1, p1, d1), 0)
+	case o2 == 0:
+		return touchAt(q2, param(q2, p1, d1), 1)
+	case o3 == 0:
+		return touchAt(p1, 0, param(p1, q1, d2))
+	case o4 == 0:
+		return touchAt(p2, 1, param(p2, q1, d2))
+	}
+
+	denom := vcross(d1, d2)
+	r := vsub(q1, p1)
+	t := vcross(r, d2) / denom
+	u := vcross(r, d1) / denom
+	pt := [2]float64{p1[0] + t*d1[0], p1[1] + t*d1[1]}
+	return SegmentIntersection{
+		Kind:    ProperIntersection,
+		Segment: geom.Line{pt, pt},
+		T:       [2]float64{t, t},
+		U:       [2]float64{u, u},
+	}
+}
+
+func sameSide(a, b float64) bool { return (a > 0 && b > 0) || (a < 0 && b < 0) }
+
+// param returns the position of pt along the segment starting at start in
+// the direction d.
+func param(pt, start, d [2]float64) float64 {
+	return vdot(vsub(pt, start), d) / vdot(d, d)
+}
+
+func touchAt(pt [2]float64, t, u float64) SegmentIntersection {
+	return SegmentIntersection{
+		Kind:    TouchIntersection,
+		Segment: geom.Line{pt, pt},
+		T:       [2]float64{t, t},
+		U:       [2]float64{u, u},
+	}
+}
+
+// overlap returns the intersection of the colinear segments p1 p2 and q1
+// q2, which is bounded by end points of the segments.
+func overlap(p1, p2, q1, q2, d1, d2 [2]float64) SegmentIntersection {
+	type end struct {
+		pt   [2]float64
+		t, u float64
+	}
+	ends := []end{
+		{p1, 0, param(p1, q1, d2)},
+		{p2, 1, param(p2, q1, d2)},
+		{q1, param(q1, p1, d1), 0},
+		{q2, param(q2, p1, d1), 1},
+	}
+	var on []end
+	for _, e := range ends {
+		if e.t >= 0 && e.t <= 1 && e.u >= 0 && e.u <= 1 {
+			on = append(on, e)
+		}
+	}
+	if len(on) == 0 {
+		return SegmentIntersection{}
+	}
+	sort.SliceStable(on, func(i, j int) bool { return on[i].t < on[j].t })
+	first, last := on[0], on[len(on)-1]
+	if first.pt == last.pt {
+		return touchAt(first.pt, first.t, first.u)
+	}
+	return SegmentIntersection{
+		Kind:    OverlapIntersection,
+		Segment: geom.Line{first.pt, last.pt},
+		T:       [2]float64{first.t, last.t},
+		U:       [2]float64{first.u, last.u},
+	}
+}
@@ -82,6 +82,9 @@ func Simplify(ctx context.Context, simplifer Simplifer, geometry geom.Geometry)
 		return geom.LineString(ls), nil
 
 	default: // Points, MutliPoints or anything else.
+		if geo, ok, err := simplifyZM(ctx, simplifer, geometry); ok {
+			return geo, err
+		}
 		return geometry, nil
 
 	}
@@ -0,0 +1,335 @@
+package simplify
+
+import (
+	"context"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+)
+
+// Topology simplifies a set of geometries so that the boundaries they
+// share are simplified the same way, and adjacent polygons stay adjacent
+// without gaps or overlaps appearing between them.
+//
+// The boundaries are cut into sections at the nodes: the vertices where
+// three or more edges meet and the ends of lines. Each section is simplified
+// once with the Simplifier, keeping its end points, and used by every ring
+// or line it is part of. For the boundaries to be found the geometries must
+// share their vertices where they touch, as the polygons of a coverage do.
+type Topology struct {
+
+	// Simplifier is used to simplify each section of the boundaries. It
+	// must keep the end points of the lines it is given, as
+	// DouglasPeucker and VisvalingamWhyatt do.
+	Simplifier planar.Simplifer
+}
+
+// sectionKey identifies a section by its first edge, last point and
+// number of points, in the direction it is simplified in
+type sectionKey struct {
+	first, second, last [2]float64
+	n                   int
+}
+
+// topology holds the nodes of the geometries being simplified and the
+// sections simplified so far
+type topology struct {
+	simplifier planar.Simplifer
+	nodes      map[[2]float64]bool
+	sections   map[sectionKey][][2]float64
+}
+
+// Simplify returns the simplified geometries. Polygons, MultiPolygons,
+// LineStrings, MultiLineStrings and collections of them are simplified,
+// other geometries are returned as is. Holes that collapse are removed, as
+// are polygons whose outer ring collapses. Rings are returned unclosed and
+// may start at a different point. If the Simplifier is nil the geometries
+// are returned unchanged.
+func (t Topology) Simplify(ctx context.Context, geoms []geom.Geometry) ([]geom.Geometry, error) {
+	if t.Simplifier == nil {
+		return geoms, nil
+	}
+
+	tp := topology{
+		simplifier: t.Simplifier,
+		nodes:      make(map[[2]float64]bool),
+		sections:   make(map[sectionKey][][2]float64),
+	}
+
+	// count the distinct edges at each vertex
+	edges := make(map[[2][2]float64]bool)
+	degree := make(map[[2]float64]int)
+	addEdge := func(a, b [2]float64) {
+		if a == b {
+			return
+		}
+		if less(b, a) {
+			a, b = b, a
+		}
+		if edges[[2][2]float64{a, b}] {
+			return
+		}
+		edges[[2][2]float64{a, b}] = true
+		degree[a]++
+		degree[b]++
+	}
+	for _, g := range geoms {
+		walkLines(g, func(pts [][2]float64, closed bool) {
+			if len(pts) == 0 {
+				return
+			}
+			for i := 1; i < len(pts); i++ {
+				addEdge(pts[i-1], pts[i])
+			}
+			if closed {
+				addEdge(pts[len(pts)-1], pts[0])
+				return
+			}
+			tp.nodes[pts[0]] = true
+			tp.nodes[pts[len(pts)-1]] = true
+		})
+	}
+	for pt, d := range degree {
+		if d > 2 {
+			tp.nodes[pt] = true
+		}
+	}
+
+	ret := make([]geom.Geometry, len(geoms))
+	for i, g := range geoms {
+		var err error
+		if ret[i], err = tp.simplify(ctx, g); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// walkLines calls fn with the rings and lines of the geometry
+func walkLines(g geom.Geometry, fn func(pts [][2]float64, closed bool)) {
+	switch gg := g.(type) {
+	case geom.Collectioner:
+		for _, c := range gg.Geometries() {
+			walkLines(c, fn)
+		}
+	case geom.MultiPolygoner:
+		for _, ply := range gg.Polygons() {
+			for _, r := range ply {
+				fn(unclosed(r), true)
+			}
+		}
+	case geom.Polygoner:
+		for _, r := range gg.LinearRings() {
+			fn(unclosed(r), true)
+		}
+	case geom.MultiLineStringer:
+		for _, ls := range gg.LineStrings() {
+			fn(ls, false)
+		}
+	case geom.LineStringer:
+		fn(gg.Vertices(), false)
+	}
+}
+
+// unclosed returns the ring without its last point if it repeats the first
+func unclosed(ring [][2]float64) [][2]float64 {
+	if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+		return ring[:len(ring)-1]
+	}
+	return ring
+}
+
+func (tp *topology) simplify(ctx context.Context, g geom.Geometry) (geom.Geometry, error) {
+	switch gg := g.(type) {
+
+	case geom.Collectioner:
+		geos := gg.Geometries()
+		coll := make(geom.Collection, len(geos))
+		for i := range geos {
+			geo, err := tp.simplify(ctx, geos[i])
+			if err != nil {
+				return nil, err
+			}
+			coll[i] = geo
+		}
+		return coll, nil
+
+	case geom.MultiPolygoner:
+		var mply geom.MultiPolygon
+		for _, ply := range gg.Polygons() {
+			sply, err := tp.polygon(ctx, ply)
+			if err != nil {
+				return nil, err
+			}
+			if len(sply) > 0 {
+				mply = append(mply, sply)
+			}
+		}
+		return mply, nil
+
+	case geom.Polygoner:
+		return tp.polygon(ctx, gg.LinearRings())
+
+	case geom.MultiLineStringer:
+		lss := gg.LineStrings()
+		mls := make(geom.MultiLineString, len(lss))
+		for i := range lss {
+			ls, err := tp.line(ctx, lss[i])
+			if err != nil {
+				return nil, err
+			}
+			mls[i] = ls
+		}
+		return mls, nil
+
+	case geom.LineStringer:
+		ls, err := tp.line(ctx, gg.Vertices())
+		if err != nil {
+			return nil, err
+		}
+		return geom.LineString(ls), nil
+
+	default:
+		return g, nil
+	}
+}
+
+// polygon simplifies the rings of the polygon, it is empty if the outer
+// ring collapses
+func (tp *topology) polygon(ctx context.Context, ply [][][2]float64) (geom.Polygon, error) {
+	ret := make(geom.Polygon, 0, len(ply))
+	for i, r := range ply {
+		sr, err := tp.ring(ctx, unclosed(r))
+		if err != nil {
+			return nil, err
+		}
+		if len(sr) < 3 {
+			if i == 0 {
+				return geom.Polygon{}, nil
+			}
+			continue
+		}
+		ret = append(ret, sr)
+	}
+	return ret, nil
+}
+
+// ring simplifies the unclosed ring, starting from its first node. Rings
+// without nodes start from their smallest point, so rings that are shared
+// entirely are simplified the same way.
+func (tp *topology) ring(ctx context.Context, ring [][2]float64) ([][2]float64, error) {
+	if len(ring) < 3 {
+		return ring, nil
+	}
+	start := -1
+	for i, pt := range ring {
+		if tp.nodes[pt] {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		start = 0
+		for i, pt := range ring {
+			if less(pt, ring[start]) {
+				start = i
+			}
+		}
+	}
+
+	pts := make([][2]float64, 0, len(ring)+1)
+	pts = append(pts, ring[start:]...)
+	pts = append(pts, ring[:start+1]...)
+	ret, err := tp.split(ctx, pts)
+	if err != nil {
+		return nil, err
+	}
+	return ret[:len(ret)-1], nil
+}
+
+// line simplifies the line, the ends of which are always nodes
+func (tp *topology) line(ctx context.Context, ln [][2]float64) ([][2]float64, error) {
+	if len(ln) <= 2 {
+		return append([][2]float64(nil), ln...), nil
+	}
+	return tp.split(ctx, ln)
+}
+
+// split cuts the line at its nodes and joins the simplified sections
+func (tp *topology) split(ctx context.Context, ln [][2]float64) ([][2]float64, error) {
+	ret := make([][2]float64, 0, len(ln))
+	start := 0
+	for i := 1; i < len(ln); i++ {
+		if i < len(ln)-1 && !tp.nodes[ln[i]] {
+			continue
+		}
+		sec, err := tp.section(ctx, ln[start:i+1])
+		if err != nil {
+			return nil, err
+		}
+		if len(ret) > 0 {
+			sec = sec[1:]
+		}
+		ret = append(ret, sec...)
+		start = i
+	}
+	return ret, nil
+}
+
+// section simplifies a section between two nodes. Sections are simplified
+// in the direction that starts with the smaller end, so a section shared by
+// two rings running in opposite directions comes out the same.
+func (tp *topology) section(ctx context.Context, sec [][2]float64) ([][2]float64, error) {
+	n := len(sec)
+	if n <= 2 {
+		return append([][2]float64(nil), sec...), nil
+	}
+
+	reversed := less(sec[n-1], sec[0]) || (sec[n-1] == sec[0] && less(sec[n-2], sec[1]))
+	if reversed {
+		sec = reverse(sec)
+	}
+
+	var ret [][2]float64
+	if sec[0] == sec[n-1] {
+		// a loop would be simplified to a point, simplify its halves
+		mid := n / 2
+		first, err := tp.section(ctx, sec[:mid+1])
+		if err != nil {
+			return nil, err
+		}
+		second, err := tp.section(ctx, sec[mid:])
+		if err != nil {
+			return nil, err
+		}
+		ret = append(first, second[1:]...)
+	} else {
+		key := sectionKey{first: sec[0], second: sec[1], last: sec[n-1], n: n}
+		var ok bool
+		if ret, ok = tp.sections[key]; !ok {
+			var err error
+			if ret, err = tp.simplifier.Simplify(ctx, sec, false); err != nil {
+				return nil, err
+			}
+			tp.sections[key] = ret
+		}
+		ret = append([][2]float64(nil), ret...)
+	}
+
+	if reversed {
+		ret = reverse(ret)
+	}
+	return ret, nil
+}
+
+func less(a, b [2]float64) bool {
+	return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+}
+
+func reverse(pts [][2]float64) [][2]float64 {
+	ret := make([][2]float64, len(pts))
+	for i, pt := range pts {
+		ret[len(pts)-1-i] = pt
+	}
+	return ret
+}
@@ -0,0 +1,91 @@
+package simplify
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestTopology(t *testing.T) {
+	// the boundary shared by the left and right squares wiggles around x=10
+	left := geom.Polygon{{{0, 0}, {10, 0}, {10, 2}, {10.3, 4}, {9.8, 6}, {10.1, 8}, {10, 10}, {0, 10}}}
+	right := geom.Polygon{{{10, 0}, {20, 0}, {20, 10}, {10, 10}, {10.1, 8}, {9.8, 6}, {10.3, 4}, {10, 2}}}
+	// the hole of the frame is filled by the island, and neither has nodes
+	frame := geom.Polygon{
+		{{30, 0}, {40, 0}, {40, 10}, {30, 10}},
+		{{32, 2}, {32, 8}, {35, 8.2}, {38, 8}, {38, 2}},
+	}
+	island := geom.Polygon{{{32, 2}, {38, 2}, {38, 8}, {35, 8.2}, {32, 8}}}
+
+	type tcase struct {
+		simplifier Topology
+		geoms      []geom.Geometry
+		expected   []geom.Geometry
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := tc.simplifier.Simplify(context.Background(), tc.geoms)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(tc.expected, got) {
+				t.Errorf("geometries, expected\n%v\n\tgot\n%v", tc.expected, got)
+			}
+		}
+	}
+
+	dp := Topology{Simplifier: DouglasPeucker{Tolerance: 0.5}}
+	tests := map[string]tcase{
+		"nil simplifier": {
+			geoms:    []geom.Geometry{left, right},
+			expected: []geom.Geometry{left, right},
+		},
+		"shared boundary": {
+			simplifier: dp,
+			geoms:      []geom.Geometry{left, right},
+			expected: []geom.Geometry{
+				geom.Polygon{{{10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+				geom.Polygon{{{10, 0}, {20, 0}, {20, 10}, {10, 10}}},
+			},
+		},
+		"shared ring": {
+			simplifier: dp,
+			geoms:      []geom.Geometry{frame, island},
+			expected: []geom.Geometry{
+				geom.Polygon{
+					{{30, 0}, {40, 0}, {40, 10}, {30, 10}},
+					{{32, 2}, {32, 8}, {38, 8}, {38, 2}},
+				},
+				geom.Polygon{{{32, 2}, {38, 2}, {38, 8}, {32, 8}}},
+			},
+		},
+		"collapsed hole": {
+			simplifier: Topology{Simplifier: VisvalingamWhyatt{Tolerance: 1}},
+			geoms: []geom.Geometry{geom.MultiPolygon{
+				{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, {{4, 4}, {4, 5}, {5, 5}}},
+				{{{20, 0}, {20.5, 0}, {20.5, 0.5}}},
+			}},
+			expected: []geom.Geometry{geom.MultiPolygon{
+				{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			}},
+		},
+		"line ending on the boundary": {
+			simplifier: dp,
+			geoms: []geom.Geometry{
+				left,
+				geom.LineString{{10, 6}, {9.8, 6}, {5, 5.8}, {0, 6}},
+			},
+			expected: []geom.Geometry{
+				geom.Polygon{{{9.8, 6}, {10, 10}, {0, 10}, {0, 0}, {10, 0}}},
+				geom.LineString{{10, 6}, {9.8, 6}, {0, 6}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
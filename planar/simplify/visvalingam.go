@@ -0,0 +1,119 @@
+package simplify
+
+import (
+	"container/heap"
+	"context"
+	"math"
+)
+
+// VisvalingamWhyatt simplifies lines by repeatedly removing the point that
+// forms the smallest triangle with its neighbours, until every remaining
+// triangle has an area of at least Tolerance. The end points are always
+// kept.
+type VisvalingamWhyatt struct {
+
+	// Tolerance is the minimum area of the triangle a point forms with its
+	// neighbours for the point to be kept, a tolerance of zero does not
+	// eliminate any points.
+	Tolerance float64
+}
+
+// vwPoint is a point of the line being simplified, linked to its
+// neighbours that have not been removed
+type vwPoint struct {
+	idx        int
+	area       float64
+	prev, next *vwPoint
+	// pos is the position in the heap, or -1 if the point is not in it
+	pos int
+}
+
+// vwHeap orders the points by their area, smallest first, ties being
+// broken by the position in the line so the result is deterministic
+type vwHeap []*vwPoint
+
+func (h vwHeap) Len() int { return len(h) }
+func (h vwHeap) Less(i, j int) bool {
+	if h[i].area != h[j].area {
+		return h[i].area < h[j].area
+	}
+	return h[i].idx < h[j].idx
+}
+func (h vwHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].pos, h[j].pos = i, j
+}
+func (h *vwHeap) Push(x interface{}) {
+	p := x.(*vwPoint)
+	p.pos = len(*h)
+	*h = append(*h, p)
+}
+func (h *vwHeap) Pop() interface{} {
+	old := *h
+	p := old[len(old)-1]
+	p.pos = -1
+	*h = old[:len(old)-1]
+	return p
+}
+
+// Simplify returns the points of the line that are kept, isClosed is
+// ignored.
+func (vw VisvalingamWhyatt) Simplify(ctx context.Context, linestring [][2]float64, isClosed bool) ([][2]float64, error) {
+	if vw.Tolerance <= 0 || len(linestring) <= 2 {
+		return append([][2]float64(nil), linestring...), nil
+	}
+
+	pts := make([]vwPoint, len(linestring))
+	for i := range pts {
+		pts[i].idx, pts[i].pos = i, -1
+		if i > 0 {
+			pts[i].prev = &pts[i-1]
+		}
+		if i < len(pts)-1 {
+			pts[i].next = &pts[i+1]
+		}
+	}
+
+	area := func(p *vwPoint) float64 {
+		a, b, c := linestring[p.prev.idx], linestring[p.idx], linestring[p.next.idx]
+		return math.Abs((b[0]-a[0])*(c[1]-a[1])-(c[0]-a[0])*(b[1]-a[1])) / 2
+	}
+
+	h := make(vwHeap, 0, len(pts)-2)
+	for i := 1; i < len(pts)-1; i++ {
+		pts[i].area = area(&pts[i])
+		heap.Push(&h, &pts[i])
+	}
+
+	kept := len(pts)
+	for h.Len() > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		p := h[0]
+		if p.area >= vw.Tolerance {
+			break
+		}
+		heap.Pop(&h)
+		kept--
+
+		prev, next := p.prev, p.next
+		prev.next, next.prev = next, prev
+		// the neighbours' triangles change, but a neighbour's area is not
+		// allowed to fall below the removed point's, so points are removed
+		// in the order of the area they take away
+		for _, n := range [2]*vwPoint{prev, next} {
+			if n.pos < 0 {
+				continue
+			}
+			n.area = math.Max(area(n), p.area)
+			heap.Fix(&h, n.pos)
+		}
+	}
+
+	ret := make([][2]float64, 0, kept)
+	for p := &pts[0]; p != nil; p = p.next {
+		ret = append(ret, linestring[p.idx])
+	}
+	return ret, nil
+}
@@ -0,0 +1,68 @@
+package simplify
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom/cmp"
+	gtesting "github.com/go-spatial/geom/testing"
+)
+
+func TestVisvalingamWhyatt(t *testing.T) {
+	type tcase struct {
+		l  [][2]float64
+		vw VisvalingamWhyatt
+		el [][2]float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			gl, err := tc.vw.Simplify(context.Background(), tc.l, false)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !cmp.LineStringEqual(tc.el, gl) {
+				t.Errorf("simplified points, expected\n%v\n\tgot\n%v", tc.el, gl)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"zero tolerance": {
+			l:  [][2]float64{{0, 0}, {1, 0}, {2, 0}},
+			el: [][2]float64{{0, 0}, {1, 0}, {2, 0}},
+		},
+		"two points": {
+			l:  [][2]float64{{0, 0}, {1, 1}},
+			vw: VisvalingamWhyatt{Tolerance: 10},
+			el: [][2]float64{{0, 0}, {1, 1}},
+		},
+		"x axis": {
+			l: gtesting.FuncLineString(0, 100, 100, func(t float64) [2]float64 {
+				return [2]float64{t, 0}
+			}),
+			vw: VisvalingamWhyatt{Tolerance: 0.001},
+			el: [][2]float64{{0, 0}, {100, 0}},
+		},
+		"small bump": {
+			l:  [][2]float64{{0, 0}, {1, 0.1}, {2, 0}, {3, 5}, {4, 0}},
+			vw: VisvalingamWhyatt{Tolerance: 1},
+			el: [][2]float64{{0, 0}, {2, 0}, {3, 5}, {4, 0}},
+		},
+		"box": {
+			l:  [][2]float64{{0, 0}, {0, 1}, {1, 1}, {1, 0}},
+			vw: VisvalingamWhyatt{Tolerance: 0.4},
+			el: [][2]float64{{0, 0}, {0, 1}, {1, 1}, {1, 0}},
+		},
+		"sin": {
+			l:  gtesting.SinLineString(1, 0, 2*math.Pi, 9),
+			vw: VisvalingamWhyatt{Tolerance: 0.5},
+			el: [][2]float64{{0, 0}, {math.Pi / 2, 1}, {3 * math.Pi / 2, -1}, {2 * math.Pi, 0}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,238 @@
+package planar
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-spatial/geom"
+)
+
+// ErrSimplifiedVertex is returned when a simplifer returns a vertex that
+// is not in the original line, so the z and m values can not be kept.
+var ErrSimplifiedVertex = errors.New("planar: simplified vertex not in original line")
+
+// keptVertices returns the index of each of the simplified vertices in
+// the original line. The simplified vertices must be a subsequence of the
+// original ones.
+func keptVertices(orig, simplified [][2]float64) ([]int, error) {
+	idxs := make([]int, 0, len(simplified))
+	j := 0
+	for _, pt := range simplified {
+		for j < len(orig) && orig[j] != pt {
+			j++
+		}
+		if j == len(orig) {
+			return nil, ErrSimplifiedVertex
+		}
+		idxs = append(idxs, j)
+		j++
+	}
+	return idxs, nil
+}
+
+func xy3(pts [][3]float64) [][2]float64 {
+	xy := make([][2]float64, len(pts))
+	for i := range pts {
+		xy[i] = [2]float64{pts[i][0], pts[i][1]}
+	}
+	return xy
+}
+
+func xy4(pts [][4]float64) [][2]float64 {
+	xy := make([][2]float64, len(pts))
+	for i := range pts {
+		xy[i] = [2]float64{pts[i][0], pts[i][1]}
+	}
+	return xy
+}
+
+func simplifyLine3(ctx context.Context, simplifer Simplifer, ln [][3]float64, isClosed bool) ([][3]float64, error) {
+	xy := xy3(ln)
+	sxy, err := simplifer.Simplify(ctx, xy, isClosed)
+	if err != nil {
+		return nil, err
+	}
+	idxs, err := keptVertices(xy, sxy)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([][3]float64, len(idxs))
+	for i, idx := range idxs {
+		ret[i] = ln[idx]
+	}
+	return ret, nil
+}
+
+func simplifyLine4(ctx context.Context, simplifer Simplifer, ln [][4]float64, isClosed bool) ([][4]float64, error) {
+	xy := xy4(ln)
+	sxy, err := simplifer.Simplify(ctx, xy, isClosed)
+	if err != nil {
+		return nil, err
+	}
+	idxs, err := keptVertices(xy, sxy)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([][4]float64, len(idxs))
+	for i, idx := range idxs {
+		ret[i] = ln[idx]
+	}
+	return ret, nil
+}
+
+// simplifyPolygon3 is simplifyPolygon for three ordinates.
+func simplifyPolygon3(ctx context.Context, simplifer Simplifer, plg [][][3]float64, isClosed bool) (ret [][][3]float64, err error) {
+	ret = make([][][3]float64, len(plg))
+	for i := range plg {
+		ls, err := simplifyLine3(ctx, simplifer, plg[i], isClosed)
+		if err != nil {
+			return nil, err
+		}
+		if len(ls) > 2 || !isClosed {
+			ret[i] = ls
+		}
+	}
+	return ret, nil
+}
+
+// simplifyPolygon4 is simplifyPolygon for four ordinates.
+func simplifyPolygon4(ctx context.Context, simplifer Simplifer, plg [][][4]float64, isClosed bool) (ret [][][4]float64, err error) {
+	ret = make([][][4]float64, len(plg))
+	for i := range plg {
+		ls, err := simplifyLine4(ctx, simplifer, plg[i], isClosed)
+		if err != nil {
+			return nil, err
+		}
+		if len(ls) > 2 || !isClosed {
+			ret[i] = ls
+		}
+	}
+	return ret, nil
+}
+
+// simplifyZM simplifies the Z, M and ZM line strings and polygons using
+// their x and y values, keeping the z and m values of the remaining
+// vertices. ok is false if the geometry is not one of these types.
+func simplifyZM(ctx context.Context, simplifer Simplifer, geometry geom.Geometry) (geo geom.Geometry, ok bool, err error) {
+	switch gg := geometry.(type) {
+
+	case geom.LineStringZ:
+		ls, err := simplifyLine3(ctx, simplifer, gg, false)
+		return geom.LineStringZ(ls), true, err
+	case geom.LineStringM:
+		ls, err := simplifyLine3(ctx, simplifer, gg, false)
+		return geom.LineStringM(ls), true, err
+	case geom.LineStringZM:
+		ls, err := simplifyLine4(ctx, simplifer, gg, false)
+		return geom.LineStringZM(ls), true, err
+
+	case geom.MultiLineStringZ:
+		mls, err := simplifyPolygon3(ctx, simplifer, gg, false)
+		return geom.MultiLineStringZ(mls), true, err
+	case geom.MultiLineStringM:
+		mls, err := simplifyPolygon3(ctx, simplifer, gg, false)
+		return geom.MultiLineStringM(mls), true, err
+	case geom.MultiLineStringZM:
+		mls, err := simplifyPolygon4(ctx, simplifer, gg, false)
+		return geom.MultiLineStringZM(mls), true, err
+
+	case geom.PolygonZ:
+		ply, err := simplifyPolygon3(ctx, simplifer, gg, true)
+		return geom.PolygonZ(ply), true, err
+	case geom.PolygonM:
+		ply, err := simplifyPolygon3(ctx, simplifer, gg, true)
+		return geom.PolygonM(ply), true, err
+	case geom.PolygonZM:
+		ply, err := simplifyPolygon4(ctx, simplifer, gg, true)
+		return geom.PolygonZM(ply), true, err
+
+	case geom.MultiPolygonZ:
+		mply := make(geom.MultiPolygonZ, len(gg))
+		for i := range gg {
+			if mply[i], err = simplifyPolygon3(ctx, simplifer, gg[i], true); err != nil {
+				return nil, true, err
+			}
+		}
+		return mply, true, nil
+	case geom.MultiPolygonM:
+		mply := make(geom.MultiPolygonM, len(gg))
+		for i := range gg {
+			if mply[i], err = simplifyPolygon3(ctx, simplifer, gg[i], true); err != nil {
+				return nil, true, err
+			}
+		}
+		return mply, true, nil
+	case geom.MultiPolygonZM:
+		mply := make(geom.MultiPolygonZM, len(gg))
+		for i := range gg {
+			if mply[i], err = simplifyPolygon4(ctx, simplifer, gg[i], true); err != nil {
+				return nil, true, err
+			}
+		}
+		return mply, true, nil
+
+	case *geom.LineStringZ:
+		if gg == nil {
+			return geometry, true, nil
+		}
+		return simplifyZM(ctx, simplifer, *gg)
+	case *geom.LineStringM:
+		if gg == nil {
+			return geometry, true, nil
+		}
+		return simplifyZM(ctx, simplifer, *gg)
+	case *geom.LineStringZM:
+		if gg == nil {
+			return geometry, true, nil
+		}
+		return simplifyZM(ctx, simplifer, *gg)
+	case *geom.MultiLineStringZ:
+		if gg == nil {
+			return geometry, true, nil
+		}
+		return simplifyZM(ctx, simplifer, *gg)
+	case *geom.MultiLineStringM:
+		if gg == nil {
+			return geometry, true, nil
+		}
+		return simplifyZM(ctx, simplifer, *gg)
+	case *geom.MultiLineStringZM:
+		if gg == nil {
+			return geometry, true, nil
+		}
+		return simplifyZM(ctx, simplifer, *gg)
+	case *geom.PolygonZ:
+		if gg == nil {
+			return geometry, true, nil
+		}
+		return simplifyZM(ctx, simplifer, *gg)
+	case *geom.PolygonM:
+		if gg == nil {
+			return geometry, true, nil
+		}
+		return simplifyZM(ctx, simplifer, *gg)
+	case *geom.PolygonZM:
+		if gg == nil {
+			return geometry, true, nil
+		}
+		return simplifyZM(ctx, simplifer, *gg)
+	case *geom.MultiPolygonZ:
+		if gg == nil {
+			return geometry, true, nil
+		}
+		return simplifyZM(ctx, simplifer, *gg)
+	case *geom.MultiPolygonM:
+		if gg == nil {
+			return geometry, true, nil
+		}
+		return simplifyZM(ctx, simplifer, *gg)
+	case *geom.MultiPolygonZM:
+		if gg == nil {
+			return geometry, true, nil
+		}
+		return simplifyZM(ctx, simplifer, *gg)
+
+	default:
+		return nil, false, nil
+	}
+}
@@ -0,0 +1,104 @@
+package planar
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+// endsSimplifer keeps only the first and last vertices.
+type endsSimplifer struct{}
+
+func (endsSimplifer) Simplify(_ context.Context, ln [][2]float64, _ bool) ([][2]float64, error) {
+	if len(ln) <= 2 {
+		return ln, nil
+	}
+	return [][2]float64{ln[0], ln[len(ln)-1]}, nil
+}
+
+// shiftSimplifer returns vertices that are not in the line.
+type shiftSimplifer struct{}
+
+func (shiftSimplifer) Simplify(_ context.Context, ln [][2]float64, _ bool) ([][2]float64, error) {
+	return [][2]float64{{ln[0][0] + 1, ln[0][1]}}, nil
+}
+
+func TestSimplifyZM(t *testing.T) {
+	type tcase struct {
+		simplifer Simplifer
+		geom      geom.Geometry
+		expected  geom.Geometry
+		err       error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Simplify(context.Background(), tc.simplifer, tc.geom)
+			if err != tc.err {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+				return
+			}
+			if tc.err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("simplify, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"linestring z": {
+			simplifer: endsSimplifer{},
+			geom:      geom.LineStringZ{{0, 0, 1}, {1, 1, 2}, {2, 0, 3}},
+			expected:  geom.LineStringZ{{0, 0, 1}, {2, 0, 3}},
+		},
+		"linestring zm pointer": {
+			simplifer: endsSimplifer{},
+			geom:      &geom.LineStringZM{{0, 0, 1, 4}, {1, 1, 2, 5}, {2, 0, 3, 6}},
+			expected:  geom.LineStringZM{{0, 0, 1, 4}, {2, 0, 3, 6}},
+		},
+		"multilinestring m": {
+			simplifer: endsSimplifer{},
+			geom:      geom.MultiLineStringM{{{0, 0, 1}, {1, 1, 2}, {2, 0, 3}}},
+			expected:  geom.MultiLineStringM{{{0, 0, 1}, {2, 0, 3}}},
+		},
+		"polygon z ring dropped": {
+			simplifer: endsSimplifer{},
+			geom:      geom.PolygonZ{{{0, 0, 1}, {1, 1, 2}, {2, 0, 3}}},
+			expected:  geom.PolygonZ{nil},
+		},
+		"multipolygon zm": {
+			simplifer: endsSimplifer{},
+			geom:      geom.MultiPolygonZM{{{{0, 0, 1, 1}, {1, 1, 2, 2}}}},
+			expected:  geom.MultiPolygonZM{{nil}},
+		},
+		"point z untouched": {
+			simplifer: endsSimplifer{},
+			geom:      geom.PointZ{1, 2, 3},
+			expected:  geom.PointZ{1, 2, 3},
+		},
+		"nested collection": {
+			simplifer: endsSimplifer{},
+			geom: geom.Collection{
+				geom.LineString{{0, 0}, {1, 1}, {2, 0}},
+				&geom.Collection{geom.LineStringZ{{0, 0, 1}, {1, 1, 2}, {2, 0, 3}}},
+			},
+			expected: geom.Collection{
+				geom.LineString{{0, 0}, {2, 0}},
+				geom.Collection{geom.LineStringZ{{0, 0, 1}, {2, 0, 3}}},
+			},
+		},
+		"vertex not in line": {
+			simplifer: shiftSimplifer{},
+			geom:      geom.LineStringZ{{0, 0, 1}, {1, 1, 2}},
+			err:       ErrSimplifiedVertex,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
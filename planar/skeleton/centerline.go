@@ -0,0 +1,169 @@
+package skeleton
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/simplify"
+)
+
+// centerlineSmoothing is the number of times the corners of a centerline
+// are cut
+const centerlineSmoothing = 2
+
+// Centerline returns the longest path along the medial axis of the
+// polygon, see MedialAxis, smoothed for placing curved labels along. The
+// path is simplified to within spacing, then its corners are cut, by no
+// more than spacing so the cuts stay inside the polygon, keeping its ends
+// where they are. Where the medial axis is in more than one piece,
+// as it is for polygons narrower than spacing in places, the longest path
+// of all the pieces is returned. It is nil if the medial axis is empty.
+func Centerline(ctx context.Context, poly geom.Polygoner, spacing float64) (geom.LineString, error) {
+	lines, err := MedialAxis(ctx, poly, spacing)
+	if err != nil {
+		return nil, err
+	}
+
+	g := make(graph)
+	for _, ln := range lines {
+		a, b := geom.Point(ln[0]), geom.Point(ln[1])
+		g[a] = append(g[a], b)
+		g[b] = append(g[b], a)
+	}
+	// visit the nodes in order, so the path is the same every time
+	nodes := make([]geom.Point, 0, len(g))
+	for pt := range g {
+		nodes = append(nodes, pt)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i][0] != nodes[j][0] {
+			return nodes[i][0] < nodes[j][0]
+		}
+		return nodes[i][1] < nodes[j][1]
+	})
+
+	// the longest path of each piece is approximated by the path from the
+	// node furthest from any of its nodes to the node furthest from that
+	var (
+		path    [][2]float64
+		longest = -1.0
+		visited = make(map[geom.Point]bool)
+	)
+	for _, pt := range nodes {
+		if visited[pt] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		_, _, start := g.furthest(pt, visited)
+		dist, prev, end := g.furthest(start, nil)
+		if dist[end] <= longest {
+			continue
+		}
+		longest = dist[end]
+		path = path[:0]
+		for pt := end; pt != start; pt = prev[pt] {
+			path = append(path, pt)
+		}
+		path = append(path, start)
+	}
+	if len(path) < 2 {
+		return nil, nil
+	}
+
+	path, err = simplify.DouglasPeucker{Tolerance: spacing}.Simplify(ctx, path, false)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < centerlineSmoothing; i++ {
+		path = cutCorners(path, spacing)
+	}
+	return geom.LineString(path), nil
+}
+
+// cutCorners replaces each corner of the line with points a quarter of the
+// way along the segments either side of it, Chaikin's algorithm, or max
+// from the corner if that is nearer, keeping the ends where they are
+func cutCorners(pts [][2]float64, max float64) [][2]float64 {
+	if len(pts) < 3 {
+		return pts
+	}
+	cut := make([][2]float64, 0, 2*len(pts))
+	cut = append(cut, pts[0])
+	for i := 0; i < len(pts)-1; i++ {
+		a, b := pts[i], pts[i+1]
+		t := 0.25
+		if l := math.Hypot(b[0]-a[0], b[1]-a[1]); l*t > max {
+			t = max / l
+		}
+		q := [2]float64{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])}
+		r := [2]float64{b[0] - t*(b[0]-a[0]), b[1] - t*(b[1]-a[1])}
+		if i > 0 {
+			cut = append(cut, q)
+		}
+		if i < len(pts)-2 {
+			cut = append(cut, r)
+		}
+	}
+	return append(cut, pts[len(pts)-1])
+}
+
+// graph is the nodes next to each of the nodes of the medial axis
+type graph map[geom.Point][]geom.Point
+
+// furthest returns the distances along the graph from the start to the
+// nodes that can be reached from it, the node before each on the way, and
+// the node that is furthest. The nodes reached are marked as visited if
+// visited is not nil.
+func (g graph) furthest(start geom.Point, visited map[geom.Point]bool) (dist map[geom.Point]float64, prev map[geom.Point]geom.Point, far geom.Point) {
+	dist = map[geom.Point]float64{start: 0}
+	prev = make(map[geom.Point]geom.Point)
+	done := make(map[geom.Point]bool)
+	far = start
+	q := &queue{{pt: start}}
+	for q.Len() > 0 {
+		it := heap.Pop(q).(queueItem)
+		if done[it.pt] {
+			continue
+		}
+		done[it.pt] = true
+		if visited != nil {
+			visited[it.pt] = true
+		}
+		if it.dist > dist[far] {
+			far = it.pt
+		}
+		for _, n := range g[it.pt] {
+			d := it.dist + math.Hypot(n[0]-it.pt[0], n[1]-it.pt[1])
+			if nd, ok := dist[n]; ok && nd <= d {
+				continue
+			}
+			dist[n], prev[n] = d, it.pt
+			heap.Push(q, queueItem{pt: n, dist: d})
+		}
+	}
+	return dist, prev, far
+}
+
+type queueItem struct {
+	pt   geom.Point
+	dist float64
+}
+
+// queue is a priority queue of nodes, nearest first
+type queue []queueItem
+
+func (q queue) Len() int            { return len(q) }
+func (q queue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q queue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *queue) Push(x interface{}) { *q = append(*q, x.(queueItem)) }
+func (q *queue) Pop() interface{} {
+	old := *q
+	it := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return it
+}
@@ -0,0 +1,89 @@
+package skeleton
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+)
+
+func TestCenterline(t *testing.T) {
+	type tcase struct {
+		poly    geom.Polygoner
+		spacing float64
+		// ends are the expected ends of the centerline, in either order,
+		// within the tolerance
+		ends      [2][2]float64
+		tolerance float64
+		err       error
+	}
+
+	near := func(a, b [2]float64, tol float64) bool {
+		return math.Hypot(a[0]-b[0], a[1]-b[1]) <= tol
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Centerline(context.Background(), tc.poly, tc.spacing)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(got) < 2 {
+				t.Fatalf("centerline, expected a line got %v", got)
+			}
+			first, last := got[0], got[len(got)-1]
+			if !(near(first, tc.ends[0], tc.tolerance) && near(last, tc.ends[1], tc.tolerance)) &&
+				!(near(first, tc.ends[1], tc.tolerance) && near(last, tc.ends[0], tc.tolerance)) {
+				t.Errorf("ends, expected %v got %v and %v", tc.ends, first, last)
+			}
+			ply := planar.NewPreparedPolygon(geom.Polygon(tc.poly.LinearRings()))
+			for _, pt := range got {
+				if !ply.Contains(pt) {
+					t.Errorf("point %v, expected inside the polygon", pt)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"rectangle": {
+			// the longest path runs out to corners at either end
+			poly:      geom.Polygon{{{0, 0}, {20, 0}, {20, 2}, {0, 2}}},
+			spacing:   0.25,
+			ends:      [2][2]float64{{0, 1}, {20, 1}},
+			tolerance: 1.1,
+		},
+		"l shape": {
+			poly:      geom.Polygon{{{0, 0}, {20, 0}, {20, 20}, {18, 20}, {18, 2}, {0, 2}}},
+			spacing:   0.25,
+			ends:      [2][2]float64{{0, 1}, {19, 20}},
+			tolerance: 1.1,
+		},
+		"spacing": {
+			poly: geom.Polygon{{{0, 0}, {20, 0}, {20, 2}, {0, 2}}},
+			err:  ErrInvalidSpacing,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestCutCorners(t *testing.T) {
+	got := cutCorners([][2]float64{{0, 0}, {4, 0}, {4, 8}}, 1.5)
+	exp := [][2]float64{{0, 0}, {3, 0}, {4, 1.5}, {4, 8}}
+	if len(got) != len(exp) {
+		t.Fatalf("points, expected %v got %v", exp, got)
+	}
+	for i := range exp {
+		if got[i] != exp[i] {
+			t.Errorf("point %v, expected %v got %v", i, exp[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package skeleton approximates the medial axis of polygons, the points
+// inside a polygon with more than one nearest point on its outline. The
+// medial axis runs down the middle of long thin polygons, so it is what
+// centerlines of rivers and road casings are made from.
+package skeleton
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay/subdivision"
+)
+
+// ErrInvalidSpacing is returned for sample spacings that are not positive.
+var ErrInvalidSpacing = errors.New("skeleton: invalid spacing")
+
+// sample is where a point sampled from the outline of the polygon is
+type sample struct {
+	ring, index, n int
+}
+
+// next reports whether the samples are next to each other on the outline
+func (s sample) next(o sample) bool {
+	if s.ring != o.ring {
+		return false
+	}
+	d := s.index - o.index
+	if d < 0 {
+		d = -d
+	}
+	return d == 1 || d == s.n-1
+}
+
+// edge is a Delaunay edge, with its points in order
+type edge [2]geom.Point
+
+func newEdge(a, b geom.Point) edge {
+	if b[0] < a[0] || (b[0] == a[0] && b[1] < a[1]) {
+		a, b = b, a
+	}
+	return edge{a, b}
+}
+
+// MedialAxis returns the edges of the approximate medial axis of the
+// polygon. The outline is sampled so that the samples are at most spacing
+// apart, and the medial axis is approximated by the edges of the Voronoi
+// diagram of the samples that are inside the polygon and separate samples
+// that are not next to each other on the outline. The smaller the spacing
+// the closer the approximation, and the more edges there are.
+func MedialAxis(ctx context.Context, poly geom.Polygoner, spacing float64) ([]geom.Line, error) {
+	if poly == nil {
+		return nil, geom.ErrNilPolygon
+	}
+	if !(spacing > 0) || math.IsInf(spacing, 0) {
+		return nil, ErrInvalidSpacing
+	}
+	ply := geom.Polygon(poly.LinearRings())
+	g, err := planar.Densify(ply, spacing)
+	if err != nil {
+		return nil, err
+	}
+
+	// the triangulation rounds the points, so the samples are found by
+	// their rounded points
+	samples := make(map[geom.Point]sample)
+	var pts []geom.Point
+	for r, ring := range g.(geom.Polygon) {
+		for i, pt := range ring {
+			rpt := round(pt)
+			if _, ok := samples[rpt]; ok {
+				continue
+			}
+			samples[rpt] = sample{ring: r, index: i, n: len(ring)}
+			pts = append(pts, rpt)
+		}
+	}
+
+	tris, err := delaunay.TriangulateConcurrent(ctx, pts, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// the Voronoi vertices are the centers of the circumcircles of the
+	// triangles, and its edges join those of neighbouring triangles
+	inside := planar.NewPreparedPolygon(ply)
+	centers := make(map[edge][]geom.Point)
+	for _, tri := range tris {
+		c, ok := circumcenter(tri)
+		if !ok || !inside.Contains(c) {
+			continue
+		}
+		for i := range tri {
+			e := newEdge(tri[i], tri[(i+1)%3])
+			centers[e] = append(centers[e], c)
+		}
+	}
+
+	var lines []geom.Line
+	for e, cs := range centers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// the Voronoi edges between samples next to each other run out to
+		// the outline
+		if len(cs) != 2 || cs[0] == cs[1] || samples[e[0]].next(samples[e[1]]) {
+			continue
+		}
+		lines = append(lines, geom.Line{cs[0], cs[1]})
+	}
+	return lines, nil
+}
+
+func round(pt [2]float64) geom.Point {
+	return geom.Point{
+		math.Round(pt[0]*subdivision.RoundingFactor) / subdivision.RoundingFactor,
+		math.Round(pt[1]*subdivision.RoundingFactor) / subdivision.RoundingFactor,
+	}
+}
+
+// circumcenter returns the center of the circle through the points of the
+// triangle, which is false for triangles with no area
+func circumcenter(tri geom.Triangle) (geom.Point, bool) {
+	bx, by := tri[1][0]-tri[0][0], tri[1][1]-tri[0][1]
+	cx, cy := tri[2][0]-tri[0][0], tri[2][1]-tri[0][1]
+	d := 2 * (bx*cy - by*cx)
+	if d == 0 {
+		return geom.Point{}, false
+	}
+	b2, c2 := bx*bx+by*by, cx*cx+cy*cy
+	return geom.Point{tri[0][0] + (cy*b2-by*c2)/d, tri[0][1] + (bx*c2-cx*b2)/d}, true
+}
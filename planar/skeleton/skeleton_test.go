@@ -0,0 +1,84 @@
+package skeleton
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+)
+
+func TestMedialAxis(t *testing.T) {
+	type tcase struct {
+		poly    geom.Polygoner
+		spacing float64
+		// middle is the part of the polygon where the medial axis is
+		// expected along y = axis
+		middle geom.Extent
+		axis   float64
+		err    error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := MedialAxis(context.Background(), tc.poly, tc.spacing)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(got) == 0 {
+				t.Fatalf("edges, expected some got none")
+			}
+			ply := planar.NewPreparedPolygon(geom.Polygon(tc.poly.LinearRings()))
+			var length float64
+			for _, ln := range got {
+				for _, pt := range ln {
+					if !ply.Contains(pt) {
+						t.Errorf("point %v, expected inside the polygon", pt)
+					}
+					if tc.middle.ContainsPoint(pt) && math.Abs(pt[1]-tc.axis) > tc.spacing/2 {
+						t.Errorf("point %v, expected on y = %v", pt, tc.axis)
+					}
+				}
+				if tc.middle.ContainsPoint(ln[0]) && tc.middle.ContainsPoint(ln[1]) {
+					length += math.Sqrt(ln.LengthSquared())
+				}
+			}
+			// the axis runs all the way through the middle
+			if exp := tc.middle.XSpan(); math.Abs(length-exp) > tc.spacing {
+				t.Errorf("length in the middle, expected %v got %v", exp, length)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"rectangle": {
+			poly:    geom.Polygon{{{0, 0}, {20, 0}, {20, 2}, {0, 2}}},
+			spacing: 0.25,
+			middle:  geom.Extent{2, 0, 18, 2},
+			axis:    1,
+		},
+		"l shape": {
+			poly:    geom.Polygon{{{0, 0}, {20, 0}, {20, 20}, {18, 20}, {18, 2}, {0, 2}}},
+			spacing: 0.25,
+			middle:  geom.Extent{2, 0, 16, 2},
+			axis:    1,
+		},
+		"spacing": {
+			poly:    geom.Polygon{{{0, 0}, {20, 0}, {20, 2}, {0, 2}}},
+			spacing: 0,
+			err:     ErrInvalidSpacing,
+		},
+		"nil": {
+			spacing: 1,
+			err:     geom.ErrNilPolygon,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
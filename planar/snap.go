@@ -0,0 +1,72 @@
+package planar
+
+import (
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/index/rtree"
+)
+
+// ErrInvalidSnapTolerance is returned by Snap for tolerances that are
+// negative, NaN or infinite.
+var ErrInvalidSnapTolerance = errors.New("planar: invalid snap tolerance")
+
+// Snap returns a copy of the geometry, of the same type, with each of its
+// vertices within tolerance of the reference moved onto it: onto the
+// nearest vertex of the reference within tolerance if there is one,
+// otherwise onto the nearest point of its segments. Boundaries that almost
+// match the reference then match it exactly, which they need to before
+// they are overlaid or unioned.
+//
+// Only the vertices are moved, none are added, so a segment is not snapped
+// where a vertex of the reference is near its middle. Vertices snapped to
+// the same place are kept, so lines and rings may have repeated points, or
+// collapse, afterwards. Points, lines, polygons, their Multi forms and
+// collections of them are supported as the reference.
+func Snap(g, reference geom.Geometry, tolerance float64) (geom.Geometry, error) {
+	if !(tolerance >= 0) || math.IsInf(tolerance, 0) {
+		return nil, ErrInvalidSnapTolerance
+	}
+	var parts geomParts
+	if err := parts.add(reference); err != nil {
+		return nil, err
+	}
+	segs := nearestSegments(parts)
+	items := make([]rtree.Item, len(segs))
+	for i, seg := range segs {
+		items[i] = rtree.Item{Extent: *geom.NewExtent(seg[:]...), Data: seg}
+	}
+	tree := rtree.New(rtree.DefaultMaxEntries)
+	tree.Load(items)
+
+	tol2 := tolerance * tolerance
+	return geom.Map(g, func(pt [2]float64) ([2]float64, error) {
+		var (
+			vertex, edge     [2]float64
+			vertexD2, edgeD2 = math.Inf(1), math.Inf(1)
+			ext              = geom.Extent{pt[0] - tolerance, pt[1] - tolerance, pt[0] + tolerance, pt[1] + tolerance}
+		)
+		tree.Search(ext, func(it rtree.Item) bool {
+			seg := it.Data.(geom.Line)
+			for _, v := range seg {
+				if d := vsub(pt, v); vdot(d, d) < vertexD2 {
+					vertex, vertexD2 = v, vdot(d, d)
+				}
+			}
+			on := nearestOnSegment(seg[0], seg[1], pt)
+			if d := vsub(pt, on); vdot(d, d) < edgeD2 {
+				edge, edgeD2 = on, vdot(d, d)
+			}
+			return true
+		})
+		switch {
+		case vertexD2 <= tol2:
+			return vertex, nil
+		case edgeD2 <= tol2:
+			return edge, nil
+		default:
+			return pt, nil
+		}
+	})
+}
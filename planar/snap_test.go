@@ -0,0 +1,87 @@
+package planar
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestSnap(t *testing.T) {
+	type tcase struct {
+		g, reference geom.Geometry
+		tolerance    float64
+		exp          geom.Geometry
+		err          error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Snap(tc.g, tc.reference, tc.tolerance)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("snapped, expected %v got %v", tc.exp, got)
+			}
+		}
+	}
+
+	square := geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}
+
+	tests := map[string]tcase{
+		"vertex": {
+			g:         geom.Point{10.05, 9.98},
+			reference: square,
+			tolerance: 0.1,
+			exp:       geom.Point{10, 10},
+		},
+		"edge": {
+			g:         geom.Point{10.05, 5},
+			reference: square,
+			tolerance: 0.1,
+			exp:       geom.Point{10, 5},
+		},
+		"vertex before edge": {
+			// the edge is nearer but the vertex is within tolerance
+			g:         geom.Point{10.01, 0.05},
+			reference: square,
+			tolerance: 0.1,
+			exp:       geom.Point{10, 0},
+		},
+		"too far": {
+			g:         geom.Point{10.5, 5},
+			reference: square,
+			tolerance: 0.1,
+			exp:       geom.Point{10.5, 5},
+		},
+		"neighbouring polygon": {
+			g:         geom.Polygon{{{10.02, 0}, {20, 0}, {20, 10}, {9.97, 10.01}, {9.99, 4}}},
+			reference: square,
+			tolerance: 0.1,
+			exp:       geom.Polygon{{{10, 0}, {20, 0}, {20, 10}, {10, 10}, {10, 4}}},
+		},
+		"line to point": {
+			g:         geom.LineString{{0, 0}, {5.05, 5}},
+			reference: geom.MultiPoint{{5, 5}},
+			tolerance: 0.1,
+			exp:       geom.LineString{{0, 0}, {5, 5}},
+		},
+		"negative tolerance": {
+			g:         geom.Point{1, 1},
+			reference: square,
+			tolerance: -1,
+			err:       ErrInvalidSnapTolerance,
+		},
+		"unknown reference": {
+			g:         geom.Point{1, 1},
+			reference: nil,
+			tolerance: 1,
+			err:       geom.ErrUnknownGeometry{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,237 @@
+package sweep
+
+import "github.com/go-spatial/geom/planar/predicates"
+
+// node is a piece in the sweep line
+type node struct {
+	piece               *piece
+	priority            uint32
+	left, right, parent *node
+}
+
+// status is the pieces crossing the sweep line, in order from bottom to
+// top. It is a treap: a binary search tree that is also a heap of random
+// priorities, which keeps it balanced.
+type status struct {
+	root *node
+	seed uint32
+}
+
+// random returns the next priority, from a xorshift generator so the
+// results do not depend on global state
+func (s *status) random() uint32 {
+	if s.seed == 0 {
+		s.seed = 2463534242
+	}
+	s.seed ^= s.seed << 13
+	s.seed ^= s.seed >> 17
+	s.seed ^= s.seed << 5
+	return s.seed
+}
+
+// insert adds the piece, returning its node
+func (s *status) insert(p *piece) *node {
+	n := &node{piece: p, priority: s.random()}
+	if s.root == nil {
+		s.root = n
+		return n
+	}
+	cur := s.root
+	for {
+		if compare(p, cur.piece) < 0 {
+			if cur.left == nil {
+				cur.left = n
+				break
+			}
+			cur = cur.left
+		} else {
+			if cur.right == nil {
+				cur.right = n
+				break
+			}
+			cur = cur.right
+		}
+	}
+	n.parent = cur
+	for n.parent != nil && n.priority < n.parent.priority {
+		s.rotateUp(n)
+	}
+	return n
+}
+
+// remove removes the node
+func (s *status) remove(n *node) {
+	// rotate the node down until it is a leaf
+	for n.left != nil || n.right != nil {
+		child := n.left
+		if child == nil || (n.right != nil && n.right.priority < child.priority) {
+			child = n.right
+		}
+		s.rotateUp(child)
+	}
+	switch {
+	case n.parent == nil:
+		s.root = nil
+	case n.parent.left == n:
+		n.parent.left = nil
+	default:
+		n.parent.right = nil
+	}
+	n.parent = nil
+}
+
+// rotateUp moves the node above its parent, keeping the order of the nodes
+func (s *status) rotateUp(n *node) {
+	p, g := n.parent, n.parent.parent
+	if n == p.left {
+		p.left = n.right
+		if n.right != nil {
+			n.right.parent = p
+		}
+		n.right = p
+	} else {
+		p.right = n.left
+		if n.left != nil {
+			n.left.parent = p
+		}
+		n.left = p
+	}
+	p.parent = n
+	n.parent = g
+	switch {
+	case g == nil:
+		s.root = n
+	case g.left == p:
+		g.left = n
+	default:
+		g.right = n
+	}
+}
+
+// next returns the node above, or nil
+func (s *status) next(n *node) *node {
+	if n.right != nil {
+		n = n.right
+		for n.left != nil {
+			n = n.left
+		}
+		return n
+	}
+	for n.parent != nil && n.parent.right == n {
+		n = n.parent
+	}
+	return n.parent
+}
+
+// prev returns the node below, or nil
+func (s *status) prev(n *node) *node {
+	if n.left != nil {
+		n = n.left
+		for n.right != nil {
+			n = n.right
+		}
+		return n
+	}
+	for n.parent != nil && n.parent.left == n {
+		n = n.parent
+	}
+	return n.parent
+}
+
+// compare returns a negative number if piece a is below piece b in the
+// sweep line, and a positive number if it is above. The order only depends
+// on the ends of the pieces, so it does not change as the sweep line moves
+// as long as the pieces do not cross.
+func compare(a, b *piece) int {
+	if a == b {
+		return 0
+	}
+
+	aPoint, bPoint := a.left == a.right, b.left == b.right
+	switch {
+	case aPoint && bPoint:
+		return compareEnds(a, b)
+	case aPoint:
+		return -side(b, a.left, a)
+	case bPoint:
+		return side(a, b.left, b)
+	}
+
+	o1 := predicates.Orient2D(a.line[0], a.line[1], b.left)
+	o2 := predicates.Orient2D(a.line[0], a.line[1], b.right)
+	if o1 == 0 && o2 == 0 {
+		// on the same line
+		return compareEnds(a, b)
+	}
+
+	switch {
+	case a.left == b.left:
+		// o1 is zero, so the right end of b decides
+		if o2 > 0 {
+			return -1
+		}
+		return 1
+
+	case a.left[0] == b.left[0]:
+		if a.left[1] < b.left[1] {
+			return -1
+		}
+		return 1
+
+	case less(a.left, b.left):
+		// b starts after a, so where it starts relative to a decides
+		o := o1
+		if o == 0 {
+			o = o2
+		}
+		if o > 0 {
+			return -1
+		}
+		return 1
+	}
+
+	// a starts after b
+	o := predicates.Orient2D(b.line[0], b.line[1], a.left)
+	if o == 0 {
+		o = predicates.Orient2D(b.line[0], b.line[1], a.right)
+	}
+	if o > 0 {
+		return 1
+	}
+	return -1
+}
+
+// side returns a negative number if the point of piece pt is above piece p,
+// and a positive number if it is below
+func side(p *piece, pt [2]float64, ptPiece *piece) int {
+	o := predicates.Orient2D(p.line[0], p.line[1], pt)
+	switch {
+	case o > 0:
+		return -1
+	case o < 0:
+		return 1
+	}
+	return compareEnds(p, ptPiece)
+}
+
+// compareEnds orders pieces on the same line by their ends, then by their
+// ids
+func compareEnds(a, b *piece) int {
+	switch {
+	case a.left != b.left:
+		if less(a.left, b.left) {
+			return -1
+		}
+		return 1
+	case a.right != b.right:
+		if less(a.right, b.right) {
+			return -1
+		}
+		return 1
+	case a.id < b.id:
+		return -1
+	case a.id > b.id:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,407 @@
+// Package sweep finds the points where line segments cross or touch, using
+// the Bentley–Ottmann sweep line algorithm, in O((n+k) log n) time for n
+// segments with k intersections.
+//
+// A vertical line is swept across the segments from left to right, keeping
+// the segments it crosses in order from bottom to top. Segments can only
+// meet after being next to each other in that order, so each segment is only
+// tested against its neighbours. When two segments cross they are split at
+// the crossing, so the order of the segments in the sweep line never
+// changes, and the order is decided with the exact orientation test of
+// package predicates.
+//
+// Reference: Bentley and Ottmann, Algorithms for Reporting and Counting
+// Geometric Intersections, 1979. The splitting of segments follows
+// Martínez, Rueda and Feito, A new algorithm for computing Boolean
+// operations on polygons, 2009.
+package sweep
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/predicates"
+)
+
+// ErrNotFinite is returned for segments with NaN or infinite coordinates,
+// which can not be swept.
+var ErrNotFinite = errors.New("sweep: segment coordinates must be finite")
+
+// Intersection is a point where two or more segments cross or touch.
+type Intersection struct {
+	Point [2]float64
+	// Segments are the indexes of the segments at the point, in increasing
+	// order.
+	Segments []int
+}
+
+// Intersections returns the points where the segments cross, touch or
+// share an end, ordered by x then y. Segments that overlap meet at the ends
+// of the overlap. Segments with both ends the same are points, which meet
+// the segments they are on.
+//
+// The points where segments cross are rounded to float64, all other points
+// are the ends of segments. ErrNotFinite is returned if a coordinate of a
+// segment is NaN or infinite.
+func Intersections(ctx context.Context, segs []geom.Line) ([]Intersection, error) {
+	for _, seg := range segs {
+		for _, v := range [4]float64{seg[0][0], seg[0][1], seg[1][0], seg[1][1]} {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return nil, ErrNotFinite
+			}
+		}
+	}
+
+	s := sweeper{}
+	for i, seg := range segs {
+		s.add(i, seg[0], seg[1])
+	}
+
+	// the segments at each event point
+	at := make(map[[2]float64][]int)
+	for len(s.queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		e := heap.Pop(&s.queue).(*event)
+		at[e.pt] = append(at[e.pt], e.piece.src)
+		s.process(e)
+	}
+
+	var ret []Intersection
+	for pt, srcs := range at {
+		sort.Ints(srcs)
+		uniq := srcs[:1]
+		for _, src := range srcs[1:] {
+			if src != uniq[len(uniq)-1] {
+				uniq = append(uniq, src)
+			}
+		}
+		if len(uniq) > 1 {
+			ret = append(ret, Intersection{Point: pt, Segments: uniq})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return less(ret[i].Point, ret[j].Point) })
+	return ret, nil
+}
+
+// splitTolerance is the distance, relative to the size of the coordinates,
+// from the line of a segment that a rounded crossing may be
+const splitTolerance = 1e-12
+
+// less orders points by x then y, which is the order they are swept
+func less(a, b [2]float64) bool {
+	return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+}
+
+// piece is a segment, or a piece of one that has been split, from left to
+// right
+type piece struct {
+	left, right [2]float64
+	// line is the segment, from left to right. Tests of which side of the
+	// piece a point is on use it, as the ends of a piece may have been
+	// rounded.
+	line [2][2]float64
+	// src is the index of the segment
+	src int
+	// id orders pieces that are otherwise the same
+	id         int
+	leftEvent  *event
+	rightEvent *event
+	// node is the piece's place in the sweep line
+	node *node
+}
+
+// event is the sweep line reaching the end of a piece
+type event struct {
+	pt    [2]float64
+	left  bool
+	piece *piece
+}
+
+// eventQueue is a heap of events, ordered by their point. At the same
+// point, pieces are removed from the sweep line before pieces are added.
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+func (q eventQueue) Less(i, j int) bool {
+	a, b := q[i], q[j]
+	if a.pt != b.pt {
+		return less(a.pt, b.pt)
+	}
+	if a.left != b.left {
+		return !a.left
+	}
+	return a.piece.id < b.piece.id
+}
+func (q eventQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *eventQueue) Push(x interface{}) { *q = append(*q, x.(*event)) }
+func (q *eventQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+type sweeper struct {
+	queue  eventQueue
+	status status
+	ids    int
+}
+
+// add adds the events for a piece of the segment
+func (s *sweeper) add(src int, a, b [2]float64) *piece {
+	if less(b, a) {
+		a, b = b, a
+	}
+	p := &piece{left: a, right: b, line: [2][2]float64{a, b}, src: src, id: s.ids}
+	s.ids++
+	p.leftEvent = &event{pt: a, left: true, piece: p}
+	p.rightEvent = &event{pt: b, piece: p}
+	heap.Push(&s.queue, p.leftEvent)
+	heap.Push(&s.queue, p.rightEvent)
+	return p
+}
+
+func (s *sweeper) process(e *event) {
+	p := e.piece
+	if e.left {
+		p.node = s.status.insert(p)
+		if prev := s.status.prev(p.node); prev != nil {
+			s.intersect(prev.piece, p)
+		}
+		// the piece may have been split, but it is still in the sweep line
+		if next := s.status.next(p.node); next != nil {
+			s.intersect(p, next.piece)
+		}
+		if p.left == p.right {
+			// a point, whose right event has already been processed
+			s.status.remove(p.node)
+			p.node = nil
+		}
+		return
+	}
+
+	if p.node == nil {
+		return
+	}
+	prev, next := s.status.prev(p.node), s.status.next(p.node)
+	s.status.remove(p.node)
+	p.node = nil
+	if prev != nil && next != nil {
+		s.intersect(prev.piece, next.piece)
+	}
+}
+
+// intersect splits the pieces where they cross or touch, other than at
+// their ends
+func (s *sweeper) intersect(a, b *piece) {
+	for _, pt := range meet(a, b) {
+		s.splitOverlapping(a, pt)
+		s.splitOverlapping(b, pt)
+	}
+}
+
+// splitOverlapping splits the piece and the pieces on the same line next to
+// it in the sweep line at the point. Only one of the pieces that overlap
+// is next to a piece crossing them, but they all cross it.
+func (s *sweeper) splitOverlapping(p *piece, pt [2]float64) {
+	s.split(p, pt)
+	if p.node == nil {
+		return
+	}
+	for _, step := range []func(*node) *node{s.status.prev, s.status.next} {
+		for n := step(p.node); n != nil && sameLine(p, n.piece); n = step(n) {
+			s.split(n.piece, pt)
+		}
+	}
+}
+
+// sameLine reports whether the pieces are on the same line
+func sameLine(a, b *piece) bool {
+	if a.line[0] == a.line[1] || b.line[0] == b.line[1] {
+		return false
+	}
+	return predicates.Orient2D(a.line[0], a.line[1], b.line[0]) == 0 &&
+		predicates.Orient2D(a.line[0], a.line[1], b.line[1]) == 0
+}
+
+// split splits the piece at the point if it is inside it. The piece keeps
+// its left end, and a new piece takes the right end.
+func (s *sweeper) split(p *piece, pt [2]float64) {
+	if !less(p.left, pt) || !less(pt, p.right) {
+		return
+	}
+	q := &piece{left: pt, right: p.right, line: p.line, src: p.src, id: s.ids}
+	s.ids++
+	q.rightEvent = p.rightEvent
+	q.rightEvent.piece = q
+	q.leftEvent = &event{pt: pt, left: true, piece: q}
+
+	p.right = pt
+	p.rightEvent = &event{pt: pt, piece: p}
+	heap.Push(&s.queue, p.rightEvent)
+	heap.Push(&s.queue, q.leftEvent)
+}
+
+// meet returns the points where the pieces cross or touch
+func meet(a, b *piece) [][2]float64 {
+	if a.left[0] > b.right[0] || b.left[0] > a.right[0] ||
+		min(a.left[1], a.right[1]) > max(b.left[1], b.right[1]) ||
+		min(b.left[1], b.right[1]) > max(a.left[1], a.right[1]) {
+		return nil
+	}
+
+	la, lb := a.line, b.line
+	switch {
+	case la[0] == la[1] || lb[0] == lb[1]:
+		// a piece is a point, which must be on the other piece
+		pt, l, other := a.left, lb, b
+		if la[0] != la[1] {
+			pt, l, other = b.left, la, a
+		}
+		if predicates.Orient2D(l[0], l[1], pt) == 0 && other.contains(pt) {
+			return [][2]float64{pt}
+		}
+		return nil
+
+	case sameLine(a, b):
+		// the ends of each piece inside the other
+		var pts [][2]float64
+		for _, pt := range [][2]float64{b.left, b.right} {
+			if a.contains(pt) {
+				pts = append(pts, pt)
+			}
+		}
+		for _, pt := range [][2]float64{a.left, a.right} {
+			if b.contains(pt) {
+				pts = append(pts, pt)
+			}
+		}
+		return pts
+	}
+
+	o1 := predicates.Orient2D(la[0], la[1], b.left)
+	o2 := predicates.Orient2D(la[0], la[1], b.right)
+	o3 := predicates.Orient2D(lb[0], lb[1], a.left)
+	o4 := predicates.Orient2D(lb[0], lb[1], a.right)
+	if o1*o2 > 0 || o3*o4 > 0 {
+		// A piece split where a third segment crosses ends at the rounded
+		// crossing, which may be just off the line of another segment
+		// crossing at the same point. The crossing is rounded the same way
+		// for each pair of segments, so it is the end of the piece.
+		if !a.splitNear(lb) && !b.splitNear(la) {
+			return nil
+		}
+		pt, ok := crossing(la, lb)
+		if !ok || !a.contains(pt) || !b.contains(pt) {
+			return nil
+		}
+		for _, end := range [][2]float64{a.left, a.right, b.left, b.right} {
+			if pt == end {
+				return [][2]float64{pt}
+			}
+		}
+		return nil
+	}
+
+	var pts [][2]float64
+	// the pieces touch at an end
+	switch {
+	case o1 == 0:
+		pts = [][2]float64{b.left}
+	case o2 == 0:
+		pts = [][2]float64{b.right}
+	case o3 == 0:
+		pts = [][2]float64{a.left}
+	case o4 == 0:
+		pts = [][2]float64{a.right}
+	default:
+		// the pieces cross
+		pt, ok := crossing(la, lb)
+		if !ok {
+			return nil
+		}
+		// keep the rounded point in the extent of both pieces
+		pt[0] = max(pt[0], max(a.left[0], b.left[0]))
+		pt[0] = min(pt[0], min(a.right[0], b.right[0]))
+		pt[1] = max(pt[1], max(min(a.left[1], a.right[1]), min(b.left[1], b.right[1])))
+		pt[1] = min(pt[1], min(max(a.left[1], a.right[1]), max(b.left[1], b.right[1])))
+		return [][2]float64{pt}
+	}
+	if !a.contains(pts[0]) || !b.contains(pts[0]) {
+		return nil
+	}
+	return pts
+}
+
+// crossing returns the point where the lines through the segments cross,
+// rounded to the nearest float64. It is worked out exactly, so segments
+// crossing at the same point give the same result. ok is false if the lines
+// are parallel.
+func crossing(a, b [2][2]float64) (pt [2]float64, ok bool) {
+	rat := func(f float64) *big.Rat { return new(big.Rat).SetFloat64(f) }
+	sub := func(p, q [2]float64) [2]*big.Rat {
+		return [2]*big.Rat{rat(p[0]).Sub(rat(p[0]), rat(q[0])), rat(p[1]).Sub(rat(p[1]), rat(q[1]))}
+	}
+	cross := func(u, v [2]*big.Rat) *big.Rat {
+		l := new(big.Rat).Mul(u[0], v[1])
+		return l.Sub(l, new(big.Rat).Mul(u[1], v[0]))
+	}
+
+	r, q, w := sub(a[1], a[0]), sub(b[1], b[0]), sub(b[0], a[0])
+	denom := cross(r, q)
+	if denom.Sign() == 0 {
+		return pt, false
+	}
+	t := cross(w, q)
+	t.Quo(t, denom)
+	for i := range pt {
+		c := new(big.Rat).Mul(t, r[i])
+		c.Add(c, rat(a[0][i]))
+		pt[i], _ = c.Float64()
+	}
+	return pt, true
+}
+
+// splitNear reports whether an end of the piece that is not an end of its
+// segment is very near the line. Ends that are not ends of the segment are
+// where the segment has been split, and are rounded.
+func (p *piece) splitNear(l [2][2]float64) bool {
+	for _, end := range [][2]float64{p.left, p.right} {
+		if end == p.line[0] || end == p.line[1] {
+			continue
+		}
+		o := math.Abs(predicates.Orient2D(l[0], l[1], end))
+		size := math.Hypot(l[1][0]-l[0][0], l[1][1]-l[0][1])
+		if o <= splitTolerance*size*(math.Abs(end[0])+math.Abs(end[1])) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether the point is between the ends of the piece, in
+// the order of the sweep
+func (p *piece) contains(pt [2]float64) bool {
+	return !less(pt, p.left) && !less(p.right, pt)
+}
+
+func min(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,164 @@
+package sweep
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestIntersections(t *testing.T) {
+	type tcase struct {
+		segs     []geom.Line
+		expected []Intersection
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Intersections(context.Background(), tc.segs)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("intersections, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {},
+		"cross": {
+			segs: []geom.Line{{{0, 0}, {2, 2}}, {{0, 2}, {2, 0}}},
+			expected: []Intersection{
+				{Point: [2]float64{1, 1}, Segments: []int{0, 1}},
+			},
+		},
+		"apart": {
+			segs: []geom.Line{{{0, 0}, {2, 2}}, {{3, 0}, {5, 2}}},
+		},
+		"shared end": {
+			segs: []geom.Line{{{0, 0}, {1, 1}}, {{1, 1}, {2, 0}}},
+			expected: []Intersection{
+				{Point: [2]float64{1, 1}, Segments: []int{0, 1}},
+			},
+		},
+		"t junction": {
+			segs: []geom.Line{{{0, 0}, {4, 0}}, {{2, 0}, {2, 3}}},
+			expected: []Intersection{
+				{Point: [2]float64{2, 0}, Segments: []int{0, 1}},
+			},
+		},
+		"overlap": {
+			segs: []geom.Line{{{0, 0}, {4, 0}}, {{6, 0}, {2, 0}}},
+			expected: []Intersection{
+				{Point: [2]float64{2, 0}, Segments: []int{0, 1}},
+				{Point: [2]float64{4, 0}, Segments: []int{0, 1}},
+			},
+		},
+		"vertical": {
+			segs: []geom.Line{{{1, -1}, {1, 1}}, {{0, 0}, {2, 0}}, {{1, 1}, {1, 3}}},
+			expected: []Intersection{
+				{Point: [2]float64{1, 0}, Segments: []int{0, 1}},
+				{Point: [2]float64{1, 1}, Segments: []int{0, 2}},
+			},
+		},
+		"point on segment": {
+			segs: []geom.Line{{{0, 0}, {4, 4}}, {{2, 2}, {2, 2}}},
+			expected: []Intersection{
+				{Point: [2]float64{2, 2}, Segments: []int{0, 1}},
+			},
+		},
+		"star": {
+			segs: []geom.Line{{{-1, 0}, {1, 0}}, {{0, -1}, {0, 1}}, {{-1, -1}, {1, 1}}, {{-1, 1}, {1, -1}}},
+			expected: []Intersection{
+				{Point: [2]float64{0, 0}, Segments: []int{0, 1, 2, 3}},
+			},
+		},
+		"nan": {
+			segs: []geom.Line{{{0, 0}, {2, 2}}, {{math.NaN(), math.NaN()}, {2, 0}}},
+			err:  ErrNotFinite,
+		},
+		"infinite": {
+			segs: []geom.Line{{{0, 0}, {2, 2}}, {{0, 2}, {math.Inf(1), 0}}},
+			err:  ErrNotFinite,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+// TestIntersectionsRandom checks the pairs of segments found meeting
+// against testing every pair
+func TestIntersectionsRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for run := 0; run < 200; run++ {
+		segs := make([]geom.Line, 40)
+		for i := range segs {
+			// a small grid so there are many shared ends and overlaps
+			for j := 0; j < 2; j++ {
+				segs[i][j] = [2]float64{float64(rnd.Intn(12)), float64(rnd.Intn(12))}
+			}
+		}
+
+		expected := make(map[[2]int]bool)
+		for i := range segs {
+			for j := i + 1; j < len(segs); j++ {
+				a := &piece{left: segs[i][0], right: segs[i][1]}
+				b := &piece{left: segs[j][0], right: segs[j][1]}
+				if less(a.right, a.left) {
+					a.left, a.right = a.right, a.left
+				}
+				if less(b.right, b.left) {
+					b.left, b.right = b.right, b.left
+				}
+				a.line, b.line = [2][2]float64{a.left, a.right}, [2][2]float64{b.left, b.right}
+				if len(meet(a, b)) > 0 {
+					expected[[2]int{i, j}] = true
+				}
+			}
+		}
+
+		got := make(map[[2]int]bool)
+		isects, err := Intersections(context.Background(), segs)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		for _, is := range isects {
+			for a, i := range is.Segments {
+				for _, j := range is.Segments[a+1:] {
+					got[[2]int{i, j}] = true
+				}
+			}
+		}
+
+		for pair := range expected {
+			if !got[pair] {
+				t.Errorf("run %v, segments %v %v and %v, expected to meet", run, pair, segs[pair[0]], segs[pair[1]])
+			}
+		}
+		for pair := range got {
+			if !expected[pair] {
+				t.Errorf("run %v, segments %v %v and %v, expected not to meet", run, pair, segs[pair[0]], segs[pair[1]])
+			}
+		}
+	}
+}
+
+func BenchmarkIntersections(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	segs := make([]geom.Line, 2000)
+	for i := range segs {
+		x, y := rnd.Float64()*1000, rnd.Float64()*1000
+		segs[i] = geom.Line{{x, y}, {x + rnd.Float64()*20, y + rnd.Float64()*20 - 10}}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Intersections(context.Background(), segs)
+	}
+}
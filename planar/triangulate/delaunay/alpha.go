@@ -0,0 +1,337 @@
+package delaunay
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"sort"
+
+	"github.com/gdey/errors"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay/subdivision"
+	"github.com/go-spatial/geom/winding"
+)
+
+// ErrInvalidAlpha is returned by AlphaShape and ConcaveHull when alpha is
+// not a positive number.
+const ErrInvalidAlpha = errors.String("alpha: alpha must be a positive number")
+
+// AlphaShape returns the alpha shape of the points: the union of the
+// Delaunay triangles whose circumradius is at most alpha. Smaller values of
+// alpha give tighter shapes that may break up into several polygons or have
+// holes; a large enough alpha gives the convex hull.
+//
+// Outer rings are counter-clockwise and holes are clockwise, with y going
+// up, unless the planar.Options of the context say otherwise; the rings are
+// not closed. If the points do not make any triangle, the result is empty.
+func AlphaShape(ctx context.Context, pts []geom.Point, alpha float64) (geom.MultiPolygon, error) {
+	if !(alpha > 0) {
+		return nil, ErrInvalidAlpha
+	}
+	tris, err := alphaTriangles(ctx, pts)
+	if err != nil || len(tris) == 0 {
+		return nil, err
+	}
+
+	var kept [][3]geom.Point
+	for _, tri := range tris {
+		if circumradius(tri) <= alpha {
+			kept = append(kept, tri)
+		}
+	}
+
+	edges := boundaryEdges(kept)
+	rings, err := linkEdges(ctx, edges)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mp    geom.MultiPolygon
+		holes [][][2]float64
+	)
+	for _, ring := range rings {
+		if winding.SignedArea(ring...) > 0 {
+			mp = append(mp, geom.Polygon{ring})
+		} else {
+			holes = append(holes, ring)
+		}
+	}
+	// the smallest outer ring that contains a hole is the one it is in
+	sort.SliceStable(mp, func(i, j int) bool { return winding.SignedArea(mp[i][0]...) < winding.SignedArea(mp[j][0]...) })
+	for _, hole := range holes {
+		for i := range mp {
+			if ringContainsRing(mp[i][0], hole) {
+				mp[i] = append(mp[i], hole)
+				break
+			}
+		}
+	}
+	return planar.FromContext(ctx).OrientMultiPolygon(mp), nil
+}
+
+// ConcaveHull returns a concave hull of the points: a single polygon without
+// holes that contains all of them. Starting from the Delaunay triangulation,
+// the triangle on the longest boundary edge is removed as long as that edge
+// is longer than alpha and removing the triangle keeps the polygon simple.
+// This is sometimes called a chi shape.
+//
+// The ring is counter-clockwise, with y going up, unless the planar.Options
+// of the context say otherwise, and is not closed. If the points do not make
+// any triangle, the result is empty.
+func ConcaveHull(ctx context.Context, pts []geom.Point, alpha float64) (geom.Polygon, error) {
+	if !(alpha > 0) {
+		return nil, ErrInvalidAlpha
+	}
+	tris, err := alphaTriangles(ctx, pts)
+	if err != nil || len(tris) == 0 {
+		return nil, err
+	}
+
+	// the triangle on the left of each edge
+	owner := make(map[[2]geom.Point]int, 3*len(tris))
+	for i, tri := range tris {
+		for j := range tri {
+			owner[[2]geom.Point{tri[j], tri[(j+1)%3]}] = i
+		}
+	}
+
+	var (
+		removed  = make([]bool, len(tris))
+		onHull   = make(map[geom.Point]bool)
+		boundary = make(map[[2]geom.Point]bool)
+		queue    edgeQueue
+	)
+	for _, e := range boundaryEdges(tris) {
+		boundary[e] = true
+		onHull[e[0]] = true
+		heap.Push(&queue, e)
+	}
+
+	for queue.Len() > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		e := heap.Pop(&queue).([2]geom.Point)
+		if edgeLength(e) <= alpha {
+			break
+		}
+		t := owner[e]
+		if removed[t] {
+			continue
+		}
+		tri := tris[t]
+		// the vertex of the triangle that is not on the edge
+		var opposite geom.Point
+		for _, pt := range tri {
+			if pt != e[0] && pt != e[1] {
+				opposite = pt
+			}
+		}
+		if onHull[opposite] {
+			// removing the triangle would pinch the polygon at the vertex,
+			// or leave the triangle on its own
+			continue
+		}
+
+		removed[t] = true
+		onHull[opposite] = true
+		delete(boundary, e)
+		for _, ne := range [2][2]geom.Point{{e[0], opposite}, {opposite, e[1]}} {
+			// the other sides of the triangle become boundary edges of the
+			// triangles next to it
+			if _, ok := owner[ne]; !ok {
+				continue
+			}
+			boundary[ne] = true
+			heap.Push(&queue, ne)
+		}
+	}
+
+	edges := make([][2]geom.Point, 0, len(boundary))
+	for e := range boundary {
+		edges = append(edges, e)
+	}
+	rings, err := linkEdges(ctx, edges)
+	if err != nil || len(rings) == 0 {
+		return nil, err
+	}
+	return planar.FromContext(ctx).OrientPolygon(geom.Polygon{rings[0]}), nil
+}
+
+// alphaTriangles returns the Delaunay triangles of the points, counter
+// clockwise, without the triangles on the frame or ones with no area
+func alphaTriangles(ctx context.Context, pts []geom.Point) ([][3]geom.Point, error) {
+	var (
+		xys  [][2]float64
+		seen = make(map[geom.Point]bool, len(pts))
+	)
+	for _, pt := range pts {
+		rpt := roundPoint(pt)
+		if seen[rpt] {
+			continue
+		}
+		seen[rpt] = true
+		xys = append(xys, [2]float64(rpt))
+	}
+	if len(xys) < 3 {
+		return nil, nil
+	}
+
+	sd, err := subdivision.NewForPoints(ctx, xys)
+	if err != nil {
+		return nil, err
+	}
+	all, err := sd.Triangles(false)
+	if err != nil {
+		return nil, err
+	}
+	tris := all[:0]
+	for _, tri := range all {
+		a := triangleArea(tri)
+		switch {
+		case a < 0:
+			tri[1], tri[2] = tri[2], tri[1]
+		case a == 0:
+			continue
+		}
+		tris = append(tris, tri)
+	}
+	return tris, nil
+}
+
+// boundaryEdges returns the edges of the counter clockwise triangles that
+// are not shared with another one of the triangles, in the direction they
+// go around their triangle. They are sorted, so the results do not depend
+// on map order.
+func boundaryEdges(tris [][3]geom.Point) [][2]geom.Point {
+	edges := make(map[[2]geom.Point]bool, 3*len(tris))
+	for _, tri := range tris {
+		for j := range tri {
+			edges[[2]geom.Point{tri[j], tri[(j+1)%3]}] = true
+		}
+	}
+	var boundary [][2]geom.Point
+	for e := range edges {
+		if !edges[[2]geom.Point{e[1], e[0]}] {
+			boundary = append(boundary, e)
+		}
+	}
+	sort.Slice(boundary, func(i, j int) bool { return edgeLess(boundary[i], boundary[j]) })
+	return boundary
+}
+
+// linkEdges joins the directed edges into rings. Where more than one edge
+// leaves a vertex, the one turning furthest clockwise from the edge coming in
+// is taken, so rings that touch at a vertex are kept apart.
+func linkEdges(ctx context.Context, edges [][2]geom.Point) ([][][2]float64, error) {
+	sort.Slice(edges, func(i, j int) bool { return edgeLess(edges[i], edges[j]) })
+	out := make(map[geom.Point][]int, len(edges))
+	for i, e := range edges {
+		out[e[0]] = append(out[e[0]], i)
+	}
+
+	var (
+		used  = make([]bool, len(edges))
+		rings [][][2]float64
+	)
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var ring [][2]float64
+		for cur := start; cur >= 0 && !used[cur]; {
+			used[cur] = true
+			e := edges[cur]
+			ring = append(ring, [2]float64(e[0]))
+			cur = nextEdge(edges, out[e[1]], used, e)
+		}
+		if len(ring) >= 3 {
+			rings = append(rings, ring)
+		}
+	}
+	return rings, nil
+}
+
+// nextEdge returns the unused edge of the candidates that turns furthest
+// clockwise from the edge in, or -1
+func nextEdge(edges [][2]geom.Point, candidates []int, used []bool, in [2]geom.Point) int {
+	back := math.Atan2(in[0][1]-in[1][1], in[0][0]-in[1][0])
+	best, bestAngle := -1, 0.0
+	for _, c := range candidates {
+		if used[c] {
+			continue
+		}
+		e := edges[c]
+		angle := back - math.Atan2(e[1][1]-e[0][1], e[1][0]-e[0][0])
+		for angle <= 0 {
+			angle += 2 * math.Pi
+		}
+		if best < 0 || angle < bestAngle {
+			best, bestAngle = c, angle
+		}
+	}
+	return best
+}
+
+// edgeQueue is a heap of edges with the longest first
+type edgeQueue [][2]geom.Point
+
+func (q edgeQueue) Len() int { return len(q) }
+func (q edgeQueue) Less(i, j int) bool {
+	li, lj := edgeLength(q[i]), edgeLength(q[j])
+	if li != lj {
+		return li > lj
+	}
+	return edgeLess(q[i], q[j])
+}
+func (q edgeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *edgeQueue) Push(x interface{}) { *q = append(*q, x.([2]geom.Point)) }
+func (q *edgeQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+func edgeLength(e [2]geom.Point) float64 {
+	return math.Hypot(e[1][0]-e[0][0], e[1][1]-e[0][1])
+}
+
+func edgeLess(a, b [2]geom.Point) bool {
+	for i := range a {
+		if a[i][0] != b[i][0] {
+			return a[i][0] < b[i][0]
+		}
+		if a[i][1] != b[i][1] {
+			return a[i][1] < b[i][1]
+		}
+	}
+	return false
+}
+
+// triangleArea returns the signed area of the triangle, positive if it is
+// counter clockwise
+func triangleArea(tri [3]geom.Point) float64 {
+	return ((tri[1][0]-tri[0][0])*(tri[2][1]-tri[0][1]) - (tri[2][0]-tri[0][0])*(tri[1][1]-tri[0][1])) / 2
+}
+
+func circumradius(tri [3]geom.Point) float64 {
+	a := edgeLength([2]geom.Point{tri[0], tri[1]})
+	b := edgeLength([2]geom.Point{tri[1], tri[2]})
+	c := edgeLength([2]geom.Point{tri[2], tri[0]})
+	return a * b * c / (4 * math.Abs(triangleArea(tri)))
+}
+
+// ringContainsRing reports if the hole is inside the ring. The rings come
+// from the same triangulation so they do not cross, but they may share
+// vertices, so the midpoint of an edge of the hole is tested.
+func ringContainsRing(ring, hole [][2]float64) bool {
+	mid := [2]float64{(hole[0][0] + hole[1][0]) / 2, (hole[0][1] + hole[1][1]) / 2}
+	return planar.PolygonContains(geom.Polygon{ring}, geom.Point(mid))
+}
@@ -0,0 +1,171 @@
+package delaunay_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay"
+)
+
+// gridPoints returns the points of the 7x7 unit grid for which keep is true
+func gridPoints(keep func(x, y int) bool) []geom.Point {
+	var pts []geom.Point
+	for x := 0; x <= 6; x++ {
+		for y := 0; y <= 6; y++ {
+			if keep(x, y) {
+				pts = append(pts, geom.Point{float64(x), float64(y)})
+			}
+		}
+	}
+	return pts
+}
+
+func ringArea(ring [][2]float64) float64 {
+	var a float64
+	for i := range ring {
+		j := (i + 1) % len(ring)
+		a += ring[i][0]*ring[j][1] - ring[j][0]*ring[i][1]
+	}
+	return a / 2
+}
+
+func TestAlphaShape(t *testing.T) {
+	type tcase struct {
+		Points []geom.Point
+		Alpha  float64
+		// Areas of the expected rings of each polygon, negative for holes
+		Areas [][]float64
+		Err   error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			mp, err := delaunay.AlphaShape(context.Background(), tc.Points, tc.Alpha)
+			if err != tc.Err {
+				t.Fatalf("error, expected %v got %v", tc.Err, err)
+			}
+			if len(mp) != len(tc.Areas) {
+				t.Fatalf("polygons, expected %v got %v", len(tc.Areas), len(mp))
+			}
+			for i := range mp {
+				if len(mp[i]) != len(tc.Areas[i]) {
+					t.Fatalf("polygon %v rings, expected %v got %v", i, len(tc.Areas[i]), len(mp[i]))
+				}
+				for j := range mp[i] {
+					if a := ringArea(mp[i][j]); math.Abs(a-tc.Areas[i][j]) > 1e-9 {
+						t.Errorf("polygon %v ring %v area, expected %v got %v", i, j, tc.Areas[i][j], a)
+					}
+				}
+			}
+		}
+	}
+
+	frame := gridPoints(func(x, y int) bool { return x < 2 || x > 4 || y < 2 || y > 4 })
+	tests := map[string]tcase{
+		"bad alpha": {
+			Points: frame,
+			Alpha:  0,
+			Err:    delaunay.ErrInvalidAlpha,
+		},
+		"too few points": {
+			Points: []geom.Point{{0, 0}, {1, 1}, {0, 0}},
+			Alpha:  1,
+		},
+		"alpha too small": {
+			Points: frame,
+			Alpha:  0.5,
+		},
+		"hole": {
+			Points: frame,
+			Alpha:  1,
+			// the hole has its corners cut off by the triangles there
+			Areas: [][]float64{{36, -14}},
+		},
+		"convex": {
+			Points: frame,
+			Alpha:  100,
+			Areas:  [][]float64{{36}},
+		},
+		"apart": {
+			Points: []geom.Point{{0, 0}, {1, 0}, {0, 1}, {10, 0}, {11, 0}, {10, 1}},
+			Alpha:  1,
+			Areas:  [][]float64{{0.5}, {0.5}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestConcaveHull(t *testing.T) {
+	type tcase struct {
+		Points []geom.Point
+		Alpha  float64
+		Area   float64
+		Err    error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			poly, err := delaunay.ConcaveHull(context.Background(), tc.Points, tc.Alpha)
+			if err != tc.Err {
+				t.Fatalf("error, expected %v got %v", tc.Err, err)
+			}
+			if tc.Area == 0 {
+				if len(poly) != 0 {
+					t.Errorf("polygon, expected empty got %v", poly)
+				}
+				return
+			}
+			if len(poly) != 1 {
+				t.Fatalf("rings, expected 1 got %v", len(poly))
+			}
+			if a := ringArea(poly[0]); math.Abs(a-tc.Area) > 1e-9 {
+				t.Errorf("area, expected %v got %v", tc.Area, a)
+			}
+			// all the points are on or inside the hull
+			ext := geom.NewExtent(poly[0]...)
+			for _, pt := range tc.Points {
+				if !ext.ContainsPoint(pt) {
+					t.Errorf("point %v, expected to be in the hull", pt)
+				}
+			}
+		}
+	}
+
+	ell := gridPoints(func(x, y int) bool { return x < 2 || y < 2 })
+	tests := map[string]tcase{
+		"bad alpha": {
+			Points: ell,
+			Alpha:  -1,
+			Err:    delaunay.ErrInvalidAlpha,
+		},
+		"too few points": {
+			Points: []geom.Point{{0, 0}, {1, 1}},
+			Alpha:  1,
+		},
+		"convex": {
+			Points: ell,
+			Alpha:  100,
+			Area:   23.5,
+		},
+		"concave": {
+			Points: ell,
+			Alpha:  1.2,
+			Area:   11,
+		},
+		"no holes": {
+			// an alpha shape would have a hole here
+			Points: gridPoints(func(x, y int) bool { return x < 2 || x > 4 || y < 2 || y > 4 }),
+			Alpha:  1,
+			Area:   36,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -1,9 +1,20 @@
 package delaunay
 
 import (
+	"context"
+
 	pkg "github.com/go-spatial/geom/cmp"
 )
 
 var cmp = pkg.HiCMP
 
 var oldCmp = pkg.SetDefault(pkg.HiCMP)
+
+// compareFor returns the comparator carried by the context, or the package
+// one if there is none.
+func compareFor(ctx context.Context) pkg.Compare {
+	if c, ok := pkg.FromContext(ctx); ok {
+		return c
+	}
+	return cmp
+}
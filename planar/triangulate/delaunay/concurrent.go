@@ -0,0 +1,348 @@
+package delaunay
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/index/kdtree"
+	"github.com/go-spatial/geom/planar/predicates"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay/subdivision"
+)
+
+// minPartitionSites is the fewest sites worth triangulating on their own
+// goroutine
+var minPartitionSites = 1024
+
+// TriangulateConcurrent returns the Delaunay triangles of the points,
+// counter clockwise with y going up unless the planar.Options of the
+// context say otherwise, without the triangles on the frame or ones with no
+// area. They are the triangles of subdivision.NewForPoints, which rounds
+// the points and ignores duplicates, though there may also be flat
+// triangles along the outside that its frame hides. Up to workers
+// goroutines are used, or runtime.GOMAXPROCS(0) if workers is less than
+// one.
+//
+// The points are split by x into a partition for each worker, and each
+// partition is triangulated on its own goroutine. The triangles whose
+// circumcircles are inside their partition are Delaunay triangles of all
+// the points; the points of the rest, along the seams between the
+// partitions, are triangulated again together, keeping the triangles whose
+// circumcircles have none of the other points in them. If the triangles do
+// not fit together, as can happen where there are four or more points on
+// a circle along a seam, all the points are triangulated on one goroutine.
+func TriangulateConcurrent(ctx context.Context, pts []geom.Point, workers int) ([]geom.Triangle, error) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	seen := make(map[geom.Point]bool, len(pts))
+	sites := make([]geom.Point, 0, len(pts))
+	for _, pt := range pts {
+		rpt := roundPoint(pt)
+		if seen[rpt] {
+			continue
+		}
+		seen[rpt] = true
+		sites = append(sites, rpt)
+	}
+	if len(sites) < 3 {
+		return nil, nil
+	}
+	sort.Slice(sites, func(i, j int) bool {
+		if sites[i][0] != sites[j][0] {
+			return sites[i][0] < sites[j][0]
+		}
+		return sites[i][1] < sites[j][1]
+	})
+
+	if n := len(sites) / minPartitionSites; n < workers {
+		workers = n
+	}
+	var tris []geom.Triangle
+	var err error
+	if workers > 1 {
+		if tris, err = triangulatePartitions(ctx, sites, workers); err != nil {
+			return nil, err
+		}
+	}
+	if tris == nil {
+		if tris, err = triangulateSites(ctx, sites); err != nil {
+			return nil, err
+		}
+	}
+	return planar.FromContext(ctx).OrientTriangles(tris), nil
+}
+
+// triangulatePartitions triangulates the sites, sorted by x, in n
+// partitions, returning nil if the triangles do not fit together
+func triangulatePartitions(ctx context.Context, sites []geom.Point, n int) ([]geom.Triangle, error) {
+	parts := partitionSites(sites, n)
+	results := make([]partitionResult, len(parts))
+	var wg sync.WaitGroup
+	for i := range parts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = parts[i].triangulate(ctx)
+		}(i)
+	}
+	wg.Wait()
+
+	var (
+		tris   []geom.Triangle
+		seam   []geom.Point
+		others []geom.Point
+	)
+	for _, r := range results {
+		if r.err != nil {
+			return nil, r.err
+		}
+		tris = append(tris, r.final...)
+		seam = append(seam, r.seam...)
+		others = append(others, r.others...)
+	}
+
+	seamTris, err := seamTriangles(ctx, seam, others)
+	if err != nil {
+		return nil, err
+	}
+	tris = uniqueTriangles(append(tris, seamTris...))
+	if !isTriangulation(tris) {
+		return nil, nil
+	}
+	return tris, nil
+}
+
+// triangulateSites returns the triangles of the subdivision of the points,
+// counter clockwise and without the ones with no area
+func triangulateSites(ctx context.Context, pts []geom.Point) ([]geom.Triangle, error) {
+	sd, err := newSubdivision(ctx, pts)
+	if err != nil {
+		return nil, err
+	}
+	all, err := sd.Triangles(false)
+	if err != nil {
+		return nil, err
+	}
+	var tris []geom.Triangle
+	for _, t := range all {
+		if tri, ok := ccwTriangle(t); ok {
+			tris = append(tris, tri)
+		}
+	}
+	return tris, nil
+}
+
+func newSubdivision(ctx context.Context, pts []geom.Point) (*subdivision.Subdivision, error) {
+	xys := make([][2]float64, len(pts))
+	for i := range pts {
+		xys[i] = pts[i]
+	}
+	return subdivision.NewForPoints(ctx, xys)
+}
+
+// ccwTriangle returns the triangle counter clockwise, false if it has no
+// area
+func ccwTriangle(t [3]geom.Point) (geom.Triangle, bool) {
+	tri := geom.Triangle{t[0], t[1], t[2]}
+	switch o := predicates.Orient2D(tri[0], tri[1], tri[2]); {
+	case o < 0:
+		tri[1], tri[2] = tri[2], tri[1]
+	case o == 0:
+		return tri, false
+	}
+	return tri, true
+}
+
+// partition is the sites, sorted by x, between the x of the sites of the
+// partitions either side of it
+type partition struct {
+	sites  []geom.Point
+	lo, hi float64
+}
+
+type partitionResult struct {
+	// final are the triangles whose circumcircles are inside the partition
+	final []geom.Triangle
+	// seam are the sites of the rest of the triangles, others the sites
+	// that are only in final triangles
+	seam, others []geom.Point
+	err          error
+}
+
+// partitionSites splits the sites, sorted by x, into n partitions; sites
+// with the same x are in the same partition
+func partitionSites(sites []geom.Point, n int) []partition {
+	var parts []partition
+	start := 0
+	for i := 1; i <= n && start < len(sites); i++ {
+		end := len(sites) * i / n
+		if end <= start {
+			continue
+		}
+		for end < len(sites) && sites[end][0] == sites[end-1][0] {
+			end++
+		}
+		parts = append(parts, partition{sites: sites[start:end]})
+		start = end
+	}
+	for i := range parts {
+		parts[i].lo, parts[i].hi = math.Inf(-1), math.Inf(1)
+		if i > 0 {
+			prev := parts[i-1].sites
+			parts[i].lo = prev[len(prev)-1][0]
+		}
+		if i < len(parts)-1 {
+			parts[i].hi = parts[i+1].sites[0][0]
+		}
+	}
+	return parts
+}
+
+func (p partition) triangulate(ctx context.Context) (r partitionResult) {
+	sd, err := newSubdivision(ctx, p.sites)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	// include the frame so the sites on it are seam sites
+	all, err := sd.Triangles(true)
+	if err != nil {
+		r.err = err
+		return r
+	}
+
+	isSite := make(map[geom.Point]bool, len(p.sites))
+	for _, pt := range p.sites {
+		isSite[pt] = true
+	}
+	isSeam := make(map[geom.Point]bool)
+	for _, t := range all {
+		tri, ok := ccwTriangle(t)
+		if ok && isSite[t[0]] && isSite[t[1]] && isSite[t[2]] {
+			c, rad := circumcircle(tri)
+			// away from the partitions either side, by more than the
+			// error in the circle
+			margin := 1e-9 * (rad + math.Abs(c[0]))
+			if c[0]-rad-margin > p.lo && c[0]+rad+margin < p.hi {
+				r.final = append(r.final, tri)
+				continue
+			}
+		}
+		for _, pt := range t {
+			if isSite[pt] {
+				isSeam[pt] = true
+			}
+		}
+	}
+	for _, pt := range p.sites {
+		if isSeam[pt] {
+			r.seam = append(r.seam, pt)
+		} else {
+			r.others = append(r.others, pt)
+		}
+	}
+	return r
+}
+
+// seamTriangles returns the triangles of the seam sites whose circumcircles
+// have none of the other sites in them
+func seamTriangles(ctx context.Context, seam, others []geom.Point) ([]geom.Triangle, error) {
+	tris, err := triangulateSites(ctx, seam)
+	if err != nil || len(others) == 0 {
+		return tris, err
+	}
+	kdt, err := kdtree.Build(others)
+	if err != nil {
+		return nil, err
+	}
+	keep := tris[:0]
+	for _, tri := range tris {
+		c, rad := circumcircle(tri)
+		empty := true
+		for _, pt := range kdt.Radius(geom.Point(c), rad*(1+1e-9)) {
+			if predicates.InCircle(tri[0], tri[1], tri[2], pt.XY()) > 0 {
+				empty = false
+				break
+			}
+		}
+		if empty {
+			keep = append(keep, tri)
+		}
+	}
+	return keep, nil
+}
+
+// circumcircle returns the center and radius of the circle through the
+// points of the triangle
+func circumcircle(tri geom.Triangle) ([2]float64, float64) {
+	bx, by := tri[1][0]-tri[0][0], tri[1][1]-tri[0][1]
+	cx, cy := tri[2][0]-tri[0][0], tri[2][1]-tri[0][1]
+	d := 2 * (bx*cy - by*cx)
+	b2, c2 := bx*bx+by*by, cx*cx+cy*cy
+	ux := (cy*b2 - by*c2) / d
+	uy := (bx*c2 - cx*b2) / d
+	return [2]float64{tri[0][0] + ux, tri[0][1] + uy}, math.Hypot(ux, uy)
+}
+
+// uniqueTriangles removes the triangles that are the same as an earlier
+// one, starting from a different point
+func uniqueTriangles(tris []geom.Triangle) []geom.Triangle {
+	seen := make(map[geom.Triangle]bool, len(tris))
+	ret := tris[:0]
+	for _, tri := range tris {
+		key := tri
+		for i := 1; i < 3; i++ {
+			if cmpPoint(tri[i], key[0]) < 0 {
+				key = geom.Triangle{tri[i], tri[(i+1)%3], tri[(i+2)%3]}
+			}
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		ret = append(ret, tri)
+	}
+	return ret
+}
+
+func cmpPoint(a, b [2]float64) int {
+	switch {
+	case a[0] < b[0] || (a[0] == b[0] && a[1] < b[1]):
+		return -1
+	case a == b:
+		return 0
+	}
+	return 1
+}
+
+// isTriangulation reports whether the counter clockwise triangles fit
+// together without overlaps or holes: no two triangles have the same edge
+// in the same direction, and the number of triangles is what Euler's
+// formula gives for the number of points and edges on the outside.
+func isTriangulation(tris []geom.Triangle) bool {
+	edges := make(map[[2][2]float64]bool, 3*len(tris))
+	verts := make(map[[2]float64]bool, len(tris))
+	for _, tri := range tris {
+		for i := range tri {
+			e := [2][2]float64{tri[i], tri[(i+1)%3]}
+			if edges[e] {
+				return false
+			}
+			edges[e] = true
+			verts[tri[i]] = true
+		}
+	}
+	outside := 0
+	for e := range edges {
+		if !edges[[2][2]float64{e[1], e[0]}] {
+			outside++
+		}
+	}
+	return len(tris) == 2*len(verts)-outside-2
+}
@@ -0,0 +1,145 @@
+package delaunay
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestTriangulateConcurrent(t *testing.T) {
+	type tcase struct {
+		pts     []geom.Point
+		workers int
+		// merged is whether the partitions are expected to fit together
+		merged bool
+	}
+
+	sortTriangles := func(tris []geom.Triangle) []geom.Triangle {
+		tris = uniqueTriangles(append([]geom.Triangle(nil), tris...))
+		keys := make([]geom.Triangle, len(tris))
+		for i, tri := range tris {
+			// the same start point as uniqueTriangles
+			keys[i] = tri
+			for j := 1; j < 3; j++ {
+				if cmpPoint(tri[j], keys[i][0]) < 0 {
+					keys[i] = geom.Triangle{tri[j], tri[(j+1)%3], tri[(j+2)%3]}
+				}
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			for k := range keys[i] {
+				if c := cmpPoint(keys[i][k], keys[j][k]); c != 0 {
+					return c < 0
+				}
+			}
+			return false
+		})
+		return keys
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctx := context.Background()
+			expected, err := triangulateSites(ctx, tc.pts)
+			if err != nil {
+				t.Fatalf("triangulate error, expected nil got %v", err)
+			}
+			got, err := TriangulateConcurrent(ctx, tc.pts, tc.workers)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			// the frame of one subdivision can hide flat triangles along
+			// the outside that the partitions do not, so there can be more
+			have := make(map[geom.Triangle]bool, len(got))
+			for _, tri := range sortTriangles(got) {
+				have[tri] = true
+			}
+			for _, tri := range sortTriangles(expected) {
+				if !have[tri] {
+					t.Errorf("triangle %v, expected to be found", tri)
+				}
+				delete(have, tri)
+			}
+			edges := make(map[[2][2]float64]bool)
+			for _, tri := range got {
+				for i := range tri {
+					edges[[2][2]float64{tri[i], tri[(i+1)%3]}] = true
+				}
+			}
+			for tri := range have {
+				outside := false
+				for i := range tri {
+					outside = outside || !edges[[2][2]float64{tri[(i+1)%3], tri[i]}]
+				}
+				if !outside {
+					t.Errorf("extra triangle %v, expected to be on the outside", tri)
+				}
+			}
+
+			sites := append([]geom.Point(nil), tc.pts...)
+			sort.Slice(sites, func(i, j int) bool { return cmpPoint(sites[i], sites[j]) < 0 })
+			merged, err := triangulatePartitions(ctx, sites, tc.workers)
+			if err != nil {
+				t.Fatalf("partitions error, expected nil got %v", err)
+			}
+			if (merged != nil) != tc.merged {
+				t.Errorf("merged, expected %v got %v", tc.merged, merged != nil)
+			}
+		}
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	random := make([]geom.Point, 3000)
+	for i := range random {
+		random[i] = roundPoint(geom.Point{rng.Float64() * 1000, rng.Float64() * 1000})
+	}
+	var grid []geom.Point
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 40; y++ {
+			grid = append(grid, geom.Point{float64(x), float64(y)})
+		}
+	}
+
+	defer func(n int) { minPartitionSites = n }(minPartitionSites)
+	minPartitionSites = 100
+
+	tests := map[string]tcase{
+		"random two workers": {
+			pts:     random,
+			workers: 2,
+			merged:  true,
+		},
+		"random eight workers": {
+			pts:     random,
+			workers: 8,
+			merged:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	// the triangles of a grid are not unique, check they cover it
+	tris, err := TriangulateConcurrent(context.Background(), grid, 4)
+	if err != nil {
+		t.Fatalf("grid error, expected nil got %v", err)
+	}
+	if !isTriangulation(tris) {
+		t.Errorf("grid, expected the triangles to fit together")
+	}
+	var area float64
+	for _, tri := range tris {
+		area += triangleArea([3]geom.Point{tri[0], tri[1], tri[2]})
+	}
+	if area != 39*39 {
+		t.Errorf("grid area, expected %v got %v", 39*39, area)
+	}
+
+	if tris, err := TriangulateConcurrent(context.Background(), []geom.Point{{0, 0}, {1, 1}}, 2); tris != nil || err != nil {
+		t.Errorf("two points, expected nil, nil got %v, %v", tris, err)
+	}
+}
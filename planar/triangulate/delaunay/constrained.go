@@ -31,6 +31,7 @@ func init() {
 func (ct *GeomConstrained) Triangles(ctx context.Context, includeFrame bool) ([]geom.Triangle, error) {
 	var pts [][2]float64
 	var constraints []geom.Line
+	compare := compareFor(ctx)
 	{
 		var seen = make(map[[2]float64]bool)
 		for _, pt := range ct.Points {
@@ -47,7 +48,7 @@ func (ct *GeomConstrained) Triangles(ctx context.Context, includeFrame bool) ([]
 			if debug {
 				log.Printf("for (%v)%v lnt: %v", i, ct.Constraints[i], lnt)
 			}
-			if cmp.Float(lnt, 0.0) {
+			if compare.Float(lnt, 0.0) {
 				continue
 			}
 			if !seen[ct.Constraints[i][0]] {
@@ -0,0 +1,281 @@
+package delaunay
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/gdey/errors"
+
+	"github.com/go-spatial/geom"
+	pkg "github.com/go-spatial/geom/cmp"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/triangulate"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay/subdivision"
+)
+
+// ErrConstraintNotInserted is returned when a constraint could not be made
+// an edge of the triangulation.
+type ErrConstraintNotInserted struct {
+	Constraint geom.Line
+	Err        error
+}
+
+func (err ErrConstraintNotInserted) Error() string {
+	return "failed to insert constraint: " + err.Err.Error()
+}
+
+// ConstrainedTriangulator builds a constrained Delaunay triangulation. The
+// constraints (breaklines) are guaranteed to be edges of the triangulation.
+// Constraints that cross each other, or that pass through one of the points,
+// are split at those points so that each piece is an edge.
+//
+// Unlike GeomConstrained the constraints are always inserted, whether or
+// not EnableConstraints is set.
+type ConstrainedTriangulator struct {
+	points    []geom.Point
+	pointData map[geom.Point]interface{}
+
+	constraints    []geom.Line
+	constraintData []interface{}
+
+	sd    *subdivision.Subdivision
+	vxidx subdivision.VertexIndex
+	err   error
+}
+
+var _ triangulate.Constrainer = (*ConstrainedTriangulator)(nil)
+
+// NewConstrainedTriangulator returns a triangulator for the given points and
+// constraints. Either may be nil.
+func NewConstrainedTriangulator(ctx context.Context, pts []geom.Point, constraints []geom.Line) (*ConstrainedTriangulator, error) {
+	ct := new(ConstrainedTriangulator)
+	ct.SetPoints(ctx, pts, nil)
+	if err := ct.AddConstraint(ctx, constraints, nil); err != nil {
+		return nil, err
+	}
+	return ct, nil
+}
+
+// SetPoints sets the points to triangulate, throwing away any existing
+// triangulation. The constraints are kept.
+func (ct *ConstrainedTriangulator) SetPoints(ctx context.Context, pts []geom.Point, data []interface{}) {
+	ct.points = ct.points[:0]
+	ct.pointData = make(map[geom.Point]interface{})
+	ct.sd, ct.vxidx, ct.err = nil, nil, nil
+
+	seen := make(map[geom.Point]bool, len(pts))
+	for i, pt := range pts {
+		pt = roundPoint(pt)
+		if i < len(data) {
+			ct.pointData[pt] = data[i]
+		}
+		if seen[pt] {
+			continue
+		}
+		seen[pt] = true
+		ct.points = append(ct.points, pt)
+	}
+}
+
+// AddConstraint adds the constraints to the triangulation. If the
+// triangulation has already been built it is rebuilt with the new
+// constraints, otherwise they are inserted when it is built.
+func (ct *ConstrainedTriangulator) AddConstraint(ctx context.Context, constraints []geom.Line, data []interface{}) error {
+	if len(data) > len(constraints) {
+		return errors.String("more data elements than constraints")
+	}
+	var added []geom.Line
+	for i, c := range constraints {
+		c = geom.Line{[2]float64(roundPoint(c[0])), [2]float64(roundPoint(c[1]))}
+		if c[0] == c[1] {
+			// zero length constraints are already satisfied by their point.
+			continue
+		}
+		var d interface{} = triangulate.EmptyMetadata
+		if i < len(data) {
+			d = data[i]
+		}
+		ct.constraints = append(ct.constraints, c)
+		ct.constraintData = append(ct.constraintData, d)
+		added = append(added, c)
+	}
+
+	if ct.sd == nil || len(added) == 0 {
+		return nil
+	}
+	// The new constraints may cross or touch the existing ones, so the
+	// triangulation is rebuilt.
+	ct.sd, ct.vxidx = nil, nil
+	return ct.Triangulate(ctx)
+}
+
+// Triangulate builds the triangulation. It is called by Triangles if
+// needed, but can be called directly to get at the error. The points on
+// the constraints are found with the comparator of the context, see
+// cmp.NewContext, or the high precision one if there is none.
+func (ct *ConstrainedTriangulator) Triangulate(ctx context.Context) error {
+	ct.sd, ct.vxidx, ct.err = nil, nil, nil
+
+	segments, pts := splitConstraints(compareFor(ctx), ct.constraints, ct.points)
+	if len(pts) == 0 {
+		return nil
+	}
+
+	// NewForPoints modifies the points it is given.
+	xys := make([][2]float64, len(pts))
+	for i := range pts {
+		xys[i] = [2]float64(pts[i])
+	}
+	sd, err := subdivision.NewForPoints(ctx, xys)
+	if err != nil {
+		ct.err = err
+		return err
+	}
+
+	vxidx := sd.VertexIndex()
+	for _, seg := range segments {
+		if err := ctx.Err(); err != nil {
+			ct.err = err
+			return err
+		}
+		err := sd.InsertConstraint(ctx, vxidx, geom.Point(seg[0]), geom.Point(seg[1]))
+		if err != nil {
+			ct.err = ErrConstraintNotInserted{Constraint: seg, Err: err}
+			return ct.err
+		}
+		// make sure the constraint is now an edge
+		if _, _, exists, _ := subdivision.ResolveStartingEndingEdges(vxidx, geom.Point(seg[0]), geom.Point(seg[1])); !exists {
+			ct.err = ErrConstraintNotInserted{Constraint: seg, Err: errors.String("edge not found after insert")}
+			return ct.err
+		}
+	}
+
+	ct.sd, ct.vxidx = sd, vxidx
+	return nil
+}
+
+// Err returns the error, if any, from the last time the triangulation was
+// built.
+func (ct *ConstrainedTriangulator) Err() error { return ct.err }
+
+// Triangles returns the triangles of the triangulation, building it if
+// needed. If the triangulation could not be built nil is returned, and Err
+// will return the reason.
+func (ct *ConstrainedTriangulator) Triangles(ctx context.Context, includeFrame bool) []geom.Triangle {
+	if ct.sd == nil && ct.err == nil {
+		if err := ct.Triangulate(ctx); err != nil {
+			return nil
+		}
+	}
+	if ct.sd == nil {
+		return nil
+	}
+	triangles, err := ct.sd.Triangles(includeFrame)
+	if err != nil {
+		ct.err = err
+		return nil
+	}
+	tris := make([]geom.Triangle, len(triangles))
+	for i, tri := range triangles {
+		tris[i] = geom.Triangle{
+			[2]float64(tri[0]),
+			[2]float64(tri[1]),
+			[2]float64(tri[2]),
+		}
+	}
+	return tris
+}
+
+// PointData returns the data given for the point in SetPoints.
+func (ct *ConstrainedTriangulator) PointData(pt geom.Point) (interface{}, bool) {
+	d, ok := ct.pointData[roundPoint(pt)]
+	return d, ok
+}
+
+// Constraints returns the constraints and the data given for them, in the
+// order they were added.
+func (ct *ConstrainedTriangulator) Constraints() ([]geom.Line, []interface{}) {
+	return ct.constraints, ct.constraintData
+}
+
+// HasEdge reports whether the line is an edge of the built
+// triangulation.
+func (ct *ConstrainedTriangulator) HasEdge(ln geom.Line) bool {
+	if ct.vxidx == nil {
+		return false
+	}
+	_, _, exists, err := subdivision.ResolveStartingEndingEdges(ct.vxidx, geom.Point(ln[0]), geom.Point(ln[1]))
+	return err == nil && exists
+}
+
+// roundPoint rounds the point the same way the subdivision does.
+func roundPoint(pt geom.Point) geom.Point {
+	return geom.Point{
+		math.Round(pt[0]*subdivision.RoundingFactor) / subdivision.RoundingFactor,
+		math.Round(pt[1]*subdivision.RoundingFactor) / subdivision.RoundingFactor,
+	}
+}
+
+// splitConstraints splits the constraints at the points where they cross
+// each other, and at any of the points that lie on them. It returns the
+// pieces and the points, including the constraint end points and the
+// crossing points. Points are on a constraint if their distance to it is
+// zero to the comparator.
+func splitConstraints(compare pkg.Compare, constraints []geom.Line, points []geom.Point) ([]geom.Line, []geom.Point) {
+	pts := append([]geom.Point(nil), points...)
+	seen := make(map[geom.Point]bool, len(points))
+	for _, pt := range points {
+		seen[pt] = true
+	}
+	addPoint := func(pt geom.Point) {
+		if !seen[pt] {
+			seen[pt] = true
+			pts = append(pts, pt)
+		}
+	}
+
+	// the points on each constraint, other than the end points
+	splits := make([][]geom.Point, len(constraints))
+	for i, c := range constraints {
+		addPoint(geom.Point(c[0]))
+		addPoint(geom.Point(c[1]))
+		for j := i + 1; j < len(constraints); j++ {
+			xpt, ok := planar.SegmentIntersect(c, constraints[j])
+			if !ok {
+				continue
+			}
+			pt := roundPoint(geom.Point(xpt))
+			splits[i] = append(splits[i], pt)
+			splits[j] = append(splits[j], pt)
+			addPoint(pt)
+		}
+	}
+
+	var segments []geom.Line
+	for i, c := range constraints {
+		onLine := splits[i]
+		for _, pt := range pts {
+			if compare.Float(planar.DistanceToLineSegment(pt, geom.Point(c[0]), geom.Point(c[1])), 0) {
+				onLine = append(onLine, pt)
+			}
+		}
+		start := geom.Point(c[0])
+		dist := func(pt geom.Point) float64 {
+			dx, dy := pt[0]-start[0], pt[1]-start[1]
+			return dx*dx + dy*dy
+		}
+		sort.Slice(onLine, func(a, b int) bool { return dist(onLine[a]) < dist(onLine[b]) })
+
+		prev := start
+		for _, pt := range onLine {
+			if pt == prev || pt == geom.Point(c[1]) {
+				continue
+			}
+			segments = append(segments, geom.Line{[2]float64(prev), [2]float64(pt)})
+			prev = pt
+		}
+		segments = append(segments, geom.Line{[2]float64(prev), c[1]})
+	}
+	return segments, pts
+}
@@ -0,0 +1,133 @@
+package delaunay_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay"
+)
+
+func TestConstrainedTriangulator(t *testing.T) {
+	coarse := cmp.New(0.01)
+
+	type tcase struct {
+		Points      []geom.Point
+		Constraints []geom.Line
+		// Edges are expected to be edges of the triangulation
+		Edges []geom.Line
+		// Triangles is the expected number of triangles, without the frame
+		Triangles int
+		// Compare, if set, is carried by the context
+		Compare *cmp.Compare
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctx := context.Background()
+			if tc.Compare != nil {
+				ctx = cmp.NewContext(ctx, *tc.Compare)
+			}
+			ct, err := delaunay.NewConstrainedTriangulator(ctx, tc.Points, tc.Constraints)
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			tris := ct.Triangles(ctx, false)
+			if err := ct.Err(); err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if len(tris) != tc.Triangles {
+				t.Errorf("triangles, expected %v got %v", tc.Triangles, len(tris))
+			}
+			for _, edge := range tc.Edges {
+				if !ct.HasEdge(edge) {
+					t.Errorf("edge %v, expected to be in the triangulation", edge)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {},
+		"non delaunay diagonal": {
+			// the delaunay diagonal of this quad is (5,1)-(5,-1)
+			Points:      []geom.Point{{0, 0}, {10, 0}, {5, 1}, {5, -1}},
+			Constraints: []geom.Line{{{0, 0}, {10, 0}}},
+			Edges:       []geom.Line{{{0, 0}, {10, 0}}},
+			Triangles:   2,
+		},
+		"constraint end points added": {
+			Points:      []geom.Point{{5, 1}, {5, -1}},
+			Constraints: []geom.Line{{{0, 0}, {10, 0}}},
+			Edges:       []geom.Line{{{0, 0}, {10, 0}}},
+			Triangles:   2,
+		},
+		"crossing constraints": {
+			Points: []geom.Point{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+			Constraints: []geom.Line{
+				{{0, 0}, {10, 10}},
+				{{10, 0}, {0, 10}},
+			},
+			Edges: []geom.Line{
+				{{0, 0}, {5, 5}},
+				{{5, 5}, {10, 10}},
+				{{10, 0}, {5, 5}},
+				{{5, 5}, {0, 10}},
+			},
+			Triangles: 4,
+		},
+		"point on constraint": {
+			Points:      []geom.Point{{0, 0}, {5, 0}, {10, 0}, {5, 1}, {5, -1}},
+			Constraints: []geom.Line{{{0, 0}, {10, 0}}},
+			Edges: []geom.Line{
+				{{0, 0}, {5, 0}},
+				{{5, 0}, {10, 0}},
+			},
+			Triangles: 4,
+		},
+		"point near constraint": {
+			// to a coarse comparator the point is on the constraint
+			Points:      []geom.Point{{0, 0}, {5, 0.001}, {10, 0}, {5, 1}, {5, -1}},
+			Constraints: []geom.Line{{{0, 0}, {10, 0}}},
+			Edges: []geom.Line{
+				{{0, 0}, {5, 0.001}},
+				{{5, 0.001}, {10, 0}},
+			},
+			Triangles: 4,
+			Compare:   &coarse,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestConstrainedTriangulatorAddConstraint(t *testing.T) {
+	ctx := context.Background()
+	var ct delaunay.ConstrainedTriangulator
+	ct.SetPoints(ctx, []geom.Point{{0, 0}, {10, 0}, {5, 1}, {5, -1}}, []interface{}{"a"})
+
+	if tris := ct.Triangles(ctx, false); len(tris) != 2 {
+		t.Fatalf("triangles, expected 2 got %v", len(tris))
+	}
+	edge := geom.Line{{0, 0}, {10, 0}}
+	if ct.HasEdge(edge) {
+		t.Fatalf("edge %v, expected not to be in the delaunay triangulation", edge)
+	}
+
+	if err := ct.AddConstraint(ctx, []geom.Line{edge}, []interface{}{"breakline"}); err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	if !ct.HasEdge(edge) {
+		t.Errorf("edge %v, expected to be in the triangulation", edge)
+	}
+
+	if d, ok := ct.PointData(geom.Point{0, 0}); !ok || d != "a" {
+		t.Errorf("point data, expected a got %v", d)
+	}
+	if _, data := ct.Constraints(); len(data) != 1 || data[0] != "breakline" {
+		t.Errorf("constraint data, expected [breakline] got %v", data)
+	}
+}
@@ -0,0 +1,127 @@
+package delaunay
+
+import (
+	"context"
+
+	"github.com/go-spatial/geom"
+)
+
+// Contours returns the lines along which the heights of the points, their
+// Z values, are each of the levels, traced across the Delaunay triangles
+// of the points. The heights are taken to change linearly across each
+// triangle, as they do for a TIN, so the heights of survey points can be
+// contoured without interpolating them onto a grid first.
+//
+// The points are rounded as the subdivision rounds them, and where points
+// are rounded to the same point the height of the first is used. A height
+// equal to a level is taken to be above it. The lines are directed with
+// the higher heights on their left, with y going up, and the lines that
+// close on themselves end with their first point. There is a
+// MultiLineString for each level, empty if the points do not make any
+// triangle.
+func Contours(ctx context.Context, pts []geom.PointZ, levels []float64) ([]geom.MultiLineString, error) {
+	var (
+		xys    = make([]geom.Point, len(pts))
+		height = make(map[geom.Point]float64, len(pts))
+	)
+	for i, pt := range pts {
+		xys[i] = roundPoint(geom.Point{pt[0], pt[1]})
+		if _, ok := height[xys[i]]; !ok {
+			height[xys[i]] = pt[2]
+		}
+	}
+	tris, err := alphaTriangles(ctx, xys)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]geom.MultiLineString, len(levels))
+	for i, level := range levels {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		lines[i] = contourLines(tris, height, level)
+	}
+	return lines, nil
+}
+
+// contourLines returns the lines along which the heights of the counter
+// clockwise triangles are the level.
+func contourLines(tris [][3]geom.Point, height map[geom.Point]float64, level float64) geom.MultiLineString {
+	var (
+		// the crossings of the edges of the triangles, by the edge with
+		// its points in order, and the next crossing along the lines
+		pts    = make(map[[2]geom.Point][2]float64)
+		next   = make(map[[2]geom.Point][2]geom.Point)
+		prev   = make(map[[2]geom.Point]bool)
+		starts [][2]geom.Point
+	)
+	for _, tri := range tris {
+		// going counter clockwise round the triangle, the edge the heights
+		// go below the level at and the edge they come back at, so the
+		// heights above are on the left of the line between them
+		var exit, entry [2]geom.Point
+		crossed := 0
+		for j := range tri {
+			a, b := tri[j], tri[(j+1)%3]
+			above, aboveNext := height[a] >= level, height[b] >= level
+			if above == aboveNext {
+				continue
+			}
+			crossed++
+			e := [2]geom.Point{a, b}
+			if edgeLess([2]geom.Point{b, a}, e) {
+				e = [2]geom.Point{b, a}
+			}
+			// from the first point of the edge, so the triangles on either
+			// side of it find the same point
+			za, zb := height[e[0]], height[e[1]]
+			t := (level - za) / (zb - za)
+			pts[e] = [2]float64{e[0][0] + t*(e[1][0]-e[0][0]), e[0][1] + t*(e[1][1]-e[0][1])}
+			if above {
+				exit = e
+			} else {
+				entry = e
+			}
+		}
+		if crossed == 0 {
+			continue
+		}
+		next[exit] = entry
+		prev[entry] = true
+		starts = append(starts, exit)
+	}
+
+	var (
+		mls     geom.MultiLineString
+		visited = make(map[[2]geom.Point]bool)
+	)
+	trace := func(start [2]geom.Point, closed bool) {
+		var line [][2]float64
+		for e, ok := start, true; ok && !visited[e]; e, ok = next[e] {
+			visited[e] = true
+			if pt := pts[e]; len(line) == 0 || line[len(line)-1] != pt {
+				line = append(line, pt)
+			}
+		}
+		if closed && len(line) > 2 {
+			line = append(line, line[0])
+		}
+		if len(line) >= 2 {
+			mls = append(mls, line)
+		}
+	}
+	// the lines that end at the hull, and then those that close on
+	// themselves
+	for _, e := range starts {
+		if !prev[e] {
+			trace(e, false)
+		}
+	}
+	for _, e := range starts {
+		if !visited[e] {
+			trace(e, true)
+		}
+	}
+	return mls
+}
@@ -0,0 +1,113 @@
+package delaunay_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay"
+)
+
+func TestContours(t *testing.T) {
+	type tcase struct {
+		Points []geom.PointZ
+		Level  float64
+		// Lines are the number of points of each of the expected lines
+		Lines []int
+		// Height returns the height at a point of the surface, to check
+		// the lines are at the level, and the higher heights on their left
+		Height func(pt [2]float64) float64
+		// Area is the expected area of the first line, if it is closed
+		Area float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := delaunay.Contours(context.Background(), tc.Points, []float64{tc.Level})
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if len(got) != 1 {
+				t.Fatalf("levels, expected 1 got %v", len(got))
+			}
+			if len(got[0]) != len(tc.Lines) {
+				t.Fatalf("lines, expected %v got %v", len(tc.Lines), len(got[0]))
+			}
+			for i, line := range got[0] {
+				if len(line) != tc.Lines[i] {
+					t.Errorf("line %v points, expected %v got %v", i, tc.Lines[i], len(line))
+				}
+				for j, pt := range line {
+					if h := tc.Height(pt); math.Abs(h-tc.Level) > 1e-9 {
+						t.Errorf("line %v point %v height, expected %v got %v", i, j, tc.Level, h)
+					}
+				}
+				// a little to the left of the middle of the first segment
+				a, b := line[0], line[1]
+				left := [2]float64{(a[0]+b[0])/2 - (b[1]-a[1])*1e-3, (a[1]+b[1])/2 + (b[0]-a[0])*1e-3}
+				if h := tc.Height(left); h <= tc.Level {
+					t.Errorf("line %v height on the left, expected above %v got %v", i, tc.Level, h)
+				}
+			}
+			if tc.Area != 0 {
+				if line := got[0][0]; line[0] != line[len(line)-1] {
+					t.Errorf("line, expected closed got %v", line)
+				}
+				if a := ringArea(got[0][0]); math.Abs(a-tc.Area) > 1e-9 {
+					t.Errorf("area, expected %v got %v", tc.Area, a)
+				}
+			}
+		}
+	}
+
+	// a pyramid 10 high, 10 across
+	pyramid := []geom.PointZ{{0, 0, 0}, {10, 0, 0}, {10, 10, 0}, {0, 10, 0}, {5, 5, 10}}
+	pyramidHeight := func(pt [2]float64) float64 {
+		return 10 - 2*math.Max(math.Abs(pt[0]-5), math.Abs(pt[1]-5))
+	}
+	// a slope along x
+	var slope []geom.PointZ
+	for x := 0; x <= 6; x++ {
+		for y := 0; y <= 6; y++ {
+			slope = append(slope, geom.PointZ{float64(x), float64(y), float64(2 * x)})
+		}
+	}
+	slopeHeight := func(pt [2]float64) float64 { return 2 * pt[0] }
+
+	tests := map[string]tcase{
+		"closed": {
+			Points: pyramid,
+			Level:  5,
+			Lines:  []int{5},
+			Height: pyramidHeight,
+			Area:   25,
+		},
+		"open": {
+			Points: slope,
+			Level:  5,
+			Lines:  []int{13},
+			Height: slopeHeight,
+		},
+		"at the points": {
+			Points: slope,
+			Level:  4,
+			Lines:  []int{7},
+			Height: slopeHeight,
+		},
+		"below": {
+			Points: slope,
+			Level:  -1,
+			Height: slopeHeight,
+		},
+		"too few points": {
+			Points: []geom.PointZ{{0, 0, 0}, {1, 1, 1}},
+			Level:  0.5,
+			Height: slopeHeight,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
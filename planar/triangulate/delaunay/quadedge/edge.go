@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-spatial/geom"
 	"github.com/go-spatial/geom/planar/intersect"
+	"github.com/go-spatial/geom/planar/predicates"
 	"github.com/go-spatial/geom/winding"
 )
 
@@ -20,6 +21,7 @@ type Edge struct {
 	next *Edge
 	qe   *QuadEdge
 	v    *geom.Point
+	data interface{}
 }
 
 // New will return a new edge that is part of an QuadEdge
@@ -43,6 +45,14 @@ func (e *Edge) QEdge() *QuadEdge {
 	return e.qe
 }
 
+// Num returns which of the four directed edges of its quad edge the edge
+// is, see QuadEdge.Edge.
+func (e *Edge) Num() int { return e.num }
+
+// SetONext sets the edge that ONext returns. Unlike Splice it changes no
+// other edge, it is for restoring the topology of a saved subdivision.
+func (e *Edge) SetONext(next *Edge) { e.next = next }
+
 // Orig returns the origin end point
 func (e *Edge) Orig() *geom.Point {
 	if e == nil {
@@ -62,6 +72,24 @@ func (e *Edge) EndPoints(org, dest *geom.Point) {
 	e.Sym().v = dest
 }
 
+// Data returns the value attached to the edge by SetData, nil if there is
+// none.
+func (e *Edge) Data() interface{} {
+	if e == nil {
+		return nil
+	}
+	return e.data
+}
+
+// SetData attaches a value to the edge, such as the id of the constraint
+// the edge is part of, or its weight. Each of the directed edges of a
+// quadedge has its own value; set the value on e.Sym() too for it to be
+// found from either direction. The value stays with the edge when Swap
+// turns it, and is lost when the edge is deleted.
+func (e *Edge) SetData(data interface{}) {
+	e.data = data
+}
+
 // AsLine returns the Edge as a geom.Line
 func (e *Edge) AsLine() geom.Line {
 	porig, pdest := e.Orig(), e.Dest()
@@ -256,17 +284,17 @@ func Validate(e *Edge, order winding.Order) (err1 error) {
 
 	if ed.Sym() != e {
 		// The Sym of Sym should be self
-		err = append(err, "invalid Sym")
+		err = append(err, ErrInvalidAlgebra{Edge: e, Issue: "Sym"})
 	}
 	if ed != e.Sym() {
-		err = append(err, fmt.Sprintf("invalid Rot: left.Rot != e.Sym %p : %p", el, e.Sym()))
+		err = append(err, ErrInvalidAlgebra{Edge: e, Issue: fmt.Sprintf("Rot: left.Rot != e.Sym %p : %p", el, e.Sym())})
 	}
 	if er != el.Sym() {
-		err = append(err, fmt.Sprintf("invalid Rot: rot != e %p : %p", er, el.Sym()))
+		err = append(err, ErrInvalidAlgebra{Edge: e, Issue: fmt.Sprintf("Rot: rot != e %p : %p", er, el.Sym())})
 	}
 
 	if e != el.InvRot() {
-		err = append(err, "invalid Rot: rot != esym.InvRot")
+		err = append(err, ErrInvalidAlgebra{Edge: e, Issue: "Rot: rot != esym.InvRot"})
 	}
 
 	if len(err) != 0 {
@@ -274,40 +302,34 @@ func Validate(e *Edge, order winding.Order) (err1 error) {
 	}
 
 	if e.Orig() == nil {
-		err = append(err, "expected edge to have origin")
+		err = append(err, ErrMissingOrigin{Edge: e})
 		return err
 	}
 
 	orig := *e.Orig()
 	seen := make(map[geom.Point]bool)
 	points := []geom.Point{}
+	edges := []*Edge{}
 	e.WalkAllONext(func(ee *Edge) bool {
 		dest := ee.Dest()
 		if dest == nil {
-			err = append(err, "dest is nil")
+			err = append(err, ErrMissingDest{Edge: ee})
 			return false
 		}
 		if ee.Orig() == nil {
-			err = append(err, "expected edge to have origin")
+			err = append(err, ErrMissingOrigin{Edge: ee})
 			return false
 		}
 		if seen[*dest] {
-			err = append(err, "dest not unique")
-			err = append(err, ee.DumpAllEdges())
+			err = append(err, ErrDuplicateDest{Edge: ee, Dest: *dest})
 			return false
 		}
 		seen[*ee.Dest()] = true
 		points = append(points, *ee.Dest())
+		edges = append(edges, ee)
 
 		if !cmp.GeomPointEqual(*ee.Orig(), orig) {
-			err = append(
-				err,
-				fmt.Sprintf(
-					"expected edge %v to have same origin %v instead of %v",
-					len(points), wkt.MustEncode(orig),
-					wkt.MustEncode(*ee.Orig()),
-				),
-			)
+			err = append(err, ErrDifferentOrigin{Edge: e, Other: ee})
 		}
 		return true
 	})
@@ -324,17 +346,13 @@ func Validate(e *Edge, order winding.Order) (err1 error) {
 
 		for j, i := len(points)-1, 0; i < len(points); j, i = i, i+1 {
 			segs[i] = geom.Line{points[j], points[i]}
-			lpt := [2]float64{
-				points[j][0] - orig[0],
-				points[j][1] - orig[1],
-			}
 			pt := [2]float64{
 				points[i][0] - orig[0],
 				points[i][1] - orig[1],
 			}
 
 			npoints[i] = geom.Point(pt)
-			xprdSum += sign(xprd(lpt, pt))
+			xprdSum += sign(predicates.Orient2D(orig, points[j], points[i]))
 		}
 
 		switch sign(xprdSum) {
@@ -343,22 +361,12 @@ func Validate(e *Edge, order winding.Order) (err1 error) {
 			// Need to check winding order with original point
 			// not enough information just using the outer points, we need to include the origin
 			if !order.OfGeomPoints(append(points, orig)...).IsCounterClockwise() {
-				err = append(err,
-					fmt.Sprintf("1. expected all points to be counter-clockwise: %v:%v\n%v",
-						wkt.MustEncode(orig),
-						wkt.MustEncode(points),
-						wkt.MustEncode(segs),
-					))
+				err = append(err, ErrWrongWinding{Edge: e, Dests: points})
 			}
 		case -1: //counter-clockwise
 
 		case 1: // clockwise
-			err = append(err, fmt.Sprintf("2. expected all points to be counter-clockwise: %v:%v\n%v\n%v",
-				wkt.MustEncode(orig),
-				wkt.MustEncode(points),
-				wkt.MustEncode(segs),
-				e.DumpAllEdges(),
-			))
+			err = append(err, ErrWrongWinding{Edge: e, Dests: points})
 
 		}
 
@@ -377,12 +385,7 @@ func Validate(e *Edge, order winding.Order) (err1 error) {
 				}
 				// the second point in each segment should be the vertex we care about.
 				// this is because of the way we build up the segments above.
-				err = append(err,
-					fmt.Sprintf("found self interstion for vertices %v and %v",
-						wkt.MustEncode(*segs[src].Point2()),
-						wkt.MustEncode(*segs[dest].Point2()),
-					),
-				)
+				err = append(err, ErrCrossingEdge{E1: edges[src], E2: edges[dest], Point: gpt})
 				return err
 			},
 		)
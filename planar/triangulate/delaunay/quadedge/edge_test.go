@@ -2,6 +2,7 @@ package quadedge
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	"github.com/go-spatial/geom"
@@ -116,6 +117,12 @@ func TestValidate(t *testing.T) {
 				for i, estr := range err {
 					t.Logf("error %v: %v", i, estr)
 				}
+			} else {
+				for i := range err {
+					if reflect.TypeOf(err[i]) != reflect.TypeOf(tc.err[i]) {
+						t.Errorf("error %v, expected %T got %T", i, tc.err[i], err[i])
+					}
+				}
 			}
 			if debug {
 				t.Logf("expected errors:")
@@ -134,14 +141,12 @@ func TestValidate(t *testing.T) {
 	tests := []tcase{
 		{
 			desc: "nil edge",
-			err:  ErrInvalid{"expected edge to have origin"},
+			err:  ErrInvalid{ErrMissingOrigin{}},
 		},
 		{
 			desc: "empty dest",
 			edge: NewWithEndPoints(&geom.Point{1, 0}, nil),
-			err: ErrInvalid{
-				"expected edge to have dest",
-			},
+			err:  ErrInvalid{ErrMissingDest{}},
 		},
 		{
 			desc: "one edge",
@@ -153,9 +158,7 @@ func TestValidate(t *testing.T) {
 		{
 			desc: "empty orig",
 			edge: NewWithEndPoints(nil, &geom.Point{1, 0}),
-			err: ErrInvalid{
-				"expected edge to have origin",
-			},
+			err:  ErrInvalid{ErrMissingOrigin{}},
 		},
 		{
 			desc: "bad origins",
@@ -171,9 +174,7 @@ func TestValidate(t *testing.T) {
 				Splice(ed1, ed2)
 				return ed1
 			}(),
-			err: ErrInvalid{
-				"expected edge 3 to have same origin POINT (375 113) instead of POINT (378 113)",
-			},
+			err: ErrInvalid{ErrDifferentOrigin{}},
 		},
 		{
 			desc: "only one edge",
@@ -207,9 +208,7 @@ func TestValidate(t *testing.T) {
 				geom.Point{-2, 0},
 				geom.Point{-2, -1},
 			),
-			err: ErrInvalid{
-				"expected all points to be counter-clockwise: MULTIPOINT (-2 1,-2 0,-2 -1)",
-			},
+			err: ErrInvalid{ErrWrongWinding{}},
 		},
 		{
 			desc: "initial good",
@@ -246,9 +245,7 @@ func TestValidate(t *testing.T) {
 				geom.Point{372, 114},
 				geom.Point{384, 112},
 			),
-			err: ErrInvalid{
-				"expected all points to be counter-clockwise: MULTIPOINT (368 117,384 112,372 114)",
-			},
+			err: ErrInvalid{ErrWrongWinding{}},
 		},
 		{
 			desc: "initial bad, same point",
@@ -257,10 +254,7 @@ func TestValidate(t *testing.T) {
 				geom.Point{368, 114},
 				geom.Point{368, 114},
 			),
-			err: ErrInvalid{
-				"dest not unique",
-				"MULTILINESTRING ((375 113,368 114),(375 113,368 114))",
-			},
+			err: ErrInvalid{ErrDuplicateDest{}},
 		},
 		{
 			desc: "initial good four point",
@@ -281,9 +275,7 @@ func TestValidate(t *testing.T) {
 				geom.Point{1, 0},
 				geom.Point{0, 1},
 			),
-			err: ErrInvalid{
-				"expected all points to be counter-clockwise: MULTIPOINT (-1 0,-1 0,0 -1,1 0,0 1)",
-			},
+			err: ErrInvalid{ErrWrongWinding{}},
 		},
 		{
 			desc: "initial bad four point ",
@@ -294,9 +286,7 @@ func TestValidate(t *testing.T) {
 				geom.Point{376, 119},
 				geom.Point{368, 117},
 			),
-			err: ErrInvalid{
-				"found self interstion for vertics POINT (376 119) and POINT (384 112)",
-			},
+			err: ErrInvalid{ErrCrossingEdge{}},
 		},
 		{
 			desc: "points in counterclockwise order",
@@ -319,9 +309,7 @@ func TestValidate(t *testing.T) {
 				geom.Point{369, 793},
 				geom.Point{475.500, 8853},
 			),
-			err: ErrInvalid{
-				"expected all points to be counter-clockwise: MULTIPOINT (-2511 -3640,-2511 -3640,273 525,426 539,369 793,475.500 8853)",
-			},
+			err: ErrInvalid{ErrWrongWinding{}},
 		},
 	}
 
@@ -330,3 +318,35 @@ func TestValidate(t *testing.T) {
 	}
 
 }
+
+func TestEdgeData(t *testing.T) {
+	a, b, c := geom.Point{0, 0}, geom.Point{1, 0}, geom.Point{0, 1}
+	ab := NewWithEndPoints(&a, &b)
+	ac := NewWithEndPoints(&a, &c)
+
+	if d := ab.Data(); d != nil {
+		t.Errorf("data, expected nil got %v", d)
+	}
+	ab.SetData("constraint 1")
+	ac.SetData(2.5)
+	Splice(ab, ac)
+
+	if d := ab.Data(); d != "constraint 1" {
+		t.Errorf("data, expected %v got %v", "constraint 1", d)
+	}
+	if d := ab.ONext().Data(); d != 2.5 {
+		t.Errorf("next data, expected %v got %v", 2.5, d)
+	}
+	// each direction has its own data
+	if d := ab.Sym().Data(); d != nil {
+		t.Errorf("sym data, expected nil got %v", d)
+	}
+	if d := ab.Rot().Data(); d != nil {
+		t.Errorf("rot data, expected nil got %v", d)
+	}
+
+	var e *Edge
+	if d := e.Data(); d != nil {
+		t.Errorf("nil edge data, expected nil got %v", d)
+	}
+}
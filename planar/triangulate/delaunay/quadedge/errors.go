@@ -1,8 +1,103 @@
 package quadedge
 
-// ErrInvalid is returned when the type is invalid and the reason
-// why it's invalid
-type ErrInvalid []string
+import (
+	"fmt"
+
+	"github.com/go-spatial/geom"
+)
+
+// ErrInvalid is returned when the type is invalid, holding the reasons why
+// it's invalid. Each reason is one of the error types below, with the
+// offending edges attached, so it can be inspected, repaired or drawn.
+type ErrInvalid []error
 
 // Error fullfils the errorer interface
 func (err ErrInvalid) Error() string { return "invalid" }
+
+// ErrInvalidAlgebra is an edge whose Rot and Sym edges are not the edges
+// of the same quadedge.
+type ErrInvalidAlgebra struct {
+	Edge  *Edge
+	Issue string
+}
+
+func (err ErrInvalidAlgebra) Error() string { return "invalid " + err.Issue }
+
+// ErrMissingOrigin is an edge without an origin.
+type ErrMissingOrigin struct {
+	Edge *Edge
+}
+
+func (ErrMissingOrigin) Error() string { return "expected edge to have origin" }
+
+// ErrMissingDest is an edge without a destination.
+type ErrMissingDest struct {
+	Edge *Edge
+}
+
+func (ErrMissingDest) Error() string { return "dest is nil" }
+
+// ErrDuplicateDest is an edge with the same destination as another edge
+// from the same origin.
+type ErrDuplicateDest struct {
+	Edge *Edge
+	Dest geom.Point
+}
+
+func (err ErrDuplicateDest) Error() string {
+	return fmt.Sprintf("dest not unique: %v", wkt.MustEncode(err.Dest))
+}
+
+// ErrDifferentOrigin is an edge in the ONext ring of Edge with a different
+// origin.
+type ErrDifferentOrigin struct {
+	Edge  *Edge
+	Other *Edge
+}
+
+func (err ErrDifferentOrigin) Error() string {
+	return fmt.Sprintf(
+		"expected edge to have same origin %v instead of %v",
+		wkt.MustEncode(*err.Edge.Orig()),
+		wkt.MustEncode(*err.Other.Orig()),
+	)
+}
+
+// ErrWrongWinding is an edge whose ONext ring does not go counter-clockwise
+// around its origin. Dests are the destinations of the ring in ONext
+// order.
+type ErrWrongWinding struct {
+	Edge  *Edge
+	Dests []geom.Point
+}
+
+func (err ErrWrongWinding) Error() string {
+	return fmt.Sprintf("expected all points to be counter-clockwise: %v:%v",
+		wkt.MustEncode(*err.Edge.Orig()),
+		wkt.MustEncode(err.Dests),
+	)
+}
+
+// ErrCrossingEdge is two edges that cross at Point, or, when found by
+// Validate, two edges whose destinations are joined by crossing segments.
+type ErrCrossingEdge struct {
+	E1, E2 *Edge
+	Point  geom.Point
+}
+
+func (err ErrCrossingEdge) Error() string {
+	return fmt.Sprintf("found crossing edges %v and %v at %v",
+		wkt.MustEncode(err.E1.AsLine()),
+		wkt.MustEncode(err.E2.AsLine()),
+		wkt.MustEncode(err.Point),
+	)
+}
+
+// ErrZeroLengthEdge is an edge whose origin and destination are the same.
+type ErrZeroLengthEdge struct {
+	Edge *Edge
+}
+
+func (err ErrZeroLengthEdge) Error() string {
+	return fmt.Sprintf("zero length edge: %v", wkt.MustEncode(err.Edge.AsLine()))
+}
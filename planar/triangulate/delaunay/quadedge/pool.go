@@ -0,0 +1,79 @@
+package quadedge
+
+import (
+	"sync"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/winding"
+)
+
+// blockSize is the number of quadedges a Pool allocates at a time
+const blockSize = 1024
+
+type block [blockSize]QuadEdge
+
+// blocks are the blocks given back by Pool.Free, for other Pools to use
+var blocks = sync.Pool{
+	New: func() interface{} { return new(block) },
+}
+
+// Pool allocates quadedges in blocks, rather than one at a time, so
+// building large subdivisions makes fewer allocations. The quadedges live
+// until Free is called, deleting an edge does not free its quadedge. The
+// zero value is ready to use, a nil Pool allocates each quadedge on its
+// own as NewQEdge does. A Pool is not safe for concurrent use.
+type Pool struct {
+	blocks []*block
+	// used is the number of quadedges used of the last block
+	used int
+}
+
+// NewQEdge returns a new quad edge object from the pool.
+func (p *Pool) NewQEdge() *QuadEdge {
+	if p == nil {
+		return NewQEdge()
+	}
+	if len(p.blocks) == 0 || p.used == blockSize {
+		p.blocks = append(p.blocks, blocks.Get().(*block))
+		p.used = 0
+	}
+	qe := &p.blocks[len(p.blocks)-1][p.used]
+	p.used++
+	qe.init()
+	return qe
+}
+
+// New returns a new edge that is part of a QuadEdge from the pool.
+func (p *Pool) New() *Edge {
+	return &p.NewQEdge().e[0]
+}
+
+// NewWithEndPoints returns a new edge from the pool with the given end
+// points.
+func (p *Pool) NewWithEndPoints(a, b *geom.Point) *Edge {
+	e := p.New()
+	e.EndPoints(a, b)
+	return e
+}
+
+// Connect is Connect, with the new edge from the pool.
+func (p *Pool) Connect(a, b *Edge, order winding.Order) *Edge {
+	return connect(p.NewWithEndPoints(a.Dest(), b.Orig()), a, b, order)
+}
+
+// Free gives back the quadedges of the pool to be used by other pools. No
+// edge from the pool may be used after it is freed; the pool can be used
+// again.
+func (p *Pool) Free() {
+	if p == nil {
+		return
+	}
+	for i, b := range p.blocks {
+		// drop the references to the points and data of the edges
+		*b = block{}
+		blocks.Put(b)
+		p.blocks[i] = nil
+	}
+	p.blocks = p.blocks[:0]
+	p.used = 0
+}
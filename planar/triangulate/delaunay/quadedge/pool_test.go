@@ -0,0 +1,39 @@
+package quadedge
+
+import (
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestPool(t *testing.T) {
+	var p Pool
+	a, b := geom.Point{0, 0}, geom.Point{1, 1}
+	var edges []*Edge
+	for i := 0; i < blockSize+1; i++ {
+		edges = append(edges, p.NewWithEndPoints(&a, &b))
+	}
+	if len(p.blocks) != 2 || p.used != 1 {
+		t.Errorf("blocks, expected 2 with 1 used got %v with %v used", len(p.blocks), p.used)
+	}
+	for _, e := range edges {
+		if e.Sym().Sym() != e || e.Rot().Rot() != e.Sym() || e.ONext() != e {
+			t.Fatalf("edge, expected an initialized edge got %v", e)
+		}
+		if *e.Dest() != b {
+			t.Fatalf("dest, expected %v got %v", b, *e.Dest())
+		}
+	}
+
+	p.Free()
+	if len(p.blocks) != 0 || p.used != 0 {
+		t.Errorf("free, expected no blocks got %v", len(p.blocks))
+	}
+
+	// a nil pool allocates each quadedge on its own
+	var np *Pool
+	if e := np.New(); e.Sym().Sym() != e {
+		t.Errorf("nil pool, expected an initialized edge")
+	}
+	np.Free()
+}
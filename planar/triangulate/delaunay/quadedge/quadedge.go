@@ -24,8 +24,14 @@ type QuadEdge struct {
 // NewQEdge create a new quad edge object
 func NewQEdge() *QuadEdge {
 	var qe QuadEdge
+	qe.init()
+	return &qe
+}
+
+// init links the edges of the quad edge object
+func (qe *QuadEdge) init() {
 	qe.e[0].num, qe.e[1].num, qe.e[2].num, qe.e[3].num = 0, 1, 2, 3
-	qe.e[0].qe, qe.e[1].qe, qe.e[2].qe, qe.e[3].qe = &qe, &qe, &qe, &qe
+	qe.e[0].qe, qe.e[1].qe, qe.e[2].qe, qe.e[3].qe = qe, qe, qe, qe
 
 	qe.e[0].next = &(qe.e[0])
 	qe.e[1].next = &(qe.e[3])
@@ -33,6 +39,8 @@ func NewQEdge() *QuadEdge {
 	qe.e[3].next = &(qe.e[1])
 
 	qe.initialized = true
-
-	return &qe
 }
+
+// Edge returns the ith of the four directed edges of the quad edge, 0 is
+// the edge, 1 its Rot, 2 its Sym and 3 its InvRot.
+func (qe *QuadEdge) Edge(i int) *Edge { return &qe.e[i&3] }
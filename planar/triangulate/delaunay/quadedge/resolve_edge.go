@@ -6,6 +6,7 @@ import (
 
 	"github.com/gdey/errors"
 	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/predicates"
 )
 
 const (
@@ -20,11 +21,6 @@ const (
 	ErrCoincidentalEdges = errors.String("coincident edges")
 )
 
-func xprd(ao, bo [2]float64) float64 {
-	// deal with yaxis downward positive
-	return (ao[0] * bo[1]) - (ao[1] * bo[0])
-}
-
 func sign(f float64) float64 {
 	if cmp.Float(f, 0.0) {
 		return 0.0
@@ -140,17 +136,15 @@ func ResolveEdge(gse *Edge, odest geom.Point) (*Edge, error) {
 		return nil, ErrInvalidEndVertex
 
 	}
-	dest := geom.Point{odest[0] - orig[0], odest[1] - orig[1]}
 
 	gse.WalkAllONext(func(e *Edge) bool {
 
 		apt := *e.Dest()
 		bpt := *e.ONext().Dest()
 
-		ao := [2]float64{apt[0] - orig[0], apt[1] - orig[1]}
-		bo := [2]float64{bpt[0] - orig[0], bpt[1] - orig[1]}
-
-		// calculate the cross product of the the dest line each of the edges
+		// calculate the orientation of the dest line to each of the edges,
+		// the same as the cross product of the lines from the origin but
+		// exact
 		//
 		// ccw == 0,1 ->  1,0 == ( 0 * 0 ) - ( 1 * 1 ) == -1   +--
 		//                                                     |⟲
@@ -158,7 +152,9 @@ func ResolveEdge(gse *Edge, odest geom.Point) (*Edge, error) {
 		//                                                     |⟳
 		// cl  == 1,0 -> -1,0 == ( 1 * 0 ) - (-1 * 0 ) ==  0 --+--
 		//                                                      O
-		ab, da, db := xprd(ao, bo), xprd(dest, ao), xprd(dest, bo)
+		ab := predicates.Orient2D(orig, apt, bpt)
+		da := predicates.Orient2D(orig, odest, apt)
+		db := predicates.Orient2D(orig, odest, bpt)
 		ccwab, cwab, zab := ab < 0, ab > 0, ab == 0
 		ccwda, cwda, zda := da < 0, da > 0, da == 0
 		ccwdb, cwdb, zdb := db < 0, db > 0, db == 0
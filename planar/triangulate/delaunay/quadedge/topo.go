@@ -6,6 +6,7 @@ import (
 	"github.com/go-spatial/geom/winding"
 
 	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/predicates"
 
 	"github.com/go-spatial/geom"
 )
@@ -41,11 +42,15 @@ func Splice(a, b *Edge) {
 // left face after the connection is complete.
 // Additionally, the data pointers of the new edge are set.
 func Connect(a, b *Edge, order winding.Order) *Edge {
+	return connect(NewWithEndPoints(a.Dest(), b.Orig()), a, b, order)
+}
+
+// connect is Connect with the new edge e
+func connect(e, a, b *Edge, order winding.Order) *Edge {
 	//const debug = true
 	if debug {
 		log.Printf("\n\n\tConnect\n\n")
 	}
-	e := NewWithEndPoints(a.Dest(), b.Orig())
 	if debug {
 		log.Printf("a: %v", wkt.MustEncode(a.AsLine()))
 		log.Printf("a:LNext(): %v", wkt.MustEncode(a.LNext().AsLine()))
@@ -146,5 +151,13 @@ func RightOf(x geom.Point, e *Edge) bool {
 	if dst == nil {
 		return false
 	}
-	return sign(x.Subtract(*org).CrossProduct(dst.Subtract(*org))) == -1.0
+	return predicates.Orient2D(*org, *dst, x) > 0
+}
+
+// InCircle indicates if the point x is inside or on the circle through the
+// points a, b and c, which may be in either order. It is false if the points
+// are collinear.
+func InCircle(a, b, c, x geom.Point) bool {
+	o := predicates.Orient2D(a, b, c)
+	return o != 0 && predicates.InCircle(a, b, c, x)*o >= 0
 }
@@ -0,0 +1,230 @@
+package subdivision
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/gdey/errors"
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay/quadedge"
+	"github.com/go-spatial/geom/winding"
+)
+
+const (
+	// ErrInvalidEncoding is returned when decoding data that is not an
+	// encoded subdivision
+	ErrInvalidEncoding = errors.String("invalid subdivision encoding")
+
+	// ErrUnsupportedVersion is returned when decoding a subdivision
+	// encoded by a newer version of the format
+	ErrUnsupportedVersion = errors.String("unsupported subdivision encoding version")
+)
+
+// encodingMagic starts every encoded subdivision
+const encodingMagic = "GSSD"
+
+// encodingVersion is the version of the format written by Encode
+const encodingVersion = 1
+
+// noVertex is the vertex index of the edges without an origin, the edges
+// of the dual
+const noVertex = math.MaxUint32
+
+// Encode writes the subdivision, its sites and the topology of its
+// quadedges, in a compact binary format that Decode reads back without
+// triangulating again. The data attached to the edges is not written.
+//
+// All the numbers are little endian. The format is the magic "GSSD", a
+// version byte, a byte that is 1 if the winding order has the y axis
+// positive down, the frame as three pairs of float64, the point count as a uint64, the number of vertices as a uint32
+// followed by the x and y of each as float64s, the number of quadedges as
+// a uint32 followed by the origin vertex index and the ONext edge of each
+// of its four edges as uint32s, and last the starting edge as a uint32. An
+// edge is the index of its quadedge times four plus its Num, and the
+// origin of the edges of the dual is 0xffffffff.
+func (sd *Subdivision) Encode(w io.Writer) error {
+	if sd == nil || sd.startingEdge == nil {
+		return ErrInvalidEncoding
+	}
+
+	var (
+		quads    []*quadedge.QuadEdge
+		quadIdx  = make(map[*quadedge.QuadEdge]uint32)
+		vertices []geom.Point
+		vertIdx  = make(map[geom.Point]uint32)
+	)
+	addQuad := func(qe *quadedge.QuadEdge) {
+		if _, ok := quadIdx[qe]; ok {
+			return
+		}
+		quadIdx[qe] = uint32(len(quads))
+		quads = append(quads, qe)
+	}
+	_ = sd.WalkAllEdges(func(e *quadedge.Edge) error {
+		addQuad(e.QEdge())
+		return nil
+	})
+
+	ref := func(e *quadedge.Edge) (uint32, error) {
+		i, ok := quadIdx[e.QEdge()]
+		if !ok {
+			// an edge of the dual not in the subdivision
+			return 0, ErrInvalidEncoding
+		}
+		return i*4 + uint32(e.Num()), nil
+	}
+	vertex := func(pt *geom.Point) uint32 {
+		if pt == nil {
+			return noVertex
+		}
+		i, ok := vertIdx[*pt]
+		if !ok {
+			i = uint32(len(vertices))
+			vertIdx[*pt] = i
+			vertices = append(vertices, *pt)
+		}
+		return i
+	}
+
+	topo := make([]uint32, 0, len(quads)*8)
+	for _, qe := range quads {
+		for i := 0; i < 4; i++ {
+			e := qe.Edge(i)
+			next, err := ref(e.ONext())
+			if err != nil {
+				return err
+			}
+			topo = append(topo, vertex(e.Orig()), next)
+		}
+	}
+	start, err := ref(sd.startingEdge)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	le := binary.LittleEndian
+	var buf [8]byte
+	putUint32 := func(v uint32) {
+		le.PutUint32(buf[:4], v)
+		bw.Write(buf[:4])
+	}
+	putFloat := func(v float64) {
+		le.PutUint64(buf[:], math.Float64bits(v))
+		bw.Write(buf[:])
+	}
+
+	bw.WriteString(encodingMagic)
+	bw.WriteByte(encodingVersion)
+	var order byte
+	if sd.Order.YPositiveDown {
+		order = 1
+	}
+	bw.WriteByte(order)
+	for _, pt := range sd.frame {
+		putFloat(pt[0])
+		putFloat(pt[1])
+	}
+	le.PutUint64(buf[:], uint64(sd.ptcount))
+	bw.Write(buf[:])
+	putUint32(uint32(len(vertices)))
+	for _, pt := range vertices {
+		putFloat(pt[0])
+		putFloat(pt[1])
+	}
+	putUint32(uint32(len(quads)))
+	for _, v := range topo {
+		putUint32(v)
+	}
+	putUint32(start)
+	return bw.Flush()
+}
+
+// Decode reads a subdivision written by Encode.
+func Decode(r io.Reader) (*Subdivision, error) {
+	br := bufio.NewReader(r)
+	le := binary.LittleEndian
+	var (
+		buf [8]byte
+		err error
+	)
+	read := func(n int) []byte {
+		if err == nil {
+			if _, err = io.ReadFull(br, buf[:n]); err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+		}
+		return buf[:n]
+	}
+	getUint32 := func() uint32 { return le.Uint32(read(4)) }
+	getFloat := func() float64 { return math.Float64frombits(le.Uint64(read(8))) }
+
+	if _, err = io.ReadFull(br, buf[:4]); err != nil || string(buf[:4]) != encodingMagic {
+		return nil, ErrInvalidEncoding
+	}
+	header := read(2)
+	if err != nil {
+		return nil, err
+	}
+	if header[0] != encodingVersion {
+		return nil, ErrUnsupportedVersion
+	}
+
+	sd := &Subdivision{
+		Order: winding.Order{YPositiveDown: header[1] == 1},
+		pool:  new(quadedge.Pool),
+	}
+	for i := range sd.frame {
+		sd.frame[i] = geom.Point{getFloat(), getFloat()}
+	}
+	sd.ptcount = int(le.Uint64(read(8)))
+
+	nvertices := getUint32()
+	if err != nil {
+		return nil, err
+	}
+	// grow the slices as the data is read, so a corrupt count does not
+	// allocate more than there is data for
+	var vertices []geom.Point
+	for i := uint32(0); i < nvertices && err == nil; i++ {
+		vertices = append(vertices, geom.Point{getFloat(), getFloat()})
+	}
+
+	nquads := getUint32()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		edges []*quadedge.Edge
+		topo  []uint32
+	)
+	for i := uint32(0); i < nquads && err == nil; i++ {
+		qe := sd.pool.NewQEdge()
+		for j := 0; j < 4; j++ {
+			edges = append(edges, qe.Edge(j))
+			topo = append(topo, getUint32(), getUint32())
+		}
+	}
+	start := getUint32()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, e := range edges {
+		v, next := topo[2*i], topo[2*i+1]
+		if next >= uint32(len(edges)) || (v != noVertex && v >= uint32(len(vertices))) {
+			return nil, ErrInvalidEncoding
+		}
+		e.SetONext(edges[next])
+		if v != noVertex {
+			e.EndPoints(&vertices[v], e.Dest())
+		}
+	}
+	if start >= uint32(len(edges)) {
+		return nil, ErrInvalidEncoding
+	}
+	sd.startingEdge = edges[start]
+	return sd, nil
+}
@@ -0,0 +1,82 @@
+package subdivision
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	ctx := context.Background()
+	points := make([][2]float64, 200)
+	rnd := rand.New(rand.NewSource(7))
+	for i := range points {
+		points[i] = [2]float64{rnd.Float64() * 1000, rnd.Float64() * 1000}
+	}
+	sd, err := NewForPoints(ctx, points)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	var buff bytes.Buffer
+	if err = sd.Encode(&buff); err != nil {
+		t.Fatalf("encode error, expected nil got %v", err)
+	}
+	encoded := buff.Bytes()
+
+	got, err := Decode(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatalf("decode error, expected nil got %v", err)
+	}
+	if err = got.Validate(ctx); err != nil {
+		t.Errorf("validate, expected nil got %v", err)
+	}
+	if got.frame != sd.frame || got.ptcount != sd.ptcount || got.Order != sd.Order {
+		t.Errorf("header, expected %v %v %v got %v %v %v", sd.frame, sd.ptcount, sd.Order, got.frame, got.ptcount, got.Order)
+	}
+	exp, _ := sd.Triangles(true)
+	tris, _ := got.Triangles(true)
+	if !reflect.DeepEqual(tris, exp) {
+		t.Errorf("triangles, expected %v got %v", exp, tris)
+	}
+
+	// the decoded subdivision can be changed further
+	if !got.InsertSite(geom.Point{500.5, 500.5}) {
+		t.Errorf("insert site, expected true got false")
+	}
+	if err = got.Validate(ctx); err != nil {
+		t.Errorf("validate after insert, expected nil got %v", err)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		version := append([]byte(nil), encoded...)
+		version[4] = 2
+		badEdge := append([]byte(nil), encoded...)
+		for i := len(badEdge) - 4; i < len(badEdge); i++ {
+			badEdge[i] = 0xff
+		}
+		tests := map[string]struct {
+			data []byte
+			err  error
+		}{
+			"magic":     {data: []byte("GSSX"), err: ErrInvalidEncoding},
+			"empty":     {data: nil, err: ErrInvalidEncoding},
+			"version":   {data: version, err: ErrUnsupportedVersion},
+			"truncated": {data: encoded[:len(encoded)-10], err: io.ErrUnexpectedEOF},
+			"bad edge":  {data: badEdge, err: ErrInvalidEncoding},
+		}
+		for name, tc := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := Decode(bytes.NewReader(tc.data))
+				if err != tc.err {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+			})
+		}
+	})
+}
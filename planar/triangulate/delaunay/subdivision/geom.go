@@ -29,6 +29,7 @@ func NewSubdivisionFromGeomLines(lines []geom.Line, order winding.Order) *Subdiv
 	var (
 		indexMap = make(map[geom.Point]*quadedge.Edge)
 		ext      *geom.Extent
+		pool     = new(quadedge.Pool)
 
 		eq *quadedge.Edge
 		oe *quadedge.Edge
@@ -67,22 +68,22 @@ func NewSubdivisionFromGeomLines(lines []geom.Line, order winding.Order) *Subdiv
 			}
 		}
 
-		eq = quadedge.New()
-		eq.EndPoints(&orig, &dest)
-
 		switch {
 		case oe != nil && de != nil:
-			eq = quadedge.Connect(oe.Sym(), de, order)
+			eq = pool.Connect(oe.Sym(), de, order)
 
 		case oe != nil && de == nil:
+			eq = pool.NewWithEndPoints(&orig, &dest)
 			quadedge.Splice(oe, eq)
 			indexMap[dest] = eq.Sym()
 
 		case oe == nil && de != nil:
+			eq = pool.NewWithEndPoints(&orig, &dest)
 			quadedge.Splice(de, eq.Sym())
 			indexMap[orig] = eq
 
 		case oe == nil && de == nil:
+			eq = pool.NewWithEndPoints(&orig, &dest)
 			indexMap[orig] = eq
 			indexMap[dest] = eq.Sym()
 
@@ -98,6 +99,7 @@ func NewSubdivisionFromGeomLines(lines []geom.Line, order winding.Order) *Subdiv
 		},
 		ptcount:      len(indexMap),
 		startingEdge: eq,
+		pool:         pool,
 	}
 	if debug {
 		var str strings.Builder
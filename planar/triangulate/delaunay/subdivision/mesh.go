@@ -0,0 +1,70 @@
+package subdivision
+
+import (
+	"context"
+
+	"github.com/gdey/errors"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/predicates"
+)
+
+// MeshTriangle is a triangle of a subdivision and the triangles next to it.
+type MeshTriangle struct {
+	// Points are the points of the triangle, counter clockwise with y up
+	Points [3]geom.Point
+	// Neighbors are the indexes of the triangles on the other side of each
+	// edge, from Points[i] to Points[(i+1)%3], or -1 if there is none
+	Neighbors [3]int
+}
+
+// Mesh returns the triangles of the subdivision with the indexes of their
+// neighbors, so the triangles can be walked from one to the next, for
+// example to interpolate over them or follow a slope down them. The edges
+// along the outside have no neighbor: the edges of the frame, or when the
+// triangles on the frame are not included the edges of the convex hull.
+func (sd *Subdivision) Mesh(ctx context.Context, includeFrame bool) ([]MeshTriangle, error) {
+	if sd == nil {
+		return nil, errors.String("subdivision is nil")
+	}
+
+	var mesh []MeshTriangle
+	WalkAllTriangles(ctx, sd.startingEdge, func(start, mid, end geom.Point) bool {
+		if IsFramePoint(sd.frame, start, mid, end) && !includeFrame {
+			return true
+		}
+		// the face outside the frame is not a triangle of the mesh
+		if IsFramePoint(sd.frame, start) && IsFramePoint(sd.frame, mid) && IsFramePoint(sd.frame, end) {
+			return true
+		}
+		tri := MeshTriangle{
+			Points:    [3]geom.Point{start, mid, end},
+			Neighbors: [3]int{-1, -1, -1},
+		}
+		if predicates.Orient2D(start, mid, end) < 0 {
+			tri.Points[1], tri.Points[2] = end, mid
+		}
+		mesh = append(mesh, tri)
+		return ctx.Err() == nil
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// the triangle on the left of each edge
+	left := make(map[[2]geom.Point]int, 3*len(mesh))
+	for i, tri := range mesh {
+		for j := range tri.Points {
+			left[[2]geom.Point{tri.Points[j], tri.Points[(j+1)%3]}] = i
+		}
+	}
+	for i := range mesh {
+		pts := mesh[i].Points
+		for j := range pts {
+			if n, ok := left[[2]geom.Point{pts[(j+1)%3], pts[j]}]; ok {
+				mesh[i].Neighbors[j] = n
+			}
+		}
+	}
+	return mesh, nil
+}
@@ -0,0 +1,67 @@
+package subdivision
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-spatial/geom/planar/index/hilbert"
+)
+
+// InsertOrder is the order NewForPoints inserts the points in.
+type InsertOrder uint8
+
+const (
+	// InputOrder inserts the points in the order they are given. Where
+	// four or more points are on a circle the triangles between them
+	// depend on that order, so the same points in a different order may
+	// give different triangles.
+	InputOrder InsertOrder = iota
+	// SortedOrder inserts the points by x then y. Consecutive points are
+	// close to each other, so they are much quicker to locate than points
+	// in a random order.
+	SortedOrder
+	// HilbertOrder inserts the points in the order they are along a
+	// Hilbert curve over their extent, by x then y for the points in the
+	// same cell of the curve. Like SortedOrder consecutive points are close.
+	HilbertOrder
+)
+
+type insertOrderKey struct{}
+
+// WithInsertOrder returns a context carrying the insert order, which is
+// used by NewForPoints and the delaunay functions given the context.
+func WithInsertOrder(ctx context.Context, order InsertOrder) context.Context {
+	return context.WithValue(ctx, insertOrderKey{}, order)
+}
+
+// InsertOrderFromContext returns the insert order carried by the context,
+// or InputOrder if there is none.
+func InsertOrderFromContext(ctx context.Context) InsertOrder {
+	order, _ := ctx.Value(insertOrderKey{}).(InsertOrder)
+	return order
+}
+
+// lessXY orders points by x then y
+func lessXY(a, b [2]float64) bool {
+	if a[0] != b[0] {
+		return a[0] < b[0]
+	}
+	return a[1] < b[1]
+}
+
+// orderPoints returns the points in the order, copying them unless the
+// order is InputOrder.
+func orderPoints(points [][2]float64, order InsertOrder) [][2]float64 {
+	switch order {
+	case SortedOrder:
+		pts := append([][2]float64(nil), points...)
+		sort.Slice(pts, func(i, j int) bool { return lessXY(pts[i], pts[j]) })
+		return pts
+	case HilbertOrder:
+		pts := append([][2]float64(nil), points...)
+		hilbert.SortPoints(pts)
+		return pts
+	default:
+		return points
+	}
+}
@@ -0,0 +1,111 @@
+package subdivision
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestInsertOrder(t *testing.T) {
+	type tcase struct {
+		order InsertOrder
+	}
+
+	// the points of a grid are cocircular in fours, so their triangles
+	// depend on the order they are inserted in
+	var points [][2]float64
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			points = append(points, [2]float64{float64(x * 10), float64(y * 10)})
+		}
+	}
+	// and a duplicate
+	points = append(points, points[9])
+
+	triangles := func(ctx context.Context, pts [][2]float64) [][3]geom.Point {
+		t.Helper()
+		sd, err := NewForPoints(ctx, append([][2]float64(nil), pts...))
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if err = sd.Validate(ctx); err != nil {
+			t.Fatalf("validate, expected nil got %v", err)
+		}
+		tris, err := sd.Triangles(false)
+		if err != nil {
+			t.Fatalf("triangles error, expected nil got %v", err)
+		}
+		return tris
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctx := WithInsertOrder(context.Background(), tc.order)
+			if got := InsertOrderFromContext(ctx); got != tc.order {
+				t.Fatalf("order, expected %v got %v", tc.order, got)
+			}
+			exp := triangles(ctx, points)
+			rnd := rand.New(rand.NewSource(1))
+			for i := 0; i < 10; i++ {
+				shuffled := append([][2]float64(nil), points...)
+				rnd.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+				if got := triangles(ctx, shuffled); !reflect.DeepEqual(got, exp) {
+					t.Fatalf("triangles of shuffle %v, expected %v got %v", i, exp, got)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"sorted":  {order: SortedOrder},
+		"hilbert": {order: HilbertOrder},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	if got := InsertOrderFromContext(context.Background()); got != InputOrder {
+		t.Errorf("default order, expected %v got %v", InputOrder, got)
+	}
+}
+
+// BenchmarkInsertOrder compares building subdivisions with the points in
+// each insert order. Inserting 100k random points in their input order
+// takes seconds, which is why they are not included.
+func BenchmarkInsertOrder(b *testing.B) {
+	orders := []struct {
+		name  string
+		order InsertOrder
+	}{
+		{"input", InputOrder},
+		{"sorted", SortedOrder},
+		{"hilbert", HilbertOrder},
+	}
+	for _, n := range []int{1000, 10000} {
+		points := make([][2]float64, n)
+		rnd := rand.New(rand.NewSource(1))
+		for i := range points {
+			points[i] = [2]float64{rnd.Float64() * 10000, rnd.Float64() * 10000}
+		}
+		for _, o := range orders {
+			ctx := WithInsertOrder(context.Background(), o.order)
+			b.Run(fmt.Sprintf("%d sites %v", n, o.name), func(b *testing.B) {
+				pts := make([][2]float64, n)
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					copy(pts, points)
+					sd, err := NewForPoints(ctx, pts)
+					if err != nil {
+						b.Fatalf("error, expected nil got %v", err)
+					}
+					sd.Free()
+				}
+			})
+		}
+	}
+}
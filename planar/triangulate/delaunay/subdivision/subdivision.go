@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"strings"
 	"sync"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/gdey/errors"
 
 	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/predicates"
 
 	"github.com/go-spatial/geom"
 	"github.com/go-spatial/geom/encoding/wkt"
@@ -30,17 +32,24 @@ type Subdivision struct {
 	vertexIndexLock  sync.RWMutex
 	vertexIndexCache VertexIndex
 	Order            winding.Order
+
+	// Tracer, if not nil, is told of each step changing the subdivision
+	Tracer Tracer
+
+	// pool allocates the quadedges of the subdivision
+	pool *quadedge.Pool
 }
 
 // New initialize a subdivision to the triangle defined by the points a,b,c.
 func New(a, b, c geom.Point) *Subdivision {
-	ea := quadedge.New()
+	pool := new(quadedge.Pool)
+	ea := pool.New()
 	ea.EndPoints(&a, &b)
-	eb := quadedge.New()
+	eb := pool.New()
 	quadedge.Splice(ea.Sym(), eb)
 	eb.EndPoints(&b, &c)
 
-	ec := quadedge.New()
+	ec := pool.New()
 	ec.EndPoints(&c, &a)
 	quadedge.Splice(eb.Sym(), ec)
 	quadedge.Splice(ec.Sym(), ea)
@@ -48,9 +57,25 @@ func New(a, b, c geom.Point) *Subdivision {
 		startingEdge: ea,
 		ptcount:      3,
 		frame:        [3]geom.Point{a, b, c},
+		pool:         pool,
 	}
 }
 
+// Free gives back the memory of the edges of the subdivision, to be used
+// by the subdivisions made after. Neither the subdivision nor any edge or
+// triangle got from it may be used after it is freed. Calling Free is
+// optional, the memory is garbage collected otherwise.
+func (sd *Subdivision) Free() {
+	if sd == nil {
+		return
+	}
+	sd.pool.Free()
+	sd.startingEdge = nil
+	sd.vertexIndexLock.Lock()
+	sd.vertexIndexCache = nil
+	sd.vertexIndexLock.Unlock()
+}
+
 func newFromPointsTestCase(desc string, points [][2]float64, sd *Subdivision) {
 	var strBuf strings.Builder
 
@@ -71,7 +96,10 @@ Desc: %v,
 }
 
 // NewForPoints creates a new subdivision for the given points, the points are
-// sorted and duplicate points are not added
+// rounded and duplicate points are not added. The points are inserted in the
+// InsertOrder carried by ctx, see WithInsertOrder. With SortedOrder or
+// HilbertOrder the subdivision depends only on the distinct rounded points,
+// so the same points given in any order always give the same triangulation.
 func NewForPoints(ctx context.Context, points [][2]float64) (sd *Subdivision, err error) {
 	//	if debug {
 	defer func() {
@@ -87,6 +115,8 @@ func NewForPoints(ctx context.Context, points [][2]float64) (sd *Subdivision, er
 
 	tri := geom.NewTriangleContainingPoints(points...)
 	sd = New(tri[0], tri[1], tri[2])
+	sd.Tracer = TracerFromContext(ctx)
+	sd.trace(StepNew)
 
 	if debug {
 		if err := sd.Validate(ctx); err != nil {
@@ -111,7 +141,7 @@ func NewForPoints(ctx context.Context, points [][2]float64) (sd *Subdivision, er
 	seen[tri[1]] = true
 	seen[tri[2]] = true
 
-	for i, pt := range points {
+	for i, pt := range orderPoints(points, InsertOrderFromContext(ctx)) {
 
 		_ = i
 		if ctx.Err() != nil {
@@ -219,7 +249,7 @@ func (sd *Subdivision) InsertSite(x geom.Point) bool {
 	// Connect the new point to the vertices of the containing
 	// triangle (or quadrilateral, if the new point fell on an
 	// existing edge.)
-	base := quadedge.NewWithEndPoints(e.Orig(), &x)
+	base := sd.pool.NewWithEndPoints(e.Orig(), &x)
 	if debug {
 		log.Printf("Created new base: %v", wkt.MustEncode(base.AsLine()))
 	}
@@ -229,6 +259,7 @@ func (sd *Subdivision) InsertSite(x geom.Point) bool {
 	}
 	quadedge.Splice(base, e)
 	sd.startingEdge = base
+	sd.trace(StepConnect, base)
 	if debug {
 		log.Printf("base edges: %v", base.DumpAllEdges())
 		log.Printf("connecting e[ %v ] to base.Sym[ %v ]",
@@ -237,7 +268,8 @@ func (sd *Subdivision) InsertSite(x geom.Point) bool {
 		)
 	}
 
-	base = quadedge.Connect(e, base.Sym(), sd.Order)
+	base = sd.pool.Connect(e, base.Sym(), sd.Order)
+	sd.trace(StepConnect, base)
 	// reset e
 	e = base.OPrev()
 	if debug {
@@ -257,7 +289,8 @@ func (sd *Subdivision) InsertSite(x geom.Point) bool {
 				wkt.MustEncode(base.Sym().AsLine()),
 			)
 		}
-		base = quadedge.Connect(e, base.Sym(), sd.Order)
+		base = sd.pool.Connect(e, base.Sym(), sd.Order)
+		sd.trace(StepConnect, base)
 		e = base.OPrev()
 		if debug {
 			count++
@@ -291,27 +324,21 @@ func (sd *Subdivision) InsertSite(x geom.Point) bool {
 			log.Printf("e: %v", wkt.MustEncode(e.AsLine()))
 			log.Printf("e.OPrev/t: %v", wkt.MustEncode(t.AsLine()))
 		}
-		crl, err := geom.CircleFromPoints([2]float64(*e.Orig()), [2]float64(*t.Dest()), [2]float64(*e.Dest()))
-		containsPoint := false
-		if err == nil {
-			containsPoint = crl.ContainsPoint([2]float64(x))
-		}
 		switch {
 		case quadedge.RightOf(*t.Dest(), e) &&
-			containsPoint:
+			quadedge.InCircle(*e.Orig(), *t.Dest(), *e.Dest(), x):
 			if debug {
-				log.Printf("Circle from points: %v,%v,%v \n%v\n%v",
+				log.Printf("Circle from points: %v,%v,%v",
 					wkt.MustEncode(*e.Orig()),
 					wkt.MustEncode(*t.Dest()),
 					wkt.MustEncode(*e.Dest()),
-					wkt.MustEncode(crl.AsLineString(100)),
-					containsPoint,
 				)
 				log.Printf("Point of consideration: %v", wkt.MustEncode(x))
 				log.Printf("%v right of %v", wkt.MustEncode(*t.Dest()), wkt.MustEncode(e.AsLine()))
 				log.Printf("Swapping e: %v", wkt.MustEncode(e.AsLine()))
 			}
 			quadedge.Swap(e)
+			sd.trace(StepSwap, e)
 			if debug {
 				log.Printf("e: %v", wkt.MustEncode(e.AsLine()))
 				log.Printf("e.OPrev: %v", wkt.MustEncode(e.OPrev().AsLine()))
@@ -331,6 +358,7 @@ func (sd *Subdivision) InsertSite(x geom.Point) bool {
 				}
 				DumpSubdivision(sd)
 			}
+			sd.trace(StepInsertSite, sd.startingEdge)
 			return true
 
 		default: // pop a suspect edge
@@ -340,6 +368,174 @@ func (sd *Subdivision) InsertSite(x geom.Point) bool {
 	}
 }
 
+// RemoveSite will remove the point from a subdivision representing a Delaunay
+// triangulation, and retriangulate the hole it leaves so that the result is
+// still a Delaunay triangulation. It returns false if the point is not in the
+// subdivision, or is one of the points of the frame. Together with
+// InsertSite, this allows a triangulation to be kept up to date as points
+// come and go, without building it again.
+//
+// The edges around the point are flipped away one at a time until only three
+// are left, which are then deleted. Each flip makes a triangle from the
+// point's neighbors that has none of the other neighbors inside its
+// circumcircle, so the new triangles are Delaunay.
+func (sd *Subdivision) RemoveSite(x geom.Point) bool {
+	if IsFramePoint(sd.frame, x) {
+		return false
+	}
+	// locate can not start from an edge at x
+	e, got := sd.startingEdge, true
+	if !ptEqual(x, e.Orig()) && !ptEqual(x, e.Dest()) {
+		e, got = sd.locate(x)
+	}
+	switch {
+	case !got:
+		return false
+	case ptEqual(x, e.Orig()):
+	case ptEqual(x, e.Dest()):
+		e = e.Sym()
+	default:
+		return false
+	}
+
+	for {
+		var es []*quadedge.Edge
+		e.WalkAllONext(func(ee *quadedge.Edge) bool {
+			es = append(es, ee)
+			return true
+		})
+		if len(es) <= 3 {
+			// the last triangle, make sure the starting edge is not one of
+			// the edges being deleted.
+			sd.startingEdge = es[0].LNext()
+			for _, ee := range es {
+				sd.trace(StepDelete, ee)
+				quadedge.Delete(ee)
+			}
+			sd.ptcount--
+			sd.trace(StepRemoveSite)
+			return true
+		}
+
+		// the sign of the orientation of the points around x, so the tests
+		// below do not depend on which way ONext goes
+		var area float64
+		for i := range es {
+			a, b := *es[i].Dest(), *es[(i+1)%len(es)].Dest()
+			area += a[0]*b[1] - b[0]*a[1]
+		}
+		sign := 1.0
+		if area < 0 {
+			sign = -1
+		}
+
+		best := -1
+		// x may only be on p,n if there is no other choice, as when x is in
+		// the middle of four points in a diamond
+		for _, onPN := range [2]bool{false, true} {
+			for i := range es {
+				if isEar(es, i, x, sign, onPN) {
+					best = i
+					break
+				}
+			}
+			if best >= 0 {
+				break
+			}
+		}
+		if best < 0 {
+			return false
+		}
+		e = es[(best+1)%len(es)]
+		quadedge.Swap(es[best])
+		sd.trace(StepSwap, es[best])
+	}
+}
+
+// isEar reports if the edge es[i] from x to v can be flipped to the edge
+// from p to n, the points before and after v around x, giving a triangle
+// p,v,n that is in the Delaunay triangulation of the points around x. The
+// triangle must be convex, have x on the other side of p,n, and have none
+// of the other points inside its circumcircle.
+func isEar(es []*quadedge.Edge, i int, x geom.Point, sign float64, onPN bool) bool {
+	prev, next := (i+len(es)-1)%len(es), (i+1)%len(es)
+	p, v, n := *es[prev].Dest(), *es[i].Dest(), *es[next].Dest()
+	if sign*predicates.Orient2D(p, v, n) <= 0 {
+		return false
+	}
+	if o := sign * predicates.Orient2D(p, n, x); o < 0 || (o == 0 && !onPN) {
+		return false
+	}
+	for j, e := range es {
+		if j == prev || j == i || j == next {
+			continue
+		}
+		if sign*predicates.InCircle(p, v, n, *e.Dest()) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// LocateNearest returns the site of the subdivision nearest to x, and false
+// if there are no sites. Points of the frame are not sites.
+//
+// The triangle containing x is located, then from one of its corners the
+// walk moves to whichever neighbor is nearer to x until none is; in a
+// Delaunay triangulation that corner is the nearest site. This takes
+// expected O(sqrt n) steps. If x is far enough outside the sites that a
+// point of the frame is nearer, all the sites are checked instead.
+func (sd *Subdivision) LocateNearest(x geom.Point) (geom.Point, bool) {
+	if sd == nil || sd.startingEdge == nil {
+		return geom.Point{}, false
+	}
+	distSq := func(pt *geom.Point) float64 {
+		dx, dy := pt[0]-x[0], pt[1]-x[1]
+		return dx*dx + dy*dy
+	}
+
+	e, got := sd.startingEdge, true
+	if !ptEqual(x, e.Orig()) && !ptEqual(x, e.Dest()) {
+		// locate can not start from an edge at x
+		e, got = sd.locate(x)
+	}
+	if got && e != nil {
+		best := distSq(e.Orig())
+		for moved := true; moved; {
+			moved = false
+			e.WalkAllONext(func(ee *quadedge.Edge) bool {
+				if d := distSq(ee.Dest()); d < best {
+					best, e, moved = d, ee.Sym(), true
+					// start again around the new vertex
+					return false
+				}
+				return true
+			})
+		}
+		if !IsFramePoint(sd.frame, *e.Orig()) {
+			return *e.Orig(), true
+		}
+	}
+
+	var (
+		nearest geom.Point
+		best    = math.Inf(1)
+		found   bool
+	)
+	_ = sd.WalkAllEdges(func(ee *quadedge.Edge) error {
+		for _, pt := range [2]*geom.Point{ee.Orig(), ee.Dest()} {
+			if IsFramePoint(sd.frame, *pt) {
+				continue
+			}
+			if d := distSq(pt); d < best {
+				nearest, best, found = *pt, d, true
+			}
+		}
+		return nil
+	})
+	return nearest, found
+}
+
 // WalkAllEdges will call the provided function for each edge in the subdivision. The walk will
 // be terminated if the function returns an error or ErrCancel. ErrCancel will not result in
 // an error be returned by main function, otherwise the error will be passed on.
@@ -376,6 +572,7 @@ func (sd *Subdivision) Validate(ctx context.Context) error {
 
 	var (
 		lines []geom.Line
+		edges []*quadedge.Edge
 		err1  quadedge.ErrInvalid
 	)
 
@@ -383,11 +580,6 @@ func (sd *Subdivision) Validate(ctx context.Context) error {
 		l := e.AsLine()
 		if err := quadedge.Validate(e, sd.Order); err != nil {
 			if verr, ok := err.(quadedge.ErrInvalid); ok {
-				wktStr, wktErr := wkt.EncodeString(l)
-				if wktErr != nil {
-					wktStr = wktErr.Error()
-				}
-				err1 = append(err1, fmt.Sprintf("edge: %v", wktStr))
 				err1 = append(err1, verr...)
 				return err1
 			}
@@ -395,10 +587,11 @@ func (sd *Subdivision) Validate(ctx context.Context) error {
 		}
 		l2 := l.LengthSquared()
 		if l2 == 0 {
-			err1 = append(err1, "zero length edge: %v", wkt.MustEncode(l))
+			err1 = append(err1, quadedge.ErrZeroLengthEdge{Edge: e})
 			return err1
 		}
 		lines = append(lines, l)
+		edges = append(edges, e)
 		return nil
 	}); err != nil {
 		return err
@@ -406,8 +599,8 @@ func (sd *Subdivision) Validate(ctx context.Context) error {
 
 	// Check for intersecting lines
 	eq := intersect.NewEventQueue(lines)
-	if err := eq.FindIntersects(ctx, true, func(i, j int, _ [2]float64) error {
-		err1 = append(err1, fmt.Sprintf("found intersecting lines: \n%v\n%v", wkt.MustEncode(lines[i]), wkt.MustEncode(lines[j])))
+	if err := eq.FindIntersects(ctx, true, func(i, j int, pt [2]float64) error {
+		err1 = append(err1, quadedge.ErrCrossingEdge{E1: edges[i], E2: edges[j], Point: geom.Point(pt)})
 		return err1
 	}); err != nil {
 		return err
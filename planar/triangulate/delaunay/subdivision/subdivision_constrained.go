@@ -163,6 +163,7 @@ testcase:
 		}
 
 		vertexIndex.Remove(e)
+		sd.trace(StepDelete, e)
 		quadedge.Delete(e)
 
 	}
@@ -214,6 +215,13 @@ testcase:
 		}
 	}
 
+	if sd.Tracer != nil {
+		if e, ok := vertexIndex.Get(start); ok {
+			if e = e.FindONextDest(end); e != nil {
+				sd.trace(StepInsertConstraint, e)
+			}
+		}
+	}
 	return nil
 }
 
@@ -285,7 +293,8 @@ func (sd *Subdivision) insertEdge(vertexIndex VertexIndex, start, end geom.Point
 		return ErrInvalidEndVertex
 	}
 
-	newEdge := quadedge.Connect(from.ONext().Sym(), to, sd.Order)
+	newEdge := sd.pool.Connect(from.ONext().Sym(), to, sd.Order)
+	sd.trace(StepConnect, newEdge)
 
 	if debug {
 		log.Printf("Connected : %v -> %v", from.ONext().Sym().AsLine(), to.AsLine())
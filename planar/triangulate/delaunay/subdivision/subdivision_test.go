@@ -3,10 +3,13 @@ package subdivision
 import (
 	"context"
 	"fmt"
+	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/go-spatial/geom/encoding/wkt"
 
+	"github.com/go-spatial/geom/planar/predicates"
 	"github.com/go-spatial/geom/planar/triangulate/delaunay/quadedge"
 	"github.com/go-spatial/geom/planar/triangulate/delaunay/test/must"
 
@@ -196,3 +199,291 @@ func TestNewForPoints(t *testing.T) {
 		t.Run(tc.Desc, fn(tc))
 	}
 }
+
+// TestNewForPointsDegenerate triangulates collinear and cocircular points,
+// checking the result is a Delaunay triangulation
+func TestNewForPointsDegenerate(t *testing.T) {
+	grid := func(x, y, step float64, n int) [][2]float64 {
+		var pts [][2]float64
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				pts = append(pts, [2]float64{x + float64(i)*step, y + float64(j)*step})
+			}
+		}
+		return pts
+	}
+	circle := func(x, y, r float64, n int) [][2]float64 {
+		var pts [][2]float64
+		for i := 0; i < n; i++ {
+			s, c := math.Sincos(2 * math.Pi * float64(i) / float64(n))
+			pts = append(pts, [2]float64{x + r*c, y + r*s})
+		}
+		return pts
+	}
+
+	tests := map[string][][2]float64{
+		"grid":             grid(0, 0, 1, 8),
+		"offset grid":      grid(1e7, 1e7, 1, 8),
+		"fine offset grid": grid(19361630.4180414, 6935925.51090632, 0.001, 6),
+		"circle":           circle(0, 0, 10, 24),
+		"offset circle":    circle(1.9e7, 6.9e6, 50, 32),
+		"grid and circle":  append(grid(-5, -5, 2, 6), circle(0, 0, 5, 16)...),
+	}
+
+	for name, pts := range tests {
+		pts := pts
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			sd, err := NewForPoints(ctx, pts)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if err = sd.Validate(ctx); err != nil {
+				t.Fatalf("validate, expected nil got %v", err)
+			}
+			tris, err := sd.Triangles(false)
+			if err != nil {
+				t.Fatalf("triangles, expected nil got %v", err)
+			}
+			// no point may be inside the circumcircle of a triangle
+			for _, tri := range tris {
+				a, b, c := tri[0], tri[1], tri[2]
+				o := predicates.Orient2D(a, b, c)
+				for _, pt := range pts {
+					if predicates.InCircle(a, b, c, pt)*o > 0 {
+						t.Fatalf("%v is inside the circumcircle of %v", pt, tri)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestRemoveSite removes points one at a time from a triangulation,
+// checking it is still a Delaunay triangulation of the points left
+func TestRemoveSite(t *testing.T) {
+	ctx := context.Background()
+	rnd := rand.New(rand.NewSource(1))
+	pts := make([][2]float64, 60)
+	for i := range pts {
+		pts[i] = [2]float64{rnd.Float64() * 100, rnd.Float64() * 100}
+	}
+	// include some points on a grid, for cocircular points
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 5; j++ {
+			pts = append(pts, [2]float64{float64(20 + 10*i), float64(20 + 10*j)})
+		}
+	}
+	sd, err := NewForPoints(ctx, pts)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	if sd.RemoveSite(geom.Point{-1, -1}) {
+		t.Errorf("remove point not in the subdivision, expected false got true")
+	}
+	if sd.RemoveSite(sd.frame[0]) {
+		t.Errorf("remove frame point, expected false got true")
+	}
+
+	for step := 1; len(pts) > 3; step++ {
+		i := rnd.Intn(len(pts))
+		removed := geom.Point(pts[i])
+		pts = append(pts[:i], pts[i+1:]...)
+		if !sd.RemoveSite(removed) {
+			t.Fatalf("remove %v, expected true got false", removed)
+		}
+		if err = sd.Validate(ctx); err != nil {
+			t.Fatalf("validate after removing %v, expected nil got %v", removed, err)
+		}
+		tris, err := sd.Triangles(true)
+		if err != nil {
+			t.Fatalf("triangles, expected nil got %v", err)
+		}
+		for _, tri := range tris {
+			a, b, c := tri[0], tri[1], tri[2]
+			if a == removed || b == removed || c == removed {
+				t.Fatalf("triangle %v, expected %v to be removed", tri, removed)
+			}
+			if IsFramePoint(sd.frame, a, b, c) {
+				continue
+			}
+			o := predicates.Orient2D(a, b, c)
+			for _, pt := range pts {
+				if predicates.InCircle(a, b, c, pt)*o > 0 {
+					t.Fatalf("after removing %v, %v is inside the circumcircle of %v", removed, pt, tri)
+				}
+			}
+		}
+
+		// put some of the points back
+		if step%5 == 0 {
+			if !sd.InsertSite(removed) {
+				t.Fatalf("insert %v, expected true got false", removed)
+			}
+			pts = append(pts, [2]float64(removed))
+		}
+	}
+}
+
+func TestLocateNearest(t *testing.T) {
+	ctx := context.Background()
+	rnd := rand.New(rand.NewSource(1))
+	pts := make([][2]float64, 200)
+	for i := range pts {
+		pts[i] = [2]float64{rnd.Float64() * 100, rnd.Float64() * 100}
+	}
+	sd, err := NewForPoints(ctx, pts)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	bruteForce := func(x geom.Point) float64 {
+		best := math.Inf(1)
+		for _, pt := range pts {
+			best = math.Min(best, math.Hypot(pt[0]-x[0], pt[1]-x[1]))
+		}
+		return best
+	}
+
+	// points inside, outside and on the sites
+	queries := []geom.Point{pts[0], pts[199], {-1e6, 50}, {1e9, 1e9}}
+	for i := 0; i < 500; i++ {
+		queries = append(queries, geom.Point{rnd.Float64()*300 - 100, rnd.Float64()*300 - 100})
+	}
+	for _, x := range queries {
+		got, ok := sd.LocateNearest(x)
+		if !ok {
+			t.Fatalf("nearest to %v, expected ok got false", x)
+		}
+		// compare distances, there may be more than one nearest site
+		if exp, d := bruteForce(x), math.Hypot(got[0]-x[0], got[1]-x[1]); d != exp {
+			t.Errorf("nearest to %v, expected distance %v got %v (%v)", x, exp, d, got)
+		}
+	}
+
+	empty := New(geom.Point{0, 0}, geom.Point{10, 0}, geom.Point{0, 10})
+	if got, ok := empty.LocateNearest(geom.Point{1, 1}); ok {
+		t.Errorf("nearest in empty subdivision, expected false got %v", got)
+	}
+}
+
+func TestMesh(t *testing.T) {
+	ctx := context.Background()
+	rnd := rand.New(rand.NewSource(1))
+	pts := make([][2]float64, 100)
+	for i := range pts {
+		pts[i] = [2]float64{rnd.Float64() * 100, rnd.Float64() * 100}
+	}
+	sd, err := NewForPoints(ctx, pts)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	for _, includeFrame := range []bool{true, false} {
+		mesh, err := sd.Mesh(ctx, includeFrame)
+		if err != nil {
+			t.Fatalf("mesh error, expected nil got %v", err)
+		}
+		// by Euler's formula, for the points and the frame, whose three
+		// edges are on the outside
+		expected := 2*(len(pts)+3) - 3 - 2
+		if !includeFrame {
+			tris, _ := sd.Triangles(false)
+			expected = len(tris)
+		}
+		if len(mesh) != expected {
+			t.Errorf("triangles, expected %v got %v", expected, len(mesh))
+		}
+
+		outside := 0
+		for i, tri := range mesh {
+			if predicates.Orient2D(tri.Points[0], tri.Points[1], tri.Points[2]) <= 0 {
+				t.Errorf("triangle %v, expected counter clockwise got %v", i, tri.Points)
+			}
+			for j, n := range tri.Neighbors {
+				if n == -1 {
+					outside++
+					continue
+				}
+				// the neighbor has the same edge the other way, and this
+				// triangle as its neighbor across it
+				a, b := tri.Points[j], tri.Points[(j+1)%3]
+				found := false
+				for k, pt := range mesh[n].Points {
+					if pt == b && mesh[n].Points[(k+1)%3] == a {
+						found = mesh[n].Neighbors[k] == i
+					}
+				}
+				if !found {
+					t.Errorf("triangle %v neighbor %v, expected to share the edge %v %v", i, n, a, b)
+				}
+			}
+		}
+		// with the frame only the outside of the frame has no neighbors
+		if includeFrame && outside != 3 {
+			t.Errorf("edges without neighbors, expected 3 got %v", outside)
+		}
+	}
+
+	var nilSD *Subdivision
+	if _, err := nilSD.Mesh(ctx, false); err == nil {
+		t.Errorf("nil subdivision error, expected an error got nil")
+	}
+}
+
+func TestFree(t *testing.T) {
+	points := make([][2]float64, 100)
+	rnd := rand.New(rand.NewSource(1))
+	for i := range points {
+		points[i] = [2]float64{rnd.Float64() * 100, rnd.Float64() * 100}
+	}
+	sd, err := NewForPoints(context.Background(), points)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	sd.Free()
+	if sd.startingEdge != nil {
+		t.Errorf("starting edge, expected nil got %v", sd.startingEdge)
+	}
+
+	// the freed quadedges are used by the next subdivision
+	sd, err = NewForPoints(context.Background(), points)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if err = sd.Validate(context.Background()); err != nil {
+		t.Errorf("validate, expected nil got %v", err)
+	}
+}
+
+func benchmarkNewForPoints(b *testing.B, n int, free bool) {
+	points := make([][2]float64, n)
+	rnd := rand.New(rand.NewSource(1))
+	for i := range points {
+		points[i] = [2]float64{rnd.Float64() * 10000, rnd.Float64() * 10000}
+	}
+	pts := make([][2]float64, n)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(pts, points)
+		sd, err := NewForPoints(context.Background(), pts)
+		if err != nil {
+			b.Fatalf("error, expected nil got %v", err)
+		}
+		if free {
+			sd.Free()
+		}
+	}
+}
+
+// BenchmarkNewForPoints compares building subdivisions which are left to
+// the garbage collector with ones whose edges are freed for reuse. The 1M
+// site inputs take a while, run them with -benchtime=1x.
+func BenchmarkNewForPoints(b *testing.B) {
+	for _, n := range []int{10000, 100000, 1000000} {
+		b.Run(fmt.Sprintf("%d sites", n), func(b *testing.B) { benchmarkNewForPoints(b, n, false) })
+		b.Run(fmt.Sprintf("%d sites freed", n), func(b *testing.B) { benchmarkNewForPoints(b, n, true) })
+	}
+}
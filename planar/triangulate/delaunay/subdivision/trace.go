@@ -0,0 +1,133 @@
+package subdivision
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/wkt"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay/quadedge"
+)
+
+// Step is a step taken building or changing a subdivision.
+type Step string
+
+const (
+	// StepNew is the subdivision of the frame, before any sites are added
+	StepNew Step = "new"
+	// StepConnect is an edge connecting a site to the subdivision
+	StepConnect Step = "connect"
+	// StepSwap is an edge swapped to keep the subdivision Delaunay
+	StepSwap Step = "swap"
+	// StepDelete is an edge about to be deleted
+	StepDelete Step = "delete"
+	// StepInsertSite is a site inserted, with an edge from it
+	StepInsertSite Step = "insert site"
+	// StepRemoveSite is a site removed
+	StepRemoveSite Step = "remove site"
+	// StepInsertConstraint is a constraint inserted, with its edge
+	StepInsertConstraint Step = "insert constraint"
+)
+
+// Tracer is told of each step taken building or changing a subdivision,
+// with the edges the step is about. Tracers can log the steps, or capture
+// them as snapshots or the frames of an animation, without the package
+// being built for debugging. The edges are only valid during the call.
+type Tracer interface {
+	Trace(sd *Subdivision, step Step, edges ...*quadedge.Edge)
+}
+
+// TracerFunc is a function that is a Tracer.
+type TracerFunc func(sd *Subdivision, step Step, edges ...*quadedge.Edge)
+
+// Trace calls fn.
+func (fn TracerFunc) Trace(sd *Subdivision, step Step, edges ...*quadedge.Edge) {
+	fn(sd, step, edges...)
+}
+
+// trace tells the tracer of the subdivision, if it has one, of the step
+func (sd *Subdivision) trace(step Step, edges ...*quadedge.Edge) {
+	if sd.Tracer != nil {
+		sd.Tracer.Trace(sd, step, edges...)
+	}
+}
+
+type tracerKey struct{}
+
+// WithTracer returns a context carrying the tracer, which is used by the
+// subdivisions made by NewForPoints and the delaunay functions given the
+// context.
+func WithTracer(ctx context.Context, t Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, t)
+}
+
+// TracerFromContext returns the tracer carried by the context, or nil if
+// there is none.
+func TracerFromContext(ctx context.Context) Tracer {
+	t, _ := ctx.Value(tracerKey{}).(Tracer)
+	return t
+}
+
+// edgeLines returns the lines of the edges
+func edgeLines(edges []*quadedge.Edge) geom.MultiLineString {
+	lines := make(geom.MultiLineString, 0, len(edges))
+	for _, e := range edges {
+		if e.Orig() == nil || e.Dest() == nil {
+			continue
+		}
+		ln := e.AsLine()
+		lines = append(lines, ln[:])
+	}
+	return lines
+}
+
+// LogTracer returns a Tracer that logs each step, with its edges as WKT,
+// to l, or to the standard logger if l is nil.
+func LogTracer(l *log.Logger) Tracer {
+	return TracerFunc(func(_ *Subdivision, step Step, edges ...*quadedge.Edge) {
+		var str strings.Builder
+		str.WriteString(string(step))
+		if len(edges) > 0 {
+			str.WriteString(": ")
+			str.WriteString(wkt.MustEncode(edgeLines(edges)))
+		}
+		if l == nil {
+			log.Print(str.String())
+			return
+		}
+		l.Print(str.String())
+	})
+}
+
+// Snapshot is the subdivision after a step.
+type Snapshot struct {
+	Step Step
+	// Edges are all the edges of the subdivision, including those of the
+	// frame
+	Edges geom.MultiLineString
+	// Highlight are the edges the step is about
+	Highlight geom.MultiLineString
+}
+
+// Recorder is a Tracer that records a Snapshot of the subdivision after
+// each step, such as to make the frames of an animation of the
+// triangulation. Every snapshot holds the whole subdivision, so recording
+// large subdivisions takes a lot of memory.
+type Recorder struct {
+	Snapshots []Snapshot
+}
+
+// Trace records the snapshot of the step.
+func (r *Recorder) Trace(sd *Subdivision, step Step, edges ...*quadedge.Edge) {
+	var all []*quadedge.Edge
+	_ = sd.WalkAllEdges(func(e *quadedge.Edge) error {
+		all = append(all, e)
+		return nil
+	})
+	r.Snapshots = append(r.Snapshots, Snapshot{
+		Step:      step,
+		Edges:     edgeLines(all),
+		Highlight: edgeLines(edges),
+	})
+}
@@ -0,0 +1,53 @@
+package subdivision
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestTracer(t *testing.T) {
+	points := [][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {5, 4}}
+	var rec Recorder
+	ctx := WithTracer(context.Background(), &rec)
+
+	sd, err := NewForPoints(ctx, points)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if len(rec.Snapshots) == 0 || rec.Snapshots[0].Step != StepNew {
+		t.Fatalf("first step, expected %v got %v", StepNew, rec.Snapshots)
+	}
+	if len(rec.Snapshots[0].Edges) != 3 {
+		t.Errorf("frame edges, expected 3 got %v", rec.Snapshots[0].Edges)
+	}
+
+	counts := make(map[Step]int)
+	for _, s := range rec.Snapshots {
+		counts[s.Step]++
+	}
+	if counts[StepInsertSite] != len(points) {
+		t.Errorf("insert site steps, expected %v got %v", len(points), counts[StepInsertSite])
+	}
+	if counts[StepConnect] < 3*len(points) {
+		t.Errorf("connect steps, expected at least %v got %v", 3*len(points), counts[StepConnect])
+	}
+	last := rec.Snapshots[len(rec.Snapshots)-1]
+	if last.Step != StepInsertSite || len(last.Highlight) != 1 {
+		t.Errorf("last step, expected %v of one edge got %v of %v", StepInsertSite, last.Step, last.Highlight)
+	}
+
+	var buff bytes.Buffer
+	sd.Tracer = LogTracer(log.New(&buff, "", 0))
+	if !sd.RemoveSite(geom.Point{5, 4}) {
+		t.Fatalf("remove site, expected true got false")
+	}
+	logged := buff.String()
+	if !strings.Contains(logged, "delete: MULTILINESTRING") || !strings.HasSuffix(logged, "remove site\n") {
+		t.Errorf("log, expected deletes and the site removed got %q", logged)
+	}
+}
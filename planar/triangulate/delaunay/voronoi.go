@@ -0,0 +1,155 @@
+package delaunay
+
+import (
+	"context"
+
+	"github.com/gdey/errors"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay/subdivision"
+)
+
+// ErrNilClipExtent is returned by Voronoi when the clip extent is nil, as
+// the cells on the hull are not bounded.
+const ErrNilClipExtent = errors.String("voronoi: clip extent is nil")
+
+// Voronoi returns the Voronoi cell of each of the points, clipped to the
+// extent. The cells are in the same order as the points, duplicate points
+// get the same cell. A cell that does not intersect the extent is an empty
+// polygon. The cells are wound as the planar.Options of the context say.
+//
+// The cells are built from the dual of the Delaunay triangulation: each cell
+// is the extent cut by the bisectors between the point and its Delaunay
+// neighbors.
+func Voronoi(ctx context.Context, pts []geom.Point, clip *geom.Extent) ([]geom.Polygon, error) {
+	if clip == nil {
+		return nil, ErrNilClipExtent
+	}
+	if len(pts) == 0 {
+		return nil, nil
+	}
+
+	var (
+		sites []geom.Point
+		xys   [][2]float64
+		// the first point for each of the rounded points
+		site = make(map[geom.Point]geom.Point, len(pts))
+	)
+	for _, pt := range pts {
+		rpt := roundPoint(pt)
+		if _, ok := site[rpt]; ok {
+			continue
+		}
+		site[rpt] = pt
+		sites = append(sites, rpt)
+		xys = append(xys, [2]float64(rpt))
+	}
+
+	var neighbors map[geom.Point][]geom.Point
+	if len(sites) > 1 {
+		sd, err := subdivision.NewForPoints(ctx, xys)
+		if err != nil {
+			return nil, err
+		}
+		if neighbors, err = delaunayNeighbors(ctx, sd, site); err != nil {
+			return nil, err
+		}
+	}
+
+	cells := make(map[geom.Point]geom.Polygon, len(sites))
+	for _, rpt := range sites {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		ns, ok := neighbors[rpt]
+		if !ok {
+			// a point on the hull, its neighbors in the triangulation
+			// include the frame, so use all the points.
+			ns = sites
+		}
+		ring := clip.Vertices()
+		for _, n := range ns {
+			if n == rpt {
+				continue
+			}
+			ring = clipToBisector(ring, site[rpt], site[n])
+			if len(ring) == 0 {
+				break
+			}
+		}
+		if len(ring) < 3 {
+			cells[rpt] = geom.Polygon{}
+			continue
+		}
+		cells[rpt] = planar.FromContext(ctx).OrientPolygon(geom.Polygon{ring})
+	}
+
+	ret := make([]geom.Polygon, len(pts))
+	for i, pt := range pts {
+		ret[i] = cells[roundPoint(pt)]
+	}
+	return ret, nil
+}
+
+// delaunayNeighbors returns the neighbors of each of the sites in the
+// subdivision. Sites that are connected to a frame point are left out.
+func delaunayNeighbors(ctx context.Context, sd *subdivision.Subdivision, site map[geom.Point]geom.Point) (map[geom.Point][]geom.Point, error) {
+	neighbors := make(map[geom.Point][]geom.Point, len(site))
+	for rpt, e := range sd.VertexIndex() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if _, ok := site[rpt]; !ok {
+			// frame point
+			continue
+		}
+		var (
+			ns     []geom.Point
+			onHull bool
+		)
+		for ne := e; ; {
+			dest := roundPoint(*ne.Dest())
+			if _, ok := site[dest]; !ok {
+				onHull = true
+				break
+			}
+			ns = append(ns, dest)
+			if ne = ne.ONext(); ne == e {
+				break
+			}
+		}
+		if !onHull {
+			neighbors[rpt] = ns
+		}
+	}
+	return neighbors, nil
+}
+
+// clipToBisector clips the convex ring to the half plane, bounded by the
+// bisector of s and n, that contains s.
+func clipToBisector(ring [][2]float64, s, n geom.Point) [][2]float64 {
+	mx, my := (s[0]+n[0])/2, (s[1]+n[1])/2
+	dx, dy := n[0]-s[0], n[1]-s[1]
+	// side is <= 0 for points closer to s than n
+	side := func(pt [2]float64) float64 {
+		return (pt[0]-mx)*dx + (pt[1]-my)*dy
+	}
+
+	var clipped [][2]float64
+	for i := range ring {
+		cur, next := ring[i], ring[(i+1)%len(ring)]
+		cs, ns := side(cur), side(next)
+		if cs <= 0 {
+			clipped = append(clipped, cur)
+		}
+		if (cs < 0 && ns > 0) || (cs > 0 && ns < 0) {
+			t := cs / (cs - ns)
+			clipped = append(clipped, [2]float64{
+				cur[0] + t*(next[0]-cur[0]),
+				cur[1] + t*(next[1]-cur[1]),
+			})
+		}
+	}
+	return clipped
+}
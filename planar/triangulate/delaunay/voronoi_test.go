@@ -0,0 +1,91 @@
+package delaunay_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay"
+)
+
+func TestVoronoi(t *testing.T) {
+	type tcase struct {
+		Points []geom.Point
+		Clip   *geom.Extent
+		// Areas of the expected cells
+		Areas []float64
+		Err   error
+	}
+
+	area := func(ply geom.Polygon) float64 {
+		if len(ply) == 0 {
+			return 0
+		}
+		var a float64
+		ring := ply[0]
+		for i := range ring {
+			j := (i + 1) % len(ring)
+			a += ring[i][0]*ring[j][1] - ring[j][0]*ring[i][1]
+		}
+		return math.Abs(a / 2)
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			cells, err := delaunay.Voronoi(context.Background(), tc.Points, tc.Clip)
+			if err != tc.Err {
+				t.Fatalf("error, expected %v got %v", tc.Err, err)
+			}
+			if len(cells) != len(tc.Areas) {
+				t.Fatalf("cells, expected %v got %v", len(tc.Areas), len(cells))
+			}
+			for i := range cells {
+				a := area(cells[i])
+				if math.Abs(a-tc.Areas[i]) > 1e-6 {
+					t.Errorf("cell %v area, expected %v got %v", i, tc.Areas[i], a)
+				}
+				if len(cells[i]) != 0 && !tc.Clip.Contains(geom.NewExtent(cells[i][0]...)) {
+					t.Errorf("cell %v, expected to be in the clip extent", i)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"nil clip": {
+			Points: []geom.Point{{0, 0}},
+			Err:    delaunay.ErrNilClipExtent,
+		},
+		"one point": {
+			Points: []geom.Point{{5, 5}},
+			Clip:   &geom.Extent{0, 0, 10, 10},
+			Areas:  []float64{100},
+		},
+		"two points": {
+			Points: []geom.Point{{2, 5}, {8, 5}},
+			Clip:   &geom.Extent{0, 0, 10, 10},
+			Areas:  []float64{50, 50},
+		},
+		"square": {
+			Points: []geom.Point{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {5, 5}},
+			Clip:   &geom.Extent{0, 0, 10, 10},
+			// the center cell is the square rotated 45°, with corners at 3 from the center
+			Areas: []float64{20.5, 20.5, 20.5, 20.5, 18},
+		},
+		"duplicate points": {
+			Points: []geom.Point{{2, 5}, {8, 5}, {2, 5}},
+			Clip:   &geom.Extent{0, 0, 10, 10},
+			Areas:  []float64{50, 50, 50},
+		},
+		"point outside clip": {
+			Points: []geom.Point{{5, 5}, {5, 50}},
+			Clip:   &geom.Extent{0, 0, 10, 10},
+			Areas:  []float64{100, 0},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
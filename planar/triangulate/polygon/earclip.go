@@ -0,0 +1,246 @@
+package polygon
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/predicates"
+)
+
+// EarClip returns the triangles of the polygon found by ear clipping. The
+// holes are first joined to the outer ring by bridges, making one ring,
+// then triangles (ears) are cut off the ring until it is all used up. Only
+// the vertices of the polygon are used, and the triangles are counter
+// clockwise, with y going up, unless the planar.Options of the context say
+// otherwise.
+//
+// ErrInvalidPolygon is returned if a hole is not inside the outer ring, or,
+// with the triangles found so far, if an ear can not be found; which
+// happens if the rings of the polygon cross.
+func EarClip(ctx context.Context, poly geom.Polygon) ([]geom.Triangle, error) {
+	rings := prepareRings(poly)
+	if len(rings) == 0 {
+		return nil, nil
+	}
+	ring, err := bridgeHoles(rings[0], rings[1:])
+	if err != nil {
+		return nil, err
+	}
+	tris, err := clipEars(ctx, ring)
+	return planar.FromContext(ctx).OrientTriangles(tris), err
+}
+
+// bridgeHoles joins each of the holes to the ring, by going from a vertex
+// of the ring to the vertex of the hole with the largest x, around the
+// hole, and back. The vertex of the ring is the nearest one that can be
+// seen from the vertex of the hole. If there is none, the hole is not
+// inside the ring, and ErrInvalidPolygon is returned.
+func bridgeHoles(ring [][2]float64, holes [][][2]float64) ([][2]float64, error) {
+	// start with the holes furthest to the right, so the ones further left
+	// can be joined to them
+	maxX := func(hole [][2]float64) int {
+		m := 0
+		for i := range hole {
+			if hole[i][0] > hole[m][0] || (hole[i][0] == hole[m][0] && hole[i][1] < hole[m][1]) {
+				m = i
+			}
+		}
+		return m
+	}
+	sort.SliceStable(holes, func(i, j int) bool {
+		return holes[i][maxX(holes[i])][0] > holes[j][maxX(holes[j])][0]
+	})
+
+	for h, hole := range holes {
+		m := maxX(hole)
+		mpt := hole[m]
+		// the hole starting from m
+		rotated := append(append([][2]float64{}, hole[m:]...), hole[:m]...)
+
+		candidates := make([]int, len(ring))
+		for i := range candidates {
+			candidates[i] = i
+		}
+		dist := func(i int) float64 { return math.Hypot(ring[i][0]-mpt[0], ring[i][1]-mpt[1]) }
+		sort.SliceStable(candidates, func(a, b int) bool { return dist(candidates[a]) < dist(candidates[b]) })
+
+		bridge := -1
+		for _, i := range candidates {
+			if ring[i] == mpt {
+				// the hole touches the ring
+				bridge = i
+				break
+			}
+			if inCone(ring, i, mpt) && visible(ring[i], mpt, ring, holes[h:]) {
+				bridge = i
+				break
+			}
+		}
+		if bridge < 0 {
+			return nil, ErrInvalidPolygon
+		}
+
+		joined := make([][2]float64, 0, len(ring)+len(hole)+2)
+		joined = append(joined, ring[:bridge+1]...)
+		if ring[bridge] == mpt {
+			joined = append(joined, rotated[1:]...)
+			joined = append(joined, mpt)
+		} else {
+			joined = append(joined, rotated...)
+			joined = append(joined, mpt, ring[bridge])
+		}
+		ring = append(joined, ring[bridge+1:]...)
+	}
+	return ring, nil
+}
+
+// inCone reports if the point is inside the angle of the counter clockwise
+// ring at vertex i, so a segment from the vertex to it starts out inside
+// the ring
+func inCone(ring [][2]float64, i int, pt [2]float64) bool {
+	a, b, c := ring[(i+len(ring)-1)%len(ring)], ring[i], ring[(i+1)%len(ring)]
+	if predicates.Orient2D(a, b, c) >= 0 {
+		// convex
+		return predicates.Orient2D(a, b, pt) > 0 && predicates.Orient2D(b, c, pt) > 0
+	}
+	// reflex
+	return !(predicates.Orient2D(a, b, pt) <= 0 && predicates.Orient2D(b, c, pt) <= 0)
+}
+
+// visible reports if the segment from a to b does not touch any of the
+// edges of the rings, other than at a or b themselves
+func visible(a, b [2]float64, ring [][2]float64, holes [][][2]float64) bool {
+	rings := append([][][2]float64{ring}, holes...)
+	for _, r := range rings {
+		for i := range r {
+			if segmentsTouch(a, b, r[i], r[(i+1)%len(r)]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// segmentsTouch reports if the segment a,b and the edge c,d have a point
+// in common, other than a shared end point
+func segmentsTouch(a, b, c, d [2]float64) bool {
+	if (c == a || c == b) && (d == a || d == b) {
+		// the same segment
+		return true
+	}
+	if c == a || c == b || d == a || d == b {
+		// they share an end, they only touch if they overlap
+		shared, other := c, d
+		if d == a || d == b {
+			shared, other = d, c
+		}
+		end := a
+		if shared == a {
+			end = b
+		}
+		return predicates.Orient2D(shared, end, other) == 0 &&
+			(onSegment(shared, end, other) || onSegment(shared, other, end))
+	}
+
+	o1, o2 := predicates.Orient2D(a, b, c), predicates.Orient2D(a, b, d)
+	o3, o4 := predicates.Orient2D(c, d, a), predicates.Orient2D(c, d, b)
+	if o1*o2 < 0 && o3*o4 < 0 {
+		return true
+	}
+	return (o1 == 0 && onSegment(a, b, c)) ||
+		(o2 == 0 && onSegment(a, b, d)) ||
+		(o3 == 0 && onSegment(c, d, a)) ||
+		(o4 == 0 && onSegment(c, d, b))
+}
+
+// onSegment reports if the point, known to be on the line through a and b,
+// is between them
+func onSegment(a, b, pt [2]float64) bool {
+	return math.Min(a[0], b[0]) <= pt[0] && pt[0] <= math.Max(a[0], b[0]) &&
+		math.Min(a[1], b[1]) <= pt[1] && pt[1] <= math.Max(a[1], b[1])
+}
+
+// clipEars cuts ears off the counter clockwise ring until there is none
+// of it left
+func clipEars(ctx context.Context, ring [][2]float64) ([]geom.Triangle, error) {
+	n := len(ring)
+	prev, next := make([]int, n), make([]int, n)
+	for i := range ring {
+		prev[i], next[i] = (i+n-1)%n, (i+1)%n
+	}
+
+	var tris []geom.Triangle
+	remove := func(i int) {
+		next[prev[i]], prev[next[i]] = next[i], prev[i]
+		n--
+	}
+
+	// the first pass does not allow other vertices on the edges of an ear,
+	// the second pass does, in case that is all there is
+	i, stalled, strict := 0, 0, true
+	for n > 3 {
+		if stalled%64 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		p, nx := prev[i], next[i]
+		o := predicates.Orient2D(ring[p], ring[i], ring[nx])
+		switch {
+		case o == 0:
+			// no area, the vertex is on the line or is a spike
+			remove(i)
+			i, stalled = p, 0
+			continue
+		case o > 0 && isEar(ring, prev, next, i, strict):
+			tris = append(tris, geom.Triangle{ring[p], ring[i], ring[nx]})
+			remove(i)
+			i, stalled, strict = p, 0, true
+			continue
+		}
+
+		i = nx
+		stalled++
+		if stalled > n {
+			if !strict {
+				return tris, ErrInvalidPolygon
+			}
+			strict, stalled = false, 0
+		}
+	}
+
+	if predicates.Orient2D(ring[prev[i]], ring[i], ring[next[i]]) > 0 {
+		tris = append(tris, geom.Triangle{ring[prev[i]], ring[i], ring[next[i]]})
+	}
+	return tris, nil
+}
+
+// isEar reports if the triangle of the vertex i and the vertices either
+// side of it has none of the other vertices of the ring inside it, or on
+// its edges if strict.
+func isEar(ring [][2]float64, prev, next []int, i int, strict bool) bool {
+	a, b, c := ring[prev[i]], ring[i], ring[next[i]]
+	for j := next[next[i]]; j != prev[i]; j = next[j] {
+		pt := ring[j]
+		if pt == a || pt == b || pt == c {
+			// the other side of a bridge
+			continue
+		}
+		o1 := predicates.Orient2D(a, b, pt)
+		o2 := predicates.Orient2D(b, c, pt)
+		o3 := predicates.Orient2D(c, a, pt)
+		if strict {
+			if o1 >= 0 && o2 >= 0 && o3 >= 0 {
+				return false
+			}
+			continue
+		}
+		if o1 > 0 && o2 > 0 && o3 > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+// Package polygon triangulates polygons, with holes, into triangles; for
+// example to draw them with OpenGL or WebGL.
+//
+// There are two ways to triangulate a polygon. EarClip is fast and uses
+// only the vertices of the polygon, but can give long thin triangles.
+// ConstrainedDelaunay uses a constrained Delaunay triangulation of the
+// vertices, which avoids thin triangles where it can.
+package polygon
+
+import (
+	"context"
+
+	"github.com/gdey/errors"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/predicates"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay"
+	"github.com/go-spatial/geom/winding"
+)
+
+// ErrInvalidPolygon is returned when the polygon could not be
+// triangulated, because its rings cross themselves or each other.
+const ErrInvalidPolygon = errors.String("polygon: could not triangulate polygon, it may not be simple")
+
+// ConstrainedDelaunay returns the triangles of the constrained Delaunay
+// triangulation of the polygon, with the edges of its rings as the
+// constraints, that are inside the polygon. The triangles are counter
+// clockwise, with y going up, unless the planar.Options of the context say
+// otherwise. As with delaunay.ConstrainedTriangulator the points are
+// rounded to the subdivision.RoundingFactor.
+func ConstrainedDelaunay(ctx context.Context, poly geom.Polygon) ([]geom.Triangle, error) {
+	rings := prepareRings(poly)
+	if len(rings) == 0 {
+		return nil, nil
+	}
+
+	var (
+		pts         []geom.Point
+		constraints []geom.Line
+	)
+	for _, ring := range rings {
+		for i := range ring {
+			pts = append(pts, ring[i])
+			constraints = append(constraints, geom.Line{ring[i], ring[(i+1)%len(ring)]})
+		}
+	}
+
+	ct, err := delaunay.NewConstrainedTriangulator(ctx, pts, constraints)
+	if err != nil {
+		return nil, err
+	}
+	all := ct.Triangles(ctx, false)
+	if err = ct.Err(); err != nil {
+		return nil, err
+	}
+
+	clean := geom.Polygon(rings)
+	var tris []geom.Triangle
+	for _, tri := range all {
+		o := predicates.Orient2D(tri[0], tri[1], tri[2])
+		if o == 0 {
+			continue
+		}
+		if o < 0 {
+			tri[1], tri[2] = tri[2], tri[1]
+		}
+		centroid := geom.Point{
+			(tri[0][0] + tri[1][0] + tri[2][0]) / 3,
+			(tri[0][1] + tri[1][1] + tri[2][1]) / 3,
+		}
+		if planar.PolygonContains(clean, centroid) {
+			tris = append(tris, tri)
+		}
+	}
+	return planar.FromContext(ctx).OrientTriangles(tris), nil
+}
+
+// prepareRings returns the rings of the polygon without repeated points,
+// the outer ring counter clockwise and the holes clockwise. Rings with
+// no area are dropped, and if the outer ring has none the result is nil.
+func prepareRings(poly geom.Polygon) [][][2]float64 {
+	var rings [][][2]float64
+	for i, ring := range poly {
+		var clean [][2]float64
+		for _, pt := range ring {
+			if len(clean) > 0 && clean[len(clean)-1] == pt {
+				continue
+			}
+			clean = append(clean, pt)
+		}
+		for len(clean) > 1 && clean[0] == clean[len(clean)-1] {
+			clean = clean[:len(clean)-1]
+		}
+
+		area := winding.SignedArea(clean...)
+		if len(clean) < 3 || area == 0 {
+			if i == 0 {
+				return nil
+			}
+			continue
+		}
+		// outer ring counter clockwise, holes clockwise
+		if (i == 0) != (area > 0) {
+			for l, r := 0, len(clean)-1; l < r; l, r = l+1, r-1 {
+				clean[l], clean[r] = clean[r], clean[l]
+			}
+		}
+		rings = append(rings, clean)
+	}
+	return rings
+}
@@ -0,0 +1,166 @@
+package polygon
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/predicates"
+	"github.com/go-spatial/geom/winding"
+)
+
+func TestTriangulate(t *testing.T) {
+	type tcase struct {
+		poly geom.Polygon
+		area float64
+		// the number of triangles from ear clipping
+		count int
+	}
+
+	check := func(t *testing.T, tc tcase, tris []geom.Triangle) {
+		var area float64
+		for _, tri := range tris {
+			o := predicates.Orient2D(tri[0], tri[1], tri[2])
+			if o <= 0 {
+				t.Errorf("triangle %v, expected counter clockwise", tri)
+			}
+			area += o / 2
+			centroid := geom.Point{
+				(tri[0][0] + tri[1][0] + tri[2][0]) / 3,
+				(tri[0][1] + tri[1][1] + tri[2][1]) / 3,
+			}
+			if !planar.PolygonContains(tc.poly, centroid) {
+				t.Errorf("triangle %v, expected to be inside the polygon", tri)
+			}
+		}
+		if math.Abs(area-tc.area) > 1e-9 {
+			t.Errorf("area, expected %v got %v", tc.area, area)
+		}
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctx := context.Background()
+			t.Run("ear clip", func(t *testing.T) {
+				tris, err := EarClip(ctx, tc.poly)
+				if err != nil {
+					t.Fatalf("error, expected nil got %v", err)
+				}
+				if len(tris) != tc.count {
+					t.Errorf("triangles, expected %v got %v", tc.count, len(tris))
+				}
+				check(t, tc, tris)
+			})
+			t.Run("constrained delaunay", func(t *testing.T) {
+				tris, err := ConstrainedDelaunay(ctx, tc.poly)
+				if err != nil {
+					t.Fatalf("error, expected nil got %v", err)
+				}
+				check(t, tc, tris)
+			})
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {},
+		"no area": {
+			poly: geom.Polygon{{{0, 0}, {1, 1}, {2, 2}}},
+		},
+		"square": {
+			poly:  geom.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 4}}},
+			area:  16,
+			count: 2,
+		},
+		"clockwise and closed": {
+			poly:  geom.Polygon{{{0, 0}, {0, 4}, {4, 4}, {4, 0}, {0, 0}}},
+			area:  16,
+			count: 2,
+		},
+		"concave": {
+			// a U shape
+			poly:  geom.Polygon{{{0, 0}, {6, 0}, {6, 6}, {4, 6}, {4, 2}, {2, 2}, {2, 6}, {0, 6}}},
+			area:  28,
+			count: 6,
+		},
+		"collinear points": {
+			poly:  geom.Polygon{{{0, 0}, {2, 0}, {4, 0}, {4, 4}, {0, 4}}},
+			area:  16,
+			count: 3,
+		},
+		"hole": {
+			poly: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{3, 3}, {3, 7}, {7, 7}, {7, 3}},
+			},
+			area:  84,
+			count: 8,
+		},
+		"two holes": {
+			poly: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{1, 1}, {1, 4}, {4, 4}, {4, 1}},
+				{{6, 6}, {6, 9}, {9, 9}, {9, 6}},
+			},
+			area:  82,
+			count: 14,
+		},
+		"hole touching the ring": {
+			poly: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 5}, {10, 10}, {0, 10}},
+				{{5, 5}, {10, 5}, {5, 8}},
+			},
+			area: 92.5,
+			// the touching vertex is used twice
+			count: 6,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestEarClipInvalid(t *testing.T) {
+	// the hole is outside the ring
+	poly := geom.Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+		{{20, 4}, {20, 6}, {22, 5}},
+	}
+	_, err := EarClip(context.Background(), poly)
+	if err != ErrInvalidPolygon {
+		t.Errorf("error, expected %v got %v", ErrInvalidPolygon, err)
+	}
+}
+
+func TestEarClipRandom(t *testing.T) {
+	// star shaped polygons with a hole in the middle of each
+	for n := 3; n < 40; n++ {
+		var outer, hole [][2]float64
+		for i := 0; i < n; i++ {
+			angle := 2 * math.Pi * float64(i) / float64(n)
+			r := 10.0
+			if i%2 == 1 {
+				r = 6
+			}
+			outer = append(outer, [2]float64{r * math.Cos(angle), r * math.Sin(angle)})
+			hole = append(hole, [2]float64{2 * math.Cos(-angle), 2 * math.Sin(-angle)})
+		}
+		poly := geom.Polygon{outer, hole}
+		tris, err := EarClip(context.Background(), poly)
+		if err != nil {
+			t.Fatalf("%v points, error expected nil got %v", n, err)
+		}
+		var area float64
+		for _, tri := range tris {
+			area += predicates.Orient2D(tri[0], tri[1], tri[2]) / 2
+		}
+		if exp := winding.SignedArea(outer...) + winding.SignedArea(hole...); math.Abs(area-exp) > 1e-9 {
+			t.Errorf("%v points, area expected %v got %v", n, exp, area)
+		}
+		if exp := 2*n + 2 - 2; len(tris) != exp {
+			t.Errorf("%v points, triangles expected %v got %v", n, exp, len(tris))
+		}
+	}
+}
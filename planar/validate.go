@@ -0,0 +1,496 @@
+package planar
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/predicates"
+	"github.com/go-spatial/geom/planar/sweep"
+)
+
+// ViolationKind is the rule of validity a geometry breaks.
+type ViolationKind uint8
+
+const (
+	// InvalidCoordinate is a coordinate that is NaN or infinite
+	InvalidCoordinate ViolationKind = iota + 1
+	// TooFewPoints is a line string with fewer than two distinct points
+	TooFewPoints
+	// UnclosedRing is a ring with fewer than three distinct points, so it
+	// can not be closed around an area
+	UnclosedRing
+	// Spike is a vertex where a ring turns back on itself
+	Spike
+	// SelfIntersection is a point where a ring crosses or touches itself
+	SelfIntersection
+	// RingsIntersect is a point where two rings of a polygon cross, or
+	// where they touch if they touch at more than one point
+	RingsIntersect
+	// HoleOutsideShell is a hole that is not inside the outer ring of its
+	// polygon
+	HoleOutsideShell
+	// NestedHoles is a hole inside another hole of the same polygon
+	NestedHoles
+	// PolygonsOverlap is a polygon of a MultiPolygon that overlaps
+	// another one, or touches it at more than one point
+	PolygonsOverlap
+)
+
+func (k ViolationKind) String() string {
+	switch k {
+	case InvalidCoordinate:
+		return "invalid coordinate"
+	case TooFewPoints:
+		return "too few points"
+	case UnclosedRing:
+		return "unclosed ring"
+	case Spike:
+		return "spike"
+	case SelfIntersection:
+		return "self-intersection"
+	case RingsIntersect:
+		return "rings intersect"
+	case HoleOutsideShell:
+		return "hole outside shell"
+	case NestedHoles:
+		return "nested holes"
+	case PolygonsOverlap:
+		return "polygons overlap"
+	default:
+		return "unknown"
+	}
+}
+
+// Violation is a place where a geometry is not valid.
+type Violation struct {
+	Kind ViolationKind
+	// Location is the point where the rule is broken.
+	Location [2]float64
+	// Path is the indexes into the geometry, from the outside in, of the
+	// part breaking the rule. For a MultiPolygon it is the index of the
+	// polygon, the ring and the vertex of the ring, where the vertex starts
+	// the segment for intersections.
+	Path []int
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%v at (%v %v) %v", v.Kind, v.Location[0], v.Location[1], v.Path)
+}
+
+// ValidationReport is the list of ways a geometry is not valid.
+type ValidationReport struct {
+	Violations []Violation
+}
+
+// Valid reports whether no violations were found.
+func (r ValidationReport) Valid() bool { return len(r.Violations) == 0 }
+
+// Validate checks the geometry against the OGC rules of validity, and
+// reports each place it breaks one. Points, lines, polygons, their Multi
+// forms and collections of them are supported.
+//
+// The rings of polygons may be closed or not and in either direction.
+// Rings must have three distinct points and no spikes, must not cross or
+// touch themselves, and may touch the other rings of the polygon at one
+// point only. Holes must be inside the outer ring and not inside each other.
+// The polygons of a MultiPolygon must not overlap. Whether the interior of
+// a polygon is split by holes touching each other is not checked.
+//
+// Rings with broken rules other than spikes are not checked further,
+// neither are polygons with broken rules checked against the other
+// polygons of a MultiPolygon.
+func Validate(g geom.Geometry) (ValidationReport, error) {
+	var v validator
+	if err := v.geometry(nil, g); err != nil {
+		return ValidationReport{}, err
+	}
+	return ValidationReport{Violations: v.violations}, nil
+}
+
+type validator struct {
+	violations []Violation
+}
+
+func (v *validator) add(kind ViolationKind, pt [2]float64, path ...int) {
+	v.violations = append(v.violations, Violation{
+		Kind:     kind,
+		Location: pt,
+		Path:     append([]int(nil), path...),
+	})
+}
+
+func (v *validator) geometry(path []int, g geom.Geometry) error {
+	switch gg := g.(type) {
+	case nil:
+
+	case geom.Collectioner:
+		for i, g := range gg.Geometries() {
+			if err := v.geometry(append(path, i), g); err != nil {
+				return err
+			}
+		}
+
+	case geom.MultiPolygoner:
+		v.multiPolygon(path, gg.Polygons())
+
+	case geom.Polygoner:
+		v.polygon(path, gg.LinearRings())
+
+	case geom.MultiLineStringer:
+		for i, ls := range gg.LineStrings() {
+			v.lineString(append(path, i), ls)
+		}
+
+	case geom.LineStringer:
+		v.lineString(path, gg.Vertices())
+
+	case geom.MultiPointer:
+		for i, pt := range gg.Points() {
+			v.coordinate(append(path, i), pt)
+		}
+
+	case geom.Pointer:
+		v.coordinate(path, gg.XY())
+
+	default:
+		return geom.ErrUnknownGeometry{Geom: g}
+	}
+	return nil
+}
+
+// coordinate reports whether the point is a valid coordinate
+func (v *validator) coordinate(path []int, pt [2]float64) bool {
+	if math.IsNaN(pt[0]) || math.IsNaN(pt[1]) || math.IsInf(pt[0], 0) || math.IsInf(pt[1], 0) {
+		v.add(InvalidCoordinate, pt, path...)
+		return false
+	}
+	return true
+}
+
+// coordinates reports whether all the points are valid coordinates
+func (v *validator) coordinates(path []int, pts [][2]float64) bool {
+	ok := true
+	for i, pt := range pts {
+		if !v.coordinate(append(path, i), pt) {
+			ok = false
+		}
+	}
+	return ok
+}
+
+func (v *validator) lineString(path []int, ls [][2]float64) {
+	if len(ls) == 0 || !v.coordinates(path, ls) {
+		return
+	}
+	for _, pt := range ls[1:] {
+		if pt != ls[0] {
+			return
+		}
+	}
+	v.add(TooFewPoints, ls[0], path...)
+}
+
+// validRing is a ring without repeated points, and the index of each point
+// in the ring given
+type validRing struct {
+	index int
+	pts   [][2]float64
+	orig  []int
+}
+
+// rings checks each of the rings on their own, returning the ones that can
+// be checked further and whether all of them are valid
+func (v *validator) rings(path []int, rings [][][2]float64) (ret []validRing, ok bool) {
+	ok = true
+	for r, ring := range rings {
+		rpath := append(path, r)
+		if !v.coordinates(rpath, ring) {
+			ok = false
+			continue
+		}
+
+		vr := validRing{index: r}
+		for i, pt := range ring {
+			if len(vr.pts) > 0 && vr.pts[len(vr.pts)-1] == pt {
+				continue
+			}
+			vr.pts, vr.orig = append(vr.pts, pt), append(vr.orig, i)
+		}
+		for len(vr.pts) > 1 && vr.pts[0] == vr.pts[len(vr.pts)-1] {
+			vr.pts, vr.orig = vr.pts[:len(vr.pts)-1], vr.orig[:len(vr.orig)-1]
+		}
+		if len(vr.pts) < 3 {
+			if len(ring) > 0 {
+				v.add(UnclosedRing, ring[0], rpath...)
+			}
+			ok = false
+			continue
+		}
+
+		n := len(vr.pts)
+		spike := make([]bool, n)
+		for i, b := range vr.pts {
+			if isSpike(vr.pts[(i+n-1)%n], b, vr.pts[(i+1)%n]) {
+				v.add(Spike, b, append(rpath, vr.orig[i])...)
+				spike[i], ok = true, false
+			}
+		}
+		if !ok {
+			vr = withoutSpikes(vr, spike)
+			if len(vr.pts) < 3 {
+				continue
+			}
+		}
+		ret = append(ret, vr)
+	}
+	return ret, ok
+}
+
+// isSpike reports whether the ring turns back on itself at b
+func isSpike(a, b, c [2]float64) bool {
+	return predicates.Orient2D(a, b, c) == 0 && vdot(vsub(a, b), vsub(c, b)) > 0
+}
+
+// withoutSpikes returns the ring without the spikes, so the rest of it can
+// be checked. Removing a spike may leave another one, or a repeated point,
+// which are removed too.
+func withoutSpikes(ring validRing, spike []bool) validRing {
+	ret := validRing{index: ring.index}
+	for i, pt := range ring.pts {
+		if spike[i] {
+			continue
+		}
+		for {
+			n := len(ret.pts)
+			if n > 0 && ret.pts[n-1] == pt {
+				break
+			}
+			if n > 1 && isSpike(ret.pts[n-2], ret.pts[n-1], pt) {
+				ret.pts, ret.orig = ret.pts[:n-1], ret.orig[:n-1]
+				continue
+			}
+			ret.pts, ret.orig = append(ret.pts, pt), append(ret.orig, ring.orig[i])
+			break
+		}
+	}
+	for len(ret.pts) > 1 && ret.pts[0] == ret.pts[len(ret.pts)-1] {
+		ret.pts, ret.orig = ret.pts[:len(ret.pts)-1], ret.orig[:len(ret.orig)-1]
+	}
+	return ret
+}
+
+// ringMeetings returns the points where each ring crosses or touches
+// itself, other than where its segments join, with the index of the first
+// segment there, and the points shared by each pair of rings
+func ringMeetings(rings []validRing) (self map[int][][2]float64, selfSeg map[int][]int, shared map[[2]int][][2]float64, err error) {
+	var (
+		segs []geom.Line
+		src  [][2]int
+	)
+	for r, ring := range rings {
+		for i := range ring.pts {
+			segs = append(segs, geom.Line{ring.pts[i], ring.pts[(i+1)%len(ring.pts)]})
+			src = append(src, [2]int{r, i})
+		}
+	}
+	inters, err := sweep.Intersections(context.Background(), segs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	self, selfSeg, shared = make(map[int][][2]float64), make(map[int][]int), make(map[[2]int][][2]float64)
+	for _, in := range inters {
+		byRing := make(map[int][]int)
+		var order []int
+		for _, s := range in.Segments {
+			r := src[s][0]
+			if _, ok := byRing[r]; !ok {
+				order = append(order, r)
+			}
+			byRing[r] = append(byRing[r], src[s][1])
+		}
+		for x, r := range order {
+			if seg, ok := selfTouch(byRing[r], len(rings[r].pts)); ok {
+				self[r] = append(self[r], in.Point)
+				selfSeg[r] = append(selfSeg[r], seg)
+			}
+			for _, r2 := range order[x+1:] {
+				key := [2]int{r, r2}
+				shared[key] = append(shared[key], in.Point)
+			}
+		}
+	}
+	return self, selfSeg, shared, nil
+}
+
+// selfTouch returns the first of the segments, of a ring of n segments,
+// meeting at a point that is not next to all the others
+func selfTouch(segs []int, n int) (int, bool) {
+	sort.Ints(segs)
+	for i, s1 := range segs {
+		for _, s2 := range segs[i+1:] {
+			if (s1+1)%n != s2 && (s2+1)%n != s1 {
+				return s1, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// inRing reports whether the point is inside the ring, and whether it is
+// on its boundary
+func inRing(ring [][2]float64, pt [2]float64) (inside, on bool) {
+	for i := range ring {
+		cross, o := crossesRay(ring[i], ring[(i+1)%len(ring)], pt)
+		if o {
+			return false, true
+		}
+		if cross {
+			inside = !inside
+		}
+	}
+	return inside, false
+}
+
+// offBoundary returns the first point of the ring that is not on the
+// boundaries of the other rings
+func offBoundary(ring validRing, others ...validRing) (pt [2]float64, i int, ok bool) {
+next:
+	for i, pt := range ring.pts {
+		for _, o := range others {
+			if _, on := inRing(o.pts, pt); on {
+				continue next
+			}
+		}
+		return pt, i, true
+	}
+	return pt, 0, false
+}
+
+// polygon checks the polygon, reporting whether it is valid
+func (v *validator) polygon(path []int, poly [][][2]float64) bool {
+	rings, ok := v.rings(path, poly)
+	if len(rings) == 0 {
+		return ok
+	}
+
+	self, selfSeg, shared, err := ringMeetings(rings)
+	if err != nil {
+		return false
+	}
+	bad := make(map[int]bool)
+	for r := range rings {
+		for i, pt := range self[r] {
+			v.add(SelfIntersection, pt, append(path, rings[r].index, rings[r].orig[selfSeg[r][i]])...)
+			bad[r] = true
+		}
+	}
+	for r1 := range rings {
+		for r2 := r1 + 1; r2 < len(rings); r2++ {
+			pts := shared[[2]int{r1, r2}]
+			if len(pts) < 2 {
+				continue
+			}
+			v.add(RingsIntersect, pts[1], append(path, rings[r2].index)...)
+			bad[r1], bad[r2] = true, true
+		}
+	}
+	if len(bad) > 0 {
+		ok = false
+	}
+
+	if rings[0].index != 0 || bad[0] {
+		// the holes can not be checked against the shell
+		return false
+	}
+	for h := 1; h < len(rings); h++ {
+		if bad[h] {
+			continue
+		}
+		hole := rings[h]
+		pt, i, found := offBoundary(hole, rings[0])
+		if !found {
+			continue
+		}
+		if inside, _ := inRing(rings[0].pts, pt); !inside {
+			v.add(HoleOutsideShell, pt, append(path, hole.index, hole.orig[i])...)
+			ok = false
+			continue
+		}
+		for o := 1; o < len(rings); o++ {
+			if o == h || bad[o] {
+				continue
+			}
+			pt, i, found := offBoundary(hole, rings[o])
+			if !found {
+				continue
+			}
+			if inside, _ := inRing(rings[o].pts, pt); inside {
+				v.add(NestedHoles, pt, append(path, hole.index, hole.orig[i])...)
+				ok = false
+			}
+		}
+	}
+	return ok
+}
+
+func (v *validator) multiPolygon(path []int, plys [][][][2]float64) {
+	var (
+		valid   []int
+		shells  []validRing
+		polygon = make([][]validRing, len(plys))
+	)
+	for i, ply := range plys {
+		ppath := append(path, i)
+		if !v.polygon(ppath, ply) || len(ply) == 0 {
+			continue
+		}
+		// the rings are valid, so they can be checked without reporting
+		var quiet validator
+		polygon[i], _ = quiet.rings(nil, ply)
+		valid = append(valid, i)
+		shells = append(shells, polygon[i][0])
+	}
+
+	_, _, shared, err := ringMeetings(shells)
+	if err != nil {
+		return
+	}
+	for x, i := range valid {
+		for y := x + 1; y < len(valid); y++ {
+			j := valid[y]
+			if pts := shared[[2]int{x, y}]; len(pts) > 1 {
+				v.add(PolygonsOverlap, pts[1], append(path, j)...)
+				continue
+			}
+			if pt, found := inPolygon(polygon[j][0], polygon[i]); found {
+				v.add(PolygonsOverlap, pt, append(path, j)...)
+				continue
+			}
+			if pt, found := inPolygon(polygon[i][0], polygon[j]); found {
+				v.add(PolygonsOverlap, pt, append(path, j)...)
+			}
+		}
+	}
+}
+
+// inPolygon returns a point of the ring inside the polygon, not on its
+// boundary, if the first point of the ring not on the boundary is inside
+func inPolygon(ring validRing, poly []validRing) ([2]float64, bool) {
+	pt, _, found := offBoundary(ring, poly...)
+	if !found {
+		return pt, false
+	}
+	if inside, _ := inRing(poly[0].pts, pt); !inside {
+		return pt, false
+	}
+	for _, hole := range poly[1:] {
+		if inside, _ := inRing(hole.pts, pt); inside {
+			return pt, false
+		}
+	}
+	return pt, true
+}
@@ -0,0 +1,123 @@
+package planar
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestValidate(t *testing.T) {
+	type tcase struct {
+		g        geom.Geometry
+		expected []Violation
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			report, err := Validate(tc.g)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(report.Violations, tc.expected) {
+				t.Errorf("violations, expected %v got %v", tc.expected, report.Violations)
+			}
+			if report.Valid() != (len(tc.expected) == 0) {
+				t.Errorf("valid, expected %v got %v", len(tc.expected) == 0, report.Valid())
+			}
+		}
+	}
+
+	square := [][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+
+	tests := map[string]tcase{
+		"nil": {},
+		"point": {
+			g: geom.Point{1, 2},
+		},
+		"infinite point": {
+			g:        geom.Point{math.Inf(1), 2},
+			expected: []Violation{{Kind: InvalidCoordinate, Location: [2]float64{math.Inf(1), 2}}},
+		},
+		"line string": {
+			g:        geom.MultiLineString{{{0, 0}, {1, 1}}, {{1, 1}, {1, 1}}},
+			expected: []Violation{{Kind: TooFewPoints, Location: [2]float64{1, 1}, Path: []int{1}}},
+		},
+		"polygon": {
+			g: geom.Polygon{square, {{2, 2}, {2, 4}, {4, 4}, {4, 2}, {2, 2}}},
+		},
+		"unclosed ring": {
+			g:        geom.Polygon{{{0, 0}, {1, 1}, {0, 0}}},
+			expected: []Violation{{Kind: UnclosedRing, Location: [2]float64{0, 0}, Path: []int{0}}},
+		},
+		"spike": {
+			g:        geom.Polygon{{{0, 0}, {10, 0}, {15, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			expected: []Violation{{Kind: Spike, Location: [2]float64{15, 0}, Path: []int{0, 2}}},
+		},
+		"bow tie": {
+			g:        geom.Polygon{{{0, 0}, {10, 10}, {10, 0}, {0, 10}}},
+			expected: []Violation{{Kind: SelfIntersection, Location: [2]float64{5, 5}, Path: []int{0, 0}}},
+		},
+		"ring touching itself": {
+			g:        geom.Polygon{{{0, 0}, {10, 0}, {5, 5}, {10, 10}, {0, 10}, {5, 5}}},
+			expected: []Violation{{Kind: SelfIntersection, Location: [2]float64{5, 5}, Path: []int{0, 1}}},
+		},
+		"hole touching the shell": {
+			g: geom.Polygon{square, {{5, 0}, {7, 5}, {3, 5}}},
+		},
+		"hole crossing the shell": {
+			g:        geom.Polygon{square, {{5, 5}, {15, 5}, {15, 8}, {5, 8}}},
+			expected: []Violation{{Kind: RingsIntersect, Location: [2]float64{10, 8}, Path: []int{1}}},
+		},
+		"hole outside the shell": {
+			g:        geom.Polygon{square, {{20, 20}, {20, 22}, {22, 22}}},
+			expected: []Violation{{Kind: HoleOutsideShell, Location: [2]float64{20, 20}, Path: []int{1, 0}}},
+		},
+		"nested holes": {
+			g:        geom.Polygon{square, {{1, 1}, {1, 9}, {9, 9}, {9, 1}}, {{4, 4}, {4, 6}, {6, 6}}},
+			expected: []Violation{{Kind: NestedHoles, Location: [2]float64{4, 4}, Path: []int{2, 0}}},
+		},
+		"multipolygon": {
+			g: geom.MultiPolygon{
+				{square, {{2, 2}, {2, 8}, {8, 8}, {8, 2}}},
+				{{{3, 3}, {7, 3}, {7, 7}, {3, 7}}},
+				{{{10, 0}, {20, 0}, {20, 10}}},
+			},
+		},
+		"polygons sharing an edge": {
+			g: geom.MultiPolygon{
+				{square},
+				{{{10, 0}, {20, 0}, {20, 10}, {10, 10}}},
+			},
+			expected: []Violation{{Kind: PolygonsOverlap, Location: [2]float64{10, 10}, Path: []int{1}}},
+		},
+		"overlapping polygons": {
+			g: geom.MultiPolygon{
+				{square},
+				{{{1, 1}, {2, 1}, {2, 2}}},
+				{{{5, 5}, {15, 5}, {15, 15}}},
+			},
+			expected: []Violation{
+				{Kind: PolygonsOverlap, Location: [2]float64{1, 1}, Path: []int{1}},
+				{Kind: PolygonsOverlap, Location: [2]float64{10, 10}, Path: []int{2}},
+			},
+		},
+		"collection": {
+			g: geom.Collection{
+				geom.Point{0, 0},
+				geom.Collection{geom.Polygon{{{0, 0}, {10, 10}, {10, 0}, {0, 10}}}},
+			},
+			expected: []Violation{{Kind: SelfIntersection, Location: [2]float64{5, 5}, Path: []int{1, 0, 0, 0}}},
+		},
+		"unknown": {
+			g:   geom.PointZ{1, 2, 3},
+			err: geom.ErrUnknownGeometry{Geom: geom.PointZ{1, 2, 3}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
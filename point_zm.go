@@ -0,0 +1,281 @@
+package geom
+
+import "errors"
+
+// ErrNilPointZ is thrown when a PointZ is null but shouldn't be
+var ErrNilPointZ = errors.New("geom: nil PointZ")
+
+// ErrNilPointM is thrown when a PointM is null but shouldn't be
+var ErrNilPointM = errors.New("geom: nil PointM")
+
+// ErrNilPointZM is thrown when a PointZM is null but shouldn't be
+var ErrNilPointZM = errors.New("geom: nil PointZM")
+
+// PointZ describes a 3D point with an elevation (z) value
+type PointZ [3]float64
+
+// XYZ returns an array of 3D coordinates
+func (p PointZ) XYZ() [3]float64 { return p }
+
+// Z is the elevation of the point
+func (p PointZ) Z() float64 { return p[2] }
+
+// Point returns the 2D point, dropping the z value
+func (p PointZ) Point() Point { return Point{p[0], p[1]} }
+
+// SetXYZ sets the three coordinates
+func (p *PointZ) SetXYZ(xyz [3]float64) (err error) {
+	if p == nil {
+		return ErrNilPointZ
+	}
+
+	p[0] = xyz[0]
+	p[1] = xyz[1]
+	p[2] = xyz[2]
+	return
+}
+
+// PointM describes a 2D point with an associated measure (m) value
+type PointM [3]float64
+
+// XYM returns the 2D coordinates and the measure
+func (p PointM) XYM() [3]float64 { return p }
+
+// M is the measure of the point
+func (p PointM) M() float64 { return p[2] }
+
+// Point returns the 2D point, dropping the m value
+func (p PointM) Point() Point { return Point{p[0], p[1]} }
+
+// SetXYM sets the coordinates and the measure
+func (p *PointM) SetXYM(xym [3]float64) (err error) {
+	if p == nil {
+		return ErrNilPointM
+	}
+
+	p[0] = xym[0]
+	p[1] = xym[1]
+	p[2] = xym[2]
+	return
+}
+
+// PointZM describes a 3D point with an associated measure (m) value
+type PointZM [4]float64
+
+// XYZM returns the 3D coordinates and the measure
+func (p PointZM) XYZM() [4]float64 { return p }
+
+// Z is the elevation of the point
+func (p PointZM) Z() float64 { return p[2] }
+
+// M is the measure of the point
+func (p PointZM) M() float64 { return p[3] }
+
+// Point returns the 2D point, dropping the z and m values
+func (p PointZM) Point() Point { return Point{p[0], p[1]} }
+
+// SetXYZM sets the coordinates and the measure
+func (p *PointZM) SetXYZM(xyzm [4]float64) (err error) {
+	if p == nil {
+		return ErrNilPointZM
+	}
+
+	p[0] = xyzm[0]
+	p[1] = xyzm[1]
+	p[2] = xyzm[2]
+	p[3] = xyzm[3]
+	return
+}
+
+// The following are helpers to project slices of higher dimension
+// coordinates down to 2D.
+
+func xyOf3(pts [][3]float64) [][2]float64 {
+	if pts == nil {
+		return nil
+	}
+	xy := make([][2]float64, len(pts))
+	for i := range pts {
+		xy[i] = [2]float64{pts[i][0], pts[i][1]}
+	}
+	return xy
+}
+
+func xyOf4(pts [][4]float64) [][2]float64 {
+	if pts == nil {
+		return nil
+	}
+	xy := make([][2]float64, len(pts))
+	for i := range pts {
+		xy[i] = [2]float64{pts[i][0], pts[i][1]}
+	}
+	return xy
+}
+
+func xyOfLines3(lines [][][3]float64) [][][2]float64 {
+	if lines == nil {
+		return nil
+	}
+	xy := make([][][2]float64, len(lines))
+	for i := range lines {
+		xy[i] = xyOf3(lines[i])
+	}
+	return xy
+}
+
+func xyOfLines4(lines [][][4]float64) [][][2]float64 {
+	if lines == nil {
+		return nil
+	}
+	xy := make([][][2]float64, len(lines))
+	for i := range lines {
+		xy[i] = xyOf4(lines[i])
+	}
+	return xy
+}
+
+// derefZM returns the value a pointer to a Z, M or ZM geometry points
+// to. A nil pointer is returned as an empty geometry of the same type.
+// Any other geometry is returned as is.
+func derefZM(g Geometry) Geometry {
+	switch gg := g.(type) {
+	case *PointZ:
+		if gg == nil {
+			return PointZ{nan, nan, nan}
+		}
+		return *gg
+	case *PointM:
+		if gg == nil {
+			return PointM{nan, nan, nan}
+		}
+		return *gg
+	case *PointZM:
+		if gg == nil {
+			return PointZM{nan, nan, nan, nan}
+		}
+		return *gg
+	case *MultiPointZ:
+		if gg == nil {
+			return MultiPointZ(nil)
+		}
+		return *gg
+	case *MultiPointM:
+		if gg == nil {
+			return MultiPointM(nil)
+		}
+		return *gg
+	case *MultiPointZM:
+		if gg == nil {
+			return MultiPointZM(nil)
+		}
+		return *gg
+	case *LineStringZ:
+		if gg == nil {
+			return LineStringZ(nil)
+		}
+		return *gg
+	case *LineStringM:
+		if gg == nil {
+			return LineStringM(nil)
+		}
+		return *gg
+	case *LineStringZM:
+		if gg == nil {
+			return LineStringZM(nil)
+		}
+		return *gg
+	case *MultiLineStringZ:
+		if gg == nil {
+			return MultiLineStringZ(nil)
+		}
+		return *gg
+	case *MultiLineStringM:
+		if gg == nil {
+			return MultiLineStringM(nil)
+		}
+		return *gg
+	case *MultiLineStringZM:
+		if gg == nil {
+			return MultiLineStringZM(nil)
+		}
+		return *gg
+	case *PolygonZ:
+		if gg == nil {
+			return PolygonZ(nil)
+		}
+		return *gg
+	case *PolygonM:
+		if gg == nil {
+			return PolygonM(nil)
+		}
+		return *gg
+	case *PolygonZM:
+		if gg == nil {
+			return PolygonZM(nil)
+		}
+		return *gg
+	case *MultiPolygonZ:
+		if gg == nil {
+			return MultiPolygonZ(nil)
+		}
+		return *gg
+	case *MultiPolygonM:
+		if gg == nil {
+			return MultiPolygonM(nil)
+		}
+		return *gg
+	case *MultiPolygonZM:
+		if gg == nil {
+			return MultiPolygonZM(nil)
+		}
+		return *gg
+	default:
+		return g
+	}
+}
+
+// XYGeometry returns the 2D projection of the Z, M and ZM geometries, or
+// pointers to them. ok will be false if the geometry is not one of these
+// types.
+func XYGeometry(g Geometry) (xy Geometry, ok bool) {
+	switch gg := derefZM(g).(type) {
+	case PointZ:
+		return gg.Point(), true
+	case PointM:
+		return gg.Point(), true
+	case PointZM:
+		return gg.Point(), true
+	case MultiPointZ:
+		return gg.MultiPoint(), true
+	case MultiPointM:
+		return gg.MultiPoint(), true
+	case MultiPointZM:
+		return gg.MultiPoint(), true
+	case LineStringZ:
+		return gg.LineString(), true
+	case LineStringM:
+		return gg.LineString(), true
+	case LineStringZM:
+		return gg.LineString(), true
+	case MultiLineStringZ:
+		return gg.MultiLineString(), true
+	case MultiLineStringM:
+		return gg.MultiLineString(), true
+	case MultiLineStringZM:
+		return gg.MultiLineString(), true
+	case PolygonZ:
+		return gg.Polygon(), true
+	case PolygonM:
+		return gg.Polygon(), true
+	case PolygonZM:
+		return gg.Polygon(), true
+	case MultiPolygonZ:
+		return gg.MultiPolygon(), true
+	case MultiPolygonM:
+		return gg.MultiPolygon(), true
+	case MultiPolygonZM:
+		return gg.MultiPolygon(), true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,143 @@
+package geom_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestPointZMSetter(t *testing.T) {
+	type tcase struct {
+		set      func() error
+		setter   geom.Geometry
+		expected geom.Geometry
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			err := tc.set()
+			if tc.err == nil && err != nil {
+				t.Errorf("error, expected nil got %v", err)
+				return
+			}
+			if tc.err != nil {
+				if err == nil || tc.err.Error() != err.Error() {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+
+			// compare the results
+			if !reflect.DeepEqual(tc.expected, tc.setter) {
+				t.Errorf("setter, expected %v got %v", tc.expected, tc.setter)
+			}
+		}
+	}
+
+	tests := map[string]func() tcase{
+		"PointZ": func() tcase {
+			g := &geom.PointZ{0, 0, 0}
+			return tcase{
+				set:      func() error { return g.SetXYZ([3]float64{1, 2, 3}) },
+				setter:   g,
+				expected: &geom.PointZ{1, 2, 3},
+			}
+		},
+		"nil PointZ": func() tcase {
+			var g *geom.PointZ
+			return tcase{
+				set: func() error { return g.SetXYZ([3]float64{1, 2, 3}) },
+				err: geom.ErrNilPointZ,
+			}
+		},
+		"PointM": func() tcase {
+			g := &geom.PointM{0, 0, 0}
+			return tcase{
+				set:      func() error { return g.SetXYM([3]float64{1, 2, 3}) },
+				setter:   g,
+				expected: &geom.PointM{1, 2, 3},
+			}
+		},
+		"nil PointM": func() tcase {
+			var g *geom.PointM
+			return tcase{
+				set: func() error { return g.SetXYM([3]float64{1, 2, 3}) },
+				err: geom.ErrNilPointM,
+			}
+		},
+		"PointZM": func() tcase {
+			g := &geom.PointZM{0, 0, 0, 0}
+			return tcase{
+				set:      func() error { return g.SetXYZM([4]float64{1, 2, 3, 4}) },
+				setter:   g,
+				expected: &geom.PointZM{1, 2, 3, 4},
+			}
+		},
+		"nil PointZM": func() tcase {
+			var g *geom.PointZM
+			return tcase{
+				set: func() error { return g.SetXYZM([4]float64{1, 2, 3, 4}) },
+				err: geom.ErrNilPointZM,
+			}
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc()))
+	}
+}
+
+func TestPointZMProjection(t *testing.T) {
+	type tcase struct {
+		got      geom.Point
+		expected geom.Point
+	}
+
+	tests := map[string]tcase{
+		"PointZ": {
+			got:      geom.PointZ{1, 2, 3}.Point(),
+			expected: geom.Point{1, 2},
+		},
+		"PointM": {
+			got:      geom.PointM{1, 2, 3}.Point(),
+			expected: geom.Point{1, 2},
+		},
+		"PointZM": {
+			got:      geom.PointZM{1, 2, 3, 4}.Point(),
+			expected: geom.Point{1, 2},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.expected, tc.got) {
+				t.Errorf("%v, expected %v got %v", name, tc.expected, tc.got)
+			}
+		})
+	}
+}
+
+func TestPointZMAccessors(t *testing.T) {
+	z := geom.PointZ{1, 2, 3}
+	if z.XYZ() != [3]float64{1, 2, 3} || z.Z() != 3 {
+		t.Errorf("PointZ, expected xyz %v z 3 got %v z %v", [3]float64(z), z.XYZ(), z.Z())
+	}
+	m := geom.PointM{1, 2, 3}
+	if m.XYM() != [3]float64{1, 2, 3} || m.M() != 3 {
+		t.Errorf("PointM, expected xym %v m 3 got %v m %v", [3]float64(m), m.XYM(), m.M())
+	}
+	zm := geom.PointZM{1, 2, 3, 4}
+	if zm.XYZM() != [4]float64{1, 2, 3, 4} || zm.Z() != 3 || zm.M() != 4 {
+		t.Errorf("PointZM, expected xyzm %v z 3 m 4 got %v z %v m %v", [4]float64(zm), zm.XYZM(), zm.Z(), zm.M())
+	}
+
+	// The Z and M points must not be usable as 2D points.
+	for _, g := range []geom.Geometry{z, m, zm} {
+		if _, ok := g.(geom.Pointer); ok {
+			t.Errorf("%T, expected not to be a geom.Pointer", g)
+		}
+	}
+}
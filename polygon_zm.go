@@ -0,0 +1,72 @@
+package geom
+
+import "errors"
+
+// ErrNilPolygonZ is thrown when a PolygonZ is nil but shouldn't be
+var ErrNilPolygonZ = errors.New("geom: nil PolygonZ")
+
+// PolygonZ is a Polygon where the linear rings are made up of 3D coordinates.
+// The same ring rules as for Polygon apply.
+type PolygonZ [][][3]float64
+
+// LinearRings returns the coordinates of the linear rings
+func (p PolygonZ) LinearRings() [][][3]float64 { return p }
+
+// Polygon returns the 2D polygon, dropping the z values
+func (p PolygonZ) Polygon() Polygon { return Polygon(xyOfLines3(p)) }
+
+// SetLinearRings modifies the array of 3D coordinates
+func (p *PolygonZ) SetLinearRings(input [][][3]float64) (err error) {
+	if p == nil {
+		return ErrNilPolygonZ
+	}
+
+	*p = append((*p)[:0], input...)
+	return
+}
+
+// ErrNilPolygonM is thrown when a PolygonM is nil but shouldn't be
+var ErrNilPolygonM = errors.New("geom: nil PolygonM")
+
+// PolygonM is a Polygon where the linear rings are made up of 2D coordinates and measures.
+// The same ring rules as for Polygon apply.
+type PolygonM [][][3]float64
+
+// LinearRings returns the coordinates of the linear rings
+func (p PolygonM) LinearRings() [][][3]float64 { return p }
+
+// Polygon returns the 2D polygon, dropping the m values
+func (p PolygonM) Polygon() Polygon { return Polygon(xyOfLines3(p)) }
+
+// SetLinearRings modifies the array of 2D coordinates and measures
+func (p *PolygonM) SetLinearRings(input [][][3]float64) (err error) {
+	if p == nil {
+		return ErrNilPolygonM
+	}
+
+	*p = append((*p)[:0], input...)
+	return
+}
+
+// ErrNilPolygonZM is thrown when a PolygonZM is nil but shouldn't be
+var ErrNilPolygonZM = errors.New("geom: nil PolygonZM")
+
+// PolygonZM is a Polygon where the linear rings are made up of 3D coordinates and measures.
+// The same ring rules as for Polygon apply.
+type PolygonZM [][][4]float64
+
+// LinearRings returns the coordinates of the linear rings
+func (p PolygonZM) LinearRings() [][][4]float64 { return p }
+
+// Polygon returns the 2D polygon, dropping the z and m values
+func (p PolygonZM) Polygon() Polygon { return Polygon(xyOfLines4(p)) }
+
+// SetLinearRings modifies the array of 3D coordinates and measures
+func (p *PolygonZM) SetLinearRings(input [][][4]float64) (err error) {
+	if p == nil {
+		return ErrNilPolygonZM
+	}
+
+	*p = append((*p)[:0], input...)
+	return
+}
@@ -0,0 +1,121 @@
+package geom_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestPolygonZMSetter(t *testing.T) {
+	type tcase struct {
+		set      func() error
+		setter   geom.Geometry
+		expected geom.Geometry
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			err := tc.set()
+			if tc.err == nil && err != nil {
+				t.Errorf("error, expected nil got %v", err)
+				return
+			}
+			if tc.err != nil {
+				if err == nil || tc.err.Error() != err.Error() {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+
+			// compare the results
+			if !reflect.DeepEqual(tc.expected, tc.setter) {
+				t.Errorf("setter, expected %v got %v", tc.expected, tc.setter)
+			}
+		}
+	}
+
+	tests := map[string]func() tcase{
+		"PolygonZ": func() tcase {
+			g := &geom.PolygonZ{}
+			return tcase{
+				set:      func() error { return g.SetLinearRings([][][3]float64{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}}) },
+				setter:   g,
+				expected: &geom.PolygonZ{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}},
+			}
+		},
+		"nil PolygonZ": func() tcase {
+			var g *geom.PolygonZ
+			return tcase{
+				set: func() error { return g.SetLinearRings([][][3]float64{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}}) },
+				err: geom.ErrNilPolygonZ,
+			}
+		},
+		"PolygonM": func() tcase {
+			g := &geom.PolygonM{}
+			return tcase{
+				set:      func() error { return g.SetLinearRings([][][3]float64{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}}) },
+				setter:   g,
+				expected: &geom.PolygonM{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}},
+			}
+		},
+		"nil PolygonM": func() tcase {
+			var g *geom.PolygonM
+			return tcase{
+				set: func() error { return g.SetLinearRings([][][3]float64{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}}) },
+				err: geom.ErrNilPolygonM,
+			}
+		},
+		"PolygonZM": func() tcase {
+			g := &geom.PolygonZM{}
+			return tcase{
+				set:      func() error { return g.SetLinearRings([][][4]float64{{{0, 0, 1, 1}, {1, 1, 2, 2}, {1, 0, 3, 3}}}) },
+				setter:   g,
+				expected: &geom.PolygonZM{{{0, 0, 1, 1}, {1, 1, 2, 2}, {1, 0, 3, 3}}},
+			}
+		},
+		"nil PolygonZM": func() tcase {
+			var g *geom.PolygonZM
+			return tcase{
+				set: func() error { return g.SetLinearRings([][][4]float64{{{0, 0, 1, 1}, {1, 1, 2, 2}, {1, 0, 3, 3}}}) },
+				err: geom.ErrNilPolygonZM,
+			}
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc()))
+	}
+}
+
+func TestPolygonZMProjection(t *testing.T) {
+	type tcase struct {
+		got      geom.Polygon
+		expected geom.Polygon
+	}
+
+	tests := map[string]tcase{
+		"PolygonZ": {
+			got:      geom.PolygonZ{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}}.Polygon(),
+			expected: geom.Polygon{{{0, 0}, {1, 1}, {1, 0}}},
+		},
+		"PolygonM": {
+			got:      geom.PolygonM{{{0, 0, 1}, {1, 1, 2}, {1, 0, 3}}}.Polygon(),
+			expected: geom.Polygon{{{0, 0}, {1, 1}, {1, 0}}},
+		},
+		"PolygonZM": {
+			got:      geom.PolygonZM{{{0, 0, 1, 1}, {1, 1, 2, 2}, {1, 0, 3, 3}}}.Polygon(),
+			expected: geom.Polygon{{{0, 0}, {1, 1}, {1, 0}}},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.expected, tc.got) {
+				t.Errorf("%v, expected %v got %v", name, tc.expected, tc.got)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package geom
+
+import "math"
+
+// PrecisionModel is a grid that coordinates are rounded to, so that the
+// results of operations are the same from run to run and can be compared
+// exactly. The zero value is floating precision, which does not round
+// coordinates.
+type PrecisionModel struct {
+	// Scale is the number of grid cells in a unit: coordinates are
+	// rounded to the nearest multiple of 1/Scale. Zero, or less, is
+	// floating precision.
+	Scale float64
+}
+
+// NewPrecisionModelGridSize returns the precision model that rounds
+// coordinates to the nearest multiple of the grid size.
+func NewPrecisionModelGridSize(gridSize float64) PrecisionModel {
+	if !(gridSize > 0) {
+		return PrecisionModel{}
+	}
+	return PrecisionModel{Scale: 1 / gridSize}
+}
+
+// IsFloating reports whether the precision model does not round
+// coordinates.
+func (pm PrecisionModel) IsFloating() bool {
+	return !(pm.Scale > 0) || math.IsInf(pm.Scale, 1)
+}
+
+// GridSize returns the distance between grid lines, which is zero for
+// floating precision.
+func (pm PrecisionModel) GridSize() float64 {
+	if pm.IsFloating() {
+		return 0
+	}
+	return 1 / pm.Scale
+}
+
+// MakePrecise rounds the value to the grid; halves are rounded away from
+// zero. NaN and infinite values are returned as they are.
+func (pm PrecisionModel) MakePrecise(v float64) float64 {
+	if pm.IsFloating() || math.IsNaN(v) || math.IsInf(v, 0) {
+		return v
+	}
+	// dividing by the scale when it is above one is exact for the grid
+	// sizes that are written as decimals, such as 0.001
+	if pm.Scale >= 1 {
+		return math.Round(v*pm.Scale) / pm.Scale
+	}
+	g := 1 / pm.Scale
+	return math.Round(v/g) * g
+}
+
+// SnapPoint rounds both coordinates of the point to the grid.
+func (pm PrecisionModel) SnapPoint(pt [2]float64) [2]float64 {
+	return [2]float64{pm.MakePrecise(pt[0]), pm.MakePrecise(pt[1])}
+}
+
+// SnapToGrid returns a copy of the geometry, of the same type, with the x
+// and y of its points rounded to the grid of the precision model. Points
+// that are rounded to the same place are kept, so lines and rings may
+// have repeated points, or collapse, afterwards.
+func SnapToGrid(g Geometry, pm PrecisionModel) (Geometry, error) {
+	return Map(g, func(pt [2]float64) ([2]float64, error) {
+		return pm.SnapPoint(pt), nil
+	})
+}
@@ -0,0 +1,90 @@
+package geom
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestPrecisionModel(t *testing.T) {
+	type tcase struct {
+		pm       PrecisionModel
+		in       float64
+		expected float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got := tc.pm.MakePrecise(tc.in)
+			if got != tc.expected && !(math.IsNaN(got) && math.IsNaN(tc.expected)) {
+				t.Errorf("value, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"floating": {
+			in:       1.23456789,
+			expected: 1.23456789,
+		},
+		"thousandths": {
+			pm:       PrecisionModel{Scale: 1000},
+			in:       1.23456789,
+			expected: 1.235,
+		},
+		"grid size": {
+			pm:       NewPrecisionModelGridSize(0.001),
+			in:       -1.23449,
+			expected: -1.234,
+		},
+		"tens": {
+			pm:       NewPrecisionModelGridSize(10),
+			in:       25,
+			expected: 30,
+		},
+		"half": {
+			pm:       NewPrecisionModelGridSize(0.5),
+			in:       1.3,
+			expected: 1.5,
+		},
+		"infinite": {
+			pm:       PrecisionModel{Scale: 10},
+			in:       math.Inf(-1),
+			expected: math.Inf(-1),
+		},
+		"nan": {
+			pm:       PrecisionModel{Scale: 10},
+			in:       math.NaN(),
+			expected: math.NaN(),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	if !NewPrecisionModelGridSize(0).IsFloating() || NewPrecisionModelGridSize(0).GridSize() != 0 {
+		t.Errorf("zero grid size, expected floating")
+	}
+	if gs := NewPrecisionModelGridSize(0.25).GridSize(); gs != 0.25 {
+		t.Errorf("grid size, expected 0.25 got %v", gs)
+	}
+}
+
+func TestSnapToGrid(t *testing.T) {
+	g := Collection{
+		Point{1.04, 2.06},
+		PolygonZ{{{0.01, 0.02, 7}, {9.96, 0.04, 7}, {10.01, 9.99, 7}}},
+	}
+	got, err := SnapToGrid(g, NewPrecisionModelGridSize(0.1))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	expected := Collection{
+		Point{1, 2.1},
+		PolygonZ{{{0, 0, 7}, {10, 0, 7}, {10, 10, 7}}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("geometry, expected %v got %v", expected, got)
+	}
+}
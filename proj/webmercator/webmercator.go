@@ -0,0 +1,69 @@
+// Package webmercator projects longitude and latitude to and from Web
+// Mercator (EPSG:3857), the projection of slippy map and vector tiles, and
+// gives the size of the pixels of its tiles.
+//
+// Web Mercator projects the WGS 84 longitude and latitude as if they were
+// on a sphere, so it is neither conformal nor equal area on the ellipsoid,
+// and its meters are only meters on the ground at the equator.
+package webmercator
+
+import "math"
+
+const (
+	// Radius is the radius of the sphere of Web Mercator, in meters
+	Radius = 6378137.0
+	// MaxLatitude is the latitude, in degrees, at which the world is
+	// square in Web Mercator; latitudes further from the equator are
+	// clamped to it.
+	MaxLatitude = 85.0511287798066
+	// MaxExtent is the greatest x and y in meters, half the width of the
+	// world, at the longitude 180 and MaxLatitude.
+	MaxExtent = math.Pi * Radius
+	// TileSize is the width and height in pixels of a tile
+	TileSize = 256
+)
+
+// ClampLatitude returns the latitude, in degrees, moved to MaxLatitude or
+// -MaxLatitude if it is further from the equator.
+func ClampLatitude(lat float64) float64 {
+	return math.Max(-MaxLatitude, math.Min(MaxLatitude, lat))
+}
+
+// Forward returns the x and y in meters of the longitude and latitude in
+// degrees. The latitude is clamped first, so the poles, which are
+// infinitely far away, are on the edge of the world.
+func Forward(lon, lat float64) (x, y float64) {
+	lat = ClampLatitude(lat)
+	x = Radius * lon * math.Pi / 180
+	y = Radius * math.Log(math.Tan(math.Pi/4+lat*math.Pi/360))
+	return x, y
+}
+
+// Inverse returns the longitude and latitude in degrees of the x and y in
+// meters.
+func Inverse(x, y float64) (lon, lat float64) {
+	lon = x / Radius * 180 / math.Pi
+	lat = (2*math.Atan(math.Exp(y/Radius)) - math.Pi/2) * 180 / math.Pi
+	return lon, lat
+}
+
+// ResolutionAtZoom returns the width in meters of a pixel of a tile at the
+// zoom, where the world is 2^zoom tiles of TileSize pixels wide. The zoom
+// may be fractional.
+func ResolutionAtZoom(zoom float64) float64 {
+	return 2 * MaxExtent / (TileSize * math.Exp2(zoom))
+}
+
+// ZoomForResolution returns the zoom, which may be fractional, at which a
+// pixel of a tile is res meters wide.
+func ZoomForResolution(res float64) float64 {
+	return math.Log2(2 * MaxExtent / (TileSize * res))
+}
+
+// MetersPerPixel returns the width on the ground in meters of a pixel of a
+// tile at the zoom and the latitude in degrees. Web Mercator stretches
+// everything away from the equator by 1/cos(lat), so a pixel covers less of
+// the ground the further it is from the equator.
+func MetersPerPixel(lat, zoom float64) float64 {
+	return ResolutionAtZoom(zoom) * math.Cos(ClampLatitude(lat)*math.Pi/180)
+}
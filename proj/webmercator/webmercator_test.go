@@ -0,0 +1,115 @@
+package webmercator
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProjection(t *testing.T) {
+	type tcase struct {
+		lonlat [2]float64
+		xy     [2]float64
+		// inverse is the longitude and latitude projected back, if it is
+		// not lonlat
+		inverse *[2]float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			x, y := Forward(tc.lonlat[0], tc.lonlat[1])
+			if math.Abs(x-tc.xy[0]) > 1e-6 || math.Abs(y-tc.xy[1]) > 1e-6 {
+				t.Errorf("forward, expected %v got %v", tc.xy, [2]float64{x, y})
+			}
+			expected := tc.lonlat
+			if tc.inverse != nil {
+				expected = *tc.inverse
+			}
+			lon, lat := Inverse(x, y)
+			if math.Abs(lon-expected[0]) > 1e-9 || math.Abs(lat-expected[1]) > 1e-9 {
+				t.Errorf("inverse, expected %v got %v", expected, [2]float64{lon, lat})
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"origin": {
+			lonlat: [2]float64{0, 0},
+			xy:     [2]float64{0, 0},
+		},
+		"corner": {
+			lonlat: [2]float64{180, MaxLatitude},
+			xy:     [2]float64{20037508.342789244, 20037508.342789244},
+		},
+		"london": {
+			lonlat: [2]float64{-0.1275, 51.507222},
+			xy:     [2]float64{-14193.235076142382, 6711510.640113423},
+		},
+		"north pole": {
+			lonlat:  [2]float64{-180, 90},
+			xy:      [2]float64{-20037508.342789244, 20037508.342789244},
+			inverse: &[2]float64{-180, MaxLatitude},
+		},
+		"south pole": {
+			lonlat:  [2]float64{90, -90},
+			xy:      [2]float64{MaxExtent / 2, -20037508.342789244},
+			inverse: &[2]float64{90, -MaxLatitude},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestResolution(t *testing.T) {
+	type tcase struct {
+		lat, zoom float64
+		res       float64
+		ground    float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if res := ResolutionAtZoom(tc.zoom); math.Abs(res-tc.res) > 1e-9 {
+				t.Errorf("resolution, expected %v got %v", tc.res, res)
+			}
+			if zoom := ZoomForResolution(tc.res); math.Abs(zoom-tc.zoom) > 1e-9 {
+				t.Errorf("zoom, expected %v got %v", tc.zoom, zoom)
+			}
+			if m := MetersPerPixel(tc.lat, tc.zoom); math.Abs(m-tc.ground) > 1e-9 {
+				t.Errorf("meters per pixel, expected %v got %v", tc.ground, m)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"zoom 0": {
+			lat:    0,
+			zoom:   0,
+			res:    156543.03392804097,
+			ground: 156543.03392804097,
+		},
+		"zoom 10 at 60": {
+			lat:    60,
+			zoom:   10,
+			res:    156543.03392804097 / 1024,
+			ground: 156543.03392804097 / 2048,
+		},
+		"fractional zoom": {
+			lat:    -60,
+			zoom:   0.5,
+			res:    156543.03392804097 / math.Sqrt2,
+			ground: 156543.03392804097 / math.Sqrt2 / 2,
+		},
+		"pole": {
+			lat:    90,
+			zoom:   0,
+			res:    156543.03392804097,
+			ground: 156543.03392804097 * math.Cos(MaxLatitude*math.Pi/180),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
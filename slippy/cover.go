@@ -0,0 +1,70 @@
+package slippy
+
+import (
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+)
+
+// Cover returns the tiles at zoom z that intersect the geometry, including
+// tiles it only touches. The geometry is assumed to be in EPSG:4326
+// (lng/lat). The tiles are sorted by column, then by row, the same as
+// FromBounds.
+//
+// The tiles are found by starting from the tile at zoom 0 and only looking
+// at the children of tiles the geometry intersects, so geometries that cover
+// a small part of a large extent do not test every tile in the extent.
+func Cover(g geom.Geometry, z uint) ([]Tile, error) {
+	pg, err := planar.Prepare(g)
+	if err != nil {
+		return nil, err
+	}
+
+	var tiles []Tile
+	var visit func(t *Tile) error
+	visit = func(t *Tile) error {
+		poly := t.Extent4326().AsPolygon()
+		ok, err := pg.Intersects(poly)
+		if err != nil || !ok {
+			return err
+		}
+		if t.Z == z {
+			tiles = append(tiles, *t)
+			return nil
+		}
+		covered, err := pg.Covers(poly)
+		if err != nil {
+			return err
+		}
+		if covered {
+			// every tile in t intersects the geometry
+			return t.RangeFamilyAt(z, func(tile *Tile) error {
+				tiles = append(tiles, *tile)
+				return nil
+			})
+		}
+		for _, child := range t.Children() {
+			if err := visit(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := visit(NewTile(0, 0, 0)); err != nil {
+		return nil, err
+	}
+
+	sortTiles(tiles)
+	return tiles, nil
+}
+
+// sortTiles sorts the tiles by column, then by row
+func sortTiles(tiles []Tile) {
+	sort.Slice(tiles, func(i, j int) bool {
+		if tiles[i].X != tiles[j].X {
+			return tiles[i].X < tiles[j].X
+		}
+		return tiles[i].Y < tiles[j].Y
+	})
+}
@@ -11,6 +11,10 @@ import (
 // MaxZoom is the lowest zoom (furthest in)
 const MaxZoom = 22
 
+// ErrInvalidQuadkey is returned by NewTileQuadkey when the quadkey can not
+// be decoded
+var ErrInvalidQuadkey = errors.New("slippy: invalid quadkey")
+
 // NewTile returns a Tile of Z,X,Y passed in
 func NewTile(z, x, y uint) *Tile {
 	return &Tile{
@@ -138,3 +142,97 @@ func (t Tile) RangeFamilyAt(zoom uint, f func(*Tile) error) error {
 
 	return nil
 }
+
+// NewTile3857 instantiates a tile containing the EPSG:3857 (aka Web
+// Mercator) coordinate with the specified zoom
+func NewTile3857(z uint, x, y float64) *Tile {
+	return &Tile{
+		Z: z,
+		X: WebX2Tile(z, x),
+		Y: WebY2Tile(z, y),
+	}
+}
+
+// FromBounds3857 returns a list of tiles that make up the bound given. The
+// bounds should be in EPSG:3857 (aka Web Mercator) [4]float64{minx,miny,maxx,maxy}
+func FromBounds3857(bounds *geom.Extent, z uint) []Tile {
+	if bounds == nil {
+		return nil
+	}
+
+	minx, maxx := minmax(WebX2Tile(z, bounds[0]), WebX2Tile(z, bounds[2]))
+	miny, maxy := minmax(WebY2Tile(z, bounds[1]), WebY2Tile(z, bounds[3]))
+	var tiles []Tile
+	for x := minx; x <= maxx; x++ {
+		for y := miny; y <= maxy; y++ {
+			tiles = append(tiles, Tile{Z: z, X: x, Y: y})
+		}
+	}
+	return tiles
+}
+
+// Parent returns the tile one zoom out that contains t, or nil if t is at
+// zoom 0
+func (t Tile) Parent() *Tile {
+	if t.Z == 0 {
+		return nil
+	}
+	return NewTile(t.Z-1, t.X>>1, t.Y>>1)
+}
+
+// Children returns the four tiles one zoom in that make up t, in the order
+// top left, top right, bottom left, bottom right
+func (t Tile) Children() [4]*Tile {
+	x, y := t.X<<1, t.Y<<1
+	return [4]*Tile{
+		NewTile(t.Z+1, x, y),
+		NewTile(t.Z+1, x+1, y),
+		NewTile(t.Z+1, x, y+1),
+		NewTile(t.Z+1, x+1, y+1),
+	}
+}
+
+// Quadkey returns the quadkey of the tile: one digit for each zoom level,
+// where each digit picks one of the four children of the tile before it.
+// The tile at zoom 0 has an empty quadkey.
+func (t Tile) Quadkey() string {
+	key := make([]byte, t.Z)
+	for i := uint(0); i < t.Z; i++ {
+		mask := uint(1) << (t.Z - 1 - i)
+		digit := byte('0')
+		if t.X&mask != 0 {
+			digit++
+		}
+		if t.Y&mask != 0 {
+			digit += 2
+		}
+		key[i] = digit
+	}
+	return string(key)
+}
+
+// NewTileQuadkey returns the tile of the quadkey. It returns
+// ErrInvalidQuadkey if the key has digits other than 0 to 3, or is longer
+// than MaxZoom.
+func NewTileQuadkey(key string) (*Tile, error) {
+	if len(key) > MaxZoom {
+		return nil, ErrInvalidQuadkey
+	}
+	t := NewTile(uint(len(key)), 0, 0)
+	for i := 0; i < len(key); i++ {
+		mask := uint(1) << uint(len(key)-1-i)
+		switch key[i] {
+		case '0':
+		case '1':
+			t.X |= mask
+		case '2':
+			t.Y |= mask
+		case '3':
+			t.X |= mask
+			t.Y |= mask
+		default:
+			return nil, ErrInvalidQuadkey
+		}
+	}
+	return t, nil
+}
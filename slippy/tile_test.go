@@ -407,3 +407,127 @@ func TestFromBounds(t *testing.T) {
 	}
 
 }
+
+func TestTileFamily(t *testing.T) {
+	tile := slippy.NewTile(3, 5, 2)
+	if p := tile.Parent(); !reflect.DeepEqual(p, slippy.NewTile(2, 2, 1)) {
+		t.Errorf("parent, expected %v got %v", slippy.NewTile(2, 2, 1), p)
+	}
+	if p := slippy.NewTile(0, 0, 0).Parent(); p != nil {
+		t.Errorf("parent of zoom 0, expected nil got %v", p)
+	}
+	expected := [4]*slippy.Tile{
+		slippy.NewTile(4, 10, 4),
+		slippy.NewTile(4, 11, 4),
+		slippy.NewTile(4, 10, 5),
+		slippy.NewTile(4, 11, 5),
+	}
+	children := tile.Children()
+	if !reflect.DeepEqual(children, expected) {
+		t.Errorf("children, expected %v got %v", expected, children)
+	}
+	for _, child := range children {
+		if !reflect.DeepEqual(child.Parent(), tile) {
+			t.Errorf("parent of %v, expected %v got %v", child, tile, child.Parent())
+		}
+	}
+}
+
+func TestQuadkey(t *testing.T) {
+	type tcase struct {
+		tile *slippy.Tile
+		key  string
+	}
+	tests := map[string]tcase{
+		"zoom 0": {tile: slippy.NewTile(0, 0, 0), key: ""},
+		"zoom 1": {tile: slippy.NewTile(1, 1, 0), key: "1"},
+		"zoom 3": {tile: slippy.NewTile(3, 3, 5), key: "213"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if key := tc.tile.Quadkey(); key != tc.key {
+				t.Errorf("quadkey, expected %q got %q", tc.key, key)
+			}
+			tile, err := slippy.NewTileQuadkey(tc.key)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(tile, tc.tile) {
+				t.Errorf("tile, expected %v got %v", tc.tile, tile)
+			}
+		})
+	}
+
+	for _, key := range []string{"124", "0123456789012345678901230"} {
+		if _, err := slippy.NewTileQuadkey(key); err != slippy.ErrInvalidQuadkey {
+			t.Errorf("quadkey %q, expected %v got %v", key, slippy.ErrInvalidQuadkey, err)
+		}
+	}
+}
+
+func TestNewTile3857(t *testing.T) {
+	tile := slippy.NewTile(13, 8054, 2677)
+	ext := tile.Extent3857()
+	center := [2]float64{(ext[0] + ext[2]) / 2, (ext[1] + ext[3]) / 2}
+	if got := slippy.NewTile3857(13, center[0], center[1]); !reflect.DeepEqual(got, tile) {
+		t.Errorf("tile, expected %v got %v", tile, got)
+	}
+
+	tiles := slippy.FromBounds3857(&geom.Extent{ext[0] + 1, ext[1] + 1, ext[2] - 1, ext[3] - 1}, 14)
+	expected := []slippy.Tile{{14, 16108, 5354}, {14, 16108, 5355}, {14, 16109, 5354}, {14, 16109, 5355}}
+	if !reflect.DeepEqual(tiles, expected) {
+		t.Errorf("tiles, expected %v got %v", expected, tiles)
+	}
+}
+
+func TestCover(t *testing.T) {
+	type tcase struct {
+		g        geom.Geometry
+		z        uint
+		expected []slippy.Tile
+	}
+	tests := map[string]tcase{
+		"point": {
+			g:        geom.Point{10, 10},
+			z:        2,
+			expected: []slippy.Tile{{2, 2, 1}},
+		},
+		"line": {
+			// goes through the corner of the four middle tiles
+			g: geom.LineString{{-10, 10}, {10, -10}},
+			z: 2,
+			expected: []slippy.Tile{
+				{2, 1, 1}, {2, 1, 2}, {2, 2, 1}, {2, 2, 2},
+			},
+		},
+		"short line": {
+			g:        geom.LineString{{-10, 10}, {-5, 5}},
+			z:        2,
+			expected: []slippy.Tile{{2, 1, 1}},
+		},
+		"polygon": {
+			g: geom.Polygon{{{-100, -10}, {100, -10}, {100, 10}, {-100, 10}}},
+			z: 2,
+			expected: []slippy.Tile{
+				{2, 0, 1}, {2, 0, 2}, {2, 1, 1}, {2, 1, 2},
+				{2, 2, 1}, {2, 2, 2}, {2, 3, 1}, {2, 3, 2},
+			},
+		},
+		"covered": {
+			g:        geom.Polygon{{{-180, -85.1}, {180, -85.1}, {180, 85.1}, {-180, 85.1}}},
+			z:        1,
+			expected: []slippy.Tile{{1, 0, 0}, {1, 0, 1}, {1, 1, 0}, {1, 1, 1}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tiles, err := slippy.Cover(tc.g, tc.z)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(tiles, tc.expected) {
+				t.Errorf("tiles, expected %v got %v", tc.expected, tiles)
+			}
+		})
+	}
+}
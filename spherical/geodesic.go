@@ -0,0 +1,251 @@
+package spherical
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNotConverged is returned when the distance between two points can not
+// be calculated, which happens for points that are nearly antipodal.
+var ErrNotConverged = errors.New("spherical: geodesic did not converge")
+
+// Ellipsoid is an ellipsoid of revolution the geodesic calculations are
+// done on. Points are long/lat pairs in degrees, distances are in the units
+// of the semi-major axis, and bearings are in degrees clockwise from north.
+type Ellipsoid struct {
+	// A is the semi-major axis, the equatorial radius
+	A float64
+	// F is the flattening, zero for a sphere
+	F float64
+}
+
+// WGS84 is the ellipsoid of the World Geodetic System 1984, in meters.
+var WGS84 = Ellipsoid{A: 6378137, F: 1 / 298.257223563}
+
+// Sphere returns a sphere with the given radius.
+func Sphere(radius float64) Ellipsoid { return Ellipsoid{A: radius} }
+
+// b returns the semi-minor axis
+func (e Ellipsoid) b() float64 { return e.A * (1 - e.F) }
+
+// Distance returns the length of the geodesic between the points, using
+// Vincenty's inverse formula.
+func (e Ellipsoid) Distance(p1, p2 [2]float64) (float64, error) {
+	d, _, _, err := e.Inverse(p1, p2)
+	return d, err
+}
+
+// Inverse returns the length of the geodesic between the points, the
+// bearing it starts with at p1 and the bearing it ends with at p2, using
+// Vincenty's inverse formula. ErrNotConverged is returned for nearly
+// antipodal points.
+func (e Ellipsoid) Inverse(p1, p2 [2]float64) (dist, bearing1, bearing2 float64, err error) {
+	a, b, f := e.A, e.b(), e.F
+	L := radians(p2[0] - p1[0])
+	sinU1, cosU1 := reducedLatitude(f, radians(p1[1]))
+	sinU2, cosU2 := reducedLatitude(f, radians(p2[1]))
+
+	var (
+		sinLambda, cosLambda float64
+		sinSigma, cosSigma   float64
+		sigma                float64
+		sinAlpha, cos2Alpha  float64
+		cos2SigmaM           float64
+		converged            bool
+	)
+	lambda := L
+	for i := 0; i < 200; i++ {
+		sinLambda, cosLambda = math.Sincos(lambda)
+		sinSigma = math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			// the points are the same
+			return 0, 0, 0, nil
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha = cosU1 * cosU2 * sinLambda / sinSigma
+		cos2Alpha = 1 - sinAlpha*sinAlpha
+		cos2SigmaM = 0
+		if cos2Alpha != 0 {
+			// zero on the equator
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cos2Alpha
+		}
+		C := f / 16 * cos2Alpha * (4 + f*(4-3*cos2Alpha))
+		prev := lambda
+		lambda = L + (1-C)*f*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda) > math.Pi+1e-9 && f != 0 {
+			break
+		}
+		if math.Abs(lambda-prev) < 1e-12 {
+			converged = true
+			break
+		}
+	}
+	if !converged {
+		return 0, 0, 0, ErrNotConverged
+	}
+
+	A, B := vincentyAB(a, b, cos2Alpha)
+	deltaSigma := vincentyDeltaSigma(B, sinSigma, cosSigma, cos2SigmaM)
+	dist = b * A * (sigma - deltaSigma)
+
+	bearing1 = math.Atan2(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+	bearing2 = math.Atan2(cosU1*sinLambda, -sinU1*cosU2+cosU1*sinU2*cosLambda)
+	return dist, normalizeBearing(degrees(bearing1)), normalizeBearing(degrees(bearing2)), nil
+}
+
+// Destination returns the point reached by following the geodesic starting
+// at p with the bearing for the distance, and the bearing at that point,
+// using Vincenty's direct formula.
+func (e Ellipsoid) Destination(p [2]float64, bearing, dist float64) (pt [2]float64, finalBearing float64) {
+	a, b, f := e.A, e.b(), e.F
+	sinAlpha1, cosAlpha1 := math.Sincos(radians(bearing))
+	sinU1, cosU1 := reducedLatitude(f, radians(p[1]))
+
+	sigma1 := math.Atan2(sinU1/cosU1, cosAlpha1)
+	sinAlpha := cosU1 * sinAlpha1
+	cos2Alpha := 1 - sinAlpha*sinAlpha
+	A, B := vincentyAB(a, b, cos2Alpha)
+
+	var sinSigma, cosSigma, cos2SigmaM float64
+	sigma := dist / (b * A)
+	for i := 0; i < 200; i++ {
+		cos2SigmaM = math.Cos(2*sigma1 + sigma)
+		sinSigma, cosSigma = math.Sincos(sigma)
+		prev := sigma
+		sigma = dist/(b*A) + vincentyDeltaSigma(B, sinSigma, cosSigma, cos2SigmaM)
+		if math.Abs(sigma-prev) < 1e-12 {
+			break
+		}
+	}
+	cos2SigmaM = math.Cos(2*sigma1 + sigma)
+	sinSigma, cosSigma = math.Sincos(sigma)
+
+	tmp := sinU1*sinSigma - cosU1*cosSigma*cosAlpha1
+	lat := math.Atan2(sinU1*cosSigma+cosU1*sinSigma*cosAlpha1, (1-f)*math.Hypot(sinAlpha, tmp))
+	lambda := math.Atan2(sinSigma*sinAlpha1, cosU1*cosSigma-sinU1*sinSigma*cosAlpha1)
+	C := f / 16 * cos2Alpha * (4 + f*(4-3*cos2Alpha))
+	L := lambda - (1-C)*f*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+
+	pt = [2]float64{normalizeLng(p[0] + degrees(L)), degrees(lat)}
+	return pt, normalizeBearing(degrees(math.Atan2(sinAlpha, -tmp)))
+}
+
+// Intermediate returns the point the fraction of the way along the
+// geodesic from p1 to p2. A fraction of 0 is p1 and 1 is p2.
+func (e Ellipsoid) Intermediate(p1, p2 [2]float64, fraction float64) ([2]float64, error) {
+	dist, bearing, _, err := e.Inverse(p1, p2)
+	if err != nil {
+		return [2]float64{}, err
+	}
+	pt, _ := e.Destination(p1, bearing, dist*fraction)
+	return pt, nil
+}
+
+// Area returns the area of the polygon, the area of its first ring less
+// the area of the others. The rings may be in either direction and closed
+// or not, but must not contain a pole.
+//
+// The area is calculated on the sphere with the same surface area as the
+// ellipsoid, using authalic latitudes, which is within a small fraction of
+// a percent of the geodesic area for all but continent sized polygons.
+func (e Ellipsoid) Area(polygon [][][2]float64) float64 {
+	var area float64
+	for i, ring := range polygon {
+		a := math.Abs(e.ringArea(ring))
+		if i == 0 {
+			area += a
+		} else {
+			area -= a
+		}
+	}
+	return area
+}
+
+// ringArea returns the signed area of the ring, positive for counter
+// clockwise rings
+func (e Ellipsoid) ringArea(ring [][2]float64) float64 {
+	if len(ring) < 3 {
+		return 0
+	}
+	var excess float64
+	prev := ring[len(ring)-1]
+	for _, pt := range ring {
+		dLng := radians(normalizeLng(pt[0] - prev[0]))
+		t1 := math.Tan(e.authalicLatitude(radians(prev[1])) / 2)
+		t2 := math.Tan(e.authalicLatitude(radians(pt[1])) / 2)
+		excess += 2 * math.Atan2(math.Tan(dLng/2)*(t1+t2), 1+t1*t2)
+		prev = pt
+	}
+	r := e.authalicRadius()
+	return excess * r * r
+}
+
+// eccentricity returns the first eccentricity of the ellipsoid
+func (e Ellipsoid) eccentricity() float64 { return math.Sqrt(e.F * (2 - e.F)) }
+
+// authalicRadius returns the radius of the sphere with the same surface
+// area as the ellipsoid
+func (e Ellipsoid) authalicRadius() float64 {
+	ecc := e.eccentricity()
+	if ecc == 0 {
+		return e.A
+	}
+	b := e.b()
+	return math.Sqrt(e.A*e.A/2 + b*b/2*math.Atanh(ecc)/ecc)
+}
+
+// authalicLatitude returns the latitude on the authalic sphere of the
+// latitude, both in radians
+func (e Ellipsoid) authalicLatitude(lat float64) float64 {
+	ecc := e.eccentricity()
+	if ecc == 0 {
+		return lat
+	}
+	q := func(sinLat float64) float64 {
+		es := ecc * sinLat
+		return (1 - ecc*ecc) * (sinLat/(1-es*es) + math.Atanh(es)/ecc)
+	}
+	return math.Asin(math.Max(-1, math.Min(1, q(math.Sin(lat))/q(1))))
+}
+
+// reducedLatitude returns the sine and cosine of the reduced latitude of
+// the latitude in radians
+func reducedLatitude(f, lat float64) (sin, cos float64) {
+	tanU := (1 - f) * math.Tan(lat)
+	cos = 1 / math.Sqrt(1+tanU*tanU)
+	return tanU * cos, cos
+}
+
+func vincentyAB(a, b, cos2Alpha float64) (A, B float64) {
+	u2 := cos2Alpha * (a*a - b*b) / (b * b)
+	A = 1 + u2/16384*(4096+u2*(-768+u2*(320-175*u2)))
+	B = u2 / 1024 * (256 + u2*(-128+u2*(74-47*u2)))
+	return A, B
+}
+
+func vincentyDeltaSigma(B, sinSigma, cosSigma, cos2SigmaM float64) float64 {
+	c2 := cos2SigmaM * cos2SigmaM
+	return B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*c2)-B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*c2)))
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
+
+// normalizeLng returns the longitude in the range [-180, 180)
+func normalizeLng(lng float64) float64 {
+	lng = math.Mod(lng+180, 360)
+	if lng < 0 {
+		lng += 360
+	}
+	return lng - 180
+}
+
+// normalizeBearing returns the bearing in the range [0, 360)
+func normalizeBearing(bearing float64) float64 {
+	bearing = math.Mod(bearing, 360)
+	if bearing < 0 {
+		bearing += 360
+	}
+	return bearing
+}
@@ -0,0 +1,176 @@
+package spherical
+
+import (
+	"math"
+	"testing"
+)
+
+// dms converts degrees, minutes and seconds to degrees
+func dms(d, m, s float64) float64 {
+	if d < 0 {
+		return d - m/60 - s/3600
+	}
+	return d + m/60 + s/3600
+}
+
+// Vincenty's example of the line from Flinders Peak to Buninyong
+var (
+	flindersPeak = [2]float64{dms(144, 25, 29.52440), dms(-37, 57, 3.72030)}
+	buninyong    = [2]float64{dms(143, 55, 35.38390), dms(-37, 39, 10.15610)}
+)
+
+func TestInverse(t *testing.T) {
+	type tcase struct {
+		ellipsoid Ellipsoid
+		p1, p2    [2]float64
+		dist      float64
+		bearing1  float64
+		bearing2  float64
+		err       error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			dist, b1, b2, err := tc.ellipsoid.Inverse(tc.p1, tc.p2)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if math.Abs(dist-tc.dist) > 1e-3 {
+				t.Errorf("distance, expected %v got %v", tc.dist, dist)
+			}
+			if math.Abs(b1-tc.bearing1) > 1e-6 {
+				t.Errorf("initial bearing, expected %v got %v", tc.bearing1, b1)
+			}
+			if math.Abs(b2-tc.bearing2) > 1e-6 {
+				t.Errorf("final bearing, expected %v got %v", tc.bearing2, b2)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"flinders peak to buninyong": {
+			ellipsoid: WGS84,
+			p1:        flindersPeak,
+			p2:        buninyong,
+			dist:      54972.271,
+			bearing1:  dms(306, 52, 5.37),
+			bearing2:  dms(307, 10, 25.07),
+		},
+		"same point": {
+			ellipsoid: WGS84,
+			p1:        [2]float64{10, 20},
+			p2:        [2]float64{10, 20},
+		},
+		"equator": {
+			ellipsoid: WGS84,
+			p1:        [2]float64{0, 0},
+			p2:        [2]float64{1, 0},
+			dist:      WGS84.A * math.Pi / 180,
+			bearing1:  90,
+			bearing2:  90,
+		},
+		"sphere quarter": {
+			ellipsoid: Sphere(1000),
+			p1:        [2]float64{0, 0},
+			p2:        [2]float64{0, 90},
+			dist:      1000 * math.Pi / 2,
+		},
+		"antipodal": {
+			ellipsoid: WGS84,
+			p1:        [2]float64{0, 0},
+			p2:        [2]float64{179.7, 0.5},
+			err:       ErrNotConverged,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDestination(t *testing.T) {
+	pt, bearing := WGS84.Destination(flindersPeak, dms(306, 52, 5.37), 54972.271)
+	if math.Abs(pt[0]-buninyong[0]) > 1e-7 || math.Abs(pt[1]-buninyong[1]) > 1e-7 {
+		t.Errorf("destination, expected %v got %v", buninyong, pt)
+	}
+	if expected := dms(307, 10, 25.07); math.Abs(bearing-expected) > 1e-6 {
+		t.Errorf("final bearing, expected %v got %v", expected, bearing)
+	}
+
+	// crossing the antimeridian
+	pt, _ = Sphere(1000).Destination([2]float64{179, 0}, 90, 1000*math.Pi/90)
+	if math.Abs(pt[0]+179) > 1e-9 || math.Abs(pt[1]) > 1e-9 {
+		t.Errorf("destination, expected [-179 0] got %v", pt)
+	}
+}
+
+func TestIntermediate(t *testing.T) {
+	mid, err := WGS84.Intermediate(flindersPeak, buninyong, 0.5)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	d1, _ := WGS84.Distance(flindersPeak, mid)
+	d2, _ := WGS84.Distance(mid, buninyong)
+	if math.Abs(d1-d2) > 1e-6 || math.Abs(d1+d2-54972.271) > 1e-3 {
+		t.Errorf("distances to the middle, got %v and %v", d1, d2)
+	}
+}
+
+func TestArea(t *testing.T) {
+	type tcase struct {
+		ellipsoid Ellipsoid
+		polygon   [][][2]float64
+		area      float64
+		// tolerance is relative to the area
+		tolerance float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			area := tc.ellipsoid.Area(tc.polygon)
+			if math.Abs(area-tc.area) > tc.tolerance*tc.area {
+				t.Errorf("area, expected %v got %v", tc.area, area)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"sphere octant": {
+			ellipsoid: Sphere(1),
+			polygon:   [][][2]float64{{{0, 0}, {90, 0}, {0, 90}}},
+			area:      math.Pi / 2,
+			tolerance: 1e-12,
+		},
+		"clockwise closed": {
+			ellipsoid: Sphere(1),
+			polygon:   [][][2]float64{{{0, 0}, {0, 90}, {90, 0}, {0, 0}}},
+			area:      math.Pi / 2,
+			tolerance: 1e-12,
+		},
+		"one degree at the equator": {
+			ellipsoid: WGS84,
+			polygon:   [][][2]float64{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}},
+			area:      12308778361.469,
+			tolerance: 1e-4,
+		},
+		"hole": {
+			ellipsoid: WGS84,
+			polygon: [][][2]float64{
+				{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+				{{0, 0}, {0, 0.5}, {0.5, 0.5}, {0.5, 0}},
+			},
+			area:      12308778361.469 - WGS84.Area([][][2]float64{{{0, 0}, {0.5, 0}, {0.5, 0.5}, {0, 0.5}}}),
+			tolerance: 1e-4,
+		},
+		"antimeridian": {
+			ellipsoid: WGS84,
+			polygon:   [][][2]float64{{{179.5, 0}, {-179.5, 0}, {-179.5, 1}, {179.5, 1}}},
+			area:      12308778361.469,
+			tolerance: 1e-4,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,155 @@
+package spherical
+
+import (
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// ErrAntipodal is returned when the great circle between two points is
+// asked for, and they are on opposite sides of the sphere, so any great
+// circle through one goes through the other.
+var ErrAntipodal = errors.New("spherical: points are antipodal")
+
+// EarthRadius is the mean radius of the Earth in meters, of the sphere the
+// great circle and rhumb line calculations are done on. They are simpler
+// and faster than those of an Ellipsoid, and within half a percent of them.
+const EarthRadius = 6371008.8
+
+// GreatCircleDistance returns the length in meters of the great circle arc
+// between the points, by the haversine formula.
+func GreatCircleDistance(p1, p2 [2]float64) float64 {
+	return angularDistance(p1, p2) * EarthRadius
+}
+
+// angularDistance returns the angle in radians between the points from the
+// center of the sphere.
+func angularDistance(p1, p2 [2]float64) float64 {
+	lat1, lat2 := radians(p1[1]), radians(p2[1])
+	sinLat := math.Sin((lat2 - lat1) / 2)
+	sinLng := math.Sin(radians(p2[0]-p1[0]) / 2)
+	h := sinLat*sinLat + math.Cos(lat1)*math.Cos(lat2)*sinLng*sinLng
+	return 2 * math.Asin(math.Sqrt(math.Min(1, h)))
+}
+
+// Bearing returns the bearing the great circle from p1 to p2 starts with,
+// in degrees clockwise from north. The bearing changes along the way, unless
+// the points are on the equator or a meridian.
+func Bearing(p1, p2 [2]float64) float64 {
+	lat1, lat2 := radians(p1[1]), radians(p2[1])
+	dLng := radians(p2[0] - p1[0])
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+	return normalizeBearing(degrees(math.Atan2(y, x)))
+}
+
+// Destination returns the point reached by following the great circle
+// starting at p with the bearing for the distance in meters.
+func Destination(p [2]float64, bearing, dist float64) [2]float64 {
+	lat1, lng1 := radians(p[1]), radians(p[0])
+	sinB, cosB := math.Sincos(radians(bearing))
+	sinD, cosD := math.Sincos(dist / EarthRadius)
+
+	sinLat2 := math.Sin(lat1)*cosD + math.Cos(lat1)*sinD*cosB
+	lat2 := math.Asin(math.Max(-1, math.Min(1, sinLat2)))
+	lng2 := lng1 + math.Atan2(sinB*sinD*math.Cos(lat1), cosD-math.Sin(lat1)*sinLat2)
+	return [2]float64{normalizeLng(degrees(lng2)), degrees(lat2)}
+}
+
+// Interpolate returns the point the fraction of the way along the great
+// circle from p1 to p2. A fraction of 0 is p1 and 1 is p2. ErrAntipodal is
+// returned if the points are antipodal, so there is no one great circle
+// between them.
+func Interpolate(p1, p2 [2]float64, fraction float64) ([2]float64, error) {
+	d := angularDistance(p1, p2)
+	if d == 0 {
+		return p1, nil
+	}
+	sinD := math.Sin(d)
+	if sinD < 1e-12 {
+		return [2]float64{}, ErrAntipodal
+	}
+	a := math.Sin((1-fraction)*d) / sinD
+	b := math.Sin(fraction*d) / sinD
+
+	v1, v2 := toVector(p1), toVector(p2)
+	var v [3]float64
+	for i := range v {
+		v[i] = a*v1[i] + b*v2[i]
+	}
+	return fromVector(v), nil
+}
+
+// toVector returns the point as a unit vector from the center of the sphere
+func toVector(p [2]float64) [3]float64 {
+	sinLat, cosLat := math.Sincos(radians(p[1]))
+	sinLng, cosLng := math.Sincos(radians(p[0]))
+	return [3]float64{cosLat * cosLng, cosLat * sinLng, sinLat}
+}
+
+// fromVector returns the point of the vector from the center of the sphere
+func fromVector(v [3]float64) [2]float64 {
+	lat := math.Atan2(v[2], math.Hypot(v[0], v[1]))
+	return [2]float64{normalizeLng(degrees(math.Atan2(v[1], v[0]))), degrees(lat)}
+}
+
+// GreatCircleLine returns the great circle from p1 to p2 as a line of
+// npoints points evenly spaced along it, at least the two of p1 and p2, for
+// drawing on a map. Where the line crosses the antimeridian it is split in
+// two, the first part ending at a longitude of 180 or -180 and the second
+// starting on the other side at the same latitude, so it is not drawn the
+// long way round the map. ErrAntipodal is returned if the points are
+// antipodal.
+func GreatCircleLine(p1, p2 [2]float64, npoints int) (geom.MultiLineString, error) {
+	if npoints < 2 {
+		npoints = 2
+	}
+	pts := make([][2]float64, npoints)
+	for i := range pts {
+		pt, err := Interpolate(p1, p2, float64(i)/float64(npoints-1))
+		if err != nil {
+			return nil, err
+		}
+		pts[i] = pt
+	}
+	// the ends as they were given, rather than calculated
+	pts[0], pts[npoints-1] = p1, p2
+
+	var (
+		mls  geom.MultiLineString
+		line = [][2]float64{pts[0]}
+	)
+	for _, pt := range pts[1:] {
+		prev := line[len(line)-1]
+		if math.Abs(pt[0]-prev[0]) <= 180 {
+			line = append(line, pt)
+			continue
+		}
+		// the side of the antimeridian the line goes off at
+		side := 180.0
+		if prev[0] < 0 {
+			side = -180
+		}
+		lat := antimeridianLatitude(prev, pt)
+		line = append(line, [2]float64{side, lat})
+		mls = append(mls, line)
+		line = [][2]float64{{-side, lat}, pt}
+	}
+	return append(mls, line), nil
+}
+
+// antimeridianLatitude returns the latitude at which the great circle
+// between the points, which are on either side of the antimeridian,
+// crosses it.
+func antimeridianLatitude(p1, p2 [2]float64) float64 {
+	lat1, lat2 := radians(p1[1]), radians(p2[1])
+	lng1, lng2 := radians(p1[0]), radians(p2[0])
+	den := math.Cos(lat1) * math.Cos(lat2) * math.Sin(lng1-lng2)
+	if math.Abs(den) < 1e-12 {
+		// the great circle goes through a pole
+		return (p1[1] + p2[1]) / 2
+	}
+	num := math.Sin(lat1)*math.Cos(lat2)*math.Sin(math.Pi-lng2) - math.Sin(lat2)*math.Cos(lat1)*math.Sin(math.Pi-lng1)
+	return degrees(math.Atan(num / den))
+}
@@ -0,0 +1,165 @@
+package spherical
+
+import (
+	"math"
+	"testing"
+)
+
+// near reports whether the points are within 1e-9 degrees of each other,
+// taking longitudes of 180 and -180 as the same, and any longitude at the
+// poles
+func near(a, b [2]float64) bool {
+	if math.Abs(a[1]-b[1]) >= 1e-9 {
+		return false
+	}
+	return 90-math.Abs(a[1]) < 1e-9 || math.Abs(normalizeLng(a[0]-b[0]+1e-10)-1e-10) < 1e-9
+}
+
+func TestGreatCircle(t *testing.T) {
+	type tcase struct {
+		p1, p2   [2]float64
+		dist     float64
+		bearing  float64
+		midpoint [2]float64
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			mid, err := Interpolate(tc.p1, tc.p2, 0.5)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if !near(mid, tc.midpoint) {
+				t.Errorf("midpoint, expected %v got %v", tc.midpoint, mid)
+			}
+			dist := GreatCircleDistance(tc.p1, tc.p2)
+			if math.Abs(dist-tc.dist) > 1e-3 {
+				t.Errorf("distance, expected %v got %v", tc.dist, dist)
+			}
+			b := Bearing(tc.p1, tc.p2)
+			if math.Abs(b-tc.bearing) > 1e-9 {
+				t.Errorf("bearing, expected %v got %v", tc.bearing, b)
+			}
+			if dest := Destination(tc.p1, b, dist); !near(dest, tc.p2) {
+				t.Errorf("destination, expected %v got %v", tc.p2, dest)
+			}
+			if dest := Destination(tc.p1, b, dist/2); !near(dest, mid) {
+				t.Errorf("destination half way, expected %v got %v", mid, dest)
+			}
+		}
+	}
+
+	degree := EarthRadius * math.Pi / 180
+	tests := map[string]tcase{
+		"equator": {
+			p1:       [2]float64{0, 0},
+			p2:       [2]float64{90, 0},
+			dist:     90 * degree,
+			bearing:  90,
+			midpoint: [2]float64{45, 0},
+		},
+		"meridian": {
+			p1:       [2]float64{10, 20},
+			p2:       [2]float64{10, -20},
+			dist:     40 * degree,
+			bearing:  180,
+			midpoint: [2]float64{10, 0},
+		},
+		"antimeridian": {
+			p1:       [2]float64{170, 0},
+			p2:       [2]float64{-170, 0},
+			dist:     20 * degree,
+			bearing:  90,
+			midpoint: [2]float64{180, 0},
+		},
+		"over the pole": {
+			p1:       [2]float64{0, 80},
+			p2:       [2]float64{180, 80},
+			dist:     20 * degree,
+			bearing:  0,
+			midpoint: [2]float64{0, 90},
+		},
+		"antipodal": {
+			p1:  [2]float64{0, 0},
+			p2:  [2]float64{180, 0},
+			err: ErrAntipodal,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestGreatCircleLine(t *testing.T) {
+	type tcase struct {
+		p1, p2  [2]float64
+		npoints int
+		// lines are the number of points of each part of the line
+		lines []int
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			mls, err := GreatCircleLine(tc.p1, tc.p2, tc.npoints)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if len(mls) != len(tc.lines) {
+				t.Fatalf("lines, expected %v got %v", len(tc.lines), len(mls))
+			}
+			for i, ln := range mls {
+				if len(ln) != tc.lines[i] {
+					t.Errorf("line %v points, expected %v got %v", i, tc.lines[i], len(ln))
+				}
+				for j := 1; j < len(ln); j++ {
+					if math.Abs(ln[j][0]-ln[j-1][0]) > 180 {
+						t.Errorf("line %v, expected not to cross the antimeridian got %v", i, ln)
+					}
+				}
+			}
+			if first, last := mls[0][0], mls[len(mls)-1]; first != tc.p1 || last[len(last)-1] != tc.p2 {
+				t.Errorf("ends, expected %v and %v got %v", tc.p1, tc.p2, mls)
+			}
+			// the parts meet on the antimeridian, on the great circle
+			for i := 1; i < len(mls); i++ {
+				end, start := mls[i-1][len(mls[i-1])-1], mls[i][0]
+				if math.Abs(end[0]) != 180 || end[0] != -start[0] || end[1] != start[1] {
+					t.Errorf("parts %v and %v, expected to meet on the antimeridian got %v and %v", i-1, i, end, start)
+				}
+				if d := GreatCircleDistance(tc.p1, end) + GreatCircleDistance(end, tc.p2) - GreatCircleDistance(tc.p1, tc.p2); math.Abs(d) > 1e-3 {
+					t.Errorf("antimeridian point %v, expected on the great circle, off by %v", end, d)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"not crossing": {
+			p1:      [2]float64{-10, 10},
+			p2:      [2]float64{100, 40},
+			npoints: 10,
+			lines:   []int{10},
+		},
+		"crossing": {
+			p1:      [2]float64{139.7, 35.7},
+			p2:      [2]float64{-122.4, 37.8},
+			npoints: 11,
+			lines:   []int{6, 7},
+		},
+		"too few points": {
+			p1:      [2]float64{0, 0},
+			p2:      [2]float64{1, 1},
+			npoints: 0,
+			lines:   []int{2},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
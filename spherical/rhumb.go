@@ -0,0 +1,67 @@
+package spherical
+
+import "math"
+
+// stretchedLatDiff returns the difference of the latitudes in radians on a
+// Mercator map, and the ratio of the difference of the latitudes to it.
+func stretchedLatDiff(lat1, lat2 float64) (dPsi, q float64) {
+	dPsi = math.Log(math.Tan(math.Pi/4+lat2/2) / math.Tan(math.Pi/4+lat1/2))
+	if math.Abs(dPsi) > 1e-12 {
+		return dPsi, (lat2 - lat1) / dPsi
+	}
+	// going east or west, the ratio is its limit
+	return dPsi, math.Cos(lat1)
+}
+
+// rhumbLngDiff returns the difference of the longitudes in radians of the
+// shorter of the ways round, crossing the antimeridian if it is shorter.
+func rhumbLngDiff(p1, p2 [2]float64) float64 {
+	return radians(normalizeLng(p2[0] - p1[0]))
+}
+
+// RhumbDistance returns the length in meters of the rhumb line between the
+// points. A rhumb line, or loxodrome, is a line of constant bearing, which
+// is straight on a Mercator map. It is longer than the great circle between
+// the same points, except along the equator or a meridian.
+func RhumbDistance(p1, p2 [2]float64) float64 {
+	lat1, lat2 := radians(p1[1]), radians(p2[1])
+	_, q := stretchedLatDiff(lat1, lat2)
+	dLng := rhumbLngDiff(p1, p2)
+	return math.Hypot(lat2-lat1, q*dLng) * EarthRadius
+}
+
+// RhumbBearing returns the bearing of the rhumb line from p1 to p2, in
+// degrees clockwise from north.
+func RhumbBearing(p1, p2 [2]float64) float64 {
+	dPsi, _ := stretchedLatDiff(radians(p1[1]), radians(p2[1]))
+	return normalizeBearing(degrees(math.Atan2(rhumbLngDiff(p1, p2), dPsi)))
+}
+
+// RhumbDestination returns the point reached by following the rhumb line
+// starting at p with the bearing for the distance in meters. A line going
+// past a pole comes back down the other side of it.
+func RhumbDestination(p [2]float64, bearing, dist float64) [2]float64 {
+	d := dist / EarthRadius
+	sinB, cosB := math.Sincos(radians(bearing))
+	lat1 := radians(p[1])
+	lat2 := lat1 + d*cosB
+	if math.Abs(lat2) > math.Pi/2 {
+		if lat2 > 0 {
+			lat2 = math.Pi - lat2
+		} else {
+			lat2 = -math.Pi - lat2
+		}
+	}
+	_, q := stretchedLatDiff(lat1, lat2)
+	lng2 := radians(p[0]) + d*sinB/q
+	return [2]float64{normalizeLng(degrees(lng2)), degrees(lat2)}
+}
+
+// RhumbInterpolate returns the point the fraction of the way along the
+// rhumb line from p1 to p2. A fraction of 0 is p1 and 1 is p2.
+func RhumbInterpolate(p1, p2 [2]float64, fraction float64) [2]float64 {
+	if fraction == 0 || p1 == p2 {
+		return p1
+	}
+	return RhumbDestination(p1, RhumbBearing(p1, p2), RhumbDistance(p1, p2)*fraction)
+}
@@ -0,0 +1,85 @@
+package spherical
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRhumb(t *testing.T) {
+	type tcase struct {
+		p1, p2  [2]float64
+		dist    float64
+		bearing float64
+		// midpoint is the point half way, if it is known
+		midpoint *[2]float64
+		// tolerance of the distance in meters
+		tolerance float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			dist := RhumbDistance(tc.p1, tc.p2)
+			if math.Abs(dist-tc.dist) > tc.tolerance {
+				t.Errorf("distance, expected %v got %v", tc.dist, dist)
+			}
+			b := RhumbBearing(tc.p1, tc.p2)
+			if math.Abs(b-tc.bearing) > 1e-4 {
+				t.Errorf("bearing, expected %v got %v", tc.bearing, b)
+			}
+			if dest := RhumbDestination(tc.p1, b, dist); !near(dest, tc.p2) {
+				t.Errorf("destination, expected %v got %v", tc.p2, dest)
+			}
+			mid := RhumbInterpolate(tc.p1, tc.p2, 0.5)
+			if tc.midpoint != nil && !near(mid, *tc.midpoint) {
+				t.Errorf("midpoint, expected %v got %v", *tc.midpoint, mid)
+			}
+			// the latitude changes evenly along a rhumb line
+			if lat := (tc.p1[1] + tc.p2[1]) / 2; math.Abs(mid[1]-lat) > 1e-9 {
+				t.Errorf("midpoint latitude, expected %v got %v", lat, mid[1])
+			}
+			if pt := RhumbInterpolate(tc.p1, tc.p2, 0); pt != tc.p1 {
+				t.Errorf("start, expected %v got %v", tc.p1, pt)
+			}
+		}
+	}
+
+	degree := EarthRadius * math.Pi / 180
+	tests := map[string]tcase{
+		"dover to calais": {
+			// the bearing of Veness's example
+			p1:        [2]float64{dms(1, 20, 17), dms(51, 7, 32)},
+			p2:        [2]float64{dms(1, 51, 9), dms(50, 57, 48)},
+			dist:      40235,
+			bearing:   dms(116, 38, 10),
+			tolerance: 1,
+		},
+		"parallel": {
+			p1:        [2]float64{0, 60},
+			p2:        [2]float64{10, 60},
+			dist:      10 * degree * 0.5,
+			bearing:   90,
+			midpoint:  &[2]float64{5, 60},
+			tolerance: 1e-6,
+		},
+		"antimeridian": {
+			p1:        [2]float64{175, -30},
+			p2:        [2]float64{-175, -30},
+			dist:      10 * degree * math.Sqrt(3) / 2,
+			bearing:   90,
+			midpoint:  &[2]float64{180, -30},
+			tolerance: 1e-6,
+		},
+		"meridian": {
+			p1:        [2]float64{20, 10},
+			p2:        [2]float64{20, -30},
+			dist:      40 * degree,
+			bearing:   180,
+			midpoint:  &[2]float64{20, -10},
+			tolerance: 1e-6,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,297 @@
+package geom
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// GeometryStats is the size and complexity of a geometry.
+type GeometryStats struct {
+	// Vertices is the number of points, with the rings of polygons
+	// counted as they are stored, not closed
+	Vertices int
+	// Rings is the number of rings of the polygons
+	Rings int
+	// Parts is the number of points, lines and polygons
+	Parts int
+	// Extent is the extent of the points, or nil if there are none
+	Extent *Extent
+
+	// WKBSize is the size in bytes of the well known binary encoding
+	WKBSize int
+	// WKTSize is the approximate size in bytes of the well known text
+	// encoding, as written by the default encoder of encoding/wkt
+	WKTSize int
+	// GeoJSONSize is the approximate size in bytes of the GeoJSON
+	// geometry object, without white space
+	GeoJSONSize int
+}
+
+// Stats returns the size and complexity of the geometry, such as to keep
+// tiles within a budget. Collections are counted with all the geometries
+// in them. Pointers to geometries, and other implementations of the
+// geometry interfaces, are counted as the geometries they encode as.
+//
+// The sizes of the text encodings are found by formatting the x and y of
+// each point, taking each Z and M value to be as long as they are on
+// average, so they are approximate for geometries with Z or M values.
+func Stats(g Geometry) (GeometryStats, error) {
+	var st GeometryStats
+	sz, err := st.add(g)
+	if err != nil {
+		return GeometryStats{}, err
+	}
+	st.WKBSize, st.WKTSize, st.GeoJSONSize = sz.wkb, sz.wkt, sz.json
+	return st, nil
+}
+
+// encodedSizes are the sizes of a geometry in each encoding
+type encodedSizes struct {
+	wkb, wkt, json int
+}
+
+// plus returns the sizes of both
+func (s encodedSizes) plus(o encodedSizes) encodedSizes {
+	return encodedSizes{s.wkb + o.wkb, s.wkt + o.wkt, s.json + o.json}
+}
+
+// layoutOf returns the layout of the coordinates of the geometry
+func layoutOf(g Geometry) Layout {
+	switch g.(type) {
+	case PointZ, MultiPointZ, LineStringZ, MultiLineStringZ, PolygonZ, MultiPolygonZ:
+		return LayoutXYZ
+	case PointM, MultiPointM, LineStringM, MultiLineStringM, PolygonM, MultiPolygonM:
+		return LayoutXYM
+	case PointZM, MultiPointZM, LineStringZM, MultiLineStringZM, PolygonZM, MultiPolygonZM:
+		return LayoutXYZM
+	default:
+		return LayoutXY
+	}
+}
+
+// wktTag returns the dimensions of the layout written after the name of
+// a geometry in well known text
+func wktTag(l Layout) string {
+	switch l {
+	case LayoutXYZ:
+		return " Z"
+	case LayoutXYM:
+		return " M"
+	case LayoutXYZM:
+		return " ZM"
+	default:
+		return ""
+	}
+}
+
+// add counts the geometry, returning its sizes
+func (st *GeometryStats) add(g Geometry) (encodedSizes, error) {
+	if col, ok := g.(Collection); ok {
+		// {"type":"GeometryCollection","geometries":[]}
+		sz := encodedSizes{wkb: 9, wkt: len("GEOMETRYCOLLECTION ()"), json: 45}
+		if len(col) == 0 {
+			sz.wkt = len("GEOMETRYCOLLECTION EMPTY")
+		}
+		for i, child := range col {
+			csz, err := st.add(child)
+			if err != nil {
+				return encodedSizes{}, err
+			}
+			if i > 0 {
+				csz.wkt++
+				csz.json++
+			}
+			sz = sz.plus(csz)
+		}
+		return sz, nil
+	}
+
+	g = derefZM(g)
+	layout := layoutOf(g)
+	if xy, ok := XYGeometry(g); ok {
+		g = xy
+	}
+	c := coordSizer{stride: layout.Stride()}
+	tag := wktTag(layout)
+
+	// the WKB header is the byte order and type, and the WKT one is the
+	// name, dimensions and parentheses
+	var sz encodedSizes
+	switch gg := g.(type) {
+	case Point:
+		st.Parts++
+		st.points(gg)
+		pt := c.point(gg)
+		// {"type":"Point","coordinates":}
+		sz = encodedSizes{wkb: 5 + 8*c.stride, wkt: len("POINT ()") + len(tag) + pt.wkt, json: 31 + pt.json}
+
+	case MultiPoint:
+		st.Parts += len(gg)
+		st.points(gg...)
+		pts := c.points(gg, false)
+		// {"type":"MultiPoint","coordinates":}
+		sz = encodedSizes{wkb: 9 + len(gg)*(5+8*c.stride), wkt: len("MULTIPOINT ") + len(tag) + pts.wkt, json: 36 + pts.json}
+		if len(gg) == 0 {
+			sz.wkt = len("MULTIPOINT EMPTY") + len(tag)
+		}
+
+	case LineString:
+		st.Parts++
+		st.points(gg...)
+		pts := c.points(gg, false)
+		// {"type":"LineString","coordinates":}
+		sz = encodedSizes{wkb: 5 + pts.wkb, wkt: len("LINESTRING ") + len(tag) + pts.wkt, json: 36 + pts.json}
+
+	case MultiLineString:
+		st.Parts += len(gg)
+		lines := c.lines(st, gg, false)
+		// {"type":"MultiLineString","coordinates":}
+		sz = encodedSizes{wkb: 5 + lines.wkb + 5*len(gg), wkt: len("MULTILINESTRING ") + len(tag) + lines.wkt, json: 41 + lines.json}
+
+	case Polygon:
+		st.Parts++
+		rings := c.lines(st, gg, true)
+		st.Rings += len(gg)
+		// {"type":"Polygon","coordinates":}
+		sz = encodedSizes{wkb: 5 + rings.wkb, wkt: len("POLYGON ") + len(tag) + rings.wkt, json: 33 + rings.json}
+
+	case MultiPolygon:
+		st.Parts += len(gg)
+		// {"type":"MultiPolygon","coordinates":[]}
+		sz = encodedSizes{wkb: 9, wkt: len("MULTIPOLYGON ()") + len(tag), json: 40}
+		if len(gg) == 0 {
+			sz.wkt = len("MULTIPOLYGON EMPTY") + len(tag)
+		}
+		for i, ply := range gg {
+			rings := c.lines(st, ply, true)
+			st.Rings += len(ply)
+			sz = sz.plus(encodedSizes{wkb: 5 + rings.wkb, wkt: rings.wkt, json: rings.json})
+			if i > 0 {
+				sz.wkt++
+				sz.json++
+			}
+		}
+
+	default:
+		base, ok := baseGeometry(g)
+		if !ok {
+			return encodedSizes{}, ErrUnknownGeometry{g}
+		}
+		return st.add(base)
+	}
+	return sz, nil
+}
+
+// baseGeometry returns the geometry, which is not one of the geometry
+// types, as the type of the geometry interface it implements, checking
+// the interfaces in the order the encoders do. This covers pointers, Line,
+// Triangle and user types. Extents are line strings of their vertices, as
+// they are for encoding/wkb and encoding/geojson.
+func baseGeometry(g Geometry) (Geometry, bool) {
+	if e, ok := g.(Extent); ok {
+		g = &e
+	}
+	if v := reflect.ValueOf(g); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, false
+	}
+	switch gg := g.(type) {
+	case Pointer:
+		return Point(gg.XY()), true
+	case MultiPointer:
+		return MultiPoint(gg.Points()), true
+	case LineStringer:
+		return LineString(gg.Vertices()), true
+	case MultiLineStringer:
+		return MultiLineString(gg.LineStrings()), true
+	case Polygoner:
+		return Polygon(gg.LinearRings()), true
+	case MultiPolygoner:
+		return MultiPolygon(gg.Polygons()), true
+	case Collectioner:
+		return Collection(gg.Geometries()), true
+	}
+	return nil, false
+}
+
+// points counts the points and adds them to the extent
+func (st *GeometryStats) points(pts ...[2]float64) {
+	if len(pts) == 0 {
+		return
+	}
+	st.Vertices += len(pts)
+	if st.Extent == nil {
+		st.Extent = NewExtent(pts...)
+		return
+	}
+	st.Extent.AddPoints(pts...)
+}
+
+// coordSizer works out the sizes of coordinates of stride ordinates
+type coordSizer struct {
+	stride int
+	buf    []byte
+}
+
+// point returns the sizes of the point in the text encodings, as x y for
+// WKT and [x,y] for GeoJSON
+func (c *coordSizer) point(pt [2]float64) encodedSizes {
+	c.buf = strconv.AppendFloat(c.buf[:0], pt[0], 'g', 10, 64)
+	wx := len(c.buf)
+	c.buf = strconv.AppendFloat(c.buf[:0], pt[1], 'g', 10, 64)
+	wy := len(c.buf)
+	c.buf = strconv.AppendFloat(c.buf[:0], pt[0], 'g', -1, 64)
+	jx := len(c.buf)
+	c.buf = strconv.AppendFloat(c.buf[:0], pt[1], 'g', -1, 64)
+	jy := len(c.buf)
+
+	// the Z and M values as long as the x and y on average, with a
+	// separator each
+	extra := c.stride - 2
+	return encodedSizes{
+		wkb:  8 * c.stride,
+		wkt:  wx + wy + 1 + extra*((wx+wy)/2+1),
+		json: jx + jy + 3 + extra*((jx+jy)/2+1),
+	}
+}
+
+// points returns the sizes of the points of a line or ring, as (x y,...)
+// for WKT and [[x,y],...] for GeoJSON. A ring that is not closed is
+// closed, as the encodings require. The WKB size includes the number of
+// points.
+func (c *coordSizer) points(pts [][2]float64, ring bool) encodedSizes {
+	sz := encodedSizes{wkb: 4, wkt: 2, json: 2}
+	if len(pts) == 0 {
+		return encodedSizes{wkb: 4, wkt: len("EMPTY"), json: 2}
+	}
+	for i, pt := range pts {
+		if i > 0 {
+			sz.wkt++
+			sz.json++
+		}
+		sz = sz.plus(c.point(pt))
+	}
+	if ring && pts[0] != pts[len(pts)-1] {
+		psz := c.point(pts[0])
+		sz = sz.plus(encodedSizes{psz.wkb, psz.wkt + 1, psz.json + 1})
+	}
+	return sz
+}
+
+// lines counts the points of the lines or rings, returning their sizes as
+// ((x y,...),...) for WKT and [[[x,y],...],...] for GeoJSON. The WKB size
+// includes the number of lines.
+func (c *coordSizer) lines(st *GeometryStats, lines [][][2]float64, rings bool) encodedSizes {
+	if len(lines) == 0 {
+		return encodedSizes{wkb: 4, wkt: len("EMPTY"), json: 2}
+	}
+	sz := encodedSizes{wkb: 4, wkt: 2, json: 2}
+	for i, ln := range lines {
+		st.points(ln...)
+		if i > 0 {
+			sz.wkt++
+			sz.json++
+		}
+		sz = sz.plus(c.points(ln, rings))
+	}
+	return sz
+}
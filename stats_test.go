@@ -0,0 +1,244 @@
+package geom_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/geojson"
+	"github.com/go-spatial/geom/encoding/wkb"
+	"github.com/go-spatial/geom/encoding/wkt"
+)
+
+func TestStats(t *testing.T) {
+	type tcase struct {
+		g        geom.Geometry
+		expected geom.GeometryStats
+		err      error
+		// encoded is the geometry the encoders are given, if not g
+		encoded geom.Geometry
+		// skipWKT skips comparing with encoding/wkt, which encodes
+		// extents as polygons and does not take user types
+		skipWKT bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := geom.Stats(tc.g)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if got.Vertices != tc.expected.Vertices || got.Rings != tc.expected.Rings || got.Parts != tc.expected.Parts {
+				t.Errorf("counts, expected %+v got %+v", tc.expected, got)
+			}
+			if !reflect.DeepEqual(got.Extent, tc.expected.Extent) {
+				t.Errorf("extent, expected %v got %v", tc.expected.Extent, got.Extent)
+			}
+
+			// the sizes are those of the encoders
+			enc := tc.g
+			if tc.encoded != nil {
+				enc = tc.encoded
+			}
+			bs, err := wkb.EncodeBytes(enc)
+			if err != nil {
+				t.Fatalf("wkb error, expected nil got %v", err)
+			}
+			if got.WKBSize != len(bs) {
+				t.Errorf("wkb size, expected %v got %v", len(bs), got.WKBSize)
+			}
+			if !tc.skipWKT {
+				str, err := wkt.EncodeString(enc)
+				if err != nil {
+					t.Fatalf("wkt error, expected nil got %v", err)
+				}
+				if got.WKTSize != len(str) {
+					t.Errorf("wkt size, expected %v (%v) got %v", len(str), str, got.WKTSize)
+				}
+			}
+			js, err := json.Marshal(geojson.Geometry{Geometry: enc})
+			if err != nil {
+				t.Fatalf("geojson error, expected nil got %v", err)
+			}
+			if got.GeoJSONSize != len(js) {
+				t.Errorf("geojson size, expected %v (%s) got %v", len(js), js, got.GeoJSONSize)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			g: geom.Point{1.5, -20},
+			expected: geom.GeometryStats{
+				Vertices: 1,
+				Parts:    1,
+				Extent:   &geom.Extent{1.5, -20, 1.5, -20},
+			},
+		},
+		"multipoint": {
+			g: geom.MultiPoint{{0, 0}, {10, 10.25}},
+			expected: geom.GeometryStats{
+				Vertices: 2,
+				Parts:    2,
+				Extent:   &geom.Extent{0, 0, 10, 10.25},
+			},
+		},
+		"linestring": {
+			g: geom.LineString{{0, 0}, {3, 4}, {100, 0.125}},
+			expected: geom.GeometryStats{
+				Vertices: 3,
+				Parts:    1,
+				Extent:   &geom.Extent{0, 0, 100, 4},
+			},
+		},
+		"multilinestring": {
+			g: geom.MultiLineString{{{0, 0}, {1, 1}}, {{2, 2}, {3, -3}}},
+			expected: geom.GeometryStats{
+				Vertices: 4,
+				Parts:    2,
+				Extent:   &geom.Extent{0, -3, 3, 2},
+			},
+		},
+		"polygon": {
+			// the rings are closed by the encoders
+			g: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+				{{2, 2}, {2, 4}, {4, 4}, {4, 2}},
+			},
+			expected: geom.GeometryStats{
+				Vertices: 8,
+				Rings:    2,
+				Parts:    1,
+				Extent:   &geom.Extent{0, 0, 10, 10},
+			},
+		},
+		"multipolygon": {
+			g: geom.MultiPolygon{
+				{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+				{{{5, 5}, {6, 5}, {6, 6}}},
+			},
+			expected: geom.GeometryStats{
+				Vertices: 7,
+				Rings:    2,
+				Parts:    2,
+				Extent:   &geom.Extent{0, 0, 6, 6},
+			},
+		},
+		"collection": {
+			g: geom.Collection{
+				geom.Point{1, 2},
+				geom.LineString{{0, 0}, {1, 1}},
+				geom.Collection{geom.Polygon{{{0, 0}, {1, 0}, {1, 1}}}},
+			},
+			expected: geom.GeometryStats{
+				Vertices: 6,
+				Rings:    1,
+				Parts:    3,
+				Extent:   &geom.Extent{0, 0, 1, 2},
+			},
+		},
+		"line": {
+			g: geom.Line{{0, 0}, {3, 4}},
+			expected: geom.GeometryStats{
+				Vertices: 2,
+				Parts:    1,
+				Extent:   &geom.Extent{0, 0, 3, 4},
+			},
+		},
+		"triangle": {
+			g: geom.Triangle{{0, 0}, {4, 0}, {0, 3}},
+			expected: geom.GeometryStats{
+				Vertices: 3,
+				Rings:    1,
+				Parts:    1,
+				Extent:   &geom.Extent{0, 0, 4, 3},
+			},
+		},
+		"polygon pointer": {
+			g: &geom.Polygon{{{0, 0}, {1, 0}, {1, 1}}},
+			expected: geom.GeometryStats{
+				Vertices: 3,
+				Rings:    1,
+				Parts:    1,
+				Extent:   &geom.Extent{0, 0, 1, 1},
+			},
+		},
+		"collection pointer": {
+			g: &geom.Collection{geom.Point{1, 2}, geom.Line{{0, 0}, {1, 1}}},
+			expected: geom.GeometryStats{
+				Vertices: 3,
+				Parts:    2,
+				Extent:   &geom.Extent{0, 0, 1, 2},
+			},
+		},
+		"polygoner": {
+			g: statsPolygoner{{{0, 0}, {2, 0}, {2, 2}}},
+			expected: geom.GeometryStats{
+				Vertices: 3,
+				Rings:    1,
+				Parts:    1,
+				Extent:   &geom.Extent{0, 0, 2, 2},
+			},
+			skipWKT: true,
+		},
+		"extent pointer": {
+			g: &geom.Extent{0, 0, 2, 1},
+			expected: geom.GeometryStats{
+				Vertices: 4,
+				Parts:    1,
+				Extent:   &geom.Extent{0, 0, 2, 1},
+			},
+			skipWKT: true,
+		},
+		"extent": {
+			// encoding/wkb and encoding/geojson only take extent pointers
+			g:       geom.Extent{0, 0, 2, 1},
+			encoded: &geom.Extent{0, 0, 2, 1},
+			expected: geom.GeometryStats{
+				Vertices: 4,
+				Parts:    1,
+				Extent:   &geom.Extent{0, 0, 2, 1},
+			},
+			skipWKT: true,
+		},
+		"nil pointer": {
+			g:   (*geom.Polygon)(nil),
+			err: geom.ErrUnknownGeometry{Geom: (*geom.Polygon)(nil)},
+		},
+		"unknown": {
+			g:   struct{}{},
+			err: geom.ErrUnknownGeometry{Geom: struct{}{}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestStatsZM(t *testing.T) {
+	st, err := geom.Stats(geom.LineStringZ{{0, 0, 5}, {1, 1, 6}})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	// the header, the number of points and 3 ordinates a point
+	if st.WKBSize != 5+4+2*24 {
+		t.Errorf("wkb size, expected %v got %v", 5+4+2*24, st.WKBSize)
+	}
+	if st.Vertices != 2 || st.Parts != 1 {
+		t.Errorf("counts, expected 2 vertices and 1 part got %+v", st)
+	}
+	// LINESTRING Z (0 0 5,1 1 6)
+	if st.WKTSize != 26 {
+		t.Errorf("wkt size, expected 26 got %v", st.WKTSize)
+	}
+}
+
+// statsPolygoner is a Polygoner that is not one of the geometry types
+type statsPolygoner [][][2]float64
+
+func (p statsPolygoner) LinearRings() [][][2]float64 { return p }
@@ -0,0 +1,148 @@
+// Package random generates random valid geometries, for property tests and
+// fuzzing of the functions that work on them. The geometries of a
+// Generator depend only on its seed, so a failing case can be made again.
+package random
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/go-spatial/geom"
+)
+
+// Generator makes random geometries inside of its extent.
+type Generator struct {
+	rnd    *rand.Rand
+	extent geom.Extent
+}
+
+// New returns a generator of geometries inside of the extent, seeded with
+// seed.
+func New(seed int64, extent geom.Extent) *Generator {
+	return &Generator{
+		rnd:    rand.New(rand.NewSource(seed)),
+		extent: extent,
+	}
+}
+
+// Extent returns the extent the geometries are made inside of.
+func (g *Generator) Extent() geom.Extent { return g.extent }
+
+func (g *Generator) pointIn(ext geom.Extent) [2]float64 {
+	return [2]float64{
+		ext[0] + g.rnd.Float64()*(ext[2]-ext[0]),
+		ext[1] + g.rnd.Float64()*(ext[3]-ext[1]),
+	}
+}
+
+// Point returns a point inside of the extent.
+func (g *Generator) Point() geom.Point { return geom.Point(g.pointIn(g.extent)) }
+
+// MultiPoint returns n distinct points inside of the extent.
+func (g *Generator) MultiPoint(n int) geom.MultiPoint {
+	seen := make(map[[2]float64]bool, n)
+	pts := make(geom.MultiPoint, 0, n)
+	for len(pts) < n {
+		pt := g.pointIn(g.extent)
+		if seen[pt] {
+			continue
+		}
+		seen[pt] = true
+		pts = append(pts, pt)
+	}
+	return pts
+}
+
+// LineString returns a line string of n points inside of the extent, with
+// no two consecutive points the same. It may cross itself. n is at least 2.
+func (g *Generator) LineString(n int) geom.LineString {
+	if n < 2 {
+		n = 2
+	}
+	ls := make(geom.LineString, 0, n)
+	for len(ls) < n {
+		pt := g.pointIn(g.extent)
+		if len(ls) > 0 && ls[len(ls)-1] == pt {
+			continue
+		}
+		ls = append(ls, pt)
+	}
+	return ls
+}
+
+// jitter is how much of the step between the angles of the points of a
+// ring they are moved by, which must be less than 0.5 for the gaps between
+// them to be less than half a turn for three points
+const jitter = 0.4
+
+// maxGap returns the largest angle between the points of a ring of n points.
+func maxGap(n int) float64 { return (1 + jitter) * 2 * math.Pi / float64(n) }
+
+// starRing returns a ring of n points around the center, at increasing
+// angles and distances between min and max from it, which is simple as
+// every point can be seen from the center.
+func (g *Generator) starRing(center [2]float64, min, max float64, n int) [][2]float64 {
+	angles := make([]float64, n)
+	for i := range angles {
+		// a jittered step keeps the angles less than maxGap apart
+		angles[i] = (float64(i) + jitter*g.rnd.Float64()) * 2 * math.Pi / float64(n)
+	}
+	sort.Float64s(angles)
+	ring := make([][2]float64, n)
+	for i, a := range angles {
+		r := min + g.rnd.Float64()*(max-min)
+		ring[i] = [2]float64{center[0] + r*math.Cos(a), center[1] + r*math.Sin(a)}
+	}
+	return ring
+}
+
+// polygonIn returns a star shaped polygon inside of the extent.
+func (g *Generator) polygonIn(ext geom.Extent, n int, hole bool) geom.Polygon {
+	if n < 3 {
+		n = 3
+	}
+	r := math.Min(ext[2]-ext[0], ext[3]-ext[1]) / 2
+	center := [2]float64{(ext[0] + ext[2]) / 2, (ext[1] + ext[3]) / 2}
+	// move the center about by up to a tenth of the size, keeping the
+	// polygon inside
+	center[0] += (g.rnd.Float64() - 0.5) * r / 5
+	center[1] += (g.rnd.Float64() - 0.5) * r / 5
+	r *= 0.9
+	ply := geom.Polygon{g.starRing(center, r/2, r, n)}
+	if hole {
+		// the closest the edges of the outer ring come to the center
+		in := r / 2 * math.Cos(maxGap(n)/2)
+		hl := g.starRing(center, in/4, in/2, n)
+		// holes are wound the other way
+		for i, j := 0, len(hl)-1; i < j; i, j = i+1, j-1 {
+			hl[i], hl[j] = hl[j], hl[i]
+		}
+		ply = append(ply, hl)
+	}
+	return ply
+}
+
+// Polygon returns a simple polygon of n points inside of the extent, with
+// a hole if hole is true. The outer ring is clockwise and the hole counter
+// clockwise, as winding.Order{} finds them. n is at least 3.
+func (g *Generator) Polygon(n int, hole bool) geom.Polygon {
+	return g.polygonIn(g.extent, n, hole)
+}
+
+// MultiPolygon returns count polygons, as Polygon makes them, that do not
+// touch each other. The extent is split into a grid of at least count
+// cells, and each polygon is inside of a different one of them.
+func (g *Generator) MultiPolygon(count, n int, holes bool) geom.MultiPolygon {
+	if count < 1 {
+		return nil
+	}
+	side := int(math.Ceil(math.Sqrt(float64(count))))
+	cells := g.extent.Grid(side, side)
+	g.rnd.Shuffle(len(cells), func(i, j int) { cells[i], cells[j] = cells[j], cells[i] })
+	mp := make(geom.MultiPolygon, count)
+	for i := range mp {
+		mp[i] = g.polygonIn(*cells[i], n, holes)
+	}
+	return mp
+}
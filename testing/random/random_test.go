@@ -0,0 +1,86 @@
+package random
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/winding"
+)
+
+func TestGenerator(t *testing.T) {
+	ext := geom.Extent{-100, -50, 300, 250}
+
+	type tcase struct {
+		seed int64
+		gen  func(g *Generator) geom.Geometry
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got := tc.gen(New(tc.seed, ext))
+			if again := tc.gen(New(tc.seed, ext)); !reflect.DeepEqual(got, again) {
+				t.Fatalf("same seed, expected %v got %v", got, again)
+			}
+			if other := tc.gen(New(tc.seed+1, ext)); reflect.DeepEqual(got, other) {
+				t.Errorf("other seed, expected a different geometry got %v", other)
+			}
+			if ok, err := ext.ContainsGeom(got); err != nil || !ok {
+				t.Errorf("contains, expected the geometry inside of %v got %v %v", ext, ok, err)
+			}
+			report, err := planar.Validate(got)
+			if err != nil {
+				t.Fatalf("validate error, expected nil got %v", err)
+			}
+			if !report.Valid() {
+				t.Errorf("valid, expected no violations got %v for %v", report.Violations, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			gen: func(g *Generator) geom.Geometry { return g.Point() },
+		},
+		"multipoint": {
+			seed: 3,
+			gen:  func(g *Generator) geom.Geometry { return g.MultiPoint(50) },
+		},
+		"linestring": {
+			seed: 5,
+			gen:  func(g *Generator) geom.Geometry { return g.LineString(20) },
+		},
+		"triangle with hole": {
+			seed: 7,
+			gen:  func(g *Generator) geom.Geometry { return g.Polygon(3, true) },
+		},
+		"polygon": {
+			seed: 11,
+			gen:  func(g *Generator) geom.Geometry { return g.Polygon(40, false) },
+		},
+		"multipolygon": {
+			seed: 13,
+			gen:  func(g *Generator) geom.Geometry { return g.MultiPolygon(7, 12, true) },
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	// many polygons of every size are valid and wound as documented
+	g := New(42, ext)
+	for i := 0; i < 200; i++ {
+		ply := g.Polygon(3+i%20, true)
+		if report, err := planar.Validate(ply); err != nil || !report.Valid() {
+			t.Fatalf("polygon %v, expected valid got %v %v for %v", i, report.Violations, err, ply)
+		}
+		if w := winding.OfPoints(ply[0]...); !w.IsClockwise() {
+			t.Errorf("polygon %v, expected the outer ring clockwise got %v", i, w)
+		}
+		if w := winding.OfPoints(ply[1]...); !w.IsCounterClockwise() {
+			t.Errorf("polygon %v, expected the hole counter clockwise got %v", i, w)
+		}
+	}
+}
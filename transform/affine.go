@@ -0,0 +1,120 @@
+// Package transform provides affine transformations of geometries.
+package transform
+
+import (
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// ErrNotInvertible is returned by Invert when the transformation collapses
+// the plane onto a line or a point, so there is no way back.
+var ErrNotInvertible = errors.New("transform: affine transformation is not invertible")
+
+// Affine is an affine transformation of the plane, made up of the first two
+// rows of the matrix
+//
+//	| A B C |
+//	| D E F |
+//	| 0 0 1 |
+//
+// so a point (x, y) is transformed to (A*x + B*y + C, D*x + E*y + F).
+// The zero value collapses everything onto the origin, use Identity to
+// start from a transformation that changes nothing.
+type Affine [6]float64
+
+// Identity is the transformation that leaves points as they are.
+var Identity = Affine{1, 0, 0, 0, 1, 0}
+
+// Translate returns the transformation that moves points by dx and dy.
+func Translate(dx, dy float64) Affine { return Affine{1, 0, dx, 0, 1, dy} }
+
+// Scale returns the transformation that scales points about the origin by
+// sx along the x axis and sy along the y axis.
+func Scale(sx, sy float64) Affine { return Affine{sx, 0, 0, 0, sy, 0} }
+
+// Rotate returns the transformation that rotates points about the origin
+// by the angle, in radians, counter clockwise.
+func Rotate(angle float64) Affine {
+	sin, cos := math.Sincos(angle)
+	return Affine{cos, -sin, 0, sin, cos, 0}
+}
+
+// RotateAbout returns the transformation that rotates points about the
+// center by the angle, in radians, counter clockwise.
+func RotateAbout(angle float64, center [2]float64) Affine {
+	return Translate(-center[0], -center[1]).
+		Compose(Rotate(angle)).
+		Compose(Translate(center[0], center[1]))
+}
+
+// Shear returns the transformation that shears points, moving x by
+// shx times y, and y by shy times x.
+func Shear(shx, shy float64) Affine { return Affine{1, shx, 0, shy, 1, 0} }
+
+// Compose returns the transformation that applies a and then b.
+func (a Affine) Compose(b Affine) Affine {
+	return Affine{
+		b[0]*a[0] + b[1]*a[3],
+		b[0]*a[1] + b[1]*a[4],
+		b[0]*a[2] + b[1]*a[5] + b[2],
+		b[3]*a[0] + b[4]*a[3],
+		b[3]*a[1] + b[4]*a[4],
+		b[3]*a[2] + b[4]*a[5] + b[5],
+	}
+}
+
+// Determinant returns the determinant of the transformation, the factor
+// areas are scaled by; it is negative if the transformation flips the
+// plane over.
+func (a Affine) Determinant() float64 { return a[0]*a[4] - a[1]*a[3] }
+
+// Invert returns the transformation that undoes a.
+func (a Affine) Invert() (Affine, error) {
+	det := a.Determinant()
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return Affine{}, ErrNotInvertible
+	}
+	return Affine{
+		a[4] / det,
+		-a[1] / det,
+		(a[1]*a[5] - a[4]*a[2]) / det,
+		-a[3] / det,
+		a[0] / det,
+		(a[3]*a[2] - a[0]*a[5]) / det,
+	}, nil
+}
+
+// Point returns the transformed point.
+func (a Affine) Point(pt [2]float64) [2]float64 {
+	return [2]float64{
+		a[0]*pt[0] + a[1]*pt[1] + a[2],
+		a[3]*pt[0] + a[4]*pt[1] + a[5],
+	}
+}
+
+// Transform returns the transformed x and y, it is the geom.Transformer
+// interface and never returns an error.
+func (a Affine) Transform(x, y float64) (float64, float64, error) {
+	pt := a.Point([2]float64{x, y})
+	return pt[0], pt[1], nil
+}
+
+// Apply returns a transformed copy of the geometry; the geometry given is
+// not modified. The Z and M values of points are kept as they are. Rings
+// of polygons keep their order, so if the transformation flips the plane
+// over (its determinant is negative) their winding order is reversed.
+func (a Affine) Apply(g geom.Geometry) (geom.Geometry, error) {
+	return geom.ApplyTransformer(g, a)
+}
+
+// Apply returns a copy of the geometry transformed by each of the
+// transformations in turn.
+func Apply(g geom.Geometry, transforms ...Affine) (geom.Geometry, error) {
+	a := Identity
+	for _, t := range transforms {
+		a = a.Compose(t)
+	}
+	return a.Apply(g)
+}
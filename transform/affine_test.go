@@ -0,0 +1,165 @@
+package transform_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+	"github.com/go-spatial/geom/transform"
+)
+
+func TestAffinePoint(t *testing.T) {
+	type tcase struct {
+		a   transform.Affine
+		pt  [2]float64
+		exp [2]float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got := tc.a.Point(tc.pt)
+			if !cmp.PointEqual(got, tc.exp) {
+				t.Errorf("point, expected %v got %v", tc.exp, got)
+			}
+
+			inv, err := tc.a.Invert()
+			if err != nil {
+				t.Fatalf("invert, expected nil got %v", err)
+			}
+			if back := inv.Point(got); !cmp.PointEqual(back, tc.pt) {
+				t.Errorf("inverted point, expected %v got %v", tc.pt, back)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"identity": {
+			a:   transform.Identity,
+			pt:  [2]float64{3, 4},
+			exp: [2]float64{3, 4},
+		},
+		"translate": {
+			a:   transform.Translate(1, -2),
+			pt:  [2]float64{3, 4},
+			exp: [2]float64{4, 2},
+		},
+		"scale": {
+			a:   transform.Scale(2, -3),
+			pt:  [2]float64{3, 4},
+			exp: [2]float64{6, -12},
+		},
+		"rotate": {
+			a:   transform.Rotate(math.Pi / 2),
+			pt:  [2]float64{3, 4},
+			exp: [2]float64{-4, 3},
+		},
+		"rotate about": {
+			a:   transform.RotateAbout(math.Pi, [2]float64{1, 1}),
+			pt:  [2]float64{3, 4},
+			exp: [2]float64{-1, -2},
+		},
+		"shear": {
+			a:   transform.Shear(2, 0),
+			pt:  [2]float64{3, 4},
+			exp: [2]float64{11, 4},
+		},
+		"compose": {
+			// scale then move, not move then scale
+			a:   transform.Scale(2, 2).Compose(transform.Translate(1, 0)),
+			pt:  [2]float64{3, 4},
+			exp: [2]float64{7, 8},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestAffineInvert(t *testing.T) {
+	_, err := transform.Scale(1, 0).Invert()
+	if err != transform.ErrNotInvertible {
+		t.Errorf("error, expected %v got %v", transform.ErrNotInvertible, err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	type tcase struct {
+		g   geom.Geometry
+		exp geom.Geometry
+		err error
+	}
+
+	move := transform.Translate(10, 20)
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := transform.Apply(tc.g, transform.Scale(2, 2), move)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("geometry, expected %v got %v", tc.exp, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			g:   geom.Point{1, 2},
+			exp: geom.Point{12, 24},
+		},
+		"point zm": {
+			g:   geom.PointZM{1, 2, 3, 4},
+			exp: geom.PointZM{12, 24, 3, 4},
+		},
+		"multipoint m": {
+			g:   geom.MultiPointM{{1, 2, 3}, {0, 0, 5}},
+			exp: geom.MultiPointM{{12, 24, 3}, {10, 20, 5}},
+		},
+		"line": {
+			g:   geom.Line{{0, 0}, {1, 1}},
+			exp: geom.Line{{10, 20}, {12, 22}},
+		},
+		"linestring": {
+			g:   geom.LineString{{0, 0}, {1, 1}},
+			exp: geom.LineString{{10, 20}, {12, 22}},
+		},
+		"multilinestring z": {
+			g:   geom.MultiLineStringZ{{{0, 0, 1}, {1, 1, 2}}},
+			exp: geom.MultiLineStringZ{{{10, 20, 1}, {12, 22, 2}}},
+		},
+		"polygon": {
+			g:   geom.Polygon{{{0, 0}, {1, 0}, {1, 1}}},
+			exp: geom.Polygon{{{10, 20}, {12, 20}, {12, 22}}},
+		},
+		"multipolygon": {
+			g:   geom.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}}}, {}},
+			exp: geom.MultiPolygon{{{{10, 20}, {12, 20}, {12, 22}}}, {}},
+		},
+		"collection": {
+			g:   geom.Collection{geom.Point{1, 2}, geom.Collection{geom.LineString{{0, 0}, {1, 1}}}},
+			exp: geom.Collection{geom.Point{12, 24}, geom.Collection{geom.LineString{{10, 20}, {12, 22}}}},
+		},
+		"unknown": {
+			g:   geom.Extent{0, 0, 1, 1},
+			err: geom.ErrUnknownGeometry{Geom: geom.Extent{0, 0, 1, 1}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestApplyCopies(t *testing.T) {
+	ls := geom.LineString{{0, 0}, {1, 1}}
+	if _, err := transform.Translate(1, 1).Apply(ls); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if exp := (geom.LineString{{0, 0}, {1, 1}}); !reflect.DeepEqual(ls, exp) {
+		t.Errorf("original, expected %v got %v", exp, ls)
+	}
+}
@@ -0,0 +1,370 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/go-spatial/geom/proj/webmercator"
+)
+
+// Transformer transforms coordinates, for example from one coordinate
+// reference system to another. It allows projection libraries to be used
+// to reproject whole geometries with ApplyTransformer.
+type Transformer interface {
+	Transform(x, y float64) (float64, float64, error)
+}
+
+// TransformerFunc is a function that is a Transformer.
+type TransformerFunc func(x, y float64) (float64, float64, error)
+
+// Transform calls fn
+func (fn TransformerFunc) Transform(x, y float64) (float64, float64, error) { return fn(x, y) }
+
+// ErrInvalidCoordinate is returned by a Transformer for a coordinate it
+// can not transform.
+type ErrInvalidCoordinate struct {
+	X, Y   float64
+	Reason string
+}
+
+func (e ErrInvalidCoordinate) Error() string {
+	return fmt.Sprintf("invalid coordinate (%v %v): %v", e.X, e.Y, e.Reason)
+}
+
+// WebMercatorMaxLat is the latitude, in degrees, at which Web Mercator
+// becomes square; latitudes further from the equator are clamped to it.
+const WebMercatorMaxLat = webmercator.MaxLatitude
+
+var (
+	// WGS84ToWebMercator transforms longitude and latitude, in degrees
+	// (EPSG:4326), to Web Mercator meters (EPSG:3857). Latitudes beyond
+	// WebMercatorMaxLat are clamped to it.
+	WGS84ToWebMercator Transformer = TransformerFunc(wgs84ToWebMercator)
+	// WebMercatorToWGS84 transforms Web Mercator meters (EPSG:3857) to
+	// longitude and latitude in degrees (EPSG:4326).
+	WebMercatorToWGS84 Transformer = TransformerFunc(webMercatorToWGS84)
+)
+
+func wgs84ToWebMercator(lng, lat float64) (float64, float64, error) {
+	if math.IsNaN(lng) || math.IsNaN(lat) || math.IsInf(lng, 0) || math.Abs(lat) > 90 {
+		return 0, 0, ErrInvalidCoordinate{X: lng, Y: lat, Reason: "not a longitude and latitude"}
+	}
+	x, y := webmercator.Forward(lng, lat)
+	return x, y, nil
+}
+
+func webMercatorToWGS84(x, y float64) (float64, float64, error) {
+	if math.IsNaN(x) || math.IsNaN(y) || math.IsInf(x, 0) || math.IsInf(y, 0) {
+		return 0, 0, ErrInvalidCoordinate{X: x, Y: y, Reason: "not a Web Mercator coordinate"}
+	}
+	lng, lat := webmercator.Inverse(x, y)
+	return lng, lat, nil
+}
+
+// ApplyTransformer returns a copy of the geometry with the x and y of each
+// of its points transformed by t; the geometry given is not modified. The
+// Z and M values of points are kept as they are. It stops at the first
+// error returned by t.
+//
+// The copy is of the same type as the geometry. Pointers to geometries
+// give pointers to the copies, and other implementations of the geometry
+// interfaces give the type of the interface, such as a Polygon for a
+// Polygoner.
+func ApplyTransformer(g Geometry, t Transformer) (Geometry, error) {
+	tg, err := transformer{t}.geometry(g)
+	if err != nil {
+		return nil, err
+	}
+	return tg, nil
+}
+
+// transformer applies a Transformer to the coordinates of the different
+// dimensions
+type transformer struct {
+	Transformer
+}
+
+// geometry returns the transformed geometry, which is not valid if there
+// is an error
+func (tr transformer) geometry(g Geometry) (Geometry, error) {
+	switch g := g.(type) {
+	case Point:
+		pt, err := tr.point(g)
+		return Point(pt), err
+	case PointZ:
+		pt, err := tr.point3(g)
+		return PointZ(pt), err
+	case PointM:
+		pt, err := tr.point3(g)
+		return PointM(pt), err
+	case PointZM:
+		pt, err := tr.point4(g)
+		return PointZM(pt), err
+
+	case MultiPoint:
+		pts, err := tr.points(g)
+		return MultiPoint(pts), err
+	case MultiPointZ:
+		pts, err := tr.points3(g)
+		return MultiPointZ(pts), err
+	case MultiPointM:
+		pts, err := tr.points3(g)
+		return MultiPointM(pts), err
+	case MultiPointZM:
+		pts, err := tr.points4(g)
+		return MultiPointZM(pts), err
+
+	case Line:
+		pts, err := tr.points(g[:])
+		if err != nil {
+			return nil, err
+		}
+		return Line{pts[0], pts[1]}, nil
+	case Triangle:
+		pts, err := tr.points(g[:])
+		if err != nil {
+			return nil, err
+		}
+		return Triangle{pts[0], pts[1], pts[2]}, nil
+
+	case LineString:
+		pts, err := tr.points(g)
+		return LineString(pts), err
+	case LineStringZ:
+		pts, err := tr.points3(g)
+		return LineStringZ(pts), err
+	case LineStringM:
+		pts, err := tr.points3(g)
+		return LineStringM(pts), err
+	case LineStringZM:
+		pts, err := tr.points4(g)
+		return LineStringZM(pts), err
+
+	case MultiLineString:
+		lines, err := tr.lines(g)
+		return MultiLineString(lines), err
+	case MultiLineStringZ:
+		lines, err := tr.lines3(g)
+		return MultiLineStringZ(lines), err
+	case MultiLineStringM:
+		lines, err := tr.lines3(g)
+		return MultiLineStringM(lines), err
+	case MultiLineStringZM:
+		lines, err := tr.lines4(g)
+		return MultiLineStringZM(lines), err
+
+	case Polygon:
+		lines, err := tr.lines(g)
+		return Polygon(lines), err
+	case PolygonZ:
+		lines, err := tr.lines3(g)
+		return PolygonZ(lines), err
+	case PolygonM:
+		lines, err := tr.lines3(g)
+		return PolygonM(lines), err
+	case PolygonZM:
+		lines, err := tr.lines4(g)
+		return PolygonZM(lines), err
+
+	case MultiPolygon:
+		mp := make(MultiPolygon, len(g))
+		for i := range g {
+			lines, err := tr.lines(g[i])
+			if err != nil {
+				return nil, err
+			}
+			mp[i] = lines
+		}
+		return mp, nil
+	case MultiPolygonZ:
+		mp := make(MultiPolygonZ, len(g))
+		for i := range g {
+			lines, err := tr.lines3(g[i])
+			if err != nil {
+				return nil, err
+			}
+			mp[i] = lines
+		}
+		return mp, nil
+	case MultiPolygonM:
+		mp := make(MultiPolygonM, len(g))
+		for i := range g {
+			lines, err := tr.lines3(g[i])
+			if err != nil {
+				return nil, err
+			}
+			mp[i] = lines
+		}
+		return mp, nil
+	case MultiPolygonZM:
+		mp := make(MultiPolygonZM, len(g))
+		for i := range g {
+			lines, err := tr.lines4(g[i])
+			if err != nil {
+				return nil, err
+			}
+			mp[i] = lines
+		}
+		return mp, nil
+
+	case Collection:
+		col := make(Collection, len(g))
+		for i := range g {
+			tg, err := ApplyTransformer(g[i], tr.Transformer)
+			if err != nil {
+				return nil, err
+			}
+			col[i] = tg
+		}
+		return col, nil
+
+	default:
+		return tr.other(g)
+	}
+}
+
+// other returns the transformed geometry for the types that are not
+// geometry types. A pointer to a geometry gives a pointer to the
+// transformed geometry, and other implementations of the geometry
+// interfaces give the type of the interface, such as a LineString for a
+// LineStringer, as Walk walks them.
+func (tr transformer) other(g Geometry) (Geometry, error) {
+	if v := reflect.ValueOf(g); v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return g, nil
+		}
+		tg, err := tr.geometry(v.Elem().Interface())
+		switch err.(type) {
+		case nil:
+			if reflect.TypeOf(tg) != v.Elem().Type() {
+				return tg, nil
+			}
+			ptr := reflect.New(v.Elem().Type())
+			ptr.Elem().Set(reflect.ValueOf(tg))
+			return ptr.Interface(), nil
+		case ErrUnknownGeometry:
+			// the pointer may implement the interfaces itself
+		default:
+			return nil, err
+		}
+	}
+
+	switch g := g.(type) {
+	case Pointer:
+		return tr.geometry(Point(g.XY()))
+	case MultiPointer:
+		return tr.geometry(MultiPoint(g.Points()))
+	case LineStringer:
+		return tr.geometry(LineString(g.Vertices()))
+	case MultiLineStringer:
+		return tr.geometry(MultiLineString(g.LineStrings()))
+	case Polygoner:
+		return tr.geometry(Polygon(g.LinearRings()))
+	case MultiPolygoner:
+		return tr.geometry(MultiPolygon(g.Polygons()))
+	case Collectioner:
+		return tr.geometry(Collection(g.Geometries()))
+	default:
+		return nil, ErrUnknownGeometry{Geom: g}
+	}
+}
+
+func (t transformer) point(pt [2]float64) ([2]float64, error) {
+	x, y, err := t.Transform(pt[0], pt[1])
+	return [2]float64{x, y}, err
+}
+
+func (t transformer) point3(pt [3]float64) ([3]float64, error) {
+	x, y, err := t.Transform(pt[0], pt[1])
+	return [3]float64{x, y, pt[2]}, err
+}
+
+func (t transformer) point4(pt [4]float64) ([4]float64, error) {
+	x, y, err := t.Transform(pt[0], pt[1])
+	return [4]float64{x, y, pt[2], pt[3]}, err
+}
+
+func (t transformer) points(pts [][2]float64) ([][2]float64, error) {
+	if pts == nil {
+		return nil, nil
+	}
+	var err error
+	ret := make([][2]float64, len(pts))
+	for i := range pts {
+		if ret[i], err = t.point(pts[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func (t transformer) points3(pts [][3]float64) ([][3]float64, error) {
+	if pts == nil {
+		return nil, nil
+	}
+	var err error
+	ret := make([][3]float64, len(pts))
+	for i := range pts {
+		if ret[i], err = t.point3(pts[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func (t transformer) points4(pts [][4]float64) ([][4]float64, error) {
+	if pts == nil {
+		return nil, nil
+	}
+	var err error
+	ret := make([][4]float64, len(pts))
+	for i := range pts {
+		if ret[i], err = t.point4(pts[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func (t transformer) lines(lines [][][2]float64) ([][][2]float64, error) {
+	if lines == nil {
+		return nil, nil
+	}
+	var err error
+	ret := make([][][2]float64, len(lines))
+	for i := range lines {
+		if ret[i], err = t.points(lines[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func (t transformer) lines3(lines [][][3]float64) ([][][3]float64, error) {
+	if lines == nil {
+		return nil, nil
+	}
+	var err error
+	ret := make([][][3]float64, len(lines))
+	for i := range lines {
+		if ret[i], err = t.points3(lines[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func (t transformer) lines4(lines [][][4]float64) ([][][4]float64, error) {
+	if lines == nil {
+		return nil, nil
+	}
+	var err error
+	ret := make([][][4]float64, len(lines))
+	for i := range lines {
+		if ret[i], err = t.points4(lines[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,177 @@
+package geom
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestWebMercatorTransformers(t *testing.T) {
+	type tcase struct {
+		lnglat [2]float64
+		merc   [2]float64
+	}
+
+	const tol = 1e-6
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			x, y, err := WGS84ToWebMercator.Transform(tc.lnglat[0], tc.lnglat[1])
+			if err != nil {
+				t.Fatalf("to web mercator error, expected nil got %v", err)
+			}
+			if math.Abs(x-tc.merc[0]) > 1e-2 || math.Abs(y-tc.merc[1]) > 1e-2 {
+				t.Errorf("to web mercator, expected %v got %v", tc.merc, [2]float64{x, y})
+			}
+
+			lng, lat, err := WebMercatorToWGS84.Transform(x, y)
+			if err != nil {
+				t.Fatalf("to wgs84 error, expected nil got %v", err)
+			}
+			if math.Abs(lng-tc.lnglat[0]) > tol || math.Abs(lat-tc.lnglat[1]) > tol {
+				t.Errorf("to wgs84, expected %v got %v", tc.lnglat, [2]float64{lng, lat})
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"origin": {
+			lnglat: [2]float64{0, 0},
+			merc:   [2]float64{0, 0},
+		},
+		"north east corner": {
+			lnglat: [2]float64{180, WebMercatorMaxLat},
+			merc:   [2]float64{20037508.34, 20037508.34},
+		},
+		"south west corner": {
+			lnglat: [2]float64{-180, -WebMercatorMaxLat},
+			merc:   [2]float64{-20037508.34, -20037508.34},
+		},
+		"san diego": {
+			lnglat: [2]float64{-117.1625, 32.715},
+			merc:   [2]float64{-13042469.84, 3857535.79},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	// latitudes beyond the max are clamped
+	_, y, err := WGS84ToWebMercator.Transform(0, 89)
+	if err != nil {
+		t.Fatalf("clamped error, expected nil got %v", err)
+	}
+	if math.Abs(y-20037508.34) > 1e-2 {
+		t.Errorf("clamped y, expected 20037508.34 got %v", y)
+	}
+}
+
+func TestApplyTransformer(t *testing.T) {
+	type tcase struct {
+		g   Geometry
+		exp Geometry
+		err error
+	}
+
+	// swap the axes, and fail for negative x
+	swap := TransformerFunc(func(x, y float64) (float64, float64, error) {
+		if x < 0 {
+			return 0, 0, ErrInvalidCoordinate{X: x, Y: y, Reason: "negative x"}
+		}
+		return y, x, nil
+	})
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := ApplyTransformer(tc.g, swap)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("geometry, expected %v got %v", tc.exp, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			g:   Point{1, 2},
+			exp: Point{2, 1},
+		},
+		"point zm": {
+			g:   PointZM{1, 2, 3, 4},
+			exp: PointZM{2, 1, 3, 4},
+		},
+		"linestring z": {
+			g:   LineStringZ{{1, 2, 3}, {4, 5, 6}},
+			exp: LineStringZ{{2, 1, 3}, {5, 4, 6}},
+		},
+		"polygon": {
+			g:   Polygon{{{0, 0}, {1, 0}, {1, 2}}},
+			exp: Polygon{{{0, 0}, {0, 1}, {2, 1}}},
+		},
+		"multipolygon m": {
+			g:   MultiPolygonM{{{{0, 0, 7}, {1, 0, 7}, {1, 2, 7}}}},
+			exp: MultiPolygonM{{{{0, 0, 7}, {0, 1, 7}, {2, 1, 7}}}},
+		},
+		"collection": {
+			g:   Collection{Point{1, 2}, Collection{Line{{1, 2}, {3, 4}}}},
+			exp: Collection{Point{2, 1}, Collection{Line{{2, 1}, {4, 3}}}},
+		},
+		"error": {
+			g:   Collection{Point{1, 2}, MultiLineString{{{1, 2}, {-3, 4}}}},
+			err: ErrInvalidCoordinate{X: -3, Y: 4, Reason: "negative x"},
+		},
+		"pointer": {
+			g:   &Polygon{{{0, 0}, {1, 0}, {1, 2}}},
+			exp: &Polygon{{{0, 0}, {0, 1}, {2, 1}}},
+		},
+		"pointer z": {
+			g:   &PointZ{1, 2, 3},
+			exp: &PointZ{2, 1, 3},
+		},
+		"nil pointer": {
+			g:   (*LineString)(nil),
+			exp: (*LineString)(nil),
+		},
+		"pointer in collection": {
+			g:   Collection{&LineString{{1, 2}, {3, 4}}},
+			exp: Collection{&LineString{{2, 1}, {4, 3}}},
+		},
+		"pointer error": {
+			g:   &MultiPoint{{-1, 2}},
+			err: ErrInvalidCoordinate{X: -1, Y: 2, Reason: "negative x"},
+		},
+		"polygoner": {
+			g:   testPolygoner{{{0, 0}, {1, 0}, {1, 2}}},
+			exp: Polygon{{{0, 0}, {0, 1}, {2, 1}}},
+		},
+		"pointer to polygoner": {
+			g:   &testPolygoner{{{0, 0}, {1, 0}, {1, 2}}},
+			exp: Polygon{{{0, 0}, {0, 1}, {2, 1}}},
+		},
+		"extent": {
+			// an extent is walked as the line string of its vertices
+			g:   &Extent{0, 0, 1, 2},
+			exp: LineString{{0, 0}, {0, 1}, {2, 1}, {2, 0}},
+		},
+		"unknown": {
+			g:   struct{}{},
+			err: ErrUnknownGeometry{Geom: struct{}{}},
+		},
+		"unknown pointer": {
+			g:   &[]int{1},
+			err: ErrUnknownGeometry{Geom: &[]int{1}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+// testPolygoner is a Polygoner that is not one of the geometry types
+type testPolygoner [][][2]float64
+
+func (p testPolygoner) LinearRings() [][][2]float64 { return p }
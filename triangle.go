@@ -67,13 +67,89 @@ func NewTriangleFromPolygon(py [][][2]float64) Triangle {
 	return t
 }
 
-// Area reaturns twice the area of the oriented triangle (a,b,c), i.e.
-// the area is positive if the triangle is oriented counterclockwise.
+// Area returns twice the area of the oriented triangle (a,b,c), i.e.
+// the area is positive if the triangle is oriented counterclockwise. Half
+// of its absolute value is the area of the triangle.
 func (t Triangle) Area() float64 {
 	a, b, c := t[0], t[1], t[2]
 	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
 }
 
+// Circumcircle returns the circle through the three points of the
+// triangle, or ErrPointsAreCoLinear if they are on a line. Unlike
+// CircleFromPoints, the radius is not rounded.
+func (t Triangle) Circumcircle() (Circle, error) {
+	// relative to the first point, for precision
+	bx, by := t[1][0]-t[0][0], t[1][1]-t[0][1]
+	cx, cy := t[2][0]-t[0][0], t[2][1]-t[0][1]
+	d := 2 * (bx*cy - by*cx)
+	if d == 0 {
+		return Circle{}, ErrPointsAreCoLinear
+	}
+	b2, c2 := bx*bx+by*by, cx*cx+cy*cy
+	ux := (cy*b2 - by*c2) / d
+	uy := (bx*c2 - cx*b2) / d
+	return Circle{
+		Center: [2]float64{t[0][0] + ux, t[0][1] + uy},
+		Radius: math.Hypot(ux, uy),
+	}, nil
+}
+
+// InCenter returns the center of the largest circle inside the triangle,
+// where its angle bisectors meet, which is always inside it. For a
+// triangle with no area it is a point on the line of the triangle.
+func (t Triangle) InCenter() [2]float64 {
+	// each point is weighted by the length of the side across from it
+	a := math.Hypot(t[2][0]-t[1][0], t[2][1]-t[1][1])
+	b := math.Hypot(t[2][0]-t[0][0], t[2][1]-t[0][1])
+	c := math.Hypot(t[1][0]-t[0][0], t[1][1]-t[0][1])
+	p := a + b + c
+	if p == 0 {
+		return t[0]
+	}
+	return [2]float64{
+		(a*t[0][0] + b*t[1][0] + c*t[2][0]) / p,
+		(a*t[0][1] + b*t[1][1] + c*t[2][1]) / p,
+	}
+}
+
+// Barycentric returns the barycentric coordinates of the point in the
+// triangle: the weights of the points of the triangle, which add up to 1,
+// that the point is the weighted sum of. The weights are all between 0 and
+// 1 for points in the triangle, and one of them is negative for points
+// outside it. ErrPointsAreCoLinear is returned if the triangle has no area.
+func (t Triangle) Barycentric(pt [2]float64) ([3]float64, error) {
+	area := t.Area()
+	if area == 0 {
+		return [3]float64{}, ErrPointsAreCoLinear
+	}
+	w0 := Triangle{pt, t[1], t[2]}.Area() / area
+	w1 := Triangle{t[0], pt, t[2]}.Area() / area
+	return [3]float64{w0, w1, 1 - w0 - w1}, nil
+}
+
+// ContainsPoint returns whether the point is inside the triangle or on its
+// edges. A triangle with no area contains no points.
+func (t Triangle) ContainsPoint(pt [2]float64) bool {
+	w, err := t.Barycentric(pt)
+	if err != nil {
+		return false
+	}
+	return w[0] >= -tolerance && w[1] >= -tolerance && w[2] >= -tolerance
+}
+
+// Interpolate returns the value at the point of the plane through the
+// values z0, z1 and z2 at the points of the triangle, such as the height
+// of a point of a TIN. Points outside the triangle are extrapolated.
+// ErrPointsAreCoLinear is returned if the triangle has no area.
+func (t Triangle) Interpolate(z0, z1, z2 float64, pt [2]float64) (float64, error) {
+	w, err := t.Barycentric(pt)
+	if err != nil {
+		return 0, err
+	}
+	return w[0]*z0 + w[1]*z1 + w[2]*z2, nil
+}
+
 // NewTriangleContaining returns a triangle that is large enough to contain the
 // given points
 func NewTriangleContaining(pts ...Point) Triangle {
@@ -1,6 +1,7 @@
 package geom_test
 
 import (
+	"math"
 	"strconv"
 	"testing"
 
@@ -47,3 +48,129 @@ func TestNewTriangleForExtent(t *testing.T) {
 		t.Run(strconv.FormatInt(int64(i), 10), fn(tests[i]))
 	}
 }
+
+func TestTriangle(t *testing.T) {
+	type tcase struct {
+		tri geom.Triangle
+		// area is the area, not twice it
+		area         float64
+		circumcircle geom.Circle
+		incenter     [2]float64
+		err          error
+	}
+
+	near := func(a, b [2]float64) bool {
+		return math.Abs(a[0]-b[0]) < 1e-9 && math.Abs(a[1]-b[1]) < 1e-9
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if area := math.Abs(tc.tri.Area()) / 2; math.Abs(area-tc.area) > 1e-9 {
+				t.Errorf("area, expected %v got %v", tc.area, area)
+			}
+			c, err := tc.tri.Circumcircle()
+			if err != tc.err {
+				t.Fatalf("circumcircle error, expected %v got %v", tc.err, err)
+			}
+			if _, err := tc.tri.Barycentric([2]float64{}); err != tc.err {
+				t.Fatalf("barycentric error, expected %v got %v", tc.err, err)
+			}
+			if in := tc.tri.InCenter(); !near(in, tc.incenter) {
+				t.Errorf("incenter, expected %v got %v", tc.incenter, in)
+			}
+			if err != nil {
+				return
+			}
+			if !near(c.Center, tc.circumcircle.Center) || math.Abs(c.Radius-tc.circumcircle.Radius) > 1e-9 {
+				t.Errorf("circumcircle, expected %v got %v", tc.circumcircle, c)
+			}
+			if !tc.tri.ContainsPoint(tc.incenter) {
+				t.Errorf("contains incenter, expected true got false")
+			}
+			// the points of the triangle are weighted by themselves
+			for i, pt := range tc.tri {
+				w, err := tc.tri.Barycentric(pt)
+				if err != nil {
+					t.Fatalf("barycentric error, expected nil got %v", err)
+				}
+				for j := range w {
+					expected := 0.0
+					if i == j {
+						expected = 1
+					}
+					if math.Abs(w[j]-expected) > 1e-9 {
+						t.Errorf("barycentric of point %v, expected %v got %v", i, expected, w)
+					}
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"right": {
+			tri:          geom.Triangle{{0, 0}, {4, 0}, {0, 3}},
+			area:         6,
+			circumcircle: geom.Circle{Center: [2]float64{2, 1.5}, Radius: 2.5},
+			// the inradius is the area over half the perimeter
+			incenter: [2]float64{1, 1},
+		},
+		"equilateral clockwise": {
+			tri:          geom.Triangle{{0, 0}, {1, math.Sqrt(3)}, {2, 0}},
+			area:         math.Sqrt(3),
+			circumcircle: geom.Circle{Center: [2]float64{1, math.Sqrt(3) / 3}, Radius: 2 / math.Sqrt(3)},
+			incenter:     [2]float64{1, math.Sqrt(3) / 3},
+		},
+		"flat": {
+			tri:      geom.Triangle{{0, 0}, {1, 1}, {2, 2}},
+			incenter: [2]float64{1, 1},
+			err:      geom.ErrPointsAreCoLinear,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestTriangleContainsPoint(t *testing.T) {
+	tri := geom.Triangle{{0, 0}, {4, 0}, {0, 4}}
+	tests := map[string]struct {
+		pt       [2]float64
+		expected bool
+	}{
+		"inside":  {pt: [2]float64{1, 1}, expected: true},
+		"edge":    {pt: [2]float64{2, 2}, expected: true},
+		"vertex":  {pt: [2]float64{4, 0}, expected: true},
+		"outside": {pt: [2]float64{3, 3}, expected: false},
+		"behind":  {pt: [2]float64{-1, 1}, expected: false},
+	}
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			if got := tri.ContainsPoint(tc.pt); got != tc.expected {
+				t.Errorf("contains %v, expected %v got %v", tc.pt, tc.expected, got)
+			}
+		})
+	}
+	if (geom.Triangle{{0, 0}, {1, 1}, {2, 2}}).ContainsPoint([2]float64{1, 1}) {
+		t.Errorf("flat triangle, expected to contain nothing got true")
+	}
+}
+
+func TestTriangleInterpolate(t *testing.T) {
+	// the plane z = 2x + 3y + 1
+	tri := geom.Triangle{{0, 0}, {5, 1}, {2, 7}}
+	plane := func(pt [2]float64) float64 { return 2*pt[0] + 3*pt[1] + 1 }
+	for _, pt := range [][2]float64{{0, 0}, {2, 2}, {3.5, 4}, {10, -3}} {
+		z, err := tri.Interpolate(plane(tri[0]), plane(tri[1]), plane(tri[2]), pt)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if math.Abs(z-plane(pt)) > 1e-9 {
+			t.Errorf("value at %v, expected %v got %v", pt, plane(pt), z)
+		}
+	}
+	if _, err := (geom.Triangle{{0, 0}, {1, 0}, {2, 0}}).Interpolate(1, 2, 3, [2]float64{1, 1}); err != geom.ErrPointsAreCoLinear {
+		t.Errorf("error, expected %v got %v", geom.ErrPointsAreCoLinear, err)
+	}
+}
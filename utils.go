@@ -1,6 +1,9 @@
 package geom
 
-import "fmt"
+import (
+	"fmt"
+	"reflect"
+)
 
 // ApplyToPoints applys the given function to each point in the geometry and any sub geometries, return a new transformed geometry.
 func ApplyToPoints(geometry Geometry, f func(coords ...float64) ([]float64, error)) (Geometry, error) {
@@ -109,84 +112,79 @@ func ApplyToPoints(geometry Geometry, f func(coords ...float64) ([]float64, erro
 	}
 }
 
-// Clone returns a deep clone of the Geometry.
+// Clone returns a deep copy of the geometry, of the same type, that shares
+// no memory with it, so changing one does not change the other. All the
+// geometry types are supported: those with Z and M values, Lines,
+// Triangles, Extents, packed geometries, collections of any of them, and
+// pointers to them, for which a pointer to a copy is returned. Nil slices
+// and pointers are kept nil.
 func Clone(geometry Geometry) (Geometry, error) {
 	switch geo := geometry.(type) {
-	default:
+	case nil:
 		return nil, fmt.Errorf("unknown Geometry: %T", geometry)
 
-	case Point:
-		return Point{geo.X(), geo.Y()}, nil
-
-	case MultiPoint:
-		pts := make(MultiPoint, len(geo))
-		for i, pt := range geo {
-			pts[i] = pt
+	case Extent:
+		return geo, nil
+	case *Extent:
+		if geo == nil {
+			return geo, nil
 		}
-		return pts, nil
+		return geo.Clone(), nil
 
-	case LineString:
-		line := make(LineString, len(geo))
-		for i, pt := range geo {
-			line[i] = pt
+	case PackedLineString:
+		return PackedLineString{Seq: cloneCoordSeq(geo.Seq)}, nil
+	case PackedPolygon:
+		if geo == nil {
+			return geo, nil
 		}
-		return line, nil
-
-	case MultiLineString:
-		lines := make(MultiLineString, len(geo))
-		for i, line := range geo {
-			// getting a geometry interface back
-			linei, err := Clone(LineString(line))
-			if err != nil {
-				return nil, fmt.Errorf("got error converting line(%v) of multiline: %v", i, err)
-			}
-
-			// get the value
-			linev, ok := linei.(LineString)
-			if !ok {
-				panic("we did not get the conversion we were expecting")
-			}
-
-			lines[i] = linev
+		ply := make(PackedPolygon, len(geo))
+		for i := range geo {
+			ply[i] = cloneCoordSeq(geo[i])
 		}
-		return lines, nil
+		return ply, nil
 
-	case Polygon:
-		// getting a geometry inteface back
-		poly := make(Polygon, len(geo))
-		for i, line := range geo {
-			linei, err := Clone(LineString(line))
+	case Collection:
+		if geo == nil {
+			return geo, nil
+		}
+		col := make(Collection, len(geo))
+		for i := range geo {
+			g, err := Clone(geo[i])
 			if err != nil {
-				return nil, fmt.Errorf("got error converting line(%v) of polygon: %v", i, err)
-			}
-
-			// get the value
-			linev, ok := linei.(LineString)
-			if !ok {
-				panic("we did not get the conversion we were expecting")
+				return nil, err
 			}
-
-			poly[i] = linev
+			col[i] = g
 		}
-		return poly, nil
+		return col, nil
+	}
 
-	case MultiPolygon:
-		mpoly := make(MultiPolygon, len(geo))
-		for i, poly := range geo {
-			// getting a geometry inteface back
-			polyi, err := Clone(Polygon(poly))
-			if err != nil {
-				return nil, fmt.Errorf("got error converting polygon(%v) of multipolygon: %v", i, err)
-			}
+	if v := reflect.ValueOf(geometry); v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return geometry, nil
+		}
+		g, err := Clone(v.Elem().Interface())
+		if err != nil {
+			return nil, err
+		}
+		ptr := reflect.New(v.Elem().Type())
+		ptr.Elem().Set(reflect.ValueOf(g))
+		return ptr.Interface(), nil
+	}
 
-			// get the value
-			polyv, ok := polyi.(Polygon)
-			if !ok {
-				panic("we did not get the conversion we were expecting")
-			}
+	// transforming the points by nothing copies them
+	g, err := transformer{TransformerFunc(func(x, y float64) (float64, float64, error) {
+		return x, y, nil
+	})}.geometry(geometry)
+	if err != nil {
+		return nil, fmt.Errorf("unknown Geometry: %T", geometry)
+	}
+	return g, nil
+}
 
-			mpoly[i] = polyv
-		}
-		return mpoly, nil
+// cloneCoordSeq returns a copy of the sequence
+func cloneCoordSeq(cs CoordSeq) CoordSeq {
+	if cs.Flat == nil {
+		return cs
 	}
+	return CoordSeq{Layout: cs.Layout, Flat: append(make([]float64, 0, len(cs.Flat)), cs.Flat...)}
 }
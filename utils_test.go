@@ -42,6 +42,44 @@ func TestClone(t *testing.T) {
 		"point ok 2": {
 			a: Point{3.14, 2.7},
 		},
+		"nil": {
+			err: "unknown Geometry: <nil>",
+		},
+		"polygon": {
+			a: Polygon{{{0, 0}, {1, 0}, {1, 1}}, {}},
+		},
+		"nil linestring": {
+			a: LineString(nil),
+		},
+		"multipolygon z": {
+			a: MultiPolygonZ{{{{0, 0, 1}, {1, 0, 2}, {1, 1, 3}}}},
+		},
+		"line": {
+			a: Line{{0, 0}, {1, 1}},
+		},
+		"extent": {
+			a: &Extent{0, 0, 1, 1},
+		},
+		"pointer": {
+			a: &MultiLineStringM{{{0, 0, 5}, {1, 1, 6}}},
+		},
+		"nil pointer": {
+			a: (*PolygonZM)(nil),
+		},
+		"packed": {
+			a: PackedPolygon{{Layout: LayoutXYM, Flat: []float64{0, 0, 1, 1, 0, 2, 1, 1, 3}}},
+		},
+		"collection": {
+			a: Collection{
+				Point{1, 2},
+				Collection{LineStringZM{{0, 0, 1, 2}, {3, 4, 5, 6}}},
+				&Triangle{{0, 0}, {1, 0}, {0, 1}},
+			},
+		},
+		"collection of unknown": {
+			a:   Collection{Point{1, 2}, int(0)},
+			err: "unknown Geometry: int",
+		},
 	}
 
 	for k, v := range tcases {
@@ -49,6 +87,28 @@ func TestClone(t *testing.T) {
 	}
 }
 
+func TestCloneShares(t *testing.T) {
+	mls := MultiLineString{{{0, 0}, {1, 1}}}
+	col := Collection{mls, &mls}
+	c, err := Clone(col)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	cmls := c.(Collection)[0].(MultiLineString)
+	cptr := c.(Collection)[1].(*MultiLineString)
+	if cptr == &mls {
+		t.Errorf("pointer, expected a new pointer got the same")
+	}
+	cmls[0][0][0] = 10
+	(*cptr)[0][1][0] = 20
+	if mls[0][0][0] != 0 || mls[0][1][0] != 1 {
+		t.Errorf("original, expected unchanged got %v", mls)
+	}
+	if (*cptr)[0][0][0] != 0 {
+		t.Errorf("clone, expected the parts unshared got %v", *cptr)
+	}
+}
+
 func TestApply(t *testing.T) {
 	type tcase struct {
 		a, b Geometry
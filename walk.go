@@ -0,0 +1,91 @@
+package geom
+
+// Walk calls fn with the x and y of each point of the geometry in order,
+// including the points of the geometries in collections, and stops at the
+// first error fn returns, which is returned. Rings are walked as they are
+// stored, so the first point of a closed ring is walked again at its end.
+// The Z and M values of points are not walked.
+func Walk(g Geometry, fn func(pt [2]float64) error) error {
+	switch gg := g.(type) {
+
+	default:
+
+		if xy, ok := XYGeometry(g); ok {
+			return Walk(xy, fn)
+		}
+		return ErrUnknownGeometry{g}
+
+	case Pointer:
+		return fn(gg.XY())
+
+	case MultiPointer:
+		return walkPoints(gg.Points(), fn)
+
+	case LineStringer:
+		return walkPoints(gg.Vertices(), fn)
+
+	case MultiLineStringer:
+
+		for _, ls := range gg.LineStrings() {
+			if err := walkPoints(ls, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case Polygoner:
+
+		for _, ls := range gg.LinearRings() {
+			if err := walkPoints(ls, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case MultiPolygoner:
+
+		for _, p := range gg.Polygons() {
+			for _, ls := range p {
+				if err := walkPoints(ls, fn); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+
+	case Collectioner:
+
+		for _, child := range gg.Geometries() {
+			if err := Walk(child, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	}
+}
+
+func walkPoints(pts [][2]float64, fn func(pt [2]float64) error) error {
+	for _, pt := range pts {
+		if err := fn(pt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Map returns a copy of the geometry, of the same type, with the x and y
+// of each of its points replaced by what fn returns for them; the geometry
+// given is not modified. The Z and M values of points are kept as they
+// are. It stops at the first error fn returns, which is returned.
+//
+// Map takes the geometries Walk takes, calling fn with the same points in
+// the same order. Pointers to geometries give pointers to the copies, and
+// other implementations of the geometry interfaces give the type of the
+// interface, as ApplyTransformer does.
+func Map(g Geometry, fn func(pt [2]float64) ([2]float64, error)) (Geometry, error) {
+	return ApplyTransformer(g, TransformerFunc(func(x, y float64) (float64, float64, error) {
+		pt, err := fn([2]float64{x, y})
+		return pt[0], pt[1], err
+	}))
+}
@@ -0,0 +1,159 @@
+package geom
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	type tcase struct {
+		g        Geometry
+		expected [][2]float64
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var got [][2]float64
+			err := Walk(tc.g, func(pt [2]float64) error {
+				got = append(got, pt)
+				return nil
+			})
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("points, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			g:        Point{1, 2},
+			expected: [][2]float64{{1, 2}},
+		},
+		"multi point": {
+			g:        MultiPoint{{1, 2}, {3, 4}},
+			expected: [][2]float64{{1, 2}, {3, 4}},
+		},
+		"line": {
+			g:        Line{{1, 2}, {3, 4}},
+			expected: [][2]float64{{1, 2}, {3, 4}},
+		},
+		"multi line string": {
+			g:        MultiLineString{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}}},
+			expected: [][2]float64{{1, 2}, {3, 4}, {5, 6}, {7, 8}},
+		},
+		"polygon z": {
+			g:        PolygonZ{{{0, 0, 1}, {1, 0, 2}, {1, 1, 3}}},
+			expected: [][2]float64{{0, 0}, {1, 0}, {1, 1}},
+		},
+		"multi polygon": {
+			g:        MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}}}, {{{5, 5}, {6, 5}, {6, 6}}}},
+			expected: [][2]float64{{0, 0}, {1, 0}, {1, 1}, {5, 5}, {6, 5}, {6, 6}},
+		},
+		"collection": {
+			g:        Collection{Point{1, 2}, Collection{LineStringM{{3, 4, 9}}}},
+			expected: [][2]float64{{1, 2}, {3, 4}},
+		},
+		"unknown": {
+			g:   Extent{0, 0, 1, 1},
+			err: ErrUnknownGeometry{Extent{0, 0, 1, 1}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	// walking stops at the first error
+	errStop := errors.New("stop")
+	count := 0
+	err := Walk(LineString{{1, 2}, {3, 4}, {5, 6}}, func(pt [2]float64) error {
+		count++
+		if pt == [2]float64{3, 4} {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop || count != 2 {
+		t.Errorf("stop, expected %v after 2 points got %v after %v", errStop, err, count)
+	}
+}
+
+func TestMap(t *testing.T) {
+	double := func(pt [2]float64) ([2]float64, error) {
+		return [2]float64{pt[0] * 2, pt[1] * 2}, nil
+	}
+
+	g := Collection{
+		Point{1, 2},
+		MultiPolygonZM{{{{0, 0, 5, 6}, {1, 0, 5, 6}, {1, 1, 5, 6}}}},
+	}
+	got, err := Map(g, double)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	expected := Collection{
+		Point{2, 4},
+		MultiPolygonZM{{{{0, 0, 5, 6}, {2, 0, 5, 6}, {2, 2, 5, 6}}}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("geometry, expected %v got %v", expected, got)
+	}
+	if g[0] != (Point{1, 2}) {
+		t.Errorf("original, expected unchanged got %v", g[0])
+	}
+
+	errBad := errors.New("bad")
+	_, err = Map(LineString{{1, 2}}, func(pt [2]float64) ([2]float64, error) {
+		return pt, errBad
+	})
+	if err != errBad {
+		t.Errorf("error, expected %v got %v", errBad, err)
+	}
+}
+
+func TestMapWalk(t *testing.T) {
+	type tcase struct {
+		g   Geometry
+		err error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var walked, mapped [][2]float64
+			werr := Walk(tc.g, func(pt [2]float64) error {
+				walked = append(walked, pt)
+				return nil
+			})
+			_, merr := Map(tc.g, func(pt [2]float64) ([2]float64, error) {
+				mapped = append(mapped, pt)
+				return pt, nil
+			})
+			if !reflect.DeepEqual(werr, tc.err) || !reflect.DeepEqual(merr, tc.err) {
+				t.Fatalf("error, expected %v got %v walking and %v mapping", tc.err, werr, merr)
+			}
+			if !reflect.DeepEqual(walked, mapped) {
+				t.Errorf("points, walked %v mapped %v", walked, mapped)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"polygon":      {g: &Polygon{{{0, 0}, {1, 0}, {1, 1}}}},
+		"line string":  {g: &LineString{{0, 0}, {1, 2}}},
+		"point z":      {g: &PointZ{1, 2, 3}},
+		"multipoint m": {g: &MultiPointM{{1, 2, 3}, {4, 5, 6}}},
+		"collection":   {g: &Collection{&Point{1, 2}, MultiLineString{{{3, 4}, {5, 6}}}}},
+		"extent":       {g: &Extent{0, 0, 1, 2}},
+		"polygoner":    {g: testPolygoner{{{0, 0}, {1, 0}, {1, 1}}}},
+		"unknown":      {g: Extent{0, 0, 1, 1}, err: ErrUnknownGeometry{Geom: Extent{0, 0, 1, 1}}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
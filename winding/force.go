@@ -0,0 +1,45 @@
+package winding
+
+import "github.com/go-spatial/geom"
+
+// ForceWinding returns a copy of the polygon with the exterior ring wound as
+// outer, and the holes the other way, reversing the rings that are not. As
+// MVT wants the exterior rings clockwise, with the y axis positive down, and
+// GeoJSON counter clockwise, with the y axis positive up, the order gives
+// the direction of the y axis to find the winding in. Colinear rings, which
+// have no winding, are kept as they are. The polygon is not changed.
+func ForceWinding(p geom.Polygon, order Order, outer Winding) geom.Polygon {
+	if p == nil {
+		return nil
+	}
+	ply := make(geom.Polygon, len(p))
+	for i, ring := range p {
+		exp := outer
+		if i > 0 {
+			exp = outer.Not()
+		}
+		ply[i] = make([][2]float64, len(ring))
+		w := order.OfPoints(ring...)
+		if w.IsColinear() || w == exp {
+			copy(ply[i], ring)
+			continue
+		}
+		for j := range ring {
+			ply[i][len(ring)-1-j] = ring[j]
+		}
+	}
+	return ply
+}
+
+// ForceWindingMultiPolygon returns a copy of the multipolygon with each of
+// its polygons wound as ForceWinding does.
+func ForceWindingMultiPolygon(mp geom.MultiPolygon, order Order, outer Winding) geom.MultiPolygon {
+	if mp == nil {
+		return nil
+	}
+	mply := make(geom.MultiPolygon, len(mp))
+	for i := range mp {
+		mply[i] = [][][2]float64(ForceWinding(geom.Polygon(mp[i]), order, outer))
+	}
+	return mply
+}
@@ -0,0 +1,81 @@
+package winding
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestForceWinding(t *testing.T) {
+	type tcase struct {
+		ply   geom.Polygon
+		order Order
+		outer Winding
+		exp   geom.Polygon
+	}
+
+	var (
+		shell    = [][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+		shellRev = [][2]float64{{0, 10}, {10, 10}, {10, 0}, {0, 0}}
+		hole     = [][2]float64{{2, 2}, {2, 4}, {4, 4}, {4, 2}}
+		holeRev  = [][2]float64{{4, 2}, {4, 4}, {2, 4}, {2, 2}}
+		flat     = [][2]float64{{5, 5}, {6, 6}, {7, 7}}
+	)
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			var in geom.Polygon
+			for _, ring := range tc.ply {
+				in = append(in, append([][2]float64{}, ring...))
+			}
+			got := ForceWinding(tc.ply, tc.order, tc.outer)
+			if !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("polygon, expected %v got %v", tc.exp, got)
+			}
+			if !reflect.DeepEqual(tc.ply, in) {
+				t.Errorf("input changed, expected %v got %v", in, tc.ply)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"already wound": {
+			ply:   geom.Polygon{shell, hole},
+			outer: OfPoints(shell...),
+			exp:   geom.Polygon{shell, hole},
+		},
+		"reversed": {
+			ply:   geom.Polygon{shell, hole},
+			outer: OfPoints(shell...).Not(),
+			exp:   geom.Polygon{shellRev, holeRev},
+		},
+		"y down": {
+			ply:   geom.Polygon{shell, hole},
+			order: Order{YPositiveDown: true},
+			outer: OfPoints(shell...),
+			exp:   geom.Polygon{shellRev, holeRev},
+		},
+		"mixed": {
+			ply:   geom.Polygon{shellRev, hole, holeRev},
+			outer: OfPoints(shell...),
+			exp:   geom.Polygon{shell, hole, hole},
+		},
+		"colinear kept": {
+			ply:   geom.Polygon{shell, flat},
+			outer: OfPoints(shell...),
+			exp:   geom.Polygon{shell, flat},
+		},
+		"nil": {},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	mp := geom.MultiPolygon{{shell, holeRev}, {shellRev}}
+	exp := geom.MultiPolygon{{shellRev, holeRev}, {shellRev}}
+	if got := ForceWindingMultiPolygon(mp, Order{}, OfPoints(shellRev...)); !reflect.DeepEqual(got, exp) {
+		t.Errorf("multipolygon, expected %v got %v", exp, got)
+	}
+}
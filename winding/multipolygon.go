@@ -0,0 +1,40 @@
+package winding
+
+// RingRef is a ring of a polygon of a multipolygon.
+type RingRef struct {
+	Polygon, Ring int
+}
+
+// OfMultiPolygon returns the winding of each of the rings of each of the
+// polygons of the multipolygon.
+func (order Order) OfMultiPolygon(mply [][][][2]float64) [][]Winding {
+	ws := make([][]Winding, len(mply))
+	for i, ply := range mply {
+		ws[i] = make([]Winding, len(ply))
+		for j, ring := range ply {
+			ws[i][j] = order.OfPoints(ring...)
+		}
+	}
+	return ws
+}
+
+// Violations returns the rings of the multipolygon that are not wound as
+// the convention given by outer says: the first ring of each polygon wound
+// as outer, and the holes the other way. Colinear rings are always
+// returned, as they have no winding. The rings are in the order they are in
+// the multipolygon.
+func (order Order) Violations(mply [][][][2]float64, outer Winding) []RingRef {
+	var bad []RingRef
+	for i, ws := range order.OfMultiPolygon(mply) {
+		for j, w := range ws {
+			exp := outer
+			if j > 0 {
+				exp = outer.Not()
+			}
+			if w != exp || w.IsColinear() {
+				bad = append(bad, RingRef{Polygon: i, Ring: j})
+			}
+		}
+	}
+	return bad
+}
@@ -0,0 +1,62 @@
+package winding
+
+import "math"
+
+// Normal returns the normal of the ring of points, by Newell's method,
+// which is the normal of the plane that best fits them, whatever way they
+// are off it. Its length is twice the area of the ring projected onto that
+// plane, and it points to the side the ring goes counter clockwise around
+// when looked at, as the right hand rule says. It is zero for rings with no
+// area.
+func Normal(pts ...[3]float64) [3]float64 {
+	var n [3]float64
+	li := len(pts) - 1
+	for i := range pts {
+		a, b := pts[li], pts[i]
+		n[0] += (a[1] - b[1]) * (a[2] + b[2])
+		n[1] += (a[2] - b[2]) * (a[0] + b[0])
+		n[2] += (a[0] - b[0]) * (a[1] + b[1])
+		li = i
+	}
+	return n
+}
+
+// SignedAreaOnPlane returns the area of the ring of points projected onto
+// the plane with the given normal, which need not be of unit length. It is
+// positive if the ring goes counter clockwise looking at the plane from the
+// side the normal points to, negative if it goes clockwise, and zero if the
+// ring or the normal has no area or length.
+func SignedAreaOnPlane(normal [3]float64, pts ...[3]float64) float64 {
+	l := math.Sqrt(normal[0]*normal[0] + normal[1]*normal[1] + normal[2]*normal[2])
+	if l == 0 || len(pts) < 3 {
+		return 0
+	}
+	n := Normal(pts...)
+	return (n[0]*normal[0] + n[1]*normal[1] + n[2]*normal[2]) / (2 * l)
+}
+
+// OfPointsOnPlane returns the winding of the points projected onto the
+// plane with the given normal. It is the winding OfPoints returns for the
+// projected points, with the x and y axes of the plane turned so the normal
+// is its z axis; so for the normal 0, 0, 1 it is the winding of the x and
+// y of the points.
+func (order Order) OfPointsOnPlane(normal [3]float64, pts ...[3]float64) Winding {
+	a := SignedAreaOnPlane(normal, pts...)
+	if order.YPositiveDown {
+		a = -a
+	}
+	switch {
+	case a == 0:
+		return Colinear
+	case a > 0:
+		return Clockwise
+	default:
+		return CounterClockwise
+	}
+}
+
+// OfPointsOnPlane returns the winding of the points projected onto the
+// plane with the given normal, see Order.OfPointsOnPlane.
+func OfPointsOnPlane(normal [3]float64, pts ...[3]float64) Winding {
+	return Order{}.OfPointsOnPlane(normal, pts...)
+}
@@ -0,0 +1,92 @@
+package winding
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestOfPointsOnPlane(t *testing.T) {
+	type tcase struct {
+		normal [3]float64
+		pts    [][3]float64
+		area   float64
+		exp    Winding
+	}
+
+	// a unit square in the x, y plane and in the plane x = y
+	square := [][3]float64{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}}
+	slanted := [][3]float64{{0, 0, 0}, {1, 1, 0}, {1, 1, 1}, {0, 0, 1}}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if got := SignedAreaOnPlane(tc.normal, tc.pts...); math.Abs(got-tc.area) > 1e-9 {
+				t.Errorf("area, expected %v got %v", tc.area, got)
+			}
+			if got := OfPointsOnPlane(tc.normal, tc.pts...); got != tc.exp {
+				t.Errorf("winding, expected %v got %v", tc.exp, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"z up": {
+			normal: [3]float64{0, 0, 1},
+			pts:    square,
+			area:   1,
+			// the same as the winding of the x and y
+			exp: OfPoints([2]float64{0, 0}, [2]float64{1, 0}, [2]float64{1, 1}, [2]float64{0, 1}),
+		},
+		"z down": {
+			normal: [3]float64{0, 0, -2},
+			pts:    square,
+			area:   -1,
+			exp:    OfPoints([2]float64{0, 0}, [2]float64{1, 0}, [2]float64{1, 1}, [2]float64{0, 1}).Not(),
+		},
+		"slanted": {
+			normal: [3]float64{1, -1, 0},
+			pts:    slanted,
+			area:   math.Sqrt2,
+			exp:    Clockwise,
+		},
+		"edge on": {
+			// looking along the plane of the square it has no area
+			normal: [3]float64{1, 0, 0},
+			pts:    square,
+			exp:    Colinear,
+		},
+		"no normal": {
+			pts: square,
+			exp: Colinear,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	if n := Normal(square...); n != [3]float64{0, 0, 2} {
+		t.Errorf("normal, expected [0 0 2] got %v", n)
+	}
+}
+
+func TestViolations(t *testing.T) {
+	var order Order
+	ring := [][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	outer := order.OfPoints(ring...)
+	rev := [][2]float64{{0, 10}, {10, 10}, {10, 0}, {0, 0}}
+	flat := [][2]float64{{0, 0}, {1, 1}, {2, 2}}
+
+	mply := [][][][2]float64{
+		{ring, rev},
+		{rev, ring, flat},
+	}
+	exp := [][]Winding{{outer, outer.Not()}, {outer.Not(), outer, Colinear}}
+	if got := order.OfMultiPolygon(mply); !reflect.DeepEqual(got, exp) {
+		t.Errorf("windings, expected %v got %v", exp, got)
+	}
+	expBad := []RingRef{{1, 0}, {1, 1}, {1, 2}}
+	if got := order.Violations(mply, outer); !reflect.DeepEqual(got, expBad) {
+		t.Errorf("violations, expected %v got %v", expBad, got)
+	}
+}
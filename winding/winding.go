@@ -60,6 +60,24 @@ func (w Winding) Not() Winding {
 	}
 }
 
+// SignedArea returns the area of the ring of points, which may or may not
+// repeat its first point at the end. It is positive if the ring goes
+// counter clockwise with the y axis going up, negative if it goes
+// clockwise, and zero if it has no area.
+func SignedArea(pts ...[2]float64) float64 {
+	if len(pts) < 3 {
+		return 0
+	}
+	// relative to the first point, for precision
+	o := pts[0]
+	var a float64
+	for i := 2; i < len(pts); i++ {
+		p, q := pts[i-1], pts[i]
+		a += (p[0]-o[0])*(q[1]-o[1]) - (q[0]-o[0])*(p[1]-o[1])
+	}
+	return a / 2
+}
+
 // Orient will take the points and calculate the Orientation of the points. by
 // summing the normal vectors. It will return 0 of the given points are colinear
 // or 1, or -1 for clockwise and counter clockwise depending on the direction of
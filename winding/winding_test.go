@@ -97,6 +97,32 @@ func TestAttributeMethods(t *testing.T) {
 	}
 }
 
+func TestSignedArea(t *testing.T) {
+	type tcase struct {
+		pts  [][2]float64
+		area float64
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if got := SignedArea(tc.pts...); got != tc.area {
+				t.Errorf("signed area, expected %v got %v", tc.area, got)
+			}
+		}
+	}
+	tests := map[string]tcase{
+		"empty":             {},
+		"line":              {pts: [][2]float64{{0, 0}, {1, 1}}},
+		"counter clockwise": {pts: [][2]float64{{0, 0}, {2, 0}, {2, 3}, {0, 3}}, area: 6},
+		"clockwise":         {pts: [][2]float64{{0, 0}, {0, 3}, {2, 3}, {2, 0}}, area: -6},
+		"closed":            {pts: [][2]float64{{0, 0}, {2, 0}, {2, 3}, {0, 3}, {0, 0}}, area: 6},
+		"colinear":          {pts: [][2]float64{{0, 0}, {1, 1}, {2, 2}}},
+		"far from zero":     {pts: [][2]float64{{1e9, 1e9}, {1e9 + 0.5, 1e9}, {1e9 + 0.5, 1e9 + 0.5}}, area: 0.125},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
 func TestOfPoints(t *testing.T) {
 	type tcase struct {
 		Desc  string